@@ -33,3 +33,6 @@ const WebhookRouterName = "webhook_router"
 
 // EventTriggerName is the event trigger actor name.
 const EventTriggerName = "event_trigger"
+
+// RetentionReaperName is the retention reaper actor name.
+const RetentionReaperName = "retention_reaper"
@@ -5,12 +5,14 @@ import (
 	"github.com/open-source-cloud/fuse/pkg/workflow"
 )
 
-// WorkflowHandlerName helper function to generate the WorkflowHandler actor name in the context of a workflow instance
-func WorkflowHandlerName(workflowID workflow.ID) string {
-	return fmt.Sprintf("workflow_handler_%s", workflowID)
+// WorkflowHandlerName helper function to generate the WorkflowHandler actor name in the context of a workflow instance.
+// The name carries the instance's namespace, so a message addressed with the wrong namespace never
+// reaches another tenant's execution.
+func WorkflowHandlerName(namespace string, workflowID workflow.ID) string {
+	return fmt.Sprintf("workflow_handler_%s_%s", namespace, workflowID)
 }
 
 // WorkflowInstanceSupervisorName helper function to generate the WorkflowInstanceSupervisor actor name in the context of a workflow instance
-func WorkflowInstanceSupervisorName(workflowID workflow.ID) string {
-	return fmt.Sprintf("%s_%s", WorkflowInstanceSupervisor, workflowID)
+func WorkflowInstanceSupervisorName(namespace string, workflowID workflow.ID) string {
+	return fmt.Sprintf("%s_%s_%s", WorkflowInstanceSupervisor, namespace, workflowID)
 }
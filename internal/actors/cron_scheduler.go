@@ -7,6 +7,7 @@ import (
 	"ergo.services/ergo/act"
 	"ergo.services/ergo/gen"
 	"github.com/open-source-cloud/fuse/internal/actors/actornames"
+	"github.com/open-source-cloud/fuse/internal/events"
 	"github.com/open-source-cloud/fuse/internal/idempotency"
 	"github.com/open-source-cloud/fuse/internal/messaging"
 	"github.com/open-source-cloud/fuse/internal/services"
@@ -24,11 +25,20 @@ const cronIdempotencyTTL = 1 * time.Hour
 type CronSchedulerFactory ActorFactory[*CronScheduler]
 
 // NewCronSchedulerFactory creates a new CronSchedulerFactory
-func NewCronSchedulerFactory(graphService services.GraphService, idempotencyStore idempotency.Store) *CronSchedulerFactory {
+func NewCronSchedulerFactory(
+	graphService services.GraphService,
+	lifecycleService services.SchemaLifecycleService,
+	namespaceService services.NamespaceService,
+	eventBus events.EventBus,
+	idempotencyStore idempotency.Store,
+) *CronSchedulerFactory {
 	return &CronSchedulerFactory{
 		Factory: func() gen.ProcessBehavior {
 			return &CronScheduler{
 				graphService:     graphService,
+				lifecycleService: lifecycleService,
+				namespaceService: namespaceService,
+				eventBus:         eventBus,
 				idempotencyStore: idempotencyStore,
 				entries:          make(map[string]cron.EntryID),
 			}
@@ -44,9 +54,13 @@ type CronScheduler struct {
 	act.Actor
 
 	graphService     services.GraphService
+	lifecycleService services.SchemaLifecycleService
+	namespaceService services.NamespaceService
+	eventBus         events.EventBus
 	idempotencyStore idempotency.Store
 	cronEngine       *cron.Cron
 	entries          map[string]cron.EntryID // schemaID -> cron entry
+	lifecycleSubs    []events.SubscriptionID
 }
 
 // Init loads all cron-triggered schemas and starts the cron engine
@@ -61,25 +75,44 @@ func (a *CronScheduler) Init(_ ...any) error {
 	}
 
 	for _, item := range schemas {
-		graph, gErr := a.graphService.FindByID(item.SchemaID)
-		if gErr != nil {
-			a.Log().Warning("failed to load schema %s: %s", item.SchemaID, gErr)
+		if !item.Lifecycle.KeepsTriggers() {
 			continue
 		}
-		tc := graph.Schema().TriggerConfig
-		if tc == nil || tc.Type != internalworkflow.TriggerCron || tc.Cron == nil {
-			continue
-		}
-		a.registerCronTrigger(graph.Schema().ID, tc.Cron)
+		a.loadCronTrigger(item.SchemaID)
 	}
 
+	a.lifecycleSubs = subscribeSchemaLifecycle(a, a.eventBus)
 	a.cronEngine.Start()
 	a.Log().Info("cron scheduler started with %d entries", len(a.entries))
 	return nil
 }
 
+// loadCronTrigger registers the cron trigger of a schema, if it has one.
+func (a *CronScheduler) loadCronTrigger(schemaID string) {
+	graph, err := a.graphService.FindByID(schemaID)
+	if err != nil {
+		a.Log().Warning("failed to load schema %s: %s", schemaID, err)
+		return
+	}
+	tc := graph.Schema().TriggerConfig
+	if tc == nil || tc.Type != internalworkflow.TriggerCron || tc.Cron == nil {
+		return
+	}
+	a.registerCronTrigger(schemaID, tc.Cron)
+}
+
 func (a *CronScheduler) registerCronTrigger(schemaID string, cfg *internalworkflow.CronConfig) {
 	entryID, err := a.cronEngine.AddFunc(cfg.Expression, func() {
+		// Deprecated schemas keep their schedule but must not start new executions
+		if lcErr := a.lifecycleService.CheckAcceptsExecutions(schemaID); lcErr != nil {
+			a.Log().Debug("cron trigger for schema %s skipped: %s", schemaID, lcErr)
+			return
+		}
+		if quotaErr := a.namespaceService.CheckConcurrency(workflow.NamespaceOf(schemaID)); quotaErr != nil {
+			a.Log().Warning("cron trigger for schema %s skipped: %s", schemaID, quotaErr)
+			return
+		}
+
 		// Build a deterministic idempotency key from schema ID + time bucket.
 		// Truncate to the minute to handle small scheduling jitter across nodes.
 		timeBucket := time.Now().Truncate(time.Minute).Format(time.RFC3339)
@@ -107,13 +140,22 @@ func (a *CronScheduler) registerCronTrigger(schemaID string, cfg *internalworkfl
 }
 
 // HandleMessage handles messages sent to the CronScheduler
-func (a *CronScheduler) HandleMessage(_ gen.PID, _ any) error {
-	// Future: handle schema upsert messages to dynamically add/remove cron entries
+func (a *CronScheduler) HandleMessage(_ gen.PID, message any) error {
+	if msg, ok := message.(schemaLifecycleMsg); ok {
+		if entryID, registered := a.entries[msg.schemaID]; registered && !msg.keepsTriggers() {
+			a.cronEngine.Remove(entryID)
+			delete(a.entries, msg.schemaID)
+			a.Log().Info("unregistered cron trigger of schema %s", msg.schemaID)
+		} else if !registered && msg.keepsTriggers() {
+			a.loadCronTrigger(msg.schemaID)
+		}
+	}
 	return nil
 }
 
 // Terminate stops the cron engine
 func (a *CronScheduler) Terminate(reason error) {
+	unsubscribeAll(a.eventBus, a.lifecycleSubs)
 	if a.cronEngine != nil {
 		a.cronEngine.Stop()
 	}
@@ -25,14 +25,22 @@ const eventIdempotencyTTL = 10 * time.Minute
 type EventTriggerFactory ActorFactory[*EventTrigger]
 
 // NewEventTriggerFactory creates a new EventTriggerFactory
-func NewEventTriggerFactory(graphService services.GraphService, eventBus events.EventBus, idempotencyStore idempotency.Store) *EventTriggerFactory {
+func NewEventTriggerFactory(
+	graphService services.GraphService,
+	lifecycleService services.SchemaLifecycleService,
+	namespaceService services.NamespaceService,
+	eventBus events.EventBus,
+	idempotencyStore idempotency.Store,
+) *EventTriggerFactory {
 	return &EventTriggerFactory{
 		Factory: func() gen.ProcessBehavior {
 			return &EventTrigger{
 				graphService:     graphService,
+				lifecycleService: lifecycleService,
+				namespaceService: namespaceService,
 				eventBus:         eventBus,
 				idempotencyStore: idempotencyStore,
-				subscriptions:    make([]events.SubscriptionID, 0),
+				subscriptions:    make(map[string]events.SubscriptionID),
 			}
 		},
 	}
@@ -46,9 +54,12 @@ type EventTrigger struct {
 	act.Actor
 
 	graphService     services.GraphService
+	lifecycleService services.SchemaLifecycleService
+	namespaceService services.NamespaceService
 	eventBus         events.EventBus
 	idempotencyStore idempotency.Store
-	subscriptions    []events.SubscriptionID
+	subscriptions    map[string]events.SubscriptionID // schemaID -> subscription
+	lifecycleSubs    []events.SubscriptionID
 }
 
 // Init loads all event-triggered schemas and subscribes to matching events
@@ -62,21 +73,30 @@ func (a *EventTrigger) Init(_ ...any) error {
 	}
 
 	for _, item := range schemas {
-		graph, gErr := a.graphService.FindByID(item.SchemaID)
-		if gErr != nil {
+		if !item.Lifecycle.KeepsTriggers() {
 			continue
 		}
-		tc := graph.Schema().TriggerConfig
-		if tc == nil || tc.Type != internalworkflow.TriggerEvent || tc.Event == nil {
-			continue
-		}
-		a.subscribeEventTrigger(graph.Schema().ID, tc.Event)
+		a.loadEventTrigger(item.SchemaID)
 	}
 
+	a.lifecycleSubs = subscribeSchemaLifecycle(a, a.eventBus)
 	a.Log().Info("event trigger started with %d subscriptions", len(a.subscriptions))
 	return nil
 }
 
+// loadEventTrigger subscribes the event trigger of a schema, if it has one.
+func (a *EventTrigger) loadEventTrigger(schemaID string) {
+	graph, err := a.graphService.FindByID(schemaID)
+	if err != nil {
+		return
+	}
+	tc := graph.Schema().TriggerConfig
+	if tc == nil || tc.Type != internalworkflow.TriggerEvent || tc.Event == nil {
+		return
+	}
+	a.subscribeEventTrigger(schemaID, tc.Event)
+}
+
 func (a *EventTrigger) subscribeEventTrigger(schemaID string, cfg *internalworkflow.EventConfig) {
 	subID, err := a.eventBus.Subscribe(cfg.EventType, func(event events.Event) error {
 		// Apply optional filter expression
@@ -91,6 +111,16 @@ func (a *EventTrigger) subscribeEventTrigger(schemaID string, cfg *internalworkf
 			}
 		}
 
+		// Deprecated schemas stay subscribed but must not start new executions
+		if lcErr := a.lifecycleService.CheckAcceptsExecutions(schemaID); lcErr != nil {
+			a.Log().Debug("event trigger for schema %s skipped: %s", schemaID, lcErr)
+			return nil
+		}
+		if quotaErr := a.namespaceService.CheckConcurrency(workflow.NamespaceOf(schemaID)); quotaErr != nil {
+			a.Log().Warning("event trigger for schema %s skipped: %s", schemaID, quotaErr)
+			return nil
+		}
+
 		// Build deterministic idempotency key from event source + type + data hash
 		idempotencyKey := buildEventIdempotencyKey(schemaID, event)
 		workflowID := workflow.NewID()
@@ -112,7 +142,7 @@ func (a *EventTrigger) subscribeEventTrigger(schemaID string, cfg *internalworkf
 		a.Log().Error("failed to subscribe to event %s for schema %s: %s", cfg.EventType, schemaID, err)
 		return
 	}
-	a.subscriptions = append(a.subscriptions, subID)
+	a.subscriptions[schemaID] = subID
 }
 
 // buildEventIdempotencyKey creates a deterministic key from event properties.
@@ -137,12 +167,22 @@ func evaluateFilter(filter string, data map[string]any) (bool, error) {
 }
 
 // HandleMessage handles messages sent to the EventTrigger
-func (a *EventTrigger) HandleMessage(_ gen.PID, _ any) error {
+func (a *EventTrigger) HandleMessage(_ gen.PID, message any) error {
+	if msg, ok := message.(schemaLifecycleMsg); ok {
+		if subID, subscribed := a.subscriptions[msg.schemaID]; subscribed && !msg.keepsTriggers() {
+			_ = a.eventBus.Unsubscribe(subID)
+			delete(a.subscriptions, msg.schemaID)
+			a.Log().Info("unregistered event trigger of schema %s", msg.schemaID)
+		} else if !subscribed && msg.keepsTriggers() {
+			a.loadEventTrigger(msg.schemaID)
+		}
+	}
 	return nil
 }
 
 // Terminate unsubscribes from all events
 func (a *EventTrigger) Terminate(reason error) {
+	unsubscribeAll(a.eventBus, a.lifecycleSubs)
 	for _, subID := range a.subscriptions {
 		_ = a.eventBus.Unsubscribe(subID)
 	}
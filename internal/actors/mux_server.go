@@ -2,6 +2,7 @@ package actors
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
@@ -18,7 +19,12 @@ import (
 
 	_ "github.com/open-source-cloud/fuse/docs" // Import generated docs
 	"github.com/open-source-cloud/fuse/internal/app/config"
+	"github.com/open-source-cloud/fuse/internal/audit"
+	"github.com/open-source-cloud/fuse/internal/auth"
+	"github.com/open-source-cloud/fuse/internal/dtos"
+	"github.com/open-source-cloud/fuse/internal/handlers"
 	"github.com/open-source-cloud/fuse/internal/metrics"
+	"github.com/open-source-cloud/fuse/internal/services"
 )
 
 // MuxServerFactory is a factory for creating MuxServer actors
@@ -31,16 +37,39 @@ type muxServer struct {
 	config        *config.Config
 	fuseMetrics   *metrics.FuseMetrics
 	ergoCollector *metrics.ErgoNodeCollector
+	authenticator auth.Authenticator
+	authorizer    auth.Authorizer
+	namespaces    services.NamespaceService
+	// executionStream serves node output streams; it is registered outside the worker pools
+	// because a stream outlives their request timeout.
+	executionStream *handlers.ExecutionStreamHandler
+	// mcpEndpoint serves the MCP server; a tool call waits for its workflow, so it is registered
+	// outside the worker pools as well.
+	mcpEndpoint *handlers.MCPEndpointHandler
 }
 
 // NewMuxServerFactory creates a new MuxServerFactory
-func NewMuxServerFactory(workers *Workers, config *config.Config, fuseMetrics *metrics.FuseMetrics) *MuxServerFactory {
+func NewMuxServerFactory(
+	workers *Workers,
+	config *config.Config,
+	fuseMetrics *metrics.FuseMetrics,
+	authenticator auth.Authenticator,
+	authorizer auth.Authorizer,
+	namespaces services.NamespaceService,
+	executionStream *handlers.ExecutionStreamHandler,
+	mcpEndpoint *handlers.MCPEndpointHandler,
+) *MuxServerFactory {
 	return &MuxServerFactory{
 		Factory: func() gen.ProcessBehavior {
 			return &muxServer{
-				workers:     workers,
-				config:      config,
-				fuseMetrics: fuseMetrics,
+				workers:         workers,
+				config:          config,
+				fuseMetrics:     fuseMetrics,
+				authenticator:   authenticator,
+				authorizer:      authorizer,
+				namespaces:      namespaces,
+				executionStream: executionStream,
+				mcpEndpoint:     mcpEndpoint,
 			}
 		},
 	}
@@ -66,7 +95,16 @@ func (m *muxServer) Init(_ ...any) error {
 	muxRouter := mux.NewRouter()
 
 	// /metrics — Prometheus scrape endpoint
-	muxRouter.Handle("/metrics", metricsHandler).Methods(http.MethodGet)
+	muxRouter.Handle("/metrics", m.protect(metricsHandler, m.config.Auth.PublicMetrics)).Methods(http.MethodGet)
+
+	// /v1/workflows/{workflowID}/execs/{execID}/stream — server-sent events of a running node
+	muxRouter.Handle("/v1/workflows/{workflowID}/execs/{execID}/stream", m.protect(m.executionStream, false)).Methods(http.MethodGet)
+	muxRouter.Handle("/v1/ns/{ns}/workflows/{workflowID}/execs/{execID}/stream", m.protect(m.requireNamespace(m.executionStream), false)).Methods(http.MethodGet)
+
+	// /v1/mcp — MCP server (streamable HTTP) offering exposed schemas as tools
+	mcpMethods := []string{http.MethodPost, http.MethodGet, http.MethodDelete}
+	muxRouter.Handle("/v1/mcp", audit.Middleware(m.protect(m.mcpEndpoint, false))).Methods(mcpMethods...)
+	muxRouter.Handle("/v1/ns/{ns}/mcp", audit.Middleware(m.protect(m.requireNamespace(m.mcpEndpoint), false))).Methods(mcpMethods...)
 
 	// create routes
 	for _, worker := range m.workers.GetAll() {
@@ -84,7 +122,7 @@ func (m *muxServer) Init(_ ...any) error {
 	// fixed @host (localhost:9090); rewrite host/schemes/basePath from the incoming request so the
 	// UI's "Try it out" targets whatever environment actually serves the docs (local, behind a
 	// reverse proxy, or a production domain) without rebuilding the spec.
-	muxRouter.HandleFunc("/docs/doc.json", func(w http.ResponseWriter, r *http.Request) {
+	muxRouter.Handle("/docs/doc.json", m.protect(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		spec := swag.GetSwagger("swagger")
 		if spec == nil {
 			http.Error(w, "swagger spec not found", http.StatusInternalServerError)
@@ -92,21 +130,21 @@ func (m *muxServer) Init(_ ...any) error {
 		}
 		w.Header().Set("Content-Type", "application/json")
 		_, _ = w.Write([]byte(patchSwaggerServer(spec.ReadDoc(), r, configuredBasePath)))
-	})
-	muxRouter.HandleFunc("/docs", func(w http.ResponseWriter, r *http.Request) {
+	}), m.config.Auth.PublicDocs))
+	muxRouter.Handle("/docs", m.protect(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		prefix := strings.TrimRight(r.Header.Get("X-Forwarded-Prefix"), "/")
 		if prefix == "" {
 			prefix = configuredBasePath
 		}
 		http.Redirect(w, r, prefix+"/docs/index.html", http.StatusMovedPermanently)
-	})
+	}), m.config.Auth.PublicDocs))
 	// The UI loads its spec relatively ("doc.json" resolves under <prefix>/docs/) so it works
 	// behind any prefix; a configured base path makes it explicit/absolute.
 	specURL := "doc.json"
 	if configuredBasePath != "" {
 		specURL = configuredBasePath + "/docs/doc.json"
 	}
-	muxRouter.PathPrefix("/docs/").Handler(httpSwagger.Handler(httpSwagger.URL(specURL)))
+	muxRouter.PathPrefix("/docs/").Handler(m.protect(httpSwagger.Handler(httpSwagger.URL(specURL)), m.config.Auth.PublicDocs))
 	m.Log().Info("swagger documentation available at /docs or /docs/index.html")
 
 	// create and spawn a web server meta-process
@@ -160,11 +198,44 @@ func (m *muxServer) createWorkerPool(webWorker WebWorker, mux *mux.Router) error
 
 	m.Log().Info("started worker pool %s to serve %s (meta-process: %s)", webWorker.PoolConfig.Name, webWorker.Pattern, workerPoolID)
 
-	mux.Handle(webWorker.Pattern, workerPool)
+	// audit.Middleware records the request metadata the services attach to audit entries
+	mux.Handle(webWorker.Pattern, audit.Middleware(m.protect(workerPool, webWorker.Public)))
+	if webWorker.Namespaced {
+		pattern := "/v1/ns/{ns}" + strings.TrimPrefix(webWorker.Pattern, "/v1")
+		mux.Handle(pattern, audit.Middleware(m.protect(m.requireNamespace(workerPool), webWorker.Public)))
+	}
 
 	return nil
 }
 
+// requireNamespace answers 404 for /v1/ns/{ns}/... requests naming an undeclared namespace, so
+// handlers only ever see namespaces that exist.
+func (m *muxServer) requireNamespace(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ns := mux.Vars(r)["ns"]
+		if m.namespaces.IsValid(ns) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(dtos.NotFoundError{
+			Message: fmt.Sprintf("namespace %s not found", ns),
+			Code:    handlers.EntityNotFound,
+			Fields:  []string{"ns"},
+		})
+	})
+}
+
+// protect requires authentication on handler unless the route is public or authentication is
+// disabled; handlers authorize the principal themselves with auth.Check.
+func (m *muxServer) protect(handler http.Handler, public bool) http.Handler {
+	if public {
+		return handler
+	}
+	return auth.Middleware(m.authenticator, m.authorizer, handler)
+}
+
 // patchSwaggerServer rewrites the host, schemes, and basePath of a Swagger 2.0 spec from the
 // incoming request so the served doc.json reflects the environment actually serving it rather
 // than the build-time @host. It honors the standard reverse-proxy forwarding headers
@@ -7,6 +7,10 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/gorilla/mux"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/internal/services"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -102,3 +106,36 @@ func TestPatchSwaggerServer_InvalidDocReturnedUnchanged(t *testing.T) {
 	const notJSON = "this is not json"
 	assert.Equal(t, notJSON, patchSwaggerServer(notJSON, r, ""))
 }
+
+func TestRequireNamespace(t *testing.T) {
+	t.Parallel()
+
+	namespaces := services.NewNamespaceService(
+		repositories.NewMemoryNamespaceRepository(),
+		repositories.NewMemoryWorkflowRepository(),
+		repositories.NewMemoryGraphRepository(),
+		repositories.NewMemoryPackageRepository(),
+	)
+	_, err := namespaces.Save(workflow.NewNamespace("billing", ""))
+	require.NoError(t, err)
+
+	m := &muxServer{namespaces: namespaces}
+	router := mux.NewRouter()
+	router.Handle("/v1/ns/{ns}/schemas", m.requireNamespace(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})))
+
+	tests := []struct {
+		path       string
+		wantStatus int
+	}{
+		{path: "/v1/ns/billing/schemas", wantStatus: http.StatusNoContent},
+		{path: "/v1/ns/default/schemas", wantStatus: http.StatusNoContent},
+		{path: "/v1/ns/shipping/schemas", wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		assert.Equal(t, tt.wantStatus, rec.Code, tt.path)
+	}
+}
@@ -27,6 +27,12 @@ type (
 		Methods    []string
 		Timeout    time.Duration
 		PoolConfig WorkerPoolConfig
+		// Public routes skip API authentication: probes must work without credentials and
+		// webhooks are verified with their trigger's own signing secret.
+		Public bool
+		// Namespaced routes are also served under /v1/ns/{ns}/..., addressing the definitions of
+		// that namespace; the plain /v1 route addresses the default namespace.
+		Namespaced bool
 	}
 )
 
@@ -37,6 +43,7 @@ func NewWorkers() *Workers {
 			{
 				Name:    handlers.HealthCheckHandlerName,
 				Pattern: "/health",
+				Public:  true,
 				Methods: []string{"GET"},
 				Timeout: 10 * time.Second,
 				PoolConfig: WorkerPoolConfig{
@@ -47,6 +54,7 @@ func NewWorkers() *Workers {
 			{
 				Name:    handlers.LivenessHandlerName,
 				Pattern: "/healthz",
+				Public:  true,
 				Methods: []string{"GET"},
 				Timeout: 10 * time.Second,
 				PoolConfig: WorkerPoolConfig{
@@ -57,6 +65,7 @@ func NewWorkers() *Workers {
 			{
 				Name:    handlers.ReadinessHandlerName,
 				Pattern: "/readyz",
+				Public:  true,
 				Methods: []string{"GET"},
 				Timeout: 10 * time.Second,
 				PoolConfig: WorkerPoolConfig{
@@ -65,50 +74,55 @@ func NewWorkers() *Workers {
 				},
 			},
 			{
-				Name:    handlers.TriggerWorkflowHandlerName,
-				Pattern: "/v1/workflows/trigger",
-				Methods: []string{"POST"},
-				Timeout: 10 * time.Second,
+				Name:       handlers.TriggerWorkflowHandlerName,
+				Pattern:    "/v1/workflows/trigger",
+				Namespaced: true,
+				Methods:    []string{"POST"},
+				Timeout:    10 * time.Second,
 				PoolConfig: WorkerPoolConfig{
 					Name:     handlers.TriggerWorkflowHandlerPoolName,
 					PoolSize: 3,
 				},
 			},
 			{
-				Name:    handlers.AsyncFunctionResultHandlerName,
-				Pattern: "/v1/workflows/{workflowID}/execs/{execID}",
-				Methods: []string{"POST"},
-				Timeout: 10 * time.Second,
+				Name:       handlers.AsyncFunctionResultHandlerName,
+				Pattern:    "/v1/workflows/{workflowID}/execs/{execID}",
+				Namespaced: true,
+				Methods:    []string{"POST"},
+				Timeout:    10 * time.Second,
 				PoolConfig: WorkerPoolConfig{
 					Name:     handlers.AsyncFunctionResultHandlerPoolName,
 					PoolSize: 3,
 				},
 			},
 			{
-				Name:    handlers.ListSchemasHandlerName,
-				Pattern: "/v1/schemas",
-				Methods: []string{"GET"},
-				Timeout: 10 * time.Second,
+				Name:       handlers.ListSchemasHandlerName,
+				Pattern:    "/v1/schemas",
+				Namespaced: true,
+				Methods:    []string{"GET"},
+				Timeout:    10 * time.Second,
 				PoolConfig: WorkerPoolConfig{
 					Name:     handlers.ListSchemasHandlerPoolName,
 					PoolSize: 3,
 				},
 			},
 			{
-				Name:    handlers.WorkflowSchemaHandlerName,
-				Pattern: "/v1/schemas/{schemaID}",
-				Methods: []string{"PUT", "GET"},
-				Timeout: 10 * time.Second,
+				Name:       handlers.WorkflowSchemaHandlerName,
+				Pattern:    "/v1/schemas/{schemaID}",
+				Namespaced: true,
+				Methods:    []string{"PUT", "GET", "DELETE"},
+				Timeout:    10 * time.Second,
 				PoolConfig: WorkerPoolConfig{
 					Name:     handlers.WorkflowSchemaHandlerPoolName,
 					PoolSize: 3,
 				},
 			},
 			{
-				Name:    handlers.PackagesHandlerName,
-				Pattern: "/v1/packages",
-				Methods: []string{"GET"},
-				Timeout: 10 * time.Second,
+				Name:       handlers.PackagesHandlerName,
+				Pattern:    "/v1/packages",
+				Namespaced: true,
+				Methods:    []string{"GET"},
+				Timeout:    10 * time.Second,
 				PoolConfig: WorkerPoolConfig{
 					Name:     handlers.PackagesHandlerPoolName,
 					PoolSize: 3,
@@ -117,9 +131,10 @@ func NewWorkers() *Workers {
 			{
 				Name: handlers.RegisterPackageHandlerName,
 				// packageID may contain slashes (e.g. fuse/pkg/logic); default {var} is single-segment only.
-				Pattern: "/v1/packages/{packageID:.+}",
-				Methods: []string{"GET", "PUT"},
-				Timeout: 10 * time.Second,
+				Pattern:    "/v1/packages/{packageID:.+}",
+				Namespaced: true,
+				Methods:    []string{"GET", "PUT"},
+				Timeout:    10 * time.Second,
 				PoolConfig: WorkerPoolConfig{
 					Name:     handlers.RegisterPackageHandlerPoolName,
 					PoolSize: 3,
@@ -146,160 +161,378 @@ func NewWorkers() *Workers {
 				},
 			},
 			{
-				Name:    handlers.CredentialsHandlerName,
-				Pattern: "/v1/credentials",
+				Name:       handlers.EnvironmentVarsHandlerName,
+				Pattern:    "/v1/environments/{name}/vars",
+				Namespaced: true,
+				Methods:    []string{"GET"},
+				Timeout:    10 * time.Second,
+				PoolConfig: WorkerPoolConfig{
+					Name:     handlers.EnvironmentVarsHandlerPoolName,
+					PoolSize: 3,
+				},
+			},
+			{
+				Name:       handlers.EnvironmentVarHandlerName,
+				Pattern:    "/v1/environments/{name}/vars/{var}",
+				Namespaced: true,
+				Methods:    []string{"GET", "PUT", "DELETE"},
+				Timeout:    10 * time.Second,
+				PoolConfig: WorkerPoolConfig{
+					Name:     handlers.EnvironmentVarHandlerPoolName,
+					PoolSize: 3,
+				},
+			},
+			{
+				Name:       handlers.PromoteEnvironmentHandlerName,
+				Pattern:    "/v1/environments/{from}/promote/{to}",
+				Namespaced: true,
+				Methods:    []string{"POST"},
+				Timeout:    30 * time.Second,
+				PoolConfig: WorkerPoolConfig{
+					Name:     handlers.PromoteEnvironmentHandlerPoolName,
+					PoolSize: 3,
+				},
+			},
+			{
+				Name:    handlers.NamespacesHandlerName,
+				Pattern: "/v1/namespaces",
 				Methods: []string{"GET"},
 				Timeout: 10 * time.Second,
 				PoolConfig: WorkerPoolConfig{
-					Name:     handlers.CredentialsHandlerPoolName,
+					Name:     handlers.NamespacesHandlerPoolName,
 					PoolSize: 3,
 				},
 			},
 			{
-				Name:    handlers.CredentialHandlerName,
-				Pattern: "/v1/credentials/{id}",
+				Name:    handlers.NamespaceHandlerName,
+				Pattern: "/v1/namespaces/{name}",
 				Methods: []string{"GET", "PUT", "DELETE"},
 				Timeout: 10 * time.Second,
+				PoolConfig: WorkerPoolConfig{
+					Name:     handlers.NamespaceHandlerPoolName,
+					PoolSize: 3,
+				},
+			},
+			{
+				Name:       handlers.CredentialsHandlerName,
+				Pattern:    "/v1/credentials",
+				Namespaced: true,
+				Methods:    []string{"GET"},
+				Timeout:    10 * time.Second,
+				PoolConfig: WorkerPoolConfig{
+					Name:     handlers.CredentialsHandlerPoolName,
+					PoolSize: 3,
+				},
+			},
+			{
+				Name:       handlers.CredentialHandlerName,
+				Pattern:    "/v1/credentials/{id}",
+				Namespaced: true,
+				Methods:    []string{"GET", "PUT", "DELETE"},
+				Timeout:    10 * time.Second,
 				PoolConfig: WorkerPoolConfig{
 					Name:     handlers.CredentialHandlerPoolName,
 					PoolSize: 3,
 				},
 			},
 			{
-				Name:    handlers.ListSchemaVersionsHandlerName,
-				Pattern: "/v1/schemas/{schemaID}/versions",
-				Methods: []string{"GET"},
+				Name:    handlers.APIKeysHandlerName,
+				Pattern: "/v1/api-keys",
+				Methods: []string{"GET", "POST"},
 				Timeout: 10 * time.Second,
 				PoolConfig: WorkerPoolConfig{
-					Name:     handlers.ListSchemaVersionsHandlerPoolName,
+					Name:     handlers.APIKeysHandlerPoolName,
+					PoolSize: 3,
+				},
+			},
+			{
+				Name:    handlers.APIKeyHandlerName,
+				Pattern: "/v1/api-keys/{id}",
+				Methods: []string{"DELETE"},
+				Timeout: 10 * time.Second,
+				PoolConfig: WorkerPoolConfig{
+					Name:     handlers.APIKeyHandlerPoolName,
 					PoolSize: 3,
 				},
 			},
 			{
-				Name:    handlers.GetSchemaVersionHandlerName,
-				Pattern: "/v1/schemas/{schemaID}/versions/{version}",
+				Name:    handlers.RolesHandlerName,
+				Pattern: "/v1/roles",
 				Methods: []string{"GET"},
 				Timeout: 10 * time.Second,
 				PoolConfig: WorkerPoolConfig{
-					Name:     handlers.GetSchemaVersionHandlerPoolName,
+					Name:     handlers.RolesHandlerPoolName,
 					PoolSize: 3,
 				},
 			},
 			{
-				Name:    handlers.ActivateSchemaVersionHandlerName,
-				Pattern: "/v1/schemas/{schemaID}/versions/{version}/activate",
-				Methods: []string{"POST"},
+				Name:    handlers.RoleHandlerName,
+				Pattern: "/v1/roles/{name}",
+				Methods: []string{"GET", "PUT", "DELETE"},
 				Timeout: 10 * time.Second,
 				PoolConfig: WorkerPoolConfig{
-					Name:     handlers.ActivateSchemaVersionHandlerPoolName,
+					Name:     handlers.RoleHandlerPoolName,
 					PoolSize: 3,
 				},
 			},
 			{
-				Name:    handlers.RollbackSchemaHandlerName,
-				Pattern: "/v1/schemas/{schemaID}/rollback",
-				Methods: []string{"POST"},
+				Name:    handlers.RoleBindingsHandlerName,
+				Pattern: "/v1/role-bindings",
+				Methods: []string{"GET", "POST", "DELETE"},
 				Timeout: 10 * time.Second,
 				PoolConfig: WorkerPoolConfig{
-					Name:     handlers.RollbackSchemaHandlerPoolName,
+					Name:     handlers.RoleBindingsHandlerPoolName,
 					PoolSize: 3,
 				},
 			},
 			{
-				Name:    handlers.ListExecutionsHandlerName,
-				Pattern: "/v1/schemas/{schemaID}/executions",
+				Name:    handlers.AuditLogHandlerName,
+				Pattern: "/v1/audit",
 				Methods: []string{"GET"},
 				Timeout: 10 * time.Second,
+				PoolConfig: WorkerPoolConfig{
+					Name:     handlers.AuditLogHandlerPoolName,
+					PoolSize: 3,
+				},
+			},
+			{
+				Name:       handlers.AgentSessionHandlerName,
+				Pattern:    "/v1/ai/sessions/{id}",
+				Namespaced: true,
+				Methods:    []string{"GET", "DELETE"},
+				Timeout:    10 * time.Second,
+				PoolConfig: WorkerPoolConfig{
+					Name:     handlers.AgentSessionHandlerPoolName,
+					PoolSize: 3,
+				},
+			},
+			{
+				Name:       handlers.MCPServersHandlerName,
+				Pattern:    "/v1/mcp-servers",
+				Namespaced: true,
+				Methods:    []string{"GET"},
+				Timeout:    10 * time.Second,
+				PoolConfig: WorkerPoolConfig{
+					Name:     handlers.MCPServersHandlerPoolName,
+					PoolSize: 3,
+				},
+			},
+			{
+				Name:       handlers.MCPServerHandlerName,
+				Pattern:    "/v1/mcp-servers/{id}",
+				Namespaced: true,
+				Methods:    []string{"GET", "PUT", "DELETE"},
+				Timeout:    10 * time.Second,
+				PoolConfig: WorkerPoolConfig{
+					Name:     handlers.MCPServerHandlerPoolName,
+					PoolSize: 3,
+				},
+			},
+			{
+				Name:       handlers.ListSchemaVersionsHandlerName,
+				Pattern:    "/v1/schemas/{schemaID}/versions",
+				Namespaced: true,
+				Methods:    []string{"GET"},
+				Timeout:    10 * time.Second,
+				PoolConfig: WorkerPoolConfig{
+					Name:     handlers.ListSchemaVersionsHandlerPoolName,
+					PoolSize: 3,
+				},
+			},
+			{
+				Name:       handlers.GetSchemaVersionHandlerName,
+				Pattern:    "/v1/schemas/{schemaID}/versions/{version}",
+				Namespaced: true,
+				Methods:    []string{"GET"},
+				Timeout:    10 * time.Second,
+				PoolConfig: WorkerPoolConfig{
+					Name:     handlers.GetSchemaVersionHandlerPoolName,
+					PoolSize: 3,
+				},
+			},
+			{
+				Name:       handlers.DiffSchemaVersionsHandlerName,
+				Pattern:    "/v1/schemas/{schemaID}/versions/{from}/diff/{to}",
+				Namespaced: true,
+				Methods:    []string{"GET"},
+				Timeout:    10 * time.Second,
+				PoolConfig: WorkerPoolConfig{
+					Name:     handlers.DiffSchemaVersionsHandlerPoolName,
+					PoolSize: 3,
+				},
+			},
+			{
+				Name:       handlers.ActivateSchemaVersionHandlerName,
+				Pattern:    "/v1/schemas/{schemaID}/versions/{version}/activate",
+				Namespaced: true,
+				Methods:    []string{"POST"},
+				Timeout:    10 * time.Second,
+				PoolConfig: WorkerPoolConfig{
+					Name:     handlers.ActivateSchemaVersionHandlerPoolName,
+					PoolSize: 3,
+				},
+			},
+			{
+				Name:       handlers.RollbackSchemaHandlerName,
+				Pattern:    "/v1/schemas/{schemaID}/rollback",
+				Namespaced: true,
+				Methods:    []string{"POST"},
+				Timeout:    10 * time.Second,
+				PoolConfig: WorkerPoolConfig{
+					Name:     handlers.RollbackSchemaHandlerPoolName,
+					PoolSize: 3,
+				},
+			},
+			{
+				Name:       handlers.SchemaLifecycleHandlerName,
+				Pattern:    "/v1/schemas/{schemaID}/lifecycle",
+				Namespaced: true,
+				Methods:    []string{"GET", "PUT"},
+				Timeout:    10 * time.Second,
+				PoolConfig: WorkerPoolConfig{
+					Name:     handlers.SchemaLifecycleHandlerPoolName,
+					PoolSize: 3,
+				},
+			},
+			{
+				Name:       handlers.SchemaTrafficHandlerName,
+				Pattern:    "/v1/schemas/{schemaID}/traffic",
+				Namespaced: true,
+				Methods:    []string{"GET", "PUT", "DELETE"},
+				Timeout:    10 * time.Second,
+				PoolConfig: WorkerPoolConfig{
+					Name:     handlers.SchemaTrafficHandlerPoolName,
+					PoolSize: 3,
+				},
+			},
+			{
+				Name:       handlers.ListExecutionsHandlerName,
+				Pattern:    "/v1/schemas/{schemaID}/executions",
+				Namespaced: true,
+				Methods:    []string{"GET"},
+				Timeout:    10 * time.Second,
 				PoolConfig: WorkerPoolConfig{
 					Name:     handlers.ListExecutionsHandlerPoolName,
 					PoolSize: 3,
 				},
 			},
 			{
-				Name:    handlers.GetWorkflowHandlerName,
-				Pattern: "/v1/workflows/{workflowID}",
-				Methods: []string{"GET"},
-				Timeout: 10 * time.Second,
+				Name:       handlers.SearchExecutionsHandlerName,
+				Pattern:    "/v1/executions",
+				Namespaced: true,
+				Methods:    []string{"GET"},
+				Timeout:    10 * time.Second,
+				PoolConfig: WorkerPoolConfig{
+					Name:     handlers.SearchExecutionsHandlerPoolName,
+					PoolSize: 3,
+				},
+			},
+			{
+				Name:       handlers.GetWorkflowHandlerName,
+				Pattern:    "/v1/workflows/{workflowID}",
+				Namespaced: true,
+				Methods:    []string{"GET"},
+				Timeout:    10 * time.Second,
 				PoolConfig: WorkerPoolConfig{
 					Name:     handlers.GetWorkflowHandlerPoolName,
 					PoolSize: 3,
 				},
 			},
 			{
-				Name:    handlers.CancelWorkflowHandlerName,
-				Pattern: "/v1/workflows/{workflowID}/cancel",
-				Methods: []string{"POST"},
-				Timeout: 10 * time.Second,
+				Name:       handlers.CancelWorkflowHandlerName,
+				Pattern:    "/v1/workflows/{workflowID}/cancel",
+				Namespaced: true,
+				Methods:    []string{"POST"},
+				Timeout:    10 * time.Second,
 				PoolConfig: WorkerPoolConfig{
 					Name:     handlers.CancelWorkflowHandlerPoolName,
 					PoolSize: 3,
 				},
 			},
 			{
-				Name:    handlers.GetWorkflowSnapshotHandlerName,
-				Pattern: "/v1/workflows/{workflowID}/snapshot",
-				Methods: []string{"GET"},
-				Timeout: 10 * time.Second,
+				Name:       handlers.GetWorkflowSnapshotHandlerName,
+				Pattern:    "/v1/workflows/{workflowID}/snapshot",
+				Namespaced: true,
+				Methods:    []string{"GET"},
+				Timeout:    10 * time.Second,
 				PoolConfig: WorkerPoolConfig{
 					Name:     handlers.GetWorkflowSnapshotHandlerPoolName,
 					PoolSize: 3,
 				},
 			},
 			{
-				Name:    handlers.RetryNodeHandlerName,
-				Pattern: "/v1/workflows/{workflowID}/retry-node",
-				Methods: []string{"POST"},
-				Timeout: 10 * time.Second,
+				Name:       handlers.RetryNodeHandlerName,
+				Pattern:    "/v1/workflows/{workflowID}/retry-node",
+				Namespaced: true,
+				Methods:    []string{"POST"},
+				Timeout:    10 * time.Second,
 				PoolConfig: WorkerPoolConfig{
 					Name:     handlers.RetryNodeHandlerPoolName,
 					PoolSize: 3,
 				},
 			},
 			{
-				Name:    handlers.RetryWorkflowHandlerName,
-				Pattern: "/v1/workflows/{workflowID}/retry",
-				Methods: []string{"POST"},
-				Timeout: 10 * time.Second,
+				Name:       handlers.RetryWorkflowHandlerName,
+				Pattern:    "/v1/workflows/{workflowID}/retry",
+				Namespaced: true,
+				Methods:    []string{"POST"},
+				Timeout:    10 * time.Second,
 				PoolConfig: WorkerPoolConfig{
 					Name:     handlers.RetryWorkflowHandlerPoolName,
 					PoolSize: 3,
 				},
 			},
 			{
-				Name:    handlers.ResolveAwakeableHandlerName,
-				Pattern: "/v1/awakeables/{awakeableID}/resolve",
-				Methods: []string{"POST"},
-				Timeout: 10 * time.Second,
+				Name:       handlers.ResolveAwakeableHandlerName,
+				Pattern:    "/v1/awakeables/{awakeableID}/resolve",
+				Namespaced: true,
+				Methods:    []string{"POST"},
+				Timeout:    10 * time.Second,
 				PoolConfig: WorkerPoolConfig{
 					Name:     handlers.ResolveAwakeableHandlerPoolName,
 					PoolSize: 3,
 				},
 			},
 			{
-				Name:    handlers.WorkflowTraceHandlerName,
-				Pattern: "/v1/workflows/{workflowID}/trace",
-				Methods: []string{"GET"},
-				Timeout: 10 * time.Second,
+				Name:       handlers.AwakeableTokenHandlerName,
+				Pattern:    "/v1/awakeables/{awakeableID}/token",
+				Namespaced: true,
+				Methods:    []string{"GET"},
+				Timeout:    10 * time.Second,
+				PoolConfig: WorkerPoolConfig{
+					Name:     handlers.AwakeableTokenHandlerPoolName,
+					PoolSize: 3,
+				},
+			},
+			{
+				Name:       handlers.WorkflowTraceHandlerName,
+				Pattern:    "/v1/workflows/{workflowID}/trace",
+				Namespaced: true,
+				Methods:    []string{"GET"},
+				Timeout:    10 * time.Second,
 				PoolConfig: WorkerPoolConfig{
 					Name:     handlers.WorkflowTraceHandlerPoolName,
 					PoolSize: 3,
 				},
 			},
 			{
-				Name:    handlers.SchemaTracesHandlerName,
-				Pattern: "/v1/schemas/{schemaID}/traces",
-				Methods: []string{"GET"},
-				Timeout: 10 * time.Second,
+				Name:       handlers.SchemaTracesHandlerName,
+				Pattern:    "/v1/schemas/{schemaID}/traces",
+				Namespaced: true,
+				Methods:    []string{"GET"},
+				Timeout:    10 * time.Second,
 				PoolConfig: WorkerPoolConfig{
 					Name:     handlers.SchemaTracesHandlerPoolName,
 					PoolSize: 3,
 				},
 			},
 			{
-				Name:    handlers.WebhookHandlerName,
-				Pattern: "/v1/hooks/{path:.+}",
-				Methods: []string{"POST"},
-				Timeout: 30 * time.Second,
+				Name:       handlers.WebhookHandlerName,
+				Pattern:    "/v1/hooks/{path:.+}",
+				Namespaced: true,
+				Public:     true,
+				Methods:    []string{"POST"},
+				Timeout:    30 * time.Second,
 				PoolConfig: WorkerPoolConfig{
 					Name:     handlers.WebhookHandlerPoolName,
 					PoolSize: 3,
@@ -0,0 +1,96 @@
+package actors
+
+import (
+	"context"
+	"fmt"
+
+	"ergo.services/ergo/act"
+	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/app/config"
+	"github.com/open-source-cloud/fuse/internal/services"
+)
+
+// RetentionReaperFactory builds the retention reaper actor.
+type RetentionReaperFactory ActorFactory[*RetentionReaper]
+
+// NewRetentionReaperFactory constructs the factory.
+func NewRetentionReaperFactory(cfg *config.Config, retentionService services.RetentionService) *RetentionReaperFactory {
+	return &RetentionReaperFactory{
+		Factory: func() gen.ProcessBehavior {
+			return &RetentionReaper{
+				config:           cfg,
+				retentionService: retentionService,
+			}
+		},
+	}
+}
+
+// reaperTickMsg is the periodic purge tick message.
+type reaperTickMsg struct{}
+
+// RetentionReaper periodically purges terminal executions that exceed their retention policy.
+// Deletes are idempotent, so in HA mode every node may run a reaper without coordination.
+type RetentionReaper struct {
+	act.Actor
+
+	config           *config.Config
+	retentionService services.RetentionService
+
+	tickCancel gen.CancelFunc
+}
+
+// Init schedules the first purge.
+func (a *RetentionReaper) Init(_ ...any) error {
+	a.Log().Info("starting retention reaper (interval: %s, batch: %d)",
+		a.config.Retention.Interval, a.config.Retention.BatchSize)
+
+	cancel, err := a.SendAfter(a.PID(), reaperTickMsg{}, a.config.Retention.Interval)
+	if err != nil {
+		return fmt.Errorf("failed to schedule retention tick: %w", err)
+	}
+	a.tickCancel = cancel
+	return nil
+}
+
+// HandleMessage processes tick messages.
+func (a *RetentionReaper) HandleMessage(_ gen.PID, message any) error {
+	switch message.(type) {
+	case reaperTickMsg:
+		a.purge()
+
+		cancel, err := a.SendAfter(a.PID(), reaperTickMsg{}, a.config.Retention.Interval)
+		if err != nil {
+			a.Log().Error("failed to reschedule retention tick: %s", err)
+		} else {
+			a.tickCancel = cancel
+		}
+	default:
+		a.Log().Warning("unknown message type: %T", message)
+	}
+	return nil
+}
+
+// Terminate is called on actor shutdown.
+func (a *RetentionReaper) Terminate(reason error) {
+	a.Log().Info("retention reaper terminating: %s", reason)
+	if a.tickCancel != nil {
+		a.tickCancel()
+	}
+}
+
+func (a *RetentionReaper) purge() {
+	report, err := a.retentionService.Purge(context.Background(), false)
+	if err != nil {
+		// Partial failures still delete what they can; the next tick retries the rest
+		a.Log().Error("retention purge failed: %s", err)
+	}
+	if report == nil || len(report.Executions) == 0 {
+		return
+	}
+	total := 0
+	for _, n := range report.Executions {
+		total += n
+	}
+	a.Log().Info("retention purge deleted %d execution(s) across %d schema(s) (skipped %d with running sub-workflows)",
+		total, len(report.Executions), report.Skipped)
+}
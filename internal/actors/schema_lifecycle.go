@@ -0,0 +1,57 @@
+package actors
+
+import (
+	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/events"
+	internalworkflow "github.com/open-source-cloud/fuse/internal/workflow"
+)
+
+// schemaLifecycleMsg tells a trigger actor that a schema changed lifecycle state or was deleted.
+type schemaLifecycleMsg struct {
+	schemaID  string
+	lifecycle internalworkflow.SchemaLifecycle
+	deleted   bool
+}
+
+// keepsTriggers reports whether the schema's triggers should (still) be registered.
+func (m schemaLifecycleMsg) keepsTriggers() bool {
+	return !m.deleted && m.lifecycle.KeepsTriggers()
+}
+
+// subscribeSchemaLifecycle forwards schema lifecycle and deletion events from the bus to the
+// process mailbox, so trigger registrations are only ever mutated on the actor's own goroutine.
+// The bus is node-local; in cluster mode the schema replication actor republishes peers' changes on
+// it. Without clustering, HA nodes pick a change up on their next restart, and until then the
+// fire-time lifecycle check keeps deprecated and archived schemas from starting executions.
+func subscribeSchemaLifecycle(process gen.Process, eventBus events.EventBus) []events.SubscriptionID {
+	if eventBus == nil {
+		return nil
+	}
+	node, pid := process.Node(), process.PID()
+	forward := func(event events.Event) error {
+		schemaID, _ := event.Data["schemaId"].(string)
+		lifecycle, _ := event.Data["lifecycle"].(string)
+		return node.Send(pid, schemaLifecycleMsg{
+			schemaID:  schemaID,
+			lifecycle: internalworkflow.SchemaLifecycle(lifecycle),
+			deleted:   event.Type == events.EventSchemaDeleted,
+		})
+	}
+
+	var subs []events.SubscriptionID
+	for _, eventType := range []string{events.EventSchemaLifecycleChanged, events.EventSchemaDeleted} {
+		subID, err := eventBus.Subscribe(eventType, forward)
+		if err != nil {
+			process.Log().Error("failed to subscribe to %s events: %s", eventType, err)
+			continue
+		}
+		subs = append(subs, subID)
+	}
+	return subs
+}
+
+func unsubscribeAll(eventBus events.EventBus, subs []events.SubscriptionID) {
+	for _, subID := range subs {
+		_ = eventBus.Unsubscribe(subID)
+	}
+}
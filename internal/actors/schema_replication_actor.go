@@ -9,8 +9,10 @@ import (
 	etcdreg "ergo.services/registrar/etcd"
 	"github.com/open-source-cloud/fuse/internal/actors/actornames"
 	"github.com/open-source-cloud/fuse/internal/app/config"
+	"github.com/open-source-cloud/fuse/internal/events"
 	"github.com/open-source-cloud/fuse/internal/messaging"
 	"github.com/open-source-cloud/fuse/internal/services"
+	internalworkflow "github.com/open-source-cloud/fuse/internal/workflow"
 )
 
 // SchemaReplicationActorFactory builds the schema replication actor.
@@ -20,23 +22,27 @@ type SchemaReplicationActorFactory ActorFactory[*SchemaReplicationActor]
 func NewSchemaReplicationActorFactory(
 	cfg *config.Config,
 	graphService services.GraphService,
+	eventBus events.EventBus,
 ) *SchemaReplicationActorFactory {
 	return &SchemaReplicationActorFactory{
 		Factory: func() gen.ProcessBehavior {
 			return &SchemaReplicationActor{
 				config:       cfg,
 				graphService: graphService,
+				eventBus:     eventBus,
 			}
 		},
 	}
 }
 
-// SchemaReplicationActor registers an ergo Event for local graph upserts and monitors peer events.
+// SchemaReplicationActor registers an ergo Event for local graph upserts and lifecycle changes,
+// and monitors peer events.
 type SchemaReplicationActor struct {
 	act.Actor
 
 	config       *config.Config
 	graphService services.GraphService
+	eventBus     events.EventBus
 
 	eventToken gen.Ref
 
@@ -149,7 +155,7 @@ func (a *SchemaReplicationActor) monitorPeerNode(peer gen.Atom) error {
 	return nil
 }
 
-// HandleMessage receives local publish requests after HTTP upsert.
+// HandleMessage receives local publish requests after HTTP upsert, lifecycle change or delete.
 func (a *SchemaReplicationActor) HandleMessage(_ gen.PID, message any) error {
 	msg, ok := message.(messaging.Message)
 	if !ok {
@@ -208,6 +214,9 @@ func (a *SchemaReplicationActor) handleReplicationEvent(message gen.MessageEvent
 		a.Log().Debug("schema replication: skip non-payload event message %T", message.Message)
 		return nil
 	}
+	if len(payload.SchemaJSON) == 0 {
+		return a.republishLifecycle(payload)
+	}
 	if err := a.graphService.ApplyReplicatedUpsert(payload.SchemaID, payload.SchemaJSON); err != nil {
 		a.Log().Error("schema replication: ApplyReplicatedUpsert %s: %s", payload.SchemaID, err)
 		return nil
@@ -215,3 +224,18 @@ func (a *SchemaReplicationActor) handleReplicationEvent(message gen.MessageEvent
 	a.Log().Info("schema replication: applied schema %s from peer event", payload.SchemaID)
 	return nil
 }
+
+// republishLifecycle hands a peer's lifecycle change or deletion to this node's trigger actors.
+// The schema store is shared, so only the trigger registrations need to follow.
+func (a *SchemaReplicationActor) republishLifecycle(payload messaging.GraphSchemaReplicationPayload) error {
+	if a.eventBus == nil {
+		return nil
+	}
+	event := services.SchemaLifecycleEvent(payload.SchemaID, internalworkflow.SchemaLifecycle(payload.Lifecycle), payload.Deleted)
+	if err := a.eventBus.Publish(event); err != nil {
+		a.Log().Error("schema replication: publish %s of schema %s: %s", event.Type, payload.SchemaID, err)
+		return nil
+	}
+	a.Log().Info("schema replication: applied %s of schema %s from peer event", event.Type, payload.SchemaID)
+	return nil
+}
@@ -41,6 +41,9 @@ func (a *WebhookRouter) Init(_ ...any) error {
 	}
 
 	for _, item := range schemas {
+		if !item.Lifecycle.KeepsTriggers() {
+			continue
+		}
 		graph, gErr := a.graphService.FindByID(item.SchemaID)
 		if gErr != nil {
 			continue
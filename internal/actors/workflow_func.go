@@ -25,13 +25,14 @@ import (
 type WorkflowFuncFactory ActorFactory[*WorkflowFunc]
 
 // NewWorkflowFuncFactory creates a dependency injection factory of WorkflowFunc worker actor
-func NewWorkflowFuncFactory(packageRegistry packages.Registry, concurrencyMgr *concurrency.Manager, rateLimiter *concurrency.RateLimiter, fuseMetrics *metrics.FuseMetrics, tracingProvider *tracing.Provider) *WorkflowFuncFactory {
+func NewWorkflowFuncFactory(packageRegistry packages.Registry, concurrencyMgr *concurrency.Manager, rateLimiter *concurrency.RateLimiter, executions *concurrency.ExecutionContexts, fuseMetrics *metrics.FuseMetrics, tracingProvider *tracing.Provider) *WorkflowFuncFactory {
 	return &WorkflowFuncFactory{
 		Factory: func() gen.ProcessBehavior {
 			return &WorkflowFunc{
 				packageRegistry:    packageRegistry,
 				concurrencyManager: concurrencyMgr,
 				rateLimiter:        rateLimiter,
+				executions:         executions,
 				fuseMetrics:        fuseMetrics,
 				tracingProvider:    tracingProvider,
 			}
@@ -48,6 +49,7 @@ type WorkflowFunc struct {
 	packageRegistry    packages.Registry
 	concurrencyManager *concurrency.Manager
 	rateLimiter        *concurrency.RateLimiter
+	executions         *concurrency.ExecutionContexts
 	fuseMetrics        *metrics.FuseMetrics
 	tracingProvider    *tracing.Provider
 }
@@ -119,7 +121,7 @@ func (a *WorkflowFunc) HandleMessage(from gen.PID, message any) error {
 				},
 			}
 			resultMsg := messaging.NewFunctionResultMessage(msgPayload.WorkflowID, msgPayload.ThreadID, msgPayload.ExecID, rlResult)
-			return a.Send(actornames.WorkflowHandlerName(msgPayload.WorkflowID), resultMsg)
+			return a.Send(actornames.WorkflowHandlerName(workflow.NamespaceOf(msgPayload.SchemaID), msgPayload.WorkflowID), resultMsg)
 		}
 	}
 
@@ -133,11 +135,14 @@ func (a *WorkflowFunc) HandleMessage(from gen.PID, message any) error {
 		return nil
 	}
 
-	result, err := pkg.ExecuteFunction(
-		a,
-		msgPayload.FunctionID,
-		workflow.NewExecutionInfo(msgPayload.WorkflowID, msgPayload.ExecID, msgPayload.Environment, input),
-	)
+	execInfo := workflow.NewExecutionInfo(msgPayload.WorkflowID, msgPayload.ExecID, msgPayload.Environment, input)
+	execInfo.SchemaID = msgPayload.SchemaID
+	execInfo.LLMBudget = msgPayload.LLMBudget
+	execInfo.CallbackToken = msgPayload.CallbackToken
+	execInfo.Checkpoints = msgPayload.Checkpoints
+	// The workflow handler cancels this context when it stops waiting for the execution.
+	execInfo.Context = a.executions.Start(msgPayload.WorkflowID.String(), msgPayload.ExecID.String())
+	result, err := pkg.ExecuteFunction(a, msgPayload.FunctionID, execInfo)
 	if err != nil {
 		if result.Output.Status != workflow.FunctionError {
 			a.Log().Error("failed to execute function %s: %s", msgPayload.FunctionID, err)
@@ -170,7 +175,7 @@ func (a *WorkflowFunc) HandleMessage(from gen.PID, message any) error {
 	a.Log().Debug("execute function %s result: %s", msgPayload.FunctionID, string(jsonResult))
 
 	resultMsg := messaging.NewFunctionResultMessage(msgPayload.WorkflowID, msgPayload.ThreadID, msgPayload.ExecID, result)
-	err = a.Send(actornames.WorkflowHandlerName(msgPayload.WorkflowID), resultMsg)
+	err = a.Send(actornames.WorkflowHandlerName(workflow.NamespaceOf(msgPayload.SchemaID), msgPayload.WorkflowID), resultMsg)
 	if err != nil {
 		return err
 	}
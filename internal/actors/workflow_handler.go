@@ -3,11 +3,15 @@ package actors
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/open-source-cloud/fuse/internal/actors/actornames"
+	"github.com/open-source-cloud/fuse/internal/concurrency"
 	"github.com/open-source-cloud/fuse/internal/events"
 	"github.com/open-source-cloud/fuse/internal/metrics"
 	"github.com/open-source-cloud/fuse/internal/packages/functions/system"
@@ -47,6 +51,11 @@ func NewWorkflowHandlerFactory(
 	tracingProvider *tracing.Provider,
 	secretStore secrets.SecretStore,
 	claimRepo repositories.ClaimRepository,
+	callbackTokens services.CallbackTokenService,
+	envVarService services.EnvironmentVarService,
+	namespaceService services.NamespaceService,
+	executions *concurrency.ExecutionContexts,
+	waiters *concurrency.Waiters,
 ) *WorkflowHandlerFactory {
 	return &WorkflowHandlerFactory{
 		Factory: func() gen.ProcessBehavior {
@@ -63,6 +72,11 @@ func NewWorkflowHandlerFactory(
 				tracingProvider:    tracingProvider,
 				secretStore:        secretStore,
 				claimRepo:          claimRepo,
+				callbackTokens:     callbackTokens,
+				envVarService:      envVarService,
+				namespaceService:   namespaceService,
+				executions:         executions,
+				waiters:            waiters,
 			}
 		},
 	}
@@ -85,6 +99,11 @@ type (
 		tracingProvider    *tracing.Provider
 		secretStore        secrets.SecretStore
 		claimRepo          repositories.ClaimRepository
+		callbackTokens     services.CallbackTokenService
+		envVarService      services.EnvironmentVarService
+		namespaceService   services.NamespaceService
+		executions         *concurrency.ExecutionContexts
+		waiters            *concurrency.Waiters
 
 		workflow       *internalworkflow.Workflow
 		executionTimer *ExecutionTimer
@@ -98,6 +117,17 @@ type (
 		// iterThreadToForEach maps a live iteration thread ID back to its parent
 		// foreach execID so the completion handler can find the ForEachState.
 		iterThreadToForEach map[uint16]string
+
+		// toolCalls holds the async tool calls started by running nodes (ADR-0027): true while the
+		// call runs, false once its result was routed back, so late timeouts and duplicate results
+		// are dropped instead of reaching the graph.
+		toolCalls map[workflow.ExecID]bool
+
+		// triggerInput feeds search attribute expressions; it is not persisted, so after a restart
+		// input-based attributes keep the values already stored.
+		triggerInput map[string]any
+		// searchAttributes holds the last values written, so only changes are upserted.
+		searchAttributes map[string]string
 	}
 
 	// WorkflowHandlerInitArgs defines the typed arguments for the WorkflowHandler Actor Init message
@@ -105,6 +135,10 @@ type (
 		schemaID    string
 		workflowID  workflow.ID
 		environment string
+		// schemaVersion pins a new execution to a specific schema version; zero runs the active one.
+		schemaVersion int
+		// input is the trigger input, only known when the execution is first spawned.
+		input map[string]any
 	}
 )
 
@@ -117,6 +151,7 @@ func (a *WorkflowHandler) Init(args ...any) error {
 	a.spanCtx = context.Background()
 	a.forEachStates = make(map[string]*internalworkflow.ForEachState)
 	a.iterThreadToForEach = make(map[uint16]string)
+	a.toolCalls = make(map[workflow.ExecID]bool)
 
 	if len(args) != 1 {
 		return fmt.Errorf("workflow actor init args must be 1 == [WorkflowHandlerInitArgs]")
@@ -125,6 +160,7 @@ func (a *WorkflowHandler) Init(args ...any) error {
 	if !ok {
 		return fmt.Errorf("workflow actor init args must be 1 == [WorkflowHandlerInitArgs]; got %T", args[0])
 	}
+	a.triggerInput = initArgs.input
 
 	if a.workflowRepository.Exists(initArgs.workflowID.String()) {
 		a.workflow, _ = a.workflowRepository.Get(initArgs.workflowID.String())
@@ -135,7 +171,7 @@ func (a *WorkflowHandler) Init(args ...any) error {
 		}
 		// Replay: the environment comes from the reconstructed workflow, not init args, so
 		// resolution stays deterministic across restart/recovery (ADR-0031).
-		a.workflow.SetSecretResolver(a.newSecretResolver(a.workflow.Environment()))
+		a.workflow.SetSecretResolver(a.newSecretResolver(a.workflow.Namespace(), a.workflow.Environment()))
 		if err := a.graphService.EnsureNodeMetadata(a.workflow.Graph()); err != nil {
 			a.Log().Error("failed to populate graph node metadata for workflow %s: %s", initArgs.workflowID, err)
 			return gen.TerminateReasonPanic
@@ -153,6 +189,11 @@ func (a *WorkflowHandler) Init(args ...any) error {
 			} else {
 				a.workflow.Journal().LoadFrom(entries)
 			}
+			// The nodes that started these tool calls are replayed and will start new ones; the
+			// results of the old calls are dropped when they arrive.
+			for execID := range a.workflow.OpenToolCalls() {
+				a.toolCalls[execID] = true
+			}
 			action = a.workflow.Resume()
 		}
 		if action != nil {
@@ -165,7 +206,7 @@ func (a *WorkflowHandler) Init(args ...any) error {
 	}
 
 	// doesnt exist - create
-	graphRef, err := a.graphService.FindByID(initArgs.schemaID)
+	graphRef, schemaVersion, err := a.resolveGraph(initArgs.schemaID, initArgs.schemaVersion)
 	if err != nil {
 		a.Log().Error("failed to get graph for schema id %s: %s", initArgs.schemaID, err)
 		return gen.TerminateReasonPanic
@@ -175,7 +216,16 @@ func (a *WorkflowHandler) Init(args ...any) error {
 		env = a.config.Environment
 	}
 	a.workflow = internalworkflow.New(initArgs.workflowID, graphRef, env)
-	a.workflow.SetSecretResolver(a.newSecretResolver(env))
+	a.workflow.SetSchemaVersion(schemaVersion)
+	// Pin the environment's variables now; replay reads them back from the persisted workflow.
+	vars, err := a.envVarService.FindAll(a.workflow.Namespace(), env)
+	if err != nil {
+		a.Log().Error("failed to load environment variables for workflow %s: %s", initArgs.workflowID, err)
+		return gen.TerminateReasonPanic
+	}
+	a.workflow.SetVars(vars)
+	a.workflow.SetInput(initArgs.input)
+	a.workflow.SetSecretResolver(a.newSecretResolver(a.workflow.Namespace(), env))
 	if a.workflowRepository.Save(a.workflow) != nil {
 		a.Log().Error("failed to save workflow for id %s: %s", initArgs.workflowID, err)
 		return nil
@@ -198,14 +248,34 @@ func (a *WorkflowHandler) Init(args ...any) error {
 	return nil
 }
 
-// newSecretResolver builds a secret resolver scoped to the workflow's environment, falling
-// back to the engine default when none was recorded (ADR-0031). Each workflow gets its own
-// resolver so different executions can resolve against different environments.
-func (a *WorkflowHandler) newSecretResolver(environment string) secrets.Resolver {
+// resolveGraph loads the graph a new execution runs on together with the schema version it
+// came from. A pinned version is loaded as-is; otherwise the active version is used and its
+// number is looked up so the execution still records which version it ran.
+func (a *WorkflowHandler) resolveGraph(schemaID string, version int) (*internalworkflow.Graph, int, error) {
+	if version > 0 {
+		graph, err := a.graphService.FindByIDAndVersion(schemaID, version)
+		return graph, version, err
+	}
+	graph, err := a.graphService.FindByID(schemaID)
+	if err != nil {
+		return nil, 0, err
+	}
+	history, err := a.graphService.GetVersionHistory(schemaID)
+	if err != nil {
+		a.Log().Warning("failed to get active version for schema %s: %s", schemaID, err)
+		return graph, 0, nil
+	}
+	return graph, history.ActiveVersion, nil
+}
+
+// newSecretResolver builds a secret resolver scoped to the workflow's namespace and environment,
+// falling back to the engine default environment when none was recorded (ADR-0031). Each workflow
+// gets its own resolver so different executions can resolve against different scopes.
+func (a *WorkflowHandler) newSecretResolver(namespace, environment string) secrets.Resolver {
 	if environment == "" {
 		environment = a.config.Environment
 	}
-	return secrets.NewResolver(a.secretStore, environment)
+	return secrets.NewNamespacedResolver(a.secretStore, namespace, environment)
 }
 
 // startRootSpan begins the OTel root span for this workflow and increments active workflow metrics.
@@ -216,8 +286,10 @@ func (a *WorkflowHandler) startRootSpan() {
 		"workflow.execute",
 		attribute.String("workflow.id", a.workflow.ID().String()),
 		attribute.String("workflow.schema_id", a.workflow.Graph().ID()),
+		attribute.String("workflow.namespace", a.workflow.Namespace()),
 	)
 	a.fuseMetrics.WorkflowsActive.Inc()
+	a.fuseMetrics.NamespaceWorkflowsActive.WithLabelValues(a.workflow.Namespace()).Inc()
 }
 
 // HandleMessage processes messages that are sent to a WorkflowHandler actor
@@ -236,6 +308,8 @@ func (a *WorkflowHandler) HandleMessage(from gen.PID, message any) error {
 		return a.handleMsgFunctionResult(msg)
 	case messaging.AsyncFunctionResult:
 		return a.handleMsgAsyncFunctionResult(msg)
+	case messaging.FunctionCheckpoint:
+		return a.handleMsgFunctionCheckpoint(msg)
 	case messaging.Timeout:
 		return a.handleMsgTimeout(msg)
 	case messaging.WorkflowTimeout:
@@ -250,6 +324,8 @@ func (a *WorkflowHandler) HandleMessage(from gen.PID, message any) error {
 		return a.handleMsgSubWorkflowCompleted(msg)
 	case messaging.RetryNode:
 		return a.handleMsgRetryNode(msg)
+	case messaging.InvokeTool:
+		return a.handleMsgInvokeTool(msg)
 	}
 
 	return nil
@@ -258,6 +334,11 @@ func (a *WorkflowHandler) HandleMessage(from gen.PID, message any) error {
 // Terminate is called whenever a WorkflowHandler actor gets terminated
 func (a *WorkflowHandler) Terminate(reason error) {
 	a.Log().Info("%s terminated with reason: %s", a.PID(), reason)
+	// Stop in-flight work on this node; a replay resumes it from its checkpoints.
+	if a.workflow != nil {
+		a.executions.CancelWorkflow(a.workflow.ID().String())
+		a.waiters.ForgetWorkflow(a.workflow.ID().String())
+	}
 }
 
 func (a *WorkflowHandler) handleMsgFunctionResult(msg messaging.Message) error {
@@ -271,6 +352,13 @@ func (a *WorkflowHandler) handleMsgFunctionResult(msg messaging.Message) error {
 		return nil
 	}
 
+	if a.isToolCall(fnResultMsg.ExecID) {
+		if !fnResultMsg.Result.Async {
+			a.completeToolCall(fnResultMsg.ExecID, fnResultMsg.Result.Output)
+		}
+		return nil
+	}
+
 	a.cancelExecutionTimeout(fnResultMsg.ExecID)
 	a.workflow.SetResultFor(fnResultMsg.ExecID, &fnResultMsg.Result)
 
@@ -279,6 +367,7 @@ func (a *WorkflowHandler) handleMsgFunctionResult(msg messaging.Message) error {
 		a.persistJournal()
 		return nil
 	}
+	a.releaseExecution(fnResultMsg.ExecID)
 	if fnResultMsg.Result.Output.Status != workflow.FunctionSuccess {
 		a.Log().Error(
 			"function result for workflow %s, execID %s failed with status %s",
@@ -298,6 +387,7 @@ func (a *WorkflowHandler) handleMsgFunctionResult(msg messaging.Message) error {
 
 	action := a.workflow.Next(fnResultMsg.ThreadID)
 	a.persistJournal()
+	a.upsertSearchAttributes()
 	if action.Type() == workflowactions.ActionNoop {
 		if a.handleForEachIterationComplete(fnResultMsg.ThreadID) {
 			return nil
@@ -321,7 +411,13 @@ func (a *WorkflowHandler) handleMsgAsyncFunctionResult(msg messaging.Message) er
 		return nil
 	}
 
+	if a.isToolCall(fnResultMsg.ExecID) {
+		a.completeToolCall(fnResultMsg.ExecID, fnResultMsg.Output)
+		return nil
+	}
+
 	a.cancelExecutionTimeout(fnResultMsg.ExecID)
+	a.releaseExecution(fnResultMsg.ExecID)
 	a.workflow.SetResultFor(fnResultMsg.ExecID, &workflow.FunctionResult{
 		Async:  true,
 		Output: fnResultMsg.Output,
@@ -345,6 +441,7 @@ func (a *WorkflowHandler) handleMsgAsyncFunctionResult(msg messaging.Message) er
 
 	action := a.workflow.Next(fnResultMsg.ExecID.Thread())
 	a.persistJournal()
+	a.upsertSearchAttributes()
 	if action.Type() == workflowactions.ActionNoop {
 		if a.handleForEachIterationComplete(fnResultMsg.ExecID.Thread()) {
 			return nil
@@ -357,6 +454,32 @@ func (a *WorkflowHandler) handleMsgAsyncFunctionResult(msg messaging.Message) er
 	return nil
 }
 
+// handleMsgFunctionCheckpoint journals a sub-step of a running execution and persists it right
+// away, so a replay after a crash or failover resumes from it.
+func (a *WorkflowHandler) handleMsgFunctionCheckpoint(msg messaging.Message) error {
+	checkpointMsg, err := msg.FunctionCheckpointMessage()
+	if err != nil {
+		a.Log().Error("failed to get function checkpoint from %s", msg)
+		return nil
+	}
+
+	if a.isTerminalState() {
+		a.Log().Debug("ignoring checkpoint for %s workflow %s", a.workflow.State(), a.workflow.ID())
+		return nil
+	}
+	if a.isToolCall(checkpointMsg.ExecID) {
+		// A tool call is restarted by its caller rather than resumed, so its sub-steps are not kept.
+		return nil
+	}
+	if !a.workflow.Checkpoint(checkpointMsg.ExecID, checkpointMsg.Index, checkpointMsg.Data) {
+		a.Log().Warning("ignoring checkpoint %d for exec %s: not the next checkpoint of a running attempt",
+			checkpointMsg.Index, checkpointMsg.ExecID)
+		return nil
+	}
+	a.persistJournal()
+	return nil
+}
+
 // claimForThisNode claims the workflow for this node before running it (ADR-0018, fixes #89). It
 // returns true when this node owns the workflow and may run it. On loss (another live node already
 // owns it) it stops this duplicate instance tree by telling the instance supervisor the workflow is
@@ -394,6 +517,30 @@ func (a *WorkflowHandler) persistWorkflowState() {
 	if err := a.workflowRepository.Save(a.workflow); err != nil {
 		a.Log().Error("failed to persist workflow state for %s: %s", a.workflow.ID(), err)
 	}
+	a.upsertSearchAttributes()
+}
+
+// upsertSearchAttributes evaluates the schema's search attributes and stores the values that
+// changed since the last call.
+func (a *WorkflowHandler) upsertSearchAttributes() {
+	values := a.workflow.SearchAttributes(a.triggerInput)
+	changed := make(map[string]string, len(values))
+	for name, value := range values {
+		if current, ok := a.searchAttributes[name]; !ok || current != value {
+			changed[name] = value
+		}
+	}
+	if len(changed) == 0 {
+		return
+	}
+	if err := a.workflowRepository.UpsertSearchAttributes(a.workflow.ID().String(), changed); err != nil {
+		a.Log().Error("failed to upsert search attributes for %s: %s", a.workflow.ID(), err)
+		return
+	}
+	if a.searchAttributes == nil {
+		a.searchAttributes = make(map[string]string, len(changed))
+	}
+	maps.Copy(a.searchAttributes, changed)
 }
 
 func (a *WorkflowHandler) persistSnapshot() {
@@ -428,6 +575,10 @@ func (a *WorkflowHandler) persistTrace() {
 		a.workflow.Graph().ID(),
 		a.workflow.Journal().Entries(),
 	)
+	if ref, err := a.workflowRepository.FindSubWorkflowRef(a.workflow.ID().String()); err == nil && ref != nil {
+		trace.ParentWorkflowID = ref.ParentWorkflowID.String()
+		trace.ParentExecID = ref.ParentExecID.String()
+	}
 	if err := a.traceRepo.Save(trace); err != nil {
 		a.Log().Error("failed to persist execution trace for %s: %s", a.workflow.ID(), err)
 	}
@@ -450,9 +601,10 @@ func (a *WorkflowHandler) publishLifecycleEvent() {
 		Type:   eventType,
 		Source: a.workflow.ID().String(),
 		Data: map[string]any{
-			"workflowId": a.workflow.ID().String(),
-			"schemaId":   a.workflow.Graph().ID(),
-			"status":     a.workflow.State().String(),
+			"workflowId":    a.workflow.ID().String(),
+			"schemaId":      a.workflow.Graph().ID(),
+			"schemaVersion": a.workflow.SchemaVersion(),
+			"status":        a.workflow.State().String(),
 		},
 	}); err != nil {
 		a.Log().Error("failed to publish lifecycle event for %s: %s", a.workflow.ID(), err)
@@ -496,6 +648,7 @@ func (a *WorkflowHandler) sendWorkflowCompleted() {
 
 	// Record metrics and end OTel root span.
 	a.fuseMetrics.WorkflowsActive.Dec()
+	a.fuseMetrics.NamespaceWorkflowsActive.WithLabelValues(a.workflow.Namespace()).Dec()
 	switch a.workflow.State() {
 	case internalworkflow.StateFinished:
 		a.fuseMetrics.WorkflowsCompleted.Inc()
@@ -507,14 +660,20 @@ func (a *WorkflowHandler) sendWorkflowCompleted() {
 		a.fuseMetrics.WorkflowsCancelled.Inc()
 		a.rootSpan.SetStatus(codes.Error, "workflow cancelled")
 	}
+	a.fuseMetrics.NamespaceWorkflowOutcomes.WithLabelValues(a.workflow.Namespace(), a.workflow.State().String()).Inc()
+	if version := a.workflow.SchemaVersion(); version > 0 {
+		a.fuseMetrics.WorkflowVersionOutcomes.WithLabelValues(a.workflow.Graph().ID(), strconv.Itoa(version), a.workflow.State().String()).Inc()
+	}
 	a.rootSpan.SetAttributes(attribute.String("workflow.final_state", a.workflow.State().String()))
 	a.rootSpan.End()
+	a.executions.CancelWorkflow(a.workflow.ID().String())
 
 	// Persist the terminal state, execution snapshot, and trace
 	a.persistJournal()
 	if err := a.workflowRepository.Save(a.workflow); err != nil {
 		a.Log().Error("failed to persist terminal state for workflow %s: %s", a.workflow.ID(), err)
 	}
+	a.upsertSearchAttributes()
 	a.persistSnapshot()
 	a.persistTrace()
 	a.publishLifecycleEvent()
@@ -556,6 +715,11 @@ func (a *WorkflowHandler) handleMsgTimeout(msg messaging.Message) error {
 
 	a.Log().Warning("execution timeout for exec %s", timeoutMsg.ExecID)
 	execID := workflow.ExecID(timeoutMsg.ExecID)
+	if a.isToolCall(execID) {
+		a.completeToolCall(execID, workflow.NewFunctionOutput(workflow.FunctionError, map[string]any{"error": "tool call timeout exceeded"}))
+		return nil
+	}
+	a.releaseExecution(execID)
 
 	// Create a timeout error result and feed through normal error handling
 	result := &workflow.FunctionResult{
@@ -592,12 +756,13 @@ func (a *WorkflowHandler) handleMsgCancelWorkflow(msg messaging.Message) error {
 
 	a.workflow.SetState(internalworkflow.StateCancelled)
 	a.executionTimer.CancelAll()
+	a.executions.CancelWorkflow(a.workflow.ID().String())
 	a.persistJournal()
 
 	// Cascade cancel to active sub-workflows
 	children, _ := a.workflowRepository.FindActiveSubWorkflows(a.workflow.ID().String())
 	for _, child := range children {
-		childCancelMsg := messaging.NewCancelWorkflowMessage(child.ChildWorkflowID, "parent cancelled")
+		childCancelMsg := messaging.NewCancelWorkflowMessage(a.workflow.Namespace(), child.ChildWorkflowID, "parent cancelled")
 		if err := a.Send(gen.Atom(actornames.WorkflowSupervisorName), childCancelMsg); err != nil {
 			a.Log().Error("failed to cascade cancel to sub-workflow %s: %s", child.ChildWorkflowID, err)
 		}
@@ -626,10 +791,38 @@ func (a *WorkflowHandler) startExecutionTimeout(execID workflow.ExecID, node *in
 	a.executionTimer.Start(a, a.PID(), execID.String(), node.Schema().Timeout.Execution.Duration())
 }
 
+// mintExecCallbackToken returns the token authorizing the async result of execID. It expires with
+// the node's execution timeout, or after the configured default lifetime when the node has none.
+func (a *WorkflowHandler) mintExecCallbackToken(execID workflow.ExecID) string {
+	var ttl time.Duration
+	if entry, exists := a.workflow.AuditLog().Get(execID.String()); exists {
+		if node, err := a.workflow.Graph().FindNode(entry.FunctionNodeID); err == nil &&
+			node.Schema().Timeout != nil {
+			ttl = node.Schema().Timeout.Execution.Duration()
+		}
+	}
+	return a.callbackTokens.Mint(services.CallbackExec, a.workflow.ID().String(), execID.String(), ttl)
+}
+
 func (a *WorkflowHandler) cancelExecutionTimeout(execID workflow.ExecID) {
 	a.executionTimer.Cancel(execID.String())
 }
 
+// executionScope is the workflow-level context sent with every function execution.
+func (a *WorkflowHandler) executionScope() messaging.ExecutionScope {
+	schema := a.workflow.Schema()
+	return messaging.ExecutionScope{
+		Environment: a.workflow.Environment(),
+		SchemaID:    schema.ID,
+		LLMBudget:   schema.LLMBudget,
+	}
+}
+
+// releaseExecution cancels the context of an execution the handler no longer waits for.
+func (a *WorkflowHandler) releaseExecution(execID workflow.ExecID) {
+	a.executions.Cancel(a.workflow.ID().String(), execID.String())
+}
+
 func (a *WorkflowHandler) startWorkflowTimeout() {
 	schema := a.workflow.Schema()
 	if schema.Timeout == nil || schema.Timeout.Total == 0 {
@@ -660,7 +853,8 @@ func (a *WorkflowHandler) handleWorkflowAction(action workflowactions.Action) {
 		a.Log().Info("scheduling retry attempt %d for exec %s in %s",
 			retryAction.Attempt, retryAction.FunctionExecID, retryAction.Delay)
 		workflowPool := WorkflowFuncPoolName(a.workflow.ID())
-		retryMsg := messaging.NewExecuteFunctionMessage(a.workflow.ID(), &retryAction.RunFunctionAction, a.workflow.Environment(), a.tracingProvider.InjectCarrier(a.spanCtx))
+		retryMsg := messaging.NewExecuteFunctionMessage(a.workflow.ID(), &retryAction.RunFunctionAction, a.executionScope(),
+			a.mintExecCallbackToken(retryAction.FunctionExecID), nil, a.tracingProvider.InjectCarrier(a.spanCtx))
 		if _, err := a.SendAfter(gen.Atom(workflowPool), retryMsg, retryAction.Delay); err != nil {
 			a.Log().Error("failed to schedule retry: %s", err)
 		}
@@ -696,7 +890,8 @@ func (a *WorkflowHandler) handleWorkflowRunFunctionAction(action workflowactions
 		}
 	}
 
-	execFnMsg := messaging.NewExecuteFunctionMessage(a.workflow.ID(), execAction, a.workflow.Environment(), a.tracingProvider.InjectCarrier(a.spanCtx))
+	execFnMsg := messaging.NewExecuteFunctionMessage(a.workflow.ID(), execAction, a.executionScope(),
+		a.mintExecCallbackToken(execAction.FunctionExecID), a.workflow.Checkpoints(execAction.FunctionExecID), a.tracingProvider.InjectCarrier(a.spanCtx))
 	err := a.Send(workflowPool, execFnMsg)
 	if err != nil {
 		a.Log().Error("failed to send execute function message to %s: %s", workflowPool, err)
@@ -708,10 +903,9 @@ func (a *WorkflowHandler) handleWorkflowRunFunctionAction(action workflowactions
 // --- Sleep / Wait ---
 
 func (a *WorkflowHandler) handleSystemSleep(action *workflowactions.RunFunctionAction) {
-	durationStr, _ := action.Args["duration"].(string)
-	duration, err := time.ParseDuration(durationStr)
+	duration, err := sleepDuration(action.Args)
 	if err != nil {
-		a.Log().Error("invalid sleep duration %q: %s", durationStr, err)
+		a.Log().Error("%s", err)
 		return
 	}
 	reason, _ := action.Args["reason"].(string)
@@ -725,9 +919,24 @@ func (a *WorkflowHandler) handleSystemSleep(action *workflowactions.RunFunctionA
 }
 
 func (a *WorkflowHandler) handleSystemWait(action *workflowactions.RunFunctionAction) {
-	awakeableID := uuid.New().String()
+	a.handleWaitForEventAction(a.waitForEventAction(action.ThreadID, action.FunctionExecID, action.Args))
+}
+
+// sleepDuration reads the duration argument of system/sleep.
+func sleepDuration(args map[string]any) (time.Duration, error) {
+	durationStr, _ := args["duration"].(string)
+	duration, err := time.ParseDuration(durationStr)
+	if err != nil {
+		return 0, fmt.Errorf("invalid sleep duration %q: %w", durationStr, err)
+	}
+	return duration, nil
+}
+
+// waitForEventAction builds the wait of a system/wait execution from its arguments, with a new
+// awakeable id.
+func (a *WorkflowHandler) waitForEventAction(threadID uint16, execID workflow.ExecID, args map[string]any) *workflowactions.WaitForEventAction {
 	var timeout time.Duration
-	if timeoutStr, ok := action.Args["timeout"].(string); ok && timeoutStr != "" {
+	if timeoutStr, ok := args["timeout"].(string); ok && timeoutStr != "" {
 		parsed, err := time.ParseDuration(timeoutStr)
 		if err != nil {
 			a.Log().Error("invalid wait timeout %q: %s", timeoutStr, err)
@@ -735,15 +944,15 @@ func (a *WorkflowHandler) handleSystemWait(action *workflowactions.RunFunctionAc
 			timeout = parsed
 		}
 	}
-	filter, _ := action.Args["filter"].(string)
+	filter, _ := args["filter"].(string)
 
-	a.handleWaitForEventAction(&workflowactions.WaitForEventAction{
-		ThreadID:    action.ThreadID,
-		ExecID:      action.FunctionExecID,
-		AwakeableID: awakeableID,
+	return &workflowactions.WaitForEventAction{
+		ThreadID:    threadID,
+		ExecID:      execID,
+		AwakeableID: uuid.New().String(),
 		Timeout:     timeout,
 		Filter:      filter,
-	})
+	}
 }
 
 func (a *WorkflowHandler) handleSleepAction(action *workflowactions.SleepAction) {
@@ -764,6 +973,13 @@ func (a *WorkflowHandler) handleSleepAction(action *workflowactions.SleepAction)
 
 func (a *WorkflowHandler) handleWaitForEventAction(action *workflowactions.WaitForEventAction) {
 	a.workflow.SetState(internalworkflow.StateSleeping)
+	a.createAwakeable(action)
+	a.persistWorkflowState()
+}
+
+// createAwakeable saves and journals the awakeable a wait is resolved through, and schedules its
+// timeout.
+func (a *WorkflowHandler) createAwakeable(action *workflowactions.WaitForEventAction) {
 	now := time.Now()
 	awakeable := &internalworkflow.Awakeable{
 		ID:         action.AwakeableID,
@@ -782,9 +998,11 @@ func (a *WorkflowHandler) handleWaitForEventAction(action *workflowactions.WaitF
 		Type:     internalworkflow.JournalAwakeableCreated,
 		ThreadID: action.ThreadID,
 		ExecID:   action.ExecID.String(),
-		Data:     map[string]any{"awakeableId": action.AwakeableID, "timeout": action.Timeout.String()},
+		Data: map[string]any{
+			"awakeableId": action.AwakeableID,
+			"timeout":     action.Timeout.String(),
+		},
 	})
-	a.persistWorkflowState()
 
 	if action.Timeout > 0 {
 		timeoutMsg := messaging.NewTimeoutMessage(action.ExecID.String())
@@ -804,6 +1022,10 @@ func (a *WorkflowHandler) handleMsgSleepWakeUp(msg messaging.Message) error {
 		a.Log().Warning("ignoring sleep wake-up for cancelled workflow %s", a.workflow.ID())
 		return nil
 	}
+	if a.isToolCall(wakeUpMsg.ExecID) {
+		a.completeToolCall(wakeUpMsg.ExecID, workflow.NewFunctionSuccessOutput(map[string]any{"sleptFor": "completed"}))
+		return nil
+	}
 
 	a.workflow.SetState(internalworkflow.StateRunning)
 	a.workflow.SetResultFor(wakeUpMsg.ExecID, &workflow.FunctionResult{
@@ -837,6 +1059,13 @@ func (a *WorkflowHandler) handleMsgAwakeableResolved(msg messaging.Message) erro
 		a.Log().Warning("ignoring awakeable resolved for cancelled workflow %s", a.workflow.ID())
 		return nil
 	}
+	if a.isToolCall(resolvedMsg.ExecID) {
+		a.completeToolCall(resolvedMsg.ExecID, workflow.NewFunctionSuccessOutput(map[string]any{
+			"data":     resolvedMsg.Data,
+			"timedOut": false,
+		}))
+		return nil
+	}
 
 	a.workflow.SetState(internalworkflow.StateRunning)
 	a.workflow.SetResultFor(resolvedMsg.ExecID, &workflow.FunctionResult{
@@ -867,7 +1096,7 @@ func (a *WorkflowHandler) notifyParentIfSubWorkflow() {
 	if err != nil || ref == nil || ref.Async {
 		return
 	}
-	parentHandlerName := actornames.WorkflowHandlerName(ref.ParentWorkflowID)
+	parentHandlerName := actornames.WorkflowHandlerName(a.workflow.Namespace(), ref.ParentWorkflowID)
 	subCompletedMsg := messaging.NewSubWorkflowCompletedMessage(
 		ref.ParentWorkflowID,
 		ref.ParentThreadID,
@@ -899,7 +1128,67 @@ func (a *WorkflowHandler) handleSystemSubWorkflow(action *workflowactions.RunFun
 }
 
 func (a *WorkflowHandler) handleSubWorkflowAction(action *workflowactions.RunSubWorkflowAction) {
+	childWorkflowID, err := a.startSubWorkflow(action)
+	if err != nil {
+		a.failSubWorkflowNode(action, err)
+		return
+	}
+
+	if action.Async {
+		a.workflow.SetResultFor(action.ParentExecID, &workflow.FunctionResult{
+			Output: triggeredSubWorkflowOutput(childWorkflowID),
+		})
+		a.persistJournal()
+		nextAction := a.workflow.Next(action.ParentThreadID)
+		if nextAction.Type() == workflowactions.ActionNoop {
+			a.checkWorkflowCompletion()
+			return
+		}
+		a.handleWorkflowAction(nextAction)
+	} else {
+		a.workflow.SetState(internalworkflow.StateSleeping)
+		a.persistWorkflowState()
+	}
+}
+
+// failSubWorkflowNode fails the sub-workflow node whose child could not be started, so its retry
+// policy or error edges take over as for any failed function.
+func (a *WorkflowHandler) failSubWorkflowNode(action *workflowactions.RunSubWorkflowAction, err error) {
+	a.Log().Error("sub-workflow node %s failed to start its child: %s", action.ParentExecID, err)
+	a.workflow.SetResultFor(action.ParentExecID, &workflow.FunctionResult{Output: subWorkflowStartError(err)})
+	nextAction := a.workflow.HandleNodeFailure(action.ParentThreadID, action.ParentExecID)
+	if nextAction == nil {
+		a.completeWithError()
+		return
+	}
+	a.persistJournal()
+	a.handleWorkflowAction(nextAction)
+}
+
+// subWorkflowStartError is the error output of a sub-workflow step or tool call whose child could
+// not be started. A refused namespace quota is tagged so onError edges and ai/orchestrate can tell
+// it from a failed child.
+func subWorkflowStartError(err error) workflow.FunctionOutput {
+	data := map[string]any{"error": err.Error()}
+	if errors.Is(err, services.ErrNamespaceQuotaExceeded) {
+		data["errorType"] = system.ErrorTypeQuotaExceeded
+	}
+	return workflow.NewFunctionOutput(workflow.FunctionError, data)
+}
+
+// startSubWorkflow records and triggers the child workflow of a sub-workflow action. Children count
+// against the namespace's concurrency quota like any other execution, so a parent cannot fan out
+// past it.
+func (a *WorkflowHandler) startSubWorkflow(action *workflowactions.RunSubWorkflowAction) (workflow.ID, error) {
+	if err := a.namespaceService.CheckConcurrency(a.workflow.Namespace()); err != nil {
+		return "", err
+	}
+
 	childWorkflowID := workflow.NewID()
+	// Child schemas resolve inside the parent's namespace; an explicit namespace is ignored so a
+	// sub-workflow can never start a schema of another tenant.
+	_, childSchemaID := workflow.SplitQualifiedID(action.SchemaID)
+	action.SchemaID = workflow.QualifyID(a.workflow.Namespace(), childSchemaID)
 
 	ref := &internalworkflow.SubWorkflowRef{
 		ParentWorkflowID: action.ParentWorkflowID,
@@ -910,8 +1199,7 @@ func (a *WorkflowHandler) handleSubWorkflowAction(action *workflowactions.RunSub
 		Async:            action.Async,
 	}
 	if err := a.workflowRepository.SaveSubWorkflowRef(ref); err != nil {
-		a.Log().Error("failed to save sub-workflow ref: %s", err)
-		return
+		return "", fmt.Errorf("failed to save sub-workflow ref: %w", err)
 	}
 
 	a.workflow.Journal().Append(internalworkflow.JournalEntry{
@@ -926,31 +1214,21 @@ func (a *WorkflowHandler) handleSubWorkflowAction(action *workflowactions.RunSub
 	})
 
 	// Sub-workflows inherit the parent's environment so secret resolution stays consistent (ADR-0031).
-	triggerMsg := messaging.NewTriggerWorkflowWithEnvMessage(action.SchemaID, childWorkflowID, a.workflow.Environment())
+	triggerMsg := messaging.NewTriggerSubWorkflowMessage(action.SchemaID, childWorkflowID, a.workflow.Environment(), action.Input)
 	if err := a.Send(gen.Atom(actornames.WorkflowSupervisorName), triggerMsg); err != nil {
-		a.Log().Error("failed to trigger sub-workflow: %s", err)
-		return
+		return "", fmt.Errorf("failed to trigger sub-workflow: %w", err)
 	}
+	return childWorkflowID, nil
+}
 
-	if action.Async {
-		a.workflow.SetResultFor(action.ParentExecID, &workflow.FunctionResult{
-			Output: workflow.NewFunctionSuccessOutput(map[string]any{
-				"workflowId": childWorkflowID.String(),
-				"status":     "triggered",
-				"output":     nil,
-			}),
-		})
-		a.persistJournal()
-		nextAction := a.workflow.Next(action.ParentThreadID)
-		if nextAction.Type() == workflowactions.ActionNoop {
-			a.checkWorkflowCompletion()
-			return
-		}
-		a.handleWorkflowAction(nextAction)
-	} else {
-		a.workflow.SetState(internalworkflow.StateSleeping)
-		a.persistWorkflowState()
-	}
+// triggeredSubWorkflowOutput is the output of an async sub-workflow, which completes once the
+// child is triggered.
+func triggeredSubWorkflowOutput(childWorkflowID workflow.ID) workflow.FunctionOutput {
+	return workflow.NewFunctionSuccessOutput(map[string]any{
+		"workflowId": childWorkflowID.String(),
+		"status":     "triggered",
+		"output":     nil,
+	})
 }
 
 // --- ForEach ---
@@ -1041,7 +1319,8 @@ func (a *WorkflowHandler) spawnForEachBatch(state *internalworkflow.ForEachState
 	a.iterThreadToForEach[iterThreadID] = state.ExecID.String()
 
 	workflowPool := WorkflowFuncPoolName(a.workflow.ID())
-	execFnMsg := messaging.NewExecuteFunctionMessage(a.workflow.ID(), runAction, a.workflow.Environment(), a.tracingProvider.InjectCarrier(a.spanCtx))
+	execFnMsg := messaging.NewExecuteFunctionMessage(a.workflow.ID(), runAction, a.executionScope(),
+		a.mintExecCallbackToken(runAction.FunctionExecID), a.workflow.Checkpoints(runAction.FunctionExecID), a.tracingProvider.InjectCarrier(a.spanCtx))
 	if err := a.Send(workflowPool, execFnMsg); err != nil {
 		a.Log().Error("foreach: failed to dispatch iteration %d: %s", batchIndex, err)
 	}
@@ -1165,23 +1444,14 @@ func (a *WorkflowHandler) handleMsgSubWorkflowCompleted(msg messaging.Message) e
 		return nil
 	}
 
-	a.workflow.SetState(internalworkflow.StateRunning)
-
-	outputStatus := workflow.FunctionSuccess
-	if completedMsg.ChildFinalState != internalworkflow.StateFinished.String() {
-		outputStatus = workflow.FunctionError
+	output := subWorkflowOutput(completedMsg.ChildWorkflowID, completedMsg.ChildFinalState, completedMsg.ChildOutput)
+	if a.isToolCall(completedMsg.ParentExecID) {
+		a.completeToolCall(completedMsg.ParentExecID, output)
+		return nil
 	}
 
-	a.workflow.SetResultFor(completedMsg.ParentExecID, &workflow.FunctionResult{
-		Output: workflow.FunctionOutput{
-			Status: outputStatus,
-			Data: map[string]any{
-				"workflowId": completedMsg.ChildWorkflowID.String(),
-				"status":     completedMsg.ChildFinalState,
-				"output":     completedMsg.ChildOutput,
-			},
-		},
-	})
+	a.workflow.SetState(internalworkflow.StateRunning)
+	a.workflow.SetResultFor(completedMsg.ParentExecID, &workflow.FunctionResult{Output: output})
 	a.workflow.Journal().Append(internalworkflow.JournalEntry{
 		Type:     internalworkflow.JournalSubWorkflowCompleted,
 		ThreadID: completedMsg.ParentThreadID,
@@ -1201,3 +1471,167 @@ func (a *WorkflowHandler) handleMsgSubWorkflowCompleted(msg messaging.Message) e
 	a.handleWorkflowAction(action)
 	return nil
 }
+
+// subWorkflowOutput is the output of a sub-workflow step or tool call once the child completed; it
+// is an error output unless the child finished.
+func subWorkflowOutput(childWorkflowID workflow.ID, finalState string, childOutput map[string]any) workflow.FunctionOutput {
+	outputStatus := workflow.FunctionSuccess
+	if finalState != internalworkflow.StateFinished.String() {
+		outputStatus = workflow.FunctionError
+	}
+	return workflow.FunctionOutput{
+		Status: outputStatus,
+		Data: map[string]any{
+			"workflowId": childWorkflowID.String(),
+			"status":     finalState,
+			"output":     childOutput,
+		},
+	}
+}
+
+// --- Async tool calls (ADR-0027) ---
+
+// handleMsgInvokeTool starts a function as an async tool call of a running node. The call runs as a
+// child execution outside the graph: intercepted functions are handled here as for graph nodes,
+// everything else is dispatched to the pool, and the result resolves the caller's waiter instead of
+// advancing a thread.
+func (a *WorkflowHandler) handleMsgInvokeTool(msg messaging.Message) error {
+	call, ok := msg.Args.(messaging.InvokeToolMessage)
+	if !ok {
+		return nil
+	}
+	if a.isTerminalState() {
+		a.waiters.Resolve(a.workflow.ID().String(), call.ExecID.String(), workflow.NewFunctionOutput(workflow.FunctionError,
+			map[string]any{"error": fmt.Sprintf("workflow is %s", a.workflow.State())}))
+		return nil
+	}
+
+	// A node replayed after a restart repeats the calls of its last journaled decision under the
+	// same exec ids: completed calls get their recorded result and running sub-workflows are
+	// awaited again instead of being started twice.
+	if result, done := a.workflow.ToolCallResult(call.ExecID); done {
+		a.waiters.Resolve(a.workflow.ID().String(), call.ExecID.String(), result.Output)
+		return nil
+	}
+	if a.toolCalls[call.ExecID] && a.reattachToolSubWorkflow(call) {
+		return nil
+	}
+
+	a.toolCalls[call.ExecID] = true
+	a.workflow.StartToolCall(call.ParentExecID, call.ExecID, call.FunctionID, call.Input)
+	a.persistJournal()
+	if call.Timeout > 0 {
+		a.executionTimer.Start(a, a.PID(), call.ExecID.String(), call.Timeout)
+	}
+
+	threadID := call.ParentExecID.Thread()
+	switch call.FunctionID {
+	case system.SleepFullFunctionID:
+		duration, err := sleepDuration(call.Input)
+		if err != nil {
+			a.failToolCall(call.ExecID, err)
+			return nil
+		}
+		wakeUpMsg := messaging.NewSleepWakeUpMessage(a.workflow.ID(), call.ExecID, threadID)
+		if _, err := a.SendAfter(a.PID(), wakeUpMsg, duration); err != nil {
+			a.failToolCall(call.ExecID, err)
+		}
+	case system.WaitFullFunctionID:
+		a.createAwakeable(a.waitForEventAction(threadID, call.ExecID, call.Input))
+		a.persistJournal()
+	case system.SubWorkflowFullFunctionID:
+		a.startToolSubWorkflow(call)
+	case system.ForEachFullFunctionID:
+		a.failToolCall(call.ExecID, fmt.Errorf("%s cannot run as a tool", call.FunctionID))
+	default:
+		runAction := &workflowactions.RunFunctionAction{
+			ThreadID:       threadID,
+			FunctionID:     call.FunctionID,
+			FunctionExecID: call.ExecID,
+			Args:           call.Input,
+		}
+		execFnMsg := messaging.NewExecuteFunctionMessage(a.workflow.ID(), runAction, a.executionScope(),
+			a.mintExecCallbackToken(call.ExecID), nil, a.tracingProvider.InjectCarrier(a.spanCtx))
+		if err := a.Send(WorkflowFuncPoolName(a.workflow.ID()), execFnMsg); err != nil {
+			a.failToolCall(call.ExecID, err)
+		}
+	}
+	return nil
+}
+
+// startToolSubWorkflow starts the child workflow of a system/subworkflow tool call. An async call
+// completes as soon as the child is triggered; otherwise the child's completion resolves it.
+func (a *WorkflowHandler) startToolSubWorkflow(call messaging.InvokeToolMessage) {
+	schemaID, _ := call.Input["schemaId"].(string)
+	input, _ := call.Input["input"].(map[string]any)
+	async, _ := call.Input["async"].(bool)
+
+	childWorkflowID, err := a.startSubWorkflow(&workflowactions.RunSubWorkflowAction{
+		ParentWorkflowID: a.workflow.ID(),
+		ParentThreadID:   call.ParentExecID.Thread(),
+		ParentExecID:     call.ExecID,
+		SchemaID:         schemaID,
+		Input:            input,
+		Async:            async,
+	})
+	switch {
+	case err != nil:
+		a.Log().Error("tool call %s failed to start sub-workflow %q: %s", call.ExecID, schemaID, err)
+		a.completeToolCall(call.ExecID, subWorkflowStartError(err))
+	case async:
+		a.completeToolCall(call.ExecID, triggeredSubWorkflowOutput(childWorkflowID))
+	default:
+		a.persistJournal()
+	}
+}
+
+// reattachToolSubWorkflow resumes waiting for the child workflow an open tool call started before a
+// restart. A child that completed meanwhile could not report back, so its final state is read from
+// the repository. It reports false when the call started no child, which is then run again.
+func (a *WorkflowHandler) reattachToolSubWorkflow(call messaging.InvokeToolMessage) bool {
+	childID, ok := a.workflow.ToolCallChild(call.ExecID)
+	if !ok {
+		return false
+	}
+	if call.Timeout > 0 {
+		a.executionTimer.Start(a, a.PID(), call.ExecID.String(), call.Timeout)
+	}
+	child, err := a.workflowRepository.Get(childID.String())
+	if err != nil {
+		a.Log().Warning("tool call %s: cannot read sub-workflow %s: %s", call.ExecID, childID, err)
+		return true
+	}
+	if state := child.State(); !state.IsActive() {
+		a.completeToolCall(call.ExecID, subWorkflowOutput(childID, state.String(), child.AggregatedOutputSnapshot()))
+	}
+	return true
+}
+
+// isToolCall reports whether execID is an async tool call rather than a graph node execution.
+func (a *WorkflowHandler) isToolCall(execID workflow.ExecID) bool {
+	_, ok := a.toolCalls[execID]
+	return ok
+}
+
+// completeToolCall journals the result of a running tool call and hands it to the waiting node.
+// Results for a call that already completed (a late timeout, a duplicate) are dropped.
+func (a *WorkflowHandler) completeToolCall(execID workflow.ExecID, output workflow.FunctionOutput) {
+	if !a.toolCalls[execID] {
+		a.Log().Debug("ignoring result of completed tool call %s", execID)
+		return
+	}
+	a.toolCalls[execID] = false
+	a.cancelExecutionTimeout(execID)
+	a.releaseExecution(execID)
+	a.workflow.CompleteToolCall(execID, &workflow.FunctionResult{Output: output})
+	a.persistJournal()
+	if !a.waiters.Resolve(a.workflow.ID().String(), execID.String(), output) {
+		a.Log().Info("tool call %s completed after its caller stopped waiting", execID)
+	}
+}
+
+// failToolCall completes a tool call that could not be started.
+func (a *WorkflowHandler) failToolCall(execID workflow.ExecID, err error) {
+	a.Log().Error("tool call %s failed to start: %s", execID, err)
+	a.completeToolCall(execID, workflow.NewFunctionOutput(workflow.FunctionError, map[string]any{"error": err.Error()}))
+}
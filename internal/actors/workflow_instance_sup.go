@@ -53,26 +53,37 @@ type (
 func (a *WorkflowInstanceSupervisor) Init(args ...any) (act.SupervisorSpec, error) {
 	a.Log().Info("starting process %s with args %s", a.PID(), args)
 
-	if len(args) != 3 {
-		return act.SupervisorSpec{}, fmt.Errorf("workflow instance supervisor init args must be 3 == [workflowID, workflowSchemaID, environment]")
+	const argsErr = "workflow instance supervisor init args must be 5 == [workflowID, workflowSchemaID, environment, schemaVersion, input]"
+	if len(args) != 5 {
+		return act.SupervisorSpec{}, fmt.Errorf(argsErr)
 	}
 	workflowID, ok := args[0].(workflow.ID)
 	if !ok {
-		return act.SupervisorSpec{}, fmt.Errorf("workflow instance supervisor init args must be 3 == [workflowID, workflowSchemaID, environment]; first arg must be a workflow.ID, got %T", args[0])
+		return act.SupervisorSpec{}, fmt.Errorf("%s; first arg must be a workflow.ID, got %T", argsErr, args[0])
 	}
 	schemaID, ok := args[1].(string)
 	if !ok {
-		return act.SupervisorSpec{}, fmt.Errorf("workflow instance supervisor init args must be 3 == [workflowID, workflowSchemaID, environment]; second arg must be a string, got %T", args[1])
+		return act.SupervisorSpec{}, fmt.Errorf("%s; second arg must be a string, got %T", argsErr, args[1])
 	}
 	environment, ok := args[2].(string)
 	if !ok {
-		return act.SupervisorSpec{}, fmt.Errorf("workflow instance supervisor init args must be 3 == [workflowID, workflowSchemaID, environment]; third arg must be a string, got %T", args[2])
+		return act.SupervisorSpec{}, fmt.Errorf("%s; third arg must be a string, got %T", argsErr, args[2])
+	}
+	schemaVersion, ok := args[3].(int)
+	if !ok {
+		return act.SupervisorSpec{}, fmt.Errorf("%s; fourth arg must be an int, got %T", argsErr, args[3])
+	}
+	input, ok := args[4].(map[string]any)
+	if !ok && args[4] != nil {
+		return act.SupervisorSpec{}, fmt.Errorf("%s; fifth arg must be a map[string]any, got %T", argsErr, args[4])
 	}
 
 	handlerInitArgs := WorkflowHandlerInitArgs{
-		schemaID:    schemaID,
-		workflowID:  workflowID,
-		environment: environment,
+		schemaID:      schemaID,
+		workflowID:    workflowID,
+		environment:   environment,
+		schemaVersion: schemaVersion,
+		input:         input,
 	}
 
 	// supervisor specification
@@ -86,7 +97,7 @@ func (a *WorkflowInstanceSupervisor) Init(args ...any) (act.SupervisorSpec, erro
 				Args:    []any{},
 			},
 			{
-				Name:    gen.Atom(actornames.WorkflowHandlerName(workflowID)),
+				Name:    gen.Atom(actornames.WorkflowHandlerName(workflow.NamespaceOf(schemaID), workflowID)),
 				Factory: a.workflowHandler.Factory,
 				Args:    []any{handlerInitArgs},
 			},
@@ -25,6 +25,7 @@ func NewWorkflowSupervisorFactory(
 	workflowInstanceSup *WorkflowInstanceSupervisorFactory,
 	graphService services.GraphService,
 	concurrencyMgr *concurrency.Manager,
+	trafficSplitService services.TrafficSplitService,
 ) *WorkflowSupervisorFactory {
 	return &WorkflowSupervisorFactory{
 		Factory: func() gen.ProcessBehavior {
@@ -34,6 +35,7 @@ func NewWorkflowSupervisorFactory(
 				workflowInstanceSup: workflowInstanceSup,
 				graphService:        graphService,
 				concurrencyManager:  concurrencyMgr,
+				trafficSplitService: trafficSplitService,
 				workflowActors:      make(map[workflow.ID]gen.PID),
 				releaseMap:          make(map[workflow.ID]func()),
 			}
@@ -50,6 +52,7 @@ type WorkflowSupervisor struct {
 	workflowInstanceSup *WorkflowInstanceSupervisorFactory
 	graphService        services.GraphService
 	concurrencyManager  *concurrency.Manager
+	trafficSplitService services.TrafficSplitService
 
 	workflowActors map[workflow.ID]gen.PID
 	releaseMap     map[workflow.ID]func()
@@ -115,7 +118,12 @@ func (a *WorkflowSupervisor) HandleMessage(from gen.PID, message any) error {
 			a.releaseMap[triggerMsg.WorkflowID] = release
 		}
 
-		err = a.spawnWorkflowActor(triggerMsg.SchemaID, triggerMsg.WorkflowID, triggerMsg.Environment)
+		version := triggerMsg.Version
+		if version == 0 {
+			version = a.resolveTrafficSplit(triggerMsg)
+		}
+
+		err = a.spawnWorkflowActor(triggerMsg.SchemaID, triggerMsg.WorkflowID, triggerMsg.Environment, version, triggerMsg.Input)
 		if err != nil {
 			a.Log().Error("failed to spawn workflow actor for schema id %s : %s", triggerMsg.SchemaID, err)
 			// Release concurrency slot on spawn failure
@@ -133,7 +141,7 @@ func (a *WorkflowSupervisor) HandleMessage(from gen.PID, message any) error {
 			a.Log().Error("failed to get cancel workflow message: %s", err)
 			return nil
 		}
-		handlerName := actornames.WorkflowHandlerName(cancelMsg.WorkflowID)
+		handlerName := actornames.WorkflowHandlerName(cancelMsg.Namespace, cancelMsg.WorkflowID)
 		if sendErr := a.Send(gen.Atom(handlerName), message); sendErr != nil {
 			a.Log().Warning("cancel requested for unknown/finished workflow %s: %s", cancelMsg.WorkflowID, sendErr)
 		}
@@ -145,7 +153,7 @@ func (a *WorkflowSupervisor) HandleMessage(from gen.PID, message any) error {
 		}
 		// The handler may have terminated (workflow reached error state).
 		// Try to send; if the handler doesn't exist, respawn it from the persisted state.
-		handlerName := actornames.WorkflowHandlerName(retryMsg.WorkflowID)
+		handlerName := actornames.WorkflowHandlerName(retryMsg.Namespace, retryMsg.WorkflowID)
 		if sendErr := a.Send(gen.Atom(handlerName), message); sendErr != nil {
 			a.Log().Info("respawning workflow actor for retry of %s", retryMsg.WorkflowID)
 			wf, getErr := a.workflowRepository.Get(retryMsg.WorkflowID.String())
@@ -153,7 +161,7 @@ func (a *WorkflowSupervisor) HandleMessage(from gen.PID, message any) error {
 				a.Log().Error("failed to get workflow %s for retry: %s", retryMsg.WorkflowID, getErr)
 				return nil
 			}
-			if spawnErr := a.spawnWorkflowActor(wf.Graph().ID(), retryMsg.WorkflowID, wf.Environment()); spawnErr != nil {
+			if spawnErr := a.spawnWorkflowActor(wf.Graph().ID(), retryMsg.WorkflowID, wf.Environment(), wf.SchemaVersion(), nil); spawnErr != nil {
 				a.Log().Error("failed to respawn workflow %s for retry: %s", retryMsg.WorkflowID, spawnErr)
 				return nil
 			}
@@ -230,21 +238,35 @@ func (a *WorkflowSupervisor) recoverWorkflows() {
 			continue
 		}
 		schemaID := wf.Schema().ID
-		if spawnErr := a.spawnWorkflowActor(schemaID, wf.ID(), wf.Environment()); spawnErr != nil {
+		if spawnErr := a.spawnWorkflowActor(schemaID, wf.ID(), wf.Environment(), wf.SchemaVersion(), nil); spawnErr != nil {
 			a.Log().Error("failed to recover workflow %s: %s", id, spawnErr)
 		}
 	}
 }
 
-func (a *WorkflowSupervisor) spawnWorkflowActor(schemaID string, workflowID workflow.ID, environment string) error {
-	err := a.StartChild(actornames.WorkflowInstanceSupervisor, workflowID, schemaID, environment)
+// resolveTrafficSplit routes an unpinned trigger through the schema's canary traffic split, so
+// API, webhook, cron, event and sub-workflow triggers all honour it. Zero runs the active version.
+func (a *WorkflowSupervisor) resolveTrafficSplit(triggerMsg messaging.TriggerWorkflowMessage) int {
+	version, err := a.trafficSplitService.ResolveVersion(triggerMsg.SchemaID, triggerMsg.Input)
+	if err != nil {
+		a.Log().Warning("failed to resolve traffic split for schema %s, running the active version: %s", triggerMsg.SchemaID, err)
+		return 0
+	}
+	if version > 0 {
+		a.Log().Debug("traffic split routed workflow %s to version %d of schema %s", triggerMsg.WorkflowID, version, triggerMsg.SchemaID)
+	}
+	return version
+}
+
+func (a *WorkflowSupervisor) spawnWorkflowActor(schemaID string, workflowID workflow.ID, environment string, version int, input map[string]any) error {
+	err := a.StartChild(actornames.WorkflowInstanceSupervisor, workflowID, schemaID, environment, version, input)
 	if err != nil {
 		a.Log().Error("failed to spawn child for schema id %s : %s", schemaID, err)
 		return err
 	}
 
 	// Find the PID of the newly started child to track it for cleanup
-	expectedName := gen.Atom(actornames.WorkflowInstanceSupervisorName(workflowID))
+	expectedName := gen.Atom(actornames.WorkflowInstanceSupervisorName(workflow.NamespaceOf(schemaID), workflowID))
 	for _, child := range a.Children() {
 		if child.Name == expectedName {
 			a.workflowActors[workflowID] = child.PID
@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/open-source-cloud/fuse/internal/app/config"
+	"github.com/open-source-cloud/fuse/internal/app/di"
+	"github.com/open-source-cloud/fuse/internal/logging"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/internal/repositories/postgres"
+	"github.com/open-source-cloud/fuse/internal/services"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+)
+
+// apiKeyTTLFlag sets the lifetime of a key issued by `api-keys create` (0 = never expires).
+var apiKeyTTLFlag time.Duration
+
+func newAPIKeysCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "api-keys",
+		Short: "Manage API keys authenticating REST API callers",
+		Long: "Create, list, and revoke API keys. Keys are stored hashed in the database; a new key " +
+			"is printed once and cannot be retrieved again. Requires DB_DRIVER=postgres, since keys " +
+			"created against the memory driver vanish when the command exits.",
+	}
+	cmd.AddCommand(newAPIKeysCreateCommand(), newAPIKeysListCommand(), newAPIKeysRevokeCommand())
+	return cmd
+}
+
+func newAPIKeysCreateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Issue a new API key and print it",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runAPIKeysApp(func(_ context.Context, svc services.APIKeyService) error {
+				key, plaintext, err := svc.Create(args[0], apiKeyTTLFlag)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("id:  %s\nkey: %s\n", key.ID, plaintext)
+				if key.ExpiresAt != nil {
+					fmt.Printf("expires: %s\n", key.ExpiresAt.Format(time.RFC3339))
+				}
+				fmt.Println("Store the key now; it cannot be shown again.")
+				return nil
+			})
+		},
+	}
+	cmd.Flags().DurationVar(&apiKeyTTLFlag, "ttl", 0, "Key lifetime, e.g. 720h (0 = never expires)")
+	return cmd
+}
+
+func newAPIKeysListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List API keys (ids, names and status; never the keys)",
+		Args:  cobra.NoArgs,
+		RunE: func(*cobra.Command, []string) error {
+			return runAPIKeysApp(func(_ context.Context, svc services.APIKeyService) error {
+				keys, err := svc.FindAll()
+				if err != nil {
+					return err
+				}
+				if len(keys) == 0 {
+					fmt.Println("(no api keys)")
+					return nil
+				}
+				now := time.Now()
+				for _, k := range keys {
+					status := "active"
+					switch {
+					case k.RevokedAt != nil:
+						status = "revoked"
+					case !k.Active(now):
+						status = "expired"
+					}
+					fmt.Printf("  - %s %s (%s, created %s)\n", k.ID, k.Name, status, k.CreatedAt.Format(time.RFC3339))
+				}
+				return nil
+			})
+		},
+	}
+}
+
+func newAPIKeysRevokeCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "revoke <id>",
+		Short: "Revoke an API key",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runAPIKeysApp(func(_ context.Context, svc services.APIKeyService) error {
+				return svc.Revoke(args[0])
+			})
+		},
+	}
+}
+
+// apiKeysAppParams are the dependencies the api-keys CLI action needs.
+type apiKeysAppParams struct {
+	fx.In
+	LC   fx.Lifecycle
+	Cfg  *config.Config
+	Pool *pgxpool.Pool `optional:"true"`
+	SD   fx.Shutdowner
+}
+
+// runAPIKeysApp boots the minimal DI graph (config + database), runs the admin action against
+// an APIKeyService, and exits.
+func runAPIKeysApp(action func(context.Context, services.APIKeyService) error) error {
+	var actionErr error
+	app := fx.New(
+		di.CommonModule,
+		di.DatabaseModule,
+		fx.Invoke(func(p apiKeysAppParams) {
+			p.LC.Append(fx.Hook{
+				OnStart: func(ctx context.Context) error {
+					var repo repositories.APIKeyRepository
+					if p.Cfg.Database.Driver == config.DBDriverPostgres && p.Pool != nil {
+						repo = postgres.NewAPIKeyRepository(p.Pool)
+					} else {
+						log.Warn().Msg("DB_DRIVER is not postgres: api keys are not persisted")
+						repo = repositories.NewMemoryAPIKeyRepository()
+					}
+					actionErr = action(ctx, services.NewAPIKeyService(repo))
+					go func() { _ = p.SD.Shutdown() }()
+					return nil
+				},
+			})
+		}),
+		fx.WithLogger(logging.NewFxLogger()),
+	)
+	app.Run()
+	if err := app.Err(); err != nil {
+		return err
+	}
+	return actionErr
+}
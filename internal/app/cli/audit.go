@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"context"
+	"os/user"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/open-source-cloud/fuse/internal/app/config"
+	"github.com/open-source-cloud/fuse/internal/audit"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/internal/repositories/postgres"
+)
+
+// withCLIActor attributes the audited changes made through ctx to the operating-system user
+// running the CLI ("cli:<user>").
+func withCLIActor(ctx context.Context) context.Context {
+	name := "unknown"
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		name = u.Username
+	}
+	return audit.WithActor(ctx, "cli:"+name)
+}
+
+// auditRepoFor selects the audit log repository matching the database driver.
+func auditRepoFor(cfg *config.Config, pool *pgxpool.Pool) repositories.AuditRepository {
+	if cfg.Database.Driver == config.DBDriverPostgres && pool != nil {
+		return postgres.NewAuditRepository(pool)
+	}
+	return repositories.NewMemoryAuditRepository()
+}
@@ -21,6 +21,9 @@ import (
 // credentialsEnvFlag scopes credential value operations to an environment (defaults to FUSE_ENVIRONMENT).
 var credentialsEnvFlag string
 
+// credentialsNamespaceFlag scopes credentials to a namespace (defaults to the default namespace).
+var credentialsNamespaceFlag string
+
 // credentialsTypeFlag sets the credential type on `credentials set` (defaults to "custom").
 var credentialsTypeFlag string
 
@@ -29,10 +32,11 @@ func newCredentialsCommand() *cobra.Command {
 		Use:   "credentials",
 		Short: "Manage credentials (typed groups of secret values) in the configured store",
 		Long: "Set, list, and delete credentials. A credential's field values are stored in the " +
-			"SECRETS_DRIVER backend at cred/<id>/<field>, per environment (ADR-0031). Requires " +
+			"SECRETS_DRIVER backend at cred/<id>/<field>, per namespace and environment (ADR-0031). Requires " +
 			"driver=memory or driver=postgres for writes.",
 	}
 	cmd.PersistentFlags().StringVar(&credentialsEnvFlag, "env", "", "Environment scope (defaults to FUSE_ENVIRONMENT)")
+	cmd.PersistentFlags().StringVar(&credentialsNamespaceFlag, "namespace", workflow.DefaultNamespaceName, "Namespace scope")
 	cmd.AddCommand(newCredentialsSetCommand(), newCredentialsListCommand(), newCredentialsDeleteCommand())
 	return cmd
 }
@@ -44,12 +48,12 @@ func newCredentialsSetCommand() *cobra.Command {
 		Args:  cobra.ExactArgs(3),
 		RunE: func(_ *cobra.Command, args []string) error {
 			id, field, value := args[0], args[1], args[2]
-			return runCredentialsApp(func(_ context.Context, svc services.CredentialService, env string) error {
+			return runCredentialsApp(func(ctx context.Context, svc services.CredentialService, scope secrets.Scope) error {
 				cred := workflow.NewCredential(id, credentialsTypeFlag, "", nil)
-				if _, err := svc.Save(cred, map[string]string{field: value}, env); err != nil {
+				if _, err := svc.Save(ctx, cred, map[string]string{field: value}, scope); err != nil {
 					return err
 				}
-				log.Info().Str("environment", env).Str("credential", id).Str("field", field).Msg("credential field set")
+				log.Info().Str("namespace", scope.Namespace).Str("environment", scope.Environment).Str("credential", id).Str("field", field).Msg("credential field set")
 				return nil
 			})
 		},
@@ -64,8 +68,8 @@ func newCredentialsListCommand() *cobra.Command {
 		Short: "List credentials (ids, type, and field names; never values)",
 		Args:  cobra.NoArgs,
 		RunE: func(*cobra.Command, []string) error {
-			return runCredentialsApp(func(_ context.Context, svc services.CredentialService, _ string) error {
-				creds, err := svc.FindAll()
+			return runCredentialsApp(func(_ context.Context, svc services.CredentialService, scope secrets.Scope) error {
+				creds, err := svc.FindAll(scope.Namespace)
 				if err != nil {
 					return err
 				}
@@ -85,15 +89,15 @@ func newCredentialsListCommand() *cobra.Command {
 func newCredentialsDeleteCommand() *cobra.Command {
 	return &cobra.Command{
 		Use:   "delete <id>",
-		Short: "Delete a credential and its field values in the environment",
+		Short: "Delete a credential and its field values in the namespace and environment",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(_ *cobra.Command, args []string) error {
 			id := args[0]
-			return runCredentialsApp(func(_ context.Context, svc services.CredentialService, env string) error {
-				if err := svc.Delete(id, env); err != nil {
+			return runCredentialsApp(func(ctx context.Context, svc services.CredentialService, scope secrets.Scope) error {
+				if err := svc.Delete(ctx, id, scope); err != nil {
 					return err
 				}
-				log.Info().Str("environment", env).Str("credential", id).Msg("credential deleted")
+				log.Info().Str("namespace", scope.Namespace).Str("environment", scope.Environment).Str("credential", id).Msg("credential deleted")
 				return nil
 			})
 		},
@@ -113,7 +117,7 @@ type credentialsAppParams struct {
 
 // runCredentialsApp boots the minimal DI graph (config + database + secrets), builds a
 // CredentialService over the selected secret backend, runs the admin action, and exits.
-func runCredentialsApp(action func(context.Context, services.CredentialService, string) error) error {
+func runCredentialsApp(action func(context.Context, services.CredentialService, secrets.Scope) error) error {
 	var actionErr error
 	app := fx.New(
 		di.CommonModule,
@@ -123,12 +127,14 @@ func runCredentialsApp(action func(context.Context, services.CredentialService,
 			p.LC.Append(fx.Hook{
 				OnStart: func(ctx context.Context) error {
 					repo := credentialRepoFor(p.Cfg, p.Pool)
-					svc := services.NewCredentialService(repo, p.Store)
+					svc := services.NewCredentialService(repo, p.Store, services.NewAuditService(auditRepoFor(p.Cfg, p.Pool)))
 					env := credentialsEnvFlag
 					if env == "" {
 						env = p.Cfg.Environment
 					}
-					actionErr = action(ctx, svc, env)
+					if actionErr = workflow.ValidateNamespaceName(credentialsNamespaceFlag); actionErr == nil {
+						actionErr = action(withCLIActor(ctx), svc, secrets.Scope{Namespace: credentialsNamespaceFlag, Environment: env})
+					}
 					go func() { _ = p.SD.Shutdown() }()
 					return nil
 				},
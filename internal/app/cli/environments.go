@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/open-source-cloud/fuse/internal/app/config"
+	"github.com/open-source-cloud/fuse/internal/app/di"
+	"github.com/open-source-cloud/fuse/internal/logging"
+	"github.com/open-source-cloud/fuse/internal/services"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+)
+
+// promoteNamespaceFlag scopes a promotion to a namespace (defaults to the default namespace).
+var promoteNamespaceFlag string
+
+// promoteApplyFlag copies variables instead of only printing the plan.
+var promoteApplyFlag bool
+
+// promoteOverwriteFlag also replaces target variables whose value differs.
+var promoteOverwriteFlag bool
+
+func newEnvironmentsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "environments",
+		Short: "Manage environments",
+	}
+	cmd.AddCommand(newEnvironmentsPromoteCommand())
+	return cmd
+}
+
+func newEnvironmentsPromoteCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "promote <from> <to>",
+		Short: "Plan (or apply) promoting one environment's configuration to another",
+		Long: "Compares two environments of a namespace: variables that differ, credential fields " +
+			"without a value in <to>, and the secrets, credentials and variables each active schema " +
+			"is missing there. With --apply, variables missing from <to> are copied (and differing " +
+			"ones too with --overwrite); secret values are never copied. Exits non-zero while a schema " +
+			"is not deployable to <to>. Requires DB_DRIVER=postgres.",
+		Args: cobra.ExactArgs(2),
+		RunE: func(_ *cobra.Command, args []string) error { return runPromoteApp(args[0], args[1]) },
+	}
+	cmd.Flags().StringVar(&promoteNamespaceFlag, "namespace", workflow.DefaultNamespaceName, "Namespace scope")
+	cmd.Flags().BoolVar(&promoteApplyFlag, "apply", false, "Copy variables missing from the target")
+	cmd.Flags().BoolVar(&promoteOverwriteFlag, "overwrite", false, "With --apply, also replace variables whose value differs")
+	return cmd
+}
+
+// runPromoteApp boots the minimal DI graph (config + database + object store + secrets +
+// repositories), plans or applies one promotion, prints it, and exits.
+func runPromoteApp(from, to string) error {
+	if cfg := config.Instance(); cfg.Database.Driver != config.DBDriverPostgres {
+		return fmt.Errorf("environments promote requires DB_DRIVER=postgres (the memory driver keeps nothing across processes)")
+	}
+	if err := workflow.ValidateNamespaceName(promoteNamespaceFlag); err != nil {
+		return err
+	}
+
+	var plan *services.PromotionPlan
+	var promoteErr error
+	app := fx.New(
+		di.CommonModule,
+		di.DatabaseModule,
+		di.ObjectStoreModule,
+		di.SecretsModule,
+		di.RepoModule,
+		fx.Provide(services.NewAuditService, services.NewEnvironmentService, services.NewEnvironmentVarService, services.NewPromotionService),
+		fx.Invoke(func(lc fx.Lifecycle, svc services.PromotionService, sd fx.Shutdowner) {
+			lc.Append(fx.Hook{
+				OnStart: func(ctx context.Context) error {
+					if promoteApplyFlag {
+						plan, promoteErr = svc.Apply(ctx, promoteNamespaceFlag, from, to, promoteOverwriteFlag)
+					} else {
+						plan, promoteErr = svc.Plan(ctx, promoteNamespaceFlag, from, to)
+					}
+					go func() { _ = sd.Shutdown() }()
+					return nil
+				},
+			})
+		}),
+		fx.WithLogger(logging.NewFxLogger()),
+	)
+	app.Run()
+	if err := app.Err(); err != nil {
+		return err
+	}
+	if promoteErr != nil {
+		return promoteErr
+	}
+	printPromotionPlan(plan)
+	if !plan.Deployable {
+		return errors.New("some schemas are not deployable to " + to)
+	}
+	return nil
+}
+
+func printPromotionPlan(plan *services.PromotionPlan) {
+	fmt.Printf("promote %s -> %s (namespace %s)\n", plan.From, plan.To, plan.Namespace)
+	if len(plan.Applied) > 0 {
+		fmt.Printf("  copied variables: %s\n", strings.Join(plan.Applied, ", "))
+	}
+	for _, v := range plan.Variables {
+		switch v.Status {
+		case services.VarDiffMissing:
+			fmt.Printf("  var %s: missing (%q)\n", v.Name, v.From)
+		case services.VarDiffChanged:
+			fmt.Printf("  var %s: %q -> %q\n", v.Name, v.From, v.To)
+		default:
+			fmt.Printf("  var %s: only in %s\n", v.Name, plan.To)
+		}
+	}
+	for _, c := range plan.Credentials {
+		fmt.Printf("  credential %s (type=%s): no value for %v\n", c.ID, c.Type, c.MissingFields)
+	}
+	for _, s := range plan.Schemas {
+		if s.Deployable {
+			fmt.Printf("  schema %s: deployable\n", s.SchemaID)
+			continue
+		}
+		fmt.Printf("  schema %s: missing secrets=%v credentials=%v vars=%v\n", s.SchemaID, s.MissingSecrets, s.MissingCredentials, s.MissingVars)
+	}
+}
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path"
@@ -55,7 +56,7 @@ func mermaidHandler(graphService services.GraphService) {
 			log.Error().Err(err).Msg("Failed to parse workflow JSON spec file")
 			return
 		}
-		graph, err = graphService.Upsert(schema.ID, schema)
+		graph, err = graphService.Upsert(withCLIActor(context.Background()), schema.ID, schema)
 		if err != nil {
 			log.Error().Err(err).Msg("Failed to upsert workflow graph")
 			return
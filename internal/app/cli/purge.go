@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/open-source-cloud/fuse/internal/app/config"
+	"github.com/open-source-cloud/fuse/internal/app/di"
+	"github.com/open-source-cloud/fuse/internal/logging"
+	"github.com/open-source-cloud/fuse/internal/services"
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+)
+
+// purgeDryRun reports purgeable executions without deleting anything.
+var purgeDryRun bool
+
+func newPurgeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "purge",
+		Short: "Delete terminal executions that exceed their retention policy",
+		Long: "Applies the global RETENTION_* limits and per-schema \"retention\" overrides once: deletes " +
+			"finished, errored and cancelled executions (with their journal, traces, awakeables and " +
+			"snapshots) in batches of RETENTION_BATCH_SIZE. Sub-workflows are purged with their root " +
+			"execution. Requires DB_DRIVER=postgres; does not start the HTTP server or actor runtime.",
+		Args: cobra.NoArgs,
+		RunE: func(*cobra.Command, []string) error { return runPurgeApp() },
+	}
+	cmd.Flags().BoolVar(&purgeDryRun, "dry-run", false, "Only report what would be deleted")
+	return cmd
+}
+
+// runPurgeApp boots the minimal DI graph (config + database + object store + repositories),
+// runs a single purge, prints the report, and exits.
+func runPurgeApp() error {
+	if cfg := config.Instance(); cfg.Database.Driver != config.DBDriverPostgres {
+		return fmt.Errorf("purge requires DB_DRIVER=postgres (the memory driver keeps no executions across processes)")
+	}
+
+	var report *services.PurgeReport
+	var purgeErr error
+	app := fx.New(
+		di.CommonModule,
+		di.DatabaseModule,
+		di.ObjectStoreModule,
+		di.RepoModule,
+		fx.Provide(services.NewRetentionService),
+		fx.Invoke(func(lc fx.Lifecycle, svc services.RetentionService, sd fx.Shutdowner) {
+			lc.Append(fx.Hook{
+				OnStart: func(ctx context.Context) error {
+					report, purgeErr = svc.Purge(ctx, purgeDryRun)
+					go func() { _ = sd.Shutdown() }()
+					return nil
+				},
+			})
+		}),
+		fx.WithLogger(logging.NewFxLogger()),
+	)
+	app.Run()
+	if err := app.Err(); err != nil {
+		return err
+	}
+	if report != nil {
+		printPurgeReport(report)
+	}
+	return purgeErr
+}
+
+func printPurgeReport(report *services.PurgeReport) {
+	verb := "purged"
+	if report.DryRun {
+		verb = "would purge"
+	}
+	if len(report.Executions) == 0 {
+		fmt.Printf("nothing to purge (skipped %d with running sub-workflows)\n", report.Skipped)
+		return
+	}
+
+	schemaIDs := make([]string, 0, len(report.Executions))
+	for id := range report.Executions {
+		schemaIDs = append(schemaIDs, id)
+	}
+	sort.Strings(schemaIDs)
+	for _, id := range schemaIDs {
+		fmt.Printf("  - %s: %s %d execution(s)\n", id, verb, report.Executions[id])
+	}
+
+	resources := make([]string, 0, len(report.Rows))
+	for r := range report.Rows {
+		resources = append(resources, r)
+	}
+	sort.Strings(resources)
+	for _, r := range resources {
+		fmt.Printf("  deleted %d %s\n", report.Rows[r], r)
+	}
+	if report.Skipped > 0 {
+		fmt.Printf("  skipped %d execution(s) with running sub-workflows\n", report.Skipped)
+	}
+}
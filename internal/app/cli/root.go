@@ -49,9 +49,12 @@ func newRoot() *cobra.Command {
 
 	rootCmd.AddCommand(newServerCommand())
 	rootCmd.AddCommand(newMigrateCommand())
+	rootCmd.AddCommand(newPurgeCommand())
 	rootCmd.AddCommand(newSeedCommand())
 	rootCmd.AddCommand(newSecretsCommand())
 	rootCmd.AddCommand(newCredentialsCommand())
+	rootCmd.AddCommand(newEnvironmentsCommand())
+	rootCmd.AddCommand(newAPIKeysCommand())
 	rootCmd.AddCommand(newWorkflowCommand())
 	rootCmd.AddCommand(newMermaidCommand())
 	rootCmd.AddCommand(newHealthCommand())
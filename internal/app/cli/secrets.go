@@ -6,28 +6,39 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/open-source-cloud/fuse/internal/app/config"
 	"github.com/open-source-cloud/fuse/internal/app/di"
+	"github.com/open-source-cloud/fuse/internal/audit"
 	"github.com/open-source-cloud/fuse/internal/logging"
+	"github.com/open-source-cloud/fuse/internal/services"
 	"github.com/open-source-cloud/fuse/pkg/secrets"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 	"go.uber.org/fx"
 )
 
-// secretsEnvFlag scopes secret operations to an environment (defaults to FUSE_ENVIRONMENT).
-var secretsEnvFlag string
+var (
+	// secretsEnvFlag scopes secret operations to an environment (defaults to FUSE_ENVIRONMENT).
+	secretsEnvFlag string
+	// secretsNamespaceFlag scopes secret operations to a namespace (defaults to the default namespace).
+	secretsNamespaceFlag string
+	// secretsRotateBatchSize is the number of secrets re-encrypted per transaction by rotate-key.
+	secretsRotateBatchSize int
+)
 
 func newSecretsCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "secrets",
 		Short: "Manage secrets in the configured store",
 		Long: "Set, list, and delete secrets in the SECRETS_DRIVER backend. With driver=postgres " +
-			"values are AES-256-GCM encrypted at rest. The memory driver is per-process (use " +
+			"values are envelope-encrypted (AES-256-GCM) at rest. The memory driver is per-process (use " +
 			"FUSE_SECRET_<NAME> env vars to seed the server instead).",
 	}
 	cmd.PersistentFlags().StringVar(&secretsEnvFlag, "env", "", "Environment scope (defaults to FUSE_ENVIRONMENT)")
-	cmd.AddCommand(newSecretsSetCommand(), newSecretsListCommand(), newSecretsDeleteCommand())
+	cmd.PersistentFlags().StringVar(&secretsNamespaceFlag, "namespace", workflow.DefaultNamespaceName, "Namespace scope")
+	cmd.AddCommand(newSecretsSetCommand(), newSecretsListCommand(), newSecretsDeleteCommand(), newSecretsRotateKeyCommand())
 	return cmd
 }
 
@@ -38,11 +49,12 @@ func newSecretsSetCommand() *cobra.Command {
 		Args:  cobra.ExactArgs(2),
 		RunE: func(_ *cobra.Command, args []string) error {
 			name, value := args[0], args[1]
-			return runSecretsApp(func(ctx context.Context, store secrets.ManagedSecretStore, env string) error {
-				if err := store.Set(ctx, secrets.Scope{Environment: env}, name, value); err != nil {
+			return runSecretsApp(func(ctx context.Context, store secrets.ManagedSecretStore, scope secrets.Scope, auditService services.AuditService) error {
+				if err := store.Set(ctx, scope, name, value); err != nil {
 					return err
 				}
-				log.Info().Str("environment", env).Str("name", name).Msg("secret set")
+				auditService.Record(ctx, secretAuditEntry(audit.ActionSecretSet, name, scope))
+				log.Info().Str("namespace", scope.Namespace).Str("environment", scope.Environment).Str("name", name).Msg("secret set")
 				return nil
 			})
 		},
@@ -55,8 +67,8 @@ func newSecretsListCommand() *cobra.Command {
 		Short: "List secret names in an environment",
 		Args:  cobra.NoArgs,
 		RunE: func(*cobra.Command, []string) error {
-			return runSecretsApp(func(ctx context.Context, store secrets.ManagedSecretStore, env string) error {
-				names, err := store.List(ctx, env)
+			return runSecretsApp(func(ctx context.Context, store secrets.ManagedSecretStore, scope secrets.Scope, _ services.AuditService) error {
+				names, err := store.List(ctx, scope)
 				if err != nil {
 					return err
 				}
@@ -68,7 +80,7 @@ func newSecretsListCommand() *cobra.Command {
 						visible = append(visible, n)
 					}
 				}
-				fmt.Printf("secrets in environment %q:\n", env)
+				fmt.Printf("secrets in namespace %q, environment %q:\n", scope.Namespace, scope.Environment)
 				if len(visible) == 0 {
 					fmt.Println("  (none)")
 				}
@@ -88,40 +100,103 @@ func newSecretsDeleteCommand() *cobra.Command {
 		Args:  cobra.ExactArgs(1),
 		RunE: func(_ *cobra.Command, args []string) error {
 			name := args[0]
-			return runSecretsApp(func(ctx context.Context, store secrets.ManagedSecretStore, env string) error {
-				if err := store.Delete(ctx, secrets.Scope{Environment: env}, name); err != nil {
+			return runSecretsApp(func(ctx context.Context, store secrets.ManagedSecretStore, scope secrets.Scope, auditService services.AuditService) error {
+				if err := store.Delete(ctx, scope, name); err != nil {
 					return err
 				}
-				log.Info().Str("environment", env).Str("name", name).Msg("secret deleted")
+				auditService.Record(ctx, secretAuditEntry(audit.ActionSecretDelete, name, scope))
+				log.Info().Str("namespace", scope.Namespace).Str("environment", scope.Environment).Str("name", name).Msg("secret deleted")
 				return nil
 			})
 		},
 	}
 }
 
+func newSecretsRotateKeyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rotate-key",
+		Short: "Re-encrypt every secret under the active keyring key",
+		Long: "Moves every secret in every namespace and environment to SECRETS_ACTIVE_KEY_ID, re-wrapping " +
+			"its data key in batches while the server keeps running. Keep the previous keys in " +
+			"SECRETS_KEYRING (or SECRETS_ENCRYPTION_KEY) until this completes, then remove them. " +
+			"Requires SECRETS_DRIVER=postgres.",
+		Args: cobra.NoArgs,
+		RunE: func(*cobra.Command, []string) error {
+			return runSecretsStoreApp(func(ctx context.Context, _ *config.Config, store secrets.SecretStore, _ services.AuditService) error {
+				rotator, ok := store.(secrets.KeyRotator)
+				if !ok {
+					return errors.New("the configured SECRETS_DRIVER does not encrypt with a keyring; rotate-key requires driver=postgres")
+				}
+				rotated, err := rotator.RotateKeys(ctx, secretsRotateBatchSize)
+				if err != nil {
+					return fmt.Errorf("rotated %d secrets before failing: %w", rotated, err)
+				}
+				log.Info().Int("rotated", rotated).Msg("secret keys rotated")
+				return nil
+			})
+		},
+	}
+	cmd.Flags().IntVar(&secretsRotateBatchSize, "batch-size", 100, "Secrets re-encrypted per transaction")
+	return cmd
+}
+
 // runSecretsApp boots the minimal DI graph (config + database + secrets), runs the
 // admin action against a managed store, and exits. It requires a ManagedSecretStore
 // (the memory and postgres backends qualify; a read-only backend does not).
-func runSecretsApp(action func(context.Context, secrets.ManagedSecretStore, string) error) error {
+func runSecretsApp(action func(context.Context, secrets.ManagedSecretStore, secrets.Scope, services.AuditService) error) error {
+	return runSecretsStoreApp(func(ctx context.Context, cfg *config.Config, store secrets.SecretStore, auditService services.AuditService) error {
+		managed, ok := store.(secrets.ManagedSecretStore)
+		if !ok {
+			return errors.New("the configured SECRETS_DRIVER is read-only; set/list/delete require driver=memory or driver=postgres")
+		}
+		env := secretsEnvFlag
+		if env == "" {
+			env = cfg.Environment
+		}
+		if err := workflow.ValidateNamespaceName(secretsNamespaceFlag); err != nil {
+			return err
+		}
+		return action(ctx, managed, secrets.Scope{Namespace: secretsNamespaceFlag, Environment: env}, auditService)
+	})
+}
+
+// secretAuditEntry is the audit entry of a secret write or delete: the secret is recorded by name
+// only, never by value or value hash.
+func secretAuditEntry(action audit.Action, name string, scope secrets.Scope) audit.Entry {
+	return audit.Entry{
+		Action:       action,
+		ResourceType: audit.ResourceSecret,
+		Resource:     name,
+		Namespace:    scope.NamespaceOrDefault(),
+		Environment:  scope.Environment,
+	}
+}
+
+// secretsAppParams are the dependencies the secrets CLI actions need. Pool is optional so the
+// commands work under the memory driver (no database).
+type secretsAppParams struct {
+	fx.In
+	LC    fx.Lifecycle
+	Cfg   *config.Config
+	Store secrets.SecretStore
+	Pool  *pgxpool.Pool `optional:"true"`
+	SD    fx.Shutdowner
+}
+
+// runSecretsStoreApp boots the minimal DI graph (config + database + secrets), runs the action
+// against the configured store, and exits.
+func runSecretsStoreApp(action func(context.Context, *config.Config, secrets.SecretStore, services.AuditService) error) error {
 	var actionErr error
 	app := fx.New(
 		di.CommonModule,
 		di.DatabaseModule,
 		di.SecretsModule,
-		fx.Invoke(func(lc fx.Lifecycle, cfg *config.Config, store secrets.SecretStore, sd fx.Shutdowner) {
-			lc.Append(fx.Hook{
+		fx.Invoke(func(p secretsAppParams) {
+			p.LC.Append(fx.Hook{
 				OnStart: func(ctx context.Context) error {
-					managed, ok := store.(secrets.ManagedSecretStore)
-					if !ok {
-						actionErr = errors.New("the configured SECRETS_DRIVER is read-only; set/list/delete require driver=memory or driver=postgres")
-					} else {
-						env := secretsEnvFlag
-						if env == "" {
-							env = cfg.Environment
-						}
-						actionErr = action(ctx, managed, env)
-					}
-					go func() { _ = sd.Shutdown() }()
+					auditService := services.NewAuditService(auditRepoFor(p.Cfg, p.Pool))
+					actionErr = action(withCLIActor(ctx), p.Cfg, p.Store, auditService)
+					go func() { _ = p.SD.Shutdown() }()
 					return nil
 				},
 			})
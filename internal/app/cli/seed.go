@@ -23,6 +23,8 @@ type noopSchemaUpsertPublisher struct{}
 
 func (noopSchemaUpsertPublisher) PublishLocalUpsert(string, *workflow.GraphSchema) {}
 
+func (noopSchemaUpsertPublisher) PublishLocalLifecycle(string, workflow.SchemaLifecycle, bool) {}
+
 func (noopSchemaUpsertPublisher) BindNode(gen.Node) {}
 
 var (
@@ -79,7 +81,7 @@ func runSeedExamplesApp() error {
 			}
 			lc.Append(fx.Hook{
 				OnStart: func(ctx context.Context) error {
-					if err := seedExampleWorkflows(ctx, gs, absDir, seedExamplesCI, seedExamplesContinueOnErr); err != nil {
+					if err := seedExampleWorkflows(withCLIActor(ctx), gs, absDir, seedExamplesCI, seedExamplesContinueOnErr); err != nil {
 						return err
 					}
 					go func() { _ = sd.Shutdown() }()
@@ -109,6 +111,7 @@ func seedExamplesModules() fx.Option {
 			func() services.SchemaUpsertPublisher {
 				return noopSchemaUpsertPublisher{}
 			},
+			services.NewAuditService,
 			services.NewGraphService,
 			services.NewPackageService,
 		),
@@ -163,7 +166,7 @@ func seedExampleWorkflows(ctx context.Context, gs services.GraphService, absDir
 			}
 			continue
 		}
-		graph, err := gs.Upsert(schema.ID, schema)
+		graph, err := gs.Upsert(ctx, schema.ID, schema)
 		if err != nil {
 			log.Error().Err(err).Str("file", path).Str("schemaID", schema.ID).Msg("failed to upsert graph schema")
 			failed = true
@@ -110,7 +110,7 @@ func runWorkflowOnce(cfg *config.Config, gs services.GraphService, spec []byte)
 	if err != nil {
 		return fmt.Errorf("parse workflow spec: %w", err)
 	}
-	graph, err := gs.Upsert(schema.ID, schema)
+	graph, err := gs.Upsert(withCLIActor(context.Background()), schema.ID, schema)
 	if err != nil {
 		return fmt.Errorf("upsert workflow graph: %w", err)
 	}
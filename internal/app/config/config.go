@@ -35,18 +35,85 @@ type (
 		Otel        OtelConfig
 		LLM         LLMConfig
 		Secrets     SecretsConfig
+		Retention   RetentionConfig
+		Auth        AuthConfig
+		Callback    CallbackConfig
+	}
+
+	// CallbackConfig configures the signed, single-use tokens that authorize async function
+	// results and awakeable resolutions.
+	CallbackConfig struct {
+		// SigningKey is a base64-encoded HMAC key of at least 32 bytes. It must be shared by every
+		// node of a cluster; when empty each process generates its own key, so tokens only verify
+		// on the node that minted them and do not survive restarts.
+		SigningKey string `env:"CALLBACK_SIGNING_KEY"`
+		// TokenTTL is the lifetime of a token whose node or awakeable has no timeout.
+		TokenTTL time.Duration `env:"CALLBACK_TOKEN_TTL" envDefault:"24h"`
+	}
+
+	// AuthConfig configures REST API authentication. When enabled every route requires an API key
+	// or a JWT bearer token, except routes marked public (health probes and webhooks) and, when
+	// configured, /metrics and /docs.
+	AuthConfig struct {
+		Enabled bool `env:"AUTH_ENABLED" envDefault:"false"`
+		// JWKSURL or JWKSFile enable JWT bearer tokens verified against that key set (URL wins).
+		JWKSURL  string `env:"AUTH_JWT_JWKS_URL"`
+		JWKSFile string `env:"AUTH_JWT_JWKS_FILE"`
+		// JWKSRefresh is how often the key set is reloaded (0 = only on unknown key IDs).
+		JWKSRefresh time.Duration `env:"AUTH_JWT_JWKS_REFRESH" envDefault:"15m"`
+		// Issuer and Audience, when set, must match the token's iss and aud claims.
+		Issuer    string        `env:"AUTH_JWT_ISSUER"`
+		Audience  string        `env:"AUTH_JWT_AUDIENCE"`
+		ClockSkew time.Duration `env:"AUTH_JWT_CLOCK_SKEW" envDefault:"30s"`
+		// PublicMetrics and PublicDocs leave /metrics and /docs unauthenticated.
+		PublicMetrics bool `env:"AUTH_PUBLIC_METRICS" envDefault:"true"`
+		PublicDocs    bool `env:"AUTH_PUBLIC_DOCS" envDefault:"true"`
+		// RBACEnabled enforces role-based authorization on authenticated requests.
+		RBACEnabled bool `env:"AUTH_RBAC_ENABLED" envDefault:"false"`
+		// RBACAdminsCSV lists subjects ("apikey:<id>" or JWT sub) holding the built-in admin role,
+		// which bootstraps role management.
+		RBACAdminsCSV string `env:"AUTH_RBAC_ADMINS"`
 	}
 
 	// SecretsConfig configures the secret store backend (ADR-0031). Schemas
 	// reference secrets by name; the engine resolves them per environment at
 	// input-mapping time and redacts them from all sinks.
 	SecretsConfig struct {
-		// Driver selects the backend: "memory" (default, dev) or "postgres"
-		// (encrypted at rest). Infisical is added in a follow-up.
+		// Driver selects the backend: "memory" (default, dev), "postgres"
+		// (encrypted at rest), or the read-only external backends "vault"
+		// (HashiCorp Vault KV v2) and "file" (mounted secret files).
 		Driver string `env:"SECRETS_DRIVER" envDefault:"memory"`
-		// EncryptionKey is a base64-encoded 32-byte AES-256 key; required for the
-		// postgres driver.
+		// CacheTTL is how long the external backends serve a value before reading it
+		// again; 0 disables caching.
+		CacheTTL time.Duration `env:"SECRETS_CACHE_TTL" envDefault:"5m"`
+		// FileDir is the root of the file backend: <dir>/<environment>/<name>.
+		FileDir string `env:"SECRETS_FILE_DIR" envDefault:"/var/run/secrets/fuse"`
+		// Vault configures the vault driver.
+		Vault VaultSecretsConfig `envPrefix:"SECRETS_VAULT_"`
+		// EncryptionKey is a base64-encoded 32-byte AES-256 key, registered in the
+		// keyring under the key ID "default". The postgres driver needs it or Keyring.
 		EncryptionKey string `env:"SECRETS_ENCRYPTION_KEY"`
+		// Keyring lists further keys as comma-separated "id:base64key" entries. Keys
+		// other than the active one are decrypt-only, kept until `fuse secrets
+		// rotate-key` has moved every secret off them.
+		Keyring string `env:"SECRETS_KEYRING"`
+		// ActiveKeyID selects the key that encrypts new and rotated secrets; it
+		// defaults to the first Keyring entry, else "default".
+		ActiveKeyID string `env:"SECRETS_ACTIVE_KEY_ID"`
+	}
+
+	// VaultSecretsConfig configures the Vault KV v2 secrets backend. Environments map to
+	// <PATH_PREFIX>/<environment>/<name> below the mount; TOKEN enables token auth and
+	// ROLE_ID/SECRET_ID AppRole auth.
+	VaultSecretsConfig struct {
+		Address      string `env:"ADDR"`
+		Mount        string `env:"MOUNT" envDefault:"secret"`
+		PathPrefix   string `env:"PATH_PREFIX" envDefault:"fuse"`
+		Field        string `env:"FIELD" envDefault:"value"`
+		Token        string `env:"TOKEN"`
+		RoleID       string `env:"ROLE_ID"`
+		SecretID     string `env:"SECRET_ID"`
+		AppRoleMount string `env:"APPROLE_MOUNT" envDefault:"approle"`
 	}
 
 	// LLMConfig configures the available LLM provider connections. Each provider
@@ -59,6 +126,33 @@ type (
 		Ollama          LLMProviderConfig `envPrefix:"LLM_OLLAMA_"`
 		Gemini          LLMProviderConfig `envPrefix:"LLM_GEMINI_"`
 		Anthropic       LLMProviderConfig `envPrefix:"LLM_ANTHROPIC_"`
+		// Pricing is the table LLM calls are priced with for cost metrics and budgets: comma-separated
+		// provider/model=input:output entries in USD per million tokens; provider/* prices any model.
+		Pricing string `env:"LLM_PRICING"`
+		// RoutingPolicies defines named fallback chains a node can use as its provider: semicolon-
+		// separated name=chain entries, a chain being tiers joined by ">" and a tier weighted routes
+		// provider[/model][*weight] joined by "|".
+		RoutingPolicies string `env:"LLM_ROUTING_POLICIES"`
+		// RoutingAttemptTimeout bounds each attempt of a routing policy, so a route that hangs falls
+		// back instead of using up the node's timeout; 0 disables it.
+		RoutingAttemptTimeout time.Duration `env:"LLM_ROUTING_ATTEMPT_TIMEOUT" envDefault:"60s"`
+		// CircuitBreakerFailures is how many consecutive rate limits, server errors or timeouts of a
+		// provider/model open its circuit, so routing policies skip it; 0 disables circuit breaking.
+		CircuitBreakerFailures int `env:"LLM_CIRCUIT_BREAKER_FAILURES" envDefault:"5"`
+		// CircuitBreakerCooldown is how long an open circuit is skipped before one trial request.
+		CircuitBreakerCooldown time.Duration `env:"LLM_CIRCUIT_BREAKER_COOLDOWN" envDefault:"30s"`
+		// SessionTTL is how long an agent session (sessionId) is kept after its last run when the
+		// node sets no sessionTTL.
+		SessionTTL time.Duration `env:"LLM_SESSION_TTL" envDefault:"720h"`
+		// VectorStoreDriver selects where ai/index stores embedded chunks: "memory" (default, dev)
+		// or "postgres" (pgvector).
+		VectorStoreDriver string `env:"VECTOR_STORE_DRIVER" envDefault:"memory"`
+		// MCPStdioEnabled allows MCP servers with the stdio transport, which run as subprocesses of
+		// the engine. Off by default: registering one runs an arbitrary command on every node.
+		MCPStdioEnabled bool `env:"MCP_STDIO_ENABLED" envDefault:"false"`
+		// MCPServerWait is how long a tool call on the engine's MCP endpoint waits for the
+		// execution to end before answering with a handle, unless the schema sets mcp.wait.
+		MCPServerWait time.Duration `env:"MCP_SERVER_WAIT" envDefault:"30s"`
 	}
 
 	// LLMProviderConfig configures a single LLM provider connection.
@@ -71,6 +165,9 @@ type (
 		// provider's apiKey is taken from that credential's apiKey field (resolved per environment).
 		Credential  string  `env:"CREDENTIAL"`
 		Temperature float32 `env:"TEMPERATURE" envDefault:"0.7"`
+		// EmbeddingModel is the default model of ai/embed, ai/index and ai/retrieve nodes; only
+		// OpenAI-compatible providers support embeddings.
+		EmbeddingModel string `env:"EMBEDDING_MODEL"`
 	}
 
 	// ParamsConfig configuration parameters
@@ -149,6 +246,31 @@ type (
 		LeaseTimeout       time.Duration `env:"HA_LEASE_TIMEOUT" envDefault:"30s"`
 	}
 
+	// RetentionConfig configures the global retention policy for terminal executions and the
+	// background reaper that enforces it. Schemas may override the limits via "retention".
+	RetentionConfig struct {
+		// Enabled starts the reaper actor; `fuse purge` works regardless.
+		Enabled bool `env:"RETENTION_ENABLED" envDefault:"false"`
+		// Interval between reaper sweeps.
+		Interval time.Duration `env:"RETENTION_INTERVAL" envDefault:"1h"`
+		// BatchSize caps how many executions are deleted per repository round-trip.
+		BatchSize int `env:"RETENTION_BATCH_SIZE" envDefault:"500"`
+		// MaxAge and MaxCount apply to every terminal state without a per-state override.
+		// Zero disables the limit.
+		MaxAge   time.Duration `env:"RETENTION_MAX_AGE" envDefault:"0"`
+		MaxCount int           `env:"RETENTION_MAX_COUNT" envDefault:"0"`
+		// Per-state overrides (RETENTION_FINISHED_MAX_AGE, RETENTION_ERROR_MAX_COUNT, ...).
+		Finished  RetentionStateConfig `envPrefix:"RETENTION_FINISHED_"`
+		Error     RetentionStateConfig `envPrefix:"RETENTION_ERROR_"`
+		Cancelled RetentionStateConfig `envPrefix:"RETENTION_CANCELLED_"`
+	}
+
+	// RetentionStateConfig holds the retention limits for a single terminal state.
+	RetentionStateConfig struct {
+		MaxAge   time.Duration `env:"MAX_AGE" envDefault:"0"`
+		MaxCount int           `env:"MAX_COUNT" envDefault:"0"`
+	}
+
 	// OtelConfig configuration for OpenTelemetry distributed tracing
 	OtelConfig struct {
 		Enabled        bool   `env:"OTEL_ENABLED" envDefault:"false"`
@@ -206,6 +328,22 @@ func (c *ClusterConfig) EtcdEndpointsList() []string {
 	return out
 }
 
+// RBACAdmins returns AUTH_RBAC_ADMINS split into non-empty trimmed subjects.
+func (c *AuthConfig) RBACAdmins() []string {
+	if c.RBACAdminsCSV == "" {
+		return nil
+	}
+	parts := strings.Split(c.RBACAdminsCSV, ",")
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
 // PeerNodeNames returns CLUSTER_PEER_NODES split into non-empty trimmed entries (full ergo node names).
 func (c *ClusterConfig) PeerNodeNames() []string {
 	if c.PeerNodesCSV == "" {
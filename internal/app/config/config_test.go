@@ -2,6 +2,7 @@ package config_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/caarlos0/env/v11"
 	"github.com/open-source-cloud/fuse/internal/app/config"
@@ -76,3 +77,21 @@ func TestLLMConfig_EnvPrefixParsing(t *testing.T) {
 	// Unset providers default to disabled.
 	assert.False(t, cfg.Anthropic.Enabled)
 }
+
+func TestRetentionConfig_EnvPrefixParsing(t *testing.T) {
+	t.Setenv("RETENTION_ENABLED", "true")
+	t.Setenv("RETENTION_MAX_AGE", "720h")
+	t.Setenv("RETENTION_ERROR_MAX_AGE", "2160h")
+	t.Setenv("RETENTION_FINISHED_MAX_COUNT", "1000")
+
+	var cfg config.RetentionConfig
+	require.NoError(t, env.Parse(&cfg))
+
+	assert.True(t, cfg.Enabled)
+	assert.Equal(t, time.Hour, cfg.Interval)
+	assert.Equal(t, 500, cfg.BatchSize)
+	assert.Equal(t, 720*time.Hour, cfg.MaxAge)
+	assert.Equal(t, 2160*time.Hour, cfg.Error.MaxAge)
+	assert.Equal(t, 1000, cfg.Finished.MaxCount)
+	assert.Zero(t, cfg.Cancelled.MaxAge)
+}
@@ -24,21 +24,40 @@ type workerHandlerRegistrationParams struct {
 	GetWorkflowHandlerFactory           *handlers.GetWorkflowHandlerFactory
 	CancelWorkflowHandlerFactory        *handlers.CancelWorkflowHandlerFactory
 	ResolveAwakeableHandlerFactory      *handlers.ResolveAwakeableHandlerFactory
+	AwakeableTokenHandlerFactory        *handlers.AwakeableTokenHandlerFactory
 	GetWorkflowSnapshotHandlerFactory   *handlers.GetWorkflowSnapshotHandlerFactory
 	RetryNodeHandlerFactory             *handlers.RetryNodeHandlerFactory
 	RetryWorkflowHandlerFactory         *handlers.RetryWorkflowHandlerFactory
 	ListExecutionsHandlerFactory        *handlers.ListExecutionsHandlerFactory
+	SearchExecutionsHandlerFactory      *handlers.SearchExecutionsHandlerFactory
 	WorkflowTraceHandlerFactory         *handlers.WorkflowTraceHandlerFactory
 	SchemaTracesHandlerFactory          *handlers.SchemaTracesHandlerFactory
 	WebhookHandlerFactory               *handlers.WebhookHandlerFactory
 	ListSchemaVersionsHandlerFactory    *handlers.ListSchemaVersionsHandlerFactory
 	GetSchemaVersionHandlerFactory      *handlers.GetSchemaVersionHandlerFactory
+	DiffSchemaVersionsHandlerFactory    *handlers.DiffSchemaVersionsHandlerFactory
 	ActivateSchemaVersionHandlerFactory *handlers.ActivateSchemaVersionHandlerFactory
 	RollbackSchemaHandlerFactory        *handlers.RollbackSchemaHandlerFactory
+	SchemaLifecycleHandlerFactory       *handlers.SchemaLifecycleHandlerFactory
+	SchemaTrafficHandlerFactory         *handlers.SchemaTrafficHandlerFactory
 	EnvironmentsHandlerFactory          *handlers.EnvironmentsHandlerFactory
 	EnvironmentHandlerFactory           *handlers.EnvironmentHandlerFactory
+	EnvironmentVarsHandlerFactory       *handlers.EnvironmentVarsHandlerFactory
+	EnvironmentVarHandlerFactory        *handlers.EnvironmentVarHandlerFactory
+	PromoteEnvironmentHandlerFactory    *handlers.PromoteEnvironmentHandlerFactory
+	NamespacesHandlerFactory            *handlers.NamespacesHandlerFactory
+	NamespaceHandlerFactory             *handlers.NamespaceHandlerFactory
 	CredentialsHandlerFactory           *handlers.CredentialsHandlerFactory
 	CredentialHandlerFactory            *handlers.CredentialHandlerFactory
+	APIKeysHandlerFactory               *handlers.APIKeysHandlerFactory
+	APIKeyHandlerFactory                *handlers.APIKeyHandlerFactory
+	RolesHandlerFactory                 *handlers.RolesHandlerFactory
+	RoleHandlerFactory                  *handlers.RoleHandlerFactory
+	RoleBindingsHandlerFactory          *handlers.RoleBindingsHandlerFactory
+	AuditLogHandlerFactory              *handlers.AuditLogHandlerFactory
+	AgentSessionHandlerFactory          *handlers.AgentSessionHandlerFactory
+	MCPServersHandlerFactory            *handlers.MCPServersHandlerFactory
+	MCPServerHandlerFactory             *handlers.MCPServerHandlerFactory
 }
 
 // newWorkers builds the HTTP worker registry with all handler factories registered.
@@ -58,21 +77,40 @@ func newWorkers(p workerHandlerRegistrationParams) *actors.Workers {
 	w.AddFactory(handlers.GetWorkflowHandlerName, p.GetWorkflowHandlerFactory.Factory)
 	w.AddFactory(handlers.CancelWorkflowHandlerName, p.CancelWorkflowHandlerFactory.Factory)
 	w.AddFactory(handlers.ResolveAwakeableHandlerName, p.ResolveAwakeableHandlerFactory.Factory)
+	w.AddFactory(handlers.AwakeableTokenHandlerName, p.AwakeableTokenHandlerFactory.Factory)
 	w.AddFactory(handlers.GetWorkflowSnapshotHandlerName, p.GetWorkflowSnapshotHandlerFactory.Factory)
 	w.AddFactory(handlers.RetryNodeHandlerName, p.RetryNodeHandlerFactory.Factory)
 	w.AddFactory(handlers.RetryWorkflowHandlerName, p.RetryWorkflowHandlerFactory.Factory)
 	w.AddFactory(handlers.ListExecutionsHandlerName, p.ListExecutionsHandlerFactory.Factory)
+	w.AddFactory(handlers.SearchExecutionsHandlerName, p.SearchExecutionsHandlerFactory.Factory)
 	w.AddFactory(handlers.WorkflowTraceHandlerName, p.WorkflowTraceHandlerFactory.Factory)
 	w.AddFactory(handlers.SchemaTracesHandlerName, p.SchemaTracesHandlerFactory.Factory)
 	w.AddFactory(handlers.WebhookHandlerName, p.WebhookHandlerFactory.Factory)
 	w.AddFactory(handlers.ListSchemaVersionsHandlerName, p.ListSchemaVersionsHandlerFactory.Factory)
 	w.AddFactory(handlers.GetSchemaVersionHandlerName, p.GetSchemaVersionHandlerFactory.Factory)
+	w.AddFactory(handlers.DiffSchemaVersionsHandlerName, p.DiffSchemaVersionsHandlerFactory.Factory)
 	w.AddFactory(handlers.ActivateSchemaVersionHandlerName, p.ActivateSchemaVersionHandlerFactory.Factory)
 	w.AddFactory(handlers.RollbackSchemaHandlerName, p.RollbackSchemaHandlerFactory.Factory)
+	w.AddFactory(handlers.SchemaLifecycleHandlerName, p.SchemaLifecycleHandlerFactory.Factory)
+	w.AddFactory(handlers.SchemaTrafficHandlerName, p.SchemaTrafficHandlerFactory.Factory)
 	w.AddFactory(handlers.EnvironmentsHandlerName, p.EnvironmentsHandlerFactory.Factory)
 	w.AddFactory(handlers.EnvironmentHandlerName, p.EnvironmentHandlerFactory.Factory)
+	w.AddFactory(handlers.EnvironmentVarsHandlerName, p.EnvironmentVarsHandlerFactory.Factory)
+	w.AddFactory(handlers.EnvironmentVarHandlerName, p.EnvironmentVarHandlerFactory.Factory)
+	w.AddFactory(handlers.PromoteEnvironmentHandlerName, p.PromoteEnvironmentHandlerFactory.Factory)
+	w.AddFactory(handlers.NamespacesHandlerName, p.NamespacesHandlerFactory.Factory)
+	w.AddFactory(handlers.NamespaceHandlerName, p.NamespaceHandlerFactory.Factory)
 	w.AddFactory(handlers.CredentialsHandlerName, p.CredentialsHandlerFactory.Factory)
 	w.AddFactory(handlers.CredentialHandlerName, p.CredentialHandlerFactory.Factory)
+	w.AddFactory(handlers.APIKeysHandlerName, p.APIKeysHandlerFactory.Factory)
+	w.AddFactory(handlers.APIKeyHandlerName, p.APIKeyHandlerFactory.Factory)
+	w.AddFactory(handlers.RolesHandlerName, p.RolesHandlerFactory.Factory)
+	w.AddFactory(handlers.RoleHandlerName, p.RoleHandlerFactory.Factory)
+	w.AddFactory(handlers.RoleBindingsHandlerName, p.RoleBindingsHandlerFactory.Factory)
+	w.AddFactory(handlers.AuditLogHandlerName, p.AuditLogHandlerFactory.Factory)
+	w.AddFactory(handlers.AgentSessionHandlerName, p.AgentSessionHandlerFactory.Factory)
+	w.AddFactory(handlers.MCPServersHandlerName, p.MCPServersHandlerFactory.Factory)
+	w.AddFactory(handlers.MCPServerHandlerName, p.MCPServerHandlerFactory.Factory)
 	return w
 }
 
@@ -92,21 +130,42 @@ var WorkerModule = fx.Module(
 		handlers.NewGetWorkflowHandlerFactory,
 		handlers.NewCancelWorkflowHandlerFactory,
 		handlers.NewResolveAwakeableHandlerFactory,
+		handlers.NewAwakeableTokenHandlerFactory,
 		handlers.NewGetWorkflowSnapshotHandlerFactory,
 		handlers.NewRetryNodeHandlerFactory,
 		handlers.NewRetryWorkflowHandlerFactory,
 		handlers.NewListExecutionsHandlerFactory,
+		handlers.NewSearchExecutionsHandlerFactory,
 		handlers.NewWorkflowTraceHandlerFactory,
 		handlers.NewSchemaTracesHandlerFactory,
 		handlers.NewWebhookHandlerFactory,
 		handlers.NewListSchemaVersionsHandlerFactory,
 		handlers.NewGetSchemaVersionHandlerFactory,
+		handlers.NewDiffSchemaVersionsHandlerFactory,
 		handlers.NewActivateSchemaVersionHandlerFactory,
 		handlers.NewRollbackSchemaHandlerFactory,
+		handlers.NewSchemaLifecycleHandlerFactory,
+		handlers.NewSchemaTrafficHandlerFactory,
 		handlers.NewEnvironmentsHandler,
 		handlers.NewEnvironmentHandler,
+		handlers.NewEnvironmentVarsHandler,
+		handlers.NewEnvironmentVarHandler,
+		handlers.NewPromoteEnvironmentHandler,
+		handlers.NewNamespacesHandler,
+		handlers.NewNamespaceHandler,
 		handlers.NewCredentialsHandler,
 		handlers.NewCredentialHandler,
+		handlers.NewAPIKeysHandler,
+		handlers.NewAPIKeyHandler,
+		handlers.NewRolesHandler,
+		handlers.NewRoleHandler,
+		handlers.NewRoleBindingsHandler,
+		handlers.NewAuditLogHandler,
+		handlers.NewAgentSessionHandler,
+		handlers.NewMCPServersHandler,
+		handlers.NewMCPServerHandler,
+		handlers.NewExecutionStreamHandler,
+		handlers.NewMCPEndpointHandler,
 		newWorkers,
 	),
 )
@@ -127,6 +186,7 @@ var ActorModule = fx.Module(
 		actors.NewCronSchedulerFactory,
 		actors.NewWebhookRouterFactory,
 		actors.NewEventTriggerFactory,
+		actors.NewRetentionReaperFactory,
 		providePgListenerActorFactory,
 	),
 )
@@ -0,0 +1,74 @@
+package di
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/open-source-cloud/fuse/internal/app/config"
+	"github.com/open-source-cloud/fuse/internal/auth"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/rs/zerolog/log"
+	"go.uber.org/fx"
+)
+
+// AuthModule provides the REST API Authenticator, nil when AUTH_ENABLED is false, and the
+// Authorizer, nil unless AUTH_RBAC_ENABLED is also true.
+var AuthModule = fx.Module(
+	"auth",
+	fx.Provide(
+		provideAuthenticator,
+		provideAuthorizer,
+	),
+)
+
+// provideAuthenticator accepts API keys from the APIKeyRepository and, when a JWKS source is
+// configured, JWT bearer tokens. The key set is loaded once up front so misconfiguration shows in
+// the startup logs; an unreachable identity provider does not block startup since keys are
+// fetched again on the first token.
+func provideAuthenticator(cfg *config.Config, keys repositories.APIKeyRepository) (auth.Authenticator, error) {
+	if !cfg.Auth.Enabled {
+		log.Warn().Msg("AUTH_ENABLED=false: the REST API is unauthenticated")
+		return nil, nil
+	}
+	chain := &auth.Chain{APIKeys: auth.NewAPIKeyAuthenticator(keys)}
+
+	var jwks *auth.JWKS
+	switch {
+	case cfg.Auth.JWKSURL != "":
+		jwks = auth.NewJWKSFromURL(cfg.Auth.JWKSURL, nil, cfg.Auth.JWKSRefresh)
+	case cfg.Auth.JWKSFile != "":
+		jwks = auth.NewJWKSFromFile(cfg.Auth.JWKSFile, cfg.Auth.JWKSRefresh)
+	}
+	if jwks != nil {
+		if err := jwks.Load(context.Background()); err != nil {
+			if cfg.Auth.JWKSURL == "" {
+				return nil, fmt.Errorf("auth: %w", err)
+			}
+			log.Warn().Err(err).Msg("failed to load jwks; retrying on the first bearer token")
+		}
+		chain.JWT = auth.NewJWTAuthenticator(auth.JWTConfig{
+			Issuer:    cfg.Auth.Issuer,
+			Audience:  cfg.Auth.Audience,
+			ClockSkew: cfg.Auth.ClockSkew,
+		}, jwks)
+	}
+	log.Info().Bool("jwt", chain.JWT != nil).Msg("REST API authentication enabled")
+	return chain, nil
+}
+
+// provideAuthorizer enforces the roles bound in the PolicyRepository; AUTH_RBAC_ADMINS subjects
+// hold the built-in admin role so a fresh install can create its first roles.
+func provideAuthorizer(cfg *config.Config, policies repositories.PolicyRepository) auth.Authorizer {
+	if !cfg.Auth.Enabled || !cfg.Auth.RBACEnabled {
+		if cfg.Auth.RBACEnabled {
+			log.Warn().Msg("AUTH_RBAC_ENABLED=true has no effect while AUTH_ENABLED=false")
+		}
+		return nil
+	}
+	admins := cfg.Auth.RBACAdmins()
+	if len(admins) == 0 {
+		log.Warn().Msg("AUTH_RBAC_ADMINS is empty: only subjects bound to the admin role can manage roles")
+	}
+	log.Info().Strs("admins", admins).Msg("REST API role-based authorization enabled")
+	return auth.NewRBAC(policies, admins)
+}
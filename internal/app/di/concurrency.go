@@ -11,5 +11,7 @@ var ConcurrencyModule = fx.Module(
 	fx.Provide(
 		concurrency.NewManager,
 		concurrency.NewRateLimiter,
+		concurrency.NewExecutionContexts,
+		concurrency.NewWaiters,
 	),
 )
@@ -2,6 +2,7 @@
 package di
 
 import (
+	"context"
 	"fmt"
 
 	"ergo.services/ergo/gen"
@@ -11,8 +12,11 @@ import (
 	"github.com/open-source-cloud/fuse/internal/logging"
 	"github.com/open-source-cloud/fuse/internal/metrics"
 	"github.com/open-source-cloud/fuse/internal/packages"
+	"github.com/open-source-cloud/fuse/internal/packages/functions/ai"
 	"github.com/open-source-cloud/fuse/internal/services"
 	"github.com/open-source-cloud/fuse/internal/tracing"
+	"github.com/open-source-cloud/fuse/pkg/llm"
+	"github.com/open-source-cloud/fuse/pkg/vectorstore"
 	"github.com/rs/zerolog"
 	"go.uber.org/fx"
 )
@@ -39,13 +43,58 @@ var CommonModule = fx.Module(
 	}),
 )
 
+func bindAgentExecRuntime(lc fx.Lifecycle, node gen.Node, runtime *packages.AgentExecRuntime) {
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			runtime.BindNode(node)
+			return nil
+		},
+	})
+}
+
+type internalPackagesParams struct {
+	fx.In
+	Providers llm.Registry
+	Registry  packages.Registry
+	Metrics   *metrics.FuseMetrics
+	Pricing   llm.Pricing
+	Ledger    ai.BudgetLedger
+	Sessions  ai.SessionStore
+	Vectors   vectorstore.VectorStore
+	Outputs   ai.OutputStream
+	Runtime   ai.ExecRuntime
+	Catalog   ai.WorkflowCatalog
+	MCPTools  packages.MCPTools
+}
+
+func provideInternalPackages(p internalPackagesParams) packages.InternalPackages {
+	return packages.NewInternal(packages.InternalDeps{
+		Providers: p.Providers,
+		Registry:  p.Registry,
+		Metrics:   p.Metrics,
+		Pricing:   p.Pricing,
+		Ledger:    p.Ledger,
+		Sessions:  p.Sessions,
+		Vectors:   p.Vectors,
+		Outputs:   p.Outputs,
+		Runtime:   p.Runtime,
+		Catalog:   p.Catalog,
+		MCPTools:  p.MCPTools,
+	})
+}
+
 // PackageModule FX module with the package providers
 var PackageModule = fx.Module(
 	"package",
 	fx.Provide(
 		packages.NewPackageRegistry,
-		packages.NewInternal,
+		provideInternalPackages,
 		providePackageRegistration,
+		packages.NewAgentExecRuntime,
+		fx.Annotate(
+			func(r *packages.AgentExecRuntime) ai.ExecRuntime { return r },
+			fx.As(new(ai.ExecRuntime)),
+		),
 	),
 )
 
@@ -57,6 +106,8 @@ var FuseAppModule = fx.Module(
 		app.NewApp,
 	),
 	fx.Invoke(func(_ gen.Node) {}),
+	// bound here rather than in PackageModule, which CLI commands use without an actor node
+	fx.Invoke(bindAgentExecRuntime),
 )
 
 // AllModules FX module with the complete application + base providers
@@ -67,14 +118,17 @@ var AllModules = fx.Options(
 	PackageModule,
 	DatabaseModule,
 	ObjectStoreModule,
+	VectorStoreModule,
 	IdempotencyModule,
 	ConcurrencyModule,
 	EventsModule,
 	RepoModule,
+	AuthModule,
 	ServicesModule,
 	WorkerModule,
 	ActorModule,
 	FuseAppModule,
+	fx.Decorate(decorateOAuth2SecretStore),
 	fx.WithLogger(logging.NewFxLogger()),
 )
 
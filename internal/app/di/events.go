@@ -4,13 +4,22 @@ import (
 	"context"
 
 	"github.com/open-source-cloud/fuse/internal/events"
+	"github.com/open-source-cloud/fuse/internal/packages/functions/ai"
+	"github.com/open-source-cloud/fuse/internal/streams"
 	"go.uber.org/fx"
 )
 
-// EventsModule FX module providing the event bus
+// EventsModule FX module providing the event bus and the execution output stream broker
 var EventsModule = fx.Module(
 	"events",
-	fx.Provide(provideEventBus),
+	fx.Provide(
+		provideEventBus,
+		provideStreamBroker,
+		fx.Annotate(
+			func(b *streams.Broker) ai.OutputStream { return b },
+			fx.As(new(ai.OutputStream)),
+		),
+	),
 )
 
 func provideEventBus(lc fx.Lifecycle) events.EventBus {
@@ -24,3 +33,7 @@ func provideEventBus(lc fx.Lifecycle) events.EventBus {
 	})
 	return bus
 }
+
+func provideStreamBroker() *streams.Broker {
+	return streams.NewBroker(streams.DefaultRetention)
+}
@@ -23,10 +23,10 @@ const (
 	providerAnthropic  = "anthropic"
 )
 
-// LLMModule provides the LLM provider registry built from configuration.
+// LLMModule provides the LLM provider registry and pricing table built from configuration.
 var LLMModule = fx.Module(
 	"llm",
-	fx.Provide(provideLLMRegistry),
+	fx.Provide(provideLLMRegistry, provideLLMPricing),
 )
 
 type llmProvider struct {
@@ -41,12 +41,19 @@ type providerBuilder func(name, apiKey, baseURL, model string) llm.Provider
 // provider's APIKey / BaseURL may be a {{secret:NAME}} reference (ADR-0031), in which case its
 // factory resolves the reference from the SecretStore against the running workflow's environment
 // on each call; providers with fully-static config are built once (fast path). All providers are
-// disabled by default; only enabled ones are registered.
-func provideLLMRegistry(cfg *config.Config, secretStore secrets.SecretStore) llm.Registry {
+// disabled by default; only enabled ones are registered. LLM_ROUTING_POLICIES adds a composite
+// provider per policy; an invalid policy, or one routing to a provider that is not enabled, fails
+// startup.
+func provideLLMRegistry(cfg *config.Config, secretStore secrets.SecretStore) (llm.Registry, error) {
 	factories := make(map[string]llm.ProviderFactory)
 
-	openAICompatBuild := func(name, apiKey, baseURL, model string) llm.Provider {
-		return openaicompat.New(openaicompat.Config{Name: name, APIKey: apiKey, BaseURL: baseURL, Model: model})
+	// openAICompatBuild binds a provider's embedding model, which needs no secret resolution.
+	openAICompatBuild := func(embeddingModel string) providerBuilder {
+		return func(name, apiKey, baseURL, model string) llm.Provider {
+			return openaicompat.New(openaicompat.Config{
+				Name: name, APIKey: apiKey, BaseURL: baseURL, Model: model, EmbeddingModel: embeddingModel,
+			})
+		}
 	}
 	anthropicBuild := func(name, apiKey, baseURL, model string) llm.Provider {
 		return anthropic.New(anthropic.Config{Name: name, APIKey: apiKey, BaseURL: baseURL, Model: model})
@@ -63,7 +70,7 @@ func provideLLMRegistry(cfg *config.Config, secretStore secrets.SecretStore) llm
 		if !p.conf.Enabled {
 			continue
 		}
-		factories[p.name] = newProviderFactory(p.name, p.conf, openAICompatBuild, secretStore, cfg.Environment)
+		factories[p.name] = newProviderFactory(p.name, p.conf, openAICompatBuild(p.conf.EmbeddingModel), secretStore, cfg.Environment)
 		log.Info().Str("provider", p.name).Str("model", p.conf.Model).Msg("LLM provider registered")
 	}
 
@@ -77,7 +84,26 @@ func provideLLMRegistry(cfg *config.Config, secretStore secrets.SecretStore) llm
 		log.Info().Msg("no LLM providers enabled; ai/chat and ai/agent nodes will be unavailable")
 	}
 
-	return llm.NewRegistry(factories, cfg.LLM.DefaultProvider)
+	// Routing policies are composite providers over the ones above, registered next to them so a
+	// node's provider (and LLM_DEFAULT_PROVIDER) may name either.
+	policies, err := llm.ParseRoutingPolicies(cfg.LLM.RoutingPolicies)
+	if err != nil {
+		return nil, fmt.Errorf("LLM_ROUTING_POLICIES: %w", err)
+	}
+	routed, err := llm.RoutingFactories(policies, factories, llm.RoutingOptions{
+		BreakerFailures: cfg.LLM.CircuitBreakerFailures,
+		BreakerCooldown: cfg.LLM.CircuitBreakerCooldown,
+		AttemptTimeout:  cfg.LLM.RoutingAttemptTimeout,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("LLM_ROUTING_POLICIES: %w", err)
+	}
+	for _, policy := range policies {
+		factories[policy.Name] = routed[policy.Name]
+		log.Info().Str("policy", policy.Name).Int("tiers", len(policy.Tiers)).Msg("LLM routing policy registered")
+	}
+
+	return llm.NewRegistry(factories, cfg.LLM.DefaultProvider), nil
 }
 
 // newProviderFactory returns a factory for one provider. When the config has no {{secret:NAME}} or
@@ -137,3 +163,16 @@ func resolveRefs(s string, resolve func(string) (string, error)) (string, error)
 	}
 	return secrets.ReplaceCredentialRefs(out, resolve)
 }
+
+// provideLLMPricing parses LLM_PRICING. An invalid table fails startup rather than leaving spend
+// silently unpriced; an empty one prices every call at zero, so only token budgets apply.
+func provideLLMPricing(cfg *config.Config) (llm.Pricing, error) {
+	pricing, err := llm.ParsePricing(cfg.LLM.Pricing)
+	if err != nil {
+		return nil, fmt.Errorf("LLM_PRICING: %w", err)
+	}
+	if len(pricing) > 0 {
+		log.Info().Int("models", len(pricing)).Msg("LLM pricing table loaded")
+	}
+	return pricing, nil
+}
@@ -27,7 +27,8 @@ func TestProvideLLMRegistry_ResolvesKeyPerEnvironment(t *testing.T) {
 		},
 	}
 
-	reg := provideLLMRegistry(cfg, store)
+	reg, err := provideLLMRegistry(cfg, store)
+	require.NoError(t, err)
 
 	// The secret resolves in the staging environment -> provider builds.
 	prov, err := reg.Get(ctx, "staging", providerOpenAI)
@@ -59,7 +60,8 @@ func TestProvideLLMRegistry_ResolvesCredential(t *testing.T) {
 		},
 	}
 
-	reg := provideLLMRegistry(cfg, store)
+	reg, err := provideLLMRegistry(cfg, store)
+	require.NoError(t, err)
 
 	// The credential's apiKey resolves in staging -> provider builds.
 	prov, err := reg.Get(ctx, "staging", providerOpenAI)
@@ -86,7 +88,8 @@ func TestProvideLLMRegistry_StaticProviderIsSingleton(t *testing.T) {
 		},
 	}
 
-	reg := provideLLMRegistry(cfg, secrets.NewMemorySecretStore())
+	reg, err := provideLLMRegistry(cfg, secrets.NewMemorySecretStore())
+	require.NoError(t, err)
 
 	// No secret refs -> the provider is built once and reused (fast path), regardless of env.
 	p1, err := reg.Get(ctx, "staging", providerOllama)
@@ -95,3 +98,37 @@ func TestProvideLLMRegistry_StaticProviderIsSingleton(t *testing.T) {
 	require.NoError(t, err)
 	assert.Same(t, p1, p2)
 }
+
+func TestProvideLLMRegistry_RoutingPolicies(t *testing.T) {
+	cfg := &config.Config{
+		Environment: "default",
+		LLM: config.LLMConfig{
+			DefaultProvider: "resilient",
+			Ollama:          config.LLMProviderConfig{Enabled: true, Model: "llama3"},
+			RoutingPolicies: "resilient=openai/gpt-4o>ollama",
+		},
+	}
+	_, err := provideLLMRegistry(cfg, secrets.NewMemorySecretStore())
+	assert.ErrorContains(t, err, "LLM_ROUTING_POLICIES", "a policy may only route to enabled providers")
+
+	cfg.LLM.OpenAI = config.LLMProviderConfig{Enabled: true, APIKey: "sk-test"}
+	reg, err := provideLLMRegistry(cfg, secrets.NewMemorySecretStore())
+	require.NoError(t, err)
+	policy, err := reg.Default(context.Background(), "prod")
+	require.NoError(t, err)
+	assert.Equal(t, "resilient", policy.Name())
+	assert.ElementsMatch(t, []string{providerOpenAI, providerOllama, "resilient"}, reg.List())
+
+	cfg.LLM.RoutingPolicies = "ollama=openai"
+	_, err = provideLLMRegistry(cfg, secrets.NewMemorySecretStore())
+	assert.ErrorContains(t, err, "name is taken")
+}
+
+func TestProvideLLMPricing(t *testing.T) {
+	pricing, err := provideLLMPricing(&config.Config{LLM: config.LLMConfig{Pricing: "openai/gpt-4o=2.5:10,ollama/*=0:0"}})
+	require.NoError(t, err)
+	assert.Len(t, pricing, 2)
+
+	_, err = provideLLMPricing(&config.Config{LLM: config.LLMConfig{Pricing: "openai/gpt-4o"}})
+	assert.ErrorContains(t, err, "LLM_PRICING")
+}
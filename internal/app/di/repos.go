@@ -23,7 +23,15 @@ var RepoModule = fx.Module(
 		provideClaimRepository,
 		provideTraceRepository,
 		provideEnvironmentRepository,
+		provideEnvironmentVarRepository,
+		provideNamespaceRepository,
 		provideCredentialRepository,
+		provideAPIKeyRepository,
+		providePolicyRepository,
+		provideAuditRepository,
+		provideLLMSpendRepository,
+		provideAgentSessionRepository,
+		provideMCPServerRepository,
 	),
 )
 
@@ -97,6 +105,24 @@ func provideEnvironmentRepository(p repoParams) repositories.EnvironmentReposito
 	return repositories.NewMemoryEnvironmentRepository()
 }
 
+func provideEnvironmentVarRepository(p repoParams) repositories.EnvironmentVarRepository {
+	if p.Config.Database.Driver == config.DBDriverPostgres && p.Pool != nil {
+		log.Debug().Msg("using postgres environment var repository")
+		return postgres.NewEnvironmentVarRepository(p.Pool)
+	}
+	log.Debug().Msg("using memory environment var repository")
+	return repositories.NewMemoryEnvironmentVarRepository()
+}
+
+func provideNamespaceRepository(p repoParams) repositories.NamespaceRepository {
+	if p.Config.Database.Driver == config.DBDriverPostgres && p.Pool != nil {
+		log.Debug().Msg("using postgres namespace repository")
+		return postgres.NewNamespaceRepository(p.Pool)
+	}
+	log.Debug().Msg("using memory namespace repository")
+	return repositories.NewMemoryNamespaceRepository()
+}
+
 func provideCredentialRepository(p repoParams) repositories.CredentialRepository {
 	if p.Config.Database.Driver == config.DBDriverPostgres && p.Pool != nil {
 		log.Debug().Msg("using postgres credential repository")
@@ -106,6 +132,24 @@ func provideCredentialRepository(p repoParams) repositories.CredentialRepository
 	return repositories.NewMemoryCredentialRepository()
 }
 
+func provideAPIKeyRepository(p repoParams) repositories.APIKeyRepository {
+	if p.Config.Database.Driver == config.DBDriverPostgres && p.Pool != nil {
+		log.Debug().Msg("using postgres api key repository")
+		return postgres.NewAPIKeyRepository(p.Pool)
+	}
+	log.Debug().Msg("using memory api key repository")
+	return repositories.NewMemoryAPIKeyRepository()
+}
+
+func providePolicyRepository(p repoParams) repositories.PolicyRepository {
+	if p.Config.Database.Driver == config.DBDriverPostgres && p.Pool != nil {
+		log.Debug().Msg("using postgres policy repository")
+		return postgres.NewPolicyRepository(p.Pool)
+	}
+	log.Debug().Msg("using memory policy repository")
+	return repositories.NewMemoryPolicyRepository()
+}
+
 func provideTraceRepository(p repoParams) repositories.TraceRepository {
 	if p.Config.Database.Driver == config.DBDriverPostgres && p.Pool != nil {
 		log.Debug().Msg("using postgres trace repository")
@@ -114,3 +158,39 @@ func provideTraceRepository(p repoParams) repositories.TraceRepository {
 	log.Debug().Msg("using memory trace repository")
 	return repositories.NewMemoryTraceRepository()
 }
+
+func provideAuditRepository(p repoParams) repositories.AuditRepository {
+	if p.Config.Database.Driver == config.DBDriverPostgres && p.Pool != nil {
+		log.Debug().Msg("using postgres audit repository")
+		return postgres.NewAuditRepository(p.Pool)
+	}
+	log.Debug().Msg("using memory audit repository")
+	return repositories.NewMemoryAuditRepository()
+}
+
+func provideLLMSpendRepository(p repoParams) repositories.LLMSpendRepository {
+	if p.Config.Database.Driver == config.DBDriverPostgres && p.Pool != nil {
+		log.Debug().Msg("using postgres llm spend repository")
+		return postgres.NewLLMSpendRepository(p.Pool)
+	}
+	log.Debug().Msg("using memory llm spend repository")
+	return repositories.NewMemoryLLMSpendRepository()
+}
+
+func provideAgentSessionRepository(p repoParams) repositories.AgentSessionRepository {
+	if p.Config.Database.Driver == config.DBDriverPostgres && p.Pool != nil {
+		log.Debug().Msg("using postgres agent session repository")
+		return postgres.NewAgentSessionRepository(p.Pool)
+	}
+	log.Debug().Msg("using memory agent session repository")
+	return repositories.NewMemoryAgentSessionRepository()
+}
+
+func provideMCPServerRepository(p repoParams) repositories.MCPServerRepository {
+	if p.Config.Database.Driver == config.DBDriverPostgres && p.Pool != nil {
+		log.Debug().Msg("using postgres mcp server repository")
+		return postgres.NewMCPServerRepository(p.Pool)
+	}
+	log.Debug().Msg("using memory mcp server repository")
+	return repositories.NewMemoryMCPServerRepository()
+}
@@ -1,10 +1,14 @@
 package di
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/open-source-cloud/fuse/internal/app/config"
+	"github.com/open-source-cloud/fuse/internal/repositories"
 	"github.com/open-source-cloud/fuse/internal/repositories/postgres"
 	"github.com/open-source-cloud/fuse/pkg/secrets"
 	"github.com/rs/zerolog/log"
@@ -29,7 +33,8 @@ type secretStoreParams struct {
 
 // provideSecretStore selects the secret backend by SECRETS_DRIVER, mirroring the
 // object-store driver pattern. Postgres encrypts at rest (AES-256-GCM); it falls
-// back to memory (with a warning) if no DB pool is available.
+// back to memory (with a warning) if no DB pool is available. Vault and file are
+// read-only and cached for SECRETS_CACHE_TTL.
 func provideSecretStore(p secretStoreParams) (secrets.SecretStore, error) {
 	switch p.Config.Secrets.Driver {
 	case "postgres":
@@ -37,14 +42,59 @@ func provideSecretStore(p secretStoreParams) (secrets.SecretStore, error) {
 			log.Warn().Msg("SECRETS_DRIVER=postgres but no database pool; falling back to the memory secret store")
 			return secrets.NewMemorySecretStoreFromEnv(p.Config.Environment), nil
 		}
-		cipher, err := secrets.NewCipherFromBase64Key(p.Config.Secrets.EncryptionKey)
+		keyring, err := secrets.ParseKeyring(p.Config.Secrets.Keyring, p.Config.Secrets.ActiveKeyID, p.Config.Secrets.EncryptionKey)
 		if err != nil {
-			return nil, fmt.Errorf("%w (set SECRETS_ENCRYPTION_KEY to a base64-encoded 32-byte key)", err)
+			return nil, fmt.Errorf("%w (set SECRETS_ENCRYPTION_KEY and/or SECRETS_KEYRING to base64-encoded 32-byte keys)", err)
 		}
-		log.Info().Str("environment", p.Config.Environment).Msg("using postgres (encrypted) secret store")
-		return postgres.NewSecretStore(p.Pool, cipher), nil
+		log.Info().Str("environment", p.Config.Environment).Str("activeKeyId", keyring.ActiveKeyID()).
+			Msg("using postgres (encrypted) secret store")
+		return postgres.NewSecretStore(p.Pool, keyring), nil
+	case "vault":
+		cfg := p.Config.Secrets.Vault
+		store, err := secrets.NewVaultSecretStore(secrets.VaultConfig{
+			Address:      cfg.Address,
+			Mount:        cfg.Mount,
+			PathPrefix:   cfg.PathPrefix,
+			Field:        cfg.Field,
+			Token:        cfg.Token,
+			RoleID:       cfg.RoleID,
+			SecretID:     cfg.SecretID,
+			AppRoleMount: cfg.AppRoleMount,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%w (set SECRETS_VAULT_ADDR and SECRETS_VAULT_TOKEN or SECRETS_VAULT_ROLE_ID)", err)
+		}
+		log.Info().Str("address", cfg.Address).Str("mount", cfg.Mount).Msg("using vault (read-only) secret store")
+		return cachedSecretStore(store, p.Config.Secrets.CacheTTL), nil
+	case "file":
+		log.Info().Str("dir", p.Config.Secrets.FileDir).Msg("using file (read-only) secret store")
+		return cachedSecretStore(secrets.NewFileSecretStore(p.Config.Secrets.FileDir), p.Config.Secrets.CacheTTL), nil
 	default:
 		log.Debug().Str("environment", p.Config.Environment).Msg("using memory secret store")
 		return secrets.NewMemorySecretStoreFromEnv(p.Config.Environment), nil
 	}
 }
+
+// cachedSecretStore wraps an external backend in a TTL cache unless SECRETS_CACHE_TTL is 0.
+func cachedSecretStore(store secrets.SecretStore, ttl time.Duration) secrets.SecretStore {
+	if ttl <= 0 {
+		return store
+	}
+	return secrets.NewCachingSecretStore(store, ttl)
+}
+
+// decorateOAuth2SecretStore computes the accessToken field of OAuth2 credentials on resolution.
+// It is applied at the root of the application graph so every consumer of the SecretStore (the
+// workflow engine, LLM providers, the credential service) sees the same token cache.
+func decorateOAuth2SecretStore(store secrets.SecretStore, repo repositories.CredentialRepository) secrets.SecretStore {
+	return secrets.NewOAuth2SecretStore(store, func(_ context.Context, namespace, id string) (string, error) {
+		cred, err := repo.FindByID(namespace, id)
+		if errors.Is(err, repositories.ErrCredentialNotFound) {
+			return "", nil
+		}
+		if err != nil {
+			return "", err
+		}
+		return cred.Type, nil
+	}, nil)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 
 	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/packages"
+	"github.com/open-source-cloud/fuse/internal/packages/functions/ai"
 	"github.com/open-source-cloud/fuse/internal/services"
 	"go.uber.org/fx"
 )
@@ -17,6 +19,36 @@ func bindSchemaReplicationPublisher(lc fx.Lifecycle, node gen.Node, pub *service
 	})
 }
 
+func bindWorkflowMCPService(lc fx.Lifecycle, node gen.Node, svc services.WorkflowMCPService) {
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			svc.BindNode(node)
+			return nil
+		},
+	})
+}
+
+func startTrafficSplitService(lc fx.Lifecycle, svc services.TrafficSplitService) {
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			return svc.Start()
+		},
+		OnStop: func(_ context.Context) error {
+			svc.Stop()
+			return nil
+		},
+	})
+}
+
+func closeMCPServers(lc fx.Lifecycle, svc services.MCPServerService) {
+	lc.Append(fx.Hook{
+		OnStop: func(_ context.Context) error {
+			svc.Close()
+			return nil
+		},
+	})
+}
+
 // ServicesModule provides the services for the application
 var ServicesModule = fx.Module(
 	"services",
@@ -29,7 +61,41 @@ var ServicesModule = fx.Module(
 		services.NewGraphService,
 		services.NewPackageService,
 		services.NewEnvironmentService,
+		services.NewEnvironmentVarService,
+		services.NewPromotionService,
+		services.NewNamespaceService,
 		services.NewCredentialService,
+		services.NewAPIKeyService,
+		services.NewPolicyService,
+		services.NewRetentionService,
+		services.NewSchemaLifecycleService,
+		services.NewTrafficSplitService,
+		services.NewCallbackTokenService,
+		services.NewAuditService,
+		services.NewBudgetService,
+		fx.Annotate(
+			func(s services.BudgetService) ai.BudgetLedger { return s },
+			fx.As(new(ai.BudgetLedger)),
+		),
+		services.NewAgentSessionService,
+		fx.Annotate(
+			func(s services.AgentSessionService) ai.SessionStore { return s },
+			fx.As(new(ai.SessionStore)),
+		),
+		services.NewWorkflowCatalog,
+		fx.Annotate(
+			func(c services.WorkflowCatalog) ai.WorkflowCatalog { return c },
+			fx.As(new(ai.WorkflowCatalog)),
+		),
+		services.NewMCPServerService,
+		fx.Annotate(
+			func(s services.MCPServerService) packages.MCPTools { return s },
+			fx.As(new(packages.MCPTools)),
+		),
+		services.NewWorkflowMCPService,
 	),
 	fx.Invoke(bindSchemaReplicationPublisher),
+	fx.Invoke(startTrafficSplitService),
+	fx.Invoke(closeMCPServers),
+	fx.Invoke(bindWorkflowMCPService),
 )
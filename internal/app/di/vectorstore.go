@@ -0,0 +1,46 @@
+package di
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/open-source-cloud/fuse/internal/app/config"
+	"github.com/open-source-cloud/fuse/internal/repositories/postgres"
+	"github.com/open-source-cloud/fuse/pkg/vectorstore"
+	"github.com/rs/zerolog/log"
+	"go.uber.org/fx"
+)
+
+// VectorStoreModule provides the vector store of ai/index and ai/retrieve, selected by
+// VECTOR_STORE_DRIVER.
+var VectorStoreModule = fx.Module(
+	"vectorstore",
+	fx.Provide(provideVectorStore),
+)
+
+type vectorStoreParams struct {
+	fx.In
+	Config *config.Config
+	Pool   *pgxpool.Pool `optional:"true"`
+}
+
+// provideVectorStore selects the vector backend. Unlike the secret store, the postgres driver does
+// not fall back to memory: an index that silently vanishes on restart is worse than a failed start.
+func provideVectorStore(p vectorStoreParams) (vectorstore.VectorStore, error) {
+	switch p.Config.LLM.VectorStoreDriver {
+	case "postgres":
+		if p.Pool == nil {
+			return nil, errors.New("VECTOR_STORE_DRIVER=postgres requires DB_DRIVER=postgres")
+		}
+		store := postgres.NewVectorStore(p.Pool)
+		if err := store.Available(context.Background()); err != nil {
+			return nil, err
+		}
+		log.Info().Msg("using postgres (pgvector) vector store")
+		return store, nil
+	default: // "memory"
+		log.Debug().Msg("using memory vector store")
+		return vectorstore.NewMemoryVectorStore(), nil
+	}
+}
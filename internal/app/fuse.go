@@ -40,6 +40,7 @@ func NewApp(
 	cronScheduler *actors.CronSchedulerFactory,
 	webhookRouter *actors.WebhookRouterFactory,
 	eventTrigger *actors.EventTriggerFactory,
+	retentionReaper *actors.RetentionReaperFactory,
 	tracingProvider *tracing.Provider,
 	_ PackagesReady,
 	readinessFlag *readiness.Flag,
@@ -62,6 +63,7 @@ func NewApp(
 		cronScheduler:        cronScheduler,
 		webhookRouter:        webhookRouter,
 		eventTrigger:         eventTrigger,
+		retentionReaper:      retentionReaper,
 		tracingProvider:      tracingProvider,
 		readinessFlag:        readinessFlag,
 	})
@@ -168,6 +170,7 @@ type Fuse struct {
 	cronScheduler        *actors.CronSchedulerFactory
 	webhookRouter        *actors.WebhookRouterFactory
 	eventTrigger         *actors.EventTriggerFactory
+	retentionReaper      *actors.RetentionReaperFactory
 	tracingProvider      *tracing.Provider
 	readinessFlag        *readiness.Flag
 	node                 gen.Node
@@ -214,6 +217,13 @@ func (app *Fuse) Load(node gen.Node, _ ...any) (gen.ApplicationSpec, error) {
 			Options: opts,
 		},
 	}
+	if app.config.Retention.Enabled {
+		group = append(group, gen.ApplicationMemberSpec{
+			Name:    actornames.RetentionReaperName,
+			Factory: app.retentionReaper.Factory,
+			Options: opts,
+		})
+	}
 	if app.config.HA.Enabled {
 		group = append(group, gen.ApplicationMemberSpec{
 			Name:    actornames.WorkflowClaimActorName,
@@ -0,0 +1,200 @@
+// Package audit describes the append-only record of administrative changes: who changed which
+// resource, when, through which request, and hashes of the resource before and after.
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/open-source-cloud/fuse/internal/auth"
+)
+
+// Actions recorded in the audit log.
+const (
+	ActionSchemaUpsert      Action = "schema.upsert"
+	ActionSchemaActivate    Action = "schema.activate"
+	ActionSchemaRollback    Action = "schema.rollback"
+	ActionPackageRegister   Action = "package.register"
+	ActionCredentialSave    Action = "credential.save"
+	ActionCredentialDelete  Action = "credential.delete"
+	ActionSecretSet         Action = "secret.set"
+	ActionSecretDelete      Action = "secret.delete"
+	ActionEnvironmentSave   Action = "environment.save"
+	ActionEnvironmentDelete Action = "environment.delete"
+	ActionVarSet            Action = "var.set"
+	ActionVarDelete         Action = "var.delete"
+	ActionWorkflowCancel    Action = "workflow.cancel"
+	ActionWorkflowRetry     Action = "workflow.retry"
+	ActionWorkflowRetryNode Action = "workflow.retry-node"
+	ActionSessionDelete     Action = "session.delete"
+	ActionMCPServerSave     Action = "mcp-server.save"
+	ActionMCPServerDelete   Action = "mcp-server.delete"
+)
+
+// Resource types recorded in the audit log.
+const (
+	ResourceSchema      = "schema"
+	ResourcePackage     = "package"
+	ResourceCredential  = "credential"
+	ResourceSecret      = "secret"
+	ResourceEnvironment = "environment"
+	ResourceVar         = "var"
+	ResourceWorkflow    = "workflow"
+	ResourceSession     = "session"
+	ResourceMCPServer   = "mcp-server"
+)
+
+// Actors recorded when a change has no authenticated principal.
+const (
+	// ActorAnonymous is an HTTP caller while authentication is disabled.
+	ActorAnonymous = "anonymous"
+	// ActorSystem is the engine itself, e.g. an automatic traffic-split rollback.
+	ActorSystem = "system"
+)
+
+// DefaultLimit and MaxLimit bound the entries a query returns.
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+type (
+	// Action is what was done, e.g. "schema.upsert".
+	Action string
+
+	// Entry is one administrative change. It never holds secret values: credentials and secrets
+	// are recorded by name, and their hashes cover metadata only.
+	Entry struct {
+		ID           string    `json:"id"`
+		Time         time.Time `json:"time"`
+		Actor        string    `json:"actor"`
+		AuthMethod   string    `json:"authMethod,omitempty"`
+		Action       Action    `json:"action"`
+		ResourceType string    `json:"resourceType"`
+		// Resource is the resource's ID within its namespace (schema ID, credential ID, ...).
+		Resource    string `json:"resource"`
+		Namespace   string `json:"namespace,omitempty"`
+		Environment string `json:"environment,omitempty"`
+		// BeforeHash and AfterHash are Hash values of the resource; empty when it did not exist
+		// before or no longer exists after.
+		BeforeHash string            `json:"beforeHash,omitempty"`
+		AfterHash  string            `json:"afterHash,omitempty"`
+		Details    map[string]string `json:"details,omitempty"`
+		Request    *Request          `json:"request,omitempty"`
+	}
+
+	// Request is the HTTP request that made a change; nil for CLI and engine changes.
+	Request struct {
+		Method     string `json:"method"`
+		Path       string `json:"path"`
+		RemoteAddr string `json:"remoteAddr,omitempty"`
+		UserAgent  string `json:"userAgent,omitempty"`
+		RequestID  string `json:"requestId,omitempty"`
+	}
+
+	// Filter selects audit entries. Zero fields match everything; results are newest first.
+	Filter struct {
+		Actor        string
+		Action       Action
+		ResourceType string
+		Resource     string
+		Namespace    string
+		Since        time.Time
+		Until        time.Time
+		// Before is an entry ID; only older entries are returned. Entry IDs are time-ordered, so
+		// the last ID of a page fetches the next one.
+		Before string
+		Limit  int
+	}
+
+	requestKey struct{}
+	actorKey   struct{}
+)
+
+// Matches reports whether e satisfies every set field of f except Limit.
+func (f Filter) Matches(e *Entry) bool {
+	return (f.Actor == "" || e.Actor == f.Actor) &&
+		(f.Action == "" || e.Action == f.Action) &&
+		(f.ResourceType == "" || e.ResourceType == f.ResourceType) &&
+		(f.Resource == "" || e.Resource == f.Resource) &&
+		(f.Namespace == "" || e.Namespace == f.Namespace) &&
+		(f.Since.IsZero() || !e.Time.Before(f.Since)) &&
+		(f.Until.IsZero() || e.Time.Before(f.Until)) &&
+		(f.Before == "" || e.ID < f.Before)
+}
+
+// EffectiveLimit returns Limit clamped to [1, MaxLimit], DefaultLimit when unset.
+func (f Filter) EffectiveLimit() int {
+	switch {
+	case f.Limit <= 0:
+		return DefaultLimit
+	case f.Limit > MaxLimit:
+		return MaxLimit
+	default:
+		return f.Limit
+	}
+}
+
+// WithRequest returns a copy of ctx carrying r's audit metadata.
+func WithRequest(ctx context.Context, r *http.Request) context.Context {
+	return context.WithValue(ctx, requestKey{}, &Request{
+		Method:     r.Method,
+		Path:       r.URL.Path,
+		RemoteAddr: r.RemoteAddr,
+		UserAgent:  r.UserAgent(),
+		RequestID:  r.Header.Get("X-Request-ID"),
+	})
+}
+
+// RequestFromContext returns the request stored by WithRequest, nil outside HTTP requests.
+func RequestFromContext(ctx context.Context) *Request {
+	req, _ := ctx.Value(requestKey{}).(*Request)
+	return req
+}
+
+// WithActor returns a copy of ctx naming the actor of changes made without a request principal,
+// e.g. "cli:alice".
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor and authentication method of ctx: the request principal,
+// else the actor set with WithActor, else ActorAnonymous inside an HTTP request and ActorSystem
+// outside one.
+func ActorFromContext(ctx context.Context) (actor, method string) {
+	if p := auth.PrincipalFromContext(ctx); p != nil {
+		return p.Subject, p.Method
+	}
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor, ""
+	}
+	if RequestFromContext(ctx) != nil {
+		return ActorAnonymous, ""
+	}
+	return ActorSystem, ""
+}
+
+// Middleware stores each request's audit metadata in its context.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(WithRequest(r.Context(), r)))
+	})
+}
+
+// Hash returns "sha256:<hex>" of v's JSON encoding, or "" for nil. Callers pass metadata only;
+// hashing a low-entropy secret would make it guessable.
+func Hash(v any) string {
+	if v == nil {
+		return ""
+	}
+	raw, err := json.Marshal(v)
+	if err != nil || string(raw) == "null" {
+		return ""
+	}
+	sum := sha256.Sum256(raw)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
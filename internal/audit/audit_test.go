@@ -0,0 +1,96 @@
+package audit_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/open-source-cloud/fuse/internal/audit"
+	"github.com/open-source-cloud/fuse/internal/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActorFromContext(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodPut, "/v1/schemas/orders", nil)
+	inRequest := audit.WithRequest(context.Background(), req)
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		wantActor  string
+		wantMethod string
+	}{
+		{name: "outside a request is the system", ctx: context.Background(), wantActor: audit.ActorSystem},
+		{name: "unauthenticated request is anonymous", ctx: inRequest, wantActor: audit.ActorAnonymous},
+		{name: "explicit actor", ctx: audit.WithActor(context.Background(), "cli:alice"), wantActor: "cli:alice"},
+		{
+			name:       "principal wins",
+			ctx:        auth.WithPrincipal(audit.WithActor(inRequest, "cli:alice"), &auth.Principal{Subject: "apikey:k1", Method: auth.MethodAPIKey}),
+			wantActor:  "apikey:k1",
+			wantMethod: auth.MethodAPIKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			actor, method := audit.ActorFromContext(tt.ctx)
+			assert.Equal(t, tt.wantActor, actor)
+			assert.Equal(t, tt.wantMethod, method)
+		})
+	}
+}
+
+func TestMiddleware_StoresRequestMetadata(t *testing.T) {
+	t.Parallel()
+
+	var got *audit.Request
+	handler := audit.Middleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got = audit.RequestFromContext(r.Context())
+	}))
+	req := httptest.NewRequest(http.MethodDelete, "/v1/credentials/stripe", nil)
+	req.Header.Set("User-Agent", "fuse-test")
+	req.Header.Set("X-Request-ID", "req-1")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	require.NotNil(t, got)
+	assert.Equal(t, http.MethodDelete, got.Method)
+	assert.Equal(t, "/v1/credentials/stripe", got.Path)
+	assert.Equal(t, "fuse-test", got.UserAgent)
+	assert.Equal(t, "req-1", got.RequestID)
+	assert.Nil(t, audit.RequestFromContext(context.Background()))
+}
+
+func TestHash(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, audit.Hash(nil))
+	h := audit.Hash(map[string]string{"a": "1"})
+	assert.True(t, strings.HasPrefix(h, "sha256:"))
+	assert.Equal(t, h, audit.Hash(map[string]string{"a": "1"}))
+	assert.NotEqual(t, h, audit.Hash(map[string]string{"a": "2"}))
+}
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	e := &audit.Entry{ID: "b", Time: now, Actor: "apikey:k1", Action: audit.ActionSchemaUpsert, ResourceType: audit.ResourceSchema, Resource: "orders", Namespace: "billing"}
+
+	assert.True(t, audit.Filter{}.Matches(e))
+	assert.True(t, audit.Filter{Actor: "apikey:k1", Action: audit.ActionSchemaUpsert, Namespace: "billing", Before: "c"}.Matches(e))
+	assert.False(t, audit.Filter{Resource: "invoices"}.Matches(e))
+	assert.False(t, audit.Filter{Before: "b"}.Matches(e))
+	assert.False(t, audit.Filter{Since: now.Add(time.Second)}.Matches(e))
+	assert.False(t, audit.Filter{Until: now}.Matches(e))
+
+	assert.Equal(t, audit.DefaultLimit, audit.Filter{}.EffectiveLimit())
+	assert.Equal(t, audit.MaxLimit, audit.Filter{Limit: audit.MaxLimit + 1}.EffectiveLimit())
+	assert.Equal(t, 5, audit.Filter{Limit: 5}.EffectiveLimit())
+}
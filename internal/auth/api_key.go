@@ -0,0 +1,121 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// APIKeyPrefix starts every API key, so keys are recognisable in configs and secret scanners.
+const APIKeyPrefix = "fuse_"
+
+const (
+	apiKeyIDBytes     = 6
+	apiKeySecretBytes = 32
+)
+
+type (
+	// APIKey is the stored form of an API key. Only a SHA-256 hash of the key is kept: keys carry
+	// 256 random bits, so a fast hash is enough and lookups stay cheap on every request.
+	APIKey struct {
+		ID        string     `json:"id"`
+		Name      string     `json:"name"`
+		Hash      string     `json:"-"`
+		CreatedAt time.Time  `json:"createdAt"`
+		ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+		RevokedAt *time.Time `json:"revokedAt,omitempty"`
+	}
+
+	// APIKeyLookup finds stored API keys by ID.
+	APIKeyLookup interface {
+		FindByID(id string) (*APIKey, error)
+	}
+
+	// APIKeyAuthenticator authenticates API keys against their stored hashes.
+	APIKeyAuthenticator struct {
+		keys APIKeyLookup
+		now  func() time.Time
+	}
+)
+
+// NewAPIKey generates a new API key. The plaintext key (fuse_<id>_<secret>) is returned once and
+// only its hash is stored.
+func NewAPIKey(name string, expiresAt *time.Time) (*APIKey, string, error) {
+	id := make([]byte, apiKeyIDBytes)
+	secret := make([]byte, apiKeySecretBytes)
+	if _, err := rand.Read(id); err != nil {
+		return nil, "", fmt.Errorf("generate api key id: %w", err)
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", fmt.Errorf("generate api key secret: %w", err)
+	}
+	key := &APIKey{
+		ID:        hex.EncodeToString(id),
+		Name:      name,
+		CreatedAt: time.Now().UTC(),
+		ExpiresAt: expiresAt,
+	}
+	plaintext := APIKeyPrefix + key.ID + "_" + base64.RawURLEncoding.EncodeToString(secret)
+	key.Hash = HashAPIKey(plaintext)
+	return key, plaintext, nil
+}
+
+// HashAPIKey returns the hex SHA-256 hash of a plaintext API key.
+func HashAPIKey(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
+
+// Active reports whether the key is neither revoked nor expired at now.
+func (k *APIKey) Active(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
+
+// NewAPIKeyAuthenticator returns an authenticator backed by keys.
+func NewAPIKeyAuthenticator(keys APIKeyLookup) *APIKeyAuthenticator {
+	return &APIKeyAuthenticator{keys: keys, now: time.Now}
+}
+
+// AuthenticateKey verifies a plaintext API key.
+func (a *APIKeyAuthenticator) AuthenticateKey(plaintext string) (*Principal, error) {
+	id, ok := apiKeyID(plaintext)
+	if !ok {
+		return nil, fmt.Errorf("%w: malformed api key", ErrInvalidCredentials)
+	}
+	key, err := a.keys.FindByID(id)
+	if err != nil || key == nil {
+		return nil, fmt.Errorf("%w: unknown api key %s", ErrInvalidCredentials, id)
+	}
+	if subtle.ConstantTimeCompare([]byte(key.Hash), []byte(HashAPIKey(plaintext))) != 1 {
+		return nil, fmt.Errorf("%w: api key %s does not match", ErrInvalidCredentials, id)
+	}
+	if !key.Active(a.now()) {
+		return nil, fmt.Errorf("%w: api key %s is revoked or expired", ErrInvalidCredentials, id)
+	}
+	return &Principal{
+		Subject: "apikey:" + key.ID,
+		Method:  MethodAPIKey,
+		Name:    key.Name,
+	}, nil
+}
+
+// apiKeyID extracts the ID segment of fuse_<id>_<secret>.
+func apiKeyID(plaintext string) (string, bool) {
+	rest, ok := strings.CutPrefix(plaintext, APIKeyPrefix)
+	if !ok {
+		return "", false
+	}
+	id, secret, ok := strings.Cut(rest, "_")
+	if !ok || len(id) != 2*apiKeyIDBytes || secret == "" {
+		return "", false
+	}
+	return id, true
+}
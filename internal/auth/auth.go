@@ -0,0 +1,93 @@
+// Package auth authenticates REST API callers with API keys or JWT bearer tokens.
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Authentication methods recorded on a Principal.
+const (
+	MethodAPIKey = "api_key"
+	MethodJWT    = "jwt"
+)
+
+var (
+	// ErrMissingCredentials is returned when a request carries neither an API key nor a bearer token
+	ErrMissingCredentials = errors.New("missing credentials")
+	// ErrInvalidCredentials is returned when the presented API key or token is unknown, expired or forged
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+type (
+	// Principal is the authenticated caller of a request.
+	Principal struct {
+		// Subject identifies the caller: "apikey:<id>" for API keys, the `sub` claim for JWTs.
+		Subject string `json:"subject"`
+		// Method is MethodAPIKey or MethodJWT.
+		Method string `json:"method"`
+		// Name is the API key name or, for JWTs, the `name` claim when present.
+		Name string `json:"name,omitempty"`
+		// Claims holds the verified JWT claims; nil for API keys.
+		Claims map[string]any `json:"-"`
+	}
+
+	// Authenticator authenticates the caller of an HTTP request.
+	Authenticator interface {
+		Authenticate(r *http.Request) (*Principal, error)
+	}
+
+	principalKey struct{}
+)
+
+// WithPrincipal returns a copy of ctx carrying the authenticated principal.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the principal stored by the authentication middleware, nil when
+// the route is public or authentication is disabled.
+func PrincipalFromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(principalKey{}).(*Principal)
+	return p
+}
+
+// bearerToken extracts the token of an `Authorization: Bearer <token>` header, falling back to
+// the X-API-Key header.
+func bearerToken(r *http.Request) string {
+	if header := r.Header.Get("Authorization"); header != "" {
+		scheme, token, ok := strings.Cut(header, " ")
+		if ok && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+		return ""
+	}
+	return strings.TrimSpace(r.Header.Get("X-API-Key"))
+}
+
+// Chain authenticates API keys (recognised by their prefix) with the API key authenticator and
+// every other bearer token with the JWT authenticator. Either may be nil to disable that method.
+type Chain struct {
+	APIKeys *APIKeyAuthenticator
+	JWT     *JWTAuthenticator
+}
+
+// Authenticate implements Authenticator.
+func (c *Chain) Authenticate(r *http.Request) (*Principal, error) {
+	token := bearerToken(r)
+	if token == "" {
+		return nil, ErrMissingCredentials
+	}
+	if strings.HasPrefix(token, APIKeyPrefix) {
+		if c.APIKeys == nil {
+			return nil, ErrInvalidCredentials
+		}
+		return c.APIKeys.AuthenticateKey(token)
+	}
+	if c.JWT == nil {
+		return nil, ErrInvalidCredentials
+	}
+	return c.JWT.AuthenticateToken(token)
+}
@@ -0,0 +1,250 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// jwksMinRefetch limits how often the key set is refetched, successfully or not, so tokens with
+	// random kids or an unreachable identity provider cannot turn every request into a fetch.
+	jwksMinRefetch = 30 * time.Second
+	// jwksLoadTimeout bounds a refetch triggered by a request.
+	jwksLoadTimeout = 10 * time.Second
+)
+
+type (
+	// JWKS is a cached JSON Web Key Set loaded from a local file or an HTTPS endpoint (typically an
+	// OIDC provider's jwks_uri). Keys are reloaded every refresh interval and whenever a token
+	// references an unknown key ID, so provider key rotation needs no restart.
+	JWKS struct {
+		source  string
+		load    func(ctx context.Context) ([]byte, error)
+		refresh time.Duration
+
+		// reloads collapses concurrent refetches into a single load.
+		reloads singleflight.Group
+
+		mu          sync.RWMutex
+		keys        map[string]crypto.PublicKey
+		fetchedAt   time.Time
+		attemptedAt time.Time
+	}
+
+	jsonWebKeySet struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+
+	jsonWebKey struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Use string `json:"use"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+		Crv string `json:"crv"`
+		X   string `json:"x"`
+		Y   string `json:"y"`
+	}
+)
+
+// NewJWKSFromFile returns a key set read from a local JSON file.
+func NewJWKSFromFile(path string, refresh time.Duration) *JWKS {
+	return &JWKS{
+		source:  path,
+		refresh: refresh,
+		load: func(_ context.Context) ([]byte, error) {
+			return os.ReadFile(path)
+		},
+	}
+}
+
+// NewJWKSFromURL returns a key set fetched from url with client.
+func NewJWKSFromURL(url string, client *http.Client, refresh time.Duration) *JWKS {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &JWKS{
+		source:  url,
+		refresh: refresh,
+		load: func(ctx context.Context) ([]byte, error) {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+			if err != nil {
+				return nil, err
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				return nil, err
+			}
+			defer func() { _ = resp.Body.Close() }()
+			if resp.StatusCode != http.StatusOK {
+				return nil, fmt.Errorf("unexpected status %s", resp.Status)
+			}
+			return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		},
+	}
+}
+
+// Load fetches the key set, replacing the cached keys. A failed load keeps the cached keys.
+func (j *JWKS) Load(ctx context.Context) error {
+	keys, err := j.fetch(ctx)
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.attemptedAt = time.Now()
+	if err != nil {
+		return err
+	}
+	j.keys = keys
+	j.fetchedAt = j.attemptedAt
+	return nil
+}
+
+func (j *JWKS) fetch(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	raw, err := j.load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load jwks from %s: %w", j.source, err)
+	}
+	keys, err := parseJWKS(raw)
+	if err != nil {
+		return nil, fmt.Errorf("parse jwks from %s: %w", j.source, err)
+	}
+	return keys, nil
+}
+
+// Key returns the verification key with the given ID. An empty kid matches the only key of a
+// single-key set.
+func (j *JWKS) Key(kid string) (crypto.PublicKey, error) {
+	j.mu.RLock()
+	key, found := j.lookup(kid)
+	stale := j.refresh > 0 && time.Since(j.fetchedAt) > j.refresh
+	recentlyAttempted := time.Since(j.attemptedAt) < jwksMinRefetch
+	j.mu.RUnlock()
+
+	if found && (!stale || recentlyAttempted) {
+		return key, nil
+	}
+	if recentlyAttempted {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	if err := j.reload(); err != nil {
+		log.Warn().Err(err).Msg("failed to refresh jwks")
+		if found {
+			return key, nil
+		}
+		return nil, err
+	}
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	if key, found = j.lookup(kid); found {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// reload refetches the key set on behalf of a request; concurrent callers share one load.
+func (j *JWKS) reload() error {
+	_, err, _ := j.reloads.Do("jwks", func() (any, error) {
+		j.mu.RLock()
+		recentlyAttempted := time.Since(j.attemptedAt) < jwksMinRefetch
+		j.mu.RUnlock()
+		if recentlyAttempted {
+			// another caller's load finished between our check and this one
+			return nil, nil
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), jwksLoadTimeout)
+		defer cancel()
+		return nil, j.Load(ctx)
+	})
+	return err
+}
+
+func (j *JWKS) lookup(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(j.keys) == 1 {
+		for _, key := range j.keys {
+			return key, true
+		}
+	}
+	key, ok := j.keys[kid]
+	return key, ok
+}
+
+func parseJWKS(raw []byte) (map[string]crypto.PublicKey, error) {
+	var set jsonWebKeySet
+	if err := json.Unmarshal(raw, &set); err != nil {
+		return nil, err
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", jwk.Kid, err)
+		}
+		keys[jwk.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no signing keys")
+	}
+	return keys, nil
+}
+
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus: %w", err)
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil || !e.IsInt64() {
+			return nil, fmt.Errorf("invalid exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, errX := base64.RawURLEncoding.DecodeString(k.X)
+		y, errY := base64.RawURLEncoding.DecodeString(k.Y)
+		size := (curve.Params().BitSize + 7) / 8
+		if errX != nil || errY != nil || len(x) != size || len(y) != size {
+			return nil, fmt.Errorf("invalid curve point")
+		}
+		// uncompressed SEC 1 point: 0x04 || X || Y; parsing checks the point is on the curve
+		return ecdsa.ParseUncompressedPublicKey(curve, append(append([]byte{4}, x...), y...))
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(raw), nil
+}
@@ -0,0 +1,206 @@
+package auth_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/open-source-cloud/fuse/internal/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testIssuer   = "https://idp.example.com"
+	testAudience = "fuse-api"
+)
+
+func b64(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+
+func rsaJWK(t *testing.T, kid string, key *rsa.PrivateKey) map[string]any {
+	t.Helper()
+	return map[string]any{
+		"kty": "RSA", "kid": kid, "use": "sig",
+		"n": b64(key.N.Bytes()),
+		"e": b64(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func ecJWK(t *testing.T, kid string, key *ecdsa.PrivateKey) map[string]any {
+	t.Helper()
+	raw, err := key.PublicKey.Bytes()
+	require.NoError(t, err)
+	return map[string]any{"kty": "EC", "kid": kid, "crv": "P-256", "x": b64(raw[1:33]), "y": b64(raw[33:])}
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]any) string {
+	t.Helper()
+	input := encodeJWT(t, map[string]any{"alg": "RS256", "kid": kid, "typ": "JWT"}, claims)
+	digest := sha256.Sum256([]byte(input))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	return input + "." + b64(sig)
+}
+
+func signES256(t *testing.T, key *ecdsa.PrivateKey, kid string, claims map[string]any) string {
+	t.Helper()
+	input := encodeJWT(t, map[string]any{"alg": "ES256", "kid": kid}, claims)
+	digest := sha256.Sum256([]byte(input))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+	return input + "." + b64(sig)
+}
+
+func encodeJWT(t *testing.T, header, claims map[string]any) string {
+	t.Helper()
+	h, err := json.Marshal(header)
+	require.NoError(t, err)
+	c, err := json.Marshal(claims)
+	require.NoError(t, err)
+	return b64(h) + "." + b64(c)
+}
+
+func validClaims() map[string]any {
+	return map[string]any{
+		"sub":  "user-42",
+		"name": "Ada",
+		"iss":  testIssuer,
+		"aud":  []string{"other", testAudience},
+		"exp":  time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+// jwksServer stands in for an OIDC provider's jwks_uri and counts fetches.
+func jwksServer(t *testing.T, keys ...map[string]any) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var fetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fetches.Add(1)
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": keys})
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &fetches
+}
+
+func newJWTAuthenticator(url string) *auth.JWTAuthenticator {
+	return auth.NewJWTAuthenticator(auth.JWTConfig{
+		Issuer:    testIssuer,
+		Audience:  testAudience,
+		ClockSkew: time.Second,
+	}, auth.NewJWKSFromURL(url, nil, time.Hour))
+}
+
+func TestJWTAuthenticator_ValidTokens(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	srv, fetches := jwksServer(t, rsaJWK(t, "rsa-1", rsaKey), ecJWK(t, "ec-1", ecKey))
+	authn := newJWTAuthenticator(srv.URL)
+
+	principal, err := authn.AuthenticateToken(signRS256(t, rsaKey, "rsa-1", validClaims()))
+	require.NoError(t, err)
+	assert.Equal(t, "user-42", principal.Subject)
+	assert.Equal(t, "Ada", principal.Name)
+	assert.Equal(t, auth.MethodJWT, principal.Method)
+
+	_, err = authn.AuthenticateToken(signES256(t, ecKey, "ec-1", validClaims()))
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), fetches.Load(), "key set is cached between tokens")
+}
+
+func TestJWTAuthenticator_RejectsInvalidTokens(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	srv, _ := jwksServer(t, rsaJWK(t, "rsa-1", rsaKey))
+	authn := newJWTAuthenticator(srv.URL)
+
+	with := func(key, value string) map[string]any {
+		claims := validClaims()
+		if value == "" {
+			delete(claims, key)
+		} else {
+			claims[key] = value
+		}
+		return claims
+	}
+	expired := validClaims()
+	expired["exp"] = time.Now().Add(-time.Minute).Unix()
+	notYet := validClaims()
+	notYet["nbf"] = time.Now().Add(time.Minute).Unix()
+
+	tests := map[string]string{
+		"forged signature": signRS256(t, otherKey, "rsa-1", validClaims()),
+		"unknown key":      signRS256(t, rsaKey, "rsa-2", validClaims()),
+		"expired":          signRS256(t, rsaKey, "rsa-1", expired),
+		"not yet valid":    signRS256(t, rsaKey, "rsa-1", notYet),
+		"wrong issuer":     signRS256(t, rsaKey, "rsa-1", with("iss", "https://evil.example.com")),
+		"wrong audience":   signRS256(t, rsaKey, "rsa-1", with("aud", "other")),
+		"missing subject":  signRS256(t, rsaKey, "rsa-1", with("sub", "")),
+		"alg none":         encodeJWT(t, map[string]any{"alg": "none", "kid": "rsa-1"}, validClaims()) + ".",
+		"malformed":        "not-a-jwt",
+	}
+	for name, token := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := authn.AuthenticateToken(token)
+			assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+		})
+	}
+}
+
+func TestJWKS_FromFile(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	raw, err := json.Marshal(map[string]any{"keys": []any{rsaJWK(t, "file-1", rsaKey)}})
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, raw, 0o600))
+
+	authn := auth.NewJWTAuthenticator(auth.JWTConfig{}, auth.NewJWKSFromFile(path, 0))
+	_, err = authn.AuthenticateToken(signRS256(t, rsaKey, "file-1", validClaims()))
+	assert.NoError(t, err)
+}
+
+func TestJWKS_FailedRefetchIsRateLimited(t *testing.T) {
+	var fetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fetches.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(srv.Close)
+	jwks := auth.NewJWKSFromURL(srv.URL, nil, time.Hour)
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := jwks.Key("rsa-1")
+			assert.Error(t, err)
+		}()
+	}
+	wg.Wait()
+	_, err := jwks.Key("rsa-1")
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), fetches.Load(), "concurrent and later requests do not refetch a failing key set")
+}
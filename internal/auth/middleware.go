@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Unauthorized is the error code returned for unauthenticated requests
+const Unauthorized = "UNAUTHORIZED"
+
+// Middleware wraps next so requests must authenticate with authn; the principal and authz are
+// stored in the request context for Check. CORS preflight requests pass through since browsers
+// never send credentials on them. A nil authn disables authentication, a nil authz leaves every
+// authenticated principal unrestricted.
+func Middleware(authn Authenticator, authz Authorizer, next http.Handler) http.Handler {
+	if authn == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+		principal, err := authn.Authenticate(r)
+		if err != nil {
+			if !errors.Is(err, ErrMissingCredentials) {
+				log.Debug().Err(err).Str("path", r.URL.Path).Str("remoteAddr", r.RemoteAddr).Msg("rejected request credentials")
+			}
+			sendUnauthorized(w, err)
+			return
+		}
+		ctx := WithPrincipal(r.Context(), principal)
+		if authz != nil {
+			ctx = WithAuthorizer(ctx, authz)
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func sendUnauthorized(w http.ResponseWriter, err error) {
+	message := ErrInvalidCredentials.Error()
+	if errors.Is(err, ErrMissingCredentials) {
+		message = ErrMissingCredentials.Error()
+	}
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("WWW-Authenticate", `Bearer realm="fuse"`)
+	w.WriteHeader(http.StatusUnauthorized)
+	// same shape as dtos.ErrorResponse, which cannot be imported here since dtos renders auth types
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"message": message,
+		"code":    Unauthorized,
+		"fields":  []string{"Authorization"},
+	})
+}
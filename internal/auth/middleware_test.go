@@ -0,0 +1,76 @@
+package auth_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/open-source-cloud/fuse/internal/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type keyStore map[string]*auth.APIKey
+
+func (s keyStore) FindByID(id string) (*auth.APIKey, error) {
+	return s[id], nil
+}
+
+func TestMiddleware(t *testing.T) {
+	key, plaintext, err := auth.NewAPIKey("ci", nil)
+	require.NoError(t, err)
+	chain := &auth.Chain{APIKeys: auth.NewAPIKeyAuthenticator(keyStore{key.ID: key})}
+
+	var seen *auth.Principal
+	handler := auth.Middleware(chain, nil, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = auth.PrincipalFromContext(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tests := []struct {
+		name       string
+		method     string
+		headers    map[string]string
+		wantStatus int
+	}{
+		{name: "bearer api key", headers: map[string]string{"Authorization": "Bearer " + plaintext}, wantStatus: http.StatusNoContent},
+		{name: "x-api-key header", headers: map[string]string{"X-API-Key": plaintext}, wantStatus: http.StatusNoContent},
+		{name: "missing credentials", wantStatus: http.StatusUnauthorized},
+		{name: "wrong key", headers: map[string]string{"Authorization": "Bearer " + plaintext + "x"}, wantStatus: http.StatusUnauthorized},
+		{name: "jwt without jwks configured", headers: map[string]string{"Authorization": "Bearer a.b.c"}, wantStatus: http.StatusUnauthorized},
+		{name: "basic scheme", headers: map[string]string{"Authorization": "Basic Zm9vOmJhcg=="}, wantStatus: http.StatusUnauthorized},
+		{name: "cors preflight", method: http.MethodOptions, wantStatus: http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen = nil
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req := httptest.NewRequest(method, "/v1/schemas", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if rec.Code == http.StatusUnauthorized {
+				assert.Contains(t, rec.Body.String(), auth.Unauthorized)
+				assert.NotEmpty(t, rec.Header().Get("WWW-Authenticate"))
+			}
+			if tt.wantStatus == http.StatusNoContent && method != http.MethodOptions {
+				require.NotNil(t, seen)
+				assert.Equal(t, "apikey:"+key.ID, seen.Subject)
+			}
+		})
+	}
+}
+
+func TestMiddleware_NilAuthenticatorDisablesAuth(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+	rec := httptest.NewRecorder()
+	auth.Middleware(nil, nil, next).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,278 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+)
+
+// Permissions granted by role rules. PermAdmin covers API keys, roles, role bindings, the audit
+// log and environment and namespace changes.
+const (
+	PermSchemaRead      Permission = "schema:read"
+	PermSchemaWrite     Permission = "schema:write"
+	PermWorkflowRead    Permission = "workflow:read"
+	PermWorkflowTrigger Permission = "workflow:trigger"
+	PermWorkflowCancel  Permission = "workflow:cancel"
+	PermCredentialWrite Permission = "credential:write"
+	PermSecretReadNames Permission = "secret:read-names"
+	PermVarRead         Permission = "var:read"
+	PermVarWrite        Permission = "var:write"
+	PermPackageRead     Permission = "package:read"
+	PermPackageRegister Permission = "package:register"
+	PermSessionRead     Permission = "session:read"
+	PermSessionDelete   Permission = "session:delete"
+	PermMCPServerRead   Permission = "mcp-server:read"
+	PermMCPServerWrite  Permission = "mcp-server:write"
+	PermEnvironmentRead Permission = "environment:read"
+	PermNamespaceRead   Permission = "namespace:read"
+	PermAdmin           Permission = "admin"
+	// PermAll in a rule grants every permission.
+	PermAll Permission = "*"
+)
+
+// AdminRole is the name of the built-in role granting every permission on every resource.
+const AdminRole = "admin"
+
+const roleNameMaxLength = 64
+
+var (
+	// ErrForbidden is returned when an authenticated principal lacks a permission on a resource.
+	ErrForbidden = errors.New("forbidden")
+	// ErrInvalidRole is returned when a role fails validation.
+	ErrInvalidRole = errors.New("invalid role")
+
+	// Permissions lists every grantable permission.
+	Permissions = []Permission{
+		PermSchemaRead,
+		PermSchemaWrite,
+		PermWorkflowRead,
+		PermWorkflowTrigger,
+		PermWorkflowCancel,
+		PermCredentialWrite,
+		PermSecretReadNames,
+		PermVarRead,
+		PermVarWrite,
+		PermPackageRead,
+		PermPackageRegister,
+		PermSessionRead,
+		PermSessionDelete,
+		PermMCPServerRead,
+		PermMCPServerWrite,
+		PermEnvironmentRead,
+		PermNamespaceRead,
+		PermAdmin,
+	}
+)
+
+type (
+	// Permission is an action a role may grant, e.g. "workflow:trigger".
+	Permission string
+
+	// Resource is what a permission is checked against. Empty fields are not part of the resource
+	// (a package has no environment, a credential has no schema) and only match rules without
+	// patterns for them. SchemaID is unqualified; the schema's namespace is in Namespace.
+	Resource struct {
+		Namespace   string
+		SchemaID    string
+		Environment string
+	}
+
+	// Rule grants permissions on the namespaces, schemas and environments matching its glob
+	// patterns (path.Match syntax, e.g. "billing-*"). An empty pattern list matches everything.
+	Rule struct {
+		Permissions  []Permission `json:"permissions"`
+		Namespaces   []string     `json:"namespaces,omitempty"`
+		Schemas      []string     `json:"schemas,omitempty"`
+		Environments []string     `json:"environments,omitempty"`
+	}
+
+	// Role is a named set of rules.
+	Role struct {
+		Name        string    `json:"name"`
+		Description string    `json:"description,omitempty"`
+		Rules       []Rule    `json:"rules"`
+		CreatedAt   time.Time `json:"createdAt"`
+		UpdatedAt   time.Time `json:"updatedAt"`
+	}
+
+	// RoleBinding grants a role to a principal subject ("apikey:<id>" or a JWT sub).
+	RoleBinding struct {
+		Subject   string    `json:"subject"`
+		Role      string    `json:"role"`
+		CreatedAt time.Time `json:"createdAt"`
+	}
+
+	// PolicyLookup reads the roles bound to a subject.
+	PolicyLookup interface {
+		FindRole(name string) (*Role, error)
+		FindBindingsBySubject(subject string) ([]*RoleBinding, error)
+	}
+
+	// Authorizer decides whether a principal may perform an action on a resource.
+	Authorizer interface {
+		Authorize(principal *Principal, perm Permission, resource Resource) error
+	}
+
+	// RBAC authorizes principals through their role bindings. Subjects listed as admins hold the
+	// built-in admin role without a stored binding, which bootstraps the first administrator.
+	RBAC struct {
+		policies PolicyLookup
+		admins   []string
+	}
+
+	authorizerContextKey struct{}
+)
+
+// BuiltinAdminRole returns the built-in role granting every permission on every resource.
+func BuiltinAdminRole() *Role {
+	return &Role{
+		Name:        AdminRole,
+		Description: "Built-in role granting every permission",
+		Rules:       []Rule{{Permissions: []Permission{PermAll}}},
+	}
+}
+
+// Validate checks the role name, permissions and patterns.
+func (r *Role) Validate() error {
+	if r.Name == "" || len(r.Name) > roleNameMaxLength || strings.ContainsAny(r.Name, " /") {
+		return fmt.Errorf("%w: name must be 1-%d characters without spaces or slashes", ErrInvalidRole, roleNameMaxLength)
+	}
+	if len(r.Rules) == 0 {
+		return fmt.Errorf("%w: at least one rule is required", ErrInvalidRole)
+	}
+	for i, rule := range r.Rules {
+		if len(rule.Permissions) == 0 {
+			return fmt.Errorf("%w: rule %d grants no permissions", ErrInvalidRole, i)
+		}
+		for _, perm := range rule.Permissions {
+			if perm != PermAll && !slices.Contains(Permissions, perm) {
+				return fmt.Errorf("%w: rule %d: unknown permission %q", ErrInvalidRole, i, perm)
+			}
+		}
+		for _, pattern := range slices.Concat(rule.Namespaces, rule.Schemas, rule.Environments) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("%w: rule %d: bad pattern %q", ErrInvalidRole, i, pattern)
+			}
+		}
+	}
+	return nil
+}
+
+// Allows reports whether any rule of the role grants perm on resource.
+func (r *Role) Allows(perm Permission, resource Resource) bool {
+	for _, rule := range r.Rules {
+		if rule.allows(perm, resource) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Rule) allows(perm Permission, resource Resource) bool {
+	if !slices.Contains(r.Permissions, perm) && !slices.Contains(r.Permissions, PermAll) {
+		return false
+	}
+	return matchesAny(r.Namespaces, resource.Namespace) &&
+		matchesAny(r.Schemas, resource.SchemaID) &&
+		matchesAny(r.Environments, resource.Environment)
+}
+
+// matchesAny reports whether value matches one of the patterns. A resource without the field
+// only matches rules that do not restrict it, so a scoped rule never grants more than its scope.
+func matchesAny(patterns []string, value string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	if value == "" {
+		return false
+	}
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// NewRBAC returns an authorizer reading bindings from policies; admins are subjects holding the
+// built-in admin role.
+func NewRBAC(policies PolicyLookup, admins []string) *RBAC {
+	return &RBAC{policies: policies, admins: admins}
+}
+
+// Authorize returns nil when one of the principal's roles grants perm on resource, ErrForbidden
+// when none does, or the lookup error.
+func (a *RBAC) Authorize(principal *Principal, perm Permission, resource Resource) error {
+	if slices.Contains(a.admins, principal.Subject) {
+		return nil
+	}
+	bindings, err := a.policies.FindBindingsBySubject(principal.Subject)
+	if err != nil {
+		return fmt.Errorf("find role bindings of %s: %w", principal.Subject, err)
+	}
+	for _, binding := range bindings {
+		role := BuiltinAdminRole()
+		if binding.Role != AdminRole {
+			if role, err = a.policies.FindRole(binding.Role); err != nil {
+				return fmt.Errorf("find role %s: %w", binding.Role, err)
+			}
+		}
+		if role.Allows(perm, resource) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s lacks %s on %s", ErrForbidden, principal.Subject, perm, resource)
+}
+
+// NewSchemaResource returns the resource of a schema given its namespace-qualified ID.
+func NewSchemaResource(schemaID, environment string) Resource {
+	namespace, local := workflow.SplitQualifiedID(schemaID)
+	return Resource{Namespace: namespace, SchemaID: local, Environment: environment}
+}
+
+// String renders the resource for error messages.
+func (r Resource) String() string {
+	parts := make([]string, 0, 3)
+	if r.SchemaID != "" {
+		parts = append(parts, "schema "+r.SchemaID)
+	}
+	if r.Environment != "" {
+		parts = append(parts, "environment "+r.Environment)
+	}
+	if r.Namespace != "" {
+		parts = append(parts, "namespace "+r.Namespace)
+	}
+	if len(parts) == 0 {
+		return "any resource"
+	}
+	return strings.Join(parts, " in ")
+}
+
+// WithAuthorizer returns a copy of ctx carrying the authorizer for Check.
+func WithAuthorizer(ctx context.Context, authz Authorizer) context.Context {
+	return context.WithValue(ctx, authorizerContextKey{}, authz)
+}
+
+// Enforced reports whether Check can deny requests made with ctx: the request was authenticated
+// and an authorizer is configured.
+func Enforced(ctx context.Context) bool {
+	authz, _ := ctx.Value(authorizerContextKey{}).(Authorizer)
+	return authz != nil && PrincipalFromContext(ctx) != nil
+}
+
+// Check authorizes the request principal for perm on resource. Requests without a principal
+// (authentication disabled or a public route) and servers without an authorizer are allowed.
+func Check(ctx context.Context, perm Permission, resource Resource) error {
+	if !Enforced(ctx) {
+		return nil
+	}
+	authz, _ := ctx.Value(authorizerContextKey{}).(Authorizer)
+	return authz.Authorize(PrincipalFromContext(ctx), perm, resource)
+}
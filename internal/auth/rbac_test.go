@@ -0,0 +1,81 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/open-source-cloud/fuse/internal/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type policyStore struct {
+	roles    map[string]*auth.Role
+	bindings map[string][]*auth.RoleBinding
+}
+
+func (s policyStore) FindRole(name string) (*auth.Role, error) { return s.roles[name], nil }
+
+func (s policyStore) FindBindingsBySubject(subject string) ([]*auth.RoleBinding, error) {
+	return s.bindings[subject], nil
+}
+
+func TestRole_Allows(t *testing.T) {
+	role := &auth.Role{Name: "billing", Rules: []auth.Rule{
+		{Permissions: []auth.Permission{auth.PermWorkflowTrigger}, Schemas: []string{"billing-*"}, Environments: []string{"staging", "dev-*"}},
+		{Permissions: []auth.Permission{auth.PermSecretReadNames}},
+		{Permissions: []auth.Permission{auth.PermSchemaWrite}, Namespaces: []string{"billing"}},
+	}}
+	require.NoError(t, role.Validate())
+
+	tests := []struct {
+		name     string
+		perm     auth.Permission
+		resource auth.Resource
+		want     bool
+	}{
+		{"matching schema and environment", auth.PermWorkflowTrigger, auth.Resource{SchemaID: "billing-invoices", Environment: "staging"}, true},
+		{"environment glob", auth.PermWorkflowTrigger, auth.Resource{SchemaID: "billing-invoices", Environment: "dev-alice"}, true},
+		{"other environment", auth.PermWorkflowTrigger, auth.Resource{SchemaID: "billing-invoices", Environment: "prod"}, false},
+		{"other schema", auth.PermWorkflowTrigger, auth.Resource{SchemaID: "shipping", Environment: "staging"}, false},
+		{"permission not granted", auth.PermWorkflowCancel, auth.Resource{SchemaID: "billing-invoices", Environment: "staging"}, false},
+		{"unscoped rule", auth.PermSecretReadNames, auth.Resource{Environment: "prod"}, true},
+		{"resource without schema", auth.PermWorkflowTrigger, auth.Resource{Environment: "staging"}, false},
+		{"resource without namespace", auth.PermSchemaWrite, auth.Resource{SchemaID: "invoices"}, false},
+		{"matching namespace", auth.PermSchemaWrite, auth.NewSchemaResource("billing:invoices", ""), true},
+		{"other namespace", auth.PermSchemaWrite, auth.NewSchemaResource("shipping:invoices", ""), false},
+		{"default namespace", auth.PermSchemaWrite, auth.NewSchemaResource("invoices", ""), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, role.Allows(tt.perm, tt.resource))
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	store := policyStore{
+		roles: map[string]*auth.Role{"deployer": {Name: "deployer", Rules: []auth.Rule{
+			{Permissions: []auth.Permission{auth.PermSchemaWrite}, Schemas: []string{"team-a-*"}},
+		}}},
+		bindings: map[string][]*auth.RoleBinding{
+			"apikey:a":  {{Subject: "apikey:a", Role: "deployer"}},
+			"ops-admin": {{Subject: "ops-admin", Role: auth.AdminRole}},
+		},
+	}
+	rbac := auth.NewRBAC(store, []string{"root"})
+	ctxFor := func(subject string) context.Context {
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: subject})
+		return auth.WithAuthorizer(ctx, rbac)
+	}
+	res := auth.Resource{SchemaID: "team-a-orders"}
+
+	assert.NoError(t, auth.Check(ctxFor("apikey:a"), auth.PermSchemaWrite, res))
+	assert.ErrorIs(t, auth.Check(ctxFor("apikey:a"), auth.PermSchemaWrite, auth.Resource{SchemaID: "team-b-orders"}), auth.ErrForbidden)
+	assert.ErrorIs(t, auth.Check(ctxFor("nobody"), auth.PermSchemaWrite, res), auth.ErrForbidden)
+	assert.NoError(t, auth.Check(ctxFor("ops-admin"), auth.PermAdmin, auth.Resource{}))
+	assert.NoError(t, auth.Check(ctxFor("root"), auth.PermCredentialWrite, auth.Resource{Environment: "prod"}))
+
+	assert.False(t, auth.Enforced(context.Background()))
+	assert.NoError(t, auth.Check(context.Background(), auth.PermSchemaWrite, res), "requests without a principal are not authorized")
+}
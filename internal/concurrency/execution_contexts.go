@@ -0,0 +1,75 @@
+package concurrency
+
+import (
+	"context"
+	"sync"
+)
+
+// ExecutionContexts tracks a cancellable context per in-flight function execution, so the workflow
+// handler can stop work (e.g. an ai/agent reasoning loop) it no longer waits for.
+type ExecutionContexts struct {
+	mu      sync.Mutex
+	cancels map[string]map[string]context.CancelFunc // workflowID -> execID -> cancel
+}
+
+// NewExecutionContexts creates an empty ExecutionContexts registry
+func NewExecutionContexts() *ExecutionContexts {
+	return &ExecutionContexts{cancels: make(map[string]map[string]context.CancelFunc)}
+}
+
+// Start returns a new context for an execution. A context already registered for the same
+// execution (a duplicate dispatch) is cancelled and replaced.
+func (e *ExecutionContexts) Start(workflowID, execID string) context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	execs, ok := e.cancels[workflowID]
+	if !ok {
+		execs = make(map[string]context.CancelFunc)
+		e.cancels[workflowID] = execs
+	}
+	if prev, exists := execs[execID]; exists {
+		prev()
+	}
+	execs[execID] = cancel
+	return ctx
+}
+
+// Cancel cancels and forgets the context of one execution. Unknown executions are ignored.
+func (e *ExecutionContexts) Cancel(workflowID, execID string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	execs, ok := e.cancels[workflowID]
+	if !ok {
+		return
+	}
+	if cancel, exists := execs[execID]; exists {
+		cancel()
+		delete(execs, execID)
+	}
+	if len(execs) == 0 {
+		delete(e.cancels, workflowID)
+	}
+}
+
+// CancelWorkflow cancels and forgets the contexts of every execution of a workflow.
+func (e *ExecutionContexts) CancelWorkflow(workflowID string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, cancel := range e.cancels[workflowID] {
+		cancel()
+	}
+	delete(e.cancels, workflowID)
+}
+
+// Len returns the number of tracked executions
+func (e *ExecutionContexts) Len() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	n := 0
+	for _, execs := range e.cancels {
+		n += len(execs)
+	}
+	return n
+}
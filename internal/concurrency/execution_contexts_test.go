@@ -0,0 +1,49 @@
+package concurrency
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecutionContexts_Cancel(t *testing.T) {
+	e := NewExecutionContexts()
+
+	ctx1 := e.Start("wf-1", "exec-1")
+	ctx2 := e.Start("wf-1", "exec-2")
+	assert.Equal(t, 2, e.Len())
+
+	e.Cancel("wf-1", "exec-1")
+	assert.Error(t, ctx1.Err())
+	assert.NoError(t, ctx2.Err())
+	assert.Equal(t, 1, e.Len())
+
+	e.Cancel("wf-1", "unknown")
+	e.Cancel("wf-unknown", "exec-2")
+	assert.NoError(t, ctx2.Err())
+}
+
+func TestExecutionContexts_CancelWorkflow(t *testing.T) {
+	e := NewExecutionContexts()
+
+	ctx1 := e.Start("wf-1", "exec-1")
+	ctx2 := e.Start("wf-1", "exec-2")
+	other := e.Start("wf-2", "exec-1")
+
+	e.CancelWorkflow("wf-1")
+	assert.Error(t, ctx1.Err())
+	assert.Error(t, ctx2.Err())
+	assert.NoError(t, other.Err())
+	assert.Equal(t, 1, e.Len())
+}
+
+func TestExecutionContexts_StartReplacesDuplicate(t *testing.T) {
+	e := NewExecutionContexts()
+
+	first := e.Start("wf-1", "exec-1")
+	second := e.Start("wf-1", "exec-1")
+
+	assert.Error(t, first.Err())
+	assert.NoError(t, second.Err())
+	assert.Equal(t, 1, e.Len())
+}
@@ -0,0 +1,89 @@
+package concurrency
+
+import (
+	"sync"
+
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+)
+
+// Waiters correlates the results of asynchronous invocations with the goroutines awaiting them
+// (ADR-0027). A caller registers the exec id of the execution it started and receives its output
+// once, when the workflow handler resolves that exec id.
+type Waiters struct {
+	mu      sync.Mutex
+	waiters map[string]map[string]chan workflow.FunctionOutput // workflowID -> execID -> waiter
+}
+
+// NewWaiters creates an empty Waiters registry
+func NewWaiters() *Waiters {
+	return &Waiters{waiters: make(map[string]map[string]chan workflow.FunctionOutput)}
+}
+
+// Register returns the channel that receives the output of an execution. A waiter already
+// registered for the same execution is replaced; its channel never receives.
+func (w *Waiters) Register(workflowID, execID string) <-chan workflow.FunctionOutput {
+	ch := make(chan workflow.FunctionOutput, 1)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	execs, ok := w.waiters[workflowID]
+	if !ok {
+		execs = make(map[string]chan workflow.FunctionOutput)
+		w.waiters[workflowID] = execs
+	}
+	execs[execID] = ch
+	return ch
+}
+
+// Resolve delivers the output of an execution to its waiter and forgets it. It reports whether a
+// waiter was registered; it never blocks.
+func (w *Waiters) Resolve(workflowID, execID string, output workflow.FunctionOutput) bool {
+	w.mu.Lock()
+	ch, ok := w.waiters[workflowID][execID]
+	if ok {
+		w.forget(workflowID, execID)
+	}
+	w.mu.Unlock()
+	if !ok {
+		return false
+	}
+	ch <- output
+	return true
+}
+
+// Forget drops the waiter of one execution, e.g. after its caller gave up waiting. Unknown
+// executions are ignored.
+func (w *Waiters) Forget(workflowID, execID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.forget(workflowID, execID)
+}
+
+func (w *Waiters) forget(workflowID, execID string) {
+	execs, ok := w.waiters[workflowID]
+	if !ok {
+		return
+	}
+	delete(execs, execID)
+	if len(execs) == 0 {
+		delete(w.waiters, workflowID)
+	}
+}
+
+// ForgetWorkflow drops the waiters of every execution of a workflow.
+func (w *Waiters) ForgetWorkflow(workflowID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.waiters, workflowID)
+}
+
+// Len returns the number of registered waiters
+func (w *Waiters) Len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	n := 0
+	for _, execs := range w.waiters {
+		n += len(execs)
+	}
+	return n
+}
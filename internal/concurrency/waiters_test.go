@@ -0,0 +1,42 @@
+package concurrency
+
+import (
+	"testing"
+
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaiters_ResolveDeliversOnce(t *testing.T) {
+	w := NewWaiters()
+	ch := w.Register("wf-1", "exec-1")
+	assert.Equal(t, 1, w.Len())
+
+	out := workflow.NewFunctionSuccessOutput(map[string]any{"ok": true})
+	require.True(t, w.Resolve("wf-1", "exec-1", out))
+	assert.Equal(t, out, <-ch)
+	assert.Equal(t, 0, w.Len())
+
+	assert.False(t, w.Resolve("wf-1", "exec-1", out), "a resolved waiter is forgotten")
+}
+
+func TestWaiters_ResolveUnknownIsIgnored(t *testing.T) {
+	w := NewWaiters()
+	assert.False(t, w.Resolve("wf-1", "exec-1", workflow.FunctionOutput{}))
+}
+
+func TestWaiters_ForgetAndForgetWorkflow(t *testing.T) {
+	w := NewWaiters()
+	w.Register("wf-1", "exec-1")
+	w.Register("wf-1", "exec-2")
+	w.Register("wf-2", "exec-1")
+
+	w.Forget("wf-1", "exec-1")
+	assert.False(t, w.Resolve("wf-1", "exec-1", workflow.FunctionOutput{}))
+	assert.Equal(t, 2, w.Len())
+
+	w.ForgetWorkflow("wf-1")
+	assert.Equal(t, 1, w.Len())
+	assert.True(t, w.Resolve("wf-2", "exec-1", workflow.FunctionOutput{}))
+}
@@ -0,0 +1,17 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/open-source-cloud/fuse/pkg/llm"
+)
+
+// AgentSessionDTO is the stored conversation of an ai/chat or ai/agent sessionId.
+type AgentSessionDTO struct {
+	ID        string        `json:"id" example:"support-ticket-4821"`
+	Namespace string        `json:"namespace" example:"default"`
+	Messages  []llm.Message `json:"messages"`
+	CreatedAt time.Time     `json:"createdAt"`
+	UpdatedAt time.Time     `json:"updatedAt"`
+	ExpiresAt time.Time     `json:"expiresAt"`
+}
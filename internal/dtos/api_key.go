@@ -0,0 +1,47 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/open-source-cloud/fuse/internal/auth"
+)
+
+// APIKeyDTO is the read shape of an API key. The key itself is never returned after creation.
+type APIKeyDTO struct {
+	ID        string     `json:"id" example:"3f9a1c2b7d4e"`
+	Name      string     `json:"name" example:"ci-pipeline"`
+	CreatedAt time.Time  `json:"createdAt"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	RevokedAt *time.Time `json:"revokedAt,omitempty"`
+	Active    bool       `json:"active"`
+}
+
+// CreateAPIKeyRequest is the request body for issuing an API key.
+type CreateAPIKeyRequest struct {
+	Name string `json:"name" example:"ci-pipeline"`
+	// TTL is a Go duration after which the key expires (e.g. "720h"); empty never expires.
+	TTL string `json:"ttl,omitempty" example:"720h"`
+}
+
+// CreateAPIKeyResponse carries the plaintext key, shown only once.
+type CreateAPIKeyResponse struct {
+	APIKeyDTO
+	Key string `json:"key" example:"fuse_3f9a1c2b7d4e_..."`
+}
+
+// APIKeyListResponse represents a list of API keys.
+type APIKeyListResponse struct {
+	Items []APIKeyDTO `json:"items"`
+}
+
+// ToAPIKeyDTO converts an API key to its read DTO.
+func ToAPIKeyDTO(k *auth.APIKey) APIKeyDTO {
+	return APIKeyDTO{
+		ID:        k.ID,
+		Name:      k.Name,
+		CreatedAt: k.CreatedAt,
+		ExpiresAt: k.ExpiresAt,
+		RevokedAt: k.RevokedAt,
+		Active:    k.Active(time.Now()),
+	}
+}
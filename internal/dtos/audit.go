@@ -0,0 +1,10 @@
+package dtos
+
+import "github.com/open-source-cloud/fuse/internal/audit"
+
+// AuditLogResponse is a page of audit entries, newest first.
+type AuditLogResponse struct {
+	Items []*audit.Entry `json:"items"`
+	// NextBefore is passed as `before` to fetch the next (older) page; empty on the last page.
+	NextBefore string `json:"nextBefore,omitempty" example:"0192f0c4-7b1e-7c3a-9d52-5b0e6f1a2c3d"`
+}
@@ -0,0 +1,18 @@
+package dtos
+
+// EnvironmentVarsResponse lists the variables of an environment in the request's namespace.
+type EnvironmentVarsResponse struct {
+	Environment string            `json:"environment" example:"staging"`
+	Vars        map[string]string `json:"vars"`
+}
+
+// EnvironmentVarDTO is a single environment variable.
+type EnvironmentVarDTO struct {
+	Name  string `json:"name" example:"API_BASE_URL"`
+	Value string `json:"value" example:"https://staging.example.com"`
+}
+
+// SetEnvironmentVarRequest is the body of a variable upsert; the name comes from the path.
+type SetEnvironmentVarRequest struct {
+	Value string `json:"value" example:"https://staging.example.com"`
+}
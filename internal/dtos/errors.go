@@ -12,9 +12,18 @@ type ErrorResponse struct {
 // BadRequestError represents a 400 Bad Request error
 type BadRequestError ErrorResponse
 
+// ForbiddenError represents a 403 Forbidden error
+type ForbiddenError ErrorResponse
+
 // NotFoundError represents a 404 Not Found error
 type NotFoundError ErrorResponse
 
+// ConflictError represents a 409 Conflict error
+type ConflictError ErrorResponse
+
+// QuotaExceededError represents a 429 error returned when a namespace quota would be exceeded
+type QuotaExceededError ErrorResponse
+
 // InternalServerErrorResponse represents a 500 Internal Server Error
 type InternalServerErrorResponse ErrorResponse
 
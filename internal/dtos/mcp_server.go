@@ -0,0 +1,64 @@
+package dtos
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/open-source-cloud/fuse/pkg/mcp"
+)
+
+// MCPServerDTO is the read shape of an MCP server registration. Env and header values are
+// write-only, like credential fields: only their names are returned.
+type MCPServerDTO struct {
+	ID          string   `json:"id" example:"wiki"`
+	Description string   `json:"description,omitempty" example:"Internal wiki search"`
+	Transport   string   `json:"transport" example:"http"`
+	Command     string   `json:"command,omitempty"`
+	Args        []string `json:"args,omitempty"`
+	Env         []string `json:"env,omitempty"`
+	URL         string   `json:"url,omitempty" example:"https://wiki.example.com/mcp"`
+	Headers     []string `json:"headers,omitempty" example:"Authorization"`
+	Timeout     string   `json:"timeout" example:"30s"`
+	Tools       []string `json:"tools,omitempty"`
+}
+
+// UpsertMCPServerRequest is the write shape. Env and header values may hold {{secret:NAME}} and
+// {{credential:ID.FIELD}} references, resolved in the environment of each run.
+type UpsertMCPServerRequest struct {
+	Description string            `json:"description,omitempty"`
+	Transport   string            `json:"transport" example:"http"`
+	Command     string            `json:"command,omitempty"`
+	Args        []string          `json:"args,omitempty"`
+	Env         map[string]string `json:"env,omitempty"`
+	URL         string            `json:"url,omitempty" example:"https://wiki.example.com/mcp"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	Timeout     string            `json:"timeout,omitempty" example:"30s"`
+	Tools       []string          `json:"tools,omitempty"`
+}
+
+// MCPServerListResponse represents a list of MCP servers.
+type MCPServerListResponse struct {
+	Items []MCPServerDTO `json:"items"`
+}
+
+// UpsertMCPServerResponse represents an MCP server upsert response.
+type UpsertMCPServerResponse struct {
+	Message string `json:"message" example:"MCP server saved successfully"`
+	Server  string `json:"server" example:"wiki"`
+}
+
+// ToMCPServerDTO converts a registration to its read DTO (names of env vars and headers only).
+func ToMCPServerDTO(s *mcp.ServerConfig) MCPServerDTO {
+	return MCPServerDTO{
+		ID:          s.ID,
+		Description: s.Description,
+		Transport:   string(s.Transport),
+		Command:     s.Command,
+		Args:        s.Args,
+		Env:         slices.Sorted(maps.Keys(s.Env)),
+		URL:         s.URL,
+		Headers:     slices.Sorted(maps.Keys(s.Headers)),
+		Timeout:     s.CallTimeout().String(),
+		Tools:       s.Tools,
+	}
+}
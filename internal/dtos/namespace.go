@@ -0,0 +1,51 @@
+package dtos
+
+import "github.com/open-source-cloud/fuse/pkg/workflow"
+
+// NamespaceQuotaDTO represents the quotas of a namespace; zero values are unlimited.
+type NamespaceQuotaDTO struct {
+	MaxConcurrentWorkflows int   `json:"maxConcurrentWorkflows,omitempty" example:"50"`
+	MaxStorageBytes        int64 `json:"maxStorageBytes,omitempty" example:"10485760"`
+}
+
+// NamespaceDTO represents a namespace data transfer object.
+type NamespaceDTO struct {
+	Name        string            `json:"name" example:"billing"`
+	Description string            `json:"description,omitempty" example:"Billing team"`
+	Quota       NamespaceQuotaDTO `json:"quota"`
+}
+
+// NamespaceListResponse represents a list of namespaces.
+type NamespaceListResponse struct {
+	Items []NamespaceDTO `json:"items"`
+}
+
+// UpsertNamespaceResponse represents a namespace upsert response.
+type UpsertNamespaceResponse struct {
+	Message   string `json:"message" example:"Namespace saved successfully"`
+	Namespace string `json:"namespace" example:"billing"`
+}
+
+// ToNamespaceDTO converts a domain namespace to its DTO.
+func ToNamespaceDTO(ns *workflow.Namespace) NamespaceDTO {
+	return NamespaceDTO{
+		Name:        ns.Name,
+		Description: ns.Description,
+		Quota: NamespaceQuotaDTO{
+			MaxConcurrentWorkflows: ns.Quota.MaxConcurrentWorkflows,
+			MaxStorageBytes:        ns.Quota.MaxStorageBytes,
+		},
+	}
+}
+
+// FromNamespaceDTO converts a DTO to a domain namespace.
+func FromNamespaceDTO(dto NamespaceDTO) *workflow.Namespace {
+	return &workflow.Namespace{
+		Name:        dto.Name,
+		Description: dto.Description,
+		Quota: workflow.NamespaceQuota{
+			MaxConcurrentWorkflows: dto.Quota.MaxConcurrentWorkflows,
+			MaxStorageBytes:        dto.Quota.MaxStorageBytes,
+		},
+	}
+}
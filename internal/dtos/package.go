@@ -34,6 +34,7 @@ type PackagedFunctionDTO struct {
 // FunctionMetadataDTO represents function metadata data transfer object
 type FunctionMetadataDTO struct {
 	Transport string            `json:"transport" example:"sync"`
+	Endpoint  string            `json:"endpoint,omitempty" example:"https://functions.example.com/score"`
 	Input     InputMetadataDTO  `json:"input"`
 	Output    OutputMetadataDTO `json:"output,omitempty"`
 }
@@ -120,6 +121,7 @@ func ToFunctionMetadataDTO(meta workflow.FunctionMetadata) FunctionMetadataDTO {
 
 	return FunctionMetadataDTO{
 		Transport: string(meta.Transport),
+		Endpoint:  meta.Endpoint,
 		Input: InputMetadataDTO{
 			CustomParameters: meta.Input.CustomParameters,
 			Parameters:       inputParams,
@@ -176,6 +178,7 @@ func FromPackagedFunctionDTO(dto PackagedFunctionDTO) *workflow.PackagedFunction
 		ID: dto.ID,
 		Metadata: workflow.FunctionMetadata{
 			Transport: transport.Type(dto.Metadata.Transport),
+			Endpoint:  dto.Metadata.Endpoint,
 			Input: workflow.InputMetadata{
 				CustomParameters: dto.Metadata.Input.CustomParameters,
 				Parameters:       inputParams,
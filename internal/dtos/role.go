@@ -0,0 +1,88 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/open-source-cloud/fuse/internal/auth"
+)
+
+// RuleDTO grants permissions on the namespaces, schemas and environments matching glob patterns;
+// an omitted pattern list matches everything.
+type RuleDTO struct {
+	Permissions  []string `json:"permissions" example:"workflow:trigger,schema:write"`
+	Namespaces   []string `json:"namespaces,omitempty" example:"billing"`
+	Schemas      []string `json:"schemas,omitempty" example:"invoice-*"`
+	Environments []string `json:"environments,omitempty" example:"staging"`
+}
+
+// RoleDTO represents an RBAC role.
+type RoleDTO struct {
+	Name        string     `json:"name" example:"billing-operator"`
+	Description string     `json:"description,omitempty" example:"Operates billing workflows in staging"`
+	Rules       []RuleDTO  `json:"rules"`
+	Builtin     bool       `json:"builtin,omitempty"`
+	CreatedAt   *time.Time `json:"createdAt,omitempty"`
+	UpdatedAt   *time.Time `json:"updatedAt,omitempty"`
+}
+
+// RoleListResponse represents a list of roles.
+type RoleListResponse struct {
+	Items []RoleDTO `json:"items"`
+}
+
+// RoleBindingDTO grants a role to a principal subject.
+type RoleBindingDTO struct {
+	Subject   string    `json:"subject" example:"apikey:3f9a1c2b7d4e"`
+	Role      string    `json:"role" example:"billing-operator"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// CreateRoleBindingRequest is the request body for binding a role to a subject.
+type CreateRoleBindingRequest struct {
+	Subject string `json:"subject" example:"apikey:3f9a1c2b7d4e"`
+	Role    string `json:"role" example:"billing-operator"`
+}
+
+// RoleBindingListResponse represents a list of role bindings.
+type RoleBindingListResponse struct {
+	Items []RoleBindingDTO `json:"items"`
+}
+
+// ToRoleDTO converts a role to its DTO.
+func ToRoleDTO(role *auth.Role) RoleDTO {
+	dto := RoleDTO{
+		Name:        role.Name,
+		Description: role.Description,
+		Rules:       make([]RuleDTO, len(role.Rules)),
+		Builtin:     role.Name == auth.AdminRole,
+	}
+	for i, rule := range role.Rules {
+		perms := make([]string, len(rule.Permissions))
+		for j, p := range rule.Permissions {
+			perms[j] = string(p)
+		}
+		dto.Rules[i] = RuleDTO{Permissions: perms, Namespaces: rule.Namespaces, Schemas: rule.Schemas, Environments: rule.Environments}
+	}
+	if !role.CreatedAt.IsZero() {
+		dto.CreatedAt, dto.UpdatedAt = &role.CreatedAt, &role.UpdatedAt
+	}
+	return dto
+}
+
+// FromRoleDTO converts a DTO to a role.
+func FromRoleDTO(dto RoleDTO) *auth.Role {
+	role := &auth.Role{Name: dto.Name, Description: dto.Description, Rules: make([]auth.Rule, len(dto.Rules))}
+	for i, rule := range dto.Rules {
+		perms := make([]auth.Permission, len(rule.Permissions))
+		for j, p := range rule.Permissions {
+			perms[j] = auth.Permission(p)
+		}
+		role.Rules[i] = auth.Rule{Permissions: perms, Namespaces: rule.Namespaces, Schemas: rule.Schemas, Environments: rule.Environments}
+	}
+	return role
+}
+
+// ToRoleBindingDTO converts a role binding to its DTO.
+func ToRoleBindingDTO(b *auth.RoleBinding) RoleBindingDTO {
+	return RoleBindingDTO{Subject: b.Subject, Role: b.Role, CreatedAt: b.CreatedAt}
+}
@@ -0,0 +1,12 @@
+package dtos
+
+// SchemaLifecycleRequest is the request body for PUT /v1/schemas/{schemaID}/lifecycle.
+type SchemaLifecycleRequest struct {
+	Lifecycle string `json:"lifecycle" validate:"required,oneof=active deprecated archived" example:"deprecated"`
+}
+
+// SchemaLifecycleResponse is the response for GET and PUT /v1/schemas/{schemaID}/lifecycle.
+type SchemaLifecycleResponse struct {
+	SchemaID  string `json:"schemaId" example:"my-workflow"`
+	Lifecycle string `json:"lifecycle" example:"deprecated"`
+}
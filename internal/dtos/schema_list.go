@@ -2,8 +2,9 @@ package dtos
 
 // GraphSchemaSummaryDTO is a compact schema row for list APIs.
 type GraphSchemaSummaryDTO struct {
-	SchemaID string `json:"schemaID" example:"my-workflow"`
-	Name     string `json:"name" example:"My workflow"`
+	SchemaID  string `json:"schemaID" example:"my-workflow"`
+	Name      string `json:"name" example:"My workflow"`
+	Lifecycle string `json:"lifecycle" example:"active"`
 }
 
 // SchemaListResponse is the response body for GET /v1/schemas.
@@ -0,0 +1,28 @@
+package dtos
+
+import "github.com/open-source-cloud/fuse/internal/workflow"
+
+// TrafficSplitRequest is the request body for PUT /v1/schemas/{schemaID}/traffic.
+type TrafficSplitRequest struct {
+	Routes       []workflow.TrafficRoute      `json:"routes" validate:"required,min=1"`
+	StickyKey    string                       `json:"stickyKey,omitempty" example:"input.customerId"`
+	AutoRollback *workflow.CanaryRollbackRule `json:"autoRollback,omitempty"`
+}
+
+// TrafficVersionStats reports the executions of one routed version since the split was configured.
+type TrafficVersionStats struct {
+	Version   int     `json:"version" example:"8"`
+	Weight    int     `json:"weight" example:"10"`
+	Baseline  bool    `json:"baseline" example:"false"`
+	Completed int     `json:"completed" example:"120"`
+	Failed    int     `json:"failed" example:"3"`
+	ErrorRate float64 `json:"errorRate" example:"0.025"`
+}
+
+// TrafficSplitResponse is the response for GET and PUT /v1/schemas/{schemaID}/traffic. Split is
+// omitted when the schema has no traffic split and every execution runs the active version.
+type TrafficSplitResponse struct {
+	SchemaID string                 `json:"schemaId" example:"my-workflow"`
+	Split    *workflow.TrafficSplit `json:"split,omitempty"`
+	Stats    []TrafficVersionStats  `json:"stats"`
+}
@@ -34,6 +34,15 @@ type SchemaVersionResponse struct {
 	Comment   string               `json:"comment,omitempty"`
 }
 
+// SchemaVersionDiffResponse is the response for GET /v1/schemas/{schemaID}/versions/{from}/diff/{to}.
+type SchemaVersionDiffResponse struct {
+	SchemaID    string              `json:"schemaId" example:"my-workflow"`
+	FromVersion int                 `json:"fromVersion" example:"2"`
+	ToVersion   int                 `json:"toVersion" example:"3"`
+	Identical   bool                `json:"identical" example:"false"`
+	Diff        workflow.SchemaDiff `json:"diff"`
+}
+
 // ActivateVersionResponse is the response for POST /v1/schemas/{schemaID}/versions/{version}/activate.
 type ActivateVersionResponse struct {
 	SchemaID        string `json:"schemaId" example:"my-workflow"`
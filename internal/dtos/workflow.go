@@ -14,6 +14,9 @@ type TriggerWorkflowRequest struct {
 	// Environment scopes secret resolution for this execution (ADR-0031). Empty defaults to
 	// the engine's configured environment (FUSE_ENVIRONMENT).
 	Environment string `json:"environment,omitempty" example:"staging"`
+	// Version runs a specific, possibly non-active, schema version (e.g. for canary testing).
+	// Zero runs the active version.
+	Version int `json:"version,omitempty" example:"3"`
 }
 
 // TriggerWorkflowResponse represents trigger workflow response
@@ -23,6 +26,7 @@ type TriggerWorkflowResponse struct {
 	Code         string `json:"code" example:"OK"`
 	Deduplicated bool   `json:"deduplicated,omitempty" example:"false"`
 	Environment  string `json:"environment,omitempty" example:"staging"`
+	Version      int    `json:"version,omitempty" example:"3"`
 }
 
 // AsyncFunctionRequest is the request body for the AsyncFunctionHandler
@@ -67,6 +71,12 @@ type ResolveAwakeableResponse struct {
 	Status      string `json:"status" example:"resolved"`
 }
 
+// AwakeableTokenResponse carries a resolve token minted for a pending awakeable
+type AwakeableTokenResponse struct {
+	AwakeableID string `json:"awakeableId" example:"awk-123"`
+	Token       string `json:"token" example:"1767225600.c2lnbmF0dXJl"`
+}
+
 // RetryNodeRequest is the request body for retrying a specific failed node
 type RetryNodeRequest struct {
 	ExecID string `json:"execId" validate:"required"`
@@ -9,3 +9,10 @@ const (
 	EventFunctionCompleted = "function.completed"
 	EventFunctionFailed    = "function.failed"
 )
+
+// Event types for workflow schema lifecycle changes
+const (
+	EventSchemaLifecycleChanged = "schema.lifecycle_changed"
+	EventSchemaDeleted          = "schema.deleted"
+	EventSchemaCanaryRolledBack = "schema.canary_rolled_back"
+)
@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/auth"
 	"github.com/open-source-cloud/fuse/internal/dtos"
 	"github.com/open-source-cloud/fuse/internal/repositories"
 	"github.com/open-source-cloud/fuse/internal/services"
@@ -49,17 +50,22 @@ func NewActivateSchemaVersionHandlerFactory(graphService services.GraphService)
 // @Param version path int true "Version number to activate"
 // @Success 200 {object} dtos.ActivateVersionResponse
 // @Failure 400 {object} dtos.BadRequestError
+// @Failure 403 {object} dtos.ForbiddenError
 // @Failure 404 {object} dtos.NotFoundError
 // @Failure 500 {object} dtos.InternalServerErrorResponse
 // @Router /v1/schemas/{schemaID}/versions/{version}/activate [post]
 func (h *ActivateSchemaVersionHandler) HandlePost(from gen.PID, w http.ResponseWriter, r *http.Request) error {
 	h.Log().Info("received activate schema version request", "from", from, "remoteAddr", r.RemoteAddr)
 
-	schemaID, err := h.GetPathParam(r, "schemaID")
+	schemaID, err := h.SchemaIDParam(r)
 	if err != nil {
 		return h.SendBadRequest(w, err, []string{"schemaID is required"})
 	}
 
+	if err := h.Authorize(r, auth.PermSchemaWrite, auth.NewSchemaResource(schemaID, "")); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
 	versionStr, err := h.GetPathParam(r, "version")
 	if err != nil {
 		return h.SendBadRequest(w, err, []string{"version is required"})
@@ -80,7 +86,7 @@ func (h *ActivateSchemaVersionHandler) HandlePost(from gen.PID, w http.ResponseW
 	}
 	previousVersion := history.ActiveVersion
 
-	if err := h.graphService.SetActiveVersion(schemaID, version); err != nil {
+	if err := h.graphService.SetActiveVersion(r.Context(), schemaID, version); err != nil {
 		if errors.Is(err, repositories.ErrSchemaVersionNotFound) {
 			return h.SendNotFound(w, "schema version not found", EmptyFields)
 		}
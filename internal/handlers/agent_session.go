@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/auth"
+	"github.com/open-source-cloud/fuse/internal/dtos"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/internal/services"
+)
+
+const (
+	// AgentSessionHandlerName is the name of the agent session handler.
+	AgentSessionHandlerName = "agent_session_handler"
+	// AgentSessionHandlerPoolName is the name of the agent session handler pool.
+	AgentSessionHandlerPoolName = "agent_session_handler_pool"
+)
+
+type (
+	// AgentSessionHandlerFactory is the factory for the agent session handler.
+	AgentSessionHandlerFactory HandlerFactory[*AgentSessionHandler]
+
+	// AgentSessionHandler reads and erases the stored conversation of an ai node sessionId.
+	AgentSessionHandler struct {
+		Handler
+		sessionService services.AgentSessionService
+	}
+)
+
+// NewAgentSessionHandler creates a new agent session handler factory.
+func NewAgentSessionHandler(sessionService services.AgentSessionService) *AgentSessionHandlerFactory {
+	return &AgentSessionHandlerFactory{
+		Factory: func() gen.ProcessBehavior {
+			return &AgentSessionHandler{sessionService: sessionService}
+		},
+	}
+}
+
+// HandleGet retrieves a session (GET /v1/ai/sessions/{id})
+// @Summary Get agent session
+// @Description Retrieve the stored conversation of an ai/chat or ai/agent sessionId in the request's namespace
+// @Tags ai
+// @Accept json
+// @Produce json
+// @Param id path string true "Session ID"
+// @Success 200 {object} dtos.AgentSessionDTO
+// @Failure 403 {object} dtos.ForbiddenError
+// @Failure 404 {object} dtos.NotFoundError
+// @Failure 500 {object} dtos.InternalServerErrorResponse
+// @Router /v1/ai/sessions/{id} [get]
+func (h *AgentSessionHandler) HandleGet(from gen.PID, w http.ResponseWriter, r *http.Request) error {
+	h.Log().Info("received get agent session request from: %v remoteAddr: %s", from, r.RemoteAddr)
+
+	id, err := h.GetPathParam(r, "id")
+	if err != nil {
+		return h.SendBadRequest(w, err, []string{"id is required"})
+	}
+
+	namespace := h.Namespace(r)
+	if err := h.Authorize(r, auth.PermSessionRead, auth.Resource{Namespace: namespace}); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
+	session, err := h.sessionService.Get(namespace, id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrAgentSessionNotFound) {
+			return h.SendNotFound(w, fmt.Sprintf("session %s not found", id), []string{"id"})
+		}
+		return h.SendInternalError(w, err)
+	}
+
+	return h.SendJSON(w, http.StatusOK, dtos.AgentSessionDTO{
+		ID:        session.ID,
+		Namespace: session.Namespace,
+		Messages:  session.Messages,
+		CreatedAt: session.CreatedAt,
+		UpdatedAt: session.UpdatedAt,
+		ExpiresAt: session.ExpiresAt,
+	})
+}
+
+// HandleDelete erases a session (DELETE /v1/ai/sessions/{id})
+// @Summary Delete agent session
+// @Description Erase the stored conversation of a sessionId in the request's namespace; the next run with that sessionId starts a new conversation
+// @Tags ai
+// @Accept json
+// @Produce json
+// @Param id path string true "Session ID"
+// @Success 204 "No Content"
+// @Failure 403 {object} dtos.ForbiddenError
+// @Failure 404 {object} dtos.NotFoundError
+// @Failure 500 {object} dtos.InternalServerErrorResponse
+// @Router /v1/ai/sessions/{id} [delete]
+func (h *AgentSessionHandler) HandleDelete(from gen.PID, w http.ResponseWriter, r *http.Request) error {
+	h.Log().Info("received delete agent session request from: %v remoteAddr: %s", from, r.RemoteAddr)
+
+	id, err := h.GetPathParam(r, "id")
+	if err != nil {
+		return h.SendBadRequest(w, err, []string{"id is required"})
+	}
+
+	namespace := h.Namespace(r)
+	if err := h.Authorize(r, auth.PermSessionDelete, auth.Resource{Namespace: namespace}); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
+	if delErr := h.sessionService.Delete(r.Context(), namespace, id); delErr != nil {
+		if errors.Is(delErr, repositories.ErrAgentSessionNotFound) {
+			return h.SendNotFound(w, fmt.Sprintf("session %s not found", id), []string{"id"})
+		}
+		return h.SendInternalError(w, delErr)
+	}
+
+	return h.SendJSON(w, http.StatusNoContent, nil)
+}
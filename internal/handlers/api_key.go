@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/auth"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/internal/services"
+)
+
+const (
+	// APIKeyHandlerName is the name of the single API key handler.
+	APIKeyHandlerName = "api_key_handler"
+	// APIKeyHandlerPoolName is the name of the single API key handler pool.
+	APIKeyHandlerPoolName = "api_key_handler_pool"
+)
+
+type (
+	// APIKeyHandlerFactory is the factory for the single API key handler.
+	APIKeyHandlerFactory HandlerFactory[*APIKeyHandler]
+
+	// APIKeyHandler revokes a single API key.
+	APIKeyHandler struct {
+		Handler
+		apiKeyService services.APIKeyService
+	}
+)
+
+// NewAPIKeyHandler creates a new single API key handler factory.
+func NewAPIKeyHandler(apiKeyService services.APIKeyService) *APIKeyHandlerFactory {
+	return &APIKeyHandlerFactory{
+		Factory: func() gen.ProcessBehavior {
+			return &APIKeyHandler{apiKeyService: apiKeyService}
+		},
+	}
+}
+
+// HandleDelete revokes an API key (DELETE /v1/api-keys/{id})
+// @Summary Revoke API key
+// @Description Revoke an API key; requests using it are rejected immediately. The key stays listed as revoked.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param id path string true "API key id"
+// @Success 204 "No Content"
+// @Failure 401 {object} dtos.ErrorResponse
+// @Failure 403 {object} dtos.ForbiddenError
+// @Failure 404 {object} dtos.NotFoundError
+// @Failure 500 {object} dtos.InternalServerErrorResponse
+// @Router /v1/api-keys/{id} [delete]
+func (h *APIKeyHandler) HandleDelete(from gen.PID, w http.ResponseWriter, r *http.Request) error {
+	h.Log().Info("received revoke api key request from: %v remoteAddr: %s", from, r.RemoteAddr)
+
+	if err := h.Authorize(r, auth.PermAdmin, auth.Resource{}); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
+	id, err := h.GetPathParam(r, "id")
+	if err != nil {
+		return h.SendBadRequest(w, err, []string{"id is required"})
+	}
+
+	if err := h.apiKeyService.Revoke(id); err != nil {
+		if errors.Is(err, repositories.ErrAPIKeyNotFound) {
+			return h.SendNotFound(w, fmt.Sprintf("api key %s not found", id), []string{"id"})
+		}
+		return h.SendInternalError(w, err)
+	}
+	return h.SendJSON(w, http.StatusNoContent, nil)
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/auth"
+	"github.com/open-source-cloud/fuse/internal/dtos"
+	"github.com/open-source-cloud/fuse/internal/services"
+)
+
+const (
+	// APIKeysHandlerName is the name of the API keys collection handler.
+	APIKeysHandlerName = "api_keys_handler"
+	// APIKeysHandlerPoolName is the name of the API keys collection handler pool.
+	APIKeysHandlerPoolName = "api_keys_handler_pool"
+)
+
+type (
+	// APIKeysHandlerFactory is the factory for the API keys collection handler.
+	APIKeysHandlerFactory HandlerFactory[*APIKeysHandler]
+
+	// APIKeysHandler lists and issues API keys.
+	APIKeysHandler struct {
+		Handler
+		apiKeyService services.APIKeyService
+	}
+)
+
+// NewAPIKeysHandler creates a new API keys collection handler factory.
+func NewAPIKeysHandler(apiKeyService services.APIKeyService) *APIKeysHandlerFactory {
+	return &APIKeysHandlerFactory{
+		Factory: func() gen.ProcessBehavior {
+			return &APIKeysHandler{apiKeyService: apiKeyService}
+		},
+	}
+}
+
+// HandleGet lists API keys (GET /v1/api-keys)
+// @Summary List API keys
+// @Description Retrieve all API keys, including revoked and expired ones (the keys themselves are never returned)
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} dtos.APIKeyListResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Failure 403 {object} dtos.ForbiddenError
+// @Failure 500 {object} dtos.InternalServerErrorResponse
+// @Router /v1/api-keys [get]
+func (h *APIKeysHandler) HandleGet(from gen.PID, w http.ResponseWriter, r *http.Request) error {
+	h.Log().Info("received list api keys request from: %v remoteAddr: %s", from, r.RemoteAddr)
+
+	if err := h.Authorize(r, auth.PermAdmin, auth.Resource{}); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
+	keys, err := h.apiKeyService.FindAll()
+	if err != nil {
+		return h.SendInternalError(w, err)
+	}
+
+	items := make([]dtos.APIKeyDTO, len(keys))
+	for i, k := range keys {
+		items[i] = dtos.ToAPIKeyDTO(k)
+	}
+	return h.SendJSON(w, http.StatusOK, dtos.APIKeyListResponse{Items: items})
+}
+
+// HandlePost issues a new API key (POST /v1/api-keys)
+// @Summary Create API key
+// @Description Issue a new API key. The key is returned once in the response and cannot be retrieved again.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dtos.CreateAPIKeyRequest true "API key request"
+// @Success 201 {object} dtos.CreateAPIKeyResponse
+// @Failure 400 {object} dtos.BadRequestError
+// @Failure 401 {object} dtos.ErrorResponse
+// @Failure 403 {object} dtos.ForbiddenError
+// @Failure 500 {object} dtos.InternalServerErrorResponse
+// @Router /v1/api-keys [post]
+func (h *APIKeysHandler) HandlePost(from gen.PID, w http.ResponseWriter, r *http.Request) error {
+	h.Log().Info("received create api key request from: %v remoteAddr: %s", from, r.RemoteAddr)
+
+	if err := h.Authorize(r, auth.PermAdmin, auth.Resource{}); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
+	var req dtos.CreateAPIKeyRequest
+	if err := h.BindJSON(w, r, &req); err != nil {
+		return h.SendBadRequest(w, err, []string{"body"})
+	}
+	var ttl time.Duration
+	if req.TTL != "" {
+		parsed, err := time.ParseDuration(req.TTL)
+		if err != nil {
+			return h.SendBadRequest(w, fmt.Errorf("invalid ttl %q: %w", req.TTL, err), []string{"ttl"})
+		}
+		ttl = parsed
+	}
+
+	key, plaintext, err := h.apiKeyService.Create(req.Name, ttl)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidAPIKeyRequest) {
+			return h.SendBadRequest(w, err, []string{"name", "ttl"})
+		}
+		return h.SendInternalError(w, err)
+	}
+
+	return h.SendJSON(w, http.StatusCreated, dtos.CreateAPIKeyResponse{
+		APIKeyDTO: dtos.ToAPIKeyDTO(key),
+		Key:       plaintext,
+	})
+}
@@ -7,8 +7,11 @@ import (
 	"github.com/open-source-cloud/fuse/internal/actors/actornames"
 
 	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/auth"
 	"github.com/open-source-cloud/fuse/internal/dtos"
 	"github.com/open-source-cloud/fuse/internal/messaging"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/internal/services"
 	"github.com/open-source-cloud/fuse/pkg/workflow"
 )
 
@@ -16,6 +19,8 @@ type (
 	// AsyncFunctionHandler Fiber http handler
 	AsyncFunctionHandler struct {
 		Handler
+		workflowRepo   repositories.WorkflowRepository
+		callbackTokens services.CallbackTokenService
 	}
 	// AsyncFunctionResultHandlerFactory is a factory for creating AsyncFunctionHandler actors
 	AsyncFunctionResultHandlerFactory HandlerFactory[*AsyncFunctionHandler]
@@ -29,25 +34,33 @@ const (
 )
 
 // NewAsyncFunctionResultHandlerFactory creates a new AsyncFunctionResultHandlerFactory
-func NewAsyncFunctionResultHandlerFactory() *AsyncFunctionResultHandlerFactory {
+func NewAsyncFunctionResultHandlerFactory(
+	workflowRepo repositories.WorkflowRepository,
+	callbackTokens services.CallbackTokenService,
+) *AsyncFunctionResultHandlerFactory {
 	return &AsyncFunctionResultHandlerFactory{
 		Factory: func() gen.ProcessBehavior {
-			return &AsyncFunctionHandler{}
+			return &AsyncFunctionHandler{workflowRepo: workflowRepo, callbackTokens: callbackTokens}
 		},
 	}
 }
 
 // HandlePost handles the http AsyncFunctionResult endpoint (POST /v1/workflows/{workflowID}/execs/{execID})
 // @Summary Submit async function result
-// @Description Submit the result of an async function execution
+// @Description Submit the result of an async function execution. The execution's callback token is required and accepted once.
 // @Tags workflows
 // @Accept json
 // @Produce json
 // @Param workflowID path string true "Workflow ID"
 // @Param execID path string true "Execution ID"
+// @Param X-Fuse-Callback-Token header string false "Callback token from ExecutionInfo (or the token query param)"
+// @Param token query string false "Callback token, when not sent as a header"
 // @Param result body dtos.AsyncFunctionRequest true "Function Result"
 // @Success 200 {object} dtos.AsyncFunctionResultResponse
 // @Failure 400 {object} dtos.BadRequestError
+// @Failure 403 {object} dtos.ForbiddenError
+// @Failure 404 {object} dtos.NotFoundError
+// @Failure 409 {object} dtos.ConflictError
 // @Failure 500 {object} dtos.InternalServerErrorResponse
 // @Router /v1/workflows/{workflowID}/execs/{execID} [post]
 func (h *AsyncFunctionHandler) HandlePost(from gen.PID, w http.ResponseWriter, r *http.Request) error {
@@ -59,6 +72,15 @@ func (h *AsyncFunctionHandler) HandlePost(from gen.PID, w http.ResponseWriter, r
 	}
 	workflowID := workflow.ID(strWorkflowID)
 
+	wf, getErr := h.workflowRepo.Get(strWorkflowID)
+	if getErr != nil || !h.InNamespace(r, wf) {
+		return h.SendNotFound(w, "workflow not found", EmptyFields)
+	}
+	// function workers need workflow:trigger on the execution they report into
+	if err := h.Authorize(r, auth.PermWorkflowTrigger, workflowResource(wf)); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
 	strExecID, err := h.GetPathParam(r, "execID")
 	if err != nil {
 		return h.SendBadRequest(w, err, []string{"execID"})
@@ -70,10 +92,19 @@ func (h *AsyncFunctionHandler) HandlePost(from gen.PID, w http.ResponseWriter, r
 		return h.SendBadRequest(w, err, []string{"body"})
 	}
 
+	// consumed after the body is read, so a malformed request does not use the token up
+	if err := h.callbackTokens.Consume(
+		h.CallbackToken(r), services.CallbackExec, workflowID.String(), execID.String(),
+	); err != nil {
+		return h.SendCallbackTokenError(w, err)
+	}
+
 	if err = h.Send(
-		actornames.WorkflowHandlerName(workflowID),
+		actornames.WorkflowHandlerName(wf.Namespace(), workflowID),
 		messaging.NewAsyncFunctionResultMessage(workflowID, execID, req.Result),
 	); err != nil {
+		// an undelivered result leaves the token usable, so the function can report it again
+		h.callbackTokens.Release(h.CallbackToken(r))
 		return h.SendInternalError(w, err)
 	}
 
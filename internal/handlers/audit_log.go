@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/audit"
+	"github.com/open-source-cloud/fuse/internal/auth"
+	"github.com/open-source-cloud/fuse/internal/dtos"
+	"github.com/open-source-cloud/fuse/internal/services"
+)
+
+const (
+	// AuditLogHandlerName is the name of the audit log handler.
+	AuditLogHandlerName = "audit_log_handler"
+	// AuditLogHandlerPoolName is the name of the audit log handler pool.
+	AuditLogHandlerPoolName = "audit_log_handler_pool"
+)
+
+type (
+	// AuditLogHandlerFactory is the factory for the audit log handler.
+	AuditLogHandlerFactory HandlerFactory[*AuditLogHandler]
+
+	// AuditLogHandler serves GET /v1/audit.
+	AuditLogHandler struct {
+		Handler
+		auditService services.AuditService
+	}
+)
+
+// NewAuditLogHandler creates a new audit log handler factory.
+func NewAuditLogHandler(auditService services.AuditService) *AuditLogHandlerFactory {
+	return &AuditLogHandlerFactory{
+		Factory: func() gen.ProcessBehavior {
+			return &AuditLogHandler{auditService: auditService}
+		},
+	}
+}
+
+// HandleGet queries the audit log (GET /v1/audit)
+// @Summary Query audit log
+// @Description Administrative changes (schema, package, credential, secret, environment and variable writes, cancels and manual retries), newest first. Secret values are never recorded.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param actor query string false "Actor, e.g. apikey:3f9a1c2b7d4e or cli:alice"
+// @Param action query string false "Action, e.g. schema.upsert"
+// @Param resourceType query string false "Resource type, e.g. schema"
+// @Param resource query string false "Resource ID within its namespace"
+// @Param namespace query string false "Namespace"
+// @Param since query string false "Only entries at or after this time (RFC3339)"
+// @Param until query string false "Only entries before this time (RFC3339)"
+// @Param before query string false "Only entries older than this entry ID (the previous page's nextBefore)"
+// @Param limit query int false "Maximum entries (default 100, max 1000)"
+// @Success 200 {object} dtos.AuditLogResponse
+// @Failure 400 {object} dtos.BadRequestError
+// @Failure 401 {object} dtos.ErrorResponse
+// @Failure 403 {object} dtos.ForbiddenError
+// @Failure 500 {object} dtos.InternalServerErrorResponse
+// @Router /v1/audit [get]
+func (h *AuditLogHandler) HandleGet(from gen.PID, w http.ResponseWriter, r *http.Request) error {
+	h.Log().Info("received audit log request from: %v remoteAddr: %s", from, r.RemoteAddr)
+
+	if err := h.Authorize(r, auth.PermAdmin, auth.Resource{}); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
+	filter, fields, err := parseAuditFilter(r.URL.Query())
+	if err != nil {
+		return h.SendBadRequest(w, err, fields)
+	}
+
+	entries, err := h.auditService.Find(filter)
+	if err != nil {
+		return h.SendInternalError(w, err)
+	}
+
+	resp := dtos.AuditLogResponse{Items: entries}
+	if len(entries) == filter.EffectiveLimit() {
+		resp.NextBefore = entries[len(entries)-1].ID
+	}
+	return h.SendJSON(w, http.StatusOK, resp)
+}
+
+// parseAuditFilter reads the audit log filters from the query string. On error it also returns
+// the fields to report to the client.
+func parseAuditFilter(q url.Values) (audit.Filter, []string, error) {
+	filter := audit.Filter{
+		Actor:        q.Get("actor"),
+		Action:       audit.Action(q.Get("action")),
+		ResourceType: q.Get("resourceType"),
+		Resource:     q.Get("resource"),
+		Namespace:    q.Get("namespace"),
+		Before:       q.Get("before"),
+	}
+	if sinceStr := q.Get("since"); sinceStr != "" {
+		t, parseErr := time.Parse(time.RFC3339, sinceStr)
+		if parseErr != nil {
+			return filter, []string{"since must be in RFC3339 format"}, parseErr
+		}
+		filter.Since = t
+	}
+	if untilStr := q.Get("until"); untilStr != "" {
+		t, parseErr := time.Parse(time.RFC3339, untilStr)
+		if parseErr != nil {
+			return filter, []string{"until must be in RFC3339 format"}, parseErr
+		}
+		filter.Until = t
+	}
+	if limitStr := q.Get("limit"); limitStr != "" {
+		limit, parseErr := strconv.Atoi(limitStr)
+		if parseErr != nil || limit < 1 {
+			return filter, []string{"limit must be a positive integer"}, fmt.Errorf("invalid limit %q", limitStr)
+		}
+		filter.Limit = limit
+	}
+	return filter, nil, nil
+}
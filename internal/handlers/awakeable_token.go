@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/auth"
+	"github.com/open-source-cloud/fuse/internal/dtos"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/internal/services"
+	internalworkflow "github.com/open-source-cloud/fuse/internal/workflow"
+)
+
+type (
+	// AwakeableTokenHandler is the handler for GET /v1/awakeables/{awakeableID}/token
+	AwakeableTokenHandler struct {
+		Handler
+		awakeableRepo  repositories.AwakeableRepository
+		workflowRepo   repositories.WorkflowRepository
+		callbackTokens services.CallbackTokenService
+	}
+	// AwakeableTokenHandlerFactory is a factory for creating AwakeableTokenHandler actors
+	AwakeableTokenHandlerFactory HandlerFactory[*AwakeableTokenHandler]
+)
+
+const (
+	// AwakeableTokenHandlerName is the name of the AwakeableTokenHandler actor
+	AwakeableTokenHandlerName = "awakeable_token_handler"
+	// AwakeableTokenHandlerPoolName is the name of the AwakeableTokenHandler pool
+	AwakeableTokenHandlerPoolName = "awakeable_token_handler_pool"
+)
+
+// NewAwakeableTokenHandlerFactory creates a new AwakeableTokenHandlerFactory
+func NewAwakeableTokenHandlerFactory(
+	awakeableRepo repositories.AwakeableRepository,
+	workflowRepo repositories.WorkflowRepository,
+	callbackTokens services.CallbackTokenService,
+) *AwakeableTokenHandlerFactory {
+	return &AwakeableTokenHandlerFactory{
+		Factory: func() gen.ProcessBehavior {
+			return &AwakeableTokenHandler{
+				awakeableRepo:  awakeableRepo,
+				workflowRepo:   workflowRepo,
+				callbackTokens: callbackTokens,
+			}
+		},
+	}
+}
+
+// HandleGet handles GET /v1/awakeables/{awakeableID}/token
+// @Summary Get awakeable resolve token
+// @Description Mints a resolve token for a pending awakeable, to hand to the external system that resolves it. Resolve tokens are never journaled; they are only returned to callers allowed to resolve the awakeable themselves. A token is accepted once and expires with the awakeable's timeout (or CALLBACK_TOKEN_TTL).
+// @Tags workflows
+// @Produce json
+// @Param awakeableID path string true "Awakeable ID"
+// @Success 200 {object} dtos.AwakeableTokenResponse
+// @Failure 400 {object} dtos.BadRequestError
+// @Failure 403 {object} dtos.ForbiddenError
+// @Failure 404 {object} dtos.NotFoundError
+// @Failure 500 {object} dtos.InternalServerErrorResponse
+// @Router /v1/awakeables/{awakeableID}/token [get]
+func (h *AwakeableTokenHandler) HandleGet(_ gen.PID, w http.ResponseWriter, r *http.Request) error {
+	awakeableID, err := h.GetPathParam(r, "awakeableID")
+	if err != nil {
+		return h.SendBadRequest(w, err, EmptyFields)
+	}
+
+	awakeable, err := h.awakeableRepo.FindByID(awakeableID)
+	if err != nil {
+		return h.SendNotFound(w, "awakeable not found", EmptyFields)
+	}
+
+	wf, getErr := h.workflowRepo.Get(awakeable.WorkflowID.String())
+	if getErr != nil || !h.InNamespace(r, wf) {
+		return h.SendNotFound(w, "awakeable not found", EmptyFields)
+	}
+	if err := h.Authorize(r, auth.PermWorkflowTrigger, workflowResource(wf)); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
+	// the token expires with the awakeable, so one minted later never outlives the first
+	var ttl time.Duration
+	if awakeable.Timeout > 0 {
+		ttl = time.Until(awakeable.DeadlineAt)
+	}
+	if awakeable.Status != internalworkflow.AwakeablePending || (awakeable.Timeout > 0 && ttl <= 0) {
+		return h.SendBadRequest(w, errors.New("awakeable is not in pending status"), []string{"awakeableID"})
+	}
+
+	return h.SendJSON(w, http.StatusOK, dtos.AwakeableTokenResponse{
+		AwakeableID: awakeableID,
+		Token:       h.callbackTokens.Mint(services.CallbackAwakeable, awakeable.WorkflowID.String(), awakeableID, ttl),
+	})
+}
@@ -5,8 +5,12 @@ import (
 	"time"
 
 	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/audit"
+	"github.com/open-source-cloud/fuse/internal/auth"
 	"github.com/open-source-cloud/fuse/internal/dtos"
 	"github.com/open-source-cloud/fuse/internal/messaging"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/internal/services"
 	"github.com/open-source-cloud/fuse/pkg/workflow"
 )
 
@@ -14,6 +18,8 @@ type (
 	// CancelWorkflowHandler is the handler for the cancel workflow endpoint
 	CancelWorkflowHandler struct {
 		Handler
+		workflowRepo repositories.WorkflowRepository
+		auditService services.AuditService
 	}
 	// CancelWorkflowHandlerFactory is a factory for creating CancelWorkflowHandler actors
 	CancelWorkflowHandlerFactory HandlerFactory[*CancelWorkflowHandler]
@@ -27,10 +33,10 @@ const (
 )
 
 // NewCancelWorkflowHandlerFactory creates a new CancelWorkflowHandlerFactory
-func NewCancelWorkflowHandlerFactory() *CancelWorkflowHandlerFactory {
+func NewCancelWorkflowHandlerFactory(workflowRepo repositories.WorkflowRepository, auditService services.AuditService) *CancelWorkflowHandlerFactory {
 	return &CancelWorkflowHandlerFactory{
 		Factory: func() gen.ProcessBehavior {
-			return &CancelWorkflowHandler{}
+			return &CancelWorkflowHandler{workflowRepo: workflowRepo, auditService: auditService}
 		},
 	}
 }
@@ -45,6 +51,8 @@ func NewCancelWorkflowHandlerFactory() *CancelWorkflowHandlerFactory {
 // @Param request body dtos.CancelWorkflowRequest false "Optional cancellation reason"
 // @Success 200 {object} dtos.CancelWorkflowResponse
 // @Failure 400 {object} dtos.BadRequestError
+// @Failure 403 {object} dtos.ForbiddenError
+// @Failure 404 {object} dtos.NotFoundError
 // @Failure 500 {object} dtos.InternalServerErrorResponse
 // @Router /v1/workflows/{workflowID}/cancel [post]
 func (h *CancelWorkflowHandler) HandlePost(_ gen.PID, w http.ResponseWriter, r *http.Request) error {
@@ -53,14 +61,28 @@ func (h *CancelWorkflowHandler) HandlePost(_ gen.PID, w http.ResponseWriter, r *
 		return h.SendBadRequest(w, err, EmptyFields)
 	}
 
+	wf, getErr := h.workflowRepo.Get(workflowID)
+	if getErr != nil || !h.InNamespace(r, wf) {
+		return h.SendNotFound(w, "workflow not found", EmptyFields)
+	}
+	if err := h.Authorize(r, auth.PermWorkflowCancel, workflowResource(wf)); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
 	var req dtos.CancelWorkflowRequest
 	_ = h.BindJSON(w, r, &req) // reason is optional
 
-	cancelMsg := messaging.NewCancelWorkflowMessage(workflow.ID(workflowID), req.Reason)
+	cancelMsg := messaging.NewCancelWorkflowMessage(wf.Namespace(), workflow.ID(workflowID), req.Reason)
 	if err := h.Send(WorkflowSupervisorName, cancelMsg); err != nil {
 		return h.SendInternalError(w, err)
 	}
 
+	entry := workflowAuditEntry(audit.ActionWorkflowCancel, workflowID, wf)
+	if req.Reason != "" {
+		entry.Details["reason"] = req.Reason
+	}
+	h.auditService.Record(r.Context(), entry)
+
 	return h.SendJSON(w, http.StatusOK, dtos.CancelWorkflowResponse{
 		WorkflowID:  workflowID,
 		Status:      "cancelled",
@@ -7,9 +7,11 @@ import (
 
 	"ergo.services/ergo/gen"
 	"github.com/open-source-cloud/fuse/internal/app/config"
+	"github.com/open-source-cloud/fuse/internal/auth"
 	"github.com/open-source-cloud/fuse/internal/dtos"
 	"github.com/open-source-cloud/fuse/internal/repositories"
 	"github.com/open-source-cloud/fuse/internal/services"
+	"github.com/open-source-cloud/fuse/pkg/secrets"
 	"github.com/open-source-cloud/fuse/pkg/workflow"
 )
 
@@ -44,12 +46,13 @@ func NewCredentialHandler(credentialService services.CredentialService, cfg *con
 	}
 }
 
-// environmentParam returns the ?environment= query value or the engine default.
-func (h *CredentialHandler) environmentParam(r *http.Request) string {
+// scope returns the request's namespace and the ?environment= query value or the engine default.
+func (h *CredentialHandler) scope(r *http.Request) secrets.Scope {
+	scope := secrets.Scope{Namespace: h.Namespace(r), Environment: h.defaultEnvironment}
 	if env := r.URL.Query().Get("environment"); env != "" {
-		return env
+		scope.Environment = env
 	}
-	return h.defaultEnvironment
+	return scope
 }
 
 // HandleGet retrieves a single credential's metadata (GET /v1/credentials/{id})
@@ -60,6 +63,7 @@ func (h *CredentialHandler) environmentParam(r *http.Request) string {
 // @Produce json
 // @Param id path string true "Credential id"
 // @Success 200 {object} dtos.CredentialDTO
+// @Failure 403 {object} dtos.ForbiddenError
 // @Failure 404 {object} dtos.NotFoundError
 // @Failure 500 {object} dtos.InternalServerErrorResponse
 // @Router /v1/credentials/{id} [get]
@@ -71,7 +75,12 @@ func (h *CredentialHandler) HandleGet(from gen.PID, w http.ResponseWriter, r *ht
 		return h.SendBadRequest(w, err, []string{"id is required"})
 	}
 
-	cred, err := h.credentialService.FindByID(id)
+	namespace := h.Namespace(r)
+	if err := h.Authorize(r, auth.PermSecretReadNames, auth.Resource{Namespace: namespace}); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
+	cred, err := h.credentialService.FindByID(namespace, id)
 	if err != nil {
 		if errors.Is(err, repositories.ErrCredentialNotFound) {
 			return h.SendNotFound(w, fmt.Sprintf("credential %s not found", id), []string{"id"})
@@ -84,7 +93,7 @@ func (h *CredentialHandler) HandleGet(from gen.PID, w http.ResponseWriter, r *ht
 
 // HandlePut creates or updates a credential and its field values for an environment.
 // @Summary Create or update credential
-// @Description Upsert a credential; field values are stored in the SecretStore for the target environment (?environment=)
+// @Description Upsert a credential; field values are stored in the SecretStore for the request's namespace and the target environment (?environment=)
 // @Tags credentials
 // @Accept json
 // @Produce json
@@ -93,6 +102,7 @@ func (h *CredentialHandler) HandleGet(from gen.PID, w http.ResponseWriter, r *ht
 // @Param credential body dtos.UpsertCredentialRequest true "Credential data"
 // @Success 200 {object} dtos.UpsertCredentialResponse
 // @Failure 400 {object} dtos.BadRequestError
+// @Failure 403 {object} dtos.ForbiddenError
 // @Failure 500 {object} dtos.InternalServerErrorResponse
 // @Router /v1/credentials/{id} [put]
 func (h *CredentialHandler) HandlePut(from gen.PID, w http.ResponseWriter, r *http.Request) error {
@@ -103,13 +113,18 @@ func (h *CredentialHandler) HandlePut(from gen.PID, w http.ResponseWriter, r *ht
 		return h.SendBadRequest(w, err, []string{"id is required"})
 	}
 
+	scope := h.scope(r)
+	if err := h.Authorize(r, auth.PermCredentialWrite, auth.Resource{Namespace: scope.Namespace, Environment: scope.Environment}); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
 	var req dtos.UpsertCredentialRequest
 	if bindErr := h.BindJSON(w, r, &req); bindErr != nil {
 		return h.SendBadRequest(w, bindErr, []string{"body"})
 	}
 
 	cred := workflow.NewCredential(id, req.Type, req.Description, nil)
-	if _, saveErr := h.credentialService.Save(cred, req.Fields, h.environmentParam(r)); saveErr != nil {
+	if _, saveErr := h.credentialService.Save(r.Context(), cred, req.Fields, scope); saveErr != nil {
 		if errors.Is(saveErr, services.ErrReadOnlySecretStore) {
 			return h.SendBadRequest(w, saveErr, []string{"SECRETS_DRIVER"})
 		}
@@ -128,13 +143,14 @@ func (h *CredentialHandler) HandlePut(from gen.PID, w http.ResponseWriter, r *ht
 
 // HandleDelete removes a credential and its field values for an environment.
 // @Summary Delete credential
-// @Description Delete a credential's metadata and its field values for the target environment (?environment=)
+// @Description Delete a credential's metadata and its field values for the request's namespace and the target environment (?environment=)
 // @Tags credentials
 // @Accept json
 // @Produce json
 // @Param id path string true "Credential id"
 // @Param environment query string false "Environment scope for the field values (defaults to FUSE_ENVIRONMENT)"
 // @Success 204 "No Content"
+// @Failure 403 {object} dtos.ForbiddenError
 // @Failure 404 {object} dtos.NotFoundError
 // @Failure 500 {object} dtos.InternalServerErrorResponse
 // @Router /v1/credentials/{id} [delete]
@@ -146,7 +162,12 @@ func (h *CredentialHandler) HandleDelete(from gen.PID, w http.ResponseWriter, r
 		return h.SendBadRequest(w, err, []string{"id is required"})
 	}
 
-	if delErr := h.credentialService.Delete(id, h.environmentParam(r)); delErr != nil {
+	scope := h.scope(r)
+	if err := h.Authorize(r, auth.PermCredentialWrite, auth.Resource{Namespace: scope.Namespace, Environment: scope.Environment}); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
+	if delErr := h.credentialService.Delete(r.Context(), id, scope); delErr != nil {
 		if errors.Is(delErr, repositories.ErrCredentialNotFound) {
 			return h.SendNotFound(w, fmt.Sprintf("credential %s not found", id), []string{"id"})
 		}
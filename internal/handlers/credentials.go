@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/auth"
 	"github.com/open-source-cloud/fuse/internal/dtos"
 	"github.com/open-source-cloud/fuse/internal/services"
 )
@@ -37,17 +38,23 @@ func NewCredentialsHandler(credentialService services.CredentialService) *Creden
 
 // HandleGet lists all credentials, metadata only (GET /v1/credentials)
 // @Summary List credentials
-// @Description Retrieve all credentials (metadata only; field values are never returned)
+// @Description Retrieve the credentials of the request's namespace (metadata only; field values are never returned)
 // @Tags credentials
 // @Accept json
 // @Produce json
 // @Success 200 {object} dtos.CredentialListResponse
+// @Failure 403 {object} dtos.ForbiddenError
 // @Failure 500 {object} dtos.InternalServerErrorResponse
 // @Router /v1/credentials [get]
 func (h *CredentialsHandler) HandleGet(from gen.PID, w http.ResponseWriter, r *http.Request) error {
 	h.Log().Info("received list credentials request from: %v remoteAddr: %s", from, r.RemoteAddr)
 
-	creds, err := h.credentialService.FindAll()
+	namespace := h.Namespace(r)
+	if err := h.Authorize(r, auth.PermSecretReadNames, auth.Resource{Namespace: namespace}); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
+	creds, err := h.credentialService.FindAll(namespace)
 	if err != nil {
 		return h.SendInternalError(w, err)
 	}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/auth"
+	"github.com/open-source-cloud/fuse/internal/dtos"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/internal/services"
+	"github.com/open-source-cloud/fuse/internal/workflow"
+)
+
+const (
+	// DiffSchemaVersionsHandlerName is the actor name for diffing two schema versions.
+	DiffSchemaVersionsHandlerName = "diff_schema_versions_handler"
+	// DiffSchemaVersionsHandlerPoolName is the worker pool for DiffSchemaVersionsHandler.
+	DiffSchemaVersionsHandlerPoolName = "diff_schema_versions_handler_pool"
+)
+
+type (
+	// DiffSchemaVersionsHandlerFactory creates DiffSchemaVersionsHandler actors.
+	DiffSchemaVersionsHandlerFactory HandlerFactory[*DiffSchemaVersionsHandler]
+	// DiffSchemaVersionsHandler serves GET /v1/schemas/{schemaID}/versions/{from}/diff/{to}.
+	DiffSchemaVersionsHandler struct {
+		Handler
+		graphService services.GraphService
+	}
+)
+
+// NewDiffSchemaVersionsHandlerFactory builds a factory for DiffSchemaVersionsHandler.
+func NewDiffSchemaVersionsHandlerFactory(graphService services.GraphService) *DiffSchemaVersionsHandlerFactory {
+	return &DiffSchemaVersionsHandlerFactory{
+		Factory: func() gen.ProcessBehavior {
+			return &DiffSchemaVersionsHandler{
+				graphService: graphService,
+			}
+		},
+	}
+}
+
+// HandleGet handles GET /v1/schemas/{schemaID}/versions/{from}/diff/{to}.
+// @Summary Diff two schema versions
+// @Description Structural diff between two versions of a workflow schema: nodes and edges added, removed or changed (including retry, timeout and merge config) and changed schema-level settings
+// @Tags schemas
+// @Accept json
+// @Produce json
+// @Param schemaID path string true "Schema ID"
+// @Param from path int true "Base version number"
+// @Param to path int true "Version number compared against the base"
+// @Success 200 {object} dtos.SchemaVersionDiffResponse
+// @Failure 400 {object} dtos.BadRequestError
+// @Failure 403 {object} dtos.ForbiddenError
+// @Failure 404 {object} dtos.NotFoundError
+// @Failure 500 {object} dtos.InternalServerErrorResponse
+// @Router /v1/schemas/{schemaID}/versions/{from}/diff/{to} [get]
+func (h *DiffSchemaVersionsHandler) HandleGet(from gen.PID, w http.ResponseWriter, r *http.Request) error {
+	h.Log().Info("received diff schema versions request", "from", from, "remoteAddr", r.RemoteAddr)
+
+	schemaID, err := h.SchemaIDParam(r)
+	if err != nil {
+		return h.SendBadRequest(w, err, []string{"schemaID is required"})
+	}
+	if err := h.Authorize(r, auth.PermSchemaRead, auth.NewSchemaResource(schemaID, "")); err != nil {
+		return h.SendForbidden(w, err)
+	}
+	fromVersion, err := h.versionParam(r, "from")
+	if err != nil {
+		return h.SendBadRequest(w, err, []string{"from"})
+	}
+	toVersion, err := h.versionParam(r, "to")
+	if err != nil {
+		return h.SendBadRequest(w, err, []string{"to"})
+	}
+
+	versions, err := h.graphService.ListVersions(schemaID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrGraphNotFound) {
+			return h.SendNotFound(w, "schema not found", EmptyFields)
+		}
+		return h.SendInternalError(w, err)
+	}
+
+	var base, target *workflow.SchemaVersion
+	for i := range versions {
+		switch versions[i].Version {
+		case fromVersion:
+			base = &versions[i]
+		case toVersion:
+			target = &versions[i]
+		}
+	}
+	if fromVersion == toVersion {
+		target = base
+	}
+	if base == nil {
+		return h.SendNotFound(w, fmt.Sprintf("schema version %d not found", fromVersion), []string{"from"})
+	}
+	if target == nil {
+		return h.SendNotFound(w, fmt.Sprintf("schema version %d not found", toVersion), []string{"to"})
+	}
+
+	diff := workflow.DiffSchemas(&base.Schema, &target.Schema)
+	return h.SendJSON(w, http.StatusOK, dtos.SchemaVersionDiffResponse{
+		SchemaID:    schemaID,
+		FromVersion: fromVersion,
+		ToVersion:   toVersion,
+		Identical:   diff.IsEmpty(),
+		Diff:        diff,
+	})
+}
+
+func (h *DiffSchemaVersionsHandler) versionParam(r *http.Request, name string) (int, error) {
+	raw, err := h.GetPathParam(r, name)
+	if err != nil {
+		return 0, err
+	}
+	version, err := strconv.Atoi(raw)
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("%s must be a positive integer", name)
+	}
+	return version, nil
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/auth"
 	"github.com/open-source-cloud/fuse/internal/dtos"
 	"github.com/open-source-cloud/fuse/internal/repositories"
 	"github.com/open-source-cloud/fuse/internal/services"
@@ -49,6 +50,7 @@ func NewEnvironmentHandler(environmentService services.EnvironmentService) *Envi
 // @Produce json
 // @Param name path string true "Environment name"
 // @Success 200 {object} dtos.EnvironmentDTO
+// @Failure 403 {object} dtos.ForbiddenError
 // @Failure 404 {object} dtos.NotFoundError
 // @Failure 500 {object} dtos.InternalServerErrorResponse
 // @Router /v1/environments/{name} [get]
@@ -60,6 +62,10 @@ func (h *EnvironmentHandler) HandleGet(from gen.PID, w http.ResponseWriter, r *h
 		return h.SendBadRequest(w, err, []string{"name is required"})
 	}
 
+	if err := h.Authorize(r, auth.PermEnvironmentRead, auth.Resource{Environment: name}); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
 	env, err := h.environmentService.FindByID(name)
 	if err != nil {
 		if errors.Is(err, repositories.ErrEnvironmentNotFound) {
@@ -81,6 +87,7 @@ func (h *EnvironmentHandler) HandleGet(from gen.PID, w http.ResponseWriter, r *h
 // @Param environment body dtos.EnvironmentDTO true "Environment data"
 // @Success 200 {object} dtos.UpsertEnvironmentResponse
 // @Failure 400 {object} dtos.BadRequestError
+// @Failure 403 {object} dtos.ForbiddenError
 // @Failure 500 {object} dtos.InternalServerErrorResponse
 // @Router /v1/environments/{name} [put]
 func (h *EnvironmentHandler) HandlePut(from gen.PID, w http.ResponseWriter, r *http.Request) error {
@@ -91,6 +98,10 @@ func (h *EnvironmentHandler) HandlePut(from gen.PID, w http.ResponseWriter, r *h
 		return h.SendBadRequest(w, err, []string{"name is required"})
 	}
 
+	if err := h.Authorize(r, auth.PermAdmin, auth.Resource{}); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
 	var dto dtos.EnvironmentDTO
 	if bindErr := h.BindJSON(w, r, &dto); bindErr != nil {
 		return h.SendBadRequest(w, bindErr, []string{"body"})
@@ -103,7 +114,7 @@ func (h *EnvironmentHandler) HandlePut(from gen.PID, w http.ResponseWriter, r *h
 		return h.SendBadRequest(w, validateErr, []string{"name"})
 	}
 
-	if _, saveErr := h.environmentService.Save(env); saveErr != nil {
+	if _, saveErr := h.environmentService.Save(r.Context(), env); saveErr != nil {
 		return h.SendInternalError(w, saveErr)
 	}
 
@@ -122,6 +133,7 @@ func (h *EnvironmentHandler) HandlePut(from gen.PID, w http.ResponseWriter, r *h
 // @Param name path string true "Environment name"
 // @Success 204 "No Content"
 // @Failure 400 {object} dtos.BadRequestError
+// @Failure 403 {object} dtos.ForbiddenError
 // @Failure 500 {object} dtos.InternalServerErrorResponse
 // @Router /v1/environments/{name} [delete]
 func (h *EnvironmentHandler) HandleDelete(from gen.PID, w http.ResponseWriter, r *http.Request) error {
@@ -132,11 +144,15 @@ func (h *EnvironmentHandler) HandleDelete(from gen.PID, w http.ResponseWriter, r
 		return h.SendBadRequest(w, err, []string{"name is required"})
 	}
 
+	if err := h.Authorize(r, auth.PermAdmin, auth.Resource{}); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
 	if name == workflow.DefaultEnvironmentName {
 		return h.SendBadRequest(w, fmt.Errorf("the default environment cannot be deleted"), []string{"name"})
 	}
 
-	if delErr := h.environmentService.Delete(name); delErr != nil {
+	if delErr := h.environmentService.Delete(r.Context(), name); delErr != nil {
 		return h.SendInternalError(w, delErr)
 	}
 
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/auth"
+	"github.com/open-source-cloud/fuse/internal/dtos"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/internal/services"
+)
+
+const (
+	// EnvironmentVarHandlerName is the name of the single environment variable handler.
+	EnvironmentVarHandlerName = "environment_var_handler"
+	// EnvironmentVarHandlerPoolName is the name of the single environment variable handler pool.
+	EnvironmentVarHandlerPoolName = "environment_var_handler_pool"
+)
+
+type (
+	// EnvironmentVarHandlerFactory is the factory for the single environment variable handler.
+	EnvironmentVarHandlerFactory HandlerFactory[*EnvironmentVarHandler]
+
+	// EnvironmentVarHandler handles a single variable of an environment.
+	EnvironmentVarHandler struct {
+		Handler
+		envVarService services.EnvironmentVarService
+	}
+)
+
+// NewEnvironmentVarHandler creates a new single environment variable handler factory.
+func NewEnvironmentVarHandler(envVarService services.EnvironmentVarService) *EnvironmentVarHandlerFactory {
+	return &EnvironmentVarHandlerFactory{
+		Factory: func() gen.ProcessBehavior {
+			return &EnvironmentVarHandler{envVarService: envVarService}
+		},
+	}
+}
+
+// pathParams returns the environment and variable names of the request.
+func (h *EnvironmentVarHandler) pathParams(r *http.Request) (string, string, error) {
+	environment, err := h.GetPathParam(r, "name")
+	if err != nil {
+		return "", "", err
+	}
+	name, err := h.GetPathParam(r, "var")
+	if err != nil {
+		return "", "", err
+	}
+	return environment, name, nil
+}
+
+// HandleGet retrieves a single variable (GET /v1/environments/{name}/vars/{var})
+// @Summary Get environment variable
+// @Description Retrieve a non-secret variable of an environment in the request's namespace
+// @Tags environments
+// @Accept json
+// @Produce json
+// @Param name path string true "Environment name"
+// @Param var path string true "Variable name"
+// @Success 200 {object} dtos.EnvironmentVarDTO
+// @Failure 403 {object} dtos.ForbiddenError
+// @Failure 404 {object} dtos.NotFoundError
+// @Failure 500 {object} dtos.InternalServerErrorResponse
+// @Router /v1/environments/{name}/vars/{var} [get]
+func (h *EnvironmentVarHandler) HandleGet(from gen.PID, w http.ResponseWriter, r *http.Request) error {
+	h.Log().Info("received get environment var request from: %v remoteAddr: %s", from, r.RemoteAddr)
+
+	environment, name, err := h.pathParams(r)
+	if err != nil {
+		return h.SendBadRequest(w, err, []string{"name and var are required"})
+	}
+
+	namespace := h.Namespace(r)
+	if err := h.Authorize(r, auth.PermVarRead, auth.Resource{Namespace: namespace, Environment: environment}); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
+	vars, err := h.envVarService.FindAll(namespace, environment)
+	if err != nil {
+		return h.SendInternalError(w, err)
+	}
+	value, ok := vars[name]
+	if !ok {
+		return h.SendNotFound(w, fmt.Sprintf("variable %s not found in environment %s", name, environment), []string{"var"})
+	}
+
+	return h.SendJSON(w, http.StatusOK, dtos.EnvironmentVarDTO{Name: name, Value: value})
+}
+
+// HandlePut creates or updates a variable (PUT /v1/environments/{name}/vars/{var})
+// @Summary Set environment variable
+// @Description Upsert a non-secret variable of a declared environment in the request's namespace; running executions keep the values they were triggered with
+// @Tags environments
+// @Accept json
+// @Produce json
+// @Param name path string true "Environment name"
+// @Param var path string true "Variable name"
+// @Param variable body dtos.SetEnvironmentVarRequest true "Variable value"
+// @Success 200 {object} dtos.EnvironmentVarDTO
+// @Failure 400 {object} dtos.BadRequestError
+// @Failure 403 {object} dtos.ForbiddenError
+// @Failure 404 {object} dtos.NotFoundError
+// @Failure 500 {object} dtos.InternalServerErrorResponse
+// @Router /v1/environments/{name}/vars/{var} [put]
+func (h *EnvironmentVarHandler) HandlePut(from gen.PID, w http.ResponseWriter, r *http.Request) error {
+	h.Log().Info("received set environment var request from: %v remoteAddr: %s", from, r.RemoteAddr)
+
+	environment, name, err := h.pathParams(r)
+	if err != nil {
+		return h.SendBadRequest(w, err, []string{"name and var are required"})
+	}
+
+	namespace := h.Namespace(r)
+	if err := h.Authorize(r, auth.PermVarWrite, auth.Resource{Namespace: namespace, Environment: environment}); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
+	var req dtos.SetEnvironmentVarRequest
+	if bindErr := h.BindJSON(w, r, &req); bindErr != nil {
+		return h.SendBadRequest(w, bindErr, []string{"body"})
+	}
+
+	if setErr := h.envVarService.Set(r.Context(), namespace, environment, name, req.Value); setErr != nil {
+		if errors.Is(setErr, repositories.ErrEnvironmentNotFound) {
+			return h.SendNotFound(w, fmt.Sprintf("environment %s not found", environment), []string{"name"})
+		}
+		return h.SendBadRequest(w, setErr, []string{"var"})
+	}
+
+	return h.SendJSON(w, http.StatusOK, dtos.EnvironmentVarDTO{Name: name, Value: req.Value})
+}
+
+// HandleDelete removes a variable (DELETE /v1/environments/{name}/vars/{var})
+// @Summary Delete environment variable
+// @Description Delete a variable of an environment in the request's namespace
+// @Tags environments
+// @Accept json
+// @Produce json
+// @Param name path string true "Environment name"
+// @Param var path string true "Variable name"
+// @Success 204 "No Content"
+// @Failure 403 {object} dtos.ForbiddenError
+// @Failure 500 {object} dtos.InternalServerErrorResponse
+// @Router /v1/environments/{name}/vars/{var} [delete]
+func (h *EnvironmentVarHandler) HandleDelete(from gen.PID, w http.ResponseWriter, r *http.Request) error {
+	h.Log().Info("received delete environment var request from: %v remoteAddr: %s", from, r.RemoteAddr)
+
+	environment, name, err := h.pathParams(r)
+	if err != nil {
+		return h.SendBadRequest(w, err, []string{"name and var are required"})
+	}
+
+	namespace := h.Namespace(r)
+	if err := h.Authorize(r, auth.PermVarWrite, auth.Resource{Namespace: namespace, Environment: environment}); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
+	if delErr := h.envVarService.Delete(r.Context(), namespace, environment, name); delErr != nil {
+		return h.SendInternalError(w, delErr)
+	}
+
+	return h.SendJSON(w, http.StatusNoContent, nil)
+}
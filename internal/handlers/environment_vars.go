@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"net/http"
+
+	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/auth"
+	"github.com/open-source-cloud/fuse/internal/dtos"
+	"github.com/open-source-cloud/fuse/internal/services"
+)
+
+const (
+	// EnvironmentVarsHandlerName is the name of the environment variables list handler.
+	EnvironmentVarsHandlerName = "environment_vars_handler"
+	// EnvironmentVarsHandlerPoolName is the name of the environment variables list handler pool.
+	EnvironmentVarsHandlerPoolName = "environment_vars_handler_pool"
+)
+
+type (
+	// EnvironmentVarsHandlerFactory is the factory for the environment variables list handler.
+	EnvironmentVarsHandlerFactory HandlerFactory[*EnvironmentVarsHandler]
+
+	// EnvironmentVarsHandler handles the variables collection of an environment.
+	EnvironmentVarsHandler struct {
+		Handler
+		envVarService services.EnvironmentVarService
+	}
+)
+
+// NewEnvironmentVarsHandler creates a new environment variables list handler factory.
+func NewEnvironmentVarsHandler(envVarService services.EnvironmentVarService) *EnvironmentVarsHandlerFactory {
+	return &EnvironmentVarsHandlerFactory{
+		Factory: func() gen.ProcessBehavior {
+			return &EnvironmentVarsHandler{envVarService: envVarService}
+		},
+	}
+}
+
+// HandleGet lists the variables of an environment (GET /v1/environments/{name}/vars)
+// @Summary List environment variables
+// @Description Retrieve the non-secret variables of an environment in the request's namespace
+// @Tags environments
+// @Accept json
+// @Produce json
+// @Param name path string true "Environment name"
+// @Success 200 {object} dtos.EnvironmentVarsResponse
+// @Failure 403 {object} dtos.ForbiddenError
+// @Failure 500 {object} dtos.InternalServerErrorResponse
+// @Router /v1/environments/{name}/vars [get]
+func (h *EnvironmentVarsHandler) HandleGet(from gen.PID, w http.ResponseWriter, r *http.Request) error {
+	h.Log().Info("received list environment vars request from: %v remoteAddr: %s", from, r.RemoteAddr)
+
+	name, err := h.GetPathParam(r, "name")
+	if err != nil {
+		return h.SendBadRequest(w, err, []string{"name is required"})
+	}
+
+	namespace := h.Namespace(r)
+	if err := h.Authorize(r, auth.PermVarRead, auth.Resource{Namespace: namespace, Environment: name}); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
+	vars, err := h.envVarService.FindAll(namespace, name)
+	if err != nil {
+		return h.SendInternalError(w, err)
+	}
+
+	return h.SendJSON(w, http.StatusOK, dtos.EnvironmentVarsResponse{Environment: name, Vars: vars})
+}
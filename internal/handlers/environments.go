@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/auth"
 	"github.com/open-source-cloud/fuse/internal/dtos"
 	"github.com/open-source-cloud/fuse/internal/services"
 )
@@ -54,9 +55,15 @@ func (h *EnvironmentsHandler) HandleGet(from gen.PID, w http.ResponseWriter, r *
 		return h.SendInternalError(w, err)
 	}
 
-	items := make([]dtos.EnvironmentDTO, len(envs))
-	for i, env := range envs {
-		items[i] = dtos.ToEnvironmentDTO(env)
+	items := make([]dtos.EnvironmentDTO, 0, len(envs))
+	for _, env := range envs {
+		if ok, err := h.allowed(r, auth.PermEnvironmentRead, auth.Resource{Environment: env.Name}); !ok {
+			if err != nil {
+				return h.SendInternalError(w, err)
+			}
+			continue
+		}
+		items = append(items, dtos.ToEnvironmentDTO(env))
 	}
 
 	return h.SendJSON(w, http.StatusOK, dtos.EnvironmentListResponse{Items: items})
@@ -0,0 +1,250 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/open-source-cloud/fuse/internal/auth"
+	"github.com/open-source-cloud/fuse/internal/dtos"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/internal/streams"
+	internalworkflow "github.com/open-source-cloud/fuse/internal/workflow"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+)
+
+// executionStreamKeepAlive is how often an idle stream sends an SSE comment so proxies do not
+// close the connection.
+const executionStreamKeepAlive = 15 * time.Second
+
+// ExecutionStreamHandler serves the live output of a node execution as server-sent events.
+// Unlike the other handlers it is a plain http.Handler rather than an actor worker: a stream stays
+// open for as long as the node runs, past any worker pool request timeout.
+type ExecutionStreamHandler struct {
+	broker       *streams.Broker
+	workflowRepo repositories.WorkflowRepository
+	journalRepo  repositories.JournalRepository
+	keepAlive    time.Duration
+}
+
+// NewExecutionStreamHandler creates a new ExecutionStreamHandler
+func NewExecutionStreamHandler(
+	broker *streams.Broker,
+	workflowRepo repositories.WorkflowRepository,
+	journalRepo repositories.JournalRepository,
+) *ExecutionStreamHandler {
+	return &ExecutionStreamHandler{broker: broker, workflowRepo: workflowRepo, journalRepo: journalRepo, keepAlive: executionStreamKeepAlive}
+}
+
+// ServeHTTP handles the execution stream endpoint (GET /v1/workflows/{workflowID}/execs/{execID}/stream)
+// @Summary Stream node output
+// @Description Streams the output of an ai/chat or ai/agent node execution as server-sent events
+// @Description while it runs: "delta" events carry text ({"content"}), "step" events mark an agent
+// @Description iteration that called tools ({"iteration","tools"}), and a final "done" event carries
+// @Description the node's status. Send Last-Event-ID to resume after a disconnect. Streams are held
+// @Description by the node running the execution and kept for a minute after the node finishes; the
+// @Description journaled node output remains the durable result. An execution that already ended, or
+// @Description whose stream is held by another node, gets its "done" event once the journal records
+// @Description the end.
+// @Tags workflows
+// @Produce text/event-stream
+// @Param workflowID path string true "Workflow ID"
+// @Param execID path string true "Execution ID of the node"
+// @Param Last-Event-ID header string false "Resume after this event ID"
+// @Success 200 {string} string "Server-sent events"
+// @Failure 403 {object} dtos.ForbiddenError
+// @Failure 404 {object} dtos.NotFoundError
+// @Router /v1/workflows/{workflowID}/execs/{execID}/stream [get]
+func (h *ExecutionStreamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	workflowID, execID := vars["workflowID"], vars["execID"]
+	wf, err := h.workflowRepo.Get(workflowID)
+	if err != nil || wf.Namespace() != requestNamespace(r) {
+		writeStreamError(w, http.StatusNotFound, dtos.ErrorResponse{Message: "workflow not found", Code: EntityNotFound, Fields: []string{"workflowID"}})
+		return
+	}
+	if err := auth.Check(r.Context(), auth.PermWorkflowRead, workflowResource(wf)); err != nil {
+		if !errors.Is(err, auth.ErrForbidden) {
+			writeStreamError(w, http.StatusInternalServerError, dtos.ErrorResponse{Message: err.Error(), Code: InternalServerError, Fields: EmptyFields})
+			return
+		}
+		writeStreamError(w, http.StatusForbidden, dtos.ErrorResponse{Message: err.Error(), Code: Forbidden, Fields: EmptyFields})
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeStreamError(w, http.StatusInternalServerError, dtos.ErrorResponse{Message: "streaming is not supported", Code: InternalServerError, Fields: EmptyFields})
+		return
+	}
+	known, status, err := h.execStatus(wf, execID)
+	if err != nil {
+		writeStreamError(w, http.StatusInternalServerError, dtos.ErrorResponse{Message: err.Error(), Code: InternalServerError, Fields: EmptyFields})
+		return
+	}
+	if !known {
+		writeStreamError(w, http.StatusNotFound, dtos.ErrorResponse{Message: "execution not found", Code: EntityNotFound, Fields: []string{"execID"}})
+		return
+	}
+	after, _ := strconv.ParseUint(r.Header.Get("Last-Event-ID"), 10, 64)
+	out := &streamWriter{w: w, flusher: flusher, lastID: after}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	// an ended execution is answered from what this node still retains, so nothing waits for a
+	// stream that will never be published here
+	if status != "" {
+		if out.write(h.broker.Retained(workflowID, execID, after)...) == nil {
+			out.finish(status)
+		}
+		return
+	}
+
+	backlog, sub := h.broker.Subscribe(workflowID, execID, after)
+	defer sub.Cancel()
+	if out.write(backlog...) != nil {
+		return
+	}
+	out.flusher.Flush()
+
+	keepAlive := time.NewTicker(h.keepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e, ok := <-sub.Events():
+			if !ok {
+				return
+			}
+			if out.write(e) != nil {
+				return
+			}
+		case <-keepAlive.C:
+			// the stream may be held by another node, or its node may have stopped: the journal
+			// tells when the execution ended
+			if status := h.endedStatus(workflowID, execID); status != "" {
+				out.drain(sub)
+				out.finish(status)
+				return
+			}
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		}
+		out.flusher.Flush()
+	}
+}
+
+// execStatus reads from the workflow's journal whether it ran execID and, once the execution ended,
+// the status it ended with; the status is empty while it runs. An execution cut short by the end of
+// its workflow ended with an error.
+func (h *ExecutionStreamHandler) execStatus(wf *internalworkflow.Workflow, execID string) (bool, string, error) {
+	entries, err := h.journalRepo.LoadAll(wf.ID().String())
+	if err != nil {
+		return false, "", err
+	}
+	known, status := false, ""
+	for _, entry := range entries {
+		if entry.ExecID != execID {
+			continue
+		}
+		switch entry.Type {
+		case internalworkflow.JournalStepStarted, internalworkflow.JournalToolCallStarted, internalworkflow.JournalStepRetrying:
+			known, status = true, ""
+		case internalworkflow.JournalStepCompleted, internalworkflow.JournalStepFailed, internalworkflow.JournalToolCallCompleted:
+			known, status = true, ""
+			if entry.Result == nil || !entry.Result.Async {
+				status = string(workflow.FunctionSuccess)
+				if entry.Type == internalworkflow.JournalStepFailed ||
+					(entry.Result != nil && entry.Result.Output.Status == workflow.FunctionError) {
+					status = string(workflow.FunctionError)
+				}
+			}
+		}
+	}
+	if known && status == "" && !wf.State().IsActive() {
+		status = string(workflow.FunctionError)
+	}
+	return known, status, nil
+}
+
+// endedStatus re-reads the workflow and returns the status its execution execID ended with, or ""
+// while it runs or when the workflow cannot be read.
+func (h *ExecutionStreamHandler) endedStatus(workflowID, execID string) string {
+	wf, err := h.workflowRepo.Get(workflowID)
+	if err != nil {
+		return ""
+	}
+	_, status, err := h.execStatus(wf, execID)
+	if err != nil {
+		return ""
+	}
+	return status
+}
+
+// streamWriter writes the events of one stream response, remembering the last event ID sent so a
+// synthesised "done" event continues the sequence.
+type streamWriter struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+	lastID  uint64
+	done    bool
+}
+
+func (s *streamWriter) write(events ...streams.Event) error {
+	for _, e := range events {
+		if err := writeStreamEvent(s.w, e); err != nil {
+			return err
+		}
+		s.lastID = max(s.lastID, e.ID)
+		s.done = s.done || e.Type == streams.EventDone
+	}
+	return nil
+}
+
+// drain writes the live events already delivered to sub.
+func (s *streamWriter) drain(sub *streams.Subscription) {
+	for {
+		select {
+		case e, ok := <-sub.Events():
+			if !ok || s.write(e) != nil {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+// finish ends the response with a "done" event carrying status, unless the stream already sent one.
+func (s *streamWriter) finish(status string) {
+	if !s.done {
+		_ = s.write(streams.Event{ID: s.lastID + 1, Type: streams.EventDone, Data: map[string]any{"status": status}})
+	}
+	s.flusher.Flush()
+}
+
+// writeStreamEvent writes e in the SSE wire format.
+func writeStreamEvent(w http.ResponseWriter, e streams.Event) error {
+	data, err := json.Marshal(e.Data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+	return err
+}
+
+// writeStreamError answers a request that cannot be streamed with a JSON error.
+func writeStreamError(w http.ResponseWriter, status int, body dtos.ErrorResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-source-cloud/fuse/internal/auth"
+	"github.com/open-source-cloud/fuse/internal/mocks"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/internal/streams"
+	internalworkflow "github.com/open-source-cloud/fuse/internal/workflow"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+)
+
+// executionStreamFixture serves the stream of a workflow whose journal has started exec-1.
+type executionStreamFixture struct {
+	srv     *httptest.Server
+	wfID    string
+	handler *ExecutionStreamHandler
+	journal repositories.JournalRepository
+}
+
+func newExecutionStreamFixture(t *testing.T, broker *streams.Broker, middlewares ...mux.MiddlewareFunc) *executionStreamFixture {
+	t.Helper()
+	repo := repositories.NewMemoryWorkflowRepository()
+	graph, err := internalworkflow.NewGraph(mocks.SmallTestGraphSchema())
+	require.NoError(t, err)
+	wfID := workflow.NewID()
+	require.NoError(t, repo.Save(internalworkflow.New(wfID, graph, workflow.DefaultEnvironmentName)))
+	journal := repositories.NewMemoryJournalRepository()
+	require.NoError(t, journal.Append(wfID.String(), internalworkflow.JournalEntry{Type: internalworkflow.JournalStepStarted, ExecID: "exec-1"}))
+
+	router := mux.NewRouter()
+	handler := NewExecutionStreamHandler(broker, repo, journal)
+	router.Handle("/v1/workflows/{workflowID}/execs/{execID}/stream", handler)
+	router.Handle("/v1/ns/{ns}/workflows/{workflowID}/execs/{execID}/stream", handler)
+	router.Use(middlewares...)
+	srv := httptest.NewServer(router)
+	t.Cleanup(srv.Close)
+	return &executionStreamFixture{srv: srv, wfID: wfID.String(), handler: handler, journal: journal}
+}
+
+func newExecutionStreamServer(t *testing.T, broker *streams.Broker, middlewares ...mux.MiddlewareFunc) (*httptest.Server, string) {
+	t.Helper()
+	f := newExecutionStreamFixture(t, broker, middlewares...)
+	return f.srv, f.wfID
+}
+
+// completeExec journals the end of exec-1.
+func (f *executionStreamFixture) completeExec(t *testing.T) {
+	t.Helper()
+	require.NoError(t, f.journal.Append(f.wfID, internalworkflow.JournalEntry{
+		Type:   internalworkflow.JournalStepCompleted,
+		ExecID: "exec-1",
+		Result: &workflow.FunctionResult{Output: workflow.NewFunctionSuccessOutput(nil)},
+	}))
+}
+
+func TestExecutionStreamHandler_StreamsEventsUntilDone(t *testing.T) {
+	broker := streams.NewBroker(time.Minute)
+	srv, wfID := newExecutionStreamServer(t, broker)
+	broker.Publish(wfID, "exec-1", streams.Event{Type: streams.EventDelta, Data: map[string]any{"content": "Hel"}})
+
+	res, err := http.Get(srv.URL + "/v1/workflows/" + wfID + "/execs/exec-1/stream")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(res.Body)
+	assert.Equal(t, "id: 1\nevent: delta\ndata: {\"content\":\"Hel\"}\n\n", readEvent(t, reader))
+
+	broker.Publish(wfID, "exec-1", streams.Event{Type: streams.EventDelta, Data: map[string]any{"content": "lo"}})
+	broker.Publish(wfID, "exec-1", streams.Event{Type: streams.EventDone, Data: map[string]any{"status": "success"}})
+	broker.Close(wfID, "exec-1")
+
+	assert.Equal(t, "id: 2\nevent: delta\ndata: {\"content\":\"lo\"}\n\n", readEvent(t, reader))
+	assert.Equal(t, "id: 3\nevent: done\ndata: {\"status\":\"success\"}\n\n", readEvent(t, reader))
+	rest, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Empty(t, rest, "the response ends with the stream")
+}
+
+func TestExecutionStreamHandler_ResumesFromLastEventID(t *testing.T) {
+	broker := streams.NewBroker(time.Minute)
+	srv, wfID := newExecutionStreamServer(t, broker)
+	broker.Publish(wfID, "exec-1", streams.Event{Type: streams.EventDelta, Data: map[string]any{"content": "a"}})
+	broker.Publish(wfID, "exec-1", streams.Event{Type: streams.EventDone, Data: map[string]any{"status": "success"}})
+	broker.Close(wfID, "exec-1")
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/v1/workflows/"+wfID+"/execs/exec-1/stream", nil)
+	require.NoError(t, err)
+	req.Header.Set("Last-Event-ID", "1")
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "id: 2\nevent: done\ndata: {\"status\":\"success\"}\n\n", string(body))
+}
+
+func TestExecutionStreamHandler_UnknownWorkflowIs404(t *testing.T) {
+	srv, _ := newExecutionStreamServer(t, streams.NewBroker(time.Minute))
+
+	res, err := http.Get(srv.URL + "/v1/workflows/unknown/execs/exec-1/stream")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+}
+
+func TestExecutionStreamHandler_OtherNamespaceIs404(t *testing.T) {
+	srv, wfID := newExecutionStreamServer(t, streams.NewBroker(time.Minute))
+
+	res, err := http.Get(srv.URL + "/v1/ns/billing/workflows/" + wfID + "/execs/exec-1/stream")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusNotFound, res.StatusCode, "executions are only found in their own namespace")
+}
+
+func TestExecutionStreamHandler_UnknownExecIs404(t *testing.T) {
+	broker := streams.NewBroker(time.Minute)
+	srv, wfID := newExecutionStreamServer(t, broker)
+
+	res, err := http.Get(srv.URL + "/v1/workflows/" + wfID + "/execs/exec-2/stream")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusNotFound, res.StatusCode, "only executions the workflow ran are streamed")
+	assert.Empty(t, broker.Retained(wfID, "exec-2", 0))
+}
+
+func TestExecutionStreamHandler_EndedExecGetsDoneAtOnce(t *testing.T) {
+	f := newExecutionStreamFixture(t, streams.NewBroker(time.Minute))
+	f.completeExec(t)
+
+	res, err := http.Get(f.srv.URL + "/v1/workflows/" + f.wfID + "/execs/exec-1/stream")
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "id: 1\nevent: done\ndata: {\"status\":\"success\"}\n\n", string(body),
+		"a stream no longer retained ends with the journaled status")
+}
+
+func TestExecutionStreamHandler_EndsWhenTheJournalRecordsTheEnd(t *testing.T) {
+	f := newExecutionStreamFixture(t, streams.NewBroker(time.Minute))
+	f.handler.keepAlive = 10 * time.Millisecond
+
+	res, err := http.Get(f.srv.URL + "/v1/workflows/" + f.wfID + "/execs/exec-1/stream")
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	// the execution runs on another node, so nothing is published here
+	f.completeExec(t)
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(string(body), "id: 1\nevent: done\ndata: {\"status\":\"success\"}\n\n"), string(body))
+}
+
+type denyAuthorizer struct{}
+
+func (denyAuthorizer) Authorize(_ *auth.Principal, perm auth.Permission, _ auth.Resource) error {
+	return fmt.Errorf("%w: lacks %s", auth.ErrForbidden, perm)
+}
+
+func TestExecutionStreamHandler_RequiresWorkflowRead(t *testing.T) {
+	deny := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := auth.WithAuthorizer(auth.WithPrincipal(r.Context(), &auth.Principal{Subject: "apikey:a"}), denyAuthorizer{})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+	srv, wfID := newExecutionStreamServer(t, streams.NewBroker(time.Minute), deny)
+
+	res, err := http.Get(srv.URL + "/v1/workflows/" + wfID + "/execs/exec-1/stream")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusForbidden, res.StatusCode)
+}
+
+// readEvent reads one SSE event, up to and including its blank terminator line.
+func readEvent(t *testing.T, r *bufio.Reader) string {
+	t.Helper()
+	var b strings.Builder
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		b.WriteString(line)
+		if line == "\n" {
+			return b.String()
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/auth"
 	"github.com/open-source-cloud/fuse/internal/dtos"
 	"github.com/open-source-cloud/fuse/internal/repositories"
 	"github.com/open-source-cloud/fuse/internal/services"
@@ -49,16 +50,20 @@ func NewGetSchemaVersionHandlerFactory(graphService services.GraphService) *GetS
 // @Param version path int true "Version number"
 // @Success 200 {object} dtos.SchemaVersionResponse
 // @Failure 400 {object} dtos.BadRequestError
+// @Failure 403 {object} dtos.ForbiddenError
 // @Failure 404 {object} dtos.NotFoundError
 // @Failure 500 {object} dtos.InternalServerErrorResponse
 // @Router /v1/schemas/{schemaID}/versions/{version} [get]
 func (h *GetSchemaVersionHandler) HandleGet(from gen.PID, w http.ResponseWriter, r *http.Request) error {
 	h.Log().Info("received get schema version request", "from", from, "remoteAddr", r.RemoteAddr)
 
-	schemaID, err := h.GetPathParam(r, "schemaID")
+	schemaID, err := h.SchemaIDParam(r)
 	if err != nil {
 		return h.SendBadRequest(w, err, []string{"schemaID is required"})
 	}
+	if err := h.Authorize(r, auth.PermSchemaRead, auth.NewSchemaResource(schemaID, "")); err != nil {
+		return h.SendForbidden(w, err)
+	}
 
 	versionStr, err := h.GetPathParam(r, "version")
 	if err != nil {
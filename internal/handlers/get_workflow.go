@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/auth"
 	"github.com/open-source-cloud/fuse/internal/dtos"
 	"github.com/open-source-cloud/fuse/internal/repositories"
 )
@@ -44,6 +45,7 @@ func NewGetWorkflowHandlerFactory(workflowRepo repositories.WorkflowRepository)
 // @Param workflowID path string true "Workflow ID"
 // @Success 200 {object} dtos.GetWorkflowResponse
 // @Failure 400 {object} dtos.BadRequestError
+// @Failure 403 {object} dtos.ForbiddenError
 // @Failure 404 {object} dtos.NotFoundError
 // @Router /v1/workflows/{workflowID} [get]
 func (h *GetWorkflowHandler) HandleGet(_ gen.PID, w http.ResponseWriter, r *http.Request) error {
@@ -53,9 +55,12 @@ func (h *GetWorkflowHandler) HandleGet(_ gen.PID, w http.ResponseWriter, r *http
 	}
 
 	wf, err := h.workflowRepo.Get(workflowID)
-	if err != nil {
+	if err != nil || !h.InNamespace(r, wf) {
 		return h.SendNotFound(w, "workflow not found", EmptyFields)
 	}
+	if err := h.Authorize(r, auth.PermWorkflowRead, workflowResource(wf)); err != nil {
+		return h.SendForbidden(w, err)
+	}
 
 	return h.SendJSON(w, http.StatusOK, dtos.GetWorkflowResponse{
 		WorkflowID: workflowID,
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/auth"
 	internalworkflow "github.com/open-source-cloud/fuse/internal/workflow"
 
 	"github.com/open-source-cloud/fuse/internal/repositories"
@@ -54,6 +55,7 @@ func NewGetWorkflowSnapshotHandlerFactory(
 // @Produce json
 // @Param workflowID path string true "Workflow ID"
 // @Success 200 {object} internalworkflow.ExecutionSnapshot
+// @Failure 403 {object} dtos.ForbiddenError
 // @Failure 404 {object} dtos.NotFoundError
 // @Router /v1/workflows/{workflowID}/snapshot [get]
 func (h *GetWorkflowSnapshotHandler) HandleGet(_ gen.PID, w http.ResponseWriter, r *http.Request) error {
@@ -64,9 +66,12 @@ func (h *GetWorkflowSnapshotHandler) HandleGet(_ gen.PID, w http.ResponseWriter,
 
 	// Check if workflow exists
 	wf, err := h.workflowRepo.Get(workflowID)
-	if err != nil {
+	if err != nil || !h.InNamespace(r, wf) {
 		return h.SendNotFound(w, "workflow not found", EmptyFields)
 	}
+	if err := h.Authorize(r, auth.PermWorkflowRead, workflowResource(wf)); err != nil {
+		return h.SendForbidden(w, err)
+	}
 
 	// Try to serve persisted snapshot first
 	snapshotRef, _ := h.workflowRepo.GetSnapshotRef(workflowID)
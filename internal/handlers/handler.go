@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 
@@ -13,7 +14,13 @@ import (
 	"ergo.services/ergo/gen"
 	"github.com/gorilla/mux"
 
+	"github.com/open-source-cloud/fuse/internal/audit"
+	"github.com/open-source-cloud/fuse/internal/auth"
 	"github.com/open-source-cloud/fuse/internal/dtos"
+	"github.com/open-source-cloud/fuse/internal/services"
+	internalworkflow "github.com/open-source-cloud/fuse/internal/workflow"
+	"github.com/open-source-cloud/fuse/pkg/transport"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
 )
 
 var (
@@ -23,6 +30,8 @@ var (
 	ErrQueryParamEmpty = errors.New("query param is empty")
 	// ErrPathParamNotFound is returned when a path param is not found
 	ErrPathParamNotFound = errors.New("path param not found")
+	// ErrQualifiedIDParam is returned when a schema or package path param already carries a namespace
+	ErrQualifiedIDParam = errors.New("IDs must not contain the namespace separator \"" + workflow.NamespaceSeparator + "\"")
 )
 
 const (
@@ -32,6 +41,12 @@ const (
 	InternalServerError string = "INTERNAL_SERVER_ERROR"
 	// EntityNotFound is the error code for a resource not found
 	EntityNotFound string = "ENTITY_NOT_FOUND"
+	// Conflict is the error code for requests that conflict with the current state of a resource
+	Conflict string = "CONFLICT"
+	// Forbidden is the error code for callers lacking a permission on a resource
+	Forbidden string = "FORBIDDEN"
+	// QuotaExceeded is the error code for requests that would exceed a namespace quota
+	QuotaExceeded string = "QUOTA_EXCEEDED"
 )
 
 // EmptyFields use it when you want to send empty fields to the client
@@ -102,6 +117,44 @@ func (h *Handler) GetPathParam(r *http.Request, key string) (string, error) {
 	return value, nil
 }
 
+// Namespace returns the namespace a request addresses: the {ns} path param of /v1/ns/{ns}/...
+// routes, or the default namespace for the unprefixed /v1 routes
+func (h *Handler) Namespace(r *http.Request) string {
+	return requestNamespace(r)
+}
+
+func requestNamespace(r *http.Request) string {
+	if ns, ok := mux.Vars(r)["ns"]; ok && ns != "" {
+		return ns
+	}
+	return workflow.DefaultNamespaceName
+}
+
+// InNamespace reports whether an execution belongs to the namespace the request addresses.
+// Executions of other namespaces are answered as not found, like their schemas
+func (h *Handler) InNamespace(r *http.Request, wf *internalworkflow.Workflow) bool {
+	return wf.Namespace() == requestNamespace(r)
+}
+
+// QualifyID qualifies a schema or package ID taken from the request with the request's namespace.
+// IDs that already contain the namespace separator are rejected, so a route can never reach
+// another namespace's definitions.
+func (h *Handler) QualifyID(r *http.Request, id string) (string, error) {
+	if strings.Contains(id, workflow.NamespaceSeparator) {
+		return "", fmt.Errorf("%w: %s", ErrQualifiedIDParam, id)
+	}
+	return workflow.QualifyID(h.Namespace(r), id), nil
+}
+
+// SchemaIDParam returns the {schemaID} path param qualified with the request's namespace
+func (h *Handler) SchemaIDParam(r *http.Request) (string, error) {
+	schemaID, err := h.GetPathParam(r, "schemaID")
+	if err != nil {
+		return "", err
+	}
+	return h.QualifyID(r, schemaID)
+}
+
 // SendInternalError sends 500 status code to client
 func (h *Handler) SendInternalError(w http.ResponseWriter, err error) error {
 	h.Log().Error("sending internal error to client", "error", err)
@@ -132,6 +185,100 @@ func (h *Handler) SendNotFound(w http.ResponseWriter, msg string, fields []strin
 	})
 }
 
+// Authorize checks that the request principal holds perm on resource (see auth.Check)
+func (h *Handler) Authorize(r *http.Request, perm auth.Permission, resource auth.Resource) error {
+	return auth.Check(r.Context(), perm, resource)
+}
+
+// allowed reports whether the request principal holds perm on resource, for filtering listings;
+// a denial is not an error, a failed policy lookup is
+func (h *Handler) allowed(r *http.Request, perm auth.Permission, resource auth.Resource) (bool, error) {
+	err := h.Authorize(r, perm, resource)
+	if errors.Is(err, auth.ErrForbidden) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// workflowResource is the authorization resource of an execution: its namespace, schema and
+// environment
+func workflowResource(wf *internalworkflow.Workflow) auth.Resource {
+	return auth.NewSchemaResource(wf.Graph().ID(), wf.Environment())
+}
+
+// workflowAuditEntry starts the audit entry of an administrative action on an execution; wf is nil
+// when the execution could not be loaded
+func workflowAuditEntry(action audit.Action, workflowID string, wf *internalworkflow.Workflow) audit.Entry {
+	entry := audit.Entry{Action: action, ResourceType: audit.ResourceWorkflow, Resource: workflowID, Details: map[string]string{}}
+	if wf != nil {
+		entry.Namespace = wf.Namespace()
+		entry.Environment = wf.Environment()
+		entry.Details["schemaId"] = wf.Graph().ID()
+	}
+	return entry
+}
+
+// SendForbidden sends 403 status code to client when err is an auth.ErrForbidden denial and 500
+// when authorization itself failed
+func (h *Handler) SendForbidden(w http.ResponseWriter, err error) error {
+	if !errors.Is(err, auth.ErrForbidden) {
+		return h.SendInternalError(w, err)
+	}
+	h.Log().Warning("sending forbidden to client", "error", err)
+	return h.SendJSON(w, http.StatusForbidden, dtos.ForbiddenError{
+		Message: err.Error(),
+		Code:    Forbidden,
+		Fields:  EmptyFields,
+	})
+}
+
+// SendConflict sends 409 status code to client
+func (h *Handler) SendConflict(w http.ResponseWriter, err error, fields []string) error {
+	h.Log().Error("sending conflict to client", "error", err)
+	return h.SendJSON(w, http.StatusConflict, dtos.ConflictError{
+		Message: err.Error(),
+		Code:    Conflict,
+		Fields:  fields,
+	})
+}
+
+// CallbackToken returns the callback token of an async result or awakeable resolution request,
+// from the transport.CallbackTokenHeader header or else the "token" query param
+func (h *Handler) CallbackToken(r *http.Request) string {
+	if token := r.Header.Get(transport.CallbackTokenHeader); token != "" {
+		return token
+	}
+	return r.URL.Query().Get("token")
+}
+
+// SendCallbackTokenError sends 403 status code to client for a missing, invalid, expired or
+// superseded callback token and 409 for one already used
+func (h *Handler) SendCallbackTokenError(w http.ResponseWriter, err error) error {
+	switch {
+	case errors.Is(err, services.ErrCallbackTokenUsed):
+		return h.SendConflict(w, err, []string{"token"})
+	case errors.Is(err, services.ErrCallbackTokenInvalid), errors.Is(err, services.ErrCallbackTokenExpired),
+		errors.Is(err, services.ErrCallbackTokenSuperseded):
+		h.Log().Warning("rejecting callback token", "error", err)
+		return h.SendJSON(w, http.StatusForbidden, dtos.ForbiddenError{
+			Message: err.Error(),
+			Code:    Forbidden,
+			Fields:  []string{"token"},
+		})
+	}
+	return h.SendInternalError(w, err)
+}
+
+// SendQuotaExceeded sends 429 status code to client
+func (h *Handler) SendQuotaExceeded(w http.ResponseWriter, err error) error {
+	h.Log().Warning("sending quota exceeded to client", "error", err)
+	return h.SendJSON(w, http.StatusTooManyRequests, dtos.QuotaExceededError{
+		Message: err.Error(),
+		Code:    QuotaExceeded,
+		Fields:  EmptyFields,
+	})
+}
+
 // SendValidationErr returns a 400 with a mapping from validator.Validation errors to a error response
 func (h *Handler) SendValidationErr(w http.ResponseWriter, err error) error {
 	h.Log().Error("sending validation to client", "error", err)
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/open-source-cloud/fuse/pkg/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_QualifyID(t *testing.T) {
+	h := &Handler{}
+	plain := httptest.NewRequest(http.MethodGet, "/v1/schemas/invoice", nil)
+	namespaced := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/v1/ns/billing/schemas/invoice", nil), map[string]string{"ns": "billing"})
+
+	tests := []struct {
+		name    string
+		r       *http.Request
+		id      string
+		want    string
+		wantErr bool
+	}{
+		{name: "default namespace stays bare", r: plain, id: "invoice", want: "invoice"},
+		{name: "namespaced route qualifies", r: namespaced, id: "invoice", want: "billing:invoice"},
+		{name: "qualified id rejected on default route", r: plain, id: "billing:invoice", wantErr: true},
+		{name: "qualified id rejected on namespaced route", r: namespaced, id: "other:invoice", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := h.QualifyID(tt.r, tt.id)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrQualifiedIDParam)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestHandler_CallbackToken(t *testing.T) {
+	h := &Handler{}
+
+	header := httptest.NewRequest(http.MethodPost, "/v1/awakeables/a1/resolve?token=from-query", nil)
+	header.Header.Set(transport.CallbackTokenHeader, "from-header")
+	assert.Equal(t, "from-header", h.CallbackToken(header), "the header wins over the query param")
+
+	query := httptest.NewRequest(http.MethodPost, "/v1/awakeables/a1/resolve?token=from-query", nil)
+	assert.Equal(t, "from-query", h.CallbackToken(query))
+
+	assert.Empty(t, h.CallbackToken(httptest.NewRequest(http.MethodPost, "/v1/awakeables/a1/resolve", nil)))
+}
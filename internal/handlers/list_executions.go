@@ -1,11 +1,15 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/auth"
 	"github.com/open-source-cloud/fuse/internal/repositories"
 )
 
@@ -39,27 +43,51 @@ func NewListExecutionsHandlerFactory(workflowRepo repositories.WorkflowRepositor
 
 // HandleGet handles GET /v1/schemas/{schemaID}/executions
 // @Summary List workflow executions for a schema
-// @Description Returns a paginated list of workflow executions filtered by status and time range
+// @Description Returns a paginated list of workflow executions filtered by status, version, search attributes and time range
 // @Tags schemas
 // @Produce json
 // @Param schemaID path string true "Schema ID"
 // @Param page query int false "Page number (default: 1)"
 // @Param size query int false "Page size (default: 20, max: 100)"
 // @Param status query string false "Filter by workflow state (e.g. finished, error, running)"
+// @Param version query int false "Filter by the schema version the execution ran on"
 // @Param from query string false "Filter by created_at >= (RFC3339 format)"
 // @Param to query string false "Filter by created_at <= (RFC3339 format)"
+// @Param attr.name query string false "Filter by search attribute value, e.g. attr.orderId=12345 (repeatable with different names)"
 // @Success 200 {object} object "Paginated list with items, total, page, size, lastPage"
 // @Failure 400 {object} dtos.BadRequestError
+// @Failure 403 {object} dtos.ForbiddenError
 // @Failure 500 {object} dtos.InternalServerErrorResponse
 // @Router /v1/schemas/{schemaID}/executions [get]
 func (h *ListExecutionsHandler) HandleGet(_ gen.PID, w http.ResponseWriter, r *http.Request) error {
-	schemaID, err := h.GetPathParam(r, "schemaID")
+	schemaID, err := h.SchemaIDParam(r)
 	if err != nil {
 		return h.SendBadRequest(w, err, EmptyFields)
 	}
+	if err := h.Authorize(r, auth.PermWorkflowRead, auth.NewSchemaResource(schemaID, "")); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
+	filter, fields, err := parseExecutionListFilter(r.URL.Query())
+	if err != nil {
+		return h.SendBadRequest(w, err, fields)
+	}
+	filter.SchemaID = schemaID
+
+	result, findErr := h.workflowRepo.FindExecutions(filter)
+	if findErr != nil {
+		return h.SendInternalError(w, findErr)
+	}
+
+	return h.SendJSON(w, http.StatusOK, result)
+}
 
-	q := r.URL.Query()
+// searchAttributeParamPrefix prefixes query parameters filtering by search attribute, e.g. attr.orderId=12345.
+const searchAttributeParamPrefix = "attr."
 
+// parseExecutionListFilter reads pagination, status, version, time range and search attribute
+// filters from the query string. On error it also returns the fields to report to the client.
+func parseExecutionListFilter(q url.Values) (repositories.ExecutionListFilter, []string, error) {
 	page, _ := strconv.Atoi(q.Get("page"))
 	if page < 1 {
 		page = 1
@@ -73,31 +101,44 @@ func (h *ListExecutionsHandler) HandleGet(_ gen.PID, w http.ResponseWriter, r *h
 	}
 
 	filter := repositories.ExecutionListFilter{
-		SchemaID: schemaID,
-		Status:   q.Get("status"),
-		Page:     page,
-		Size:     size,
+		Status: q.Get("status"),
+		Page:   page,
+		Size:   size,
 	}
 
+	if versionStr := q.Get("version"); versionStr != "" {
+		version, parseErr := strconv.Atoi(versionStr)
+		if parseErr != nil || version < 1 {
+			return filter, []string{"version must be a positive integer"}, fmt.Errorf("invalid version %q", versionStr)
+		}
+		filter.Version = version
+	}
 	if fromStr := q.Get("from"); fromStr != "" {
 		t, parseErr := time.Parse(time.RFC3339, fromStr)
 		if parseErr != nil {
-			return h.SendBadRequest(w, parseErr, []string{"from must be in RFC3339 format"})
+			return filter, []string{"from must be in RFC3339 format"}, parseErr
 		}
 		filter.From = t
 	}
 	if toStr := q.Get("to"); toStr != "" {
 		t, parseErr := time.Parse(time.RFC3339, toStr)
 		if parseErr != nil {
-			return h.SendBadRequest(w, parseErr, []string{"to must be in RFC3339 format"})
+			return filter, []string{"to must be in RFC3339 format"}, parseErr
 		}
 		filter.To = t
 	}
-
-	result, findErr := h.workflowRepo.FindExecutions(filter)
-	if findErr != nil {
-		return h.SendInternalError(w, findErr)
+	for key, values := range q {
+		name, ok := strings.CutPrefix(key, searchAttributeParamPrefix)
+		if !ok {
+			continue
+		}
+		if name == "" || len(values) != 1 {
+			return filter, []string{key}, fmt.Errorf("search attribute filter %q needs a name and exactly one value", key)
+		}
+		if filter.Attributes == nil {
+			filter.Attributes = make(map[string]string)
+		}
+		filter.Attributes[name] = values[0]
 	}
-
-	return h.SendJSON(w, http.StatusOK, result)
+	return filter, nil, nil
 }
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/auth"
 	"github.com/open-source-cloud/fuse/internal/dtos"
 	"github.com/open-source-cloud/fuse/internal/repositories"
 	"github.com/open-source-cloud/fuse/internal/services"
@@ -47,16 +48,20 @@ func NewListSchemaVersionsHandlerFactory(graphService services.GraphService) *Li
 // @Param schemaID path string true "Schema ID"
 // @Success 200 {object} dtos.SchemaVersionListResponse
 // @Failure 400 {object} dtos.BadRequestError
+// @Failure 403 {object} dtos.ForbiddenError
 // @Failure 404 {object} dtos.NotFoundError
 // @Failure 500 {object} dtos.InternalServerErrorResponse
 // @Router /v1/schemas/{schemaID}/versions [get]
 func (h *ListSchemaVersionsHandler) HandleGet(from gen.PID, w http.ResponseWriter, r *http.Request) error {
 	h.Log().Info("received list schema versions request", "from", from, "remoteAddr", r.RemoteAddr)
 
-	schemaID, err := h.GetPathParam(r, "schemaID")
+	schemaID, err := h.SchemaIDParam(r)
 	if err != nil {
 		return h.SendBadRequest(w, err, []string{"schemaID is required"})
 	}
+	if err := h.Authorize(r, auth.PermSchemaRead, auth.NewSchemaResource(schemaID, "")); err != nil {
+		return h.SendForbidden(w, err)
+	}
 
 	versions, err := h.graphService.ListVersions(schemaID)
 	if err != nil {
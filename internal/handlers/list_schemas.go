@@ -1,11 +1,16 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
+	"strings"
 
 	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/auth"
 	"github.com/open-source-cloud/fuse/internal/dtos"
 	"github.com/open-source-cloud/fuse/internal/services"
+	"github.com/open-source-cloud/fuse/internal/workflow"
+	pkgworkflow "github.com/open-source-cloud/fuse/pkg/workflow"
 )
 
 const (
@@ -21,16 +26,19 @@ type (
 	// ListSchemasHandler serves GET /v1/schemas.
 	ListSchemasHandler struct {
 		Handler
-		graphService services.GraphService
+		lifecycleService services.SchemaLifecycleService
 	}
 )
 
+// defaultListedLifecycles are listed when no lifecycle filter is given; archived schemas are hidden.
+var defaultListedLifecycles = []workflow.SchemaLifecycle{workflow.SchemaActive, workflow.SchemaDeprecated}
+
 // NewListSchemasHandlerFactory builds a factory for ListSchemasHandler.
-func NewListSchemasHandlerFactory(graphService services.GraphService) *ListSchemasHandlerFactory {
+func NewListSchemasHandlerFactory(lifecycleService services.SchemaLifecycleService) *ListSchemasHandlerFactory {
 	return &ListSchemasHandlerFactory{
 		Factory: func() gen.ProcessBehavior {
 			return &ListSchemasHandler{
-				graphService: graphService,
+				lifecycleService: lifecycleService,
 			}
 		},
 	}
@@ -38,35 +46,73 @@ func NewListSchemasHandlerFactory(graphService services.GraphService) *ListSchem
 
 // HandleGet handles GET /v1/schemas.
 // @Summary List workflow schemas
-// @Description List all workflow graph schemas registered in the app
+// @Description List the workflow graph schemas of the request's namespace the caller may read, filtered by lifecycle state (active and deprecated by default). Schema IDs are returned unqualified.
 // @Tags schemas
 // @Accept json
 // @Produce json
+// @Param lifecycle query string false "Comma-separated lifecycle states (active, deprecated, archived) or 'all'"
 // @Success 200 {object} dtos.SchemaListResponse
+// @Failure 400 {object} dtos.BadRequestError
 // @Failure 500 {object} dtos.InternalServerErrorResponse
 // @Router /v1/schemas [get]
 func (h *ListSchemasHandler) HandleGet(from gen.PID, w http.ResponseWriter, r *http.Request) error {
 	h.Log().Info("received list schemas request from: %v remoteAddr: %s", from, r.RemoteAddr)
 
-	items, err := h.graphService.ListSchemas()
+	lifecycles := defaultListedLifecycles
+	if raw, qErr := h.GetQueryParam(r, "lifecycle"); qErr == nil {
+		parsed, err := parseLifecycleFilter(raw)
+		if err != nil {
+			return h.SendBadRequest(w, err, []string{"lifecycle"})
+		}
+		lifecycles = parsed
+	}
+
+	items, err := h.lifecycleService.List(lifecycles...)
 	if err != nil {
 		h.Log().Error("failed to list schemas", "error", err, "from", from)
 		return h.SendInternalError(w, err)
 	}
 
-	dtoItems := make([]dtos.GraphSchemaSummaryDTO, len(items))
-	for i, it := range items {
-		dtoItems[i] = dtos.GraphSchemaSummaryDTO{SchemaID: it.SchemaID, Name: it.Name}
+	namespace := h.Namespace(r)
+	dtoItems := make([]dtos.GraphSchemaSummaryDTO, 0, len(items))
+	for _, it := range items {
+		itemNamespace, schemaID := pkgworkflow.SplitQualifiedID(it.SchemaID)
+		if itemNamespace != namespace {
+			continue
+		}
+		if ok, err := h.allowed(r, auth.PermSchemaRead, auth.NewSchemaResource(it.SchemaID, "")); !ok {
+			if err != nil {
+				return h.SendInternalError(w, err)
+			}
+			continue
+		}
+		dtoItems = append(dtoItems, dtos.GraphSchemaSummaryDTO{SchemaID: schemaID, Name: it.Name, Lifecycle: string(it.Lifecycle)})
 	}
 
-	h.Log().Info("schemas listed", "from", from, "count", len(items))
+	h.Log().Info("schemas listed", "from", from, "namespace", namespace, "count", len(dtoItems))
 
 	return h.SendJSON(w, http.StatusOK, dtos.SchemaListResponse{
 		Metadata: dtos.PaginationMetadata{
-			Total: len(items),
+			Total: len(dtoItems),
 			Page:  0,
-			Size:  len(items),
+			Size:  len(dtoItems),
 		},
 		Items: dtoItems,
 	})
 }
+
+// parseLifecycleFilter parses a comma-separated lifecycle list; "all" selects every state.
+func parseLifecycleFilter(raw string) ([]workflow.SchemaLifecycle, error) {
+	if raw == "all" {
+		return workflow.SchemaLifecycles, nil
+	}
+	var out []workflow.SchemaLifecycle
+	for _, part := range strings.Split(raw, ",") {
+		lifecycle := workflow.SchemaLifecycle(strings.TrimSpace(part))
+		if !lifecycle.IsValid() {
+			return nil, fmt.Errorf("unknown lifecycle %q", lifecycle)
+		}
+		out = append(out, lifecycle)
+	}
+	return out, nil
+}
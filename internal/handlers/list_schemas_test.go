@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/open-source-cloud/fuse/internal/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLifecycleFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    []workflow.SchemaLifecycle
+		wantErr bool
+	}{
+		{name: "single state", raw: "archived", want: []workflow.SchemaLifecycle{workflow.SchemaArchived}},
+		{name: "comma separated", raw: "active, deprecated", want: []workflow.SchemaLifecycle{workflow.SchemaActive, workflow.SchemaDeprecated}},
+		{name: "all", raw: "all", want: workflow.SchemaLifecycles},
+		{name: "unknown state", raw: "active,retired", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseLifecycleFilter(tt.raw)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
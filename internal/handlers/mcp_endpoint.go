@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/open-source-cloud/fuse/internal/app/config"
+	"github.com/open-source-cloud/fuse/internal/dtos"
+	"github.com/open-source-cloud/fuse/internal/services"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+)
+
+// MCPEndpointHandler serves the engine's MCP server over streamable HTTP. Like the execution
+// stream it is a plain http.Handler rather than an actor worker: a tool call waits for the
+// workflow it triggers, which may take longer than a worker pool request timeout.
+type MCPEndpointHandler struct {
+	service            services.WorkflowMCPService
+	environmentService services.EnvironmentService
+	defaultEnvironment string
+}
+
+// NewMCPEndpointHandler creates a new MCPEndpointHandler
+func NewMCPEndpointHandler(cfg *config.Config, service services.WorkflowMCPService, environmentService services.EnvironmentService) *MCPEndpointHandler {
+	return &MCPEndpointHandler{service: service, environmentService: environmentService, defaultEnvironment: cfg.Environment}
+}
+
+// ServeHTTP handles the MCP endpoint (POST /v1/mcp)
+// @Summary MCP server
+// @Description Model Context Protocol server (streamable HTTP, stateless) for external AI assistants.
+// @Description Schemas of the namespace with mcp.expose are offered as tools whose parameters are the
+// @Description schema's input contract, filtered to those the caller may trigger. A tool call triggers
+// @Description the schema and waits up to mcp.wait (MCP_SERVER_WAIT) for its output, else answers with
+// @Description the workflowId and a fuse://workflows/{workflowId} resource. Executions' status, trace
+// @Description and snapshot are readable as resources.
+// @Tags mcp
+// @Accept json
+// @Produce json
+// @Param environment query string false "Environment tool calls run in (defaults to the engine environment)"
+// @Param message body object true "JSON-RPC 2.0 message"
+// @Success 200 {object} object "JSON-RPC 2.0 response"
+// @Success 202 {string} string "Notification accepted"
+// @Failure 400 {object} dtos.BadRequestError
+// @Router /v1/mcp [post]
+func (h *MCPEndpointHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	namespace := mux.Vars(r)["ns"]
+	if namespace == "" {
+		namespace = workflow.DefaultNamespaceName
+	}
+	environment := r.URL.Query().Get("environment")
+	if environment == "" {
+		environment = h.defaultEnvironment
+	}
+	if !h.environmentService.IsValid(environment) {
+		writeStreamError(w, http.StatusBadRequest, dtos.ErrorResponse{
+			Message: fmt.Sprintf("unknown environment %q", environment),
+			Code:    BadRequest,
+			Fields:  []string{"environment"},
+		})
+		return
+	}
+	h.service.Server(namespace, environment).ServeHTTP(w, r)
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"ergo.services/ergo/gen"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-source-cloud/fuse/internal/app/config"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/internal/services"
+	"github.com/open-source-cloud/fuse/pkg/mcp"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+)
+
+// recordingMCPService serves pings and records which server was asked for.
+type recordingMCPService struct {
+	namespace, environment string
+}
+
+func (s *recordingMCPService) Server(namespace, environment string) *mcp.Server {
+	s.namespace, s.environment = namespace, environment
+	return mcp.NewServer(mcp.Implementation{Name: "fuse"}, "", nil)
+}
+
+func (s *recordingMCPService) BindNode(gen.Node) {}
+
+func TestMCPEndpointHandler_ResolvesNamespaceAndEnvironment(t *testing.T) {
+	envRepo := repositories.NewMemoryEnvironmentRepository()
+	require.NoError(t, envRepo.Save(&workflow.Environment{Name: "prod"}))
+	svc := &recordingMCPService{}
+	h := NewMCPEndpointHandler(&config.Config{Environment: workflow.DefaultEnvironmentName}, svc, services.NewEnvironmentService(envRepo, nil))
+	router := mux.NewRouter()
+	router.Handle("/v1/mcp", h)
+	router.Handle("/v1/ns/{ns}/mcp", h)
+	srv := httptest.NewServer(router)
+	defer srv.Close()
+
+	ping := func(path string) int {
+		res, err := http.Post(srv.URL+path, "application/json", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+		require.NoError(t, err)
+		_ = res.Body.Close()
+		return res.StatusCode
+	}
+
+	assert.Equal(t, http.StatusOK, ping("/v1/mcp"))
+	assert.Equal(t, workflow.DefaultNamespaceName, svc.namespace)
+	assert.Equal(t, workflow.DefaultEnvironmentName, svc.environment)
+
+	assert.Equal(t, http.StatusOK, ping("/v1/ns/billing/mcp?environment=prod"))
+	assert.Equal(t, "billing", svc.namespace)
+	assert.Equal(t, "prod", svc.environment)
+
+	assert.Equal(t, http.StatusBadRequest, ping("/v1/mcp?environment=moon"))
+}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/auth"
+	"github.com/open-source-cloud/fuse/internal/dtos"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/internal/services"
+	"github.com/open-source-cloud/fuse/pkg/mcp"
+)
+
+const (
+	// MCPServerHandlerName is the name of the single MCP server handler.
+	MCPServerHandlerName = "mcp_server_handler"
+	// MCPServerHandlerPoolName is the name of the single MCP server handler pool.
+	MCPServerHandlerPoolName = "mcp_server_handler_pool"
+)
+
+type (
+	// MCPServerHandlerFactory is the factory for the single MCP server handler.
+	MCPServerHandlerFactory HandlerFactory[*MCPServerHandler]
+
+	// MCPServerHandler handles a single MCP server registration.
+	MCPServerHandler struct {
+		Handler
+		mcpServerService services.MCPServerService
+	}
+)
+
+// NewMCPServerHandler creates a new single MCP server handler factory.
+func NewMCPServerHandler(mcpServerService services.MCPServerService) *MCPServerHandlerFactory {
+	return &MCPServerHandlerFactory{
+		Factory: func() gen.ProcessBehavior {
+			return &MCPServerHandler{mcpServerService: mcpServerService}
+		},
+	}
+}
+
+// HandleGet retrieves an MCP server registration (GET /v1/mcp-servers/{id})
+// @Summary Get MCP server by id
+// @Description Retrieve an MCP server registration (env and header names only; values are never returned)
+// @Tags mcp
+// @Accept json
+// @Produce json
+// @Param id path string true "MCP server id"
+// @Success 200 {object} dtos.MCPServerDTO
+// @Failure 403 {object} dtos.ForbiddenError
+// @Failure 404 {object} dtos.NotFoundError
+// @Failure 500 {object} dtos.InternalServerErrorResponse
+// @Router /v1/mcp-servers/{id} [get]
+func (h *MCPServerHandler) HandleGet(from gen.PID, w http.ResponseWriter, r *http.Request) error {
+	h.Log().Info("received get mcp server request from: %v remoteAddr: %s", from, r.RemoteAddr)
+
+	id, err := h.GetPathParam(r, "id")
+	if err != nil {
+		return h.SendBadRequest(w, err, []string{"id is required"})
+	}
+
+	namespace := h.Namespace(r)
+	if err := h.Authorize(r, auth.PermMCPServerRead, auth.Resource{Namespace: namespace}); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
+	server, err := h.mcpServerService.FindByID(namespace, id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrMCPServerNotFound) {
+			return h.SendNotFound(w, fmt.Sprintf("mcp server %s not found", id), []string{"id"})
+		}
+		return h.SendInternalError(w, err)
+	}
+
+	return h.SendJSON(w, http.StatusOK, dtos.ToMCPServerDTO(server))
+}
+
+// HandlePut registers or replaces an MCP server.
+// @Summary Create or update MCP server
+// @Description Upsert an MCP server registration in the request's namespace; its tools become available to ai/agent nodes as mcp__<server>__<tool>
+// @Tags mcp
+// @Accept json
+// @Produce json
+// @Param id path string true "MCP server id"
+// @Param server body dtos.UpsertMCPServerRequest true "MCP server registration"
+// @Success 200 {object} dtos.UpsertMCPServerResponse
+// @Failure 400 {object} dtos.BadRequestError
+// @Failure 403 {object} dtos.ForbiddenError
+// @Failure 500 {object} dtos.InternalServerErrorResponse
+// @Router /v1/mcp-servers/{id} [put]
+func (h *MCPServerHandler) HandlePut(from gen.PID, w http.ResponseWriter, r *http.Request) error {
+	h.Log().Info("received upsert mcp server request from: %v remoteAddr: %s", from, r.RemoteAddr)
+
+	id, err := h.GetPathParam(r, "id")
+	if err != nil {
+		return h.SendBadRequest(w, err, []string{"id is required"})
+	}
+
+	namespace := h.Namespace(r)
+	if err := h.Authorize(r, auth.PermMCPServerWrite, auth.Resource{Namespace: namespace}); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
+	var req dtos.UpsertMCPServerRequest
+	if bindErr := h.BindJSON(w, r, &req); bindErr != nil {
+		return h.SendBadRequest(w, bindErr, []string{"body"})
+	}
+
+	var timeout time.Duration
+	if req.Timeout != "" {
+		if timeout, err = time.ParseDuration(req.Timeout); err != nil {
+			return h.SendBadRequest(w, err, []string{"timeout"})
+		}
+	}
+
+	server := &mcp.ServerConfig{
+		ID:          id,
+		Description: req.Description,
+		Transport:   mcp.TransportType(req.Transport),
+		Command:     req.Command,
+		Args:        req.Args,
+		Env:         req.Env,
+		URL:         req.URL,
+		Headers:     req.Headers,
+		Timeout:     timeout,
+		Tools:       req.Tools,
+	}
+	if saveErr := h.mcpServerService.Save(r.Context(), namespace, server); saveErr != nil {
+		if errors.Is(saveErr, mcp.ErrInvalidServerConfig) || errors.Is(saveErr, services.ErrMCPStdioDisabled) {
+			return h.SendBadRequest(w, saveErr, []string{"server"})
+		}
+		return h.SendInternalError(w, saveErr)
+	}
+
+	return h.SendJSON(w, http.StatusOK, dtos.UpsertMCPServerResponse{
+		Message: "MCP server saved successfully",
+		Server:  id,
+	})
+}
+
+// HandleDelete removes an MCP server registration and closes its connections.
+// @Summary Delete MCP server
+// @Description Delete an MCP server registration from the request's namespace
+// @Tags mcp
+// @Accept json
+// @Produce json
+// @Param id path string true "MCP server id"
+// @Success 204 "No Content"
+// @Failure 403 {object} dtos.ForbiddenError
+// @Failure 404 {object} dtos.NotFoundError
+// @Failure 500 {object} dtos.InternalServerErrorResponse
+// @Router /v1/mcp-servers/{id} [delete]
+func (h *MCPServerHandler) HandleDelete(from gen.PID, w http.ResponseWriter, r *http.Request) error {
+	h.Log().Info("received delete mcp server request from: %v remoteAddr: %s", from, r.RemoteAddr)
+
+	id, err := h.GetPathParam(r, "id")
+	if err != nil {
+		return h.SendBadRequest(w, err, []string{"id is required"})
+	}
+
+	namespace := h.Namespace(r)
+	if err := h.Authorize(r, auth.PermMCPServerWrite, auth.Resource{Namespace: namespace}); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
+	if delErr := h.mcpServerService.Delete(r.Context(), namespace, id); delErr != nil {
+		if errors.Is(delErr, repositories.ErrMCPServerNotFound) {
+			return h.SendNotFound(w, fmt.Sprintf("mcp server %s not found", id), []string{"id"})
+		}
+		return h.SendInternalError(w, delErr)
+	}
+
+	return h.SendJSON(w, http.StatusNoContent, nil)
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http"
+
+	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/auth"
+	"github.com/open-source-cloud/fuse/internal/dtos"
+	"github.com/open-source-cloud/fuse/internal/services"
+)
+
+const (
+	// MCPServersHandlerName is the name of the MCP servers list handler.
+	MCPServersHandlerName = "mcp_servers_handler"
+	// MCPServersHandlerPoolName is the name of the MCP servers list handler pool.
+	MCPServersHandlerPoolName = "mcp_servers_handler_pool"
+)
+
+type (
+	// MCPServersHandlerFactory is the factory for the MCP servers list handler.
+	MCPServersHandlerFactory HandlerFactory[*MCPServersHandler]
+
+	// MCPServersHandler handles the MCP servers collection endpoint.
+	MCPServersHandler struct {
+		Handler
+		mcpServerService services.MCPServerService
+	}
+)
+
+// NewMCPServersHandler creates a new MCP servers list handler factory.
+func NewMCPServersHandler(mcpServerService services.MCPServerService) *MCPServersHandlerFactory {
+	return &MCPServersHandlerFactory{
+		Factory: func() gen.ProcessBehavior {
+			return &MCPServersHandler{mcpServerService: mcpServerService}
+		},
+	}
+}
+
+// HandleGet lists the registered MCP servers (GET /v1/mcp-servers)
+// @Summary List MCP servers
+// @Description Retrieve the MCP servers registered in the request's namespace (env and header names only; values are never returned)
+// @Tags mcp
+// @Accept json
+// @Produce json
+// @Success 200 {object} dtos.MCPServerListResponse
+// @Failure 403 {object} dtos.ForbiddenError
+// @Failure 500 {object} dtos.InternalServerErrorResponse
+// @Router /v1/mcp-servers [get]
+func (h *MCPServersHandler) HandleGet(from gen.PID, w http.ResponseWriter, r *http.Request) error {
+	h.Log().Info("received list mcp servers request from: %v remoteAddr: %s", from, r.RemoteAddr)
+
+	namespace := h.Namespace(r)
+	if err := h.Authorize(r, auth.PermMCPServerRead, auth.Resource{Namespace: namespace}); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
+	servers, err := h.mcpServerService.FindAll(namespace)
+	if err != nil {
+		return h.SendInternalError(w, err)
+	}
+
+	items := make([]dtos.MCPServerDTO, len(servers))
+	for i, s := range servers {
+		items[i] = dtos.ToMCPServerDTO(s)
+	}
+
+	return h.SendJSON(w, http.StatusOK, dtos.MCPServerListResponse{Items: items})
+}
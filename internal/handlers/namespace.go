@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/auth"
+	"github.com/open-source-cloud/fuse/internal/dtos"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/internal/services"
+)
+
+const (
+	// NamespaceHandlerName is the name of the single-namespace handler.
+	NamespaceHandlerName = "namespace_handler"
+	// NamespaceHandlerPoolName is the name of the single-namespace handler pool.
+	NamespaceHandlerPoolName = "namespace_handler_pool"
+)
+
+type (
+	// NamespaceHandlerFactory is the factory for the single-namespace handler.
+	NamespaceHandlerFactory HandlerFactory[*NamespaceHandler]
+
+	// NamespaceHandler handles a single namespace resource.
+	NamespaceHandler struct {
+		Handler
+		namespaceService services.NamespaceService
+	}
+)
+
+// NewNamespaceHandler creates a new single-namespace handler factory.
+func NewNamespaceHandler(namespaceService services.NamespaceService) *NamespaceHandlerFactory {
+	return &NamespaceHandlerFactory{
+		Factory: func() gen.ProcessBehavior {
+			return &NamespaceHandler{
+				namespaceService: namespaceService,
+			}
+		},
+	}
+}
+
+// HandleGet retrieves a single namespace (GET /v1/namespaces/{name})
+// @Summary Get namespace by name
+// @Description Retrieve a single namespace and its quotas
+// @Tags namespaces
+// @Accept json
+// @Produce json
+// @Param name path string true "Namespace name"
+// @Success 200 {object} dtos.NamespaceDTO
+// @Failure 403 {object} dtos.ForbiddenError
+// @Failure 404 {object} dtos.NotFoundError
+// @Failure 500 {object} dtos.InternalServerErrorResponse
+// @Router /v1/namespaces/{name} [get]
+func (h *NamespaceHandler) HandleGet(from gen.PID, w http.ResponseWriter, r *http.Request) error {
+	h.Log().Info("received get namespace request from: %v remoteAddr: %s", from, r.RemoteAddr)
+
+	name, err := h.GetPathParam(r, "name")
+	if err != nil {
+		return h.SendBadRequest(w, err, []string{"name is required"})
+	}
+
+	if err := h.Authorize(r, auth.PermNamespaceRead, auth.Resource{Namespace: name}); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
+	ns, err := h.namespaceService.FindByName(name)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNamespaceNotFound) {
+			return h.SendNotFound(w, fmt.Sprintf("namespace %s not found", name), []string{"name"})
+		}
+		return h.SendInternalError(w, err)
+	}
+
+	return h.SendJSON(w, http.StatusOK, dtos.ToNamespaceDTO(ns))
+}
+
+// HandlePut creates or updates a namespace and its quotas (PUT /v1/namespaces/{name})
+// @Summary Create or update namespace
+// @Description Upsert a namespace; the path name is authoritative. Quotas apply to new executions and definitions only.
+// @Tags namespaces
+// @Accept json
+// @Produce json
+// @Param name path string true "Namespace name"
+// @Param namespace body dtos.NamespaceDTO true "Namespace data"
+// @Success 200 {object} dtos.UpsertNamespaceResponse
+// @Failure 400 {object} dtos.BadRequestError
+// @Failure 403 {object} dtos.ForbiddenError
+// @Failure 500 {object} dtos.InternalServerErrorResponse
+// @Router /v1/namespaces/{name} [put]
+func (h *NamespaceHandler) HandlePut(from gen.PID, w http.ResponseWriter, r *http.Request) error {
+	h.Log().Info("received upsert namespace request from: %v remoteAddr: %s", from, r.RemoteAddr)
+
+	name, err := h.GetPathParam(r, "name")
+	if err != nil {
+		return h.SendBadRequest(w, err, []string{"name is required"})
+	}
+
+	if err := h.Authorize(r, auth.PermAdmin, auth.Resource{}); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
+	var dto dtos.NamespaceDTO
+	if bindErr := h.BindJSON(w, r, &dto); bindErr != nil {
+		return h.SendBadRequest(w, bindErr, []string{"body"})
+	}
+
+	// The path name is authoritative over the body.
+	dto.Name = name
+	ns := dtos.FromNamespaceDTO(dto)
+	if validateErr := ns.Validate(); validateErr != nil {
+		return h.SendBadRequest(w, validateErr, []string{"name", "quota"})
+	}
+
+	if _, saveErr := h.namespaceService.Save(ns); saveErr != nil {
+		return h.SendInternalError(w, saveErr)
+	}
+
+	return h.SendJSON(w, http.StatusOK, dtos.UpsertNamespaceResponse{
+		Message:   "Namespace saved successfully",
+		Namespace: ns.Name,
+	})
+}
+
+// HandleDelete removes an empty namespace (DELETE /v1/namespaces/{name})
+// @Summary Delete namespace
+// @Description Delete a namespace that no longer owns schemas or packages (the default namespace cannot be deleted)
+// @Tags namespaces
+// @Accept json
+// @Produce json
+// @Param name path string true "Namespace name"
+// @Success 204 "No Content"
+// @Failure 400 {object} dtos.BadRequestError
+// @Failure 403 {object} dtos.ForbiddenError
+// @Failure 404 {object} dtos.NotFoundError
+// @Failure 409 {object} dtos.ConflictError
+// @Failure 500 {object} dtos.InternalServerErrorResponse
+// @Router /v1/namespaces/{name} [delete]
+func (h *NamespaceHandler) HandleDelete(from gen.PID, w http.ResponseWriter, r *http.Request) error {
+	h.Log().Info("received delete namespace request from: %v remoteAddr: %s", from, r.RemoteAddr)
+
+	name, err := h.GetPathParam(r, "name")
+	if err != nil {
+		return h.SendBadRequest(w, err, []string{"name is required"})
+	}
+
+	if err := h.Authorize(r, auth.PermAdmin, auth.Resource{}); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
+	if delErr := h.namespaceService.Delete(name); delErr != nil {
+		switch {
+		case errors.Is(delErr, services.ErrDefaultNamespace):
+			return h.SendBadRequest(w, delErr, []string{"name"})
+		case errors.Is(delErr, repositories.ErrNamespaceNotFound):
+			return h.SendNotFound(w, fmt.Sprintf("namespace %s not found", name), []string{"name"})
+		case errors.Is(delErr, services.ErrNamespaceInUse):
+			return h.SendConflict(w, delErr, []string{"name"})
+		}
+		return h.SendInternalError(w, delErr)
+	}
+
+	return h.SendJSON(w, http.StatusNoContent, nil)
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"net/http"
+
+	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/auth"
+	"github.com/open-source-cloud/fuse/internal/dtos"
+	"github.com/open-source-cloud/fuse/internal/services"
+)
+
+const (
+	// NamespacesHandlerName is the name of the namespaces list handler.
+	NamespacesHandlerName = "namespaces_handler"
+	// NamespacesHandlerPoolName is the name of the namespaces list handler pool.
+	NamespacesHandlerPoolName = "namespaces_handler_pool"
+)
+
+type (
+	// NamespacesHandlerFactory is the factory for the namespaces list handler.
+	NamespacesHandlerFactory HandlerFactory[*NamespacesHandler]
+
+	// NamespacesHandler handles the namespaces collection endpoint.
+	NamespacesHandler struct {
+		Handler
+		namespaceService services.NamespaceService
+	}
+)
+
+// NewNamespacesHandler creates a new namespaces list handler factory.
+func NewNamespacesHandler(namespaceService services.NamespaceService) *NamespacesHandlerFactory {
+	return &NamespacesHandlerFactory{
+		Factory: func() gen.ProcessBehavior {
+			return &NamespacesHandler{
+				namespaceService: namespaceService,
+			}
+		},
+	}
+}
+
+// HandleGet lists all declared namespaces (GET /v1/namespaces)
+// @Summary List namespaces
+// @Description Retrieve all declared namespaces and their quotas
+// @Tags namespaces
+// @Accept json
+// @Produce json
+// @Success 200 {object} dtos.NamespaceListResponse
+// @Failure 500 {object} dtos.InternalServerErrorResponse
+// @Router /v1/namespaces [get]
+func (h *NamespacesHandler) HandleGet(from gen.PID, w http.ResponseWriter, r *http.Request) error {
+	h.Log().Info("received list namespaces request from: %v remoteAddr: %s", from, r.RemoteAddr)
+
+	namespaces, err := h.namespaceService.FindAll()
+	if err != nil {
+		return h.SendInternalError(w, err)
+	}
+
+	items := make([]dtos.NamespaceDTO, 0, len(namespaces))
+	for _, ns := range namespaces {
+		if ok, err := h.allowed(r, auth.PermNamespaceRead, auth.Resource{Namespace: ns.Name}); !ok {
+			if err != nil {
+				return h.SendInternalError(w, err)
+			}
+			continue
+		}
+		items = append(items, dtos.ToNamespaceDTO(ns))
+	}
+
+	return h.SendJSON(w, http.StatusOK, dtos.NamespaceListResponse{Items: items})
+}
@@ -4,8 +4,10 @@ import (
 	"net/http"
 
 	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/auth"
 	"github.com/open-source-cloud/fuse/internal/dtos"
 	"github.com/open-source-cloud/fuse/internal/services"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
 )
 
 const (
@@ -39,16 +41,22 @@ func NewPackagesHandler(packageService services.PackageService) *PackagesHandler
 
 // HandleGet handles the GET request for the packages' endpoint (GET /packages)
 // @Summary List all packages
-// @Description Retrieve all registered packages
+// @Description Retrieve the packages registered in the request's namespace. IDs stay qualified ("billing:tools") because schemas reference functions by them.
 // @Tags packages
 // @Accept json
 // @Produce json
 // @Success 200 {object} dtos.PackageListResponse
+// @Failure 403 {object} dtos.ForbiddenError
 // @Failure 500 {object} dtos.InternalServerErrorResponse
 // @Router /v1/packages [get]
 func (h *PackagesHandler) HandleGet(from gen.PID, w http.ResponseWriter, r *http.Request) error {
 	h.Log().Info("received list packages request from: %v remoteAddr: %s", from, r.RemoteAddr)
 
+	namespace := h.Namespace(r)
+	if err := h.Authorize(r, auth.PermPackageRead, auth.Resource{Namespace: namespace}); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
 	packages, err := h.packageService.FindAll(services.PackageOptions{
 		Load: true,
 	})
@@ -57,19 +65,21 @@ func (h *PackagesHandler) HandleGet(from gen.PID, w http.ResponseWriter, r *http
 		return h.SendInternalError(w, err)
 	}
 
-	h.Log().Info("packages listed", "from", from, "remoteAddr", r.RemoteAddr, "packages", len(packages))
-
-	// Convert packages to DTOs
-	items := make([]dtos.PackageDTO, len(packages))
-	for i, pkg := range packages {
-		items[i] = dtos.ToPackageDTO(pkg)
+	// Convert the namespace's packages to DTOs
+	items := make([]dtos.PackageDTO, 0, len(packages))
+	for _, pkg := range packages {
+		if workflow.NamespaceOf(pkg.ID) == namespace {
+			items = append(items, dtos.ToPackageDTO(pkg))
+		}
 	}
 
+	h.Log().Info("packages listed", "from", from, "remoteAddr", r.RemoteAddr, "namespace", namespace, "packages", len(items))
+
 	return h.SendJSON(w, http.StatusOK, dtos.PackageListResponse{
 		Metadata: dtos.PaginationMetadata{
-			Total: len(packages),
+			Total: len(items),
 			Page:  0,
-			Size:  len(packages),
+			Size:  len(items),
 		},
 		Items: items,
 	})
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/auth"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/internal/services"
+)
+
+const (
+	// PromoteEnvironmentHandlerName is the name of the environment promotion handler.
+	PromoteEnvironmentHandlerName = "promote_environment_handler"
+	// PromoteEnvironmentHandlerPoolName is the name of the environment promotion handler pool.
+	PromoteEnvironmentHandlerPoolName = "promote_environment_handler_pool"
+)
+
+type (
+	// PromoteEnvironmentHandlerFactory is the factory for the environment promotion handler.
+	PromoteEnvironmentHandlerFactory HandlerFactory[*PromoteEnvironmentHandler]
+
+	// PromoteEnvironmentHandler serves POST /v1/environments/{from}/promote/{to}.
+	PromoteEnvironmentHandler struct {
+		Handler
+		promotionService services.PromotionService
+	}
+)
+
+// NewPromoteEnvironmentHandler creates a new environment promotion handler factory.
+func NewPromoteEnvironmentHandler(promotionService services.PromotionService) *PromoteEnvironmentHandlerFactory {
+	return &PromoteEnvironmentHandlerFactory{
+		Factory: func() gen.ProcessBehavior {
+			return &PromoteEnvironmentHandler{promotionService: promotionService}
+		},
+	}
+}
+
+// HandlePost plans or applies a promotion (POST /v1/environments/{from}/promote/{to})
+// @Summary Promote environment
+// @Description Compare two environments of the request's namespace: variables that differ, credential fields without a value in the target, and the secrets, credentials and variables each active schema is missing there. With apply=true, variables missing from the target are copied (and differing ones too with overwrite=true); secret values are never copied.
+// @Tags environments
+// @Accept json
+// @Produce json
+// @Param from path string true "Source environment"
+// @Param to path string true "Target environment"
+// @Param apply query bool false "Copy variables instead of only planning"
+// @Param overwrite query bool false "With apply, also replace variables whose value differs in the target"
+// @Success 200 {object} services.PromotionPlan
+// @Failure 400 {object} dtos.BadRequestError
+// @Failure 403 {object} dtos.ForbiddenError
+// @Failure 404 {object} dtos.NotFoundError
+// @Failure 500 {object} dtos.InternalServerErrorResponse
+// @Router /v1/environments/{from}/promote/{to} [post]
+func (h *PromoteEnvironmentHandler) HandlePost(from gen.PID, w http.ResponseWriter, r *http.Request) error {
+	h.Log().Info("received promote environment request from: %v remoteAddr: %s", from, r.RemoteAddr)
+
+	source, err := h.GetPathParam(r, "from")
+	if err != nil {
+		return h.SendBadRequest(w, err, []string{"from is required"})
+	}
+	target, err := h.GetPathParam(r, "to")
+	if err != nil {
+		return h.SendBadRequest(w, err, []string{"to is required"})
+	}
+
+	apply, overwrite := false, false
+	if raw, qErr := h.GetQueryParam(r, "apply"); qErr == nil {
+		if apply, err = strconv.ParseBool(raw); err != nil {
+			return h.SendBadRequest(w, err, []string{"apply"})
+		}
+	}
+	if raw, qErr := h.GetQueryParam(r, "overwrite"); qErr == nil {
+		if overwrite, err = strconv.ParseBool(raw); err != nil {
+			return h.SendBadRequest(w, err, []string{"overwrite"})
+		}
+	}
+
+	namespace := h.Namespace(r)
+	var plan *services.PromotionPlan
+	if apply {
+		if err := h.Authorize(r, auth.PermVarWrite, auth.Resource{Namespace: namespace, Environment: target}); err != nil {
+			return h.SendForbidden(w, err)
+		}
+		plan, err = h.promotionService.Apply(r.Context(), namespace, source, target, overwrite)
+	} else {
+		plan, err = h.promotionService.Plan(r.Context(), namespace, source, target)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrEnvironmentNotFound):
+			return h.SendNotFound(w, fmt.Sprintf("environment %s or %s not found", source, target), []string{"from", "to"})
+		case errors.Is(err, services.ErrPromoteToSameEnvironment):
+			return h.SendBadRequest(w, err, []string{"to"})
+		default:
+			return h.SendInternalError(w, err)
+		}
+	}
+
+	return h.SendJSON(w, http.StatusOK, plan)
+}
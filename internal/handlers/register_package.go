@@ -6,9 +6,11 @@ import (
 	"net/http"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/open-source-cloud/fuse/internal/auth"
 	"github.com/open-source-cloud/fuse/internal/dtos"
 	"github.com/open-source-cloud/fuse/internal/repositories"
 	"github.com/open-source-cloud/fuse/internal/services"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
 
 	"ergo.services/ergo/gen"
 )
@@ -27,16 +29,18 @@ type (
 	// RegisterPackageHandler is the handler for the register package endpoint
 	RegisterPackageHandler struct {
 		Handler
-		packageService services.PackageService
+		packageService   services.PackageService
+		namespaceService services.NamespaceService
 	}
 )
 
 // NewRegisterPackageHandler creates a new register package handler factory
-func NewRegisterPackageHandler(packageService services.PackageService) *RegisterPackageHandlerFactory {
+func NewRegisterPackageHandler(packageService services.PackageService, namespaceService services.NamespaceService) *RegisterPackageHandlerFactory {
 	return &RegisterPackageHandlerFactory{
 		Factory: func() gen.ProcessBehavior {
 			return &RegisterPackageHandler{
-				packageService: packageService,
+				packageService:   packageService,
+				namespaceService: namespaceService,
 			}
 		},
 	}
@@ -44,7 +48,7 @@ func NewRegisterPackageHandler(packageService services.PackageService) *Register
 
 // HandlePut handles the PUT request for the register package endpoint (PUT /packages/:packageID)
 // @Summary Register or update package
-// @Description Register a new package or update existing one
+// @Description Register a new package or update existing one. The package is stored under the path ID qualified with the request's namespace.
 // @Tags packages
 // @Accept json
 // @Produce json
@@ -52,26 +56,41 @@ func NewRegisterPackageHandler(packageService services.PackageService) *Register
 // @Param package body dtos.PackageDTO true "Package Data"
 // @Success 200 {object} dtos.RegisterPackageResponse
 // @Failure 400 {object} dtos.BadRequestError
+// @Failure 403 {object} dtos.ForbiddenError
 // @Failure 404 {object} dtos.NotFoundError
+// @Failure 429 {object} dtos.QuotaExceededError
 // @Failure 500 {object} dtos.InternalServerErrorResponse
 // @Router /v1/packages/{packageID} [put]
 func (h *RegisterPackageHandler) HandlePut(from gen.PID, w http.ResponseWriter, r *http.Request) error {
 	h.Log().Info("received register package request from: %v remoteAddr: %s", from, r.RemoteAddr)
 
-	packageID, err := h.GetPathParam(r, "packageID")
+	packageID, err := h.packageIDParam(r)
 	if err != nil {
 		return h.SendBadRequest(w, err, []string{"packageID is required"})
 	}
 
+	namespace := h.Namespace(r)
+	if err := h.Authorize(r, auth.PermPackageRegister, auth.Resource{Namespace: namespace}); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
 	var pkgDTO dtos.PackageDTO
 	if err := h.BindJSON(w, r, &pkgDTO); err != nil {
 		return h.SendBadRequest(w, err, []string{"body"})
 	}
 
-	// Convert DTO to domain model
+	// Convert DTO to domain model; the path ID is canonical so a body cannot target another namespace
 	pkg := dtos.FromPackageDTO(pkgDTO)
+	pkg.ID = packageID
+
+	if err := h.checkStorage(namespace, pkg); err != nil {
+		if errors.Is(err, services.ErrNamespaceQuotaExceeded) {
+			return h.SendQuotaExceeded(w, err)
+		}
+		return h.SendInternalError(w, err)
+	}
 
-	pkg, err = h.packageService.Save(pkg)
+	pkg, err = h.packageService.Save(r.Context(), pkg)
 	if err != nil {
 		h.Log().Error("failed to save package", "error", err, "packageID", packageID)
 		if errors.As(err, &validator.ValidationErrors{}) {
@@ -104,7 +123,7 @@ func (h *RegisterPackageHandler) HandlePut(from gen.PID, w http.ResponseWriter,
 func (h *RegisterPackageHandler) HandleGet(from gen.PID, w http.ResponseWriter, r *http.Request) error {
 	h.Log().Info("received get package request from: %v remoteAddr: %s", from, r.RemoteAddr)
 
-	packageID, err := h.GetPathParam(r, "packageID")
+	packageID, err := h.packageIDParam(r)
 	if err != nil {
 		return h.SendBadRequest(w, err, []string{"packageID is required"})
 	}
@@ -128,3 +147,32 @@ func (h *RegisterPackageHandler) HandleGet(from gen.PID, w http.ResponseWriter,
 	pkgDTO := dtos.ToPackageDTO(pkg)
 	return h.SendJSON(w, http.StatusOK, pkgDTO)
 }
+
+// packageIDParam returns the {packageID} path param qualified with the request's namespace
+func (h *RegisterPackageHandler) packageIDParam(r *http.Request) (string, error) {
+	packageID, err := h.GetPathParam(r, "packageID")
+	if err != nil {
+		return "", err
+	}
+	return h.QualifyID(r, packageID)
+}
+
+// checkStorage checks the growth of the namespace's storage when pkg replaces its stored version
+func (h *RegisterPackageHandler) checkStorage(namespace string, pkg *workflow.Package) error {
+	size, err := services.JSONSize(pkg)
+	if err != nil {
+		return err
+	}
+	existing, err := h.packageService.FindByID(pkg.ID, services.PackageOptions{})
+	switch {
+	case err == nil:
+		existingSize, err := services.JSONSize(existing)
+		if err != nil {
+			return err
+		}
+		size -= existingSize
+	case !errors.Is(err, repositories.ErrPackageNotFound):
+		return err
+	}
+	return h.namespaceService.CheckStorage(namespace, size)
+}
@@ -1,13 +1,16 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"ergo.services/ergo/gen"
 	"github.com/open-source-cloud/fuse/internal/actors/actornames"
+	"github.com/open-source-cloud/fuse/internal/auth"
 	"github.com/open-source-cloud/fuse/internal/dtos"
 	"github.com/open-source-cloud/fuse/internal/messaging"
 	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/internal/services"
 	internalworkflow "github.com/open-source-cloud/fuse/internal/workflow"
 )
 
@@ -15,7 +18,9 @@ type (
 	// ResolveAwakeableHandler is the handler for the resolve awakeable endpoint
 	ResolveAwakeableHandler struct {
 		Handler
-		awakeableRepo repositories.AwakeableRepository
+		awakeableRepo  repositories.AwakeableRepository
+		workflowRepo   repositories.WorkflowRepository
+		callbackTokens services.CallbackTokenService
 	}
 	// ResolveAwakeableHandlerFactory is a factory for creating ResolveAwakeableHandler actors
 	ResolveAwakeableHandlerFactory HandlerFactory[*ResolveAwakeableHandler]
@@ -29,11 +34,17 @@ const (
 )
 
 // NewResolveAwakeableHandlerFactory creates a new ResolveAwakeableHandlerFactory
-func NewResolveAwakeableHandlerFactory(awakeableRepo repositories.AwakeableRepository) *ResolveAwakeableHandlerFactory {
+func NewResolveAwakeableHandlerFactory(
+	awakeableRepo repositories.AwakeableRepository,
+	workflowRepo repositories.WorkflowRepository,
+	callbackTokens services.CallbackTokenService,
+) *ResolveAwakeableHandlerFactory {
 	return &ResolveAwakeableHandlerFactory{
 		Factory: func() gen.ProcessBehavior {
 			return &ResolveAwakeableHandler{
-				awakeableRepo: awakeableRepo,
+				awakeableRepo:  awakeableRepo,
+				workflowRepo:   workflowRepo,
+				callbackTokens: callbackTokens,
 			}
 		},
 	}
@@ -41,15 +52,19 @@ func NewResolveAwakeableHandlerFactory(awakeableRepo repositories.AwakeableRepos
 
 // HandlePost handles the resolve awakeable endpoint (POST /v1/awakeables/{awakeableID}/resolve)
 // @Summary Resolve awakeable
-// @Description Completes a pending awakeable with payload data and resumes the workflow
+// @Description Completes a pending awakeable with payload data and resumes the workflow. Requires a resolve token of the awakeable (GET /v1/awakeables/{awakeableID}/token), accepted once.
 // @Tags workflows
 // @Accept json
 // @Produce json
 // @Param awakeableID path string true "Awakeable ID"
+// @Param X-Fuse-Callback-Token header string false "Resolve token (or the token query param)"
+// @Param token query string false "Resolve token, when not sent as a header"
 // @Param request body dtos.ResolveAwakeableRequest true "Resolution payload"
 // @Success 200 {object} dtos.ResolveAwakeableResponse
 // @Failure 400 {object} dtos.BadRequestError
+// @Failure 403 {object} dtos.ForbiddenError
 // @Failure 404 {object} dtos.NotFoundError
+// @Failure 409 {object} dtos.ConflictError
 // @Failure 500 {object} dtos.InternalServerErrorResponse
 // @Router /v1/awakeables/{awakeableID}/resolve [post]
 func (h *ResolveAwakeableHandler) HandlePost(_ gen.PID, w http.ResponseWriter, r *http.Request) error {
@@ -68,16 +83,36 @@ func (h *ResolveAwakeableHandler) HandlePost(_ gen.PID, w http.ResponseWriter, r
 		return h.SendNotFound(w, "awakeable not found", EmptyFields)
 	}
 
+	wf, getErr := h.workflowRepo.Get(awakeable.WorkflowID.String())
+	if getErr != nil || !h.InNamespace(r, wf) {
+		return h.SendNotFound(w, "awakeable not found", EmptyFields)
+	}
+	if err := h.Authorize(r, auth.PermWorkflowTrigger, workflowResource(wf)); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
 	if awakeable.Status != internalworkflow.AwakeablePending {
 		return h.SendBadRequest(w, nil, []string{"awakeable is not in pending status"})
 	}
 
+	if err := h.callbackTokens.Consume(
+		h.CallbackToken(r), services.CallbackAwakeable, awakeable.WorkflowID.String(), awakeableID,
+	); err != nil {
+		return h.SendCallbackTokenError(w, err)
+	}
+
+	// the token is given back whenever the resolution does not reach the workflow, so the caller
+	// can retry with it
 	if err := h.awakeableRepo.Resolve(awakeableID, req.Data); err != nil {
+		h.callbackTokens.Release(h.CallbackToken(r))
+		if errors.Is(err, repositories.ErrAwakeableNotFound) {
+			return h.SendBadRequest(w, err, []string{"awakeable is not in pending status"})
+		}
 		return h.SendInternalError(w, err)
 	}
 
 	// Send resolution message to the workflow handler
-	handlerName := actornames.WorkflowHandlerName(awakeable.WorkflowID)
+	handlerName := actornames.WorkflowHandlerName(wf.Namespace(), awakeable.WorkflowID)
 	resolvedMsg := messaging.NewAwakeableResolvedMessage(
 		awakeable.WorkflowID,
 		awakeableID,
@@ -86,7 +121,11 @@ func (h *ResolveAwakeableHandler) HandlePost(_ gen.PID, w http.ResponseWriter, r
 		req.Data,
 	)
 	if err := h.Send(gen.Atom(handlerName), resolvedMsg); err != nil {
-		h.Log().Error("failed to send awakeable resolved message: %s", err)
+		if reopenErr := h.awakeableRepo.Reopen(awakeableID); reopenErr != nil {
+			h.Log().Error("failed to reopen awakeable %s: %s", awakeableID, reopenErr)
+		}
+		h.callbackTokens.Release(h.CallbackToken(r))
+		return h.SendInternalError(w, err)
 	}
 
 	return h.SendJSON(w, http.StatusOK, dtos.ResolveAwakeableResponse{
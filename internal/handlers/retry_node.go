@@ -4,9 +4,12 @@ import (
 	"net/http"
 
 	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/audit"
+	"github.com/open-source-cloud/fuse/internal/auth"
 	"github.com/open-source-cloud/fuse/internal/dtos"
 	"github.com/open-source-cloud/fuse/internal/messaging"
 	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/internal/services"
 	"github.com/open-source-cloud/fuse/pkg/workflow"
 )
 
@@ -15,6 +18,7 @@ type (
 	RetryNodeHandler struct {
 		Handler
 		workflowRepo repositories.WorkflowRepository
+		auditService services.AuditService
 	}
 	// RetryNodeHandlerFactory is a factory for creating RetryNodeHandler actors
 	RetryNodeHandlerFactory HandlerFactory[*RetryNodeHandler]
@@ -28,11 +32,12 @@ const (
 )
 
 // NewRetryNodeHandlerFactory creates a new RetryNodeHandlerFactory
-func NewRetryNodeHandlerFactory(workflowRepo repositories.WorkflowRepository) *RetryNodeHandlerFactory {
+func NewRetryNodeHandlerFactory(workflowRepo repositories.WorkflowRepository, auditService services.AuditService) *RetryNodeHandlerFactory {
 	return &RetryNodeHandlerFactory{
 		Factory: func() gen.ProcessBehavior {
 			return &RetryNodeHandler{
 				workflowRepo: workflowRepo,
+				auditService: auditService,
 			}
 		},
 	}
@@ -48,6 +53,7 @@ func NewRetryNodeHandlerFactory(workflowRepo repositories.WorkflowRepository) *R
 // @Param request body dtos.RetryNodeRequest true "Execution ID of the failed node"
 // @Success 202 {object} dtos.RetryNodeResponse
 // @Failure 400 {object} dtos.BadRequestError
+// @Failure 403 {object} dtos.ForbiddenError
 // @Failure 404 {object} dtos.NotFoundError
 // @Failure 500 {object} dtos.InternalServerErrorResponse
 // @Router /v1/workflows/{workflowID}/retry-node [post]
@@ -64,18 +70,25 @@ func (h *RetryNodeHandler) HandlePost(_ gen.PID, w http.ResponseWriter, r *http.
 
 	// Validate workflow exists and is in error state
 	wf, getErr := h.workflowRepo.Get(workflowID)
-	if getErr != nil {
+	if getErr != nil || !h.InNamespace(r, wf) {
 		return h.SendNotFound(w, "workflow not found", EmptyFields)
 	}
+	if err := h.Authorize(r, auth.PermWorkflowTrigger, workflowResource(wf)); err != nil {
+		return h.SendForbidden(w, err)
+	}
 	if wf.State().String() != "error" {
 		return h.SendBadRequest(w, nil, []string{"workflow must be in error state to retry a node"})
 	}
 
-	retryMsg := messaging.NewRetryNodeMessage(workflow.ID(workflowID), workflow.ExecID(req.ExecID))
+	retryMsg := messaging.NewRetryNodeMessage(wf.Namespace(), workflow.ID(workflowID), workflow.ExecID(req.ExecID))
 	if err := h.Send(WorkflowSupervisorName, retryMsg); err != nil {
 		return h.SendInternalError(w, err)
 	}
 
+	entry := workflowAuditEntry(audit.ActionWorkflowRetryNode, workflowID, wf)
+	entry.Details["execId"] = req.ExecID
+	h.auditService.Record(r.Context(), entry)
+
 	return h.SendJSON(w, http.StatusAccepted, dtos.RetryNodeResponse{
 		WorkflowID: workflowID,
 		ExecID:     req.ExecID,
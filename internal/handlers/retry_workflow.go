@@ -1,13 +1,17 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"ergo.services/ergo/gen"
 	"github.com/google/uuid"
+	"github.com/open-source-cloud/fuse/internal/audit"
+	"github.com/open-source-cloud/fuse/internal/auth"
 	"github.com/open-source-cloud/fuse/internal/dtos"
 	"github.com/open-source-cloud/fuse/internal/messaging"
 	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/internal/services"
 	internalworkflow "github.com/open-source-cloud/fuse/internal/workflow"
 	"github.com/open-source-cloud/fuse/pkg/workflow"
 )
@@ -16,8 +20,10 @@ type (
 	// RetryWorkflowHandler handles POST /v1/workflows/{workflowID}/retry
 	RetryWorkflowHandler struct {
 		Handler
-		workflowRepo repositories.WorkflowRepository
-		journalRepo  repositories.JournalRepository
+		workflowRepo     repositories.WorkflowRepository
+		journalRepo      repositories.JournalRepository
+		namespaceService services.NamespaceService
+		auditService     services.AuditService
 	}
 	// RetryWorkflowHandlerFactory is a factory for creating RetryWorkflowHandler actors
 	RetryWorkflowHandlerFactory HandlerFactory[*RetryWorkflowHandler]
@@ -34,12 +40,16 @@ const (
 func NewRetryWorkflowHandlerFactory(
 	workflowRepo repositories.WorkflowRepository,
 	journalRepo repositories.JournalRepository,
+	namespaceService services.NamespaceService,
+	auditService services.AuditService,
 ) *RetryWorkflowHandlerFactory {
 	return &RetryWorkflowHandlerFactory{
 		Factory: func() gen.ProcessBehavior {
 			return &RetryWorkflowHandler{
-				workflowRepo: workflowRepo,
-				journalRepo:  journalRepo,
+				workflowRepo:     workflowRepo,
+				journalRepo:      journalRepo,
+				namespaceService: namespaceService,
+				auditService:     auditService,
 			}
 		},
 	}
@@ -55,7 +65,9 @@ func NewRetryWorkflowHandlerFactory(
 // @Param request body dtos.RetryWorkflowRequest false "Retry strategy and optional exec ID"
 // @Success 202 {object} dtos.RetryWorkflowResponse
 // @Failure 400 {object} dtos.BadRequestError
+// @Failure 403 {object} dtos.ForbiddenError
 // @Failure 404 {object} dtos.NotFoundError
+// @Failure 429 {object} dtos.QuotaExceededError
 // @Failure 500 {object} dtos.InternalServerErrorResponse
 // @Router /v1/workflows/{workflowID}/retry [post]
 func (h *RetryWorkflowHandler) HandlePost(_ gen.PID, w http.ResponseWriter, r *http.Request) error {
@@ -68,9 +80,12 @@ func (h *RetryWorkflowHandler) HandlePost(_ gen.PID, w http.ResponseWriter, r *h
 	_ = h.BindJSON(w, r, &req) // all fields optional
 
 	wf, getErr := h.workflowRepo.Get(workflowID)
-	if getErr != nil {
+	if getErr != nil || !h.InNamespace(r, wf) {
 		return h.SendNotFound(w, "workflow not found", EmptyFields)
 	}
+	if err := h.Authorize(r, auth.PermWorkflowTrigger, workflowResource(wf)); err != nil {
+		return h.SendForbidden(w, err)
+	}
 
 	strategy := req.Strategy
 	if strategy == "" {
@@ -79,15 +94,23 @@ func (h *RetryWorkflowHandler) HandlePost(_ gen.PID, w http.ResponseWriter, r *h
 
 	switch strategy {
 	case "from-scratch":
-		return h.retryFromScratch(w, wf)
+		return h.retryFromScratch(w, r, wf)
 	case "from-failed":
-		return h.retryFromFailed(w, workflowID, wf, req.ExecID)
+		return h.retryFromFailed(w, r, workflowID, wf, req.ExecID)
 	default:
 		return h.SendBadRequest(w, nil, []string{"strategy must be 'from-scratch' or 'from-failed'"})
 	}
 }
 
-func (h *RetryWorkflowHandler) retryFromScratch(w http.ResponseWriter, wf *internalworkflow.Workflow) error {
+func (h *RetryWorkflowHandler) retryFromScratch(w http.ResponseWriter, r *http.Request, wf *internalworkflow.Workflow) error {
+	// a from-scratch retry is a new execution, so it counts against the concurrency quota
+	if err := h.namespaceService.CheckConcurrency(wf.Namespace()); err != nil {
+		if errors.Is(err, services.ErrNamespaceQuotaExceeded) {
+			return h.SendQuotaExceeded(w, err)
+		}
+		return h.SendInternalError(w, err)
+	}
+
 	newWfID := workflow.ID(uuid.New().String())
 	schemaID := wf.Graph().ID()
 
@@ -96,6 +119,11 @@ func (h *RetryWorkflowHandler) retryFromScratch(w http.ResponseWriter, wf *inter
 		return h.SendInternalError(w, err)
 	}
 
+	entry := workflowAuditEntry(audit.ActionWorkflowRetry, wf.ID().String(), wf)
+	entry.Details["strategy"] = "from-scratch"
+	entry.Details["newWorkflowId"] = newWfID.String()
+	h.auditService.Record(r.Context(), entry)
+
 	return h.SendJSON(w, http.StatusAccepted, dtos.RetryWorkflowResponse{
 		OriginalWorkflowID: wf.ID().String(),
 		NewWorkflowID:      newWfID.String(),
@@ -103,7 +131,7 @@ func (h *RetryWorkflowHandler) retryFromScratch(w http.ResponseWriter, wf *inter
 	})
 }
 
-func (h *RetryWorkflowHandler) retryFromFailed(w http.ResponseWriter, workflowID string, wf *internalworkflow.Workflow, execID string) error {
+func (h *RetryWorkflowHandler) retryFromFailed(w http.ResponseWriter, r *http.Request, workflowID string, wf *internalworkflow.Workflow, execID string) error {
 	if wf.State() != internalworkflow.StateError {
 		return h.SendBadRequest(w, nil, []string{"workflow must be in error state for from-failed retry"})
 	}
@@ -120,11 +148,16 @@ func (h *RetryWorkflowHandler) retryFromFailed(w http.ResponseWriter, workflowID
 		execID = failed[len(failed)-1].ExecID
 	}
 
-	retryMsg := messaging.NewRetryNodeMessage(workflow.ID(workflowID), workflow.ExecID(execID))
+	retryMsg := messaging.NewRetryNodeMessage(wf.Namespace(), workflow.ID(workflowID), workflow.ExecID(execID))
 	if err := h.Send(WorkflowSupervisorName, retryMsg); err != nil {
 		return h.SendInternalError(w, err)
 	}
 
+	entry := workflowAuditEntry(audit.ActionWorkflowRetry, workflowID, wf)
+	entry.Details["strategy"] = "from-failed"
+	entry.Details["execId"] = execID
+	h.auditService.Record(r.Context(), entry)
+
 	return h.SendJSON(w, http.StatusAccepted, dtos.RetryWorkflowResponse{
 		OriginalWorkflowID: workflowID,
 		Status:             "accepted",
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/auth"
+	"github.com/open-source-cloud/fuse/internal/dtos"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/internal/services"
+)
+
+const (
+	// RoleHandlerName is the name of the single-role handler.
+	RoleHandlerName = "role_handler"
+	// RoleHandlerPoolName is the name of the single-role handler pool.
+	RoleHandlerPoolName = "role_handler_pool"
+)
+
+type (
+	// RoleHandlerFactory is the factory for the single-role handler.
+	RoleHandlerFactory HandlerFactory[*RoleHandler]
+
+	// RoleHandler handles a single RBAC role.
+	RoleHandler struct {
+		Handler
+		policyService services.PolicyService
+	}
+)
+
+// NewRoleHandler creates a new single-role handler factory.
+func NewRoleHandler(policyService services.PolicyService) *RoleHandlerFactory {
+	return &RoleHandlerFactory{
+		Factory: func() gen.ProcessBehavior {
+			return &RoleHandler{policyService: policyService}
+		},
+	}
+}
+
+// HandleGet retrieves a role (GET /v1/roles/{name})
+// @Summary Get role by name
+// @Description Retrieve a single RBAC role
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param name path string true "Role name"
+// @Success 200 {object} dtos.RoleDTO
+// @Failure 403 {object} dtos.ForbiddenError
+// @Failure 404 {object} dtos.NotFoundError
+// @Failure 500 {object} dtos.InternalServerErrorResponse
+// @Router /v1/roles/{name} [get]
+func (h *RoleHandler) HandleGet(from gen.PID, w http.ResponseWriter, r *http.Request) error {
+	h.Log().Info("received get role request from: %v remoteAddr: %s", from, r.RemoteAddr)
+
+	name, err := h.GetPathParam(r, "name")
+	if err != nil {
+		return h.SendBadRequest(w, err, []string{"name is required"})
+	}
+
+	if err := h.Authorize(r, auth.PermAdmin, auth.Resource{}); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
+	role, err := h.policyService.FindRole(name)
+	if err != nil {
+		if errors.Is(err, repositories.ErrRoleNotFound) {
+			return h.SendNotFound(w, fmt.Sprintf("role %s not found", name), []string{"name"})
+		}
+		return h.SendInternalError(w, err)
+	}
+	return h.SendJSON(w, http.StatusOK, dtos.ToRoleDTO(role))
+}
+
+// HandlePut creates or replaces a role (PUT /v1/roles/{name})
+// @Summary Create or update role
+// @Description Upsert an RBAC role; the path name is authoritative. Changes apply to the next request of every bound subject.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param name path string true "Role name"
+// @Param role body dtos.RoleDTO true "Role"
+// @Success 200 {object} dtos.RoleDTO
+// @Failure 400 {object} dtos.BadRequestError
+// @Failure 403 {object} dtos.ForbiddenError
+// @Failure 500 {object} dtos.InternalServerErrorResponse
+// @Router /v1/roles/{name} [put]
+func (h *RoleHandler) HandlePut(from gen.PID, w http.ResponseWriter, r *http.Request) error {
+	h.Log().Info("received upsert role request from: %v remoteAddr: %s", from, r.RemoteAddr)
+
+	name, err := h.GetPathParam(r, "name")
+	if err != nil {
+		return h.SendBadRequest(w, err, []string{"name is required"})
+	}
+
+	if err := h.Authorize(r, auth.PermAdmin, auth.Resource{}); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
+	var dto dtos.RoleDTO
+	if bindErr := h.BindJSON(w, r, &dto); bindErr != nil {
+		return h.SendBadRequest(w, bindErr, []string{"body"})
+	}
+	dto.Name = name
+
+	role, err := h.policyService.SaveRole(dtos.FromRoleDTO(dto))
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidRole) || errors.Is(err, services.ErrBuiltinRole) {
+			return h.SendBadRequest(w, err, []string{"rules"})
+		}
+		return h.SendInternalError(w, err)
+	}
+	return h.SendJSON(w, http.StatusOK, dtos.ToRoleDTO(role))
+}
+
+// HandleDelete removes a role and its bindings (DELETE /v1/roles/{name})
+// @Summary Delete role
+// @Description Delete an RBAC role and unbind it from every subject (the built-in admin role cannot be deleted)
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param name path string true "Role name"
+// @Success 204 "No Content"
+// @Failure 400 {object} dtos.BadRequestError
+// @Failure 403 {object} dtos.ForbiddenError
+// @Failure 404 {object} dtos.NotFoundError
+// @Failure 500 {object} dtos.InternalServerErrorResponse
+// @Router /v1/roles/{name} [delete]
+func (h *RoleHandler) HandleDelete(from gen.PID, w http.ResponseWriter, r *http.Request) error {
+	h.Log().Info("received delete role request from: %v remoteAddr: %s", from, r.RemoteAddr)
+
+	name, err := h.GetPathParam(r, "name")
+	if err != nil {
+		return h.SendBadRequest(w, err, []string{"name is required"})
+	}
+
+	if err := h.Authorize(r, auth.PermAdmin, auth.Resource{}); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
+	if err := h.policyService.DeleteRole(name); err != nil {
+		switch {
+		case errors.Is(err, services.ErrBuiltinRole):
+			return h.SendBadRequest(w, err, []string{"name"})
+		case errors.Is(err, repositories.ErrRoleNotFound):
+			return h.SendNotFound(w, fmt.Sprintf("role %s not found", name), []string{"name"})
+		}
+		return h.SendInternalError(w, err)
+	}
+	return h.SendJSON(w, http.StatusNoContent, nil)
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/auth"
+	"github.com/open-source-cloud/fuse/internal/dtos"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/internal/services"
+)
+
+const (
+	// RoleBindingsHandlerName is the name of the role bindings handler.
+	RoleBindingsHandlerName = "role_bindings_handler"
+	// RoleBindingsHandlerPoolName is the name of the role bindings handler pool.
+	RoleBindingsHandlerPoolName = "role_bindings_handler_pool"
+)
+
+type (
+	// RoleBindingsHandlerFactory is the factory for the role bindings handler.
+	RoleBindingsHandlerFactory HandlerFactory[*RoleBindingsHandler]
+
+	// RoleBindingsHandler lists, creates and deletes role bindings.
+	RoleBindingsHandler struct {
+		Handler
+		policyService services.PolicyService
+	}
+)
+
+// NewRoleBindingsHandler creates a new role bindings handler factory.
+func NewRoleBindingsHandler(policyService services.PolicyService) *RoleBindingsHandlerFactory {
+	return &RoleBindingsHandlerFactory{
+		Factory: func() gen.ProcessBehavior {
+			return &RoleBindingsHandler{policyService: policyService}
+		},
+	}
+}
+
+// HandleGet lists role bindings (GET /v1/role-bindings)
+// @Summary List role bindings
+// @Description Retrieve all role bindings, or those of one subject
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param subject query string false "Only bindings of this subject (apikey:<id> or a JWT sub)"
+// @Success 200 {object} dtos.RoleBindingListResponse
+// @Failure 403 {object} dtos.ForbiddenError
+// @Failure 500 {object} dtos.InternalServerErrorResponse
+// @Router /v1/role-bindings [get]
+func (h *RoleBindingsHandler) HandleGet(from gen.PID, w http.ResponseWriter, r *http.Request) error {
+	h.Log().Info("received list role bindings request from: %v remoteAddr: %s", from, r.RemoteAddr)
+
+	if err := h.Authorize(r, auth.PermAdmin, auth.Resource{}); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
+	bindings, err := h.policyService.FindBindings(r.URL.Query().Get("subject"))
+	if err != nil {
+		return h.SendInternalError(w, err)
+	}
+
+	items := make([]dtos.RoleBindingDTO, len(bindings))
+	for i, b := range bindings {
+		items[i] = dtos.ToRoleBindingDTO(b)
+	}
+	return h.SendJSON(w, http.StatusOK, dtos.RoleBindingListResponse{Items: items})
+}
+
+// HandlePost binds a role to a subject (POST /v1/role-bindings)
+// @Summary Create role binding
+// @Description Grant a role to a subject; binding an already bound role is a no-op
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dtos.CreateRoleBindingRequest true "Role binding"
+// @Success 201 {object} dtos.RoleBindingDTO
+// @Failure 400 {object} dtos.BadRequestError
+// @Failure 403 {object} dtos.ForbiddenError
+// @Failure 404 {object} dtos.NotFoundError
+// @Failure 500 {object} dtos.InternalServerErrorResponse
+// @Router /v1/role-bindings [post]
+func (h *RoleBindingsHandler) HandlePost(from gen.PID, w http.ResponseWriter, r *http.Request) error {
+	h.Log().Info("received create role binding request from: %v remoteAddr: %s", from, r.RemoteAddr)
+
+	if err := h.Authorize(r, auth.PermAdmin, auth.Resource{}); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
+	var req dtos.CreateRoleBindingRequest
+	if err := h.BindJSON(w, r, &req); err != nil {
+		return h.SendBadRequest(w, err, []string{"body"})
+	}
+
+	binding, err := h.policyService.Bind(req.Subject, req.Role)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidRoleBinding):
+			return h.SendBadRequest(w, err, []string{"subject"})
+		case errors.Is(err, repositories.ErrRoleNotFound):
+			return h.SendNotFound(w, fmt.Sprintf("role %s not found", req.Role), []string{"role"})
+		}
+		return h.SendInternalError(w, err)
+	}
+	return h.SendJSON(w, http.StatusCreated, dtos.ToRoleBindingDTO(binding))
+}
+
+// HandleDelete removes a role binding (DELETE /v1/role-bindings?subject=&role=)
+// @Summary Delete role binding
+// @Description Revoke a role from a subject
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param subject query string true "Subject"
+// @Param role query string true "Role name"
+// @Success 204 "No Content"
+// @Failure 400 {object} dtos.BadRequestError
+// @Failure 403 {object} dtos.ForbiddenError
+// @Failure 404 {object} dtos.NotFoundError
+// @Failure 500 {object} dtos.InternalServerErrorResponse
+// @Router /v1/role-bindings [delete]
+func (h *RoleBindingsHandler) HandleDelete(from gen.PID, w http.ResponseWriter, r *http.Request) error {
+	h.Log().Info("received delete role binding request from: %v remoteAddr: %s", from, r.RemoteAddr)
+
+	subject, err := h.GetQueryParam(r, "subject")
+	if err != nil {
+		return h.SendBadRequest(w, err, []string{"subject"})
+	}
+	role, err := h.GetQueryParam(r, "role")
+	if err != nil {
+		return h.SendBadRequest(w, err, []string{"role"})
+	}
+
+	if err := h.Authorize(r, auth.PermAdmin, auth.Resource{}); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
+	if err := h.policyService.Unbind(subject, role); err != nil {
+		if errors.Is(err, repositories.ErrRoleBindingNotFound) {
+			return h.SendNotFound(w, fmt.Sprintf("%s is not bound to role %s", subject, role), []string{"subject", "role"})
+		}
+		return h.SendInternalError(w, err)
+	}
+	return h.SendJSON(w, http.StatusNoContent, nil)
+}
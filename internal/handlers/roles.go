@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+
+	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/auth"
+	"github.com/open-source-cloud/fuse/internal/dtos"
+	"github.com/open-source-cloud/fuse/internal/services"
+)
+
+const (
+	// RolesHandlerName is the name of the roles collection handler.
+	RolesHandlerName = "roles_handler"
+	// RolesHandlerPoolName is the name of the roles collection handler pool.
+	RolesHandlerPoolName = "roles_handler_pool"
+)
+
+type (
+	// RolesHandlerFactory is the factory for the roles collection handler.
+	RolesHandlerFactory HandlerFactory[*RolesHandler]
+
+	// RolesHandler lists RBAC roles.
+	RolesHandler struct {
+		Handler
+		policyService services.PolicyService
+	}
+)
+
+// NewRolesHandler creates a new roles collection handler factory.
+func NewRolesHandler(policyService services.PolicyService) *RolesHandlerFactory {
+	return &RolesHandlerFactory{
+		Factory: func() gen.ProcessBehavior {
+			return &RolesHandler{policyService: policyService}
+		},
+	}
+}
+
+// HandleGet lists roles (GET /v1/roles)
+// @Summary List roles
+// @Description Retrieve all RBAC roles, starting with the built-in admin role
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} dtos.RoleListResponse
+// @Failure 403 {object} dtos.ForbiddenError
+// @Failure 500 {object} dtos.InternalServerErrorResponse
+// @Router /v1/roles [get]
+func (h *RolesHandler) HandleGet(from gen.PID, w http.ResponseWriter, r *http.Request) error {
+	h.Log().Info("received list roles request from: %v remoteAddr: %s", from, r.RemoteAddr)
+
+	if err := h.Authorize(r, auth.PermAdmin, auth.Resource{}); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
+	roles, err := h.policyService.FindAllRoles()
+	if err != nil {
+		return h.SendInternalError(w, err)
+	}
+
+	items := make([]dtos.RoleDTO, len(roles))
+	for i, role := range roles {
+		items[i] = dtos.ToRoleDTO(role)
+	}
+	return h.SendJSON(w, http.StatusOK, dtos.RoleListResponse{Items: items})
+}
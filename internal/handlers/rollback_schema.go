@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/auth"
 	"github.com/open-source-cloud/fuse/internal/dtos"
 	"github.com/open-source-cloud/fuse/internal/repositories"
 	"github.com/open-source-cloud/fuse/internal/services"
@@ -48,17 +49,22 @@ func NewRollbackSchemaHandlerFactory(graphService services.GraphService) *Rollba
 // @Param request body dtos.RollbackRequest true "Rollback Request"
 // @Success 200 {object} dtos.RollbackResponse
 // @Failure 400 {object} dtos.BadRequestError
+// @Failure 403 {object} dtos.ForbiddenError
 // @Failure 404 {object} dtos.NotFoundError
 // @Failure 500 {object} dtos.InternalServerErrorResponse
 // @Router /v1/schemas/{schemaID}/rollback [post]
 func (h *RollbackSchemaHandler) HandlePost(from gen.PID, w http.ResponseWriter, r *http.Request) error {
 	h.Log().Info("received rollback schema request", "from", from, "remoteAddr", r.RemoteAddr)
 
-	schemaID, err := h.GetPathParam(r, "schemaID")
+	schemaID, err := h.SchemaIDParam(r)
 	if err != nil {
 		return h.SendBadRequest(w, err, []string{"schemaID is required"})
 	}
 
+	if err := h.Authorize(r, auth.PermSchemaWrite, auth.NewSchemaResource(schemaID, "")); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
 	var req dtos.RollbackRequest
 	if err := h.BindJSON(w, r, &req); err != nil {
 		return h.SendBadRequest(w, err, []string{"body"})
@@ -67,7 +73,7 @@ func (h *RollbackSchemaHandler) HandlePost(from gen.PID, w http.ResponseWriter,
 		return h.SendBadRequest(w, errors.New("version must be a positive integer"), []string{"version"})
 	}
 
-	sv, err := h.graphService.Rollback(schemaID, req.Version, req.Comment)
+	sv, err := h.graphService.Rollback(r.Context(), schemaID, req.Version, req.Comment)
 	if err != nil {
 		if errors.Is(err, repositories.ErrSchemaVersionNotFound) {
 			return h.SendNotFound(w, "schema version not found", EmptyFields)
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/auth"
+	"github.com/open-source-cloud/fuse/internal/dtos"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/internal/services"
+	"github.com/open-source-cloud/fuse/internal/workflow"
+)
+
+const (
+	// SchemaLifecycleHandlerName is the actor name for reading and changing a schema's lifecycle.
+	SchemaLifecycleHandlerName = "schema_lifecycle_handler"
+	// SchemaLifecycleHandlerPoolName is the worker pool for SchemaLifecycleHandler.
+	SchemaLifecycleHandlerPoolName = "schema_lifecycle_handler_pool"
+)
+
+type (
+	// SchemaLifecycleHandlerFactory creates SchemaLifecycleHandler actors.
+	SchemaLifecycleHandlerFactory HandlerFactory[*SchemaLifecycleHandler]
+	// SchemaLifecycleHandler serves GET and PUT /v1/schemas/{schemaID}/lifecycle.
+	SchemaLifecycleHandler struct {
+		Handler
+		lifecycleService services.SchemaLifecycleService
+	}
+)
+
+// NewSchemaLifecycleHandlerFactory builds a factory for SchemaLifecycleHandler.
+func NewSchemaLifecycleHandlerFactory(lifecycleService services.SchemaLifecycleService) *SchemaLifecycleHandlerFactory {
+	return &SchemaLifecycleHandlerFactory{
+		Factory: func() gen.ProcessBehavior {
+			return &SchemaLifecycleHandler{
+				lifecycleService: lifecycleService,
+			}
+		},
+	}
+}
+
+// HandleGet handles GET /v1/schemas/{schemaID}/lifecycle.
+// @Summary Get schema lifecycle
+// @Description Return the lifecycle state (active, deprecated, archived) of a schema
+// @Tags schemas
+// @Accept json
+// @Produce json
+// @Param schemaID path string true "Schema ID"
+// @Success 200 {object} dtos.SchemaLifecycleResponse
+// @Failure 400 {object} dtos.BadRequestError
+// @Failure 404 {object} dtos.NotFoundError
+// @Failure 500 {object} dtos.InternalServerErrorResponse
+// @Router /v1/schemas/{schemaID}/lifecycle [get]
+func (h *SchemaLifecycleHandler) HandleGet(from gen.PID, w http.ResponseWriter, r *http.Request) error {
+	h.Log().Info("received get schema lifecycle request", "from", from, "remoteAddr", r.RemoteAddr)
+
+	schemaID, err := h.SchemaIDParam(r)
+	if err != nil {
+		return h.SendBadRequest(w, err, []string{"schemaID is required"})
+	}
+
+	lifecycle, err := h.lifecycleService.Find(schemaID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrGraphNotFound) {
+			return h.SendNotFound(w, fmt.Sprintf("schema %s not found", schemaID), EmptyFields)
+		}
+		return h.SendInternalError(w, err)
+	}
+
+	return h.SendJSON(w, http.StatusOK, dtos.SchemaLifecycleResponse{
+		SchemaID:  schemaID,
+		Lifecycle: string(lifecycle),
+	})
+}
+
+// HandlePut handles PUT /v1/schemas/{schemaID}/lifecycle.
+// @Summary Change schema lifecycle
+// @Description Deprecate (reject new executions), archive (also unregister cron, webhook and event triggers) or reactivate a schema
+// @Tags schemas
+// @Accept json
+// @Produce json
+// @Param schemaID path string true "Schema ID"
+// @Param request body dtos.SchemaLifecycleRequest true "Lifecycle Request"
+// @Success 200 {object} dtos.SchemaLifecycleResponse
+// @Failure 400 {object} dtos.BadRequestError
+// @Failure 403 {object} dtos.ForbiddenError
+// @Failure 404 {object} dtos.NotFoundError
+// @Failure 500 {object} dtos.InternalServerErrorResponse
+// @Router /v1/schemas/{schemaID}/lifecycle [put]
+func (h *SchemaLifecycleHandler) HandlePut(from gen.PID, w http.ResponseWriter, r *http.Request) error {
+	h.Log().Info("received change schema lifecycle request", "from", from, "remoteAddr", r.RemoteAddr)
+
+	schemaID, err := h.SchemaIDParam(r)
+	if err != nil {
+		return h.SendBadRequest(w, err, []string{"schemaID is required"})
+	}
+
+	if err := h.Authorize(r, auth.PermSchemaWrite, auth.NewSchemaResource(schemaID, "")); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
+	var req dtos.SchemaLifecycleRequest
+	if err := h.BindJSON(w, r, &req); err != nil {
+		return h.SendBadRequest(w, err, []string{"body"})
+	}
+
+	lifecycle := workflow.SchemaLifecycle(req.Lifecycle)
+	if err := h.lifecycleService.Transition(schemaID, lifecycle); err != nil {
+		if errors.Is(err, services.ErrInvalidSchemaLifecycle) {
+			return h.SendBadRequest(w, err, []string{"lifecycle"})
+		}
+		if errors.Is(err, repositories.ErrGraphNotFound) {
+			return h.SendNotFound(w, fmt.Sprintf("schema %s not found", schemaID), EmptyFields)
+		}
+		return h.SendInternalError(w, err)
+	}
+
+	h.Log().Info("schema lifecycle changed", "schemaID", schemaID, "lifecycle", lifecycle)
+
+	return h.SendJSON(w, http.StatusOK, dtos.SchemaLifecycleResponse{
+		SchemaID:  schemaID,
+		Lifecycle: string(lifecycle),
+	})
+}
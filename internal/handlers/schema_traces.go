@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/auth"
 	"github.com/open-source-cloud/fuse/internal/dtos"
 	"github.com/open-source-cloud/fuse/internal/repositories"
 )
@@ -50,12 +51,16 @@ func NewSchemaTracesHandlerFactory(traceRepo repositories.TraceRepository) *Sche
 // @Param since query string false "Only traces after this time (RFC3339)"
 // @Success 200 {object} dtos.TraceListResponse
 // @Failure 400 {object} dtos.BadRequestError
+// @Failure 403 {object} dtos.ForbiddenError
 // @Router /v1/schemas/{schemaID}/traces [get]
 func (h *SchemaTracesHandler) HandleGet(_ gen.PID, w http.ResponseWriter, r *http.Request) error {
-	schemaID, err := h.GetPathParam(r, "schemaID")
+	schemaID, err := h.SchemaIDParam(r)
 	if err != nil {
 		return h.SendBadRequest(w, err, EmptyFields)
 	}
+	if err := h.Authorize(r, auth.PermWorkflowRead, auth.NewSchemaResource(schemaID, "")); err != nil {
+		return h.SendForbidden(w, err)
+	}
 
 	opts := repositories.TraceQueryOpts{}
 
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/auth"
+	"github.com/open-source-cloud/fuse/internal/dtos"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/internal/services"
+	"github.com/open-source-cloud/fuse/internal/workflow"
+)
+
+const (
+	// SchemaTrafficHandlerName is the actor name for managing a schema's canary traffic split.
+	SchemaTrafficHandlerName = "schema_traffic_handler"
+	// SchemaTrafficHandlerPoolName is the worker pool for SchemaTrafficHandler.
+	SchemaTrafficHandlerPoolName = "schema_traffic_handler_pool"
+)
+
+type (
+	// SchemaTrafficHandlerFactory creates SchemaTrafficHandler actors.
+	SchemaTrafficHandlerFactory HandlerFactory[*SchemaTrafficHandler]
+	// SchemaTrafficHandler serves GET, PUT and DELETE /v1/schemas/{schemaID}/traffic.
+	SchemaTrafficHandler struct {
+		Handler
+		trafficSplitService services.TrafficSplitService
+	}
+)
+
+// NewSchemaTrafficHandlerFactory builds a factory for SchemaTrafficHandler.
+func NewSchemaTrafficHandlerFactory(trafficSplitService services.TrafficSplitService) *SchemaTrafficHandlerFactory {
+	return &SchemaTrafficHandlerFactory{
+		Factory: func() gen.ProcessBehavior {
+			return &SchemaTrafficHandler{
+				trafficSplitService: trafficSplitService,
+			}
+		},
+	}
+}
+
+// HandleGet handles GET /v1/schemas/{schemaID}/traffic.
+// @Summary Get schema traffic split
+// @Description Return the canary traffic split of a schema with per-version success and failure counts since it was configured
+// @Tags schemas
+// @Accept json
+// @Produce json
+// @Param schemaID path string true "Schema ID"
+// @Success 200 {object} dtos.TrafficSplitResponse
+// @Failure 400 {object} dtos.BadRequestError
+// @Failure 404 {object} dtos.NotFoundError
+// @Failure 500 {object} dtos.InternalServerErrorResponse
+// @Router /v1/schemas/{schemaID}/traffic [get]
+func (h *SchemaTrafficHandler) HandleGet(from gen.PID, w http.ResponseWriter, r *http.Request) error {
+	h.Log().Info("received get schema traffic request", "from", from, "remoteAddr", r.RemoteAddr)
+
+	schemaID, err := h.SchemaIDParam(r)
+	if err != nil {
+		return h.SendBadRequest(w, err, []string{"schemaID is required"})
+	}
+
+	split, err := h.trafficSplitService.Find(schemaID)
+	if err != nil {
+		return h.sendServiceError(w, schemaID, err)
+	}
+	return h.sendSplit(w, schemaID, split)
+}
+
+// HandlePut handles PUT /v1/schemas/{schemaID}/traffic.
+// @Summary Set schema traffic split
+// @Description Route new executions across schema versions by weight (weights add up to 100), optionally sticky by an expression over the trigger input, with an optional automatic rollback rule. Replacing a split resets its statistics.
+// @Tags schemas
+// @Accept json
+// @Produce json
+// @Param schemaID path string true "Schema ID"
+// @Param request body dtos.TrafficSplitRequest true "Traffic Split Request"
+// @Success 200 {object} dtos.TrafficSplitResponse
+// @Failure 400 {object} dtos.BadRequestError
+// @Failure 403 {object} dtos.ForbiddenError
+// @Failure 404 {object} dtos.NotFoundError
+// @Failure 500 {object} dtos.InternalServerErrorResponse
+// @Router /v1/schemas/{schemaID}/traffic [put]
+func (h *SchemaTrafficHandler) HandlePut(from gen.PID, w http.ResponseWriter, r *http.Request) error {
+	h.Log().Info("received set schema traffic request", "from", from, "remoteAddr", r.RemoteAddr)
+
+	schemaID, err := h.SchemaIDParam(r)
+	if err != nil {
+		return h.SendBadRequest(w, err, []string{"schemaID is required"})
+	}
+
+	if err := h.Authorize(r, auth.PermSchemaWrite, auth.NewSchemaResource(schemaID, "")); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
+	var req dtos.TrafficSplitRequest
+	if err := h.BindJSON(w, r, &req); err != nil {
+		return h.SendBadRequest(w, err, []string{"body"})
+	}
+
+	split := &workflow.TrafficSplit{
+		Routes:       req.Routes,
+		StickyKey:    req.StickyKey,
+		AutoRollback: req.AutoRollback,
+	}
+	if err := h.trafficSplitService.Set(schemaID, split); err != nil {
+		if errors.Is(err, services.ErrInvalidTrafficSplit) {
+			return h.SendBadRequest(w, err, []string{"routes"})
+		}
+		return h.sendServiceError(w, schemaID, err)
+	}
+
+	h.Log().Info("schema traffic split configured", "schemaID", schemaID)
+	return h.sendSplit(w, schemaID, split)
+}
+
+// HandleDelete handles DELETE /v1/schemas/{schemaID}/traffic.
+// @Summary Remove schema traffic split
+// @Description Remove the traffic split so every new execution runs the active version
+// @Tags schemas
+// @Accept json
+// @Produce json
+// @Param schemaID path string true "Schema ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} dtos.BadRequestError
+// @Failure 403 {object} dtos.ForbiddenError
+// @Failure 404 {object} dtos.NotFoundError
+// @Failure 500 {object} dtos.InternalServerErrorResponse
+// @Router /v1/schemas/{schemaID}/traffic [delete]
+func (h *SchemaTrafficHandler) HandleDelete(from gen.PID, w http.ResponseWriter, r *http.Request) error {
+	h.Log().Info("received remove schema traffic request", "from", from, "remoteAddr", r.RemoteAddr)
+
+	schemaID, err := h.SchemaIDParam(r)
+	if err != nil {
+		return h.SendBadRequest(w, err, []string{"schemaID is required"})
+	}
+
+	if err := h.Authorize(r, auth.PermSchemaWrite, auth.NewSchemaResource(schemaID, "")); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
+	if err := h.trafficSplitService.Clear(schemaID); err != nil {
+		return h.sendServiceError(w, schemaID, err)
+	}
+	return h.SendJSON(w, http.StatusNoContent, nil)
+}
+
+func (h *SchemaTrafficHandler) sendSplit(w http.ResponseWriter, schemaID string, split *workflow.TrafficSplit) error {
+	stats, err := h.trafficSplitService.Stats(schemaID)
+	if err != nil {
+		return h.sendServiceError(w, schemaID, err)
+	}
+	resp := dtos.TrafficSplitResponse{
+		SchemaID: schemaID,
+		Split:    split,
+		Stats:    make([]dtos.TrafficVersionStats, 0, len(stats)),
+	}
+	for _, vs := range stats {
+		resp.Stats = append(resp.Stats, dtos.TrafficVersionStats(vs))
+	}
+	return h.SendJSON(w, http.StatusOK, resp)
+}
+
+func (h *SchemaTrafficHandler) sendServiceError(w http.ResponseWriter, schemaID string, err error) error {
+	if errors.Is(err, repositories.ErrGraphNotFound) {
+		return h.SendNotFound(w, fmt.Sprintf("schema %s not found", schemaID), EmptyFields)
+	}
+	return h.SendInternalError(w, err)
+}
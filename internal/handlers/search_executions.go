@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net/http"
+
+	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/auth"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+)
+
+type (
+	// SearchExecutionsHandler handles GET /v1/executions
+	SearchExecutionsHandler struct {
+		Handler
+		workflowRepo repositories.WorkflowRepository
+	}
+	// SearchExecutionsHandlerFactory is a factory for creating SearchExecutionsHandler actors
+	SearchExecutionsHandlerFactory HandlerFactory[*SearchExecutionsHandler]
+)
+
+const (
+	// SearchExecutionsHandlerName is the name of the SearchExecutionsHandler actor
+	SearchExecutionsHandlerName = "search_executions_handler"
+	// SearchExecutionsHandlerPoolName is the name of the SearchExecutionsHandler pool
+	SearchExecutionsHandlerPoolName = "search_executions_handler_pool"
+)
+
+// NewSearchExecutionsHandlerFactory creates a new SearchExecutionsHandlerFactory
+func NewSearchExecutionsHandlerFactory(workflowRepo repositories.WorkflowRepository) *SearchExecutionsHandlerFactory {
+	return &SearchExecutionsHandlerFactory{
+		Factory: func() gen.ProcessBehavior {
+			return &SearchExecutionsHandler{
+				workflowRepo: workflowRepo,
+			}
+		},
+	}
+}
+
+// HandleGet handles GET /v1/executions
+// @Summary Search workflow executions across schemas
+// @Description Returns a paginated list of workflow executions of any schema in the request's namespace, typically filtered by search attribute (e.g. attr.orderId=12345)
+// @Tags workflows
+// @Produce json
+// @Param schemaId query string false "Restrict the search to one schema"
+// @Param attr.name query string false "Filter by search attribute value, e.g. attr.orderId=12345 (repeatable with different names)"
+// @Param page query int false "Page number (default: 1)"
+// @Param size query int false "Page size (default: 20, max: 100)"
+// @Param status query string false "Filter by workflow state (e.g. finished, error, running)"
+// @Param version query int false "Filter by the schema version the execution ran on"
+// @Param from query string false "Filter by created_at >= (RFC3339 format)"
+// @Param to query string false "Filter by created_at <= (RFC3339 format)"
+// @Success 200 {object} object "Paginated list with items, total, page, size, lastPage"
+// @Failure 400 {object} dtos.BadRequestError
+// @Failure 403 {object} dtos.ForbiddenError
+// @Failure 500 {object} dtos.InternalServerErrorResponse
+// @Router /v1/executions [get]
+func (h *SearchExecutionsHandler) HandleGet(_ gen.PID, w http.ResponseWriter, r *http.Request) error {
+	q := r.URL.Query()
+	filter, fields, err := parseExecutionListFilter(q)
+	if err != nil {
+		return h.SendBadRequest(w, err, fields)
+	}
+	filter.Namespace = h.Namespace(r)
+	resource := auth.Resource{Namespace: filter.Namespace}
+	if schemaID := q.Get("schemaId"); schemaID != "" {
+		if filter.SchemaID, err = h.QualifyID(r, schemaID); err != nil {
+			return h.SendBadRequest(w, err, []string{"schemaId"})
+		}
+		resource = auth.NewSchemaResource(filter.SchemaID, "")
+	}
+	if err := h.Authorize(r, auth.PermWorkflowRead, resource); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
+	result, findErr := h.workflowRepo.FindExecutions(filter)
+	if findErr != nil {
+		return h.SendInternalError(w, findErr)
+	}
+
+	return h.SendJSON(w, http.StatusOK, result)
+}
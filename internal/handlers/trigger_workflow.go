@@ -2,6 +2,7 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -9,9 +10,11 @@ import (
 
 	"ergo.services/ergo/gen"
 	"github.com/open-source-cloud/fuse/internal/app/config"
+	"github.com/open-source-cloud/fuse/internal/auth"
 	"github.com/open-source-cloud/fuse/internal/dtos"
 	"github.com/open-source-cloud/fuse/internal/idempotency"
 	"github.com/open-source-cloud/fuse/internal/messaging"
+	"github.com/open-source-cloud/fuse/internal/repositories"
 	"github.com/open-source-cloud/fuse/internal/services"
 )
 
@@ -23,6 +26,9 @@ type (
 		idempotencyTTL     config.IdempotencyConfig
 		defaultEnvironment string
 		environmentService services.EnvironmentService
+		lifecycleService   services.SchemaLifecycleService
+		graphService       services.GraphService
+		namespaceService   services.NamespaceService
 	}
 	// TriggerWorkflowHandlerFactory is a factory for creating TriggerWorkflowHandler actors
 	TriggerWorkflowHandlerFactory HandlerFactory[*TriggerWorkflowHandler]
@@ -38,7 +44,14 @@ const (
 )
 
 // NewTriggerWorkflowHandlerFactory creates a new TriggerWorkflowHandlerFactory
-func NewTriggerWorkflowHandlerFactory(store idempotency.Store, cfg *config.Config, environmentService services.EnvironmentService) *TriggerWorkflowHandlerFactory {
+func NewTriggerWorkflowHandlerFactory(
+	store idempotency.Store,
+	cfg *config.Config,
+	environmentService services.EnvironmentService,
+	lifecycleService services.SchemaLifecycleService,
+	graphService services.GraphService,
+	namespaceService services.NamespaceService,
+) *TriggerWorkflowHandlerFactory {
 	return &TriggerWorkflowHandlerFactory{
 		Factory: func() gen.ProcessBehavior {
 			return &TriggerWorkflowHandler{
@@ -46,6 +59,9 @@ func NewTriggerWorkflowHandlerFactory(store idempotency.Store, cfg *config.Confi
 				idempotencyTTL:     cfg.Idempotency,
 				defaultEnvironment: cfg.Environment,
 				environmentService: environmentService,
+				lifecycleService:   lifecycleService,
+				graphService:       graphService,
+				namespaceService:   namespaceService,
 			}
 		},
 	}
@@ -53,13 +69,17 @@ func NewTriggerWorkflowHandlerFactory(store idempotency.Store, cfg *config.Confi
 
 // HandlePost handles the http TriggerWorkflow endpoint (POST /v1/workflows/trigger)
 // @Summary Trigger workflow execution
-// @Description Triggers a new workflow instance from a schema
+// @Description Triggers a new workflow instance from a schema of the request's namespace
 // @Tags workflows
 // @Accept json
 // @Produce json
 // @Param request body dtos.TriggerWorkflowRequest true "Trigger Request"
 // @Success 200 {object} dtos.TriggerWorkflowResponse
 // @Failure 400 {object} dtos.BadRequestError
+// @Failure 403 {object} dtos.ForbiddenError
+// @Failure 404 {object} dtos.NotFoundError
+// @Failure 409 {object} dtos.ConflictError
+// @Failure 429 {object} dtos.QuotaExceededError
 // @Failure 500 {object} dtos.InternalServerErrorResponse
 // @Router /v1/workflows/trigger [post]
 func (h *TriggerWorkflowHandler) HandlePost(from gen.PID, w http.ResponseWriter, r *http.Request) error {
@@ -73,10 +93,23 @@ func (h *TriggerWorkflowHandler) HandlePost(from gen.PID, w http.ResponseWriter,
 	if req.SchemaID == "" {
 		return h.SendBadRequest(w, fmt.Errorf("schemaID is required"), []string{"schemaID"})
 	}
+	schemaID, err := h.QualifyID(r, req.SchemaID)
+	if err != nil {
+		return h.SendBadRequest(w, err, []string{"schemaID"})
+	}
 
-	// Check idempotency key
+	environment := req.Environment
+	if environment == "" {
+		environment = h.defaultEnvironment
+	}
+	if err := h.Authorize(r, auth.PermWorkflowTrigger, auth.NewSchemaResource(schemaID, environment)); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
+	// Check idempotency key; keys are scoped to the namespace so tenants cannot collide
+	idempotencyKey := workflow.QualifyID(h.Namespace(r), req.IdempotencyKey)
 	if req.IdempotencyKey != "" {
-		if existingID, exists := h.idempotencyStore.Check(req.IdempotencyKey); exists {
+		if existingID, exists := h.idempotencyStore.Check(idempotencyKey); exists {
 			return h.SendJSON(w, http.StatusOK, dtos.TriggerWorkflowResponse{
 				SchemaID:     req.SchemaID,
 				WorkflowID:   existingID,
@@ -86,22 +119,47 @@ func (h *TriggerWorkflowHandler) HandlePost(from gen.PID, w http.ResponseWriter,
 		}
 	}
 
-	environment := req.Environment
-	if environment == "" {
-		environment = h.defaultEnvironment
+	// Unknown schemas keep failing asynchronously in the supervisor, as before lifecycles existed
+	if err := h.lifecycleService.CheckAcceptsExecutions(schemaID); err != nil {
+		if errors.Is(err, services.ErrSchemaNotAcceptingExecutions) {
+			return h.SendConflict(w, err, []string{"schemaID"})
+		}
+		if !errors.Is(err, repositories.ErrGraphNotFound) {
+			return h.SendInternalError(w, err)
+		}
+	}
+
+	if req.Version < 0 {
+		return h.SendBadRequest(w, fmt.Errorf("invalid version %d", req.Version), []string{"version"})
+	}
+	if req.Version > 0 {
+		if _, err := h.graphService.FindByIDAndVersion(schemaID, req.Version); err != nil {
+			if errors.Is(err, repositories.ErrSchemaVersionNotFound) {
+				return h.SendNotFound(w, fmt.Sprintf("version %d of schema %s not found", req.Version, req.SchemaID), []string{"version"})
+			}
+			return h.SendInternalError(w, err)
+		}
 	}
+
 	if !h.environmentService.IsValid(environment) {
 		return h.SendBadRequest(w, fmt.Errorf("unknown environment %q", environment), []string{"environment"})
 	}
 
+	if err := h.namespaceService.CheckConcurrency(h.Namespace(r)); err != nil {
+		if errors.Is(err, services.ErrNamespaceQuotaExceeded) {
+			return h.SendQuotaExceeded(w, err)
+		}
+		return h.SendInternalError(w, err)
+	}
+
 	workflowID := workflow.NewID()
-	if err := h.Send(WorkflowSupervisorName, messaging.NewTriggerWorkflowWithEnvMessage(req.SchemaID, workflowID, environment)); err != nil {
+	if err := h.Send(WorkflowSupervisorName, messaging.NewTriggerWorkflowWithVersionMessage(schemaID, workflowID, environment, req.Version)); err != nil {
 		return h.SendInternalError(w, err)
 	}
 
 	// Record idempotency key after successful trigger
 	if req.IdempotencyKey != "" {
-		if err := h.idempotencyStore.Set(req.IdempotencyKey, workflowID.String(), h.idempotencyTTL.TTL); err != nil {
+		if err := h.idempotencyStore.Set(idempotencyKey, workflowID.String(), h.idempotencyTTL.TTL); err != nil {
 			h.Log().Warning("failed to set idempotency key: %s", err)
 		}
 	}
@@ -111,5 +169,6 @@ func (h *TriggerWorkflowHandler) HandlePost(from gen.PID, w http.ResponseWriter,
 		WorkflowID:  workflowID.String(),
 		Code:        "OK",
 		Environment: environment,
+		Version:     req.Version,
 	})
 }
@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -21,7 +22,9 @@ type (
 	// WebhookHandler handles incoming webhook requests and triggers matching workflows
 	WebhookHandler struct {
 		Handler
-		graphService services.GraphService
+		graphService     services.GraphService
+		lifecycleService services.SchemaLifecycleService
+		namespaceService services.NamespaceService
 	}
 	// WebhookHandlerFactory is a factory for creating WebhookHandler actors
 	WebhookHandlerFactory HandlerFactory[*WebhookHandler]
@@ -35,11 +38,17 @@ const (
 )
 
 // NewWebhookHandlerFactory creates a new WebhookHandlerFactory
-func NewWebhookHandlerFactory(graphService services.GraphService) *WebhookHandlerFactory {
+func NewWebhookHandlerFactory(
+	graphService services.GraphService,
+	lifecycleService services.SchemaLifecycleService,
+	namespaceService services.NamespaceService,
+) *WebhookHandlerFactory {
 	return &WebhookHandlerFactory{
 		Factory: func() gen.ProcessBehavior {
 			return &WebhookHandler{
-				graphService: graphService,
+				graphService:     graphService,
+				lifecycleService: lifecycleService,
+				namespaceService: namespaceService,
 			}
 		},
 	}
@@ -47,7 +56,7 @@ func NewWebhookHandlerFactory(graphService services.GraphService) *WebhookHandle
 
 // HandlePost handles incoming webhook requests (POST /v1/hooks/{path:.*})
 // @Summary Handle incoming webhook
-// @Description Routes incoming webhooks to the matching workflow trigger
+// @Description Routes incoming webhooks to the matching workflow trigger of the request's namespace
 // @Tags webhooks
 // @Accept json
 // @Produce json
@@ -55,6 +64,8 @@ func NewWebhookHandlerFactory(graphService services.GraphService) *WebhookHandle
 // @Success 200 {object} dtos.TriggerWorkflowResponse
 // @Failure 400 {object} dtos.BadRequestError
 // @Failure 404 {object} dtos.NotFoundError
+// @Failure 409 {object} dtos.ConflictError
+// @Failure 429 {object} dtos.QuotaExceededError
 // @Failure 500 {object} dtos.InternalServerErrorResponse
 // @Router /v1/hooks/{path} [post]
 func (h *WebhookHandler) HandlePost(_ gen.PID, w http.ResponseWriter, r *http.Request) error {
@@ -64,11 +75,24 @@ func (h *WebhookHandler) HandlePost(_ gen.PID, w http.ResponseWriter, r *http.Re
 	}
 	webhookPath = "/" + webhookPath
 
-	// Find matching schema by scanning all schemas with webhook triggers
-	schemaID, webhookCfg, err := h.resolveWebhook(webhookPath)
+	// Find matching schema by scanning the namespace's schemas with webhook triggers
+	namespace := h.Namespace(r)
+	schemaID, webhookCfg, err := h.resolveWebhook(namespace, webhookPath)
 	if err != nil {
 		return h.SendNotFound(w, fmt.Sprintf("no webhook registered for path %s", webhookPath), EmptyFields)
 	}
+	if lcErr := h.lifecycleService.CheckAcceptsExecutions(schemaID); lcErr != nil {
+		if errors.Is(lcErr, services.ErrSchemaNotAcceptingExecutions) {
+			return h.SendConflict(w, lcErr, EmptyFields)
+		}
+		return h.SendInternalError(w, lcErr)
+	}
+	if quotaErr := h.namespaceService.CheckConcurrency(namespace); quotaErr != nil {
+		if errors.Is(quotaErr, services.ErrNamespaceQuotaExceeded) {
+			return h.SendQuotaExceeded(w, quotaErr)
+		}
+		return h.SendInternalError(w, quotaErr)
+	}
 
 	// Read request body
 	body, err := io.ReadAll(r.Body)
@@ -106,13 +130,17 @@ func (h *WebhookHandler) HandlePost(_ gen.PID, w http.ResponseWriter, r *http.Re
 	})
 }
 
-func (h *WebhookHandler) resolveWebhook(path string) (string, *internalworkflow.WebhookConfig, error) {
+func (h *WebhookHandler) resolveWebhook(namespace, path string) (string, *internalworkflow.WebhookConfig, error) {
 	schemas, err := h.graphService.ListSchemas()
 	if err != nil {
 		return "", nil, err
 	}
 
 	for _, item := range schemas {
+		// Archived schemas have their webhook paths unregistered
+		if !item.Lifecycle.KeepsTriggers() || workflow.NamespaceOf(item.SchemaID) != namespace {
+			continue
+		}
 		graph, gErr := h.graphService.FindByID(item.SchemaID)
 		if gErr != nil {
 			continue
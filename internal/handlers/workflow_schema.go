@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/open-source-cloud/fuse/internal/auth"
 	"github.com/open-source-cloud/fuse/internal/dtos"
 	"github.com/open-source-cloud/fuse/internal/services"
 	"github.com/open-source-cloud/fuse/internal/workflow"
@@ -19,7 +21,9 @@ type (
 	// WorkflowSchemaHandler is the handler for the WorkflowSchemaHandler endpoint
 	WorkflowSchemaHandler struct {
 		Handler
-		graphService services.GraphService
+		graphService     services.GraphService
+		lifecycleService services.SchemaLifecycleService
+		namespaceService services.NamespaceService
 	}
 	// WorkflowSchemaHandlerFactory is a factory for creating WorkflowSchemaHandler actors
 	WorkflowSchemaHandlerFactory HandlerFactory[*WorkflowSchemaHandler]
@@ -36,11 +40,17 @@ const (
 type UpsertSchemaBody = workflow.GraphSchema
 
 // NewWorkflowSchemaHandlerFactory creates a new WorkflowSchemaHandlerFactory
-func NewWorkflowSchemaHandlerFactory(graphService services.GraphService) *WorkflowSchemaHandlerFactory {
+func NewWorkflowSchemaHandlerFactory(
+	graphService services.GraphService,
+	lifecycleService services.SchemaLifecycleService,
+	namespaceService services.NamespaceService,
+) *WorkflowSchemaHandlerFactory {
 	return &WorkflowSchemaHandlerFactory{
 		Factory: func() gen.ProcessBehavior {
 			return &WorkflowSchemaHandler{
-				graphService: graphService,
+				graphService:     graphService,
+				lifecycleService: lifecycleService,
+				namespaceService: namespaceService,
 			}
 		},
 	}
@@ -56,17 +66,23 @@ func NewWorkflowSchemaHandlerFactory(graphService services.GraphService) *Workfl
 // @Param schema body UpsertSchemaBody true "Workflow Schema"
 // @Success 200 {object} dtos.UpsertSchemaResponse
 // @Failure 400 {object} dtos.BadRequestError
+// @Failure 403 {object} dtos.ForbiddenError
 // @Failure 404 {object} dtos.NotFoundError
+// @Failure 429 {object} dtos.QuotaExceededError
 // @Failure 500 {object} dtos.InternalServerErrorResponse
 // @Router /v1/schemas/{schemaID} [put]
 func (h *WorkflowSchemaHandler) HandlePut(from gen.PID, w http.ResponseWriter, r *http.Request) error {
 	h.Log().Info("received upsert workflow schema request from: %v remoteAddr: %s", from, r.RemoteAddr)
 
-	schemaID, err := h.GetPathParam(r, "schemaID")
+	schemaID, err := h.SchemaIDParam(r)
 	if err != nil {
 		return h.SendBadRequest(w, err, []string{"schemaID is required"})
 	}
 
+	if err := h.Authorize(r, auth.PermSchemaWrite, auth.NewSchemaResource(schemaID, "")); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
 	h.Log().Info("upserting workflow schema", "from", from, "schemaID", schemaID)
 
 	rawJSON, err := io.ReadAll(r.Body)
@@ -79,11 +95,26 @@ func (h *WorkflowSchemaHandler) HandlePut(from gen.PID, w http.ResponseWriter, r
 		return h.SendBadRequest(w, err, EmptyFields)
 	}
 
-	_, err = h.graphService.Upsert(schemaID, schema)
+	// every upsert stores a new version, so the whole schema counts against the storage quota
+	size, err := services.JSONSize(schema)
+	if err != nil {
+		return h.SendInternalError(w, err)
+	}
+	if err := h.namespaceService.CheckStorage(h.Namespace(r), size); err != nil {
+		if errors.Is(err, services.ErrNamespaceQuotaExceeded) {
+			return h.SendQuotaExceeded(w, err)
+		}
+		return h.SendInternalError(w, err)
+	}
+
+	_, err = h.graphService.Upsert(r.Context(), schemaID, schema)
 	if err != nil {
 		if errors.As(err, &validator.ValidationErrors{}) {
 			return h.SendValidationErr(w, err)
 		}
+		if errors.Is(err, workflow.ErrInvalidSearchAttribute) {
+			return h.SendBadRequest(w, err, []string{"searchAttributes"})
+		}
 		if errors.Is(err, repositories.ErrGraphNotFound) {
 			return h.SendNotFound(w, fmt.Sprintf("schema %s not found", schemaID), EmptyFields)
 		}
@@ -106,16 +137,20 @@ func (h *WorkflowSchemaHandler) HandlePut(from gen.PID, w http.ResponseWriter, r
 // @Param schemaID path string true "Schema ID"
 // @Success 200 {object} UpsertSchemaBody
 // @Failure 400 {object} dtos.BadRequestError
+// @Failure 403 {object} dtos.ForbiddenError
 // @Failure 404 {object} dtos.NotFoundError
 // @Failure 500 {object} dtos.InternalServerErrorResponse
 // @Router /v1/schemas/{schemaID} [get]
 func (h *WorkflowSchemaHandler) HandleGet(from gen.PID, w http.ResponseWriter, r *http.Request) error {
 	h.Log().Info("received get workflow schema request", "from", from, "remoteAddr", r.RemoteAddr)
 
-	schemaID, err := h.GetPathParam(r, "schemaID")
+	schemaID, err := h.SchemaIDParam(r)
 	if err != nil {
 		return h.SendBadRequest(w, err, []string{"schemaID"})
 	}
+	if err := h.Authorize(r, auth.PermSchemaRead, auth.NewSchemaResource(schemaID, "")); err != nil {
+		return h.SendForbidden(w, err)
+	}
 
 	h.Log().Info("fetching workflow schema", "from", from, "schemaID", schemaID)
 
@@ -131,3 +166,53 @@ func (h *WorkflowSchemaHandler) HandleGet(from gen.PID, w http.ResponseWriter, r
 
 	return h.SendJSON(w, http.StatusOK, graph.Schema())
 }
+
+// HandleDelete deletes a workflow schema and all of its versions -- DELETE /v1/schemas/{schemaID}
+// @Summary Delete workflow schema
+// @Description Delete a schema and all of its versions. Refused while executions are in flight unless force=true.
+// @Tags schemas
+// @Accept json
+// @Produce json
+// @Param schemaID path string true "Schema ID"
+// @Param force query bool false "Delete even if executions are in flight"
+// @Success 204 "No Content"
+// @Failure 400 {object} dtos.BadRequestError
+// @Failure 403 {object} dtos.ForbiddenError
+// @Failure 404 {object} dtos.NotFoundError
+// @Failure 409 {object} dtos.ConflictError
+// @Failure 500 {object} dtos.InternalServerErrorResponse
+// @Router /v1/schemas/{schemaID} [delete]
+func (h *WorkflowSchemaHandler) HandleDelete(from gen.PID, w http.ResponseWriter, r *http.Request) error {
+	h.Log().Info("received delete workflow schema request from: %v remoteAddr: %s", from, r.RemoteAddr)
+
+	schemaID, err := h.SchemaIDParam(r)
+	if err != nil {
+		return h.SendBadRequest(w, err, []string{"schemaID is required"})
+	}
+
+	if err := h.Authorize(r, auth.PermSchemaWrite, auth.NewSchemaResource(schemaID, "")); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
+	force := false
+	if raw, qErr := h.GetQueryParam(r, "force"); qErr == nil {
+		force, err = strconv.ParseBool(raw)
+		if err != nil {
+			return h.SendBadRequest(w, err, []string{"force"})
+		}
+	}
+
+	if err := h.lifecycleService.Delete(schemaID, force); err != nil {
+		if errors.Is(err, repositories.ErrGraphNotFound) {
+			return h.SendNotFound(w, fmt.Sprintf("schema %s not found", schemaID), EmptyFields)
+		}
+		if errors.Is(err, services.ErrSchemaHasExecutionsInFlight) {
+			return h.SendConflict(w, err, EmptyFields)
+		}
+		return h.SendInternalError(w, err)
+	}
+
+	h.Log().Info("deleted workflow schema", "from", from, "schemaID", schemaID, "force", force)
+
+	return h.SendJSON(w, http.StatusNoContent, nil)
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/auth"
 	"github.com/open-source-cloud/fuse/internal/repositories"
 )
 
@@ -11,7 +12,8 @@ type (
 	// WorkflowTraceHandler is the handler for the workflow trace endpoint
 	WorkflowTraceHandler struct {
 		Handler
-		traceRepo repositories.TraceRepository
+		traceRepo    repositories.TraceRepository
+		workflowRepo repositories.WorkflowRepository
 	}
 	// WorkflowTraceHandlerFactory is a factory for creating WorkflowTraceHandler actors
 	WorkflowTraceHandlerFactory HandlerFactory[*WorkflowTraceHandler]
@@ -25,11 +27,12 @@ const (
 )
 
 // NewWorkflowTraceHandlerFactory creates a new WorkflowTraceHandlerFactory
-func NewWorkflowTraceHandlerFactory(traceRepo repositories.TraceRepository) *WorkflowTraceHandlerFactory {
+func NewWorkflowTraceHandlerFactory(traceRepo repositories.TraceRepository, workflowRepo repositories.WorkflowRepository) *WorkflowTraceHandlerFactory {
 	return &WorkflowTraceHandlerFactory{
 		Factory: func() gen.ProcessBehavior {
 			return &WorkflowTraceHandler{
-				traceRepo: traceRepo,
+				traceRepo:    traceRepo,
+				workflowRepo: workflowRepo,
 			}
 		},
 	}
@@ -43,6 +46,7 @@ func NewWorkflowTraceHandlerFactory(traceRepo repositories.TraceRepository) *Wor
 // @Param workflowID path string true "Workflow ID"
 // @Success 200 {object} workflow.ExecutionTrace
 // @Failure 400 {object} dtos.BadRequestError
+// @Failure 403 {object} dtos.ForbiddenError
 // @Failure 404 {object} dtos.NotFoundError
 // @Router /v1/workflows/{workflowID}/trace [get]
 func (h *WorkflowTraceHandler) HandleGet(_ gen.PID, w http.ResponseWriter, r *http.Request) error {
@@ -51,6 +55,14 @@ func (h *WorkflowTraceHandler) HandleGet(_ gen.PID, w http.ResponseWriter, r *ht
 		return h.SendBadRequest(w, err, EmptyFields)
 	}
 
+	wf, err := h.workflowRepo.Get(workflowID)
+	if err != nil || !h.InNamespace(r, wf) {
+		return h.SendNotFound(w, "workflow not found", EmptyFields)
+	}
+	if err := h.Authorize(r, auth.PermWorkflowRead, workflowResource(wf)); err != nil {
+		return h.SendForbidden(w, err)
+	}
+
 	trace, err := h.traceRepo.FindByWorkflowID(workflowID)
 	if err != nil {
 		return h.SendNotFound(w, "trace not found", EmptyFields)
@@ -6,6 +6,7 @@ package anthropic
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/anthropics/anthropic-sdk-go"
@@ -37,6 +38,8 @@ type Provider struct {
 	client       anthropic.Client
 }
 
+var _ llm.StreamingProvider = (*Provider)(nil)
+
 // New builds a Provider from cfg.
 func New(cfg Config) *Provider {
 	opts := make([]option.RequestOption, 0, 2)
@@ -58,12 +61,69 @@ func (p *Provider) Name() string { return p.name }
 
 // Chat performs a message completion, translating to and from the Anthropic SDK types.
 func (p *Provider) Chat(ctx context.Context, req llm.ChatRequest) (llm.ChatResponse, error) {
+	params, err := p.messageParams(req)
+	if err != nil {
+		return llm.ChatResponse{}, err
+	}
+
+	msg, err := p.client.Messages.New(ctx, params)
+	if err != nil {
+		return llm.ChatResponse{}, fmt.Errorf("anthropic[%s]: chat completion failed: %w", p.name, statusError(err))
+	}
+
+	return fromAnthropicMessage(msg), nil
+}
+
+// ChatStream performs a streaming message completion. Text deltas are sent as they arrive and the
+// final chunk carries the accumulated message; a failure is sent as a chunk with Err set. The
+// channel is closed after the final chunk, or when ctx is done.
+func (p *Provider) ChatStream(ctx context.Context, req llm.ChatRequest) (<-chan llm.StreamChunk, error) {
+	params, err := p.messageParams(req)
+	if err != nil {
+		return nil, err
+	}
+
+	stream := p.client.Messages.NewStreaming(ctx, params)
+	chunks := make(chan llm.StreamChunk)
+	go func() {
+		defer close(chunks)
+		defer func() { _ = stream.Close() }()
+
+		var msg anthropic.Message
+		for stream.Next() {
+			event := stream.Current()
+			if err := msg.Accumulate(event); err != nil {
+				sendChunk(ctx, chunks, llm.StreamChunk{Err: fmt.Errorf("anthropic[%s]: chat stream: %w", p.name, err)})
+				return
+			}
+			delta, ok := event.AsAny().(anthropic.ContentBlockDeltaEvent)
+			if !ok {
+				continue
+			}
+			if text, ok := delta.Delta.AsAny().(anthropic.TextDelta); ok && text.Text != "" {
+				if !sendChunk(ctx, chunks, llm.StreamChunk{ContentDelta: text.Text}) {
+					return
+				}
+			}
+		}
+		if err := stream.Err(); err != nil {
+			sendChunk(ctx, chunks, llm.StreamChunk{Err: fmt.Errorf("anthropic[%s]: chat stream failed: %w", p.name, statusError(err))})
+			return
+		}
+		resp := fromAnthropicMessage(&msg)
+		sendChunk(ctx, chunks, llm.StreamChunk{Done: true, Response: &resp})
+	}()
+	return chunks, nil
+}
+
+// messageParams builds the SDK request for req, applying the default model and max tokens.
+func (p *Provider) messageParams(req llm.ChatRequest) (anthropic.MessageNewParams, error) {
 	model := req.Model
 	if model == "" {
 		model = p.defaultModel
 	}
 	if model == "" {
-		return llm.ChatResponse{}, fmt.Errorf("anthropic[%s]: no model specified and no default configured", p.name)
+		return anthropic.MessageNewParams{}, fmt.Errorf("anthropic[%s]: no model specified and no default configured", p.name)
 	}
 
 	system, messages := toAnthropicMessages(req.Messages)
@@ -83,13 +143,27 @@ func (p *Provider) Chat(ctx context.Context, req llm.ChatRequest) (llm.ChatRespo
 	if tc, ok := toAnthropicToolChoice(req.ToolChoice); ok {
 		params.ToolChoice = tc
 	}
+	return params, nil
+}
 
-	msg, err := p.client.Messages.New(ctx, params)
-	if err != nil {
-		return llm.ChatResponse{}, fmt.Errorf("anthropic[%s]: chat completion failed: %w", p.name, err)
+// statusError wraps an Anthropic API error in llm.StatusError, keeping its HTTP status visible to
+// the fallback logic of routing policies.
+func statusError(err error) error {
+	var apiErr *anthropic.Error
+	if errors.As(err, &apiErr) {
+		return &llm.StatusError{StatusCode: apiErr.StatusCode, Err: err}
 	}
+	return err
+}
 
-	return fromAnthropicMessage(msg), nil
+// sendChunk delivers chunk unless ctx is done first; it reports whether the consumer got it.
+func sendChunk(ctx context.Context, chunks chan<- llm.StreamChunk, chunk llm.StreamChunk) bool {
+	select {
+	case chunks <- chunk:
+		return true
+	case <-ctx.Done():
+		return false
+	}
 }
 
 // resolveMaxTokens returns the request's max tokens or the default.
@@ -28,6 +28,18 @@ func newStubServer(t *testing.T, respBody string, captured *map[string]any) *htt
 	}))
 }
 
+// newStreamServer returns an httptest server that replies with the given server-sent events, each
+// a [type, data] pair.
+func newStreamServer(t *testing.T, events [][2]string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, e := range events {
+			_, _ = io.WriteString(w, "event: "+e[0]+"\ndata: "+e[1]+"\n\n")
+		}
+	}))
+}
+
 func TestProvider_Chat_ParsesTextResponse(t *testing.T) {
 	resp := `{
 		"id": "msg_1",
@@ -142,3 +154,76 @@ func TestProvider_Chat_NoModelErrors(t *testing.T) {
 	})
 	assert.Error(t, err)
 }
+
+func TestProvider_Chat_KeepsAPIStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = io.WriteString(w, `{"type":"error","error":{"type":"invalid_request_error","message":"bad tools"}}`)
+	}))
+	defer srv.Close()
+
+	p := anthropic.New(anthropic.Config{Name: "anthropic", APIKey: "test", BaseURL: srv.URL, Model: "claude-test"})
+	_, err := p.Chat(context.Background(), llm.ChatRequest{Messages: []llm.Message{{Role: llm.RoleUser, Content: "hi"}}})
+	var statusErr *llm.StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusBadRequest, statusErr.StatusCode)
+}
+
+func TestProvider_ChatStream_StreamsTextAndToolUse(t *testing.T) {
+	srv := newStreamServer(t, [][2]string{
+		{"message_start", `{"type":"message_start","message":{"id":"msg_1","type":"message","role":"assistant","model":"claude-test","content":[],"usage":{"input_tokens":5,"output_tokens":0}}}`},
+		{"content_block_start", `{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`},
+		{"content_block_delta", `{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Let me "}}`},
+		{"content_block_delta", `{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"check."}}`},
+		{"content_block_stop", `{"type":"content_block_stop","index":0}`},
+		{"content_block_start", `{"type":"content_block_start","index":1,"content_block":{"type":"tool_use","id":"toolu_1","name":"http__request","input":{}}}`},
+		{"content_block_delta", `{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"{\"path\":"}}`},
+		{"content_block_delta", `{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"\"/x\"}"}}`},
+		{"content_block_stop", `{"type":"content_block_stop","index":1}`},
+		{"message_delta", `{"type":"message_delta","delta":{"stop_reason":"tool_use"},"usage":{"output_tokens":9}}`},
+		{"message_stop", `{"type":"message_stop"}`},
+	})
+	defer srv.Close()
+
+	p := anthropic.New(anthropic.Config{Name: "anthropic", APIKey: "test", BaseURL: srv.URL, Model: "claude-test"})
+	chunks, err := p.ChatStream(context.Background(), llm.ChatRequest{Messages: []llm.Message{{Role: llm.RoleUser, Content: "hi"}}})
+	require.NoError(t, err)
+
+	var deltas []string
+	var final llm.StreamChunk
+	for chunk := range chunks {
+		if chunk.Done || chunk.Err != nil {
+			final = chunk
+			break
+		}
+		deltas = append(deltas, chunk.ContentDelta)
+	}
+
+	require.NoError(t, final.Err)
+	require.True(t, final.Done)
+	assert.Equal(t, []string{"Let me ", "check."}, deltas)
+	assert.Equal(t, "Let me check.", final.Response.Message.Content)
+	assert.Equal(t, "tool_use", final.Response.FinishReason)
+	assert.Equal(t, llm.Usage{PromptTokens: 5, CompletionTokens: 9, TotalTokens: 14}, final.Response.Usage)
+	require.Len(t, final.Response.Message.ToolCalls, 1)
+	assert.Equal(t, "http__request", final.Response.Message.ToolCalls[0].Name)
+	assert.JSONEq(t, `{"path":"/x"}`, string(final.Response.Message.ToolCalls[0].Arguments))
+}
+
+func TestProvider_ChatStream_ReportsStreamErrors(t *testing.T) {
+	srv := newStreamServer(t, [][2]string{
+		{"error", `{"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`},
+	})
+	defer srv.Close()
+
+	p := anthropic.New(anthropic.Config{Name: "anthropic", APIKey: "test", BaseURL: srv.URL, Model: "claude-test"})
+	chunks, err := p.ChatStream(context.Background(), llm.ChatRequest{Messages: []llm.Message{{Role: llm.RoleUser, Content: "hi"}}})
+	require.NoError(t, err)
+
+	final := <-chunks
+	require.Error(t, final.Err)
+	assert.Contains(t, final.Err.Error(), "chat stream failed")
+	_, open := <-chunks
+	assert.False(t, open, "the stream is closed after the error")
+}
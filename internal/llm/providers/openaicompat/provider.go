@@ -7,6 +7,7 @@ package openaicompat
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/open-source-cloud/fuse/pkg/llm"
@@ -25,17 +26,25 @@ type Config struct {
 	BaseURL string
 	// Model is the default model used when a request does not specify one.
 	Model string
+	// EmbeddingModel is the default model for Embed requests that do not specify one.
+	EmbeddingModel string
 	// Headers are extra HTTP headers sent on every request (e.g. OpenRouter ranking headers).
 	Headers map[string]string
 }
 
 // Provider is an llm.Provider backed by the OpenAI Go SDK.
 type Provider struct {
-	name         string
-	defaultModel string
-	client       openai.Client
+	name           string
+	defaultModel   string
+	embeddingModel string
+	client         openai.Client
 }
 
+var (
+	_ llm.StreamingProvider = (*Provider)(nil)
+	_ llm.EmbeddingProvider = (*Provider)(nil)
+)
+
 // New builds a Provider from cfg.
 func New(cfg Config) *Provider {
 	opts := make([]option.RequestOption, 0, 2+len(cfg.Headers))
@@ -50,9 +59,10 @@ func New(cfg Config) *Provider {
 	}
 
 	return &Provider{
-		name:         cfg.Name,
-		defaultModel: cfg.Model,
-		client:       openai.NewClient(opts...),
+		name:           cfg.Name,
+		defaultModel:   cfg.Model,
+		embeddingModel: cfg.EmbeddingModel,
+		client:         openai.NewClient(opts...),
 	}
 }
 
@@ -61,12 +71,78 @@ func (p *Provider) Name() string { return p.name }
 
 // Chat performs a chat completion, translating to and from the OpenAI SDK types.
 func (p *Provider) Chat(ctx context.Context, req llm.ChatRequest) (llm.ChatResponse, error) {
+	params, err := p.chatParams(req)
+	if err != nil {
+		return llm.ChatResponse{}, err
+	}
+
+	completion, err := p.client.Chat.Completions.New(ctx, params)
+	if err != nil {
+		return llm.ChatResponse{}, fmt.Errorf("openaicompat[%s]: chat completion failed: %w", p.name, statusError(err))
+	}
+	return p.toChatResponse(completion)
+}
+
+// ChatStream performs a streaming chat completion. Content deltas are sent as they arrive and the
+// final chunk carries the accumulated response; usage is requested with stream_options, so it is
+// zero on backends that do not report it for streams. A failure is sent as a chunk with Err set.
+// The channel is closed after the final chunk, or when ctx is done.
+func (p *Provider) ChatStream(ctx context.Context, req llm.ChatRequest) (<-chan llm.StreamChunk, error) {
+	params, err := p.chatParams(req)
+	if err != nil {
+		return nil, err
+	}
+	params.StreamOptions = openai.ChatCompletionStreamOptionsParam{IncludeUsage: openai.Bool(true)}
+
+	stream := p.client.Chat.Completions.NewStreaming(ctx, params)
+	chunks := make(chan llm.StreamChunk)
+	go func() {
+		defer close(chunks)
+		defer func() { _ = stream.Close() }()
+
+		var acc openai.ChatCompletionAccumulator
+		for stream.Next() {
+			chunk := stream.Current()
+			if !acc.AddChunk(chunk) {
+				sendChunk(ctx, chunks, llm.StreamChunk{Err: fmt.Errorf("openaicompat[%s]: chat stream returned chunks of different completions", p.name)})
+				return
+			}
+			if len(chunk.Choices) > 0 && chunk.Choices[0].Delta.Content != "" {
+				if !sendChunk(ctx, chunks, llm.StreamChunk{ContentDelta: chunk.Choices[0].Delta.Content}) {
+					return
+				}
+			}
+		}
+		if err := stream.Err(); err != nil {
+			sendChunk(ctx, chunks, llm.StreamChunk{Err: fmt.Errorf("openaicompat[%s]: chat stream failed: %w", p.name, statusError(err))})
+			return
+		}
+		// Some backends omit the type on streamed tool calls; every tool we send is a function.
+		for i := range acc.Choices {
+			for j := range acc.Choices[i].Message.ToolCalls {
+				if acc.Choices[i].Message.ToolCalls[j].Type == "" {
+					acc.Choices[i].Message.ToolCalls[j].Type = "function"
+				}
+			}
+		}
+		resp, err := p.toChatResponse(&acc.ChatCompletion)
+		if err != nil {
+			sendChunk(ctx, chunks, llm.StreamChunk{Err: err})
+			return
+		}
+		sendChunk(ctx, chunks, llm.StreamChunk{Done: true, Response: &resp})
+	}()
+	return chunks, nil
+}
+
+// chatParams builds the SDK request for req, applying the default model.
+func (p *Provider) chatParams(req llm.ChatRequest) (openai.ChatCompletionNewParams, error) {
 	model := req.Model
 	if model == "" {
 		model = p.defaultModel
 	}
 	if model == "" {
-		return llm.ChatResponse{}, fmt.Errorf("openaicompat[%s]: no model specified and no default configured", p.name)
+		return openai.ChatCompletionNewParams{}, fmt.Errorf("openaicompat[%s]: no model specified and no default configured", p.name)
 	}
 
 	params := openai.ChatCompletionNewParams{
@@ -87,11 +163,11 @@ func (p *Provider) Chat(ctx context.Context, req llm.ChatRequest) (llm.ChatRespo
 			OfAuto: openai.String(req.ToolChoice),
 		}
 	}
+	return params, nil
+}
 
-	completion, err := p.client.Chat.Completions.New(ctx, params)
-	if err != nil {
-		return llm.ChatResponse{}, fmt.Errorf("openaicompat[%s]: chat completion failed: %w", p.name, err)
-	}
+// toChatResponse converts the first choice of a completion to the agnostic response.
+func (p *Provider) toChatResponse(completion *openai.ChatCompletion) (llm.ChatResponse, error) {
 	if len(completion.Choices) == 0 {
 		return llm.ChatResponse{}, fmt.Errorf("openaicompat[%s]: completion returned no choices", p.name)
 	}
@@ -108,6 +184,84 @@ func (p *Provider) Chat(ctx context.Context, req llm.ChatRequest) (llm.ChatRespo
 	}, nil
 }
 
+// statusError tags an error the API answered with its HTTP status, so routing policies can fall
+// back on rate limits and server errors.
+func statusError(err error) error {
+	var apiErr *openai.Error
+	if errors.As(err, &apiErr) {
+		return &llm.StatusError{StatusCode: apiErr.StatusCode, Err: err}
+	}
+	return err
+}
+
+// sendChunk delivers chunk unless ctx is done first; it reports whether the consumer got it.
+func sendChunk(ctx context.Context, chunks chan<- llm.StreamChunk, chunk llm.StreamChunk) bool {
+	select {
+	case chunks <- chunk:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// Embed embeds req.Input with the embeddings endpoint, returning vectors in input order.
+func (p *Provider) Embed(ctx context.Context, req llm.EmbeddingRequest) (llm.EmbeddingResponse, error) {
+	model := req.Model
+	if model == "" {
+		model = p.embeddingModel
+	}
+	if model == "" {
+		return llm.EmbeddingResponse{}, fmt.Errorf("openaicompat[%s]: no embedding model specified and no default configured", p.name)
+	}
+	if len(req.Input) == 0 {
+		return llm.EmbeddingResponse{}, fmt.Errorf("openaicompat[%s]: embedding input is empty", p.name)
+	}
+
+	params := openai.EmbeddingNewParams{
+		Model:          model,
+		Input:          openai.EmbeddingNewParamsInputUnion{OfArrayOfStrings: req.Input},
+		EncodingFormat: openai.EmbeddingNewParamsEncodingFormatFloat,
+	}
+	if req.Dimensions > 0 {
+		params.Dimensions = openai.Int(int64(req.Dimensions))
+	}
+
+	res, err := p.client.Embeddings.New(ctx, params)
+	if err != nil {
+		return llm.EmbeddingResponse{}, fmt.Errorf("openaicompat[%s]: embedding request failed: %w", p.name, statusError(err))
+	}
+
+	// The API tags each vector with its input index; do not rely on response order.
+	vectors := make([][]float32, len(req.Input))
+	for _, e := range res.Data {
+		if e.Index < 0 || int(e.Index) >= len(vectors) {
+			return llm.EmbeddingResponse{}, fmt.Errorf("openaicompat[%s]: embedding index %d out of range", p.name, e.Index)
+		}
+		v := make([]float32, len(e.Embedding))
+		for i, f := range e.Embedding {
+			v[i] = float32(f)
+		}
+		vectors[e.Index] = v
+	}
+	for i, v := range vectors {
+		if v == nil {
+			return llm.EmbeddingResponse{}, fmt.Errorf("openaicompat[%s]: no embedding returned for input %d", p.name, i)
+		}
+	}
+
+	if res.Model != "" {
+		model = res.Model
+	}
+	return llm.EmbeddingResponse{
+		Vectors: vectors,
+		Model:   model,
+		Usage: llm.Usage{
+			PromptTokens: int(res.Usage.PromptTokens),
+			TotalTokens:  int(res.Usage.TotalTokens),
+		},
+	}, nil
+}
+
 // toOpenAIMessages converts provider-agnostic messages to SDK message params.
 func toOpenAIMessages(messages []llm.Message) []openai.ChatCompletionMessageParamUnion {
 	out := make([]openai.ChatCompletionMessageParamUnion, 0, len(messages))
@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/open-source-cloud/fuse/internal/llm/providers/openaicompat"
@@ -28,6 +29,36 @@ func newStubServer(t *testing.T, respBody string, captured *map[string]any) *htt
 	}))
 }
 
+// newStreamServer returns an httptest server that records the request body and replies with the
+// given server-sent event payloads, followed by the [DONE] sentinel.
+func newStreamServer(t *testing.T, events []string, captured *map[string]any) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if captured != nil {
+			_ = json.Unmarshal(body, captured)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, e := range append(events, "[DONE]") {
+			_, _ = io.WriteString(w, "data: "+e+"\n\n")
+		}
+	}))
+}
+
+// collect drains a stream, returning the content deltas and the final chunk.
+func collect(t *testing.T, chunks <-chan llm.StreamChunk) ([]string, llm.StreamChunk) {
+	t.Helper()
+	var deltas []string
+	for chunk := range chunks {
+		if chunk.Done || chunk.Err != nil {
+			return deltas, chunk
+		}
+		deltas = append(deltas, chunk.ContentDelta)
+	}
+	t.Fatal("stream closed without a final chunk")
+	return nil, llm.StreamChunk{}
+}
+
 func TestProvider_Chat_ParsesTextResponse(t *testing.T) {
 	resp := `{
 		"id": "chatcmpl-1",
@@ -111,3 +142,113 @@ func TestProvider_Chat_NoModelErrors(t *testing.T) {
 	})
 	assert.Error(t, err)
 }
+
+func TestProvider_Embed_OrdersVectorsByIndex(t *testing.T) {
+	resp := `{
+		"object": "list",
+		"model": "embed-small",
+		"data": [
+			{"object":"embedding","index":1,"embedding":[0.5,0.25]},
+			{"object":"embedding","index":0,"embedding":[1,0]}
+		],
+		"usage": {"prompt_tokens":6,"total_tokens":6}
+	}`
+	var captured map[string]any
+	srv := newStubServer(t, resp, &captured)
+	defer srv.Close()
+
+	p := openaicompat.New(openaicompat.Config{Name: "stub", BaseURL: srv.URL, EmbeddingModel: "embed-small"})
+	out, err := p.Embed(context.Background(), llm.EmbeddingRequest{Input: []string{"a", "b"}, Dimensions: 2})
+
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{1, 0}, {0.5, 0.25}}, out.Vectors)
+	assert.Equal(t, "embed-small", out.Model)
+	assert.Equal(t, 6, out.Usage.PromptTokens)
+	assert.Equal(t, "embed-small", captured["model"])
+	assert.InDelta(t, 2, captured["dimensions"], 0)
+	assert.Equal(t, []any{"a", "b"}, captured["input"])
+}
+
+func TestProvider_Embed_Errors(t *testing.T) {
+	missing := newStubServer(t, `{"object":"list","data":[{"object":"embedding","index":0,"embedding":[1]}],"usage":{}}`, nil)
+	defer missing.Close()
+
+	_, err := openaicompat.New(openaicompat.Config{Name: "stub", BaseURL: missing.URL}).
+		Embed(context.Background(), llm.EmbeddingRequest{Input: []string{"a"}})
+	assert.ErrorContains(t, err, "no embedding model")
+
+	_, err = openaicompat.New(openaicompat.Config{Name: "stub", BaseURL: missing.URL, EmbeddingModel: "m"}).
+		Embed(context.Background(), llm.EmbeddingRequest{Input: []string{"a", "b"}})
+	assert.ErrorContains(t, err, "no embedding returned for input 1")
+}
+
+func TestProvider_ChatStream_StreamsContentAndAggregates(t *testing.T) {
+	var captured map[string]any
+	srv := newStreamServer(t, []string{
+		`{"id":"c1","object":"chat.completion.chunk","choices":[{"index":0,"delta":{"role":"assistant","content":"Hel"}}]}`,
+		`{"id":"c1","object":"chat.completion.chunk","choices":[{"index":0,"delta":{"content":"lo"}}]}`,
+		`{"id":"c1","object":"chat.completion.chunk","choices":[{"index":0,"delta":{},"finish_reason":"stop"}]}`,
+		`{"id":"c1","object":"chat.completion.chunk","choices":[],"usage":{"prompt_tokens":4,"completion_tokens":2,"total_tokens":6}}`,
+	}, &captured)
+	defer srv.Close()
+
+	p := openaicompat.New(openaicompat.Config{Name: "openai", BaseURL: srv.URL, Model: "gpt-test"})
+	chunks, err := p.ChatStream(context.Background(), llm.ChatRequest{Messages: []llm.Message{{Role: llm.RoleUser, Content: "hi"}}})
+	require.NoError(t, err)
+
+	deltas, final := collect(t, chunks)
+	require.NoError(t, final.Err)
+	assert.Equal(t, []string{"Hel", "lo"}, deltas)
+	require.NotNil(t, final.Response)
+	assert.Equal(t, "Hello", final.Response.Message.Content)
+	assert.Equal(t, "stop", final.Response.FinishReason)
+	assert.Equal(t, llm.Usage{PromptTokens: 4, CompletionTokens: 2, TotalTokens: 6}, final.Response.Usage)
+
+	assert.Equal(t, true, captured["stream"])
+	assert.Equal(t, map[string]any{"include_usage": true}, captured["stream_options"])
+}
+
+func TestProvider_ChatStream_AccumulatesToolCalls(t *testing.T) {
+	srv := newStreamServer(t, []string{
+		`{"id":"c1","object":"chat.completion.chunk","choices":[{"index":0,"delta":{"role":"assistant","tool_calls":[{"index":0,"id":"call_1","function":{"name":"fuse_pkg_debug__nil","arguments":"{\"a\""}}]}}]}`,
+		`{"id":"c1","object":"chat.completion.chunk","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":":1}"}}]}}]}`,
+		`{"id":"c1","object":"chat.completion.chunk","choices":[{"index":0,"delta":{},"finish_reason":"tool_calls"}]}`,
+	}, nil)
+	defer srv.Close()
+
+	p := openaicompat.New(openaicompat.Config{Name: "openai", BaseURL: srv.URL, Model: "gpt-test"})
+	chunks, err := p.ChatStream(context.Background(), llm.ChatRequest{Messages: []llm.Message{{Role: llm.RoleUser, Content: "hi"}}})
+	require.NoError(t, err)
+
+	deltas, final := collect(t, chunks)
+	require.NoError(t, final.Err)
+	assert.Empty(t, deltas)
+	require.Len(t, final.Response.Message.ToolCalls, 1, "a tool call without a streamed type is still a function call")
+	tc := final.Response.Message.ToolCalls[0]
+	assert.Equal(t, "call_1", tc.ID)
+	assert.Equal(t, "fuse_pkg_debug__nil", tc.Name)
+	assert.JSONEq(t, `{"a":1}`, string(tc.Arguments))
+}
+
+func TestProvider_ChatStream_Errors(t *testing.T) {
+	p := openaicompat.New(openaicompat.Config{Name: "openai", BaseURL: "http://127.0.0.1:1"})
+	_, err := p.ChatStream(context.Background(), llm.ChatRequest{Messages: []llm.Message{{Role: llm.RoleUser, Content: "hi"}}})
+	require.Error(t, err, "no model is reported before the request is made")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = io.WriteString(w, `{"error":{"message":"bad model"}}`)
+	}))
+	defer srv.Close()
+	p = openaicompat.New(openaicompat.Config{Name: "openai", BaseURL: srv.URL, Model: "gpt-test"})
+	chunks, err := p.ChatStream(context.Background(), llm.ChatRequest{Messages: []llm.Message{{Role: llm.RoleUser, Content: "hi"}}})
+	require.NoError(t, err)
+
+	_, final := collect(t, chunks)
+	require.Error(t, final.Err)
+	assert.True(t, strings.Contains(final.Err.Error(), "chat stream failed"), final.Err.Error())
+	var statusErr *llm.StatusError
+	require.ErrorAs(t, final.Err, &statusErr, "the API's status is kept for routing policies")
+	assert.Equal(t, http.StatusBadRequest, statusErr.StatusCode)
+}
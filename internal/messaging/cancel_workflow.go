@@ -8,15 +8,18 @@ import (
 
 // CancelWorkflowMessage defines a CancelWorkflow message
 type CancelWorkflowMessage struct {
+	// Namespace is the workflow's namespace, which addresses its handler.
+	Namespace  string
 	WorkflowID workflow.ID
 	Reason     string
 }
 
 // NewCancelWorkflowMessage creates a new CancelWorkflow message
-func NewCancelWorkflowMessage(workflowID workflow.ID, reason string) Message {
+func NewCancelWorkflowMessage(namespace string, workflowID workflow.ID, reason string) Message {
 	return Message{
 		Type: CancelWorkflow,
 		Args: CancelWorkflowMessage{
+			Namespace:  namespace,
 			WorkflowID: workflowID,
 			Reason:     reason,
 		},
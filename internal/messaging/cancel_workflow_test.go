@@ -10,18 +10,19 @@ import (
 
 func TestNewCancelWorkflowMessage(t *testing.T) {
 	wfID := workflow.NewID()
-	msg := NewCancelWorkflowMessage(wfID, "user requested")
+	msg := NewCancelWorkflowMessage("billing", wfID, "user requested")
 
 	assert.Equal(t, CancelWorkflow, msg.Type)
 	cancelMsg, ok := msg.Args.(CancelWorkflowMessage)
 	require.True(t, ok)
+	assert.Equal(t, "billing", cancelMsg.Namespace)
 	assert.Equal(t, wfID, cancelMsg.WorkflowID)
 	assert.Equal(t, "user requested", cancelMsg.Reason)
 }
 
 func TestMessage_CancelWorkflowMessage_Success(t *testing.T) {
 	wfID := workflow.NewID()
-	msg := NewCancelWorkflowMessage(wfID, "test")
+	msg := NewCancelWorkflowMessage(workflow.DefaultNamespaceName, wfID, "test")
 
 	result, err := msg.CancelWorkflowMessage()
 
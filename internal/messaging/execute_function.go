@@ -8,6 +8,16 @@ import (
 	"github.com/open-source-cloud/fuse/pkg/workflow"
 )
 
+// ExecutionScope is the workflow-level context a function execution runs in.
+type ExecutionScope struct {
+	// Environment is the workflow's resolution scope (ADR-0031).
+	Environment string
+	// SchemaID is the schema the workflow was triggered from.
+	SchemaID string
+	// LLMBudget is the schema's LLM spend cap, if any.
+	LLMBudget *workflow.LLMBudget
+}
+
 // ExecuteFunctionMessage defines a ExecuteFunction message
 type ExecuteFunctionMessage struct {
 	WorkflowID  workflow.ID     `json:"workflow_id"`
@@ -17,26 +27,39 @@ type ExecuteFunctionMessage struct {
 	FunctionID  string          `json:"function_id"`
 	Input       map[string]any  `json:"input"`
 	Environment string          `json:"environment"`
+	SchemaID    string          `json:"schema_id,omitempty"`
+	// LLMBudget is the schema's LLM spend cap, enforced by ai functions (see ExecutionInfo).
+	LLMBudget *workflow.LLMBudget `json:"llm_budget,omitempty"`
+	// CallbackToken authorizes the single async result of this execution (see ExecutionInfo).
+	CallbackToken string `json:"callback_token,omitempty"`
+	// Checkpoints are the sub-steps already journaled by this execution attempt (see ExecutionInfo).
+	Checkpoints []map[string]any `json:"checkpoints,omitempty"`
 }
 
 // NewExecuteFunctionMessage creates a new ExecuteFunction message.
-// environment is the workflow's resolution scope (ADR-0031), carried to the function so the
-// engine can resolve per-context capabilities. Pass a non-nil traceCarrier to propagate the
-// calling span's context to the worker.
-func NewExecuteFunctionMessage(workflowID workflow.ID, execAction *workflowactions.RunFunctionAction, environment string, traceCarrier map[string]string) Message {
+// scope carries the workflow's environment (ADR-0031), schema and LLM budget to the function so
+// the engine can resolve per-context capabilities and enforce spend caps. Pass a non-nil traceCarrier to propagate the
+// calling span's context to the worker. callbackToken is the signed token an async function
+// presents when it reports its result. checkpoints lets a replayed execution resume from the
+// sub-steps it journaled before it was interrupted.
+func NewExecuteFunctionMessage(workflowID workflow.ID, execAction *workflowactions.RunFunctionAction, scope ExecutionScope, callbackToken string, checkpoints []map[string]any, traceCarrier map[string]string) Message {
 	lastSlashIndex := strings.LastIndex(execAction.FunctionID, "/")
 
 	return Message{
 		Type:         ExecuteFunction,
 		TraceCarrier: traceCarrier,
 		Args: ExecuteFunctionMessage{
-			WorkflowID:  workflowID,
-			ExecID:      execAction.FunctionExecID,
-			ThreadID:    execAction.ThreadID,
-			PackageID:   execAction.FunctionID[:lastSlashIndex],
-			FunctionID:  execAction.FunctionID,
-			Input:       execAction.Args,
-			Environment: environment,
+			WorkflowID:    workflowID,
+			ExecID:        execAction.FunctionExecID,
+			ThreadID:      execAction.ThreadID,
+			PackageID:     execAction.FunctionID[:lastSlashIndex],
+			FunctionID:    execAction.FunctionID,
+			Input:         execAction.Args,
+			Environment:   scope.Environment,
+			SchemaID:      scope.SchemaID,
+			LLMBudget:     scope.LLMBudget,
+			CallbackToken: callbackToken,
+			Checkpoints:   checkpoints,
 		},
 	}
 }
@@ -0,0 +1,38 @@
+package messaging
+
+import (
+	"fmt"
+
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+)
+
+// FunctionCheckpointMessage defines a FunctionCheckpoint message
+type FunctionCheckpointMessage struct {
+	WorkflowID workflow.ID     `json:"workflow_id"`
+	ExecID     workflow.ExecID `json:"exec_id"`
+	// Index is the number of checkpoints the function already knew for its attempt; the handler
+	// only journals a checkpoint that extends the attempt's list exactly.
+	Index int            `json:"index"`
+	Data  map[string]any `json:"data"`
+}
+
+// NewFunctionCheckpointMessage creates a new FunctionCheckpoint message
+func NewFunctionCheckpointMessage(workflowID workflow.ID, execID workflow.ExecID, index int, data map[string]any) Message {
+	return Message{
+		Type: FunctionCheckpoint,
+		Args: FunctionCheckpointMessage{
+			WorkflowID: workflowID,
+			ExecID:     execID,
+			Index:      index,
+			Data:       data,
+		},
+	}
+}
+
+// FunctionCheckpointMessage helper func to cast from a generic Message type
+func (m Message) FunctionCheckpointMessage() (FunctionCheckpointMessage, error) {
+	if m.Type != FunctionCheckpoint {
+		return FunctionCheckpointMessage{}, fmt.Errorf("message type %s is not FunctionCheckpoint", m.Type)
+	}
+	return m.Args.(FunctionCheckpointMessage), nil
+}
@@ -0,0 +1,43 @@
+package messaging
+
+import (
+	"time"
+
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+)
+
+// InvokeToolMessage defines an InvokeTool message: a running node (the parent execution) asks the
+// workflow handler to run a function as a child execution and route its result back to the node
+// instead of into the graph (ADR-0027).
+type InvokeToolMessage struct {
+	WorkflowID   workflow.ID
+	ParentExecID workflow.ExecID
+	// ExecID is the child execution allocated by the caller; its result resolves the caller's waiter.
+	ExecID     workflow.ExecID
+	FunctionID string
+	Input      map[string]any
+	// Timeout bounds the child execution; when it elapses the call completes with an error.
+	Timeout time.Duration
+}
+
+// NewInvokeToolMessage creates a new InvokeTool message
+func NewInvokeToolMessage(
+	workflowID workflow.ID,
+	parentExecID workflow.ExecID,
+	execID workflow.ExecID,
+	functionID string,
+	input map[string]any,
+	timeout time.Duration,
+) Message {
+	return Message{
+		Type: InvokeTool,
+		Args: InvokeToolMessage{
+			WorkflowID:   workflowID,
+			ParentExecID: parentExecID,
+			ExecID:       execID,
+			FunctionID:   functionID,
+			Input:        input,
+			Timeout:      timeout,
+		},
+	}
+}
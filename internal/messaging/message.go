@@ -13,6 +13,8 @@ const (
 	FunctionResult MessageType = "function:result"
 	// AsyncFunctionResult message type
 	AsyncFunctionResult MessageType = "function:async:result"
+	// FunctionCheckpoint message type - a running function journals one of its sub-steps
+	FunctionCheckpoint MessageType = "function:checkpoint"
 	// WorkflowCompleted message type
 	WorkflowCompleted MessageType = "workflow:completed"
 	// RecoverWorkflows message type - triggers startup recovery of in-progress workflows
@@ -33,6 +35,8 @@ const (
 	PublishGraphSchemaUpsert MessageType = "schema:publish-upsert"
 	// RetryNode message type - manually retry a specific failed node
 	RetryNode MessageType = "workflow:retry-node"
+	// InvokeTool message type - a running node starts a function as an async tool call (ADR-0027)
+	InvokeTool MessageType = "function:tool:invoke"
 )
 
 // Message defines the basic Message
@@ -8,15 +8,18 @@ import (
 
 // RetryNodeMessage requests a manual retry of a specific failed node execution.
 type RetryNodeMessage struct {
+	// Namespace is the workflow's namespace, which addresses its handler.
+	Namespace  string
 	WorkflowID workflow.ID
 	ExecID     workflow.ExecID
 }
 
 // NewRetryNodeMessage creates a new RetryNode message.
-func NewRetryNodeMessage(workflowID workflow.ID, execID workflow.ExecID) Message {
+func NewRetryNodeMessage(namespace string, workflowID workflow.ID, execID workflow.ExecID) Message {
 	return Message{
 		Type: RetryNode,
 		Args: RetryNodeMessage{
+			Namespace:  namespace,
 			WorkflowID: workflowID,
 			ExecID:     execID,
 		},
@@ -1,9 +1,12 @@
 package messaging
 
 // GraphSchemaReplicationPayload is published via ergo SendEvent and applied on peer nodes without republishing.
+// A payload without SchemaJSON replicates a lifecycle change, or a deletion when Deleted is set.
 type GraphSchemaReplicationPayload struct {
 	SchemaID   string
 	SchemaJSON []byte
+	Lifecycle  string
+	Deleted    bool
 }
 
 // NewPublishGraphSchemaUpsertMessage wraps a replication payload for the schema replication actor.
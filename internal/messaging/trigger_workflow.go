@@ -13,6 +13,8 @@ type TriggerWorkflowMessage struct {
 	// Environment scopes secret resolution for this execution (ADR-0031). Empty means the
 	// engine default applies (resolved by the WorkflowHandler).
 	Environment string
+	// Version pins the execution to a specific schema version. Zero runs the active version.
+	Version int
 }
 
 // NewTriggerWorkflowMessage creates a new TriggerWorkflow message
@@ -38,6 +40,48 @@ func NewTriggerWorkflowWithEnvMessage(schemaID string, workflowID workflow.ID, e
 	}
 }
 
+// NewTriggerWorkflowWithVersionMessage creates a TriggerWorkflow message scoped to an environment
+// and pinned to a specific schema version (zero runs the active version).
+func NewTriggerWorkflowWithVersionMessage(schemaID string, workflowID workflow.ID, environment string, version int) Message {
+	return Message{
+		Type: TriggerWorkflow,
+		Args: TriggerWorkflowMessage{
+			SchemaID:    schemaID,
+			WorkflowID:  workflowID,
+			Environment: environment,
+			Version:     version,
+		},
+	}
+}
+
+// NewTriggerWorkflowWithEnvAndInputMessage creates a TriggerWorkflow message scoped to an
+// environment with input data.
+func NewTriggerWorkflowWithEnvAndInputMessage(schemaID string, workflowID workflow.ID, environment string, input map[string]any) Message {
+	return Message{
+		Type: TriggerWorkflow,
+		Args: TriggerWorkflowMessage{
+			SchemaID:    schemaID,
+			WorkflowID:  workflowID,
+			Input:       input,
+			Environment: environment,
+		},
+	}
+}
+
+// NewTriggerSubWorkflowMessage creates a TriggerWorkflow message for a sub-workflow, which runs in
+// its parent's environment with the input the parent passed.
+func NewTriggerSubWorkflowMessage(schemaID string, workflowID workflow.ID, environment string, input map[string]any) Message {
+	return Message{
+		Type: TriggerWorkflow,
+		Args: TriggerWorkflowMessage{
+			SchemaID:    schemaID,
+			WorkflowID:  workflowID,
+			Input:       input,
+			Environment: environment,
+		},
+	}
+}
+
 // NewTriggerWorkflowWithInputMessage creates a TriggerWorkflow message with input data
 func NewTriggerWorkflowWithInputMessage(schemaID string, workflowID workflow.ID, input map[string]any) Message {
 	return Message{
@@ -16,6 +16,16 @@ type FuseMetrics struct {
 	// WorkflowsCancelled is the total number of cancelled workflows.
 	WorkflowsCancelled prometheus.Counter
 
+	// WorkflowVersionOutcomes counts terminal workflow executions per schema version, to compare
+	// a canary against its baseline. Labels: schema_id, version, status (finished|error|cancelled).
+	WorkflowVersionOutcomes *prometheus.CounterVec
+
+	// NamespaceWorkflowsActive is the number of in-flight workflows per namespace. Labels: namespace.
+	NamespaceWorkflowsActive *prometheus.GaugeVec
+	// NamespaceWorkflowOutcomes counts terminal workflow executions per namespace.
+	// Labels: namespace, status (finished|error|cancelled).
+	NamespaceWorkflowOutcomes *prometheus.CounterVec
+
 	// NodeExecDuration records the duration of individual node (function) executions.
 	// Labels: function_id, status (success|error).
 	NodeExecDuration *prometheus.HistogramVec
@@ -25,6 +35,13 @@ type FuseMetrics struct {
 	LLMTokens *prometheus.CounterVec
 	// LLMCalls counts LLM completion calls. Labels: function, provider, model, status (success|error).
 	LLMCalls *prometheus.CounterVec
+	// LLMCost accumulates the USD cost of LLM calls, priced from the LLM_PRICING table.
+	// Labels: function, provider, model.
+	LLMCost *prometheus.CounterVec
+
+	// RetentionPurged counts rows and objects deleted by retention purges.
+	// Labels: resource (workflows|journal_entries|execution_traces|awakeables|llm_spend|snapshots|agent_sessions).
+	RetentionPurged *prometheus.CounterVec
 
 	registry *prometheus.Registry
 }
@@ -57,6 +74,23 @@ func NewFuseMetrics() *FuseMetrics {
 			Help:      "Total number of workflow instances that were cancelled.",
 		}),
 
+		WorkflowVersionOutcomes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "fuse",
+			Name:      "workflow_version_outcomes_total",
+			Help:      "Total terminal workflow executions by schema version and final status.",
+		}, []string{"schema_id", "version", "status"}),
+
+		NamespaceWorkflowsActive: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "fuse",
+			Name:      "namespace_workflows_active",
+			Help:      "Number of currently active (in-flight) workflow instances by namespace.",
+		}, []string{"namespace"}),
+		NamespaceWorkflowOutcomes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "fuse",
+			Name:      "namespace_workflow_outcomes_total",
+			Help:      "Total terminal workflow executions by namespace and final status.",
+		}, []string{"namespace", "status"}),
+
 		NodeExecDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
 			Namespace: "fuse",
 			Name:      "node_exec_duration_seconds",
@@ -74,6 +108,17 @@ func NewFuseMetrics() *FuseMetrics {
 			Name:      "llm_calls_total",
 			Help:      "Total LLM completion calls made by ai nodes.",
 		}, []string{"function", "provider", "model", "status"}),
+		LLMCost: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "fuse",
+			Name:      "llm_cost_usd_total",
+			Help:      "Total USD cost of LLM calls made by ai nodes, per the configured pricing table.",
+		}, []string{"function", "provider", "model"}),
+
+		RetentionPurged: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "fuse",
+			Name:      "retention_purged_rows_total",
+			Help:      "Total rows and objects deleted by retention purges, by resource.",
+		}, []string{"resource"}),
 	}
 
 	reg.MustRegister(
@@ -81,9 +126,14 @@ func NewFuseMetrics() *FuseMetrics {
 		m.WorkflowsCompleted,
 		m.WorkflowsFailed,
 		m.WorkflowsCancelled,
+		m.WorkflowVersionOutcomes,
+		m.NamespaceWorkflowsActive,
+		m.NamespaceWorkflowOutcomes,
 		m.NodeExecDuration,
 		m.LLMTokens,
 		m.LLMCalls,
+		m.LLMCost,
+		m.RetentionPurged,
 	)
 
 	return m
@@ -41,6 +41,19 @@ func TestFuseMetrics_workflowCounters(t *testing.T) {
 	assert.Equal(t, 1.0, testutil.ToFloat64(m.WorkflowsCancelled))
 }
 
+func TestFuseMetrics_namespaceLabels(t *testing.T) {
+	m := metrics.NewFuseMetrics()
+
+	m.NamespaceWorkflowsActive.WithLabelValues("billing").Inc()
+	m.NamespaceWorkflowsActive.WithLabelValues("default").Inc()
+	m.NamespaceWorkflowsActive.WithLabelValues("billing").Dec()
+	m.NamespaceWorkflowOutcomes.WithLabelValues("billing", "finished").Inc()
+
+	assert.Equal(t, 0.0, testutil.ToFloat64(m.NamespaceWorkflowsActive.WithLabelValues("billing")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.NamespaceWorkflowsActive.WithLabelValues("default")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.NamespaceWorkflowOutcomes.WithLabelValues("billing", "finished")))
+}
+
 func TestFuseMetrics_nodeExecDuration(t *testing.T) {
 	m := metrics.NewFuseMetrics()
 
@@ -0,0 +1,63 @@
+package packages
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/actors/actornames"
+	"github.com/open-source-cloud/fuse/internal/concurrency"
+	"github.com/open-source-cloud/fuse/internal/messaging"
+	"github.com/open-source-cloud/fuse/internal/packages/functions/ai"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+)
+
+// errRuntimeNotBound is returned by InvokeAsync before the actor node is bound at startup.
+var errRuntimeNotBound = errors.New("async tool runtime is not bound to an actor node")
+
+// AgentExecRuntime adapts the workflow engine to the ai.ExecRuntime port (ADR-0027). Each call is
+// sent to the workflow handler of the calling execution, which runs it as a child execution; the
+// result comes back through the Waiters registry, correlated by the child's exec id. The agent
+// runs in its own goroutine, so calls go through the node rather than a process.
+type AgentExecRuntime struct {
+	waiters *concurrency.Waiters
+	mu      sync.RWMutex
+	node    gen.Node
+}
+
+// compile-time assertion that the adapter satisfies the port.
+var _ ai.ExecRuntime = (*AgentExecRuntime)(nil)
+
+// NewAgentExecRuntime creates the runtime; call BindNode from fx OnStart after the node exists.
+func NewAgentExecRuntime(waiters *concurrency.Waiters) *AgentExecRuntime {
+	return &AgentExecRuntime{waiters: waiters}
+}
+
+// BindNode wires the ergo node the calls are sent through; safe to call once at startup.
+func (r *AgentExecRuntime) BindNode(node gen.Node) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.node = node
+}
+
+// InvokeAsync registers a waiter for the call's exec id and asks the workflow handler to start it.
+// The waiter is dropped when ctx is done, so an abandoned call leaves nothing behind.
+func (r *AgentExecRuntime) InvokeAsync(ctx context.Context, call ai.AsyncCall) (<-chan workflow.FunctionOutput, error) {
+	r.mu.RLock()
+	node := r.node
+	r.mu.RUnlock()
+	if node == nil {
+		return nil, errRuntimeNotBound
+	}
+
+	wfID, execID := call.WorkflowID.String(), call.ExecID.String()
+	results := r.waiters.Register(wfID, execID)
+	msg := messaging.NewInvokeToolMessage(call.WorkflowID, call.ParentExecID, call.ExecID, call.FunctionID, call.Input, call.Timeout)
+	if err := node.Send(gen.Atom(actornames.WorkflowHandlerName(call.Namespace, call.WorkflowID)), msg); err != nil {
+		r.waiters.Forget(wfID, execID)
+		return nil, err
+	}
+	context.AfterFunc(ctx, func() { r.waiters.Forget(wfID, execID) })
+	return results, nil
+}
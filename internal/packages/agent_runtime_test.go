@@ -0,0 +1,99 @@
+package packages
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/actors/actornames"
+	"github.com/open-source-cloud/fuse/internal/concurrency"
+	"github.com/open-source-cloud/fuse/internal/messaging"
+	"github.com/open-source-cloud/fuse/internal/packages/functions/ai"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sendingNode records the messages sent through it; other gen.Node methods are not used.
+type sendingNode struct {
+	gen.Node
+	to   []any
+	sent []any
+	err  error
+}
+
+func (n *sendingNode) Send(to any, message any) error {
+	n.to = append(n.to, to)
+	n.sent = append(n.sent, message)
+	return n.err
+}
+
+func testAsyncCall() ai.AsyncCall {
+	parent := workflow.NewExecID(2)
+	return ai.AsyncCall{
+		Namespace:    "billing",
+		WorkflowID:   "wf-1",
+		ParentExecID: parent,
+		ExecID:       workflow.NewExecID(parent.Thread()),
+		FunctionID:   "fuse/pkg/ai/chat",
+		Input:        map[string]any{"input": "hi"},
+		Timeout:      time.Minute,
+	}
+}
+
+func TestAgentExecRuntime_SendsTheCallAndDeliversTheResult(t *testing.T) {
+	waiters := concurrency.NewWaiters()
+	node := &sendingNode{}
+	runtime := NewAgentExecRuntime(waiters)
+	runtime.BindNode(node)
+	call := testAsyncCall()
+
+	results, err := runtime.InvokeAsync(context.Background(), call)
+	require.NoError(t, err)
+
+	require.Len(t, node.sent, 1)
+	assert.Equal(t, gen.Atom(actornames.WorkflowHandlerName(call.Namespace, call.WorkflowID)), node.to[0])
+	msg := node.sent[0].(messaging.Message)
+	assert.Equal(t, messaging.InvokeTool, msg.Type)
+	assert.Equal(t, messaging.InvokeToolMessage{
+		WorkflowID:   call.WorkflowID,
+		ParentExecID: call.ParentExecID,
+		ExecID:       call.ExecID,
+		FunctionID:   call.FunctionID,
+		Input:        call.Input,
+		Timeout:      call.Timeout,
+	}, msg.Args)
+
+	out := workflow.NewFunctionSuccessOutput(map[string]any{"output": "hello"})
+	require.True(t, waiters.Resolve(call.WorkflowID.String(), call.ExecID.String(), out))
+	assert.Equal(t, out, <-results)
+}
+
+func TestAgentExecRuntime_ForgetsTheWaiterWhenTheCallerStops(t *testing.T) {
+	waiters := concurrency.NewWaiters()
+	runtime := NewAgentExecRuntime(waiters)
+	runtime.BindNode(&sendingNode{})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	_, err := runtime.InvokeAsync(ctx, testAsyncCall())
+	require.NoError(t, err)
+	assert.Equal(t, 1, waiters.Len())
+
+	cancel()
+	assert.Eventually(t, func() bool { return waiters.Len() == 0 }, time.Second, 5*time.Millisecond)
+}
+
+func TestAgentExecRuntime_Errors(t *testing.T) {
+	waiters := concurrency.NewWaiters()
+	runtime := NewAgentExecRuntime(waiters)
+
+	_, err := runtime.InvokeAsync(context.Background(), testAsyncCall())
+	assert.ErrorIs(t, err, errRuntimeNotBound)
+
+	runtime.BindNode(&sendingNode{err: errors.New("no such process")})
+	_, err = runtime.InvokeAsync(context.Background(), testAsyncCall())
+	assert.EqualError(t, err, "no such process")
+	assert.Zero(t, waiters.Len(), "a call that was not sent leaves no waiter")
+}
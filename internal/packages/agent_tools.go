@@ -1,58 +1,82 @@
 package packages
 
 import (
+	"context"
 	"fmt"
+	"strings"
 
 	"github.com/open-source-cloud/fuse/internal/packages/functions/ai"
 	"github.com/open-source-cloud/fuse/internal/packages/functions/logic"
 	"github.com/open-source-cloud/fuse/internal/packages/functions/system"
 	"github.com/open-source-cloud/fuse/internal/packages/transport"
+	"github.com/open-source-cloud/fuse/pkg/mcp"
 	"github.com/open-source-cloud/fuse/pkg/workflow"
 )
 
-// interceptedOrAsyncFunctionIDs lists full function ids that must never be
-// exposed to an agent as tools in Phase B (ADR-0007). These either complete
-// asynchronously or are intercepted by the WorkflowHandler, so their result is
-// routed away from the agent goroutine rather than returned inline. The whole
-// ai package is excluded separately in ListTools (all ai/* nodes are async).
-var interceptedOrAsyncFunctionIDs = map[string]struct{}{
+// asyncFunctionIDs lists full function ids that either complete asynchronously
+// or are intercepted by the WorkflowHandler, so their result cannot be returned
+// inline. They are exposed as Async tools, which the agent calls through
+// ai.ExecRuntime as child executions (ADR-0027). The whole ai package is async
+// too: its nodes run in their own goroutines and need the execution's scope.
+var asyncFunctionIDs = map[string]struct{}{
 	system.SleepFullFunctionID:                    {}, // intercepted by WorkflowHandler
 	system.WaitFullFunctionID:                     {}, // intercepted
 	system.SubWorkflowFullFunctionID:              {}, // intercepted
-	system.ForEachFullFunctionID:                  {}, // intercepted
 	logic.PackageID + "/" + logic.TimerFunctionID: {}, // async (delivers via Finish)
 }
 
+// excludedFunctionIDs lists full function ids that are never tools. system/foreach
+// iterates over threads of the graph it belongs to, which a tool call has not.
+var excludedFunctionIDs = map[string]struct{}{
+	system.ForEachFullFunctionID: {},
+}
+
+// MCPTools lists and calls the tools of the MCP servers registered in the namespace of the
+// running schema. It is implemented by the MCP server service, which depends on this package.
+type MCPTools interface {
+	ListTools(ctx context.Context, execInfo *workflow.ExecutionInfo) []ai.ToolDescriptor
+	InvokeTool(functionID string, execInfo *workflow.ExecutionInfo) (workflow.FunctionResult, error)
+}
+
 // AgentToolRegistry adapts the package Registry to the ai.ToolRegistry port the
-// ai/agent node depends on. It lives in package packages (not ai) so that ai
-// does not import internal/packages, which would create an import cycle.
+// ai/agent node depends on, merging in the tools of MCP servers. It lives in package
+// packages (not ai) so that ai does not import internal/packages, which would create an
+// import cycle.
 type AgentToolRegistry struct {
 	registry Registry
+	mcp      MCPTools
 }
 
 // compile-time assertion that the adapter satisfies the port.
 var _ ai.ToolRegistry = (*AgentToolRegistry)(nil)
 
-// NewAgentToolRegistry creates an adapter over the package registry. It holds the
-// Registry interface (not a snapshot); ListTools reads it lazily at agent-execution
-// time, after the registry has been populated at startup.
-func NewAgentToolRegistry(registry Registry) *AgentToolRegistry {
-	return &AgentToolRegistry{registry: registry}
+// NewAgentToolRegistry creates an adapter over the package registry and, when mcpTools is not
+// nil, the MCP servers. It holds the Registry interface (not a snapshot); ListTools reads it
+// lazily at agent-execution time, after the registry has been populated at startup.
+func NewAgentToolRegistry(registry Registry, mcpTools MCPTools) *AgentToolRegistry {
+	return &AgentToolRegistry{registry: registry, mcp: mcpTools}
 }
 
-// ListTools returns the synchronous, declared-parameter functions eligible to be
-// exposed to the model as tools.
-func (a *AgentToolRegistry) ListTools() []ai.ToolDescriptor {
-	pkgs, err := a.registry.List()
-	if err != nil {
-		return nil
+// ListTools returns the declared-parameter functions of the packages visible from the
+// execution's namespace that are eligible to be exposed to the model as tools, marking the
+// asynchronous ones, followed by the MCP tools of the execution's namespace.
+func (a *AgentToolRegistry) ListTools(ctx context.Context, execInfo *workflow.ExecutionInfo) []ai.ToolDescriptor {
+	tools := a.functionTools(executionNamespace(execInfo))
+	if a.mcp != nil {
+		tools = append(tools, a.mcp.ListTools(ctx, execInfo)...)
 	}
+	return tools
+}
 
+// functionTools returns the functions of the packages visible from namespace that may be tools.
+func (a *AgentToolRegistry) functionTools(namespace string) []ai.ToolDescriptor {
 	tools := make([]ai.ToolDescriptor, 0)
+	pkgs, err := a.registry.ListVisible(namespace)
+	if err != nil {
+		return tools
+	}
+
 	for _, pkg := range pkgs {
-		if pkg.ID == ai.PackageID {
-			continue // every ai/* function (chat, agent) is async — never a tool in Phase B
-		}
 		for fullID, fn := range pkg.Functions {
 			if !isExposableTool(fullID, fn) {
 				continue
@@ -66,6 +90,7 @@ func (a *AgentToolRegistry) ListTools() []ai.ToolDescriptor {
 				MangledName: ai.MangleToolName(fullID),
 				Description: fmt.Sprintf("FUSE function %s", fullID),
 				Parameters:  ai.ParameterSchemaToJSONSchema(params),
+				Async:       isAsyncTool(pkg.ID, fullID),
 			})
 		}
 	}
@@ -74,10 +99,14 @@ func (a *AgentToolRegistry) ListTools() []ai.ToolDescriptor {
 
 // InvokeTool runs the function with the given full id synchronously in-process and
 // returns its result inline (Async == false). The function must belong to a
-// registered package. No worker handle is used: only synchronous functions are
-// exposed as tools, so the actor system is never reached.
+// registered package visible from the execution's namespace; MCP tool ids (mcp/<server>/<tool>) are called on their server.
+// No worker handle is used, so the actor system is never reached; Async tools are
+// invoked through AgentExecRuntime instead.
 func (a *AgentToolRegistry) InvokeTool(functionID string, execInfo *workflow.ExecutionInfo) (workflow.FunctionResult, error) {
-	pkgs, err := a.registry.List()
+	if strings.HasPrefix(functionID, mcp.FunctionIDPrefix) && a.mcp != nil {
+		return a.mcp.InvokeTool(functionID, execInfo)
+	}
+	pkgs, err := a.registry.ListVisible(executionNamespace(execInfo))
 	if err != nil {
 		return workflow.FunctionResult{}, err
 	}
@@ -89,9 +118,17 @@ func (a *AgentToolRegistry) InvokeTool(functionID string, execInfo *workflow.Exe
 	return workflow.FunctionResult{}, fmt.Errorf("tool function %q not found", functionID)
 }
 
+// executionNamespace returns the namespace of the execution a tool is listed or invoked for.
+func executionNamespace(execInfo *workflow.ExecutionInfo) string {
+	if execInfo == nil {
+		return workflow.DefaultNamespaceName
+	}
+	return workflow.NamespaceOf(execInfo.SchemaID)
+}
+
 // isExposableTool reports whether a function may be offered to the model as a tool:
-// it must be invocable in-process (has an internal transport), use declared (not
-// schemaless/CustomParameters) inputs, and not be on the intercepted/async denylist.
+// it must be an internal function (has an internal transport), use declared (not
+// schemaless/CustomParameters) inputs, and not be excluded.
 func isExposableTool(fullID string, fn *LoadedFunction) bool {
 	if fn == nil || fn.Metadata == nil {
 		return false
@@ -104,8 +141,18 @@ func isExposableTool(fullID string, fn *LoadedFunction) bool {
 	if fn.Metadata.Input.CustomParameters {
 		return false
 	}
-	if _, excluded := interceptedOrAsyncFunctionIDs[fullID]; excluded {
+	if _, excluded := excludedFunctionIDs[fullID]; excluded {
 		return false
 	}
 	return true
 }
+
+// isAsyncTool reports whether a tool completes asynchronously and must be invoked
+// through ai.ExecRuntime.
+func isAsyncTool(packageID, fullID string) bool {
+	if packageID == ai.PackageID {
+		return true
+	}
+	_, async := asyncFunctionIDs[fullID]
+	return async
+}
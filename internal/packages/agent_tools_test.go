@@ -1,6 +1,7 @@
 package packages
 
 import (
+	"context"
 	"testing"
 
 	"github.com/open-source-cloud/fuse/internal/packages/functions/ai"
@@ -20,7 +21,7 @@ func newTestRegistry(t *testing.T) Registry {
 	reg.Register(system.New())
 	reg.Register(logic.New())
 
-	// A stand-in ai package so we can assert the whole ai package is excluded
+	// A stand-in ai package so we can assert the whole ai package is async
 	// without constructing the real one (which needs a provider + tool registry).
 	fakeAi := workflow.NewPackage(ai.PackageID,
 		workflow.NewFunction("chat", workflow.FunctionMetadata{
@@ -42,32 +43,41 @@ func toolIDSet(tools []ai.ToolDescriptor) map[string]ai.ToolDescriptor {
 	return out
 }
 
-func TestListTools_IncludesSyncSchemaFunctionsOnly(t *testing.T) {
+func TestListTools_IncludesSchemaFunctionsAndMarksAsyncOnes(t *testing.T) {
 	t.Parallel()
 
-	adapter := NewAgentToolRegistry(newTestRegistry(t))
-	byID := toolIDSet(adapter.ListTools())
+	adapter := NewAgentToolRegistry(newTestRegistry(t), nil)
+	byID := toolIDSet(adapter.ListTools(context.Background(), nil))
 
-	// Included: synchronous, declared-parameter functions.
-	assert.Contains(t, byID, "fuse/pkg/logic/sum")
-	assert.Contains(t, byID, "fuse/pkg/logic/rand")
+	// Synchronous, declared-parameter functions are invoked inline.
+	for _, id := range []string{"fuse/pkg/logic/sum", "fuse/pkg/logic/rand"} {
+		require.Contains(t, byID, id)
+		assert.False(t, byID[id].Async, id)
+	}
+
+	// Intercepted system functions, async (timer) functions and the whole ai package go
+	// through the ExecRuntime.
+	for _, id := range []string{
+		system.SleepFullFunctionID,
+		system.WaitFullFunctionID,
+		system.SubWorkflowFullFunctionID,
+		"fuse/pkg/logic/timer",
+		"fuse/pkg/ai/chat",
+	} {
+		require.Contains(t, byID, id)
+		assert.True(t, byID[id].Async, id)
+	}
 
-	// Excluded: intercepted system functions.
-	assert.NotContains(t, byID, system.SleepFullFunctionID)
-	assert.NotContains(t, byID, system.WaitFullFunctionID)
-	assert.NotContains(t, byID, system.SubWorkflowFullFunctionID)
+	// Excluded: foreach (needs the graph) and schemaless (if).
 	assert.NotContains(t, byID, system.ForEachFullFunctionID)
-	// Excluded: async (timer) and schemaless (if), and the whole ai package.
-	assert.NotContains(t, byID, "fuse/pkg/logic/timer")
 	assert.NotContains(t, byID, "fuse/pkg/logic/if")
-	assert.NotContains(t, byID, "fuse/pkg/ai/chat")
 }
 
 func TestListTools_DescriptorShape(t *testing.T) {
 	t.Parallel()
 
-	adapter := NewAgentToolRegistry(newTestRegistry(t))
-	sum, ok := toolIDSet(adapter.ListTools())["fuse/pkg/logic/sum"]
+	adapter := NewAgentToolRegistry(newTestRegistry(t), nil)
+	sum, ok := toolIDSet(adapter.ListTools(context.Background(), nil))["fuse/pkg/logic/sum"]
 	require.True(t, ok)
 
 	assert.Equal(t, "fuse__pkg__logic__sum", sum.MangledName)
@@ -80,7 +90,7 @@ func TestListTools_DescriptorShape(t *testing.T) {
 func TestInvokeTool_RunsSyncFunctionInline(t *testing.T) {
 	t.Parallel()
 
-	adapter := NewAgentToolRegistry(newTestRegistry(t))
+	adapter := NewAgentToolRegistry(newTestRegistry(t), nil)
 	input, err := workflow.NewFunctionInputWith(map[string]any{"values": []float64{2, 3}})
 	require.NoError(t, err)
 	execInfo := workflow.NewExecutionInfo("wf-1", workflow.NewExecID(1), "", input)
@@ -95,13 +105,42 @@ func TestInvokeTool_RunsSyncFunctionInline(t *testing.T) {
 func TestInvokeTool_UnknownFunctionReturnsError(t *testing.T) {
 	t.Parallel()
 
-	adapter := NewAgentToolRegistry(newTestRegistry(t))
+	adapter := NewAgentToolRegistry(newTestRegistry(t), nil)
 	execInfo := workflow.NewExecutionInfo("wf-1", workflow.NewExecID(1), "", nil)
 
 	_, err := adapter.InvokeTool("does/not/exist", execInfo)
 	require.Error(t, err)
 }
 
+// fakeMCPTools offers one MCP tool and records the calls routed to it.
+type fakeMCPTools struct {
+	invoked []string
+}
+
+func (f *fakeMCPTools) ListTools(context.Context, *workflow.ExecutionInfo) []ai.ToolDescriptor {
+	return []ai.ToolDescriptor{{FunctionID: "mcp/wiki/search", MangledName: "mcp__wiki__search"}}
+}
+
+func (f *fakeMCPTools) InvokeTool(functionID string, _ *workflow.ExecutionInfo) (workflow.FunctionResult, error) {
+	f.invoked = append(f.invoked, functionID)
+	return workflow.NewFunctionResultSuccessWith(map[string]any{"text": "found"}), nil
+}
+
+func TestAgentToolRegistry_MergesAndRoutesMCPTools(t *testing.T) {
+	t.Parallel()
+
+	mcpTools := &fakeMCPTools{}
+	adapter := NewAgentToolRegistry(newTestRegistry(t), mcpTools)
+	byID := toolIDSet(adapter.ListTools(context.Background(), nil))
+	assert.Contains(t, byID, "mcp/wiki/search")
+	assert.Contains(t, byID, "fuse/pkg/logic/sum", "MCP tools join the package functions")
+
+	res, err := adapter.InvokeTool("mcp/wiki/search", workflow.NewExecutionInfo("wf-1", workflow.NewExecID(1), "", nil))
+	require.NoError(t, err)
+	assert.Equal(t, "found", res.Output.Data["text"])
+	assert.Equal(t, []string{"mcp/wiki/search"}, mcpTools.invoked)
+}
+
 func TestIsExposableTool_Predicate(t *testing.T) {
 	t.Parallel()
 
@@ -120,8 +159,10 @@ func TestIsExposableTool_Predicate(t *testing.T) {
 	}
 	assert.False(t, isExposableTool("fuse/pkg/logic/if", custom))
 
-	// intercepted/async denylist
-	assert.False(t, isExposableTool(system.SleepFullFunctionID, intl))
+	// excluded
+	assert.False(t, isExposableTool(system.ForEachFullFunctionID, intl))
+	// intercepted functions are exposed, as async tools
+	assert.True(t, isExposableTool(system.SleepFullFunctionID, intl))
 
 	// non-invocable (nil transport)
 	noTransport := &LoadedFunction{ID: "x", Metadata: &FunctionMetadata{Transport: transport.Internal}}
@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/open-source-cloud/fuse/internal/packages/transport"
@@ -22,12 +21,27 @@ const (
 	defaultMaxIterations = 10
 	// maxMaxIterations is the hard upper bound an author may request.
 	maxMaxIterations = 25
-	// agentTimeout bounds the whole multi-step interaction.
-	agentTimeout = 5 * time.Minute
+	// defaultAgentTimeout bounds one attempt of the multi-step interaction when the node sets no
+	// timeout input.
+	defaultAgentTimeout = 5 * time.Minute
+	// defaultAgentRetrieveTopK is the number of chunks retrieveFrom adds when retrieveTopK is unset.
+	defaultAgentRetrieveTopK = 4
+	// defaultToolTimeout bounds one tool call when the node sets no toolTimeout input.
+	defaultToolTimeout = 2 * time.Minute
+	// asyncToolGrace is how much longer than the tool timeout the agent waits for an async tool.
+	// The engine's timeout fires first and is journaled; the grace only matters when the workflow
+	// handler is gone and no result will ever come.
+	asyncToolGrace = 5 * time.Second
 )
 
-// ErrAgentInputRequired is returned when the required task input is missing.
-var ErrAgentInputRequired = errors.New("ai/agent: input is required")
+var (
+	// ErrAgentInputRequired is returned when the required task input is missing.
+	ErrAgentInputRequired = errors.New("ai/agent: input is required")
+	// ErrAgentInvalidTimeout is returned when the timeout input is not a positive duration.
+	ErrAgentInvalidTimeout = errors.New("ai/agent: timeout must be a positive duration (e.g. 90s, 10m)")
+	// ErrAgentInvalidToolTimeout is returned when the toolTimeout input is not a positive duration.
+	ErrAgentInvalidToolTimeout = errors.New("ai/agent: toolTimeout must be a positive duration (e.g. 30s, 5m)")
+)
 
 // AgentFunctionMetadata returns the metadata for the agent function.
 func AgentFunctionMetadata() workflow.FunctionMetadata {
@@ -37,15 +51,28 @@ func AgentFunctionMetadata() workflow.FunctionMetadata {
 			CustomParameters: false,
 			Parameters: []workflow.ParameterSchema{
 				{Name: "input", Type: "string", Required: true, Description: "The task / goal the agent should accomplish"},
-				{Name: "provider", Type: "string", Required: false, Description: "Provider registry key (e.g. openai, ollama). Defaults to the configured default provider"},
+				{Name: "provider", Type: "string", Required: false, Description: "Provider registry key (e.g. openai, ollama) or routing policy name. Defaults to the configured default provider"},
 				{Name: "model", Type: "string", Required: false, Description: "Model id. Defaults to the provider's configured default model"},
 				{Name: "systemPrompt", Type: "string", Required: false, Description: "Optional system instruction prepended to the conversation"},
 				{Name: "temperature", Type: "float", Required: false, Description: "Sampling temperature; if omitted the provider default is used"},
 				{Name: "maxIterations", Type: "int", Required: false, Default: defaultMaxIterations, Description: "Maximum reasoning iterations (clamped to [1, 25])"},
-				{Name: "allowedTools", Type: "array", Required: false, Description: "Optional allowlist of full function ids the agent may use as tools; empty means all eligible tools"},
+				{Name: "allowedTools", Type: "array", Required: false, Description: "Optional allowlist of full function ids the agent may use as tools, MCP tools as mcp/<server>/<tool>; empty means all eligible tools"},
 				{Name: "maxContextTokens", Type: "int", Required: false, Description: "Optional token budget for the running transcript (approximate); 0/absent disables trimming (ADR-0028)"},
 				{Name: "contextStrategy", Type: "string", Required: false, Description: "When over maxContextTokens: 'drop-oldest' (default) or 'summarize' (an extra LLM call summarizes dropped turns)"},
 				{Name: "outputSchema", Type: "array", Required: false, Description: "Optional list of {name,type,required,description} fields; when set the final output is a validated object matching this schema (ADR-0030)"},
+				{Name: "timeout", Type: "string", Required: false, Default: defaultAgentTimeout.String(), Description: "Deadline for one attempt of the reasoning loop as a duration (e.g. 90s, 10m); the node's execution timeout also applies"},
+				{Name: "toolTimeout", Type: "string", Required: false, Default: defaultToolTimeout.String(), Description: "Deadline for each tool call as a duration, notably asynchronous tools (sub-workflows, waits, other ai nodes) and MCP tools, which their server's timeout bounds too; a call that exceeds it returns an error to the model"},
+				{Name: "maxCostUSD", Type: "float", Required: false, Description: "Optional USD cap for this node's LLM calls, priced from LLM_PRICING"},
+				{Name: "maxTokens", Type: "int", Required: false, Description: "Optional cap on the total tokens of this node's LLM calls"},
+				{Name: "onBudgetExceeded", Type: "string", Required: false, Default: budgetActionFail, Description: "When a node or schema LLM budget is exceeded: 'fail' (default) fails the node with errorType budget_exceeded; 'stop' ends the loop and returns the last answer"},
+				{Name: "sessionId", Type: "string", Required: false, Description: "Optional conversation id; runs sharing it in a namespace see the earlier turns, and a successful run appends its input and answer"},
+				{Name: "sessionTTL", Type: "string", Required: false, Description: "How long the session is kept after this run as a duration (e.g. 24h); defaults to LLM_SESSION_TTL"},
+				{Name: "sessionMaxTokens", Type: "int", Required: false, Default: defaultSessionMaxTokens, Description: "Token budget (approximate) of the stored session transcript; older turns are trimmed with contextStrategy"},
+				{Name: "retrieveFrom", Type: "string", Required: false, Description: "Optional collection (see ai/index) whose chunks nearest to the input are added to the conversation before the first iteration"},
+				{Name: "retrieveTopK", Type: "int", Required: false, Default: defaultAgentRetrieveTopK, Description: "Maximum number of chunks retrieveFrom adds"},
+				{Name: "retrieveMinScore", Type: "float", Required: false, Description: "Minimum cosine similarity of a chunk retrieveFrom adds"},
+				{Name: "embeddingProvider", Type: "string", Required: false, Description: "Provider that embeds the input for retrieveFrom; defaults to provider"},
+				{Name: "embeddingModel", Type: "string", Required: false, Description: "Embedding model for retrieveFrom; must be the model the collection was indexed with. Defaults to the provider's EMBEDDING_MODEL"},
 			},
 			Edges: workflow.InputEdgeMetadata{
 				Count:      0,
@@ -55,8 +82,9 @@ func AgentFunctionMetadata() workflow.FunctionMetadata {
 		Output: workflow.OutputMetadata{
 			Parameters: []workflow.ParameterSchema{
 				{Name: "output", Type: "string", Required: true, Description: "The agent's final text answer"},
-				{Name: "usage", Type: "map", Required: false, Description: "Aggregated token usage across all reasoning steps"},
-				{Name: "steps", Type: "array", Required: false, Description: "Trace of each tool call: tool, arguments, and result or error"},
+				{Name: "usage", Type: "map", Required: false, Description: "Aggregated token usage across all reasoning steps and its priced cost (costUSD); with a routing policy also the policy, provider and model of the last step and the fallbacks of all steps"},
+				{Name: "steps", Type: "array", Required: false, Description: "Trace of each tool call: tool, arguments, and result or error; asynchronous calls also carry the execId of their child execution"},
+				{Name: "stopReason", Type: "string", Required: false, Description: "Set to budget_exceeded when onBudgetExceeded 'stop' ended the loop early"},
 			},
 			Edges: make([]workflow.OutputEdgeMetadata, 0),
 		},
@@ -64,8 +92,11 @@ func AgentFunctionMetadata() workflow.FunctionMetadata {
 }
 
 // makeAgentFunction builds the ai/agent function, closing over the provider
-// registry, the tool registry, and the usage recorder (ADR-0029).
-func makeAgentFunction(providers llm.Registry, tools ToolRegistry, usage UsageRecorder) workflow.Function {
+// registry, the tool registry, the usage recorder (ADR-0029), the pricing table
+// and ledger its spend is metered against, the session and vector stores
+// (ADR-0028), the output stream the model's text is streamed to, and the runtime
+// async tools are called through (ADR-0027).
+func makeAgentFunction(deps Deps) workflow.Function {
 	return func(execInfo *workflow.ExecutionInfo) (workflow.FunctionResult, error) {
 		input := execInfo.Input
 
@@ -76,47 +107,101 @@ func makeAgentFunction(providers llm.Registry, tools ToolRegistry, usage UsageRe
 
 		providerName := input.GetStr("provider")
 
-		llmTools, byMangled := buildTools(tools.ListTools(), allowedToolSet(input))
+		timeout, err := agentTimeout(input)
+		if err != nil {
+			return workflow.NewFunctionResultError(err)
+		}
+		toolTimeout, err := agentToolTimeout(input)
+		if err != nil {
+			return workflow.NewFunctionResultError(err)
+		}
+		limit, err := nodeBudgetLimit(input)
+		if err != nil {
+			return workflow.NewFunctionResultError(err)
+		}
+		onBudgetExceeded, err := budgetAction(input)
+		if err != nil {
+			return workflow.NewFunctionResultError(err)
+		}
+		sess, err := sessionFromInput(deps.Sessions, execInfo)
+		if err != nil {
+			return workflow.NewFunctionResultError(err)
+		}
+		retrieval, err := agentRetriever(deps.Vectors, execInfo)
+		if err != nil {
+			return workflow.NewFunctionResultError(err)
+		}
 
 		executor := &agentExecutor{
-			tools:       tools,
-			byMangled:   byMangled,
-			llmTools:    llmTools,
+			function:    AgentFunctionID,
+			tools:       deps.Tools,
+			runtime:     deps.Runtime,
+			toolTimeout: toolTimeout,
 			model:       input.GetStr("model"),
 			temp:        optionalTemperature(input),
 			maxIters:    clampIterations(input.GetInt("maxIterations")),
 			wfID:             execInfo.WorkflowID,
 			execID:           execInfo.ExecID,
 			environment:      execInfo.Environment,
-			usage:            usage,
+			schemaID:         execInfo.SchemaID,
+			onBudgetExceeded: onBudgetExceeded,
 			maxContextTokens: input.GetInt("maxContextTokens"),
 			contextStrategy:  contextStrategyOrDefault(input.GetStr("contextStrategy")),
 			outputSchema:     parseOutputSchema(input),
+			checkpoints:      execInfo.Checkpoints,
+			checkpoint:       execInfo.Checkpoint,
 		}
 
-		messages := make([]llm.Message, 0, 4)
-		if systemPrompt := input.GetStr("systemPrompt"); systemPrompt != "" {
-			messages = append(messages, llm.Message{Role: llm.RoleSystem, Content: systemPrompt})
-		}
-		messages = append(messages, llm.Message{Role: llm.RoleUser, Content: userInput})
-
 		// Provider resolution and the reasoning loop run in their own goroutine and report back
 		// via Finish so the WorkflowFunc pool worker is freed immediately (mirrors ai/chat).
 		// Resolution is here too because per-context provider keys (ADR-0031) may hit the secret
-		// store. The provider is resolved ONCE and reused across the loop (stable within a run).
+		// store, and loading the session and listing the tools of MCP servers are I/O too. The
+		// provider is resolved ONCE and reused across the loop (stable within a run).
+		// The loop's context is cancelled when the engine abandons the execution (workflow
+		// cancelled, node timed out) or when the agent's own timeout elapses.
 		go func() {
-			ctx, cancel := context.WithTimeout(context.Background(), agentTimeout)
+			ctx, cancel := context.WithTimeout(execInfo.Ctx(), timeout)
 			defer cancel()
+			executor.meter = newSpendMeter(ctx, deps.Usage, deps.Pricing, deps.Ledger, execInfo, limit)
+			executor.usage = executor.meter
+			executor.stream = openNodeStream(deps.Outputs, execInfo)
+			finish := func(out workflow.FunctionOutput) {
+				execInfo.Finish(out)
+				executor.stream.finish(out)
+			}
 
-			provider, err := resolveProvider(ctx, providers, execInfo.Environment, providerName)
+			provider, err := resolveProvider(ctx, deps.Providers, execInfo.Environment, providerName)
 			if err != nil {
 				log.Error().Err(err).Str("provider", providerName).Msg("ai/agent provider resolution failed")
-				execInfo.Finish(errorOutput(fmt.Sprintf("ai/agent: provider resolution failed: %v", err)))
+				finish(errorOutput(fmt.Sprintf("ai/agent: provider resolution failed: %v", err)))
 				return
 			}
 			executor.provider = provider
+			executor.llmTools, executor.byMangled = buildTools(deps.Tools.ListTools(ctx, execInfo), allowedToolSet(input), deps.Runtime != nil)
 
-			execInfo.Finish(executor.run(ctx, messages))
+			if sess != nil {
+				if err := sess.load(ctx); err != nil {
+					finish(errorOutput(fmt.Sprintf("ai/agent: %v", err)))
+					return
+				}
+			}
+			messages := sess.conversation(input.GetStr("systemPrompt"), userInput)
+			if retrieval != nil {
+				embedder := provider
+				if name := input.GetStr("embeddingProvider"); name != "" {
+					if embedder, err = resolveProvider(ctx, deps.Providers, execInfo.Environment, name); err != nil {
+						finish(errorOutput(fmt.Sprintf("ai/agent: embedding provider resolution failed: %v", err)))
+						return
+					}
+				}
+				if messages, err = executor.retrieveContext(ctx, retrieval, embedder, messages, userInput); err != nil {
+					finish(errorOutput(fmt.Sprintf("ai/agent: retrieval failed: %v", err)))
+					return
+				}
+			}
+			out := executor.meter.withRouting(executor.run(ctx, messages))
+			sess.record(ctx, executor.summarizer(), userInput, out)
+			finish(out)
 		}()
 
 		return workflow.NewFunctionResultAsync(), nil
@@ -125,9 +210,14 @@ func makeAgentFunction(providers llm.Registry, tools ToolRegistry, usage UsageRe
 
 // agentExecutor holds the immutable per-run parameters for the reasoning loop.
 type agentExecutor struct {
+	// function is the id of the node running the loop (ai/agent, ai/orchestrate); it labels usage
+	// metrics and prefixes errors.
+	function    string
 	provider    llm.Provider
 	tools       ToolRegistry
-	byMangled   map[string]string // mangled tool name -> real function id
+	runtime     ExecRuntime
+	toolTimeout time.Duration
+	byMangled   map[string]ToolDescriptor // mangled tool name -> tool
 	llmTools    []llm.Tool
 	model       string
 	temp        *float32
@@ -135,126 +225,218 @@ type agentExecutor struct {
 	wfID             workflow.ID
 	execID           workflow.ExecID
 	environment      string
+	schemaID         string
 	usage            UsageRecorder
+	meter            *spendMeter
+	onBudgetExceeded string
 	maxContextTokens int
 	contextStrategy  string
 	outputSchema     []workflow.ParameterSchema
+	// retrieved records the retrieveFrom lookup made before the loop, if any.
+	retrieved *retrievedStep
+	// checkpoints are the iterations journaled by an interrupted run of this attempt.
+	checkpoints []map[string]any
+	// checkpoint journals a completed iteration; nil when the execution cannot be checkpointed.
+	checkpoint func(map[string]any)
+	// stream receives the model's text as it is generated; nil when no output stream is wired.
+	stream *nodeStream
+	// maxChildren caps the child workflows an orchestrator starts; children counts those started.
+	maxChildren int
+	children    int
+	// fatal ends the run once the current tool calls return; it is set when the namespace's
+	// concurrency quota refused a child workflow.
+	fatal *workflow.FunctionOutput
 }
 
 // run drives the reasoning loop until a final answer, an error, or the iteration
-// limit. It returns exactly one FunctionOutput; the caller calls Finish once.
+// limit. It returns exactly one FunctionOutput; the caller calls Finish once. Every
+// iteration that ends in tool calls is journaled, and a replayed run resumes after the
+// last journaled iteration instead of repeating its model and tool calls.
 func (e *agentExecutor) run(ctx context.Context, messages []llm.Message) workflow.FunctionOutput {
-	totalUsage := llm.Usage{}
-	steps := make([]map[string]any, 0)
-
-	for i := 0; i < e.maxIters; i++ {
-		// Bound the growing transcript to the configured token budget (ADR-0028).
-		if trimmed, step := e.applyContextPolicy(ctx, messages); step != nil {
-			messages = trimmed
-			steps = append(steps, step)
-		}
+	state, err := e.resume(messages)
+	if err != nil {
+		return errorOutput(err.Error())
+	}
+	e.meter.restore(state.cost, state.usage.TotalTokens)
+	e.meter.addChildCost(state.childCost)
+	e.children = countChildren(state.steps)
+	if e.retrieved != nil {
+		state.steps = append([]map[string]any{e.retrieved.step}, state.steps...)
+		addUsage(&state.usage, e.retrieved.usage)
+	}
 
-		resp, err := e.provider.Chat(ctx, llm.ChatRequest{
-			Model:       e.model,
-			Messages:    messages,
-			Tools:       e.llmTools,
-			Temperature: e.temp,
-			ToolChoice:  "auto",
-		})
-		if err != nil {
-			e.usage.RecordCall(AgentFunctionID, e.provider.Name(), e.model, "error")
-			log.Error().Err(err).Str("provider", e.provider.Name()).Msg("ai/agent completion failed")
-			return errorOutput(fmt.Sprintf("ai/agent: completion failed: %v", err))
+	for ; state.iteration < e.maxIters; state.iteration++ {
+		if err := ctx.Err(); err != nil {
+			return e.errorf("stopped: %v", err)
+		}
+		if budgetErr := e.meter.Check(); budgetErr != nil {
+			return e.budgetExceeded(budgetErr, state)
 		}
-		e.usage.RecordCall(AgentFunctionID, e.provider.Name(), e.model, "success")
-		e.usage.RecordUsage(AgentFunctionID, e.provider.Name(), e.model, resp.Usage)
+		cp := agentCheckpoint{Iteration: state.iteration}
+		costBefore, childCostBefore := e.meter.Cost(), e.meter.ChildCost()
+
+		var resp llm.ChatResponse
+		var execIDs []string
+		if d := state.decision; d != nil {
+			// The run was interrupted while this iteration's async tool calls ran: repeat the
+			// journaled decision instead of asking the model again.
+			state.decision = nil
+			if d.Context != nil {
+				state.messages = applyContextCut(state.messages, *d.Context)
+			}
+			state.steps = append(state.steps, d.Steps...)
+			cp.Context, cp.Steps = d.Context, append(cp.Steps, d.Steps...)
+			resp = llm.ChatResponse{Message: d.Messages[0], Usage: d.Usage}
+			execIDs = d.ExecIDs
+			e.meter.restore(d.CostUSD, d.Usage.TotalTokens)
+		} else {
+			// Bound the growing transcript to the configured token budget (ADR-0028).
+			if trimmed, step, cut := e.applyContextPolicy(ctx, state.messages); step != nil {
+				state.messages = trimmed
+				state.steps = append(state.steps, step)
+				cp.Context = cut
+				cp.Steps = append(cp.Steps, step)
+			}
 
-		totalUsage.PromptTokens += resp.Usage.PromptTokens
-		totalUsage.CompletionTokens += resp.Usage.CompletionTokens
-		totalUsage.TotalTokens += resp.Usage.TotalTokens
+			resp, err = complete(ctx, e.provider, llm.ChatRequest{
+				Model:       e.model,
+				Messages:    state.messages,
+				Tools:       e.llmTools,
+				Temperature: e.temp,
+				ToolChoice:  "auto",
+			}, e.stream)
+			if err != nil {
+				e.usage.RecordCall(e.function, e.provider.Name(), e.model, "error")
+				log.Error().Err(err).Str("provider", e.provider.Name()).Str("function", e.function).Msg("ai completion failed")
+				return e.errorf("completion failed: %v", err)
+			}
+			recordCompletion(e.usage, e.function, e.provider, e.model, resp)
+		}
 
-		messages = append(messages, resp.Message)
+		addUsage(&state.usage, resp.Usage)
+		state.messages = append(state.messages, resp.Message)
+		// A call that takes spend over a budget ends the loop before any of its tool calls run.
+		if budgetErr := e.meter.Exceeded(); budgetErr != nil {
+			return e.budgetExceeded(budgetErr, state)
+		}
 
 		if len(resp.Message.ToolCalls) == 0 {
 			// Structured output (ADR-0030): coerce the final answer into the requested schema.
 			if len(e.outputSchema) > 0 {
-				obj, u, serr := structuredOutput(ctx, e.provider, e.model, messages, e.outputSchema, e.usage, AgentFunctionID)
+				obj, u, serr := structuredOutput(ctx, e.provider, e.model, state.messages, e.outputSchema, e.usage, e.function)
 				if serr != nil {
-					return errorOutput(fmt.Sprintf("ai/agent: structured output failed: %v", serr))
+					return e.errorf("structured output failed: %v", serr)
+				}
+				addUsage(&state.usage, u)
+				if budgetErr := e.meter.Exceeded(); budgetErr != nil {
+					return e.budgetExceeded(budgetErr, state)
 				}
-				totalUsage.PromptTokens += u.PromptTokens
-				totalUsage.CompletionTokens += u.CompletionTokens
-				totalUsage.TotalTokens += u.TotalTokens
-				return successOutputData(obj, totalUsage, steps)
+				return successOutputData(obj, state.usage, e.meter.Cost(), state.steps)
 			}
-			return successOutput(resp.Message.Content, totalUsage, steps)
+			return successOutput(resp.Message.Content, state.usage, e.meter.Cost(), state.steps)
 		}
 
-		for _, tc := range resp.Message.ToolCalls {
-			toolMsg, step := e.executeToolCall(tc)
-			messages = append(messages, toolMsg)
-			steps = append(steps, step)
+		cp.Messages = append(cp.Messages, resp.Message)
+		cp.Usage = resp.Usage
+		if execIDs == nil {
+			if execIDs = e.allocateExecIDs(resp.Message.ToolCalls); execIDs != nil {
+				e.saveCheckpoint(agentCheckpoint{
+					Iteration: cp.Iteration,
+					Context:   cp.Context,
+					Messages:  cp.Messages,
+					Steps:     cp.Steps,
+					Usage:     cp.Usage,
+					CostUSD:   e.meter.Cost() - costBefore,
+					Pending:   true,
+					ExecIDs:   execIDs,
+				})
+			}
+		}
+		for i, tc := range resp.Message.ToolCalls {
+			toolMsg, step := e.executeToolCall(ctx, tc, e.decidedExecID(execIDs, i))
+			state.messages = append(state.messages, toolMsg)
+			state.steps = append(state.steps, step)
+			cp.Messages = append(cp.Messages, toolMsg)
+			cp.Steps = append(cp.Steps, step)
+		}
+		// Calls cut short by the end of the run did not complete; a resumed run repeats them.
+		if err := ctx.Err(); err != nil {
+			return e.errorf("stopped: %v", err)
 		}
+		if e.fatal != nil {
+			return *e.fatal
+		}
+		cp.CostUSD = e.meter.Cost() - costBefore
+		cp.ChildCostUSD = e.meter.ChildCost() - childCostBefore
+		e.saveCheckpoint(cp)
+		e.stream.step(state.iteration, toolNames(resp.Message.ToolCalls))
 	}
 
-	return errorOutput("ai/agent: max iterations reached")
+	return e.errorf("max iterations reached")
+}
+
+// budgetExceeded ends the loop on an exceeded budget. With onBudgetExceeded "stop" it returns the
+// model's last answer as a success; otherwise, and always with an outputSchema (whose object cannot
+// be produced without another call), it fails the node with a budget_exceeded error.
+func (e *agentExecutor) budgetExceeded(err *llm.BudgetExceededError, state *agentState) workflow.FunctionOutput {
+	if e.onBudgetExceeded != budgetActionStop || len(e.outputSchema) > 0 {
+		return budgetErrorOutput(err, state.usage, e.meter.Cost())
+	}
+	out := successOutput(lastAssistantContent(state.messages), state.usage, e.meter.Cost(), state.steps)
+	out.Data["stopReason"] = llm.ErrorTypeBudgetExceeded
+	out.Data["budget"] = err.Data()
+	return out
+}
+
+// lastAssistantContent returns the text of the newest assistant turn that has any.
+func lastAssistantContent(messages []llm.Message) string {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == llm.RoleAssistant && messages[i].Content != "" {
+			return messages[i].Content
+		}
+	}
+	return ""
 }
 
 // applyContextPolicy bounds the running transcript to maxContextTokens (ADR-0028). It returns the
-// trimmed messages and a step record, or (nil, nil) when no trimming was needed.
-func (e *agentExecutor) applyContextPolicy(ctx context.Context, messages []llm.Message) ([]llm.Message, map[string]any) {
+// trimmed messages, a step record, and the cut that was applied, or (nil, nil, nil) when no
+// trimming was needed.
+func (e *agentExecutor) applyContextPolicy(ctx context.Context, messages []llm.Message) ([]llm.Message, map[string]any, *contextCut) {
 	if e.maxContextTokens <= 0 {
-		return nil, nil
+		return nil, nil, nil
 	}
-	kept, dropped := trimContext(messages, e.maxContextTokens)
+	_, dropped := trimContext(messages, e.maxContextTokens)
 	if len(dropped) == 0 {
-		return nil, nil
+		return nil, nil, nil
 	}
 	step := map[string]any{"context": "trimmed", "strategy": e.contextStrategy, "droppedTurns": len(dropped)}
+	cut := &contextCut{Dropped: len(dropped)}
 	if e.contextStrategy == contextStrategySummarize {
-		if summary := e.summarizeDropped(ctx, dropped); summary != "" {
-			h := headLen(messages)
-			summaryTurn := llm.Message{Role: llm.RoleSystem, Content: "Summary of earlier turns: " + summary}
-			out := concatMessages(messages[:h], append([]llm.Message{summaryTurn}, kept[h:]...))
+		if summary := e.summarizer().summarize(ctx, dropped); summary != "" {
+			cut.Summary = summary
 			step["summarized"] = true
-			return out, step
 		}
 	}
-	return kept, step
+	return applyContextCut(messages, *cut), step, cut
 }
 
-// summarizeDropped asks the provider to summarize the dropped turns into one note. Returns "" on
-// error, so the caller falls back to plain drop-oldest.
-func (e *agentExecutor) summarizeDropped(ctx context.Context, dropped []llm.Message) string {
-	var b strings.Builder
-	for _, m := range dropped {
-		fmt.Fprintf(&b, "%s: %s\n", m.Role, m.Content)
-	}
-	resp, err := e.provider.Chat(ctx, llm.ChatRequest{
-		Model: e.model,
-		Messages: []llm.Message{
-			{Role: llm.RoleSystem, Content: "Summarize the following conversation turns concisely, preserving facts, tool results, and decisions."},
-			{Role: llm.RoleUser, Content: b.String()},
-		},
-	})
-	if err != nil {
-		log.Warn().Err(err).Msg("ai/agent context summarization failed; dropping oldest turns instead")
-		return ""
-	}
-	e.usage.RecordCall(AgentFunctionID, e.provider.Name(), e.model, "success")
-	e.usage.RecordUsage(AgentFunctionID, e.provider.Name(), e.model, resp.Usage)
-	return resp.Message.Content
+// summarizer returns the summarizer of the summarize context strategy for this run.
+func (e *agentExecutor) summarizer() *turnSummarizer {
+	return &turnSummarizer{provider: e.provider, model: e.model, usage: e.usage, function: e.function}
 }
 
 // executeToolCall resolves, invokes, and records a single model-requested tool
 // call. It never aborts the run: unknown tools, bad arguments, invocation errors,
 // and tool errors are all fed back to the model as the tool's result so it can
-// recover or report them.
-func (e *agentExecutor) executeToolCall(tc llm.ToolCall) (llm.Message, map[string]any) {
-	realID, known := e.byMangled[tc.Name]
+// recover or report them. Async tools run as child executions through the
+// runtime, under the exec id decided for the call, and block the loop until
+// they complete or time out.
+func (e *agentExecutor) executeToolCall(ctx context.Context, tc llm.ToolCall, execID workflow.ExecID) (llm.Message, map[string]any) {
+	tool, known := e.byMangled[tc.Name]
 	if !known {
 		return e.toolError(tc, tc.Name, nil, fmt.Sprintf("unknown or disallowed tool %q", tc.Name))
 	}
+	realID := tool.FunctionID
 
 	var args map[string]any
 	if len(tc.Arguments) > 0 {
@@ -263,17 +445,28 @@ func (e *agentExecutor) executeToolCall(tc llm.ToolCall) (llm.Message, map[strin
 		}
 	}
 
+	if tool.SchemaID != "" {
+		return e.executeWorkflowToolCall(ctx, tc, tool, args, execID)
+	}
+	if tool.Async {
+		return e.executeAsyncToolCall(ctx, tc, realID, args, execID)
+	}
+
 	nestedInput, err := workflow.NewFunctionInputWith(args)
 	if err != nil {
 		return e.toolError(tc, realID, args, fmt.Sprintf("failed to build tool input: %v", err))
 	}
 
-	result, err := e.tools.InvokeTool(realID, workflow.NewExecutionInfo(e.wfID, e.execID, e.environment, nestedInput))
+	toolCtx, cancel := context.WithTimeout(ctx, e.toolTimeout)
+	defer cancel()
+	toolExecInfo := workflow.NewExecutionInfo(e.wfID, e.execID, e.environment, nestedInput)
+	toolExecInfo.SchemaID, toolExecInfo.Context = e.schemaID, toolCtx
+	result, err := e.tools.InvokeTool(realID, toolExecInfo)
 	if err != nil {
 		return e.toolError(tc, realID, args, err.Error())
 	}
 	if result.Async {
-		return e.toolError(tc, realID, args, "tool is asynchronous and not supported by the agent")
+		return e.toolError(tc, realID, args, "tool completed asynchronously but is not listed as an async tool")
 	}
 	if result.Output.Status == workflow.FunctionError {
 		return e.toolError(tc, realID, args, fmt.Sprintf("tool returned an error: %v", result.Output.Data))
@@ -283,6 +476,81 @@ func (e *agentExecutor) executeToolCall(tc llm.ToolCall) (llm.Message, map[strin
 	return toolMessage(tc, result.Output.Data), step
 }
 
+// executeAsyncToolCall runs an async tool as a child execution of this node and waits for its
+// output, the tool timeout, or the end of the run. The step records the child's exec id so the
+// call can be found in the journal.
+func (e *agentExecutor) executeAsyncToolCall(ctx context.Context, tc llm.ToolCall, toolID string, args map[string]any, execID workflow.ExecID) (llm.Message, map[string]any) {
+	output, err := e.invokeAsync(ctx, toolID, args, execID)
+	var msg llm.Message
+	var step map[string]any
+	switch {
+	case err != nil:
+		msg, step = e.toolError(tc, toolID, args, fmt.Sprintf("async tool call failed: %v", err))
+	case output.Status == workflow.FunctionError:
+		msg, step = e.toolError(tc, toolID, args, fmt.Sprintf("tool returned an error: %v", output.Data))
+	default:
+		msg, step = toolMessage(tc, output.Data), map[string]any{"tool": toolID, "arguments": args, "result": output.Data}
+	}
+	step["execId"] = execID.String()
+	return msg, step
+}
+
+// invokeAsync runs function as the child execution execID of this node and waits for its output.
+func (e *agentExecutor) invokeAsync(ctx context.Context, functionID string, input map[string]any, execID workflow.ExecID) (workflow.FunctionOutput, error) {
+	call := AsyncCall{
+		Namespace:    workflow.NamespaceOf(e.schemaID),
+		WorkflowID:   e.wfID,
+		ParentExecID: e.execID,
+		ExecID:       execID,
+		FunctionID:   functionID,
+		Input:        input,
+		Timeout:      e.toolTimeout,
+	}
+	ctx, cancel := context.WithTimeout(ctx, e.toolTimeout+asyncToolGrace)
+	defer cancel()
+
+	results, err := e.runtime.InvokeAsync(ctx, call)
+	if err != nil {
+		return workflow.FunctionOutput{}, err
+	}
+	select {
+	case output := <-results:
+		return output, nil
+	case <-ctx.Done():
+		return workflow.FunctionOutput{}, ctx.Err()
+	}
+}
+
+// allocateExecIDs allocates the child exec ids of the async calls among calls, in call order, or
+// returns nil when every call is synchronous.
+func (e *agentExecutor) allocateExecIDs(calls []llm.ToolCall) []string {
+	var execIDs []string
+	for i, tc := range calls {
+		if tool, ok := e.byMangled[tc.Name]; !ok || !tool.Async {
+			continue
+		}
+		if execIDs == nil {
+			execIDs = make([]string, len(calls))
+		}
+		execIDs[i] = workflow.NewExecID(e.execID.Thread()).String()
+	}
+	return execIDs
+}
+
+// decidedExecID returns the exec id decided for the i-th tool call, or a fresh one when the
+// decision has none (a synchronous call).
+func (e *agentExecutor) decidedExecID(execIDs []string, i int) workflow.ExecID {
+	if i < len(execIDs) && execIDs[i] != "" {
+		return workflow.ExecID(execIDs[i])
+	}
+	return workflow.NewExecID(e.execID.Thread())
+}
+
+// errorf builds a terminal error output prefixed with the node's function.
+func (e *agentExecutor) errorf(format string, args ...any) workflow.FunctionOutput {
+	return errorOutput(fmt.Sprintf("ai/"+e.function+": "+format, args...))
+}
+
 // toolError builds the tool-result message and trace step for a failed tool call.
 func (e *agentExecutor) toolError(tc llm.ToolCall, toolID string, args map[string]any, msg string) (llm.Message, map[string]any) {
 	step := map[string]any{"tool": toolID, "error": msg}
@@ -293,18 +561,22 @@ func (e *agentExecutor) toolError(tc llm.ToolCall, toolID string, args map[strin
 }
 
 // buildTools converts tool descriptors into llm.Tool definitions (optionally
-// filtered by an allowlist of real function ids) and the mangled->real id map.
-func buildTools(descriptors []ToolDescriptor, allowed map[string]struct{}) ([]llm.Tool, map[string]string) {
+// filtered by an allowlist of real function ids) and the mangled name -> tool map.
+// Async tools are left out when no runtime can run them.
+func buildTools(descriptors []ToolDescriptor, allowed map[string]struct{}, async bool) ([]llm.Tool, map[string]ToolDescriptor) {
 	tools := make([]llm.Tool, 0, len(descriptors))
-	byMangled := make(map[string]string, len(descriptors))
+	byMangled := make(map[string]ToolDescriptor, len(descriptors))
 	for _, d := range descriptors {
+		if d.Async && !async {
+			continue
+		}
 		if allowed != nil {
 			if _, ok := allowed[d.FunctionID]; !ok {
 				continue
 			}
 		}
 		tools = append(tools, llm.Tool{Name: d.MangledName, Description: d.Description, Parameters: d.Parameters})
-		byMangled[d.MangledName] = d.FunctionID
+		byMangled[d.MangledName] = d
 	}
 	return tools, byMangled
 }
@@ -328,6 +600,16 @@ func allowedToolSet(input *workflow.FunctionInput) map[string]struct{} {
 	return set
 }
 
+// agentTimeout reads the optional timeout input, defaulting to defaultAgentTimeout.
+func agentTimeout(input *workflow.FunctionInput) (time.Duration, error) {
+	return durationInput(input, "timeout", defaultAgentTimeout, ErrAgentInvalidTimeout)
+}
+
+// agentToolTimeout reads the optional toolTimeout input, defaulting to defaultToolTimeout.
+func agentToolTimeout(input *workflow.FunctionInput) (time.Duration, error) {
+	return durationInput(input, "toolTimeout", defaultToolTimeout, ErrAgentInvalidToolTimeout)
+}
+
 // clampIterations applies the default and the hard cap.
 func clampIterations(v int) int {
 	if v <= 0 {
@@ -339,6 +621,15 @@ func clampIterations(v int) int {
 	return v
 }
 
+// toolNames returns the names of the tools the model called.
+func toolNames(calls []llm.ToolCall) []string {
+	names := make([]string, len(calls))
+	for i, tc := range calls {
+		names[i] = tc.Name
+	}
+	return names
+}
+
 // toolMessage builds a RoleTool result message answering a specific tool call.
 func toolMessage(tc llm.ToolCall, data map[string]any) llm.Message {
 	return llm.Message{Role: llm.RoleTool, ToolCallID: tc.ID, Name: tc.Name, Content: marshalToolContent(data)}
@@ -354,24 +645,27 @@ func marshalToolContent(data map[string]any) string {
 }
 
 // successOutput builds the agent's final successful output.
-func successOutput(answer string, usage llm.Usage, steps []map[string]any) workflow.FunctionOutput {
-	return successOutputData(answer, usage, steps)
+func successOutput(answer string, usage llm.Usage, costUSD float64, steps []map[string]any) workflow.FunctionOutput {
+	return successOutputData(answer, usage, costUSD, steps)
 }
 
 // successOutputData builds the agent's final output; output is the answer text or, for structured
 // output (ADR-0030), the validated object.
-func successOutputData(output any, usage llm.Usage, steps []map[string]any) workflow.FunctionOutput {
+func successOutputData(output any, usage llm.Usage, costUSD float64, steps []map[string]any) workflow.FunctionOutput {
 	return workflow.NewFunctionSuccessOutput(map[string]any{
 		"output": output,
-		"usage": map[string]any{
-			"promptTokens":     usage.PromptTokens,
-			"completionTokens": usage.CompletionTokens,
-			"totalTokens":      usage.TotalTokens,
-		},
-		"steps": steps,
+		"usage":  usageData(usage, costUSD),
+		"steps":  steps,
 	})
 }
 
+// addUsage accumulates u into total.
+func addUsage(total *llm.Usage, u llm.Usage) {
+	total.PromptTokens += u.PromptTokens
+	total.CompletionTokens += u.CompletionTokens
+	total.TotalTokens += u.TotalTokens
+}
+
 // errorOutput builds a terminal error output.
 func errorOutput(msg string) workflow.FunctionOutput {
 	return workflow.NewFunctionOutput(workflow.FunctionError, map[string]any{"error": msg})
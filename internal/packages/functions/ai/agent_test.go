@@ -50,7 +50,9 @@ type fakeToolRegistry struct {
 	invoked     []string
 }
 
-func (f *fakeToolRegistry) ListTools() []ToolDescriptor { return f.descriptors }
+func (f *fakeToolRegistry) ListTools(context.Context, *workflow.ExecutionInfo) []ToolDescriptor {
+	return f.descriptors
+}
 
 func (f *fakeToolRegistry) InvokeTool(id string, e *workflow.ExecutionInfo) (workflow.FunctionResult, error) {
 	f.invoked = append(f.invoked, id)
@@ -100,6 +102,12 @@ func registryWith(prov llm.Provider) llm.Registry {
 }
 
 func runAgent(t *testing.T, providers llm.Registry, tools ToolRegistry, input map[string]any) (workflow.FunctionResult, workflow.FunctionOutput) {
+	t.Helper()
+	return runAgentWith(t, providers, tools, input, nil)
+}
+
+// runAgentWith is runAgent with a hook to set up the ExecutionInfo (context, checkpoints) first.
+func runAgentWith(t *testing.T, providers llm.Registry, tools ToolRegistry, input map[string]any, setup func(*workflow.ExecutionInfo)) (workflow.FunctionResult, workflow.FunctionOutput) {
 	t.Helper()
 	fnInput, err := workflow.NewFunctionInputWith(input)
 	require.NoError(t, err)
@@ -107,8 +115,11 @@ func runAgent(t *testing.T, providers llm.Registry, tools ToolRegistry, input ma
 	done := make(chan workflow.FunctionOutput, 1)
 	execInfo := workflow.NewExecutionInfo("wf-1", workflow.NewExecID(1), "", fnInput)
 	execInfo.Finish = func(out workflow.FunctionOutput) { done <- out }
+	if setup != nil {
+		setup(execInfo)
+	}
 
-	res, err := makeAgentFunction(providers, tools, NopUsageRecorder{})(execInfo)
+	res, err := makeAgentFunction(Deps{Providers: providers, Tools: tools, Usage: NopUsageRecorder{}})(execInfo)
 	require.NoError(t, err)
 	if !res.Async {
 		return res, workflow.FunctionOutput{}
@@ -327,6 +338,134 @@ func TestAgent_ProviderErrorDeliveredAsErrorOutput(t *testing.T) {
 	assert.Contains(t, out.Data["error"], "boom")
 }
 
+func TestAgent_InvalidTimeoutReturnsSyncError(t *testing.T) {
+	prov := &scriptedProvider{name: "stub"}
+	res, _ := runAgent(t, registryWith(prov), &fakeToolRegistry{}, map[string]any{"input": "hi", "timeout": "soon"})
+	assert.False(t, res.Async)
+	assert.Equal(t, workflow.FunctionError, res.Output.Status)
+	assert.Zero(t, prov.calls)
+}
+
+func TestAgent_CancelledContextStopsLoop(t *testing.T) {
+	prov := &scriptedProvider{name: "stub", responses: []llm.ChatResponse{finalAnswer("never")}}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, out := runAgentWith(t, registryWith(prov), &fakeToolRegistry{}, map[string]any{"input": "hi"},
+		func(e *workflow.ExecutionInfo) { e.Context = ctx })
+
+	assert.Equal(t, workflow.FunctionError, out.Status)
+	assert.Contains(t, out.Data["error"], "stopped")
+	assert.Zero(t, prov.calls, "no model call after cancellation")
+}
+
+func TestAgent_CheckpointsEachToolIteration(t *testing.T) {
+	prov := &scriptedProvider{
+		name: "stub",
+		responses: []llm.ChatResponse{
+			toolCallResponse("call-1", "fuse__pkg__logic__sum", `{"values":[2,3]}`),
+			finalAnswer("the sum is 5"),
+		},
+	}
+	tools := &fakeToolRegistry{descriptors: []ToolDescriptor{sumDescriptor}}
+
+	var checkpoints []map[string]any
+	_, out := runAgentWith(t, registryWith(prov), tools, map[string]any{"input": "add 2 and 3"},
+		func(e *workflow.ExecutionInfo) {
+			e.Checkpoint = func(data map[string]any) { checkpoints = append(checkpoints, data) }
+		})
+
+	require.Equal(t, workflow.FunctionSuccess, out.Status)
+	require.Len(t, checkpoints, 1, "only the tool-calling iteration is checkpointed")
+	cp, err := decodeCheckpoint(checkpoints[0])
+	require.NoError(t, err)
+	assert.Equal(t, 0, cp.Iteration)
+	require.Len(t, cp.Messages, 2)
+	assert.Equal(t, llm.RoleAssistant, cp.Messages[0].Role)
+	assert.Equal(t, "call-1", cp.Messages[1].ToolCallID)
+	assert.Equal(t, 4, cp.Usage.TotalTokens)
+	require.Len(t, cp.Steps, 1)
+	assert.Equal(t, "fuse/pkg/logic/sum", cp.Steps[0]["tool"])
+}
+
+func TestAgent_ResumesFromCheckpointsWithoutRepeatingWork(t *testing.T) {
+	first := &scriptedProvider{
+		name:      "stub",
+		responses: []llm.ChatResponse{toolCallResponse("call-1", "fuse__pkg__logic__sum", `{"values":[2,3]}`)},
+		err:       errors.New("node crashed"),
+		errOnCall: 1,
+	}
+	var checkpoints []map[string]any
+	runAgentWith(t, registryWith(first), &fakeToolRegistry{descriptors: []ToolDescriptor{sumDescriptor}},
+		map[string]any{"input": "add 2 and 3"},
+		func(e *workflow.ExecutionInfo) {
+			e.Checkpoint = func(data map[string]any) { checkpoints = append(checkpoints, data) }
+		})
+	require.Len(t, checkpoints, 1)
+
+	replayed := &scriptedProvider{name: "stub", responses: []llm.ChatResponse{finalAnswer("the sum is 5")}}
+	tools := &fakeToolRegistry{descriptors: []ToolDescriptor{sumDescriptor}}
+	var more []map[string]any
+	_, out := runAgentWith(t, registryWith(replayed), tools, map[string]any{"input": "add 2 and 3"},
+		func(e *workflow.ExecutionInfo) {
+			e.Checkpoints = checkpoints
+			e.Checkpoint = func(data map[string]any) { more = append(more, data) }
+		})
+
+	require.Equal(t, workflow.FunctionSuccess, out.Status)
+	assert.Equal(t, "the sum is 5", out.Data["output"])
+	assert.Empty(t, tools.invoked, "the journaled tool call is not repeated")
+	assert.Empty(t, more)
+
+	// only the next iteration is issued, with the transcript rebuilt from the journal
+	require.Len(t, replayed.requests, 1)
+	msgs := replayed.requests[0].Messages
+	require.Len(t, msgs, 3)
+	assert.Equal(t, llm.RoleUser, msgs[0].Role)
+	assert.Equal(t, "call-1", msgs[1].ToolCalls[0].ID)
+	assert.Equal(t, "call-1", msgs[2].ToolCallID)
+
+	usage, ok := out.Data["usage"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, 6, usage["totalTokens"], "journaled usage is carried over")
+	steps, ok := out.Data["steps"].([]map[string]any)
+	require.True(t, ok)
+	require.Len(t, steps, 1)
+	assert.Equal(t, "fuse/pkg/logic/sum", steps[0]["tool"])
+}
+
+func TestAgent_UnreadableCheckpointFailsAttempt(t *testing.T) {
+	prov := &scriptedProvider{name: "stub"}
+	_, out := runAgentWith(t, registryWith(prov), &fakeToolRegistry{}, map[string]any{"input": "hi"},
+		func(e *workflow.ExecutionInfo) {
+			e.Checkpoints = []map[string]any{{"iteration": 3}}
+		})
+
+	assert.Equal(t, workflow.FunctionError, out.Status)
+	assert.Contains(t, out.Data["error"], "cannot resume from checkpoint 0")
+	assert.Zero(t, prov.calls)
+}
+
+func TestAgentTimeout(t *testing.T) {
+	t.Parallel()
+	in := func(v string) *workflow.FunctionInput {
+		fnInput, err := workflow.NewFunctionInputWith(map[string]any{"timeout": v})
+		require.NoError(t, err)
+		return fnInput
+	}
+
+	d, err := agentTimeout(in(""))
+	require.NoError(t, err)
+	assert.Equal(t, defaultAgentTimeout, d)
+
+	d, err = agentTimeout(in("90s"))
+	require.NoError(t, err)
+	assert.Equal(t, 90*time.Second, d)
+
+	_, err = agentTimeout(in("-1m"))
+	assert.ErrorIs(t, err, ErrAgentInvalidTimeout)
+}
+
 func TestClampIterations(t *testing.T) {
 	t.Parallel()
 	assert.Equal(t, defaultMaxIterations, clampIterations(0))
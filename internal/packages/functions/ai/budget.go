@@ -0,0 +1,274 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/open-source-cloud/fuse/pkg/llm"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// budgetActionFail fails the node with a budget_exceeded error (default).
+	budgetActionFail = "fail"
+	// budgetActionStop ends the agent loop and returns the last answer as a success.
+	budgetActionStop = "stop"
+)
+
+// ErrAgentInvalidBudgetAction is returned when onBudgetExceeded is neither fail nor stop.
+var ErrAgentInvalidBudgetAction = errors.New("ai/agent: onBudgetExceeded must be 'fail' or 'stop'")
+
+// BudgetScope identifies what an LLM call is charged to: its workflow execution and its schema's
+// daily total in an environment, together with the schema's limits.
+type BudgetScope struct {
+	WorkflowID  string
+	SchemaID    string
+	Environment string
+	Budget      *workflow.LLMBudget
+}
+
+// BudgetLedger enforces schema LLM budgets against spend shared by every node of the cluster. It is
+// a port so this package stays free of repositories; the engine injects a repository-backed ledger.
+type BudgetLedger interface {
+	// Check returns a *llm.BudgetExceededError when a budget of the scope is already exceeded.
+	Check(ctx context.Context, scope BudgetScope) error
+	// Charge adds the spend of one call to the scope's totals and returns a *llm.BudgetExceededError
+	// when that takes them over a budget.
+	Charge(ctx context.Context, scope BudgetScope, costUSD float64, tokens int) error
+}
+
+// spendMeter prices the completions of one ai node execution and enforces its budgets. It wraps
+// the node's UsageRecorder, so every call path that records usage (completions, structured output,
+// context summarization) is priced, recorded as fuse_llm_cost_usd_total and charged. Ledger
+// failures other than an exceeded budget are logged and do not block the node.
+type spendMeter struct {
+	UsageRecorder
+	ctx     context.Context
+	pricing llm.Pricing
+	ledger  BudgetLedger
+	scope   BudgetScope
+	limit   workflow.BudgetLimit
+
+	mu        sync.Mutex
+	cost      float64
+	childCost float64
+	tokens    int
+	exceeded  *llm.BudgetExceededError
+	// served is the route of the last completion a routing policy served; fallbacks collects the
+	// routes every such completion fell back from.
+	served    *llm.Routing
+	fallbacks []llm.FallbackAttempt
+}
+
+func newSpendMeter(ctx context.Context, usage UsageRecorder, pricing llm.Pricing, ledger BudgetLedger, execInfo *workflow.ExecutionInfo, limit workflow.BudgetLimit) *spendMeter {
+	return &spendMeter{
+		UsageRecorder: usage,
+		ctx:           ctx,
+		pricing:       pricing,
+		ledger:        ledger,
+		scope: BudgetScope{
+			WorkflowID:  execInfo.WorkflowID.String(),
+			SchemaID:    execInfo.SchemaID,
+			Environment: execInfo.Environment,
+			Budget:      execInfo.LLMBudget,
+		},
+		limit: limit,
+	}
+}
+
+// RecordUsage records the tokens of a completion, prices it, and charges it to the node's and the
+// schema's budgets.
+func (m *spendMeter) RecordUsage(function, provider, model string, u llm.Usage) {
+	m.UsageRecorder.RecordUsage(function, provider, model, u)
+	cost := m.pricing.Cost(provider, model, u)
+	m.RecordCost(function, provider, model, cost)
+
+	m.mu.Lock()
+	m.cost += cost
+	m.tokens += u.TotalTokens
+	nodeErr := nodeBudgetError(m.limit, m.cost+m.childCost, m.tokens)
+	m.mu.Unlock()
+	m.exceed(nodeErr)
+
+	if m.ledger != nil && m.scope.Budget != nil {
+		m.exceed(m.ledgerResult(m.ledger.Charge(m.ctx, m.scope, cost, u.TotalTokens)))
+	}
+}
+
+// RecordRouting keeps how a routing policy served one of the node's completions.
+func (m *spendMeter) RecordRouting(r *llm.Routing) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.served = r
+	m.fallbacks = append(m.fallbacks, r.Fallbacks...)
+}
+
+// withRouting adds to the usage of a node's output the provider and model that served its last
+// completion and the fallbacks its completions took, when they were served by a routing policy.
+func (m *spendMeter) withRouting(out workflow.FunctionOutput) workflow.FunctionOutput {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	usage, ok := out.Data["usage"].(map[string]any)
+	if m.served == nil || !ok {
+		return out
+	}
+	fallbacks := make([]map[string]any, len(m.fallbacks))
+	for i, f := range m.fallbacks {
+		fallbacks[i] = map[string]any{"provider": f.Provider, "model": f.Model, "reason": f.Reason}
+	}
+	usage["policy"] = m.served.Policy
+	usage["provider"] = m.served.Provider
+	usage["model"] = m.served.Model
+	usage["fallbacks"] = fallbacks
+	return out
+}
+
+// restore accounts spend made by an interrupted earlier run of this execution. It was already
+// recorded and charged, so it only counts towards the node's limits.
+func (m *spendMeter) restore(cost float64, tokens int) {
+	m.mu.Lock()
+	m.cost += cost
+	m.tokens += tokens
+	nodeErr := nodeBudgetError(m.limit, m.cost+m.childCost, m.tokens)
+	m.mu.Unlock()
+	m.exceed(nodeErr)
+}
+
+// addChildCost accounts the LLM cost of a child workflow the node ran. The child's own nodes
+// recorded and charged it; it only counts towards this node's maxCostUSD.
+func (m *spendMeter) addChildCost(cost float64) {
+	m.mu.Lock()
+	m.childCost += cost
+	nodeErr := nodeBudgetError(m.limit, m.cost+m.childCost, m.tokens)
+	m.mu.Unlock()
+	m.exceed(nodeErr)
+}
+
+// Check returns the exceeded budget, if any, that forbids another LLM call.
+func (m *spendMeter) Check() *llm.BudgetExceededError {
+	if err := m.Exceeded(); err != nil {
+		return err
+	}
+	if m.ledger != nil && m.scope.Budget != nil {
+		m.exceed(m.ledgerResult(m.ledger.Check(m.ctx, m.scope)))
+	}
+	return m.Exceeded()
+}
+
+// Exceeded returns the first budget the recorded spend went over, or nil.
+func (m *spendMeter) Exceeded() *llm.BudgetExceededError {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.exceeded
+}
+
+// Cost returns the USD cost of the execution's calls so far.
+func (m *spendMeter) Cost() float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.cost
+}
+
+// ChildCost returns the USD cost reported by the node's child workflows so far.
+func (m *spendMeter) ChildCost() float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.childCost
+}
+
+func (m *spendMeter) exceed(err *llm.BudgetExceededError) {
+	if err == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.exceeded == nil {
+		m.exceeded = err
+	}
+}
+
+// ledgerResult extracts an exceeded budget from a ledger result; other errors are logged.
+func (m *spendMeter) ledgerResult(err error) *llm.BudgetExceededError {
+	if err == nil {
+		return nil
+	}
+	var budgetErr *llm.BudgetExceededError
+	if errors.As(err, &budgetErr) {
+		return budgetErr
+	}
+	log.Warn().Err(err).Str("workflowID", m.scope.WorkflowID).Msg("ai: llm budget ledger unavailable; budget not enforced for this call")
+	return nil
+}
+
+// nodeBudgetError reports whether spend went over a node limit; a zero limit is not enforced.
+func nodeBudgetError(limit workflow.BudgetLimit, cost float64, tokens int) *llm.BudgetExceededError {
+	return limitError(llm.BudgetScopeNode, limit, cost, tokens)
+}
+
+// limitError reports whether spend went over limit, checking cost before tokens.
+func limitError(scope string, limit workflow.BudgetLimit, cost float64, tokens int) *llm.BudgetExceededError {
+	if limit.MaxCostUSD > 0 && cost > limit.MaxCostUSD {
+		return &llm.BudgetExceededError{Scope: scope, Resource: llm.BudgetResourceCost, Limit: limit.MaxCostUSD, Spent: cost}
+	}
+	if limit.MaxTokens > 0 && tokens > limit.MaxTokens {
+		return &llm.BudgetExceededError{Scope: scope, Resource: llm.BudgetResourceTokens, Limit: float64(limit.MaxTokens), Spent: float64(tokens)}
+	}
+	return nil
+}
+
+// LimitError reports whether spend went over a schema budget limit; the engine's ledger uses it so
+// node and schema budgets are exceeded by the same rule.
+func LimitError(scope string, limit *workflow.BudgetLimit, cost float64, tokens int) error {
+	if limit == nil {
+		return nil
+	}
+	if err := limitError(scope, *limit, cost, tokens); err != nil {
+		return err
+	}
+	return nil
+}
+
+// nodeBudgetLimit reads the node's maxCostUSD and maxTokens inputs.
+func nodeBudgetLimit(input *workflow.FunctionInput) (workflow.BudgetLimit, error) {
+	limit := workflow.BudgetLimit{MaxTokens: input.GetInt("maxTokens")}
+	switch v := input.Get("maxCostUSD").(type) {
+	case nil:
+	case float64:
+		limit.MaxCostUSD = v
+	case float32:
+		limit.MaxCostUSD = float64(v)
+	case int:
+		limit.MaxCostUSD = float64(v)
+	default:
+		return limit, fmt.Errorf("ai: maxCostUSD must be a number, got %T", v)
+	}
+	if limit.MaxCostUSD < 0 || limit.MaxTokens < 0 {
+		return limit, errors.New("ai: maxCostUSD and maxTokens must not be negative")
+	}
+	return limit, nil
+}
+
+// budgetAction reads the agent's onBudgetExceeded input, defaulting to fail.
+func budgetAction(input *workflow.FunctionInput) (string, error) {
+	switch action := input.GetStr("onBudgetExceeded"); action {
+	case "":
+		return budgetActionFail, nil
+	case budgetActionFail, budgetActionStop:
+		return action, nil
+	default:
+		return "", fmt.Errorf("%w: got %q", ErrAgentInvalidBudgetAction, action)
+	}
+}
+
+// budgetErrorOutput fails the node with a typed error that onError edges can match on errorType.
+func budgetErrorOutput(err *llm.BudgetExceededError, usage llm.Usage, costUSD float64) workflow.FunctionOutput {
+	return workflow.NewFunctionOutput(workflow.FunctionError, map[string]any{
+		"error":     err.Error(),
+		"errorType": llm.ErrorTypeBudgetExceeded,
+		"budget":    err.Data(),
+		"usage":     usageData(usage, costUSD),
+	})
+}
@@ -0,0 +1,197 @@
+package ai
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/open-source-cloud/fuse/pkg/llm"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubPricing prices the "stub" provider at $1 per million input and $2 per million output tokens.
+var stubPricing = llm.Pricing{"stub/*": {InputPerMillion: 1, OutputPerMillion: 2}}
+
+// fakeLedger reports a fixed Check result and records charges.
+type fakeLedger struct {
+	checkErr error
+	charged  []float64
+	scopes   []BudgetScope
+}
+
+func (f *fakeLedger) Check(context.Context, BudgetScope) error { return f.checkErr }
+
+func (f *fakeLedger) Charge(_ context.Context, scope BudgetScope, costUSD float64, _ int) error {
+	f.charged = append(f.charged, costUSD)
+	f.scopes = append(f.scopes, scope)
+	return nil
+}
+
+// runMetered runs fn (chat or agent, built with pricing and a ledger) and waits for its output.
+func runMetered(t *testing.T, fn workflow.Function, input map[string]any, setup func(*workflow.ExecutionInfo)) workflow.FunctionOutput {
+	t.Helper()
+	fnInput, err := workflow.NewFunctionInputWith(input)
+	require.NoError(t, err)
+	done := make(chan workflow.FunctionOutput, 1)
+	execInfo := workflow.NewExecutionInfo("wf-1", workflow.NewExecID(1), "prod", fnInput)
+	execInfo.Finish = func(out workflow.FunctionOutput) { done <- out }
+	if setup != nil {
+		setup(execInfo)
+	}
+
+	res, err := fn(execInfo)
+	require.NoError(t, err)
+	require.True(t, res.Async, "unexpected sync result: %v", res.Output.Data)
+	select {
+	case out := <-done:
+		return out
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for async Finish")
+		return workflow.FunctionOutput{}
+	}
+}
+
+func TestChat_PricesUsageAndRecordsCost(t *testing.T) {
+	prov := &stubProvider{name: "stub", resp: llm.ChatResponse{
+		Message: llm.Message{Role: llm.RoleAssistant, Content: "hi"},
+		Usage:   llm.Usage{PromptTokens: 1000, CompletionTokens: 500, TotalTokens: 1500},
+	}}
+	rec := &fakeUsageRecorder{}
+	ledger := &fakeLedger{}
+	budget := &workflow.LLMBudget{Daily: &workflow.BudgetLimit{MaxCostUSD: 10}}
+
+	out := runMetered(t, makeChatFunction(Deps{Providers: registryWith(prov), Usage: rec, Pricing: stubPricing, Ledger: ledger}), map[string]any{"input": "hello"},
+		func(e *workflow.ExecutionInfo) {
+			e.SchemaID = "orders"
+			e.LLMBudget = budget
+		})
+
+	require.Equal(t, workflow.FunctionSuccess, out.Status)
+	usage := out.Data["usage"].(map[string]any)
+	assert.InDelta(t, 0.002, usage["costUSD"], 1e-12)
+	assert.Len(t, rec.cost, 1)
+	assert.InDelta(t, 0.002, rec.cost[0], 1e-12)
+	require.Len(t, ledger.scopes, 1)
+	assert.Equal(t, BudgetScope{WorkflowID: "wf-1", SchemaID: "orders", Environment: "prod", Budget: budget}, ledger.scopes[0])
+}
+
+func TestChat_FailsWhenCallExceedsNodeBudget(t *testing.T) {
+	prov := &stubProvider{name: "stub", resp: llm.ChatResponse{
+		Message: llm.Message{Role: llm.RoleAssistant, Content: "a long answer"},
+		Usage:   llm.Usage{PromptTokens: 10, CompletionTokens: 90, TotalTokens: 100},
+	}}
+
+	out := runMetered(t, makeChatFunction(Deps{Providers: registryWith(prov), Usage: NopUsageRecorder{}}),
+		map[string]any{"input": "hello", "maxTokens": 50}, nil)
+
+	require.Equal(t, workflow.FunctionError, out.Status)
+	assert.Equal(t, llm.ErrorTypeBudgetExceeded, out.Data["errorType"])
+	assert.Equal(t, map[string]any{"scope": "node", "resource": "tokens", "limit": 50.0, "spent": 100.0}, out.Data["budget"])
+}
+
+func TestChat_SchemaBudgetExceededSkipsCall(t *testing.T) {
+	prov := &stubProvider{name: "stub", resp: finalAnswer("unused")}
+	ledger := &fakeLedger{checkErr: &llm.BudgetExceededError{Scope: llm.BudgetScopeDaily, Resource: llm.BudgetResourceCost, Limit: 5, Spent: 5.5}}
+
+	out := runMetered(t, makeChatFunction(Deps{Providers: registryWith(prov), Usage: NopUsageRecorder{}, Pricing: stubPricing, Ledger: ledger}), map[string]any{"input": "hello"},
+		func(e *workflow.ExecutionInfo) {
+			e.LLMBudget = &workflow.LLMBudget{Daily: &workflow.BudgetLimit{MaxCostUSD: 5}}
+		})
+
+	require.Equal(t, workflow.FunctionError, out.Status)
+	assert.Equal(t, llm.ErrorTypeBudgetExceeded, out.Data["errorType"])
+	assert.Empty(t, prov.last.Messages, "the provider must not be called")
+}
+
+func TestChat_RejectsInvalidBudgetInput(t *testing.T) {
+	fnInput, err := workflow.NewFunctionInputWith(map[string]any{"input": "hello", "maxCostUSD": "lots"})
+	require.NoError(t, err)
+	res, err := makeChatFunction(Deps{Providers: registryWith(&stubProvider{name: "stub"}), Usage: NopUsageRecorder{}})(
+		workflow.NewExecutionInfo("wf-1", "exec-1", "", fnInput))
+	require.NoError(t, err)
+	assert.Equal(t, workflow.FunctionError, res.Output.Status)
+}
+
+func TestAgent_BudgetExceededFailsByDefault(t *testing.T) {
+	prov := &scriptedProvider{name: "stub", responses: []llm.ChatResponse{
+		toolCallResponse("c1", sumDescriptor.MangledName, `{"values":[1,2]}`),
+		finalAnswer("done"),
+	}}
+	tools := &fakeToolRegistry{descriptors: []ToolDescriptor{sumDescriptor}}
+
+	out := runMetered(t, makeAgentFunction(Deps{Providers: registryWith(prov), Tools: tools, Usage: NopUsageRecorder{}}),
+		map[string]any{"input": "add", "maxTokens": 3}, nil)
+
+	require.Equal(t, workflow.FunctionError, out.Status)
+	assert.Equal(t, llm.ErrorTypeBudgetExceeded, out.Data["errorType"])
+	assert.Empty(t, tools.invoked, "tool calls of the call that went over budget must not run")
+	assert.Equal(t, 1, prov.calls)
+}
+
+func TestAgent_BudgetExceededStopReturnsLastAnswer(t *testing.T) {
+	first := toolCallResponse("c1", sumDescriptor.MangledName, `{"values":[1,2]}`)
+	first.Message.Content = "Let me add those."
+	prov := &scriptedProvider{name: "stub", responses: []llm.ChatResponse{
+		first,
+		toolCallResponse("c2", sumDescriptor.MangledName, `{"values":[3]}`),
+		finalAnswer("unreached"),
+	}}
+	tools := &fakeToolRegistry{descriptors: []ToolDescriptor{sumDescriptor}}
+
+	out := runMetered(t, makeAgentFunction(Deps{Providers: registryWith(prov), Tools: tools, Usage: NopUsageRecorder{}, Pricing: stubPricing}),
+		map[string]any{"input": "add", "maxTokens": 6, "onBudgetExceeded": "stop"}, nil)
+
+	require.Equal(t, workflow.FunctionSuccess, out.Status)
+	assert.Equal(t, "Let me add those.", out.Data["output"])
+	assert.Equal(t, llm.ErrorTypeBudgetExceeded, out.Data["stopReason"])
+	assert.Equal(t, 2, prov.calls)
+	assert.Len(t, tools.invoked, 1)
+	usage := out.Data["usage"].(map[string]any)
+	assert.Equal(t, 8, usage["totalTokens"])
+	assert.InDelta(t, 12e-6, usage["costUSD"], 1e-12)
+}
+
+func TestAgent_SchemaBudgetExceededBeforeFirstCall(t *testing.T) {
+	prov := &scriptedProvider{name: "stub", responses: []llm.ChatResponse{finalAnswer("unused")}}
+	ledger := &fakeLedger{checkErr: &llm.BudgetExceededError{Scope: llm.BudgetScopeExecution, Resource: llm.BudgetResourceTokens, Limit: 10, Spent: 12}}
+
+	out := runMetered(t, makeAgentFunction(Deps{Providers: registryWith(prov), Tools: &fakeToolRegistry{}, Usage: NopUsageRecorder{}, Ledger: ledger}),
+		map[string]any{"input": "go"},
+		func(e *workflow.ExecutionInfo) {
+			e.LLMBudget = &workflow.LLMBudget{PerExecution: &workflow.BudgetLimit{MaxTokens: 10}}
+		})
+
+	require.Equal(t, workflow.FunctionError, out.Status)
+	assert.Equal(t, "execution", out.Data["budget"].(map[string]any)["scope"])
+	assert.Zero(t, prov.calls)
+}
+
+func TestAgent_ResumedSpendCountsTowardsNodeBudget(t *testing.T) {
+	cp, err := encodeCheckpoint(agentCheckpoint{
+		Iteration: 0,
+		Messages:  []llm.Message{{Role: llm.RoleAssistant, Content: "working"}},
+		Usage:     llm.Usage{TotalTokens: 4},
+		CostUSD:   0.5,
+	})
+	require.NoError(t, err)
+	prov := &scriptedProvider{name: "stub", responses: []llm.ChatResponse{finalAnswer("unused")}}
+
+	out := runMetered(t, makeAgentFunction(Deps{Providers: registryWith(prov), Tools: &fakeToolRegistry{}, Usage: NopUsageRecorder{}}),
+		map[string]any{"input": "go", "maxCostUSD": 0.25},
+		func(e *workflow.ExecutionInfo) { e.Checkpoints = []map[string]any{cp} })
+
+	require.Equal(t, workflow.FunctionError, out.Status)
+	assert.Equal(t, llm.ErrorTypeBudgetExceeded, out.Data["errorType"])
+	assert.Zero(t, prov.calls)
+}
+
+func TestAgent_RejectsInvalidBudgetAction(t *testing.T) {
+	fnInput, err := workflow.NewFunctionInputWith(map[string]any{"input": "go", "onBudgetExceeded": "ignore"})
+	require.NoError(t, err)
+	res, err := makeAgentFunction(Deps{Providers: registryWith(&scriptedProvider{name: "stub"}), Tools: &fakeToolRegistry{}, Usage: NopUsageRecorder{}})(
+		workflow.NewExecutionInfo("wf-1", "exec-1", "", fnInput))
+	require.NoError(t, err)
+	assert.Equal(t, workflow.FunctionError, res.Output.Status)
+}
@@ -37,7 +37,7 @@ func ChatFunctionMetadata() workflow.FunctionMetadata {
 					Name:        "provider",
 					Type:        "string",
 					Required:    false,
-					Description: "Provider registry key (e.g. openai, ollama). Defaults to the configured default provider",
+					Description: "Provider registry key (e.g. openai, ollama) or routing policy name. Defaults to the configured default provider",
 				},
 				{
 					Name:        "model",
@@ -63,6 +63,43 @@ func ChatFunctionMetadata() workflow.FunctionMetadata {
 					Required:    false,
 					Description: "Optional list of {name,type,required,description} fields; when set the output is a validated object matching this schema (ADR-0030)",
 				},
+				{
+					Name:        "maxCostUSD",
+					Type:        "float",
+					Required:    false,
+					Description: "Optional USD cap for this node's LLM calls, priced from LLM_PRICING; a call that goes over it fails the node with errorType budget_exceeded",
+				},
+				{
+					Name:        "maxTokens",
+					Type:        "int",
+					Required:    false,
+					Description: "Optional cap on the total tokens of this node's LLM calls",
+				},
+				{
+					Name:        "sessionId",
+					Type:        "string",
+					Required:    false,
+					Description: "Optional conversation id; runs sharing it in a namespace see the earlier turns, and a successful run appends its input and answer",
+				},
+				{
+					Name:        "sessionTTL",
+					Type:        "string",
+					Required:    false,
+					Description: "How long the session is kept after this run as a duration (e.g. 24h); defaults to LLM_SESSION_TTL",
+				},
+				{
+					Name:        "sessionMaxTokens",
+					Type:        "int",
+					Required:    false,
+					Default:     defaultSessionMaxTokens,
+					Description: "Token budget (approximate) of the stored session transcript; older turns are trimmed with contextStrategy",
+				},
+				{
+					Name:        "contextStrategy",
+					Type:        "string",
+					Required:    false,
+					Description: "How an over-budget session transcript is trimmed: 'drop-oldest' (default) or 'summarize' (an extra LLM call summarizes dropped turns)",
+				},
 			},
 			Edges: workflow.InputEdgeMetadata{
 				Count:      0,
@@ -81,7 +118,7 @@ func ChatFunctionMetadata() workflow.FunctionMetadata {
 					Name:        "usage",
 					Type:        "map",
 					Required:    false,
-					Description: "Token usage: promptTokens, completionTokens, totalTokens",
+					Description: "Token usage and its priced cost: promptTokens, completionTokens, totalTokens, costUSD; with a routing policy also policy, provider, model and fallbacks",
 				},
 			},
 			Edges: make([]workflow.OutputEdgeMetadata, 0),
@@ -89,9 +126,10 @@ func ChatFunctionMetadata() workflow.FunctionMetadata {
 	}
 }
 
-// makeChatFunction builds the ai/chat function, closing over the provider registry and the
-// usage recorder (ADR-0029).
-func makeChatFunction(providers llm.Registry, usage UsageRecorder) workflow.Function {
+// makeChatFunction builds the ai/chat function, closing over the provider registry, the usage
+// recorder (ADR-0029), the pricing table and ledger its spend is metered against, the session
+// store (ADR-0028), and the output stream its answer is streamed to as it is generated.
+func makeChatFunction(deps Deps) workflow.Function {
 	return func(execInfo *workflow.ExecutionInfo) (workflow.FunctionResult, error) {
 		input := execInfo.Input
 
@@ -102,77 +140,98 @@ func makeChatFunction(providers llm.Registry, usage UsageRecorder) workflow.Func
 
 		providerName := input.GetStr("provider")
 
-		messages := make([]llm.Message, 0, 2)
-		if systemPrompt := input.GetStr("systemPrompt"); systemPrompt != "" {
-			messages = append(messages, llm.Message{Role: llm.RoleSystem, Content: systemPrompt})
-		}
-		messages = append(messages, llm.Message{Role: llm.RoleUser, Content: userInput})
-
 		req := llm.ChatRequest{
 			Model:       input.GetStr("model"),
-			Messages:    messages,
 			Temperature: optionalTemperature(input),
 		}
 		outputSchema := parseOutputSchema(input)
+		limit, err := nodeBudgetLimit(input)
+		if err != nil {
+			return workflow.NewFunctionResultError(err)
+		}
+		sess, err := sessionFromInput(deps.Sessions, execInfo)
+		if err != nil {
+			return workflow.NewFunctionResultError(err)
+		}
 
 		// Provider resolution and the completion run in their own goroutine and report back via
 		// Finish so the WorkflowFunc pool worker is freed immediately (mirrors logic/timer).
 		// Resolution is in here too because per-context provider keys (ADR-0031) may hit the
-		// secret store, which is I/O we must keep off the pool worker.
+		// secret store, which is I/O we must keep off the pool worker; so is loading the session.
 		go func() {
 			ctx, cancel := context.WithTimeout(context.Background(), chatTimeout)
 			defer cancel()
+			meter := newSpendMeter(ctx, deps.Usage, deps.Pricing, deps.Ledger, execInfo, limit)
+			stream := openNodeStream(deps.Outputs, execInfo)
+			finish := func(out workflow.FunctionOutput) {
+				execInfo.Finish(out)
+				stream.finish(out)
+			}
 
-			provider, err := resolveProvider(ctx, providers, execInfo.Environment, providerName)
+			provider, err := resolveProvider(ctx, deps.Providers, execInfo.Environment, providerName)
 			if err != nil {
 				log.Error().Err(err).Str("provider", providerName).Msg("ai/chat provider resolution failed")
-				execInfo.Finish(workflow.NewFunctionOutput(workflow.FunctionError, map[string]any{"error": err.Error()}))
+				finish(workflow.NewFunctionOutput(workflow.FunctionError, map[string]any{"error": err.Error()}))
 				return
 			}
-
-			// Structured output (ADR-0030): coerce the answer into the requested schema.
-			if len(outputSchema) > 0 {
-				obj, u, serr := structuredOutput(ctx, provider, req.Model, messages, outputSchema, usage, ChatFunctionID)
-				if serr != nil {
-					log.Error().Err(serr).Str("provider", provider.Name()).Msg("ai/chat structured output failed")
-					execInfo.Finish(workflow.NewFunctionOutput(workflow.FunctionError, map[string]any{"error": serr.Error()}))
+			if sess != nil {
+				if err := sess.load(ctx); err != nil {
+					finish(workflow.NewFunctionOutput(workflow.FunctionError, map[string]any{"error": "ai/chat: " + err.Error()}))
 					return
 				}
-				execInfo.Finish(workflow.NewFunctionSuccessOutput(map[string]any{
-					"output": obj,
-					"usage": map[string]any{
-						"promptTokens":     u.PromptTokens,
-						"completionTokens": u.CompletionTokens,
-						"totalTokens":      u.TotalTokens,
-					},
-				}))
-				return
 			}
 
-			resp, err := provider.Chat(ctx, req)
-			if err != nil {
-				usage.RecordCall(ChatFunctionID, provider.Name(), req.Model, "error")
-				log.Error().Err(err).Str("provider", provider.Name()).Msg("ai/chat completion failed")
-				execInfo.Finish(workflow.NewFunctionOutput(workflow.FunctionError, map[string]any{"error": err.Error()}))
-				return
-			}
-			usage.RecordCall(ChatFunctionID, provider.Name(), req.Model, "success")
-			usage.RecordUsage(ChatFunctionID, provider.Name(), req.Model, resp.Usage)
-
-			execInfo.Finish(workflow.NewFunctionSuccessOutput(map[string]any{
-				"output": resp.Message.Content,
-				"usage": map[string]any{
-					"promptTokens":     resp.Usage.PromptTokens,
-					"completionTokens": resp.Usage.CompletionTokens,
-					"totalTokens":      resp.Usage.TotalTokens,
-				},
-			}))
+			req.Messages = sess.conversation(input.GetStr("systemPrompt"), userInput)
+			out := meter.withRouting(chatCompletion(ctx, provider, req, outputSchema, meter, stream))
+			sess.record(ctx, &turnSummarizer{provider: provider, model: req.Model, usage: meter, function: ChatFunctionID}, userInput, out)
+			finish(out)
 		}()
 
 		return workflow.NewFunctionResultAsync(), nil
 	}
 }
 
+// chatCompletion runs the node's single completion, or its structured-output exchange, and builds
+// the node's output. Budgets are checked before the call, and a call that takes spend over one
+// fails the node. A text answer is streamed to stream as it is generated; a structured one is not.
+func chatCompletion(ctx context.Context, provider llm.Provider, req llm.ChatRequest, outputSchema []workflow.ParameterSchema, meter *spendMeter, stream *nodeStream) workflow.FunctionOutput {
+	if budgetErr := meter.Check(); budgetErr != nil {
+		return budgetErrorOutput(budgetErr, llm.Usage{}, 0)
+	}
+
+	// Structured output (ADR-0030): coerce the answer into the requested schema.
+	if len(outputSchema) > 0 {
+		obj, u, serr := structuredOutput(ctx, provider, req.Model, req.Messages, outputSchema, meter, ChatFunctionID)
+		if serr != nil {
+			log.Error().Err(serr).Str("provider", provider.Name()).Msg("ai/chat structured output failed")
+			return workflow.NewFunctionOutput(workflow.FunctionError, map[string]any{"error": serr.Error()})
+		}
+		if budgetErr := meter.Exceeded(); budgetErr != nil {
+			return budgetErrorOutput(budgetErr, u, meter.Cost())
+		}
+		return workflow.NewFunctionSuccessOutput(map[string]any{
+			"output": obj,
+			"usage":  usageData(u, meter.Cost()),
+		})
+	}
+
+	resp, err := complete(ctx, provider, req, stream)
+	if err != nil {
+		meter.RecordCall(ChatFunctionID, provider.Name(), req.Model, "error")
+		log.Error().Err(err).Str("provider", provider.Name()).Msg("ai/chat completion failed")
+		return workflow.NewFunctionOutput(workflow.FunctionError, map[string]any{"error": err.Error()})
+	}
+	recordCompletion(meter, ChatFunctionID, provider, req.Model, resp)
+	if budgetErr := meter.Exceeded(); budgetErr != nil {
+		return budgetErrorOutput(budgetErr, resp.Usage, meter.Cost())
+	}
+
+	return workflow.NewFunctionSuccessOutput(map[string]any{
+		"output": resp.Message.Content,
+		"usage":  usageData(resp.Usage, meter.Cost()),
+	})
+}
+
 // resolveProvider returns the named provider, or the registry default when name is empty, built
 // for the given environment so per-context keys (ADR-0031) resolve against the running workflow.
 func resolveProvider(ctx context.Context, providers llm.Registry, environment, name string) (llm.Provider, error) {
@@ -0,0 +1,125 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/open-source-cloud/fuse/pkg/llm"
+	"github.com/rs/zerolog/log"
+)
+
+// agentCheckpoint is the journaled record of one completed reasoning iteration: everything needed
+// to rebuild the transcript, trace, and usage without repeating the iteration's model and tool
+// calls. Only iterations that end in tool calls are checkpointed; the final answer is the step's
+// result.
+//
+// An iteration that calls async tools is also journaled before those calls start, as a pending
+// decision holding only the assistant turn and the exec ids allocated to its async calls. Its
+// completed checkpoint supersedes it; a run resumed from a trailing decision repeats the decided
+// calls under the same exec ids, so the engine can hand back their results or reattach to their
+// running child workflows instead of starting them twice.
+type agentCheckpoint struct {
+	Iteration int `json:"iteration"`
+	// Context is the cut the context policy applied before the model call, if any.
+	Context *contextCut `json:"context,omitempty"`
+	// Messages are the turns the iteration appended: the assistant turn and one result per tool call.
+	Messages []llm.Message    `json:"messages"`
+	Steps    []map[string]any `json:"steps,omitempty"`
+	Usage    llm.Usage        `json:"usage"`
+	// CostUSD is the priced cost of every LLM call the iteration made.
+	CostUSD float64 `json:"costUSD,omitempty"`
+	// ChildCostUSD is the LLM cost reported by the child workflows the iteration ran.
+	ChildCostUSD float64 `json:"childCostUSD,omitempty"`
+	// Pending marks a decision journaled before the iteration's async tool calls started.
+	Pending bool `json:"pending,omitempty"`
+	// ExecIDs are the child exec ids of a decision's tool calls, in call order; empty for the
+	// synchronous ones.
+	ExecIDs []string `json:"execIds,omitempty"`
+}
+
+// agentState is the reasoning loop's running state.
+type agentState struct {
+	messages  []llm.Message
+	steps     []map[string]any
+	usage     llm.Usage
+	cost      float64
+	childCost float64
+	iteration int
+	// decision is the journaled decision of an iteration interrupted while its async tool calls ran.
+	decision *agentCheckpoint
+}
+
+// resume rebuilds the loop state from the checkpoints of an interrupted run, replaying them on top
+// of the initial messages. A checkpoint that cannot be read fails the attempt rather than guessing
+// at the transcript; a retry starts over with no checkpoints.
+func (e *agentExecutor) resume(messages []llm.Message) (*agentState, error) {
+	state := &agentState{messages: messages, steps: make([]map[string]any, 0)}
+	for i, raw := range e.checkpoints {
+		cp, err := decodeCheckpoint(raw)
+		if err == nil && cp.Iteration != state.iteration {
+			err = fmt.Errorf("out of order iteration %d", cp.Iteration)
+		}
+		if err == nil && cp.Pending && len(cp.Messages) != 1 {
+			err = fmt.Errorf("decision of iteration %d has %d messages", cp.Iteration, len(cp.Messages))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("ai/%s: cannot resume from checkpoint %d: %w", e.function, i, err)
+		}
+		if cp.Pending {
+			state.decision = &cp
+			continue
+		}
+		state.decision = nil
+		if cp.Context != nil {
+			state.messages = applyContextCut(state.messages, *cp.Context)
+		}
+		state.messages = concatMessages(state.messages, cp.Messages)
+		state.steps = append(state.steps, cp.Steps...)
+		addUsage(&state.usage, cp.Usage)
+		state.cost += cp.CostUSD
+		state.childCost += cp.ChildCostUSD
+		state.iteration = cp.Iteration + 1
+	}
+	return state, nil
+}
+
+// saveCheckpoint journals a completed iteration. It is a no-op when the execution cannot be
+// checkpointed; an unencodable checkpoint is skipped, costing only a repeated iteration on replay.
+func (e *agentExecutor) saveCheckpoint(cp agentCheckpoint) {
+	if e.checkpoint == nil {
+		return
+	}
+	data, err := encodeCheckpoint(cp)
+	if err != nil {
+		log.Warn().Err(err).Int("iteration", cp.Iteration).Str("function", e.function).Msg("ai checkpoint could not be encoded; skipped")
+		return
+	}
+	e.checkpoint(data)
+}
+
+// encodeCheckpoint converts a checkpoint to its JSON object form, so the journal entry held in
+// memory matches what a persisted and reloaded journal returns.
+func encodeCheckpoint(cp agentCheckpoint) (map[string]any, error) {
+	b, err := json.Marshal(cp)
+	if err != nil {
+		return nil, err
+	}
+	var data map[string]any
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// decodeCheckpoint is the inverse of encodeCheckpoint.
+func decodeCheckpoint(data map[string]any) (agentCheckpoint, error) {
+	var cp agentCheckpoint
+	b, err := json.Marshal(data)
+	if err != nil {
+		return cp, err
+	}
+	if err := json.Unmarshal(b, &cp); err != nil {
+		return cp, err
+	}
+	return cp, nil
+}
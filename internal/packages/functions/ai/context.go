@@ -1,6 +1,13 @@
 package ai
 
-import "github.com/open-source-cloud/fuse/pkg/llm"
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/open-source-cloud/fuse/pkg/llm"
+	"github.com/rs/zerolog/log"
+)
 
 const (
 	// contextStrategyDropOldest drops the oldest middle turns when over budget (deterministic).
@@ -74,3 +81,55 @@ func concatMessages(a, b []llm.Message) []llm.Message {
 	out = append(out, b...)
 	return out
 }
+
+// contextCut records how the context policy reshaped a transcript: the number of oldest middle
+// turns dropped and, under the summarize strategy, the summary that replaced them. Replaying a
+// cut reproduces the trimmed transcript without another model call.
+type contextCut struct {
+	Dropped int    `json:"dropped"`
+	Summary string `json:"summary,omitempty"`
+}
+
+// applyContextCut drops cut.Dropped turns after the head of messages, inserting the summary turn
+// (if any) in their place.
+func applyContextCut(messages []llm.Message, cut contextCut) []llm.Message {
+	h := headLen(messages)
+	rest := messages[h:]
+	dropped := min(cut.Dropped, len(rest))
+	var middle []llm.Message
+	if cut.Summary != "" {
+		middle = []llm.Message{{Role: llm.RoleSystem, Content: "Summary of earlier turns: " + cut.Summary}}
+	}
+	return concatMessages(messages[:h], append(middle, rest[dropped:]...))
+}
+
+// turnSummarizer condenses dropped turns into one note for the summarize strategy, using the
+// node's provider and model and recording the extra call's usage.
+type turnSummarizer struct {
+	provider llm.Provider
+	model    string
+	usage    UsageRecorder
+	function string
+}
+
+// summarize asks the provider to summarize the dropped turns into one note. Returns "" on error, so
+// the caller falls back to plain drop-oldest.
+func (s *turnSummarizer) summarize(ctx context.Context, dropped []llm.Message) string {
+	var b strings.Builder
+	for _, m := range dropped {
+		fmt.Fprintf(&b, "%s: %s\n", m.Role, m.Content)
+	}
+	resp, err := s.provider.Chat(ctx, llm.ChatRequest{
+		Model: s.model,
+		Messages: []llm.Message{
+			{Role: llm.RoleSystem, Content: "Summarize the following conversation turns concisely, preserving facts, tool results, and decisions."},
+			{Role: llm.RoleUser, Content: b.String()},
+		},
+	})
+	if err != nil {
+		log.Warn().Err(err).Str("function", s.function).Msg("ai context summarization failed; dropping oldest turns instead")
+		return ""
+	}
+	recordCompletion(s.usage, s.function, s.provider, s.model, resp)
+	return resp.Message.Content
+}
@@ -75,3 +75,36 @@ func TestTrimContext(t *testing.T) {
 		assert.Empty(t, dropped)
 	})
 }
+
+func TestApplyContextCut(t *testing.T) {
+	t.Parallel()
+
+	in := []llm.Message{
+		msg(llm.RoleSystem, 10),
+		msg(llm.RoleUser, 10),
+		msg(llm.RoleAssistant, 10),
+		msg(llm.RoleTool, 10),
+		msg(llm.RoleAssistant, 10),
+		msg(llm.RoleTool, 10),
+	}
+
+	t.Run("replaying a drop matches trimContext", func(t *testing.T) {
+		t.Parallel()
+		kept, dropped := trimContext(in, 40)
+		assert.Equal(t, kept, applyContextCut(in, contextCut{Dropped: len(dropped)}))
+	})
+
+	t.Run("summary replaces the dropped turns", func(t *testing.T) {
+		t.Parallel()
+		out := applyContextCut(in, contextCut{Dropped: 2, Summary: "earlier"})
+		assert.Len(t, out, 5)
+		assert.Equal(t, llm.RoleSystem, out[2].Role)
+		assert.Equal(t, "Summary of earlier turns: earlier", out[2].Content)
+		assert.Equal(t, in[4:], out[3:])
+	})
+
+	t.Run("oversized cut keeps only the head", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, in[:2], applyContextCut(in, contextCut{Dropped: 99}))
+	})
+}
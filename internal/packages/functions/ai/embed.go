@@ -0,0 +1,142 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/open-source-cloud/fuse/internal/packages/transport"
+	"github.com/open-source-cloud/fuse/pkg/llm"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+	"github.com/rs/zerolog/log"
+)
+
+// EmbedFunctionID is the id of the embed function.
+const EmbedFunctionID = "embed"
+
+// ErrEmbedInputRequired is returned when neither input nor inputs is set.
+var ErrEmbedInputRequired = errors.New("ai/embed: input or inputs is required")
+
+// EmbedFunctionMetadata returns the metadata for the embed function.
+func EmbedFunctionMetadata() workflow.FunctionMetadata {
+	return workflow.FunctionMetadata{
+		Transport: transport.Internal,
+		Input: workflow.InputMetadata{
+			CustomParameters: false,
+			Parameters: []workflow.ParameterSchema{
+				{Name: "input", Type: "string", Required: false, Description: "A text to embed"},
+				{Name: "inputs", Type: "array", Required: false, Description: "Texts to embed, one vector each; used when input is not set"},
+				{Name: "provider", Type: "string", Required: false, Description: "Provider registry key; it must support embeddings. Defaults to the configured default provider"},
+				{Name: "model", Type: "string", Required: false, Description: "Embedding model id. Defaults to the provider's EMBEDDING_MODEL"},
+				{Name: "dimensions", Type: "int", Required: false, Description: "Requested vector length, for models that can shorten their vectors"},
+				{Name: "maxCostUSD", Type: "float", Required: false, Description: "Optional USD cap for this node's embedding calls, priced from LLM_PRICING"},
+				{Name: "maxTokens", Type: "int", Required: false, Description: "Optional cap on the total tokens of this node's embedding calls"},
+			},
+			Edges: workflow.InputEdgeMetadata{
+				Count:      0,
+				Parameters: make([]workflow.ParameterSchema, 0),
+			},
+		},
+		Output: workflow.OutputMetadata{
+			Parameters: []workflow.ParameterSchema{
+				{Name: "vectors", Type: "array", Required: true, Description: "One vector per text, in input order"},
+				{Name: "vector", Type: "array", Required: false, Description: "The vector of input, when input was set"},
+				{Name: "usage", Type: "map", Required: false, Description: "Token usage and its priced cost (costUSD)"},
+			},
+			Edges: make([]workflow.OutputEdgeMetadata, 0),
+		},
+	}
+}
+
+// makeEmbedFunction builds the ai/embed function, closing over the provider registry and the
+// usage recorder, pricing table and ledger its spend is metered against.
+func makeEmbedFunction(deps Deps) workflow.Function {
+	return func(execInfo *workflow.ExecutionInfo) (workflow.FunctionResult, error) {
+		input := execInfo.Input
+
+		req := embeddingRequest(input)
+		single := input.GetStr("input")
+		if single != "" {
+			req.Input = []string{single}
+		} else {
+			texts, err := stringsInput(input, "inputs")
+			if err != nil {
+				return workflow.NewFunctionResultError(err)
+			}
+			req.Input = texts
+		}
+		if len(req.Input) == 0 {
+			return workflow.NewFunctionResultError(ErrEmbedInputRequired)
+		}
+		limit, err := nodeBudgetLimit(input)
+		if err != nil {
+			return workflow.NewFunctionResultError(err)
+		}
+		providerName := input.GetStr("provider")
+
+		go func() {
+			ctx, cancel := context.WithTimeout(execInfo.Ctx(), embedTimeout)
+			defer cancel()
+			meter := newSpendMeter(ctx, deps.Usage, deps.Pricing, deps.Ledger, execInfo, limit)
+
+			provider, err := resolveProvider(ctx, deps.Providers, execInfo.Environment, providerName)
+			if err != nil {
+				log.Error().Err(err).Str("provider", providerName).Msg("ai/embed provider resolution failed")
+				execInfo.Finish(errorOutput(fmt.Sprintf("ai/embed: provider resolution failed: %v", err)))
+				return
+			}
+			vectors, u, err := embedTexts(ctx, provider, req, meter, EmbedFunctionID)
+			if out, failed := embeddingFailure(EmbedFunctionID, err, u, meter); failed {
+				execInfo.Finish(out)
+				return
+			}
+
+			data := map[string]any{"vectors": vectors, "usage": usageData(u, meter.Cost())}
+			if single != "" {
+				data["vector"] = vectors[0]
+			}
+			execInfo.Finish(workflow.NewFunctionSuccessOutput(data))
+		}()
+
+		return workflow.NewFunctionResultAsync(), nil
+	}
+}
+
+// embeddingFailure builds the output of a retrieval node whose embedding step failed or went over a
+// budget; failed is false when the node may continue.
+func embeddingFailure(function string, err error, u llm.Usage, meter *spendMeter) (out workflow.FunctionOutput, failed bool) {
+	var budgetErr *llm.BudgetExceededError
+	if errors.As(err, &budgetErr) {
+		return budgetErrorOutput(budgetErr, u, meter.Cost()), true
+	}
+	if err != nil {
+		log.Error().Err(err).Str("function", function).Msg("ai embedding failed")
+		return errorOutput(fmt.Sprintf("ai/%s: %v", function, err)), true
+	}
+	if budgetErr := meter.Exceeded(); budgetErr != nil {
+		return budgetErrorOutput(budgetErr, u, meter.Cost()), true
+	}
+	return workflow.FunctionOutput{}, false
+}
+
+// stringsInput reads an array input whose items must all be strings.
+func stringsInput(input *workflow.FunctionInput, key string) ([]string, error) {
+	switch v := input.Get(key).(type) {
+	case nil:
+		return nil, nil
+	case []string:
+		return v, nil
+	case []any:
+		out := make([]string, 0, len(v))
+		for i, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("ai: %s[%d] must be a string, got %T", key, i, item)
+			}
+			out = append(out, s)
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("ai: %s must be an array of strings, got %T", key, v)
+	}
+}
@@ -0,0 +1,250 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/open-source-cloud/fuse/internal/packages/transport"
+	"github.com/open-source-cloud/fuse/pkg/vectorstore"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+	"github.com/rs/zerolog/log"
+)
+
+// IndexFunctionID is the id of the index function.
+const IndexFunctionID = "index"
+
+const (
+	// defaultChunkSize is the chunk length, in characters, when the node sets no chunkSize.
+	defaultChunkSize = 1000
+	// defaultChunkOverlap is the number of characters consecutive chunks share by default.
+	defaultChunkOverlap = 100
+)
+
+// ErrIndexDocumentsRequired is returned when neither documents nor input is set.
+var ErrIndexDocumentsRequired = errors.New("ai/index: documents or input is required")
+
+// IndexFunctionMetadata returns the metadata for the index function.
+func IndexFunctionMetadata() workflow.FunctionMetadata {
+	return workflow.FunctionMetadata{
+		Transport: transport.Internal,
+		Input: workflow.InputMetadata{
+			CustomParameters: false,
+			Parameters: []workflow.ParameterSchema{
+				{Name: "collection", Type: "string", Required: true, Description: "Collection the chunks are stored in, scoped to the workflow's namespace"},
+				{Name: "documents", Type: "array", Required: false, Description: "Documents to index: strings, or {id, content, metadata} objects. A document without an id is identified by a hash of its content"},
+				{Name: "input", Type: "string", Required: false, Description: "A single document to index; used when documents is not set"},
+				{Name: "id", Type: "string", Required: false, Description: "Id of the input document"},
+				{Name: "metadata", Type: "map", Required: false, Description: "Metadata of the input document, stored with each of its chunks"},
+				{Name: "chunkSize", Type: "int", Required: false, Default: defaultChunkSize, Description: "Maximum chunk length in characters; chunks end at whitespace where possible"},
+				{Name: "chunkOverlap", Type: "int", Required: false, Default: defaultChunkOverlap, Description: "Characters shared by consecutive chunks of a document; must be smaller than chunkSize"},
+				{Name: "provider", Type: "string", Required: false, Description: "Provider registry key; it must support embeddings. Defaults to the configured default provider"},
+				{Name: "model", Type: "string", Required: false, Description: "Embedding model id. Defaults to the provider's EMBEDDING_MODEL; a collection must be queried with the model it was indexed with"},
+				{Name: "dimensions", Type: "int", Required: false, Description: "Requested vector length, for models that can shorten their vectors"},
+				{Name: "maxCostUSD", Type: "float", Required: false, Description: "Optional USD cap for this node's embedding calls, priced from LLM_PRICING"},
+				{Name: "maxTokens", Type: "int", Required: false, Description: "Optional cap on the total tokens of this node's embedding calls"},
+			},
+			Edges: workflow.InputEdgeMetadata{
+				Count:      0,
+				Parameters: make([]workflow.ParameterSchema, 0),
+			},
+		},
+		Output: workflow.OutputMetadata{
+			Parameters: []workflow.ParameterSchema{
+				{Name: "chunks", Type: "int", Required: true, Description: "Number of chunks stored"},
+				{Name: "ids", Type: "array", Required: true, Description: "Ids of the stored chunks: <documentId>#<n>"},
+				{Name: "usage", Type: "map", Required: false, Description: "Token usage and its priced cost (costUSD)"},
+			},
+			Edges: make([]workflow.OutputEdgeMetadata, 0),
+		},
+	}
+}
+
+// indexDocument is one document of an ai/index node.
+type indexDocument struct {
+	id       string
+	content  string
+	metadata map[string]any
+}
+
+// makeIndexFunction builds the ai/index function: it splits documents into overlapping chunks,
+// embeds them and upserts them into the vector store. Re-indexing a document replaces its chunks
+// by id; chunks beyond the new chunk count of a shrunken document are left in place.
+func makeIndexFunction(deps Deps) workflow.Function {
+	return func(execInfo *workflow.ExecutionInfo) (workflow.FunctionResult, error) {
+		input := execInfo.Input
+
+		if deps.Vectors == nil {
+			return workflow.NewFunctionResultError(ErrVectorStoreUnavailable)
+		}
+		collection := input.GetStr("collection")
+		if collection == "" {
+			return workflow.NewFunctionResultError(ErrCollectionRequired)
+		}
+		docs, err := indexDocuments(input)
+		if err != nil {
+			return workflow.NewFunctionResultError(err)
+		}
+		size := intOrDefault(input, "chunkSize", defaultChunkSize)
+		overlap := intOrDefault(input, "chunkOverlap", defaultChunkOverlap)
+		if size <= 0 || overlap < 0 || overlap >= size {
+			return workflow.NewFunctionResultError(fmt.Errorf("ai/index: need 0 <= chunkOverlap < chunkSize, got %d and %d", overlap, size))
+		}
+		limit, err := nodeBudgetLimit(input)
+		if err != nil {
+			return workflow.NewFunctionResultError(err)
+		}
+
+		chunks := make([]vectorstore.Chunk, 0, len(docs))
+		req := embeddingRequest(input)
+		for _, doc := range docs {
+			for i, text := range chunkText(doc.content, size, overlap) {
+				metadata := make(map[string]any, len(doc.metadata)+2)
+				for k, v := range doc.metadata {
+					metadata[k] = v
+				}
+				metadata["documentId"] = doc.id
+				metadata["chunk"] = i
+				chunks = append(chunks, vectorstore.Chunk{ID: fmt.Sprintf("%s#%d", doc.id, i), Content: text, Metadata: metadata})
+				req.Input = append(req.Input, text)
+			}
+		}
+		providerName := input.GetStr("provider")
+		namespace := workflow.NamespaceOf(execInfo.SchemaID)
+
+		go func() {
+			ctx, cancel := context.WithTimeout(execInfo.Ctx(), embedTimeout)
+			defer cancel()
+			meter := newSpendMeter(ctx, deps.Usage, deps.Pricing, deps.Ledger, execInfo, limit)
+
+			provider, err := resolveProvider(ctx, deps.Providers, execInfo.Environment, providerName)
+			if err != nil {
+				log.Error().Err(err).Str("provider", providerName).Msg("ai/index provider resolution failed")
+				execInfo.Finish(errorOutput(fmt.Sprintf("ai/index: provider resolution failed: %v", err)))
+				return
+			}
+			embedded, u, err := embedTexts(ctx, provider, req, meter, IndexFunctionID)
+			if out, failed := embeddingFailure(IndexFunctionID, err, u, meter); failed {
+				execInfo.Finish(out)
+				return
+			}
+
+			ids := make([]string, len(chunks))
+			for i := range chunks {
+				chunks[i].Vector = embedded[i]
+				ids[i] = chunks[i].ID
+			}
+			if err := deps.Vectors.Upsert(ctx, namespace, collection, chunks); err != nil {
+				execInfo.Finish(errorOutput(fmt.Sprintf("ai/index: store chunks in %q: %v", collection, err)))
+				return
+			}
+			execInfo.Finish(workflow.NewFunctionSuccessOutput(map[string]any{
+				"chunks": len(chunks),
+				"ids":    ids,
+				"usage":  usageData(u, meter.Cost()),
+			}))
+		}()
+
+		return workflow.NewFunctionResultAsync(), nil
+	}
+}
+
+// indexDocuments reads the documents input, or the input/id/metadata inputs of a single document.
+func indexDocuments(input *workflow.FunctionInput) ([]indexDocument, error) {
+	raw := input.Get("documents")
+	if raw == nil {
+		content := input.GetStr("input")
+		if strings.TrimSpace(content) == "" {
+			return nil, ErrIndexDocumentsRequired
+		}
+		metadata, _ := input.Get("metadata").(map[string]any)
+		return []indexDocument{newIndexDocument(input.GetStr("id"), content, metadata)}, nil
+	}
+
+	var items []any
+	switch v := raw.(type) {
+	case []any:
+		items = v
+	case []string:
+		for _, s := range v {
+			items = append(items, s)
+		}
+	case []map[string]any:
+		for _, m := range v {
+			items = append(items, m)
+		}
+	default:
+		return nil, fmt.Errorf("ai/index: documents must be an array, got %T", raw)
+	}
+
+	docs := make([]indexDocument, 0, len(items))
+	for i, item := range items {
+		switch v := item.(type) {
+		case string:
+			docs = append(docs, newIndexDocument("", v, nil))
+		case map[string]any:
+			content, _ := v["content"].(string)
+			id, _ := v["id"].(string)
+			metadata, _ := v["metadata"].(map[string]any)
+			docs = append(docs, newIndexDocument(id, content, metadata))
+		default:
+			return nil, fmt.Errorf("ai/index: documents[%d] must be a string or an object, got %T", i, item)
+		}
+		if strings.TrimSpace(docs[len(docs)-1].content) == "" {
+			return nil, fmt.Errorf("ai/index: documents[%d] has no content", i)
+		}
+	}
+	if len(docs) == 0 {
+		return nil, ErrIndexDocumentsRequired
+	}
+	return docs, nil
+}
+
+// newIndexDocument builds a document, deriving a missing id from its content so re-indexing the
+// same text replaces rather than duplicates it.
+func newIndexDocument(id, content string, metadata map[string]any) indexDocument {
+	if id == "" {
+		sum := sha256.Sum256([]byte(content))
+		id = hex.EncodeToString(sum[:8])
+	}
+	return indexDocument{id: id, content: content, metadata: metadata}
+}
+
+// chunkText splits text into chunks of at most size characters, consecutive chunks sharing overlap
+// characters at most. Chunks end at the last whitespace of their second half and the overlap starts
+// at a word when possible, so words are rarely cut.
+func chunkText(text string, size, overlap int) []string {
+	runes := []rune(strings.TrimSpace(text))
+	chunks := make([]string, 0, len(runes)/size+1)
+	for start := 0; start < len(runes); {
+		for unicode.IsSpace(runes[start]) {
+			start++
+		}
+		end := min(start+size, len(runes))
+		if end < len(runes) {
+			for i := end; i > start+size/2; i-- {
+				if unicode.IsSpace(runes[i]) {
+					end = i
+					break
+				}
+			}
+		}
+		if chunk := strings.TrimSpace(string(runes[start:end])); chunk != "" {
+			chunks = append(chunks, chunk)
+		}
+		if end == len(runes) {
+			break
+		}
+		// Start the next chunk overlap characters back, moved forward to a word start.
+		next := max(end-overlap, start+1)
+		for next < end && !unicode.IsSpace(runes[next-1]) {
+			next++
+		}
+		start = next
+	}
+	return chunks
+}
@@ -0,0 +1,462 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/open-source-cloud/fuse/internal/packages/functions/system"
+	"github.com/open-source-cloud/fuse/internal/packages/transport"
+	"github.com/open-source-cloud/fuse/pkg/llm"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+	"github.com/rs/zerolog/log"
+)
+
+// OrchestrateFunctionID is the id of the orchestrator function.
+const OrchestrateFunctionID = "orchestrate"
+
+const (
+	// defaultMaxChildren is the default number of child workflows one orchestrator run may start.
+	defaultMaxChildren = 10
+	// maxMaxChildren is the hard upper bound an author may request.
+	maxMaxChildren = 50
+	// defaultMaxDepth is the default length of the chain of workflows an orchestrator may run in,
+	// counting its own.
+	defaultMaxDepth = 3
+	// maxMaxDepth is the hard upper bound an author may request.
+	maxMaxDepth = 5
+	// defaultOrchestrateTimeout bounds one attempt of the orchestrator when the node sets no
+	// timeout input; child workflows usually take longer than tool calls.
+	defaultOrchestrateTimeout = 30 * time.Minute
+	// defaultChildTimeout bounds one child workflow when the node sets no childTimeout input.
+	defaultChildTimeout = 10 * time.Minute
+	// workflowToolPrefix starts the tool name of every workflow an orchestrator offers.
+	workflowToolPrefix = "workflow__"
+)
+
+var (
+	// ErrOrchestrateInputRequired is returned when the required task input is missing.
+	ErrOrchestrateInputRequired = errors.New("ai/orchestrate: input is required")
+	// ErrOrchestrateWorkflowsRequired is returned when the workflows input lists no schema.
+	ErrOrchestrateWorkflowsRequired = errors.New("ai/orchestrate: workflows must list at least one schema id")
+	// ErrOrchestrateUnavailable is returned when the engine cannot run child workflows for the node.
+	ErrOrchestrateUnavailable = errors.New("ai/orchestrate: child workflows are not available in this engine")
+	// ErrOrchestrateInvalidTimeout is returned when the timeout input is not a positive duration.
+	ErrOrchestrateInvalidTimeout = errors.New("ai/orchestrate: timeout must be a positive duration (e.g. 10m, 1h)")
+	// ErrOrchestrateInvalidChildTimeout is returned when the childTimeout input is not a positive
+	// duration.
+	ErrOrchestrateInvalidChildTimeout = errors.New("ai/orchestrate: childTimeout must be a positive duration (e.g. 5m, 1h)")
+)
+
+// unsafeToolNameChars matches the characters providers reject in tool names.
+var unsafeToolNameChars = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+// WorkflowCatalog describes the workflow schemas ai/orchestrate may run as tools and where an
+// execution sits in a chain of sub-workflows. Like ToolRegistry it is a port: the engine implements
+// it over its repositories.
+type WorkflowCatalog interface {
+	// DescribeWorkflow returns the schema of namespace as a tool, failing when it does not exist
+	// or does not accept new executions.
+	DescribeWorkflow(namespace, schemaID string) (WorkflowTool, error)
+	// Depth returns the number of parent workflows of the execution: 0 for a top-level execution.
+	Depth(workflowID workflow.ID) (int, error)
+}
+
+// WorkflowTool is a workflow schema offered to an orchestrator's model.
+type WorkflowTool struct {
+	SchemaID    string
+	Name        string
+	Description string
+	// Input is the schema's trigger input contract; it becomes the tool's parameters.
+	Input []workflow.ParameterSchema
+}
+
+// OrchestrateFunctionMetadata returns the metadata for the orchestrate function.
+func OrchestrateFunctionMetadata() workflow.FunctionMetadata {
+	return workflow.FunctionMetadata{
+		Transport: transport.Internal,
+		Input: workflow.InputMetadata{
+			CustomParameters: false,
+			Parameters: []workflow.ParameterSchema{
+				{Name: "input", Type: "string", Required: true, Description: "The task / goal the orchestrator should accomplish"},
+				{Name: "workflows", Type: "array", Required: true, Description: "Schema ids of the workflows the orchestrator may run; each is offered to the model as a tool whose parameters are the schema's input contract"},
+				{Name: "provider", Type: "string", Required: false, Description: "Provider registry key (e.g. openai, ollama) or routing policy name. Defaults to the configured default provider"},
+				{Name: "model", Type: "string", Required: false, Description: "Model id. Defaults to the provider's configured default model"},
+				{Name: "systemPrompt", Type: "string", Required: false, Description: "Optional system instruction prepended to the conversation"},
+				{Name: "temperature", Type: "float", Required: false, Description: "Sampling temperature; if omitted the provider default is used"},
+				{Name: "maxIterations", Type: "int", Required: false, Default: defaultMaxIterations, Description: "Maximum reasoning iterations (clamped to [1, 25])"},
+				{Name: "maxChildren", Type: "int", Required: false, Default: defaultMaxChildren, Description: "Maximum child workflows one run may start (clamped to [1, 50]); further calls return an error to the model"},
+				{Name: "maxDepth", Type: "int", Required: false, Default: defaultMaxDepth, Description: "Maximum length of the chain of parent workflows, counting the orchestrator's own (clamped to [1, 5]); an orchestrator deeper than this fails"},
+				{Name: "outputSchema", Type: "array", Required: false, Description: "Optional list of {name,type,required,description} fields; when set the final output is a validated object matching this schema (ADR-0030)"},
+				{Name: "timeout", Type: "string", Required: false, Default: defaultOrchestrateTimeout.String(), Description: "Deadline for one attempt of the orchestrator as a duration (e.g. 10m, 1h); the node's execution timeout also applies"},
+				{Name: "childTimeout", Type: "string", Required: false, Default: defaultChildTimeout.String(), Description: "Deadline for each child workflow as a duration; a child that exceeds it returns an error to the model"},
+				{Name: "maxCostUSD", Type: "float", Required: false, Description: "Optional USD cap for this node's LLM calls and those of its child workflows, priced from LLM_PRICING"},
+				{Name: "maxTokens", Type: "int", Required: false, Description: "Optional cap on the total tokens of this node's LLM calls"},
+				{Name: "onBudgetExceeded", Type: "string", Required: false, Default: budgetActionFail, Description: "When a node or schema LLM budget is exceeded: 'fail' (default) fails the node with errorType budget_exceeded; 'stop' ends the loop and returns the last answer"},
+			},
+			Edges: workflow.InputEdgeMetadata{
+				Count:      0,
+				Parameters: make([]workflow.ParameterSchema, 0),
+			},
+		},
+		Output: workflow.OutputMetadata{
+			Parameters: []workflow.ParameterSchema{
+				{Name: "output", Type: "string", Required: true, Description: "The orchestrator's final text answer"},
+				{Name: "usage", Type: "map", Required: false, Description: "Aggregated token usage of the orchestrator's own LLM calls, their priced cost (costUSD), and the LLM cost of its child workflows (childCostUSD); with a routing policy also policy, provider, model and fallbacks"},
+				{Name: "steps", Type: "array", Required: false, Description: "Trace of each workflow call: workflow, arguments, execId, childWorkflowId, status, and result or error"},
+				{Name: "children", Type: "array", Required: false, Description: "The child workflows started: schemaId, workflowId, execId and status"},
+				{Name: "stopReason", Type: "string", Required: false, Description: "Set to budget_exceeded when onBudgetExceeded 'stop' ended the loop early"},
+			},
+			Edges: make([]workflow.OutputEdgeMetadata, 0),
+		},
+	}
+}
+
+// makeOrchestrateFunction builds the ai/orchestrate function (ADR-0026). It is ai/agent's
+// reasoning loop with workflows instead of functions as tools: every call runs a whitelisted
+// schema as a child workflow through the runtime, and the catalog describes those schemas and
+// bounds how deep orchestrators may nest.
+func makeOrchestrateFunction(deps Deps) workflow.Function {
+	return func(execInfo *workflow.ExecutionInfo) (workflow.FunctionResult, error) {
+		input := execInfo.Input
+
+		userInput := input.GetStr("input")
+		if userInput == "" {
+			return workflow.NewFunctionResultError(ErrOrchestrateInputRequired)
+		}
+		schemaIDs := workflowSchemaIDs(input)
+		if len(schemaIDs) == 0 {
+			return workflow.NewFunctionResultError(ErrOrchestrateWorkflowsRequired)
+		}
+		if deps.Runtime == nil || deps.Catalog == nil {
+			return workflow.NewFunctionResultError(ErrOrchestrateUnavailable)
+		}
+
+		providerName := input.GetStr("provider")
+
+		timeout, err := orchestrateTimeout(input)
+		if err != nil {
+			return workflow.NewFunctionResultError(err)
+		}
+		childTimeout, err := orchestrateChildTimeout(input)
+		if err != nil {
+			return workflow.NewFunctionResultError(err)
+		}
+		limit, err := nodeBudgetLimit(input)
+		if err != nil {
+			return workflow.NewFunctionResultError(err)
+		}
+		onBudgetExceeded, err := budgetAction(input)
+		if err != nil {
+			return workflow.NewFunctionResultError(err)
+		}
+		maxDepth := clampInt(input.GetInt("maxDepth"), defaultMaxDepth, maxMaxDepth)
+
+		executor := &agentExecutor{
+			function:         OrchestrateFunctionID,
+			runtime:          deps.Runtime,
+			toolTimeout:      childTimeout,
+			model:            input.GetStr("model"),
+			temp:             optionalTemperature(input),
+			maxIters:         clampIterations(input.GetInt("maxIterations")),
+			wfID:             execInfo.WorkflowID,
+			execID:           execInfo.ExecID,
+			environment:      execInfo.Environment,
+			onBudgetExceeded: onBudgetExceeded,
+			outputSchema:     parseOutputSchema(input),
+			checkpoints:      execInfo.Checkpoints,
+			checkpoint:       execInfo.Checkpoint,
+			maxChildren:      clampInt(input.GetInt("maxChildren"), defaultMaxChildren, maxMaxChildren),
+		}
+
+		// Like ai/agent, everything that may block runs in its own goroutine and reports back via
+		// Finish: the catalog reads schemas and the chain of parent workflows, and the loop waits
+		// for child workflows that can run for minutes.
+		go func() {
+			ctx, cancel := context.WithTimeout(execInfo.Ctx(), timeout)
+			defer cancel()
+			executor.meter = newSpendMeter(ctx, deps.Usage, deps.Pricing, deps.Ledger, execInfo, limit)
+			executor.usage = executor.meter
+			executor.stream = openNodeStream(deps.Outputs, execInfo)
+			finish := func(out workflow.FunctionOutput) {
+				execInfo.Finish(out)
+				executor.stream.finish(out)
+			}
+
+			depth, err := deps.Catalog.Depth(execInfo.WorkflowID)
+			if err != nil {
+				finish(executor.errorf("cannot determine workflow depth: %v", err))
+				return
+			}
+			if depth+1 > maxDepth {
+				finish(executor.errorf("workflow depth %d exceeds maxDepth %d", depth+1, maxDepth))
+				return
+			}
+			namespace, _ := workflow.SplitQualifiedID(execInfo.SchemaID)
+			descriptors, err := describeWorkflows(deps.Catalog, namespace, schemaIDs)
+			if err != nil {
+				finish(executor.errorf("%v", err))
+				return
+			}
+			executor.llmTools, executor.byMangled = buildTools(descriptors, nil, true)
+
+			provider, err := resolveProvider(ctx, deps.Providers, execInfo.Environment, providerName)
+			if err != nil {
+				log.Error().Err(err).Str("provider", providerName).Msg("ai/orchestrate provider resolution failed")
+				finish(executor.errorf("provider resolution failed: %v", err))
+				return
+			}
+			executor.provider = provider
+
+			var messages []llm.Message
+			if systemPrompt := input.GetStr("systemPrompt"); systemPrompt != "" {
+				messages = append(messages, llm.Message{Role: llm.RoleSystem, Content: systemPrompt})
+			}
+			messages = append(messages, llm.Message{Role: llm.RoleUser, Content: userInput})
+			finish(executor.withChildren(executor.meter.withRouting(executor.run(ctx, messages))))
+		}()
+
+		return workflow.NewFunctionResultAsync(), nil
+	}
+}
+
+// describeWorkflows turns the whitelisted schemas of namespace into tools, failing on the first the
+// catalog cannot describe so a misconfigured node fails before spending anything.
+func describeWorkflows(catalog WorkflowCatalog, namespace string, schemaIDs []string) ([]ToolDescriptor, error) {
+	descriptors := make([]ToolDescriptor, 0, len(schemaIDs))
+	names := make(map[string]string, len(schemaIDs))
+	for _, schemaID := range schemaIDs {
+		wf, err := catalog.DescribeWorkflow(namespace, schemaID)
+		if err != nil {
+			return nil, fmt.Errorf("workflow %q: %w", schemaID, err)
+		}
+		name := workflowToolName(schemaID)
+		if other, taken := names[name]; taken {
+			return nil, fmt.Errorf("workflows %q and %q map to the same tool name %q", other, schemaID, name)
+		}
+		names[name] = schemaID
+		descriptors = append(descriptors, ToolDescriptor{
+			FunctionID:  system.SubWorkflowFullFunctionID,
+			MangledName: name,
+			Description: workflowToolDescription(wf),
+			Parameters:  ParameterSchemaToJSONSchema(wf.Input),
+			Async:       true,
+			SchemaID:    schemaID,
+		})
+	}
+	return descriptors, nil
+}
+
+// workflowToolName derives a provider-safe tool name from a schema id.
+func workflowToolName(schemaID string) string {
+	return workflowToolPrefix + unsafeToolNameChars.ReplaceAllString(schemaID, "_")
+}
+
+// workflowToolDescription tells the model what running the workflow does.
+func workflowToolDescription(wf WorkflowTool) string {
+	description := fmt.Sprintf("Runs the workflow %q", wf.SchemaID)
+	if wf.Name != "" && wf.Name != wf.SchemaID {
+		description += " (" + wf.Name + ")"
+	}
+	if wf.Description != "" {
+		description += ": " + wf.Description
+	}
+	return description + ". Returns the workflow's final status and the outputs of its nodes."
+}
+
+// executeWorkflowToolCall runs the workflow behind a tool as a child workflow of this node and
+// waits for it to end. Calls over maxChildren or the cost cap, and calls missing required input,
+// are refused without starting a child. A child refused by the namespace's concurrency quota fails
+// the node. The child's LLM cost counts towards maxCostUSD.
+func (e *agentExecutor) executeWorkflowToolCall(ctx context.Context, tc llm.ToolCall, tool ToolDescriptor, args map[string]any, execID workflow.ExecID) (llm.Message, map[string]any) {
+	step := map[string]any{"workflow": tool.SchemaID, "arguments": args}
+	refuse := func(msg string) (llm.Message, map[string]any) {
+		step["error"] = msg
+		return toolMessage(tc, map[string]any{"error": msg}), step
+	}
+	if e.children >= e.maxChildren {
+		return refuse(fmt.Sprintf("child workflow limit reached (%d); no more workflows can be run", e.maxChildren))
+	}
+	if budgetErr := e.meter.Exceeded(); budgetErr != nil {
+		return refuse(budgetErr.Error())
+	}
+	if missing := missingArguments(tool.Parameters, args); len(missing) > 0 {
+		return refuse("missing required input: " + strings.Join(missing, ", "))
+	}
+	if args == nil {
+		args = map[string]any{}
+	}
+
+	e.children++
+	step["execId"] = execID.String()
+	output, err := e.invokeAsync(ctx, tool.FunctionID, map[string]any{"schemaId": tool.SchemaID, "input": args}, execID)
+	if err != nil {
+		return refuse(fmt.Sprintf("child workflow failed: %v", err))
+	}
+	if childID, ok := output.Data["workflowId"].(string); ok {
+		step["childWorkflowId"] = childID
+		step["status"] = output.Data["status"]
+	}
+	childOutput, _ := output.Data["output"].(map[string]any)
+	e.meter.addChildCost(childLLMCost(childOutput))
+
+	if output.Status == workflow.FunctionError {
+		if output.Data["errorType"] == system.ErrorTypeQuotaExceeded {
+			// the child never started; the namespace is full, so the node fails instead of
+			// letting the model retry into the same quota
+			e.children--
+			fatal := e.errorf("%v", output.Data["error"])
+			fatal.Data["errorType"] = system.ErrorTypeQuotaExceeded
+			e.fatal = &fatal
+			return refuse(fmt.Sprint(output.Data["error"]))
+		}
+		msg, ok := output.Data["error"].(string)
+		if !ok {
+			msg = fmt.Sprintf("child workflow ended %v", output.Data["status"])
+		}
+		step["error"] = msg
+		return toolMessage(tc, output.Data), step
+	}
+	step["result"] = output.Data
+	return toolMessage(tc, output.Data), step
+}
+
+// withChildren adds the child workflows and their LLM cost to an orchestrator's output.
+func (e *agentExecutor) withChildren(out workflow.FunctionOutput) workflow.FunctionOutput {
+	if usage, ok := out.Data["usage"].(map[string]any); ok {
+		usage["childCostUSD"] = e.meter.ChildCost()
+	}
+	steps, _ := out.Data["steps"].([]map[string]any)
+	if steps == nil {
+		return out
+	}
+	children := make([]map[string]any, 0, e.children)
+	for _, step := range steps {
+		if _, started := step["execId"]; !started || step["workflow"] == nil {
+			continue
+		}
+		child := map[string]any{"schemaId": step["workflow"], "execId": step["execId"]}
+		if id, ok := step["childWorkflowId"]; ok {
+			child["workflowId"] = id
+			child["status"] = step["status"]
+		}
+		children = append(children, child)
+	}
+	out.Data["children"] = children
+	return out
+}
+
+// countChildren returns the number of child workflows the given steps started.
+func countChildren(steps []map[string]any) int {
+	n := 0
+	for _, step := range steps {
+		if _, started := step["execId"]; started && step["workflow"] != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// childLLMCost sums the LLM cost the nodes of a child workflow reported in their usage output,
+// including that of their own children.
+func childLLMCost(nodeOutputs map[string]any) float64 {
+	total := 0.0
+	for _, v := range nodeOutputs {
+		output, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+		usage, ok := output["usage"].(map[string]any)
+		if !ok {
+			continue
+		}
+		total += number(usage["costUSD"]) + number(usage["childCostUSD"])
+	}
+	return total
+}
+
+// number reads a numeric output value, which is float64 once journaled and reloaded.
+func number(v any) float64 {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case float32:
+		return float64(n)
+	case int:
+		return float64(n)
+	case int64:
+		return float64(n)
+	default:
+		return 0
+	}
+}
+
+// missingArguments returns the required properties of a JSON Schema object absent from args.
+func missingArguments(schema map[string]any, args map[string]any) []string {
+	required, _ := schema["required"].([]string)
+	var missing []string
+	for _, name := range required {
+		if _, ok := args[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// workflowSchemaIDs reads the workflows input. Like sub-workflows, child workflows run in the
+// orchestrator's namespace, so a namespace in an id is dropped.
+func workflowSchemaIDs(input *workflow.FunctionInput) []string {
+	raw := input.GetAnySliceOrDefault("workflows", nil)
+	ids := make([]string, 0, len(raw))
+	seen := make(map[string]struct{}, len(raw))
+	for _, v := range raw {
+		qualified, ok := v.(string)
+		if !ok || qualified == "" {
+			continue
+		}
+		_, id := workflow.SplitQualifiedID(qualified)
+		if _, dup := seen[id]; dup {
+			continue
+		}
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// orchestrateTimeout reads the optional timeout input, defaulting to defaultOrchestrateTimeout.
+func orchestrateTimeout(input *workflow.FunctionInput) (time.Duration, error) {
+	return durationInput(input, "timeout", defaultOrchestrateTimeout, ErrOrchestrateInvalidTimeout)
+}
+
+// orchestrateChildTimeout reads the optional childTimeout input, defaulting to defaultChildTimeout.
+func orchestrateChildTimeout(input *workflow.FunctionInput) (time.Duration, error) {
+	return durationInput(input, "childTimeout", defaultChildTimeout, ErrOrchestrateInvalidChildTimeout)
+}
+
+// durationInput reads a positive duration input, defaulting to def when unset.
+func durationInput(input *workflow.FunctionInput, name string, def time.Duration, invalid error) (time.Duration, error) {
+	raw := input.GetStr(name)
+	if raw == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%w: got %q", invalid, raw)
+	}
+	return d, nil
+}
+
+// clampInt applies a default to unset (non-positive) values and a hard cap.
+func clampInt(v, def, hardMax int) int {
+	if v <= 0 {
+		return def
+	}
+	if v > hardMax {
+		return hardMax
+	}
+	return v
+}
@@ -0,0 +1,235 @@
+package ai
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/open-source-cloud/fuse/internal/packages/functions/system"
+	"github.com/open-source-cloud/fuse/pkg/llm"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeWorkflowCatalog struct {
+	workflows map[string]WorkflowTool
+	depth     int
+}
+
+func (f *fakeWorkflowCatalog) DescribeWorkflow(_, schemaID string) (WorkflowTool, error) {
+	wf, ok := f.workflows[schemaID]
+	if !ok {
+		return WorkflowTool{}, errors.New("graph not found")
+	}
+	return wf, nil
+}
+
+func (f *fakeWorkflowCatalog) Depth(workflow.ID) (int, error) { return f.depth, nil }
+
+var summarizeWorkflow = WorkflowTool{
+	SchemaID:    "summarize",
+	Name:        "Summarize a document",
+	Description: "writes a summary",
+	Input:       []workflow.ParameterSchema{{Name: "url", Type: "string", Required: true}},
+}
+
+func newCatalog() *fakeWorkflowCatalog {
+	return &fakeWorkflowCatalog{workflows: map[string]WorkflowTool{"summarize": summarizeWorkflow}}
+}
+
+// finishedChild answers a workflow call like the engine does when the child workflow finishes.
+func finishedChild(costUSD float64) func(AsyncCall) workflow.FunctionOutput {
+	return func(AsyncCall) workflow.FunctionOutput {
+		return workflow.NewFunctionSuccessOutput(map[string]any{
+			"workflowId": "child-1",
+			"status":     "finished",
+			"output":     map[string]any{"chat": map[string]any{"output": "a summary", "usage": map[string]any{"costUSD": costUSD}}},
+		})
+	}
+}
+
+func runOrchestrate(t *testing.T, prov llm.Provider, runtime ExecRuntime, catalog WorkflowCatalog, input map[string]any, setup func(*workflow.ExecutionInfo)) workflow.FunctionOutput {
+	t.Helper()
+	return runMetered(t, makeOrchestrateFunction(Deps{Providers: registryWith(prov), Usage: NopUsageRecorder{}, Runtime: runtime, Catalog: catalog}), input, setup)
+}
+
+func TestOrchestrate_RunsWhitelistedWorkflowsAsChildren(t *testing.T) {
+	prov := &scriptedProvider{name: "stub", responses: []llm.ChatResponse{
+		toolCallResponse("c1", "workflow__summarize", `{"url":"https://example.com"}`),
+		finalAnswer("done"),
+	}}
+	runtime := &fakeExecRuntime{respond: finishedChild(0.25)}
+
+	out := runOrchestrate(t, prov, runtime, newCatalog(), map[string]any{
+		"input": "summarize example.com", "workflows": []any{"summarize"}, "childTimeout": "1m",
+	}, nil)
+
+	require.Equal(t, workflow.FunctionSuccess, out.Status, out.Data)
+	assert.Equal(t, "done", out.Data["output"])
+
+	require.Len(t, prov.requests[0].Tools, 1)
+	tool := prov.requests[0].Tools[0]
+	assert.Equal(t, "workflow__summarize", tool.Name)
+	assert.Contains(t, tool.Description, "writes a summary")
+	assert.Equal(t, []string{"url"}, tool.Parameters["required"])
+
+	require.Len(t, runtime.calls, 1)
+	call := runtime.calls[0]
+	assert.Equal(t, system.SubWorkflowFullFunctionID, call.FunctionID)
+	assert.Equal(t, map[string]any{"schemaId": "summarize", "input": map[string]any{"url": "https://example.com"}}, call.Input)
+
+	children := out.Data["children"].([]map[string]any)
+	require.Len(t, children, 1)
+	assert.Equal(t, map[string]any{"schemaId": "summarize", "execId": call.ExecID.String(), "workflowId": "child-1", "status": "finished"}, children[0])
+	usage := out.Data["usage"].(map[string]any)
+	assert.InDelta(t, 0.25, usage["childCostUSD"], 1e-9)
+
+	toolMsg, ok := findToolMessage(prov.requests[1].Messages)
+	require.True(t, ok)
+	assert.Contains(t, toolMsg.Content, "a summary")
+}
+
+func TestOrchestrate_RefusesCallsOverTheCaps(t *testing.T) {
+	cases := map[string]struct {
+		calls []llm.ToolCall
+		input map[string]any
+		want  string
+	}{
+		"children": {
+			calls: []llm.ToolCall{
+				{ID: "c1", Name: "workflow__summarize", Arguments: []byte(`{"url":"a"}`)},
+				{ID: "c2", Name: "workflow__summarize", Arguments: []byte(`{"url":"b"}`)},
+			},
+			input: map[string]any{"maxChildren": 1},
+			want:  "child workflow limit reached",
+		},
+		"required input": {
+			calls: []llm.ToolCall{{ID: "c1", Name: "workflow__summarize", Arguments: []byte(`{}`)}, {ID: "c2", Name: "workflow__summarize", Arguments: []byte(`{"url":"b"}`)}},
+			want:  "missing required input: url",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			prov := &scriptedProvider{name: "stub", responses: []llm.ChatResponse{
+				{Message: llm.Message{Role: llm.RoleAssistant, ToolCalls: tc.calls}},
+				finalAnswer("done"),
+			}}
+			runtime := &fakeExecRuntime{respond: finishedChild(0)}
+			input := map[string]any{"input": "go", "workflows": []any{"summarize"}}
+			for k, v := range tc.input {
+				input[k] = v
+			}
+
+			out := runOrchestrate(t, prov, runtime, newCatalog(), input, nil)
+
+			require.Equal(t, workflow.FunctionSuccess, out.Status, out.Data)
+			assert.Len(t, runtime.calls, 1)
+			steps := out.Data["steps"].([]map[string]any)
+			require.Len(t, steps, 2)
+			refused := steps[0]
+			if name == "children" {
+				refused = steps[1]
+			}
+			assert.Contains(t, refused["error"], tc.want)
+			assert.NotContains(t, refused, "execId")
+			assert.Len(t, out.Data["children"], 1)
+		})
+	}
+}
+
+func TestOrchestrate_ChildCostCountsTowardsMaxCost(t *testing.T) {
+	prov := &scriptedProvider{name: "stub", responses: []llm.ChatResponse{
+		toolCallResponse("c1", "workflow__summarize", `{"url":"a"}`),
+		finalAnswer("done"),
+	}}
+	runtime := &fakeExecRuntime{respond: finishedChild(0.5)}
+
+	out := runOrchestrate(t, prov, runtime, newCatalog(), map[string]any{
+		"input": "go", "workflows": []any{"summarize"}, "maxCostUSD": 0.1,
+	}, nil)
+
+	assert.Equal(t, workflow.FunctionError, out.Status)
+	assert.Equal(t, llm.ErrorTypeBudgetExceeded, out.Data["errorType"])
+	assert.Equal(t, 1, prov.calls, "no model call once children spent the budget")
+}
+
+func TestOrchestrate_QuotaRefusedChildFailsNode(t *testing.T) {
+	prov := &scriptedProvider{name: "stub", responses: []llm.ChatResponse{
+		toolCallResponse("c1", "workflow__summarize", `{"url":"a"}`),
+		finalAnswer("done"),
+	}}
+	runtime := &fakeExecRuntime{respond: func(AsyncCall) workflow.FunctionOutput {
+		return workflow.NewFunctionOutput(workflow.FunctionError, map[string]any{
+			"error":     "namespace quota exceeded: namespace billing already runs 5 of 5 concurrent workflows",
+			"errorType": system.ErrorTypeQuotaExceeded,
+		})
+	}}
+
+	out := runOrchestrate(t, prov, runtime, newCatalog(), map[string]any{
+		"input": "go", "workflows": []any{"summarize"},
+	}, nil)
+
+	require.Equal(t, workflow.FunctionError, out.Status)
+	assert.Equal(t, system.ErrorTypeQuotaExceeded, out.Data["errorType"])
+	assert.Contains(t, out.Data["error"], "namespace quota exceeded")
+	assert.Equal(t, 1, prov.calls, "the model is not asked again once the namespace is full")
+}
+
+func TestOrchestrate_FailsBeyondMaxDepth(t *testing.T) {
+	prov := &scriptedProvider{name: "stub"}
+	catalog := newCatalog()
+	catalog.depth = 2
+
+	out := runOrchestrate(t, prov, &fakeExecRuntime{}, catalog, map[string]any{
+		"input": "go", "workflows": []any{"summarize"}, "maxDepth": 2,
+	}, nil)
+
+	assert.Equal(t, workflow.FunctionError, out.Status)
+	assert.Contains(t, out.Data["error"], "exceeds maxDepth 2")
+	assert.Zero(t, prov.calls)
+}
+
+func TestOrchestrate_UnknownWorkflowFails(t *testing.T) {
+	prov := &scriptedProvider{name: "stub"}
+
+	out := runOrchestrate(t, prov, &fakeExecRuntime{}, newCatalog(), map[string]any{
+		"input": "go", "workflows": []any{"summarize", "missing"},
+	}, nil)
+
+	assert.Equal(t, workflow.FunctionError, out.Status)
+	assert.Contains(t, out.Data["error"], `workflow "missing"`)
+	assert.Zero(t, prov.calls)
+}
+
+func TestOrchestrate_InvalidInputReturnsSyncError(t *testing.T) {
+	cases := map[string]struct {
+		input   map[string]any
+		runtime ExecRuntime
+		catalog WorkflowCatalog
+		want    error
+	}{
+		"no input":     {input: map[string]any{"workflows": []any{"summarize"}}, runtime: &fakeExecRuntime{}, catalog: newCatalog(), want: ErrOrchestrateInputRequired},
+		"no workflows": {input: map[string]any{"input": "go"}, runtime: &fakeExecRuntime{}, catalog: newCatalog(), want: ErrOrchestrateWorkflowsRequired},
+		"no runtime":   {input: map[string]any{"input": "go", "workflows": []any{"summarize"}}, catalog: newCatalog(), want: ErrOrchestrateUnavailable},
+		"no catalog":   {input: map[string]any{"input": "go", "workflows": []any{"summarize"}}, runtime: &fakeExecRuntime{}, want: ErrOrchestrateUnavailable},
+		"childTimeout": {input: map[string]any{"input": "go", "workflows": []any{"summarize"}, "childTimeout": "soon"}, runtime: &fakeExecRuntime{}, catalog: newCatalog(), want: ErrOrchestrateInvalidChildTimeout},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fnInput, err := workflow.NewFunctionInputWith(tc.input)
+			require.NoError(t, err)
+			execInfo := workflow.NewExecutionInfo("wf-1", workflow.NewExecID(1), "", fnInput)
+
+			res, err := makeOrchestrateFunction(Deps{Providers: registryWith(&scriptedProvider{name: "stub"}), Usage: NopUsageRecorder{}, Runtime: tc.runtime, Catalog: tc.catalog})(execInfo)
+
+			require.NoError(t, err)
+			assert.False(t, res.Async)
+			assert.Equal(t, workflow.FunctionError, res.Output.Status)
+			assert.Contains(t, res.Output.Data["error"], tc.want.Error())
+		})
+	}
+}
+
+func TestWorkflowToolName(t *testing.T) {
+	assert.Equal(t, "workflow__orders_v2_refund", workflowToolName("orders.v2/refund"))
+}
@@ -1,27 +1,55 @@
-// Package ai provides LLM-backed workflow nodes: ai/chat (a single completion)
-// and, in later phases, ai/agent (a tool-calling reasoning loop). Both slot into
-// the workflow graph as ordinary internal functions.
+// Package ai provides LLM-backed workflow nodes: ai/chat (a single completion),
+// ai/agent (a tool-calling reasoning loop), ai/orchestrate (the same loop running
+// workflows as tools), and the retrieval nodes ai/embed, ai/index and ai/retrieve.
+// All slot into the workflow graph as ordinary internal functions.
 package ai
 
 import (
 	"github.com/open-source-cloud/fuse/pkg/llm"
+	"github.com/open-source-cloud/fuse/pkg/vectorstore"
 	"github.com/open-source-cloud/fuse/pkg/workflow"
 )
 
 // PackageID is the id of the ai function package.
 const PackageID = "fuse/pkg/ai"
 
-// New creates a new ai Package. The LLM provider registry is closed over by the
-// function implementations so they can resolve providers at execution time; the
-// tool registry lets the agent expose existing functions as tools and invoke them;
-// the usage recorder surfaces token usage to observability (ADR-0029).
-func New(providers llm.Registry, tools ToolRegistry, usage UsageRecorder) *workflow.Package {
-	if usage == nil {
-		usage = NopUsageRecorder{}
+// Deps are the services the ai functions are built on. Providers is required; the others may be
+// nil where the functions using them are not run.
+type Deps struct {
+	// Providers are the LLM providers, resolved at execution time.
+	Providers llm.Registry
+	// Tools lets the agent expose existing functions as tools and invoke them.
+	Tools ToolRegistry
+	// Usage surfaces token usage to observability (ADR-0029); nil records nothing.
+	Usage UsageRecorder
+	// Pricing prices LLM calls, and Ledger charges them to schema budgets; a nil Ledger
+	// enforces only per-node limits.
+	Pricing llm.Pricing
+	Ledger  BudgetLedger
+	// Sessions keeps the conversations of nodes that set a sessionId.
+	Sessions SessionStore
+	// Vectors backs ai/index, ai/retrieve and agent retrieval.
+	Vectors vectorstore.VectorStore
+	// Outputs receives the text of ai/chat and ai/agent as it is generated; nil disables streaming.
+	Outputs OutputStream
+	// Runtime lets the agent call asynchronous functions as tools; nil offers synchronous tools only.
+	Runtime ExecRuntime
+	// Catalog describes the workflows ai/orchestrate runs as child workflows through Runtime.
+	Catalog WorkflowCatalog
+}
+
+// New creates a new ai Package over deps.
+func New(deps Deps) *workflow.Package {
+	if deps.Usage == nil {
+		deps.Usage = NopUsageRecorder{}
 	}
 	return workflow.NewPackage(
 		PackageID,
-		workflow.NewFunction(ChatFunctionID, ChatFunctionMetadata(), makeChatFunction(providers, usage)),
-		workflow.NewFunction(AgentFunctionID, AgentFunctionMetadata(), makeAgentFunction(providers, tools, usage)),
+		workflow.NewFunction(ChatFunctionID, ChatFunctionMetadata(), makeChatFunction(deps)),
+		workflow.NewFunction(AgentFunctionID, AgentFunctionMetadata(), makeAgentFunction(deps)),
+		workflow.NewFunction(OrchestrateFunctionID, OrchestrateFunctionMetadata(), makeOrchestrateFunction(deps)),
+		workflow.NewFunction(EmbedFunctionID, EmbedFunctionMetadata(), makeEmbedFunction(deps)),
+		workflow.NewFunction(IndexFunctionID, IndexFunctionMetadata(), makeIndexFunction(deps)),
+		workflow.NewFunction(RetrieveFunctionID, RetrieveFunctionMetadata(), makeRetrieveFunction(deps)),
 	)
 }
@@ -0,0 +1,223 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/open-source-cloud/fuse/pkg/llm"
+	"github.com/open-source-cloud/fuse/pkg/vectorstore"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+)
+
+// embedTimeout bounds an ai/embed, ai/index or ai/retrieve node.
+const embedTimeout = 2 * time.Minute
+
+// embedBatchSize is the number of texts sent in one embedding request.
+const embedBatchSize = 64
+
+var (
+	// ErrEmbeddingsUnsupported is returned when the resolved provider cannot embed text.
+	ErrEmbeddingsUnsupported = errors.New("ai: provider does not support embeddings")
+	// ErrVectorStoreUnavailable is returned when a retrieval node runs but no vector store is wired.
+	ErrVectorStoreUnavailable = errors.New("ai: no vector store is configured")
+	// ErrCollectionRequired is returned when a retrieval node sets no collection.
+	ErrCollectionRequired = errors.New("ai: collection is required")
+)
+
+// embedTexts embeds texts in batches with provider, metering every call like a completion so
+// embeddings count towards cost metrics and budgets. Budgets are checked before each batch.
+func embedTexts(ctx context.Context, provider llm.Provider, req llm.EmbeddingRequest, meter *spendMeter, function string) ([][]float32, llm.Usage, error) {
+	embedder, ok := provider.(llm.EmbeddingProvider)
+	if !ok {
+		return nil, llm.Usage{}, fmt.Errorf("%w: %q", ErrEmbeddingsUnsupported, provider.Name())
+	}
+	vectors := make([][]float32, 0, len(req.Input))
+	var total llm.Usage
+	for start := 0; start < len(req.Input); start += embedBatchSize {
+		if budgetErr := meter.Check(); budgetErr != nil {
+			return nil, total, budgetErr
+		}
+		batch := req
+		batch.Input = req.Input[start:min(start+embedBatchSize, len(req.Input))]
+		resp, err := embedder.Embed(ctx, batch)
+		if err != nil {
+			meter.RecordCall(function, provider.Name(), req.Model, "error")
+			return nil, total, err
+		}
+		model := resp.Model
+		if model == "" {
+			model = req.Model
+		}
+		meter.RecordCall(function, provider.Name(), model, "success")
+		meter.RecordUsage(function, provider.Name(), model, resp.Usage)
+		addUsage(&total, resp.Usage)
+		if len(resp.Vectors) != len(batch.Input) {
+			return nil, total, fmt.Errorf("ai: provider %q returned %d vectors for %d inputs", provider.Name(), len(resp.Vectors), len(batch.Input))
+		}
+		vectors = append(vectors, resp.Vectors...)
+	}
+	return vectors, total, nil
+}
+
+// newRetriever validates the retrieval settings of a node.
+func newRetriever(store vectorstore.VectorStore, execInfo *workflow.ExecutionInfo, collection string, topK int, minScore float64, req llm.EmbeddingRequest) (*retriever, error) {
+	if store == nil {
+		return nil, ErrVectorStoreUnavailable
+	}
+	if collection == "" {
+		return nil, ErrCollectionRequired
+	}
+	if topK <= 0 {
+		return nil, fmt.Errorf("ai: topK must be positive, got %d", topK)
+	}
+	return &retriever{
+		store:      store,
+		namespace:  workflow.NamespaceOf(execInfo.SchemaID),
+		collection: collection,
+		topK:       topK,
+		minScore:   minScore,
+		req:        req,
+	}, nil
+}
+
+// optionalFloat reads a numeric input, returning 0 when it is absent.
+func optionalFloat(input *workflow.FunctionInput, key string) (float64, error) {
+	switch v := input.Get(key).(type) {
+	case nil:
+		return 0, nil
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	default:
+		return 0, fmt.Errorf("ai: %s must be a number, got %T", key, v)
+	}
+}
+
+// intOrDefault reads an int input, returning def when it is absent or zero.
+func intOrDefault(input *workflow.FunctionInput, key string, def int) int {
+	if v := input.GetInt(key); v != 0 {
+		return v
+	}
+	return def
+}
+
+// embeddingRequest reads the model and dimensions inputs shared by the retrieval nodes.
+func embeddingRequest(input *workflow.FunctionInput) llm.EmbeddingRequest {
+	return llm.EmbeddingRequest{Model: input.GetStr("model"), Dimensions: input.GetInt("dimensions")}
+}
+
+// retriever finds the chunks of a collection nearest to a query. It backs ai/retrieve and the
+// agent's retrieveFrom option (ADR-0028 Option C).
+type retriever struct {
+	store      vectorstore.VectorStore
+	namespace  string
+	collection string
+	topK       int
+	minScore   float64
+	req        llm.EmbeddingRequest
+}
+
+// retrieve embeds query and returns the matches scoring at least minScore.
+func (r *retriever) retrieve(ctx context.Context, provider llm.Provider, query string, meter *spendMeter, function string) ([]vectorstore.Match, llm.Usage, error) {
+	req := r.req
+	req.Input = []string{query}
+	vectors, usage, err := embedTexts(ctx, provider, req, meter, function)
+	if err != nil {
+		return nil, usage, err
+	}
+	matches, err := r.store.Query(ctx, r.namespace, r.collection, vectors[0], r.topK)
+	if err != nil {
+		return nil, usage, fmt.Errorf("query collection %q: %w", r.collection, err)
+	}
+	kept := matches[:0]
+	for _, m := range matches {
+		if m.Score >= r.minScore {
+			kept = append(kept, m)
+		}
+	}
+	return kept, usage, nil
+}
+
+// matchesData converts matches to the node output form.
+func matchesData(matches []vectorstore.Match) []map[string]any {
+	out := make([]map[string]any, 0, len(matches))
+	for _, m := range matches {
+		item := map[string]any{"id": m.ID, "content": m.Content, "score": m.Score}
+		if len(m.Metadata) > 0 {
+			item["metadata"] = m.Metadata
+		}
+		out = append(out, item)
+	}
+	return out
+}
+
+// retrievedContext joins the matched chunks into one numbered block of text for a prompt.
+func retrievedContext(matches []vectorstore.Match) string {
+	parts := make([]string, 0, len(matches))
+	for i, m := range matches {
+		parts = append(parts, fmt.Sprintf("[%d] %s", i+1, m.Content))
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// retrievedStep is the retrieveFrom lookup of an agent run: its trace step and embedding usage.
+type retrievedStep struct {
+	step  map[string]any
+	usage llm.Usage
+}
+
+// agentRetriever reads the agent's retrieveFrom inputs; it returns nil when retrieveFrom is unset.
+func agentRetriever(store vectorstore.VectorStore, execInfo *workflow.ExecutionInfo) (*retriever, error) {
+	input := execInfo.Input
+	collection := input.GetStr("retrieveFrom")
+	if collection == "" {
+		return nil, nil
+	}
+	minScore, err := optionalFloat(input, "retrieveMinScore")
+	if err != nil {
+		return nil, err
+	}
+	return newRetriever(store, execInfo, collection, intOrDefault(input, "retrieveTopK", defaultAgentRetrieveTopK), minScore,
+		llm.EmbeddingRequest{Model: input.GetStr("embeddingModel")})
+}
+
+// retrieveContext looks up the chunks nearest to the task and adds them as a system turn after the
+// leading system turns, where context trimming never drops them (ADR-0028 Option C). A budget that
+// is already exceeded skips the lookup and is reported by the loop's own check.
+func (e *agentExecutor) retrieveContext(ctx context.Context, r *retriever, embedder llm.Provider, messages []llm.Message, task string) ([]llm.Message, error) {
+	matches, u, err := r.retrieve(ctx, embedder, task, e.meter, e.function)
+	var budgetErr *llm.BudgetExceededError
+	if errors.As(err, &budgetErr) {
+		return messages, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(matches))
+	for _, m := range matches {
+		ids = append(ids, m.ID)
+	}
+	e.retrieved = &retrievedStep{
+		step:  map[string]any{"retrieval": r.collection, "matches": ids},
+		usage: u,
+	}
+	if len(matches) == 0 {
+		return messages, nil
+	}
+
+	h := 0
+	for h < len(messages) && messages[h].Role == llm.RoleSystem {
+		h++
+	}
+	turn := llm.Message{
+		Role:    llm.RoleSystem,
+		Content: fmt.Sprintf("Relevant context retrieved from %q:\n\n%s", r.collection, retrievedContext(matches)),
+	}
+	return concatMessages(messages[:h], append([]llm.Message{turn}, messages[h:]...)), nil
+}
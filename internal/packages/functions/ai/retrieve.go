@@ -0,0 +1,105 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/open-source-cloud/fuse/internal/packages/transport"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+	"github.com/rs/zerolog/log"
+)
+
+// RetrieveFunctionID is the id of the retrieve function.
+const RetrieveFunctionID = "retrieve"
+
+// defaultRetrieveTopK is the number of chunks ai/retrieve returns when the node sets no topK.
+const defaultRetrieveTopK = 5
+
+// ErrRetrieveQueryRequired is returned when the query input is missing.
+var ErrRetrieveQueryRequired = errors.New("ai/retrieve: query is required")
+
+// RetrieveFunctionMetadata returns the metadata for the retrieve function.
+func RetrieveFunctionMetadata() workflow.FunctionMetadata {
+	return workflow.FunctionMetadata{
+		Transport: transport.Internal,
+		Input: workflow.InputMetadata{
+			CustomParameters: false,
+			Parameters: []workflow.ParameterSchema{
+				{Name: "collection", Type: "string", Required: true, Description: "Collection to search, scoped to the workflow's namespace"},
+				{Name: "query", Type: "string", Required: true, Description: "Text to find related chunks for"},
+				{Name: "topK", Type: "int", Required: false, Default: defaultRetrieveTopK, Description: "Maximum number of chunks returned"},
+				{Name: "minScore", Type: "float", Required: false, Description: "Minimum cosine similarity (-1 to 1) of a returned chunk"},
+				{Name: "provider", Type: "string", Required: false, Description: "Provider registry key; it must support embeddings. Defaults to the configured default provider"},
+				{Name: "model", Type: "string", Required: false, Description: "Embedding model id; must be the model the collection was indexed with. Defaults to the provider's EMBEDDING_MODEL"},
+				{Name: "dimensions", Type: "int", Required: false, Description: "Requested vector length; must match the collection's"},
+				{Name: "maxCostUSD", Type: "float", Required: false, Description: "Optional USD cap for this node's embedding calls, priced from LLM_PRICING"},
+				{Name: "maxTokens", Type: "int", Required: false, Description: "Optional cap on the total tokens of this node's embedding calls"},
+			},
+			Edges: workflow.InputEdgeMetadata{
+				Count:      0,
+				Parameters: make([]workflow.ParameterSchema, 0),
+			},
+		},
+		Output: workflow.OutputMetadata{
+			Parameters: []workflow.ParameterSchema{
+				{Name: "matches", Type: "array", Required: true, Description: "Matched chunks, most similar first: id, content, metadata, score"},
+				{Name: "context", Type: "string", Required: true, Description: "The matched contents as numbered passages, ready to place in a prompt"},
+				{Name: "usage", Type: "map", Required: false, Description: "Token usage and its priced cost (costUSD)"},
+			},
+			Edges: make([]workflow.OutputEdgeMetadata, 0),
+		},
+	}
+}
+
+// makeRetrieveFunction builds the ai/retrieve function: it embeds the query and returns the
+// nearest chunks of a collection.
+func makeRetrieveFunction(deps Deps) workflow.Function {
+	return func(execInfo *workflow.ExecutionInfo) (workflow.FunctionResult, error) {
+		input := execInfo.Input
+
+		query := input.GetStr("query")
+		if query == "" {
+			return workflow.NewFunctionResultError(ErrRetrieveQueryRequired)
+		}
+		minScore, err := optionalFloat(input, "minScore")
+		if err != nil {
+			return workflow.NewFunctionResultError(err)
+		}
+		r, err := newRetriever(deps.Vectors, execInfo, input.GetStr("collection"),
+			intOrDefault(input, "topK", defaultRetrieveTopK), minScore, embeddingRequest(input))
+		if err != nil {
+			return workflow.NewFunctionResultError(err)
+		}
+		limit, err := nodeBudgetLimit(input)
+		if err != nil {
+			return workflow.NewFunctionResultError(err)
+		}
+		providerName := input.GetStr("provider")
+
+		go func() {
+			ctx, cancel := context.WithTimeout(execInfo.Ctx(), embedTimeout)
+			defer cancel()
+			meter := newSpendMeter(ctx, deps.Usage, deps.Pricing, deps.Ledger, execInfo, limit)
+
+			provider, err := resolveProvider(ctx, deps.Providers, execInfo.Environment, providerName)
+			if err != nil {
+				log.Error().Err(err).Str("provider", providerName).Msg("ai/retrieve provider resolution failed")
+				execInfo.Finish(errorOutput(fmt.Sprintf("ai/retrieve: provider resolution failed: %v", err)))
+				return
+			}
+			matches, u, err := r.retrieve(ctx, provider, query, meter, RetrieveFunctionID)
+			if out, failed := embeddingFailure(RetrieveFunctionID, err, u, meter); failed {
+				execInfo.Finish(out)
+				return
+			}
+			execInfo.Finish(workflow.NewFunctionSuccessOutput(map[string]any{
+				"matches": matchesData(matches),
+				"context": retrievedContext(matches),
+				"usage":   usageData(u, meter.Cost()),
+			}))
+		}()
+
+		return workflow.NewFunctionResultAsync(), nil
+	}
+}
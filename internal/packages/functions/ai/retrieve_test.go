@@ -0,0 +1,202 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/open-source-cloud/fuse/pkg/llm"
+	"github.com/open-source-cloud/fuse/pkg/vectorstore"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// keywordEmbedder is a chat-capable provider that embeds a text as the counts of a few keywords,
+// so related texts have close vectors.
+type keywordEmbedder struct {
+	scriptedProvider
+	mu       sync.Mutex
+	embedded [][]string
+}
+
+var embedKeywords = []string{"refund", "shipping", "password"}
+
+func (k *keywordEmbedder) Embed(_ context.Context, req llm.EmbeddingRequest) (llm.EmbeddingResponse, error) {
+	k.mu.Lock()
+	k.embedded = append(k.embedded, req.Input)
+	k.mu.Unlock()
+	resp := llm.EmbeddingResponse{Model: "embed-1"}
+	for _, text := range req.Input {
+		v := make([]float32, len(embedKeywords))
+		for i, kw := range embedKeywords {
+			v[i] = float32(strings.Count(strings.ToLower(text), kw))
+		}
+		resp.Vectors = append(resp.Vectors, v)
+		resp.Usage.PromptTokens += len(strings.Fields(text))
+	}
+	resp.Usage.TotalTokens = resp.Usage.PromptTokens
+	return resp, nil
+}
+
+func newKeywordEmbedder(responses ...llm.ChatResponse) *keywordEmbedder {
+	return &keywordEmbedder{scriptedProvider: scriptedProvider{name: "stub", responses: responses}}
+}
+
+func TestIndexThenRetrieve_FindsTheRelatedChunk(t *testing.T) {
+	prov := newKeywordEmbedder()
+	store := vectorstore.NewMemoryVectorStore()
+	inSchema := func(e *workflow.ExecutionInfo) { e.SchemaID = "support:bot" }
+
+	out := runMetered(t, makeIndexFunction(Deps{Providers: registryWith(prov), Usage: NopUsageRecorder{}, Vectors: store}), map[string]any{
+		"collection": "faq",
+		"documents": []any{
+			map[string]any{"id": "refunds", "content": "A refund is issued within 5 days.", "metadata": map[string]any{"source": "faq.md"}},
+			"Shipping is free above 50 EUR.",
+		},
+	}, inSchema)
+	require.Equal(t, workflow.FunctionSuccess, out.Status, out.Data)
+	assert.Equal(t, 2, out.Data["chunks"])
+	assert.Contains(t, out.Data["ids"], "refunds#0")
+
+	out = runMetered(t, makeRetrieveFunction(Deps{Providers: registryWith(prov), Usage: NopUsageRecorder{}, Vectors: store}),
+		map[string]any{"collection": "faq", "query": "when do I get my refund?", "topK": 1}, inSchema)
+
+	require.Equal(t, workflow.FunctionSuccess, out.Status, out.Data)
+	matches, ok := out.Data["matches"].([]map[string]any)
+	require.True(t, ok)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "refunds#0", matches[0]["id"])
+	assert.Equal(t, map[string]any{"source": "faq.md", "documentId": "refunds", "chunk": 0}, matches[0]["metadata"])
+	assert.Equal(t, "[1] A refund is issued within 5 days.", out.Data["context"])
+
+	other, err := store.Query(context.Background(), "default", "faq", []float32{1, 0, 0}, 5)
+	require.NoError(t, err)
+	assert.Empty(t, other, "collections are scoped by the schema's namespace")
+}
+
+func TestRetrieve_MinScoreFiltersWeakMatches(t *testing.T) {
+	store := vectorstore.NewMemoryVectorStore()
+	require.NoError(t, store.Upsert(context.Background(), "default", "faq", []vectorstore.Chunk{
+		{ID: "a", Content: "refund", Vector: []float32{1, 0, 0}},
+		{ID: "b", Content: "mixed", Vector: []float32{1, 1, 1}},
+	}))
+
+	out := runMetered(t, makeRetrieveFunction(Deps{Providers: registryWith(newKeywordEmbedder()), Usage: NopUsageRecorder{}, Vectors: store}),
+		map[string]any{"collection": "faq", "query": "refund", "minScore": 0.9}, nil)
+
+	require.Equal(t, workflow.FunctionSuccess, out.Status, out.Data)
+	assert.Len(t, out.Data["matches"], 1)
+}
+
+func TestEmbed_ReturnsVectorsAndPricesUsage(t *testing.T) {
+	prov := newKeywordEmbedder()
+	rec := &fakeUsageRecorder{}
+
+	out := runMetered(t, makeEmbedFunction(Deps{Providers: registryWith(prov), Usage: rec, Pricing: stubPricing}),
+		map[string]any{"input": "refund refund shipping"}, nil)
+
+	require.Equal(t, workflow.FunctionSuccess, out.Status, out.Data)
+	assert.Equal(t, []float32{2, 1, 0}, out.Data["vector"])
+	assert.Equal(t, [][]float32{{2, 1, 0}}, out.Data["vectors"])
+	usage, _ := out.Data["usage"].(map[string]any)
+	assert.Equal(t, 3, usage["promptTokens"])
+	assert.InDelta(t, 3.0/1e6, usage["costUSD"], 1e-12)
+	usages, statuses := rec.snapshot()
+	assert.Len(t, usages, 1)
+	assert.Equal(t, []string{"success"}, statuses)
+}
+
+func TestEmbed_BatchesLargeInputs(t *testing.T) {
+	prov := newKeywordEmbedder()
+	texts := make([]any, embedBatchSize+1)
+	for i := range texts {
+		texts[i] = "refund"
+	}
+
+	out := runMetered(t, makeEmbedFunction(Deps{Providers: registryWith(prov), Usage: NopUsageRecorder{}}), map[string]any{"inputs": texts}, nil)
+
+	require.Equal(t, workflow.FunctionSuccess, out.Status, out.Data)
+	assert.Len(t, out.Data["vectors"], embedBatchSize+1)
+	assert.Len(t, prov.embedded, 2)
+	assert.NotContains(t, out.Data, "vector")
+}
+
+func TestEmbed_ProviderWithoutEmbeddingsFails(t *testing.T) {
+	out := runMetered(t, makeEmbedFunction(Deps{Providers: registryWith(&stubProvider{name: "stub"}), Usage: NopUsageRecorder{}}),
+		map[string]any{"input": "hi"}, nil)
+
+	assert.Equal(t, workflow.FunctionError, out.Status)
+	assert.Contains(t, out.Data["error"], ErrEmbeddingsUnsupported.Error())
+}
+
+func TestRetrievalNodes_InvalidInputs(t *testing.T) {
+	prov := newKeywordEmbedder()
+	store := vectorstore.NewMemoryVectorStore()
+	cases := map[string]struct {
+		fn    workflow.Function
+		input map[string]any
+		want  string
+	}{
+		"embed without input":         {makeEmbedFunction(Deps{Providers: registryWith(prov)}), map[string]any{}, ErrEmbedInputRequired.Error()},
+		"index without store":         {makeIndexFunction(Deps{Providers: registryWith(prov)}), map[string]any{"collection": "c", "input": "x"}, ErrVectorStoreUnavailable.Error()},
+		"index without docs":          {makeIndexFunction(Deps{Providers: registryWith(prov), Vectors: store}), map[string]any{"collection": "c"}, ErrIndexDocumentsRequired.Error()},
+		"index bad overlap":           {makeIndexFunction(Deps{Providers: registryWith(prov), Vectors: store}), map[string]any{"collection": "c", "input": "x", "chunkSize": 10, "chunkOverlap": 10}, "chunkOverlap"},
+		"retrieve without query":      {makeRetrieveFunction(Deps{Providers: registryWith(prov), Vectors: store}), map[string]any{"collection": "c"}, ErrRetrieveQueryRequired.Error()},
+		"retrieve without collection": {makeRetrieveFunction(Deps{Providers: registryWith(prov), Vectors: store}), map[string]any{"query": "q"}, ErrCollectionRequired.Error()},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fnInput, err := workflow.NewFunctionInputWith(tc.input)
+			require.NoError(t, err)
+
+			res, err := tc.fn(workflow.NewExecutionInfo("wf-1", "exec-1", "", fnInput))
+
+			require.NoError(t, err)
+			assert.False(t, res.Async)
+			assert.Equal(t, workflow.FunctionError, res.Output.Status)
+			assert.Contains(t, res.Output.Data["error"], tc.want)
+		})
+	}
+}
+
+func TestChunkText(t *testing.T) {
+	chunks := chunkText("The quick brown fox jumps over the lazy dog and keeps running far away", 20, 8)
+
+	assert.Equal(t, []string{"The quick brown fox", "fox jumps over the", "over the lazy dog", "lazy dog and keeps", "keeps running far", "far away"}, chunks)
+	assert.Equal(t, []string{"short"}, chunkText("  short  ", 100, 10))
+	assert.Empty(t, chunkText("   ", 100, 10))
+}
+
+func TestAgent_RetrieveFromAddsContextAfterSystemPrompt(t *testing.T) {
+	prov := newKeywordEmbedder(finalAnswer("Refunds take 5 days."))
+	store := vectorstore.NewMemoryVectorStore()
+	require.NoError(t, store.Upsert(context.Background(), "default", "faq", []vectorstore.Chunk{
+		{ID: "refunds#0", Content: "A refund is issued within 5 days.", Vector: []float32{1, 0, 0}},
+		{ID: "shipping#0", Content: "Shipping is free.", Vector: []float32{0, 1, 0}},
+	}))
+
+	out := runMetered(t, makeAgentFunction(Deps{Providers: registryWith(prov), Tools: &fakeToolRegistry{}, Usage: NopUsageRecorder{}, Vectors: store}),
+		map[string]any{"input": "how long does a refund take?", "systemPrompt": "be brief", "retrieveFrom": "faq", "retrieveTopK": 1}, nil)
+
+	require.Equal(t, workflow.FunctionSuccess, out.Status, out.Data)
+	messages := prov.requests[0].Messages
+	require.Len(t, messages, 3)
+	assert.Equal(t, "be brief", messages[0].Content)
+	assert.Equal(t, llm.RoleSystem, messages[1].Role)
+	assert.Contains(t, messages[1].Content, "A refund is issued within 5 days.")
+	assert.NotContains(t, messages[1].Content, "Shipping")
+	steps, _ := out.Data["steps"].([]map[string]any)
+	require.Len(t, steps, 1)
+	assert.Equal(t, map[string]any{"retrieval": "faq", "matches": []string{"refunds#0"}}, steps[0])
+	usage, _ := out.Data["usage"].(map[string]any)
+	assert.Equal(t, 6+1, usage["promptTokens"], "the embedding of the input counts towards usage")
+}
+
+func TestAgent_RetrieveFromWithoutStoreFails(t *testing.T) {
+	res, _ := runAgent(t, registryWith(newKeywordEmbedder()), &fakeToolRegistry{}, map[string]any{"input": "x", "retrieveFrom": "faq"})
+
+	assert.False(t, res.Async)
+	assert.Contains(t, res.Output.Data["error"], ErrVectorStoreUnavailable.Error())
+}
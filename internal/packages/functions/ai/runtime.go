@@ -0,0 +1,37 @@
+package ai
+
+import (
+	"context"
+	"time"
+
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+)
+
+// ExecRuntime runs functions that complete asynchronously — intercepted system functions,
+// sub-workflows, timers, other ai nodes — as tools of a running node (ADR-0027). It is the async
+// counterpart of ToolRegistry: instead of returning a result inline, each call becomes a child
+// execution of the calling node that the workflow engine runs and journals, and its output is
+// routed back to the caller by exec id.
+//
+// Like ToolRegistry it is declared here and implemented in package packages, which can reach the
+// actor system.
+type ExecRuntime interface {
+	// InvokeAsync starts call and returns a channel that receives the call's output once. The
+	// engine ends the call with an error output when call.Timeout elapses. When ctx is done the
+	// caller has stopped waiting and the channel is abandoned.
+	InvokeAsync(ctx context.Context, call AsyncCall) (<-chan workflow.FunctionOutput, error)
+}
+
+// AsyncCall is one asynchronous tool call made by a running execution.
+type AsyncCall struct {
+	// Namespace is the calling workflow's namespace, which addresses its handler.
+	Namespace  string
+	WorkflowID workflow.ID
+	// ParentExecID is the execution making the call.
+	ParentExecID workflow.ExecID
+	// ExecID is the child execution the call runs as, allocated by the caller on the parent's thread.
+	ExecID     workflow.ExecID
+	FunctionID string
+	Input      map[string]any
+	Timeout    time.Duration
+}
@@ -0,0 +1,196 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/open-source-cloud/fuse/pkg/llm"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeExecRuntime answers each async call with respond, or never when respond is nil.
+type fakeExecRuntime struct {
+	mu      sync.Mutex
+	respond func(AsyncCall) workflow.FunctionOutput
+	err     error
+	calls   []AsyncCall
+	ctxs    []context.Context
+}
+
+func (f *fakeExecRuntime) InvokeAsync(ctx context.Context, call AsyncCall) (<-chan workflow.FunctionOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, call)
+	f.ctxs = append(f.ctxs, ctx)
+	if f.err != nil {
+		return nil, f.err
+	}
+	results := make(chan workflow.FunctionOutput, 1)
+	if f.respond != nil {
+		results <- f.respond(call)
+	}
+	return results, nil
+}
+
+var chatToolDescriptor = ToolDescriptor{
+	FunctionID:  "fuse/pkg/ai/chat",
+	MangledName: "fuse__pkg__ai__chat",
+	Description: "chat",
+	Parameters:  map[string]any{"type": "object", "properties": map[string]any{"input": map[string]any{"type": "string"}}},
+	Async:       true,
+}
+
+func runAgentWithRuntime(t *testing.T, prov llm.Provider, tools ToolRegistry, runtime ExecRuntime, input map[string]any) workflow.FunctionOutput {
+	t.Helper()
+	return runMetered(t, makeAgentFunction(Deps{Providers: registryWith(prov), Tools: tools, Usage: NopUsageRecorder{}, Runtime: runtime}), input, nil)
+}
+
+func TestAgent_AsyncToolRunsAsAChildExecution(t *testing.T) {
+	prov := &scriptedProvider{name: "stub", responses: []llm.ChatResponse{
+		toolCallResponse("c1", "fuse__pkg__ai__chat", `{"input":"summarize"}`),
+		finalAnswer("done"),
+	}}
+	tools := &fakeToolRegistry{descriptors: []ToolDescriptor{chatToolDescriptor}}
+	runtime := &fakeExecRuntime{respond: func(AsyncCall) workflow.FunctionOutput {
+		return workflow.NewFunctionSuccessOutput(map[string]any{"output": "a summary"})
+	}}
+
+	out := runAgentWithRuntime(t, prov, tools, runtime, map[string]any{"input": "go", "toolTimeout": "30s"})
+
+	require.Equal(t, workflow.FunctionSuccess, out.Status)
+	assert.Empty(t, tools.invoked, "async tools are not invoked inline")
+	require.Len(t, runtime.calls, 1)
+	call := runtime.calls[0]
+	assert.Equal(t, workflow.ID("wf-1"), call.WorkflowID)
+	assert.Equal(t, "fuse/pkg/ai/chat", call.FunctionID)
+	assert.Equal(t, map[string]any{"input": "summarize"}, call.Input)
+	assert.Equal(t, 30*time.Second, call.Timeout)
+	assert.NotEqual(t, call.ParentExecID, call.ExecID)
+	assert.Equal(t, call.ParentExecID.Thread(), call.ExecID.Thread(), "the child runs on the parent's thread")
+	assert.Error(t, runtime.ctxs[0].Err(), "the wait ends with the tool call")
+
+	steps := out.Data["steps"].([]map[string]any)
+	require.Len(t, steps, 1)
+	assert.Equal(t, call.ExecID.String(), steps[0]["execId"])
+	assert.Equal(t, map[string]any{"output": "a summary"}, steps[0]["result"])
+	toolMsg, ok := findToolMessage(prov.requests[1].Messages)
+	require.True(t, ok)
+	assert.JSONEq(t, `{"output":"a summary"}`, toolMsg.Content)
+}
+
+func TestAgent_AsyncToolErrorsAreFedBack(t *testing.T) {
+	cases := map[string]struct {
+		runtime *fakeExecRuntime
+		want    string
+	}{
+		"timeout": {
+			runtime: &fakeExecRuntime{respond: func(AsyncCall) workflow.FunctionOutput {
+				return workflow.NewFunctionOutput(workflow.FunctionError, map[string]any{"error": "tool call timeout exceeded"})
+			}},
+			want: "tool call timeout exceeded",
+		},
+		"dispatch": {
+			runtime: &fakeExecRuntime{err: errors.New("handler gone")},
+			want:    "async tool call failed: handler gone",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			prov := &scriptedProvider{name: "stub", responses: []llm.ChatResponse{
+				toolCallResponse("c1", "fuse__pkg__ai__chat", `{}`),
+				finalAnswer("recovered"),
+			}}
+			tools := &fakeToolRegistry{descriptors: []ToolDescriptor{chatToolDescriptor}}
+
+			out := runAgentWithRuntime(t, prov, tools, tc.runtime, map[string]any{"input": "go"})
+
+			require.Equal(t, workflow.FunctionSuccess, out.Status)
+			assert.Equal(t, "recovered", out.Data["output"])
+			steps := out.Data["steps"].([]map[string]any)
+			require.Len(t, steps, 1)
+			assert.Contains(t, steps[0]["error"], tc.want)
+			assert.NotEmpty(t, steps[0]["execId"])
+		})
+	}
+}
+
+func TestAgent_AsyncToolWaitEndsWithTheRun(t *testing.T) {
+	prov := &scriptedProvider{name: "stub", responses: []llm.ChatResponse{toolCallResponse("c1", "fuse__pkg__ai__chat", `{}`)}}
+	tools := &fakeToolRegistry{descriptors: []ToolDescriptor{chatToolDescriptor}}
+	runtime := &fakeExecRuntime{} // never answers
+
+	out := runAgentWithRuntime(t, prov, tools, runtime, map[string]any{"input": "go", "timeout": "50ms"})
+
+	assert.Equal(t, workflow.FunctionError, out.Status)
+	assert.Contains(t, out.Data["error"], "stopped")
+}
+
+func TestAgent_AsyncToolsHiddenWithoutARuntime(t *testing.T) {
+	prov := &scriptedProvider{name: "stub", responses: []llm.ChatResponse{finalAnswer("ok")}}
+	tools := &fakeToolRegistry{descriptors: []ToolDescriptor{sumDescriptor, chatToolDescriptor}}
+
+	out := runAgentWithRuntime(t, prov, tools, nil, map[string]any{"input": "go"})
+
+	require.Equal(t, workflow.FunctionSuccess, out.Status)
+	require.Len(t, prov.requests[0].Tools, 1)
+	assert.Equal(t, sumDescriptor.MangledName, prov.requests[0].Tools[0].Name)
+}
+
+func TestAgent_InvalidToolTimeoutReturnsSyncError(t *testing.T) {
+	prov := &scriptedProvider{name: "stub"}
+	res, _ := runAgent(t, registryWith(prov), &fakeToolRegistry{}, map[string]any{"input": "hi", "toolTimeout": "0s"})
+	assert.Equal(t, workflow.FunctionError, res.Output.Status)
+	assert.Contains(t, res.Output.Data["error"], "toolTimeout")
+}
+
+func TestAgent_ResumedDecisionRepeatsAsyncCallsUnderTheirExecIDs(t *testing.T) {
+	tools := &fakeToolRegistry{descriptors: []ToolDescriptor{chatToolDescriptor}}
+	first := &scriptedProvider{name: "stub", responses: []llm.ChatResponse{toolCallResponse("c1", "fuse__pkg__ai__chat", `{"input":"summarize"}`)}}
+	interrupted := &fakeExecRuntime{} // the run ends while the child execution runs
+	var checkpoints []map[string]any
+	runMetered(t, makeAgentFunction(Deps{Providers: registryWith(first), Tools: tools, Usage: NopUsageRecorder{}, Runtime: interrupted}),
+		map[string]any{"input": "go", "timeout": "50ms"},
+		func(e *workflow.ExecutionInfo) {
+			e.Checkpoint = func(data map[string]any) { checkpoints = append(checkpoints, data) }
+		})
+	require.Len(t, checkpoints, 1, "the decision is journaled before the async call starts")
+	decision, err := decodeCheckpoint(checkpoints[0])
+	require.NoError(t, err)
+	assert.True(t, decision.Pending)
+	require.Len(t, interrupted.calls, 1)
+	assert.Equal(t, []string{interrupted.calls[0].ExecID.String()}, decision.ExecIDs)
+
+	replayed := &scriptedProvider{name: "stub", responses: []llm.ChatResponse{finalAnswer("done")}}
+	runtime := &fakeExecRuntime{respond: func(AsyncCall) workflow.FunctionOutput {
+		return workflow.NewFunctionSuccessOutput(map[string]any{"output": "a summary"})
+	}}
+	var more []map[string]any
+	out := runMetered(t, makeAgentFunction(Deps{Providers: registryWith(replayed), Tools: tools, Usage: NopUsageRecorder{}, Runtime: runtime}),
+		map[string]any{"input": "go"},
+		func(e *workflow.ExecutionInfo) {
+			e.Checkpoints = checkpoints
+			e.Checkpoint = func(data map[string]any) { more = append(more, data) }
+		})
+
+	require.Equal(t, workflow.FunctionSuccess, out.Status, out.Data)
+	require.Len(t, replayed.requests, 1, "the decided iteration does not query the model again")
+	require.Len(t, runtime.calls, 1)
+	assert.Equal(t, interrupted.calls[0].ExecID, runtime.calls[0].ExecID)
+	require.Len(t, more, 1)
+	completed, err := decodeCheckpoint(more[0])
+	require.NoError(t, err)
+	assert.False(t, completed.Pending)
+	assert.Equal(t, 0, completed.Iteration)
+	assert.Equal(t, 6, out.Data["usage"].(map[string]any)["totalTokens"], "the decision's usage is carried over")
+
+	// a completed checkpoint supersedes its decision
+	state, err := (&agentExecutor{function: AgentFunctionID, checkpoints: append(checkpoints, more...)}).resume(nil)
+	require.NoError(t, err)
+	assert.Nil(t, state.decision)
+	assert.Equal(t, 1, state.iteration)
+}
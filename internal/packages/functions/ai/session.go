@@ -0,0 +1,142 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/open-source-cloud/fuse/pkg/llm"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// defaultSessionMaxTokens bounds a session transcript when the node sets no sessionMaxTokens.
+	defaultSessionMaxTokens = 8000
+	// maxSessionIDLength matches the width of the stored session key.
+	maxSessionIDLength = 128
+)
+
+var (
+	// ErrSessionsUnavailable is returned when a node sets sessionId but no session store is wired.
+	ErrSessionsUnavailable = errors.New("ai: sessionId is set but no session store is configured")
+	// ErrInvalidSessionID is returned when sessionId is longer than maxSessionIDLength.
+	ErrInvalidSessionID = fmt.Errorf("ai: sessionId must be at most %d characters", maxSessionIDLength)
+	// ErrInvalidSessionTTL is returned when the sessionTTL input is not a positive duration.
+	ErrInvalidSessionTTL = errors.New("ai: sessionTTL must be a positive duration (e.g. 24h, 720h)")
+)
+
+// SessionStore persists the conversation shared by the runs of one sessionId (ADR-0028 Option B).
+// Sessions are scoped by the namespace of the running workflow.
+type SessionStore interface {
+	// Load returns the session's transcript, or nil for a new or expired session.
+	Load(ctx context.Context, namespace, id string) ([]llm.Message, error)
+	// Save replaces the session's transcript and keeps it for ttl after this run; ttl 0 uses the
+	// store's default.
+	Save(ctx context.Context, namespace, id string, messages []llm.Message, ttl time.Duration) error
+}
+
+// session binds one run to its persisted conversation. The run sees the stored history between its
+// system prompt and its input; on success its input and final answer are appended, and the
+// transcript is bounded to maxTokens with the node's context strategy before it is saved.
+type session struct {
+	store     SessionStore
+	namespace string
+	id        string
+	ttl       time.Duration
+	maxTokens int
+	strategy  string
+	history   []llm.Message
+}
+
+// sessionFromInput reads the session inputs of a node; it returns nil when sessionId is not set.
+func sessionFromInput(store SessionStore, execInfo *workflow.ExecutionInfo) (*session, error) {
+	input := execInfo.Input
+	id := input.GetStr("sessionId")
+	if id == "" {
+		return nil, nil
+	}
+	if store == nil {
+		return nil, ErrSessionsUnavailable
+	}
+	if len(id) > maxSessionIDLength {
+		return nil, ErrInvalidSessionID
+	}
+	var ttl time.Duration
+	if raw := input.GetStr("sessionTTL"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("%w: got %q", ErrInvalidSessionTTL, raw)
+		}
+		ttl = d
+	}
+	maxTokens := input.GetInt("sessionMaxTokens")
+	if maxTokens <= 0 {
+		maxTokens = defaultSessionMaxTokens
+	}
+	return &session{
+		store:     store,
+		namespace: workflow.NamespaceOf(execInfo.SchemaID),
+		id:        id,
+		ttl:       ttl,
+		maxTokens: maxTokens,
+		strategy:  contextStrategyOrDefault(input.GetStr("contextStrategy")),
+	}, nil
+}
+
+// load reads the stored history.
+func (s *session) load(ctx context.Context) error {
+	history, err := s.store.Load(ctx, s.namespace, s.id)
+	if err != nil {
+		return fmt.Errorf("load session %q: %w", s.id, err)
+	}
+	s.history = history
+	return nil
+}
+
+// conversation builds the messages of a run: the optional system prompt, the session history (when
+// s is non-nil) and the user input.
+func (s *session) conversation(systemPrompt, userInput string) []llm.Message {
+	messages := make([]llm.Message, 0, 2)
+	if systemPrompt != "" {
+		messages = append(messages, llm.Message{Role: llm.RoleSystem, Content: systemPrompt})
+	}
+	if s != nil {
+		messages = append(messages, s.history...)
+	}
+	return append(messages, llm.Message{Role: llm.RoleUser, Content: userInput})
+}
+
+// record appends a successful run's input and answer to the session and saves it. Failed runs leave
+// the session untouched. A save failure is logged rather than failing a node whose answer (and
+// spend) already exist; the next run then sees the previous history.
+func (s *session) record(ctx context.Context, summarizer *turnSummarizer, userInput string, out workflow.FunctionOutput) {
+	if s == nil || out.Status != workflow.FunctionSuccess {
+		return
+	}
+	answer, ok := out.Data["output"].(string)
+	if !ok {
+		b, err := json.Marshal(out.Data["output"])
+		if err != nil {
+			log.Warn().Err(err).Str("sessionId", s.id).Msg("ai: cannot encode answer for session")
+			return
+		}
+		answer = string(b)
+	}
+	transcript := concatMessages(s.history, []llm.Message{
+		{Role: llm.RoleUser, Content: userInput},
+		{Role: llm.RoleAssistant, Content: answer},
+	})
+	if _, dropped := trimContext(transcript, s.maxTokens); len(dropped) > 0 {
+		cut := contextCut{Dropped: len(dropped)}
+		if s.strategy == contextStrategySummarize {
+			cut.Summary = summarizer.summarize(ctx, dropped)
+		}
+		transcript = applyContextCut(transcript, cut)
+	}
+	if err := s.store.Save(ctx, s.namespace, s.id, transcript, s.ttl); err != nil {
+		log.Warn().Err(err).Str("sessionId", s.id).Msg("ai: failed to save session")
+	}
+}
@@ -0,0 +1,167 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/open-source-cloud/fuse/pkg/llm"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSessionStore keeps transcripts in memory, keyed by namespace and id.
+type fakeSessionStore struct {
+	mu       sync.Mutex
+	sessions map[string][]llm.Message
+	ttls     map[string]time.Duration
+	loadErr  error
+}
+
+func newFakeSessionStore() *fakeSessionStore {
+	return &fakeSessionStore{sessions: make(map[string][]llm.Message), ttls: make(map[string]time.Duration)}
+}
+
+func (f *fakeSessionStore) Load(_ context.Context, namespace, id string) ([]llm.Message, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.loadErr != nil {
+		return nil, f.loadErr
+	}
+	return f.sessions[namespace+"/"+id], nil
+}
+
+func (f *fakeSessionStore) Save(_ context.Context, namespace, id string, messages []llm.Message, ttl time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sessions[namespace+"/"+id] = messages
+	f.ttls[namespace+"/"+id] = ttl
+	return nil
+}
+
+func (f *fakeSessionStore) get(key string) []llm.Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.sessions[key]
+}
+
+func TestChat_SessionCarriesConversationAcrossRuns(t *testing.T) {
+	store := newFakeSessionStore()
+	prov := &scriptedProvider{name: "stub", responses: []llm.ChatResponse{finalAnswer("Hi Ada."), finalAnswer("Your name is Ada.")}}
+	fn := makeChatFunction(Deps{Providers: registryWith(prov), Usage: NopUsageRecorder{}, Sessions: store})
+	inSchema := func(e *workflow.ExecutionInfo) { e.SchemaID = "support:bot" }
+
+	out := runMetered(t, fn, map[string]any{"input": "I am Ada", "sessionId": "t-1", "systemPrompt": "be kind", "sessionTTL": "48h"}, inSchema)
+	require.Equal(t, workflow.FunctionSuccess, out.Status)
+	out = runMetered(t, fn, map[string]any{"input": "What is my name?", "sessionId": "t-1", "systemPrompt": "be kind"}, inSchema)
+	require.Equal(t, workflow.FunctionSuccess, out.Status)
+
+	second := prov.requests[1].Messages
+	require.Len(t, second, 4)
+	assert.Equal(t, llm.Message{Role: llm.RoleSystem, Content: "be kind"}, second[0])
+	assert.Equal(t, "I am Ada", second[1].Content)
+	assert.Equal(t, "Hi Ada.", second[2].Content)
+	assert.Equal(t, "What is my name?", second[3].Content)
+
+	stored := store.get("support/t-1")
+	require.Len(t, stored, 4, "system prompts are not stored")
+	assert.Equal(t, llm.RoleAssistant, stored[3].Role)
+	assert.Equal(t, "Your name is Ada.", stored[3].Content)
+	assert.Equal(t, time.Duration(0), store.ttls["support/t-1"], "the last run set no sessionTTL")
+}
+
+func TestAgent_SessionStoresOnlyInputAndAnswer(t *testing.T) {
+	store := newFakeSessionStore()
+	prov := &scriptedProvider{name: "stub", responses: []llm.ChatResponse{
+		{Message: llm.Message{Role: llm.RoleAssistant, ToolCalls: []llm.ToolCall{{ID: "c1", Name: "fuse_pkg_debug__nil", Arguments: []byte(`{}`)}}}},
+		finalAnswer("done"),
+	}}
+	tools := &fakeToolRegistry{descriptors: []ToolDescriptor{{FunctionID: "fuse/pkg/debug/nil", MangledName: "fuse_pkg_debug__nil"}}}
+
+	out := runMetered(t, makeAgentFunction(Deps{Providers: registryWith(prov), Tools: tools, Usage: NopUsageRecorder{}, Sessions: store}),
+		map[string]any{"input": "do it", "sessionId": "s-1"}, nil)
+
+	require.Equal(t, workflow.FunctionSuccess, out.Status)
+	assert.Equal(t, []llm.Message{
+		{Role: llm.RoleUser, Content: "do it"},
+		{Role: llm.RoleAssistant, Content: "done"},
+	}, store.get("default/s-1"))
+}
+
+func TestChat_SessionTranscriptIsBounded(t *testing.T) {
+	store := newFakeSessionStore()
+	long := strings.Repeat("x", 400) // ~100 tokens per turn
+	store.sessions["default/s-1"] = []llm.Message{
+		{Role: llm.RoleUser, Content: "first " + long},
+		{Role: llm.RoleAssistant, Content: "a1 " + long},
+		{Role: llm.RoleUser, Content: "q2 " + long},
+		{Role: llm.RoleAssistant, Content: "a2 " + long},
+	}
+	prov := &scriptedProvider{name: "stub", responses: []llm.ChatResponse{finalAnswer("a3"), finalAnswer("short summary")}}
+
+	out := runMetered(t, makeChatFunction(Deps{Providers: registryWith(prov), Usage: NopUsageRecorder{}, Sessions: store}),
+		map[string]any{"input": "q3", "sessionId": "s-1", "sessionMaxTokens": 250, "contextStrategy": "summarize"}, nil)
+
+	require.Equal(t, workflow.FunctionSuccess, out.Status)
+	stored := store.get("default/s-1")
+	require.Len(t, stored, 5)
+	assert.True(t, strings.HasPrefix(stored[0].Content, "first "), "the first turn is kept")
+	assert.Equal(t, llm.Message{Role: llm.RoleSystem, Content: "Summary of earlier turns: short summary"}, stored[1])
+	assert.Equal(t, "q3", stored[3].Content)
+	assert.Equal(t, "a3", stored[4].Content)
+}
+
+func TestChat_FailedRunLeavesSessionUntouched(t *testing.T) {
+	store := newFakeSessionStore()
+	prov := &scriptedProvider{name: "stub", err: errors.New("boom"), errOnCall: 0}
+
+	out := runMetered(t, makeChatFunction(Deps{Providers: registryWith(prov), Usage: NopUsageRecorder{}, Sessions: store}),
+		map[string]any{"input": "hello", "sessionId": "s-1"}, nil)
+
+	assert.Equal(t, workflow.FunctionError, out.Status)
+	assert.Nil(t, store.get("default/s-1"))
+}
+
+func TestChat_SessionLoadFailureFailsTheNode(t *testing.T) {
+	store := newFakeSessionStore()
+	store.loadErr = errors.New("db down")
+	prov := &scriptedProvider{name: "stub", responses: []llm.ChatResponse{finalAnswer("hi")}}
+
+	out := runMetered(t, makeChatFunction(Deps{Providers: registryWith(prov), Usage: NopUsageRecorder{}, Sessions: store}),
+		map[string]any{"input": "hello", "sessionId": "s-1"}, nil)
+
+	assert.Equal(t, workflow.FunctionError, out.Status)
+	assert.Contains(t, out.Data["error"], "db down")
+	assert.Zero(t, prov.calls)
+}
+
+func TestSession_InvalidInputs(t *testing.T) {
+	prov := &stubProvider{name: "stub"}
+	cases := map[string]struct {
+		store SessionStore
+		input map[string]any
+		want  error
+	}{
+		"no store":    {nil, map[string]any{"input": "hi", "sessionId": "s-1"}, ErrSessionsUnavailable},
+		"bad ttl":     {newFakeSessionStore(), map[string]any{"input": "hi", "sessionId": "s-1", "sessionTTL": "soon"}, ErrInvalidSessionTTL},
+		"id too long": {newFakeSessionStore(), map[string]any{"input": "hi", "sessionId": strings.Repeat("s", maxSessionIDLength+1)}, ErrInvalidSessionID},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fnInput, err := workflow.NewFunctionInputWith(tc.input)
+			require.NoError(t, err)
+			execInfo := workflow.NewExecutionInfo("wf-1", "exec-1", "", fnInput)
+
+			res, err := makeChatFunction(Deps{Providers: registryWith(prov), Usage: NopUsageRecorder{}, Sessions: tc.store})(execInfo)
+
+			require.NoError(t, err)
+			assert.False(t, res.Async)
+			assert.Equal(t, workflow.FunctionError, res.Output.Status)
+			assert.Contains(t, res.Output.Data["error"], tc.want.Error())
+		})
+	}
+}
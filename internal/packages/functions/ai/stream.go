@@ -0,0 +1,94 @@
+package ai
+
+import (
+	"context"
+	"errors"
+
+	"github.com/open-source-cloud/fuse/internal/streams"
+	"github.com/open-source-cloud/fuse/pkg/llm"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+)
+
+// errStreamIncomplete is returned when a provider's stream ends without a final chunk.
+var errStreamIncomplete = errors.New("stream ended before the completion finished")
+
+// OutputStream receives the incremental output of running ai nodes so clients can follow an
+// execution live (GET /v1/workflows/{workflowID}/execs/{execID}/stream). Publishing is best
+// effort; the node's journaled output is unaffected.
+type OutputStream interface {
+	// Publish appends an event to the execution's stream.
+	Publish(workflowID, execID string, event streams.Event)
+	// Close ends the execution's stream.
+	Close(workflowID, execID string)
+}
+
+// nodeStream publishes the events of one node execution. A nil *nodeStream publishes nothing, so
+// callers need not check whether streaming is configured.
+type nodeStream struct {
+	out        OutputStream
+	workflowID string
+	execID     string
+}
+
+// openNodeStream returns the stream of the execution, or nil when no OutputStream is wired.
+func openNodeStream(out OutputStream, execInfo *workflow.ExecutionInfo) *nodeStream {
+	if out == nil {
+		return nil
+	}
+	return &nodeStream{out: out, workflowID: execInfo.WorkflowID.String(), execID: execInfo.ExecID.String()}
+}
+
+// delta publishes a piece of model output.
+func (s *nodeStream) delta(content string) {
+	if s == nil {
+		return
+	}
+	s.out.Publish(s.workflowID, s.execID, streams.Event{Type: streams.EventDelta, Data: map[string]any{"content": content}})
+}
+
+// step publishes the end of an agent iteration that called the given tools.
+func (s *nodeStream) step(iteration int, tools []string) {
+	if s == nil {
+		return
+	}
+	s.out.Publish(s.workflowID, s.execID, streams.Event{Type: streams.EventStep, Data: map[string]any{"iteration": iteration, "tools": tools}})
+}
+
+// finish publishes the node's final status and ends the stream.
+func (s *nodeStream) finish(out workflow.FunctionOutput) {
+	if s == nil {
+		return
+	}
+	s.out.Publish(s.workflowID, s.execID, streams.Event{Type: streams.EventDone, Data: map[string]any{"status": string(out.Status)}})
+	s.out.Close(s.workflowID, s.execID)
+}
+
+// complete runs one chat completion. When the provider can stream and s is set, content deltas are
+// published as they arrive and the aggregated response is returned as from Chat.
+func complete(ctx context.Context, provider llm.Provider, req llm.ChatRequest, s *nodeStream) (llm.ChatResponse, error) {
+	sp, ok := provider.(llm.StreamingProvider)
+	if !ok || s == nil {
+		return provider.Chat(ctx, req)
+	}
+	chunks, err := sp.ChatStream(ctx, req)
+	if err != nil {
+		return llm.ChatResponse{}, err
+	}
+	for chunk := range chunks {
+		switch {
+		case chunk.Err != nil:
+			return llm.ChatResponse{}, chunk.Err
+		case chunk.Done:
+			if chunk.Response == nil {
+				return llm.ChatResponse{}, errStreamIncomplete
+			}
+			return *chunk.Response, nil
+		case chunk.ContentDelta != "":
+			s.delta(chunk.ContentDelta)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return llm.ChatResponse{}, err
+	}
+	return llm.ChatResponse{}, errStreamIncomplete
+}
@@ -0,0 +1,146 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/open-source-cloud/fuse/internal/streams"
+	"github.com/open-source-cloud/fuse/pkg/llm"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// streamingProvider replays scriptedProvider's responses as streams, sending the content in
+// pieces of up to three characters.
+type streamingProvider struct {
+	scriptedProvider
+	streamErr error // sent mid-stream instead of the final chunk
+}
+
+func (s *streamingProvider) ChatStream(ctx context.Context, req llm.ChatRequest) (<-chan llm.StreamChunk, error) {
+	resp, err := s.Chat(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	chunks := make(chan llm.StreamChunk, len(resp.Message.Content)+1)
+	for rest := resp.Message.Content; rest != ""; {
+		n := min(3, len(rest))
+		chunks <- llm.StreamChunk{ContentDelta: rest[:n]}
+		rest = rest[n:]
+	}
+	if s.streamErr != nil {
+		chunks <- llm.StreamChunk{Err: s.streamErr}
+	} else {
+		chunks <- llm.StreamChunk{Done: true, Response: &resp}
+	}
+	close(chunks)
+	return chunks, nil
+}
+
+// fakeOutputStream records the events published for each execution.
+type fakeOutputStream struct {
+	mu     sync.Mutex
+	events []streams.Event
+	closed chan struct{}
+}
+
+func newFakeOutputStream() *fakeOutputStream {
+	return &fakeOutputStream{closed: make(chan struct{})}
+}
+
+func (f *fakeOutputStream) Publish(_, _ string, event streams.Event) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, event)
+}
+
+func (f *fakeOutputStream) Close(_, _ string) { close(f.closed) }
+
+// waitEvents returns the published events once the stream is closed.
+func (f *fakeOutputStream) waitEvents(t *testing.T) []streams.Event {
+	t.Helper()
+	select {
+	case <-f.closed:
+	case <-time.After(3 * time.Second):
+		t.Fatal("stream was not closed")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.events
+}
+
+// summarize renders events compactly: "delta:<content>", "step:<tools>", "done:<status>".
+func summarize(events []streams.Event) []string {
+	out := make([]string, 0, len(events))
+	for _, e := range events {
+		switch e.Type {
+		case streams.EventDelta:
+			out = append(out, "delta:"+e.Data["content"].(string))
+		case streams.EventStep:
+			out = append(out, "step:"+e.Data["tools"].([]string)[0])
+		case streams.EventDone:
+			out = append(out, "done:"+e.Data["status"].(string))
+		}
+	}
+	return out
+}
+
+func TestChat_StreamsDeltasAndReturnsTheAggregatedAnswer(t *testing.T) {
+	prov := &streamingProvider{scriptedProvider: scriptedProvider{name: "stub", responses: []llm.ChatResponse{finalAnswer("Hello!")}}}
+	outputs := newFakeOutputStream()
+
+	out := runMetered(t, makeChatFunction(Deps{Providers: registryWith(prov), Usage: NopUsageRecorder{}, Outputs: outputs}), map[string]any{"input": "hi"}, nil)
+
+	require.Equal(t, workflow.FunctionSuccess, out.Status)
+	assert.Equal(t, "Hello!", out.Data["output"])
+	assert.Equal(t, 2, out.Data["usage"].(map[string]any)["totalTokens"])
+	assert.Equal(t, []string{"delta:Hel", "delta:lo!", "done:success"}, summarize(outputs.waitEvents(t)))
+}
+
+func TestChat_NonStreamingProviderOnlyPublishesDone(t *testing.T) {
+	prov := &scriptedProvider{name: "stub", responses: []llm.ChatResponse{finalAnswer("Hello!")}}
+	outputs := newFakeOutputStream()
+
+	out := runMetered(t, makeChatFunction(Deps{Providers: registryWith(prov), Usage: NopUsageRecorder{}, Outputs: outputs}), map[string]any{"input": "hi"}, nil)
+
+	require.Equal(t, workflow.FunctionSuccess, out.Status)
+	assert.Equal(t, []string{"done:success"}, summarize(outputs.waitEvents(t)))
+}
+
+func TestChat_StreamFailureFailsTheNode(t *testing.T) {
+	prov := &streamingProvider{
+		scriptedProvider: scriptedProvider{name: "stub", responses: []llm.ChatResponse{finalAnswer("Hello!")}},
+		streamErr:        errors.New("connection reset"),
+	}
+	outputs := newFakeOutputStream()
+
+	out := runMetered(t, makeChatFunction(Deps{Providers: registryWith(prov), Usage: NopUsageRecorder{}, Outputs: outputs}), map[string]any{"input": "hi"}, nil)
+
+	assert.Equal(t, workflow.FunctionError, out.Status)
+	assert.Contains(t, out.Data["error"], "connection reset")
+	assert.Equal(t, []string{"delta:Hel", "delta:lo!", "done:error"}, summarize(outputs.waitEvents(t)))
+}
+
+func TestAgent_StreamsEachIterationAndMarksToolSteps(t *testing.T) {
+	toolStep := toolCallResponse("c1", "fuse_pkg_debug__nil", `{}`)
+	toolStep.Message.Content = "Checking"
+	prov := &streamingProvider{scriptedProvider: scriptedProvider{name: "stub", responses: []llm.ChatResponse{toolStep, finalAnswer("Done.")}}}
+	tools := &fakeToolRegistry{descriptors: []ToolDescriptor{{FunctionID: "fuse/pkg/debug/nil", MangledName: "fuse_pkg_debug__nil"}}}
+	outputs := newFakeOutputStream()
+
+	out := runMetered(t, makeAgentFunction(Deps{Providers: registryWith(prov), Tools: tools, Usage: NopUsageRecorder{}, Outputs: outputs}),
+		map[string]any{"input": "do it"}, nil)
+
+	require.Equal(t, workflow.FunctionSuccess, out.Status)
+	assert.Equal(t, "Done.", out.Data["output"])
+	assert.Equal(t, []string{
+		"delta:Che", "delta:cki", "delta:ng",
+		"step:fuse_pkg_debug__nil",
+		"delta:Don", "delta:e.",
+		"done:success",
+	}, summarize(outputs.waitEvents(t)))
+}
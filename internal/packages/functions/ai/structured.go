@@ -59,8 +59,7 @@ func structuredOutput(ctx context.Context, provider llm.Provider, model string,
 			usage.RecordCall(function, provider.Name(), model, "error")
 			return nil, total, fmt.Errorf("structured output call failed: %w", err)
 		}
-		usage.RecordCall(function, provider.Name(), model, "success")
-		usage.RecordUsage(function, provider.Name(), model, resp.Usage)
+		recordCompletion(usage, function, provider, model, resp)
 		total.PromptTokens += resp.Usage.PromptTokens
 		total.CompletionTokens += resp.Usage.CompletionTokens
 		total.TotalTokens += resp.Usage.TotalTokens
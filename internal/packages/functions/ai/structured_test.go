@@ -89,7 +89,7 @@ func TestChat_StructuredOutputDeliversObject(t *testing.T) {
 	execInfo := workflow.NewExecutionInfo("wf-1", "exec-1", "", fnInput)
 	execInfo.Finish = func(out workflow.FunctionOutput) { done <- out }
 
-	res, err := makeChatFunction(reg, NopUsageRecorder{})(execInfo)
+	res, err := makeChatFunction(Deps{Providers: reg, Usage: NopUsageRecorder{}})(execInfo)
 	require.NoError(t, err)
 	require.True(t, res.Async)
 	select {
@@ -1,6 +1,7 @@
 package ai
 
 import (
+	"context"
 	"sort"
 	"strings"
 
@@ -17,14 +18,18 @@ import (
 // pointing the right way (packages -> ai).
 type ToolRegistry interface {
 	// ListTools returns the functions eligible to be exposed to the model as
-	// tools. Per ADR-0007 (Phase B) this is synchronous, declared-parameter
-	// functions only; asynchronous/intercepted and schemaless functions are
-	// excluded by the implementation.
-	ListTools() []ToolDescriptor
-	// InvokeTool runs the function identified by its full id (e.g.
-	// "fuse/pkg/logic/sum") synchronously in-process and returns its result inline
-	// (FunctionResult.Async == false). No worker handle is involved: Phase B exposes
-	// only synchronous tools, so the actor system is never reached.
+	// tools: declared-parameter functions, with the asynchronous and intercepted
+	// ones marked Async, and the tools of the MCP servers registered in the
+	// namespace of execInfo's schema. Schemaless functions are excluded by the
+	// implementation. Listing may reach MCP servers, bounded by ctx.
+	ListTools(ctx context.Context, execInfo *workflow.ExecutionInfo) []ToolDescriptor
+	// InvokeTool runs the synchronous function identified by its full id (e.g.
+	// "fuse/pkg/logic/sum") in-process, or calls the MCP tool (e.g.
+	// "mcp/wiki/search"), and returns its result inline
+	// (FunctionResult.Async == false). No worker handle is involved, so the actor
+	// system is never reached; Async tools go through ExecRuntime instead.
+	// execInfo carries the agent's schema and a context that ends with the tool
+	// timeout.
 	InvokeTool(functionID string, execInfo *workflow.ExecutionInfo) (workflow.FunctionResult, error)
 }
 
@@ -38,6 +43,12 @@ type ToolDescriptor struct {
 	Description string
 	// Parameters is a JSON Schema object describing the tool's arguments.
 	Parameters map[string]any
+	// Async marks a function that completes through the workflow engine rather than inline; it can
+	// only be called through an ExecRuntime (ADR-0027).
+	Async bool
+	// SchemaID is set on the workflows offered by ai/orchestrate: calling the tool runs that
+	// schema as a child workflow with the arguments as its trigger input (ADR-0026).
+	SchemaID string
 }
 
 // toolNameSeparator replaces "/" in tool names because most providers restrict
@@ -10,6 +10,8 @@ type UsageRecorder interface {
 	RecordUsage(function, provider, model string, u llm.Usage)
 	// RecordCall records that a completion call was made and its outcome (success|error).
 	RecordCall(function, provider, model, status string)
+	// RecordCost records the USD cost of one completion, as priced by the configured table.
+	RecordCost(function, provider, model string, usd float64)
 }
 
 // NopUsageRecorder is a UsageRecorder that does nothing (default for tests / when metrics are off).
@@ -20,3 +22,42 @@ func (NopUsageRecorder) RecordUsage(string, string, string, llm.Usage) {}
 
 // RecordCall does nothing.
 func (NopUsageRecorder) RecordCall(string, string, string, string) {}
+
+// RecordCost does nothing.
+func (NopUsageRecorder) RecordCost(string, string, string, float64) {}
+
+// routingRecorder is implemented by recorders that also keep how routing policies served the
+// node's completions (spendMeter), for the node's usage output.
+type routingRecorder interface {
+	RecordRouting(r *llm.Routing)
+}
+
+// recordCompletion records a successful completion. One served by a routing policy is recorded,
+// and so priced, under the provider and model of the route that answered it; each route the
+// policy sent it to before is recorded as a failed call of that route.
+func recordCompletion(usage UsageRecorder, function string, provider llm.Provider, model string, resp llm.ChatResponse) {
+	name := provider.Name()
+	if r := resp.Routing; r != nil {
+		name, model = r.Provider, r.Model
+		for _, f := range r.Fallbacks {
+			if f.Reason != llm.FallbackCircuitOpen {
+				usage.RecordCall(function, f.Provider, f.Model, "error")
+			}
+		}
+		if rr, ok := usage.(routingRecorder); ok {
+			rr.RecordRouting(r)
+		}
+	}
+	usage.RecordCall(function, name, model, "success")
+	usage.RecordUsage(function, name, model, resp.Usage)
+}
+
+// usageData renders token usage and its priced cost as the "usage" output of an ai node.
+func usageData(u llm.Usage, costUSD float64) map[string]any {
+	return map[string]any{
+		"promptTokens":     u.PromptTokens,
+		"completionTokens": u.CompletionTokens,
+		"totalTokens":      u.TotalTokens,
+		"costUSD":          costUSD,
+	}
+}
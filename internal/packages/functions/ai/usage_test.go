@@ -11,11 +11,12 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-// fakeUsageRecorder captures RecordUsage / RecordCall invocations.
+// fakeUsageRecorder captures RecordUsage / RecordCall / RecordCost invocations.
 type fakeUsageRecorder struct {
 	mu     sync.Mutex
 	usage  []llm.Usage
 	status []string
+	cost   []float64
 }
 
 func (f *fakeUsageRecorder) RecordUsage(_, _, _ string, u llm.Usage) {
@@ -30,6 +31,12 @@ func (f *fakeUsageRecorder) RecordCall(_, _, _, status string) {
 	f.status = append(f.status, status)
 }
 
+func (f *fakeUsageRecorder) RecordCost(_, _, _ string, usd float64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.cost = append(f.cost, usd)
+}
+
 func (f *fakeUsageRecorder) snapshot() ([]llm.Usage, []string) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
@@ -53,7 +60,7 @@ func TestChat_RecordsUsageMetrics(t *testing.T) {
 	execInfo := workflow.NewExecutionInfo("wf-1", "exec-1", "", fnInput)
 	execInfo.Finish = func(out workflow.FunctionOutput) { done <- out }
 
-	res, err := makeChatFunction(reg, rec)(execInfo)
+	res, err := makeChatFunction(Deps{Providers: reg, Usage: rec})(execInfo)
 	require.NoError(t, err)
 	require.True(t, res.Async)
 	select {
@@ -72,4 +79,33 @@ func TestChat_RecordsUsageMetrics(t *testing.T) {
 	var nop UsageRecorder = NopUsageRecorder{}
 	nop.RecordUsage(ChatFunctionID, "stub", "m", llm.Usage{PromptTokens: 1})
 	nop.RecordCall(ChatFunctionID, "stub", "m", "success")
+	nop.RecordCost(ChatFunctionID, "stub", "m", 0.1)
+}
+
+func TestChat_RoutedCompletionUsage(t *testing.T) {
+	// A routing policy answers under its own name but reports the route that served the call.
+	prov := &stubProvider{name: "resilient", resp: llm.ChatResponse{
+		Message: llm.Message{Role: llm.RoleAssistant, Content: "hi"},
+		Usage:   llm.Usage{PromptTokens: 1000, CompletionTokens: 500, TotalTokens: 1500},
+		Routing: &llm.Routing{Policy: "resilient", Provider: "stub", Model: "m", Fallbacks: []llm.FallbackAttempt{
+			{Provider: "openai", Model: "gpt-4o", Reason: "status 429"},
+			{Provider: "anthropic", Reason: llm.FallbackCircuitOpen},
+		}},
+	}}
+	rec := &fakeUsageRecorder{}
+
+	out := runMetered(t, makeChatFunction(Deps{Providers: registryWith(prov), Usage: rec, Pricing: stubPricing}), map[string]any{"input": "hello"}, nil)
+
+	require.Equal(t, workflow.FunctionSuccess, out.Status)
+	usage := out.Data["usage"].(map[string]any)
+	assert.Equal(t, "resilient", usage["policy"])
+	assert.Equal(t, "stub", usage["provider"])
+	assert.Equal(t, "m", usage["model"])
+	assert.Equal(t, []map[string]any{
+		{"provider": "openai", "model": "gpt-4o", "reason": "status 429"},
+		{"provider": "anthropic", "model": "", "reason": llm.FallbackCircuitOpen},
+	}, usage["fallbacks"])
+	assert.InDelta(t, 0.002, usage["costUSD"], 1e-12, "priced as the provider that served the call")
+	_, status := rec.snapshot()
+	assert.Equal(t, []string{"error", "success"}, status, "a route skipped by its breaker was not called")
 }
@@ -11,6 +11,10 @@ const SubWorkflowFunctionID = "subworkflow"
 // SubWorkflowFullFunctionID is the full function ID for system/subworkflow
 const SubWorkflowFullFunctionID = PackageID + "/" + SubWorkflowFunctionID
 
+// ErrorTypeQuotaExceeded is the errorType of a sub-workflow whose child was refused by the
+// namespace's concurrency quota, so onError edges can route on it.
+const ErrorTypeQuotaExceeded = "quota_exceeded"
+
 // SubWorkflowFunctionMetadata returns the metadata of the sub-workflow function
 func SubWorkflowFunctionMetadata() workflow.FunctionMetadata {
 	return workflow.FunctionMetadata{
@@ -8,6 +8,7 @@ import (
 	"github.com/open-source-cloud/fuse/internal/packages/functions/logic"
 	"github.com/open-source-cloud/fuse/internal/packages/functions/system"
 	"github.com/open-source-cloud/fuse/pkg/llm"
+	"github.com/open-source-cloud/fuse/pkg/vectorstore"
 	"github.com/open-source-cloud/fuse/pkg/workflow"
 )
 
@@ -18,23 +19,53 @@ type (
 	}
 )
 
-// NewInternal creates new InternalPackages service. The LLM provider registry is
-// injected so the ai package can expose chat/agent functions, the package registry
-// backs the agent's tool catalog (synchronous functions become tools), and the metrics
-// recorder surfaces LLM token usage to observability (ADR-0029).
-func NewInternal(providers llm.Registry, registry Registry, fuseMetrics *metrics.FuseMetrics) InternalPackages {
+// InternalDeps are the services the internal packages are built on. Providers and Registry are
+// required; the others may be nil where the functions using them are not run, as in tests.
+type InternalDeps struct {
+	// Providers are the LLM providers the ai package exposes chat/agent functions over.
+	Providers llm.Registry
+	// Registry backs the agent's tool catalog: synchronous functions become tools.
+	Registry Registry
+	// Metrics surfaces LLM token usage to observability (ADR-0029).
+	Metrics *metrics.FuseMetrics
+	// Pricing prices LLM calls, and Ledger charges them to schema budgets.
+	Pricing llm.Pricing
+	Ledger  ai.BudgetLedger
+	// Sessions keeps the conversations of ai nodes across runs.
+	Sessions ai.SessionStore
+	// Vectors holds the chunks ai/index embeds for retrieval (ADR-0028).
+	Vectors vectorstore.VectorStore
+	// Outputs carries model text to clients following an execution while its ai nodes run.
+	Outputs ai.OutputStream
+	// Runtime runs the agent's asynchronous tools through the workflow engine (ADR-0027),
+	// including the child workflows of ai/orchestrate, whose schemas Catalog describes (ADR-0026).
+	Runtime ai.ExecRuntime
+	Catalog ai.WorkflowCatalog
+	// MCPTools adds the tools of registered MCP servers to the agent's tool catalog.
+	MCPTools MCPTools
+}
+
+// NewInternal creates new InternalPackages service
+func NewInternal(deps InternalDeps) InternalPackages {
 	return &DefaultInternalPackages{
-		providers: providers,
-		tools:     NewAgentToolRegistry(registry),
-		usage:     newUsageRecorder(fuseMetrics),
+		ai: ai.Deps{
+			Providers: deps.Providers,
+			Tools:     NewAgentToolRegistry(deps.Registry, deps.MCPTools),
+			Usage:     newUsageRecorder(deps.Metrics),
+			Pricing:   deps.Pricing,
+			Ledger:    deps.Ledger,
+			Sessions:  deps.Sessions,
+			Vectors:   deps.Vectors,
+			Outputs:   deps.Outputs,
+			Runtime:   deps.Runtime,
+			Catalog:   deps.Catalog,
+		},
 	}
 }
 
 // DefaultInternalPackages service for registering internal packages
 type DefaultInternalPackages struct {
-	providers llm.Registry
-	tools     ai.ToolRegistry
-	usage     ai.UsageRecorder
+	ai ai.Deps
 }
 
 // List returns the list of internal packages
@@ -44,6 +75,6 @@ func (p *DefaultInternalPackages) List() []*workflow.Package {
 		logic.New(),
 		http.New(),
 		system.New(),
-		ai.New(p.providers, p.tools, p.usage),
+		ai.New(p.ai),
 	}
 }
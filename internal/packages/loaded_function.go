@@ -22,6 +22,15 @@ func NewLoadedInternalFunction(id string, metadata *FunctionMetadata, fn workflo
 	}
 }
 
+// NewLoadedHTTPFunction creates a new LoadedFunction with transport.HTTPFunctionTransport calling endpoint as transport.FunctionTransport
+func NewLoadedHTTPFunction(id string, metadata *FunctionMetadata, endpoint string) *LoadedFunction {
+	return &LoadedFunction{
+		ID:        id,
+		Metadata:  metadata,
+		Transport: transport.NewHTTPFunctionTransport(endpoint, nil),
+	}
+}
+
 // LoadedFunction represents an executable LoadedFunction and it's metadata
 type LoadedFunction struct {
 	ID        string                      `json:"id"`
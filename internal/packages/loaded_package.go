@@ -5,6 +5,7 @@ import (
 
 	"github.com/open-source-cloud/fuse/internal/actors/actor"
 	"github.com/open-source-cloud/fuse/internal/packages/transport"
+	pkgtransport "github.com/open-source-cloud/fuse/pkg/transport"
 	"github.com/open-source-cloud/fuse/pkg/workflow"
 )
 
@@ -109,7 +110,10 @@ func MapToRegistryPackage(pkg *workflow.Package) *LoadedPackage {
 			metadata.Output.Edges[edge.Name] = outputEdge
 		}
 
-		if function.Metadata.Transport == transport.Internal && function.Function != nil {
+		if function.Metadata.Transport == pkgtransport.HTTP && function.Metadata.Endpoint != "" {
+			metadata.Transport = pkgtransport.HTTP
+			functions[functionID] = NewLoadedHTTPFunction(functionID, metadata, function.Metadata.Endpoint)
+		} else if function.Metadata.Transport == transport.Internal && function.Function != nil {
 			functions[functionID] = NewLoadedInternalFunction(
 				functionID,
 				metadata,
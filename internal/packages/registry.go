@@ -15,6 +15,7 @@ type (
 		Get(pkgID string) (*LoadedPackage, error)
 		Has(pkgID string) bool
 		List() ([]*LoadedPackage, error)
+		ListVisible(namespace string) ([]*LoadedPackage, error)
 	}
 
 	// MemoryRegistry is a MemoryRegistry for the packages
@@ -24,7 +25,9 @@ type (
 	}
 )
 
-// pkgRegistry is a global package registry
+// pkgRegistry is the node's package registry. It is keyed by namespace-qualified package IDs
+// (see workflow.QualifyID), so the packages of different namespaces never share a key; lookups on
+// behalf of an execution go through ListVisible.
 var pkgRegistry Registry
 
 // NewPackageRegistry creates a new provider MemoryRegistry
@@ -93,3 +96,17 @@ func (r *MemoryRegistry) List() ([]*LoadedPackage, error) {
 	}
 	return packages, nil
 }
+
+// ListVisible returns the packages an execution of namespace may use: the namespace's own and the
+// shared default-namespace ones.
+func (r *MemoryRegistry) ListVisible(namespace string) ([]*LoadedPackage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	packages := make([]*LoadedPackage, 0, len(r.packages))
+	for id, pkg := range r.packages {
+		if pkgNamespace := workflow.NamespaceOf(id); pkgNamespace == workflow.DefaultNamespaceName || pkgNamespace == namespace {
+			packages = append(packages, pkg)
+		}
+	}
+	return packages, nil
+}
@@ -0,0 +1,102 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/open-source-cloud/fuse/internal/actors/actor"
+	pkgtransport "github.com/open-source-cloud/fuse/pkg/transport"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+)
+
+// maxHTTPFunctionResponse caps the body read from an HTTP function.
+const maxHTTPFunctionResponse = 10 << 20
+
+// HTTPFunctionRequest is the body POSTed to an HTTP transport function. The execution's callback
+// token travels in the pkgtransport.CallbackTokenHeader header.
+type HTTPFunctionRequest struct {
+	WorkflowID  string           `json:"workflowId"`
+	ExecID      string           `json:"execId"`
+	Environment string           `json:"environment,omitempty"`
+	Input       map[string]any   `json:"input"`
+	Checkpoints []map[string]any `json:"checkpoints,omitempty"`
+}
+
+// NewHTTPFunctionTransport creates a new HTTPFunctionTransport calling endpoint
+func NewHTTPFunctionTransport(endpoint string, client *http.Client) FunctionTransport {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &HTTPFunctionTransport{endpoint: endpoint, client: client}
+}
+
+// HTTPFunctionTransport implements the HTTP type of transport: the function is a remote endpoint
+// that answers 200 with its FunctionOutput, or 202 to report the result later through
+// POST /v1/workflows/{workflowID}/execs/{execID} with the callback token it was sent.
+type HTTPFunctionTransport struct {
+	endpoint string
+	client   *http.Client
+}
+
+// Execute calls the function's endpoint
+func (t *HTTPFunctionTransport) Execute(_ actor.Handle, execInfo *workflow.ExecutionInfo) (workflow.FunctionResult, error) {
+	return t.call(execInfo, true)
+}
+
+// ExecuteSync calls the function's endpoint, which must answer with its output
+func (t *HTTPFunctionTransport) ExecuteSync(execInfo *workflow.ExecutionInfo) (workflow.FunctionResult, error) {
+	return t.call(execInfo, false)
+}
+
+func (t *HTTPFunctionTransport) call(execInfo *workflow.ExecutionInfo, allowAsync bool) (workflow.FunctionResult, error) {
+	if execInfo == nil {
+		return workflow.FunctionResult{}, errNilExecutionInfo
+	}
+	payload := HTTPFunctionRequest{
+		WorkflowID:  execInfo.WorkflowID.String(),
+		ExecID:      execInfo.ExecID.String(),
+		Environment: execInfo.Environment,
+		Checkpoints: execInfo.Checkpoints,
+	}
+	if execInfo.Input != nil {
+		payload.Input = execInfo.Input.Raw()
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return workflow.FunctionResult{}, fmt.Errorf("transport: encode http function request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(execInfo.Ctx(), http.MethodPost, t.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return workflow.FunctionResult{}, fmt.Errorf("transport: http function request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if execInfo.CallbackToken != "" {
+		req.Header.Set(pkgtransport.CallbackTokenHeader, execInfo.CallbackToken)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return workflow.FunctionResult{}, fmt.Errorf("transport: call %s: %w", t.endpoint, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxHTTPFunctionResponse))
+	if err != nil {
+		return workflow.FunctionResult{}, fmt.Errorf("transport: read %s response: %w", t.endpoint, err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusAccepted && allowAsync:
+		return workflow.FunctionResult{Async: true}, nil
+	case resp.StatusCode == http.StatusOK:
+		var output workflow.FunctionOutput
+		if err := json.Unmarshal(respBody, &output); err != nil {
+			return workflow.FunctionResult{}, fmt.Errorf("transport: decode %s response: %w", t.endpoint, err)
+		}
+		return workflow.FunctionResult{Output: output}, nil
+	}
+	return workflow.FunctionResult{}, fmt.Errorf("transport: %s answered %d: %s", t.endpoint, resp.StatusCode, bytes.TrimSpace(respBody))
+}
@@ -0,0 +1,76 @@
+package transport
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	pkgtransport "github.com/open-source-cloud/fuse/pkg/transport"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newHTTPExecutionInfo(t *testing.T) *workflow.ExecutionInfo {
+	t.Helper()
+	input, err := workflow.NewFunctionInputWith(map[string]any{"amount": 42.0})
+	require.NoError(t, err)
+	execInfo := workflow.NewExecutionInfo("wf-1", workflow.NewExecID(1), "prod", input)
+	execInfo.CallbackToken = "1767225600.signature"
+	return execInfo
+}
+
+func TestHTTPFunctionTransport_SendsCallbackToken(t *testing.T) {
+	t.Parallel()
+
+	var got *http.Request
+	var body HTTPFunctionRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	execInfo := newHTTPExecutionInfo(t)
+	result, err := NewHTTPFunctionTransport(srv.URL, srv.Client()).Execute(fakeHandle{}, execInfo)
+	require.NoError(t, err)
+	assert.True(t, result.Async, "202 leaves the result to the callback")
+
+	require.NotNil(t, got)
+	assert.Equal(t, "1767225600.signature", got.Header.Get(pkgtransport.CallbackTokenHeader))
+	assert.Equal(t, "wf-1", body.WorkflowID)
+	assert.Equal(t, execInfo.ExecID.String(), body.ExecID)
+	assert.Equal(t, map[string]any{"amount": 42.0}, body.Input)
+}
+
+func TestHTTPFunctionTransport_SyncOutput(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(workflow.NewFunctionSuccessOutput(map[string]any{"score": 7.0}))
+	}))
+	defer srv.Close()
+
+	result, err := NewHTTPFunctionTransport(srv.URL, srv.Client()).ExecuteSync(newHTTPExecutionInfo(t))
+	require.NoError(t, err)
+	assert.False(t, result.Async)
+	assert.Equal(t, workflow.FunctionSuccess, result.Output.Status)
+	assert.Equal(t, 7.0, result.Output.Data["score"])
+}
+
+func TestHTTPFunctionTransport_Errors(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	_, err := NewHTTPFunctionTransport(srv.URL, srv.Client()).ExecuteSync(newHTTPExecutionInfo(t))
+	assert.ErrorContains(t, err, "answered 202", "a synchronous call cannot wait for a callback")
+
+	_, err = NewHTTPFunctionTransport(srv.URL, srv.Client()).Execute(fakeHandle{}, nil)
+	assert.ErrorIs(t, err, errNilExecutionInfo)
+}
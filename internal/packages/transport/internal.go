@@ -1,6 +1,8 @@
 package transport
 
 import (
+	"sync/atomic"
+
 	"ergo.services/ergo/gen"
 	"github.com/open-source-cloud/fuse/internal/actors/actor"
 	"github.com/open-source-cloud/fuse/internal/actors/actornames"
@@ -15,15 +17,25 @@ import (
 // return gen.ErrUnsupported ("not supported"). From WorkflowFunc pool workers, sending with a
 // captured workflow-handler gen.PID has been observed to fail that way; addressing the handler by
 // its registered gen.Atom name on the local node matches sync Send(string, ...) and succeeds.
-func sendAsyncFunctionResult(n gen.Node, wfID workflow.ID, execID workflow.ExecID, output workflow.FunctionOutput) error {
+func sendAsyncFunctionResult(n gen.Node, namespace string, wfID workflow.ID, execID workflow.ExecID, output workflow.FunctionOutput) error {
 	if n == nil {
 		return errNilNode
 	}
-	handlerName := gen.Atom(actornames.WorkflowHandlerName(wfID))
+	handlerName := gen.Atom(actornames.WorkflowHandlerName(namespace, wfID))
 	msg := messaging.NewAsyncFunctionResultMessage(wfID, execID, output)
 	return n.Send(handlerName, msg)
 }
 
+// sendFunctionCheckpoint delivers a sub-step checkpoint to the workflow handler, addressed by its
+// registered name for the same reason as sendAsyncFunctionResult.
+func sendFunctionCheckpoint(n gen.Node, namespace string, wfID workflow.ID, execID workflow.ExecID, index int, data map[string]any) error {
+	if n == nil {
+		return errNilNode
+	}
+	handlerName := gen.Atom(actornames.WorkflowHandlerName(namespace, wfID))
+	return n.Send(handlerName, messaging.NewFunctionCheckpointMessage(wfID, execID, index, data))
+}
+
 // NewInternalFunctionTransport creates a new InternalFunctionTransport
 func NewInternalFunctionTransport(fn workflow.Function) FunctionTransport {
 	return &InternalFunctionTransport{
@@ -50,13 +62,24 @@ func (t *InternalFunctionTransport) Execute(handle actor.Handle, execInfo *workf
 			return
 		}
 		n := handle.Node()
-		if err := sendAsyncFunctionResult(n, execInfo.WorkflowID, execInfo.ExecID, result); err != nil {
+		if err := sendAsyncFunctionResult(n, workflow.NamespaceOf(execInfo.SchemaID), execInfo.WorkflowID, execInfo.ExecID, result); err != nil {
 			log.Error().Err(err).
 				Str("workflowID", string(execInfo.WorkflowID)).
 				Str("execID", execInfo.ExecID.String()).
 				Msg("failed to send async function result")
 		}
 	}
+	var next atomic.Int64
+	next.Store(int64(len(execInfo.Checkpoints)))
+	execInfo.Checkpoint = func(data map[string]any) {
+		index := int(next.Add(1) - 1)
+		if err := sendFunctionCheckpoint(handle.Node(), workflow.NamespaceOf(execInfo.SchemaID), execInfo.WorkflowID, execInfo.ExecID, index, data); err != nil {
+			log.Error().Err(err).
+				Str("workflowID", string(execInfo.WorkflowID)).
+				Str("execID", execInfo.ExecID.String()).
+				Msg("failed to send function checkpoint")
+		}
+	}
 	return t.fn(execInfo)
 }
 
@@ -64,7 +87,8 @@ func (t *InternalFunctionTransport) Execute(handle actor.Handle, execInfo *workf
 // for in-process tool invocation (ai/agent), where only synchronous functions are
 // eligible, so the result is returned inline via FunctionResult and the worker
 // pool / actor system is never involved. Finish is bound to a guard that logs and
-// ignores any (unexpected) async-completion attempt rather than panicking.
+// ignores any (unexpected) async-completion attempt rather than panicking. Tools are not
+// checkpointed: Checkpoint stays nil.
 func (t *InternalFunctionTransport) ExecuteSync(execInfo *workflow.ExecutionInfo) (workflow.FunctionResult, error) {
 	if execInfo == nil {
 		return workflow.FunctionResult{}, errNilExecutionInfo
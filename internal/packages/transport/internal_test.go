@@ -30,14 +30,14 @@ func TestSendAsyncFunctionResult_UsesHandlerAtom(t *testing.T) {
 	execID := workflow.NewExecID(1)
 	out := workflow.FunctionOutput{Data: map[string]any{"k": "v"}}
 
-	err := sendAsyncFunctionResult(n, wfID, execID, out)
+	err := sendAsyncFunctionResult(n, "billing", wfID, execID, out)
 	require.NoError(t, err)
 
 	raw, popped := events.Pop()
 	require.True(t, popped)
 	ev, ok := raw.(unit.SendEvent)
 	require.True(t, ok, "expected SendEvent, got %T", raw)
-	wantAtom := gen.Atom(actornames.WorkflowHandlerName(wfID))
+	wantAtom := gen.Atom(actornames.WorkflowHandlerName("billing", wfID))
 	require.Equal(t, wantAtom, ev.To)
 
 	msg, ok := ev.Message.(messaging.Message)
@@ -48,10 +48,32 @@ func TestSendAsyncFunctionResult_UsesHandlerAtom(t *testing.T) {
 func TestSendAsyncFunctionResult_NilNode(t *testing.T) {
 	t.Parallel()
 
-	err := sendAsyncFunctionResult(nil, "wf", workflow.NewExecID(0), workflow.FunctionOutput{})
+	err := sendAsyncFunctionResult(nil, workflow.DefaultNamespaceName, "wf", workflow.NewExecID(0), workflow.FunctionOutput{})
 	require.ErrorIs(t, err, errNilNode)
 }
 
+func TestSendFunctionCheckpoint_NilNode(t *testing.T) {
+	t.Parallel()
+
+	err := sendFunctionCheckpoint(nil, workflow.DefaultNamespaceName, "wf", workflow.NewExecID(0), 0, map[string]any{})
+	require.ErrorIs(t, err, errNilNode)
+}
+
+func TestExecute_BindsCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	var bound bool
+	fn := func(execInfo *workflow.ExecutionInfo) (workflow.FunctionResult, error) {
+		bound = execInfo.Checkpoint != nil
+		return workflow.NewFunctionResultAsync(), nil
+	}
+	tr := NewInternalFunctionTransport(fn)
+
+	_, err := tr.Execute(fakeHandle{}, workflow.NewExecutionInfo("wf-1", workflow.NewExecID(1), "", nil))
+	require.NoError(t, err)
+	assert.True(t, bound, "a worker execution can journal checkpoints")
+}
+
 func TestExecuteSync_RunsFunctionWithoutHandle(t *testing.T) {
 	t.Parallel()
 
@@ -66,6 +88,7 @@ func TestExecuteSync_RunsFunctionWithoutHandle(t *testing.T) {
 	res, err := tr.ExecuteSync(execInfo)
 	require.NoError(t, err)
 	require.True(t, called, "the function should run")
+	assert.Nil(t, execInfo.Checkpoint, "tool invocations are not checkpointed")
 	assert.False(t, res.Async, "a synchronous invocation returns its result inline")
 	assert.Equal(t, workflow.FunctionSuccess, res.Output.Status)
 	assert.Equal(t, true, res.Output.Data["ok"])
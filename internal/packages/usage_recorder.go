@@ -32,3 +32,10 @@ func (r metricsUsageRecorder) RecordUsage(function, provider, model string, u ll
 func (r metricsUsageRecorder) RecordCall(function, provider, model, status string) {
 	r.m.LLMCalls.WithLabelValues(function, provider, model, status).Inc()
 }
+
+// RecordCost records the USD cost of a completion.
+func (r metricsUsageRecorder) RecordCost(function, provider, model string, usd float64) {
+	if usd > 0 {
+		r.m.LLMCost.WithLabelValues(function, provider, model).Add(usd)
+	}
+}
@@ -0,0 +1,39 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"github.com/open-source-cloud/fuse/pkg/llm"
+)
+
+// ErrAgentSessionNotFound is returned when an agent session does not exist or has expired.
+var ErrAgentSessionNotFound = errors.New("agent session not found")
+
+type (
+	// AgentSession is the persisted conversation of the ai/chat and ai/agent runs that share a
+	// sessionId within a namespace. Messages hold the user and final assistant turns (plus any
+	// summary of trimmed turns); system prompts and tool traffic stay per run.
+	AgentSession struct {
+		Namespace string        `json:"namespace"`
+		ID        string        `json:"id"`
+		Messages  []llm.Message `json:"messages"`
+		CreatedAt time.Time     `json:"createdAt"`
+		UpdatedAt time.Time     `json:"updatedAt"`
+		// ExpiresAt is when the session is forgotten; every save pushes it back by the session TTL.
+		ExpiresAt time.Time `json:"expiresAt"`
+	}
+
+	// AgentSessionRepository persists agent sessions. Expired sessions read as not found until
+	// DeleteExpired removes them.
+	AgentSessionRepository interface {
+		// Get returns a live session or ErrAgentSessionNotFound.
+		Get(namespace, id string) (*AgentSession, error)
+		// Save creates or replaces a session, keeping the CreatedAt of an existing one.
+		Save(session *AgentSession) error
+		// Delete removes a session; ErrAgentSessionNotFound when there is none.
+		Delete(namespace, id string) error
+		// DeleteExpired removes the sessions that expired before the given time; returns rows deleted.
+		DeleteExpired(before time.Time) (int64, error)
+	}
+)
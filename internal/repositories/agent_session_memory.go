@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// MemoryAgentSessionRepository is an in-memory AgentSessionRepository for dev and testing.
+type MemoryAgentSessionRepository struct {
+	mu       sync.RWMutex
+	sessions map[agentSessionKey]*AgentSession
+}
+
+type agentSessionKey struct {
+	namespace string
+	id        string
+}
+
+// NewMemoryAgentSessionRepository creates an empty memory agent session repository.
+func NewMemoryAgentSessionRepository() *MemoryAgentSessionRepository {
+	return &MemoryAgentSessionRepository{sessions: make(map[agentSessionKey]*AgentSession)}
+}
+
+// Get returns a copy of a live session.
+func (r *MemoryAgentSessionRepository) Get(namespace, id string) (*AgentSession, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	session, ok := r.sessions[agentSessionKey{namespace, id}]
+	if !ok || !session.ExpiresAt.After(time.Now()) {
+		return nil, ErrAgentSessionNotFound
+	}
+	return copyAgentSession(session), nil
+}
+
+// Save creates or replaces a session.
+func (r *MemoryAgentSessionRepository) Save(session *AgentSession) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := agentSessionKey{session.Namespace, session.ID}
+	stored := copyAgentSession(session)
+	if existing, ok := r.sessions[key]; ok {
+		stored.CreatedAt = existing.CreatedAt
+	}
+	r.sessions[key] = stored
+	return nil
+}
+
+// Delete removes a session.
+func (r *MemoryAgentSessionRepository) Delete(namespace, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := agentSessionKey{namespace, id}
+	if _, ok := r.sessions[key]; !ok {
+		return ErrAgentSessionNotFound
+	}
+	delete(r.sessions, key)
+	return nil
+}
+
+// DeleteExpired removes the sessions that expired before the given time.
+func (r *MemoryAgentSessionRepository) DeleteExpired(before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for key, session := range r.sessions {
+		if session.ExpiresAt.Before(before) {
+			delete(r.sessions, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func copyAgentSession(session *AgentSession) *AgentSession {
+	c := *session
+	c.Messages = slices.Clone(session.Messages)
+	return &c
+}
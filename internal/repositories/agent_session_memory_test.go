@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/open-source-cloud/fuse/pkg/llm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryAgentSessionRepository(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Get returns a copy of the saved session", func(t *testing.T) {
+		t.Parallel()
+		repo := NewMemoryAgentSessionRepository()
+		require.NoError(t, repo.Save(&AgentSession{
+			Namespace: "default",
+			ID:        "s-1",
+			Messages:  []llm.Message{{Role: llm.RoleUser, Content: "hi"}},
+			ExpiresAt: time.Now().Add(time.Hour),
+		}))
+
+		session, err := repo.Get("default", "s-1")
+		require.NoError(t, err)
+		session.Messages[0].Content = "changed"
+
+		again, err := repo.Get("default", "s-1")
+		require.NoError(t, err)
+		assert.Equal(t, "hi", again.Messages[0].Content)
+		_, err = repo.Get("billing", "s-1")
+		assert.ErrorIs(t, err, ErrAgentSessionNotFound)
+	})
+
+	t.Run("expired sessions read as not found until purged", func(t *testing.T) {
+		t.Parallel()
+		repo := NewMemoryAgentSessionRepository()
+		now := time.Now()
+		require.NoError(t, repo.Save(&AgentSession{Namespace: "default", ID: "old", ExpiresAt: now.Add(-time.Minute)}))
+		require.NoError(t, repo.Save(&AgentSession{Namespace: "default", ID: "live", ExpiresAt: now.Add(time.Hour)}))
+
+		_, err := repo.Get("default", "old")
+		assert.ErrorIs(t, err, ErrAgentSessionNotFound)
+
+		deleted, err := repo.DeleteExpired(now)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+		_, err = repo.Get("default", "live")
+		assert.NoError(t, err)
+	})
+
+	t.Run("Delete of a missing session is not found", func(t *testing.T) {
+		t.Parallel()
+		repo := NewMemoryAgentSessionRepository()
+		assert.ErrorIs(t, repo.Delete("default", "nope"), ErrAgentSessionNotFound)
+	})
+}
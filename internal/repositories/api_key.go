@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"github.com/open-source-cloud/fuse/internal/auth"
+)
+
+// ErrAPIKeyNotFound is returned when an API key is not found.
+var ErrAPIKeyNotFound = errors.New("api key not found")
+
+type (
+	// APIKeyRepository stores API keys. Only key hashes are stored; the plaintext key is shown
+	// once when it is created.
+	APIKeyRepository interface {
+		FindByID(id string) (*auth.APIKey, error)
+		FindAll() ([]*auth.APIKey, error)
+		Save(key *auth.APIKey) error
+		Revoke(id string, at time.Time) error
+	}
+)
@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/open-source-cloud/fuse/internal/auth"
+)
+
+// MemoryAPIKeyRepository is an in-memory APIKeyRepository for dev and testing.
+type MemoryAPIKeyRepository struct {
+	mu   sync.RWMutex
+	keys map[string]*auth.APIKey
+}
+
+// NewMemoryAPIKeyRepository creates an empty memory API key repository.
+func NewMemoryAPIKeyRepository() *MemoryAPIKeyRepository {
+	return &MemoryAPIKeyRepository{keys: make(map[string]*auth.APIKey)}
+}
+
+// FindByID finds an API key by id.
+func (r *MemoryAPIKeyRepository) FindByID(id string) (*auth.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key, ok := r.keys[id]
+	if !ok {
+		return nil, ErrAPIKeyNotFound
+	}
+	clone := *key
+	return &clone, nil
+}
+
+// FindAll returns all API keys, newest first.
+func (r *MemoryAPIKeyRepository) FindAll() ([]*auth.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]*auth.APIKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		clone := *key
+		keys = append(keys, &clone)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.After(keys[j].CreatedAt) })
+	return keys, nil
+}
+
+// Save upserts an API key.
+func (r *MemoryAPIKeyRepository) Save(key *auth.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clone := *key
+	r.keys[key.ID] = &clone
+	return nil
+}
+
+// Revoke marks an API key revoked; revoking twice keeps the first revocation time.
+func (r *MemoryAPIKeyRepository) Revoke(id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, ok := r.keys[id]
+	if !ok {
+		return ErrAPIKeyNotFound
+	}
+	if key.RevokedAt == nil {
+		key.RevokedAt = &at
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/open-source-cloud/fuse/internal/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryAPIKeyRepository(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Save and FindByID round-trip", func(t *testing.T) {
+		t.Parallel()
+		repo := NewMemoryAPIKeyRepository()
+		key, _, err := auth.NewAPIKey("ci", nil)
+		require.NoError(t, err)
+
+		require.NoError(t, repo.Save(key))
+		found, err := repo.FindByID(key.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, "ci", found.Name)
+		assert.Equal(t, key.Hash, found.Hash)
+	})
+
+	t.Run("FindByID returns ErrAPIKeyNotFound for unknown", func(t *testing.T) {
+		t.Parallel()
+		repo := NewMemoryAPIKeyRepository()
+		_, err := repo.FindByID("nope")
+		assert.ErrorIs(t, err, ErrAPIKeyNotFound)
+	})
+
+	t.Run("Revoke keeps the first revocation time", func(t *testing.T) {
+		t.Parallel()
+		repo := NewMemoryAPIKeyRepository()
+		key, _, err := auth.NewAPIKey("ci", nil)
+		require.NoError(t, err)
+		require.NoError(t, repo.Save(key))
+
+		first := time.Now().UTC()
+		require.NoError(t, repo.Revoke(key.ID, first))
+		require.NoError(t, repo.Revoke(key.ID, first.Add(time.Hour)))
+
+		found, err := repo.FindByID(key.ID)
+		require.NoError(t, err)
+		require.NotNil(t, found.RevokedAt)
+		assert.True(t, found.RevokedAt.Equal(first))
+		assert.ErrorIs(t, repo.Revoke("nope", first), ErrAPIKeyNotFound)
+	})
+}
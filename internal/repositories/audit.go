@@ -0,0 +1,13 @@
+package repositories
+
+import "github.com/open-source-cloud/fuse/internal/audit"
+
+type (
+	// AuditRepository stores the administrative audit log. It is append-only: entries are never
+	// updated or deleted.
+	AuditRepository interface {
+		Append(entry *audit.Entry) error
+		// Find returns the entries matching filter, newest first, at most filter.EffectiveLimit().
+		Find(filter audit.Filter) ([]*audit.Entry, error)
+	}
+)
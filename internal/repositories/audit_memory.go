@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"maps"
+	"sync"
+
+	"github.com/open-source-cloud/fuse/internal/audit"
+)
+
+// MemoryAuditRepository is an in-memory AuditRepository for dev and testing.
+type MemoryAuditRepository struct {
+	mu      sync.RWMutex
+	entries []*audit.Entry
+}
+
+// NewMemoryAuditRepository creates an empty memory audit repository.
+func NewMemoryAuditRepository() *MemoryAuditRepository {
+	return &MemoryAuditRepository{}
+}
+
+// Append stores a copy of entry.
+func (r *MemoryAuditRepository) Append(entry *audit.Entry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *entry
+	stored.Details = maps.Clone(entry.Details)
+	r.entries = append(r.entries, &stored)
+	return nil
+}
+
+// Find returns copies of the matching entries, newest first.
+func (r *MemoryAuditRepository) Find(filter audit.Filter) ([]*audit.Entry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	limit := filter.EffectiveLimit()
+	result := make([]*audit.Entry, 0)
+	for i := len(r.entries) - 1; i >= 0 && len(result) < limit; i-- {
+		if filter.Matches(r.entries[i]) {
+			entry := *r.entries[i]
+			result = append(result, &entry)
+		}
+	}
+	return result, nil
+}
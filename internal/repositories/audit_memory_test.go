@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/open-source-cloud/fuse/internal/audit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryAuditRepository(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Find returns matching entries newest first", func(t *testing.T) {
+		t.Parallel()
+		repo := NewMemoryAuditRepository()
+		start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		for i := range 5 {
+			action := audit.ActionSchemaUpsert
+			if i%2 == 1 {
+				action = audit.ActionCredentialSave
+			}
+			require.NoError(t, repo.Append(&audit.Entry{
+				ID: fmt.Sprintf("id-%d", i), Time: start.Add(time.Duration(i) * time.Minute),
+				Actor: "apikey:ak1", Action: action, ResourceType: audit.ResourceSchema, Resource: "orders",
+			}))
+		}
+
+		entries, err := repo.Find(audit.Filter{Action: audit.ActionSchemaUpsert})
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+		assert.Equal(t, "id-4", entries[0].ID)
+		assert.Equal(t, "id-0", entries[2].ID)
+
+		entries, err = repo.Find(audit.Filter{Before: "id-4", Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Equal(t, "id-3", entries[0].ID)
+
+		entries, err = repo.Find(audit.Filter{Since: start.Add(time.Minute), Until: start.Add(3 * time.Minute)})
+		require.NoError(t, err)
+		assert.Len(t, entries, 2)
+	})
+
+	t.Run("stored entries are isolated from the caller", func(t *testing.T) {
+		t.Parallel()
+		repo := NewMemoryAuditRepository()
+		entry := &audit.Entry{ID: "a", Details: map[string]string{"version": "1"}}
+		require.NoError(t, repo.Append(entry))
+		entry.Details["version"] = "2"
+
+		entries, err := repo.Find(audit.Filter{})
+		require.NoError(t, err)
+		assert.Equal(t, "1", entries[0].Details["version"])
+	})
+}
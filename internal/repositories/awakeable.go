@@ -15,4 +15,9 @@ type AwakeableRepository interface {
 	FindByID(id string) (*workflow.Awakeable, error)
 	FindPending(workflowID string) ([]*workflow.Awakeable, error)
 	Resolve(id string, result map[string]any) error
+	// Reopen returns a resolved awakeable to pending, for a resolution that never reached its
+	// workflow
+	Reopen(id string) error
+	// DeleteByWorkflowIDs removes all awakeables of the given workflows; returns rows deleted
+	DeleteByWorkflowIDs(workflowIDs []string) (int64, error)
 }
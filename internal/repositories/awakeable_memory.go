@@ -56,10 +56,41 @@ func (r *MemoryAwakeableRepository) Resolve(id string, result map[string]any) er
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	awakeable, exists := r.awakeables[id]
-	if !exists {
+	if !exists || awakeable.Status != workflow.AwakeablePending {
 		return ErrAwakeableNotFound
 	}
 	awakeable.Status = workflow.AwakeableResolved
 	awakeable.Result = result
 	return nil
 }
+
+// Reopen returns a resolved awakeable to pending
+func (r *MemoryAwakeableRepository) Reopen(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	awakeable, exists := r.awakeables[id]
+	if !exists || awakeable.Status != workflow.AwakeableResolved {
+		return ErrAwakeableNotFound
+	}
+	awakeable.Status = workflow.AwakeablePending
+	awakeable.Result = nil
+	return nil
+}
+
+// DeleteByWorkflowIDs removes all awakeables belonging to the given workflows
+func (r *MemoryAwakeableRepository) DeleteByWorkflowIDs(workflowIDs []string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ids := make(map[string]struct{}, len(workflowIDs))
+	for _, id := range workflowIDs {
+		ids[id] = struct{}{}
+	}
+	var deleted int64
+	for id, a := range r.awakeables {
+		if _, ok := ids[a.WorkflowID.String()]; ok {
+			delete(r.awakeables, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
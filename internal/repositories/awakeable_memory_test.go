@@ -85,3 +85,36 @@ func TestMemoryAwakeableRepository_Resolve_NotFound(t *testing.T) {
 
 	assert.ErrorIs(t, err, ErrAwakeableNotFound)
 }
+
+func TestMemoryAwakeableRepository_ResolveOnceAndReopen(t *testing.T) {
+	repo := NewMemoryAwakeableRepository()
+	_ = repo.Save(newTestAwakeable("awk-1", pkgworkflow.NewID()))
+
+	require.NoError(t, repo.Resolve("awk-1", map[string]any{"approved": true}))
+	assert.ErrorIs(t, repo.Resolve("awk-1", map[string]any{}), ErrAwakeableNotFound, "only a pending awakeable resolves")
+
+	require.NoError(t, repo.Reopen("awk-1"))
+	found, err := repo.FindByID("awk-1")
+	require.NoError(t, err)
+	assert.Equal(t, workflow.AwakeablePending, found.Status)
+	assert.Nil(t, found.Result)
+	assert.ErrorIs(t, repo.Reopen("awk-1"), ErrAwakeableNotFound, "only a resolved awakeable reopens")
+}
+
+func TestMemoryAwakeableRepository_DeleteByWorkflowIDs(t *testing.T) {
+	repo := NewMemoryAwakeableRepository()
+	wfID := pkgworkflow.NewID()
+	otherID := pkgworkflow.NewID()
+	require.NoError(t, repo.Save(newTestAwakeable("awk-1", wfID)))
+	require.NoError(t, repo.Save(newTestAwakeable("awk-2", wfID)))
+	require.NoError(t, repo.Save(newTestAwakeable("awk-3", otherID)))
+
+	deleted, err := repo.DeleteByWorkflowIDs([]string{wfID.String()})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+	_, err = repo.FindByID("awk-1")
+	assert.ErrorIs(t, err, ErrAwakeableNotFound)
+	_, err = repo.FindByID("awk-3")
+	assert.NoError(t, err)
+}
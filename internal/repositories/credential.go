@@ -10,12 +10,13 @@ import (
 var ErrCredentialNotFound = errors.New("credential not found")
 
 type (
-	// CredentialRepository stores credential metadata (ADR-0031 Option B). Field VALUES are not
-	// stored here; they live in the SecretStore at cred/<id>/<field>, per environment.
+	// CredentialRepository stores credential metadata (ADR-0031 Option B) per namespace. Field
+	// VALUES are not stored here; they live in the SecretStore at cred/<id>/<field>, per namespace
+	// and environment.
 	CredentialRepository interface {
-		FindByID(id string) (*workflow.Credential, error)
-		FindAll() ([]*workflow.Credential, error)
-		Save(cred *workflow.Credential) error
-		Delete(id string) error
+		FindByID(namespace, id string) (*workflow.Credential, error)
+		FindAll(namespace string) ([]*workflow.Credential, error)
+		Save(namespace string, cred *workflow.Credential) error
+		Delete(namespace, id string) error
 	}
 )
@@ -10,53 +10,56 @@ import (
 // MemoryCredentialRepository is an in-memory CredentialRepository for dev and testing.
 type MemoryCredentialRepository struct {
 	mu          sync.RWMutex
-	credentials map[string]*workflow.Credential
+	credentials map[string]map[string]*workflow.Credential // namespace -> id -> credential
 }
 
 // NewMemoryCredentialRepository creates an empty memory credential repository.
 func NewMemoryCredentialRepository() *MemoryCredentialRepository {
-	return &MemoryCredentialRepository{credentials: make(map[string]*workflow.Credential)}
+	return &MemoryCredentialRepository{credentials: make(map[string]map[string]*workflow.Credential)}
 }
 
-// FindByID finds a credential by id.
-func (r *MemoryCredentialRepository) FindByID(id string) (*workflow.Credential, error) {
+// FindByID finds a credential of a namespace by id.
+func (r *MemoryCredentialRepository) FindByID(namespace, id string) (*workflow.Credential, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	cred, ok := r.credentials[id]
+	cred, ok := r.credentials[namespace][id]
 	if !ok {
 		return nil, ErrCredentialNotFound
 	}
 	return cred, nil
 }
 
-// FindAll returns all credentials sorted by id.
-func (r *MemoryCredentialRepository) FindAll() ([]*workflow.Credential, error) {
+// FindAll returns the credentials of a namespace sorted by id.
+func (r *MemoryCredentialRepository) FindAll(namespace string) ([]*workflow.Credential, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	creds := make([]*workflow.Credential, 0, len(r.credentials))
-	for _, cred := range r.credentials {
+	creds := make([]*workflow.Credential, 0, len(r.credentials[namespace]))
+	for _, cred := range r.credentials[namespace] {
 		creds = append(creds, cred)
 	}
 	sort.Slice(creds, func(i, j int) bool { return creds[i].ID < creds[j].ID })
 	return creds, nil
 }
 
-// Save upserts a credential.
-func (r *MemoryCredentialRepository) Save(cred *workflow.Credential) error {
+// Save upserts a credential in a namespace.
+func (r *MemoryCredentialRepository) Save(namespace string, cred *workflow.Credential) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	r.credentials[cred.ID] = cred
+	if r.credentials[namespace] == nil {
+		r.credentials[namespace] = make(map[string]*workflow.Credential)
+	}
+	r.credentials[namespace][cred.ID] = cred
 	return nil
 }
 
-// Delete removes a credential by id.
-func (r *MemoryCredentialRepository) Delete(id string) error {
+// Delete removes a credential of a namespace by id.
+func (r *MemoryCredentialRepository) Delete(namespace, id string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	delete(r.credentials, id)
+	delete(r.credentials[namespace], id)
 	return nil
 }
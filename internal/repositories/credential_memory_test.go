@@ -10,13 +10,14 @@ import (
 
 func TestMemoryCredentialRepository(t *testing.T) {
 	t.Parallel()
+	ns := workflow.DefaultNamespaceName
 
 	t.Run("Save and FindByID round-trip", func(t *testing.T) {
 		t.Parallel()
 		repo := NewMemoryCredentialRepository()
 
-		require.NoError(t, repo.Save(workflow.NewCredential("openai-prod", "openai", "Prod", []string{"apiKey"})))
-		cred, err := repo.FindByID("openai-prod")
+		require.NoError(t, repo.Save(ns, workflow.NewCredential("openai-prod", "openai", "Prod", []string{"apiKey"})))
+		cred, err := repo.FindByID(ns, "openai-prod")
 
 		require.NoError(t, err)
 		assert.Equal(t, "openai", cred.Type)
@@ -26,17 +27,17 @@ func TestMemoryCredentialRepository(t *testing.T) {
 	t.Run("FindByID returns ErrCredentialNotFound for unknown", func(t *testing.T) {
 		t.Parallel()
 		repo := NewMemoryCredentialRepository()
-		_, err := repo.FindByID("nope")
+		_, err := repo.FindByID(ns, "nope")
 		assert.ErrorIs(t, err, ErrCredentialNotFound)
 	})
 
 	t.Run("FindAll returns sorted credentials", func(t *testing.T) {
 		t.Parallel()
 		repo := NewMemoryCredentialRepository()
-		require.NoError(t, repo.Save(workflow.NewCredential("b-cred", "custom", "", nil)))
-		require.NoError(t, repo.Save(workflow.NewCredential("a-cred", "custom", "", nil)))
+		require.NoError(t, repo.Save(ns, workflow.NewCredential("b-cred", "custom", "", nil)))
+		require.NoError(t, repo.Save(ns, workflow.NewCredential("a-cred", "custom", "", nil)))
 
-		creds, err := repo.FindAll()
+		creds, err := repo.FindAll(ns)
 		require.NoError(t, err)
 		require.Len(t, creds, 2)
 		assert.Equal(t, "a-cred", creds[0].ID)
@@ -46,10 +47,26 @@ func TestMemoryCredentialRepository(t *testing.T) {
 	t.Run("Delete removes a credential", func(t *testing.T) {
 		t.Parallel()
 		repo := NewMemoryCredentialRepository()
-		require.NoError(t, repo.Save(workflow.NewCredential("c1", "custom", "", nil)))
+		require.NoError(t, repo.Save(ns, workflow.NewCredential("c1", "custom", "", nil)))
 
-		require.NoError(t, repo.Delete("c1"))
-		_, err := repo.FindByID("c1")
+		require.NoError(t, repo.Delete(ns, "c1"))
+		_, err := repo.FindByID(ns, "c1")
 		assert.ErrorIs(t, err, ErrCredentialNotFound)
 	})
+
+	t.Run("namespaces are isolated", func(t *testing.T) {
+		t.Parallel()
+		repo := NewMemoryCredentialRepository()
+		require.NoError(t, repo.Save("billing", workflow.NewCredential("stripe", "custom", "", nil)))
+
+		_, err := repo.FindByID(ns, "stripe")
+		assert.ErrorIs(t, err, ErrCredentialNotFound)
+		creds, err := repo.FindAll(ns)
+		require.NoError(t, err)
+		assert.Empty(t, creds)
+
+		cred, err := repo.FindByID("billing", "stripe")
+		require.NoError(t, err)
+		assert.Equal(t, "stripe", cred.ID)
+	})
 }
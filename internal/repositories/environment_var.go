@@ -0,0 +1,12 @@
+package repositories
+
+type (
+	// EnvironmentVarRepository stores non-secret environment variables per namespace and
+	// environment. Values are plain text: they are neither encrypted nor redacted.
+	EnvironmentVarRepository interface {
+		// FindAll returns the variables of a namespace's environment (an empty map when none).
+		FindAll(namespace, environment string) (map[string]string, error)
+		Set(namespace, environment, name, value string) error
+		Delete(namespace, environment, name string) error
+	}
+)
@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"maps"
+	"sync"
+)
+
+// MemoryEnvironmentVarRepository is an in-memory EnvironmentVarRepository for dev and testing.
+type MemoryEnvironmentVarRepository struct {
+	mu   sync.RWMutex
+	vars map[envVarScope]map[string]string
+}
+
+type envVarScope struct {
+	namespace   string
+	environment string
+}
+
+// NewMemoryEnvironmentVarRepository creates an empty memory environment variable repository.
+func NewMemoryEnvironmentVarRepository() *MemoryEnvironmentVarRepository {
+	return &MemoryEnvironmentVarRepository{vars: make(map[envVarScope]map[string]string)}
+}
+
+// FindAll returns a copy of the variables of a namespace's environment.
+func (r *MemoryEnvironmentVarRepository) FindAll(namespace, environment string) (map[string]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	vars := make(map[string]string, len(r.vars[envVarScope{namespace, environment}]))
+	maps.Copy(vars, r.vars[envVarScope{namespace, environment}])
+	return vars, nil
+}
+
+// Set upserts a variable.
+func (r *MemoryEnvironmentVarRepository) Set(namespace, environment, name, value string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	scope := envVarScope{namespace, environment}
+	if r.vars[scope] == nil {
+		r.vars[scope] = make(map[string]string)
+	}
+	r.vars[scope][name] = value
+	return nil
+}
+
+// Delete removes a variable; deleting a missing one is not an error.
+func (r *MemoryEnvironmentVarRepository) Delete(namespace, environment, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.vars[envVarScope{namespace, environment}], name)
+	return nil
+}
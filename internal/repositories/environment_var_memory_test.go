@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryEnvironmentVarRepository(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Set and FindAll are scoped by namespace and environment", func(t *testing.T) {
+		t.Parallel()
+		repo := NewMemoryEnvironmentVarRepository()
+		require.NoError(t, repo.Set("default", "prod", "API_BASE_URL", "https://api.example.com"))
+		require.NoError(t, repo.Set("default", "staging", "API_BASE_URL", "https://staging.example.com"))
+		require.NoError(t, repo.Set("billing", "prod", "BUCKET", "billing-prod"))
+
+		vars, err := repo.FindAll("default", "prod")
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"API_BASE_URL": "https://api.example.com"}, vars)
+	})
+
+	t.Run("FindAll returns an empty map for an unknown scope", func(t *testing.T) {
+		t.Parallel()
+		repo := NewMemoryEnvironmentVarRepository()
+
+		vars, err := repo.FindAll("default", "nope")
+
+		require.NoError(t, err)
+		assert.Empty(t, vars)
+		assert.NotNil(t, vars)
+	})
+
+	t.Run("FindAll returns a copy", func(t *testing.T) {
+		t.Parallel()
+		repo := NewMemoryEnvironmentVarRepository()
+		require.NoError(t, repo.Set("default", "prod", "FLAG", "on"))
+
+		vars, _ := repo.FindAll("default", "prod")
+		vars["FLAG"] = "off"
+
+		again, _ := repo.FindAll("default", "prod")
+		assert.Equal(t, "on", again["FLAG"])
+	})
+
+	t.Run("Delete removes a variable", func(t *testing.T) {
+		t.Parallel()
+		repo := NewMemoryEnvironmentVarRepository()
+		require.NoError(t, repo.Set("default", "prod", "FLAG", "on"))
+
+		require.NoError(t, repo.Delete("default", "prod", "FLAG"))
+		require.NoError(t, repo.Delete("default", "prod", "FLAG"))
+
+		vars, _ := repo.FindAll("default", "prod")
+		assert.Empty(t, vars)
+	})
+}
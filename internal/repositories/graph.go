@@ -19,8 +19,9 @@ var (
 type (
 	// GraphSchemaListItem is lightweight metadata for listing stored graph schemas.
 	GraphSchemaListItem struct {
-		SchemaID string
-		Name     string
+		SchemaID  string
+		Name      string
+		Lifecycle workflow.SchemaLifecycle
 	}
 	// GraphRepository defines the interface o a GraphRepository repository
 	GraphRepository interface {
@@ -40,5 +41,16 @@ type (
 		SetActiveVersion(schemaID string, version int) error
 		// GetVersionHistory returns aggregate version metadata for a schema.
 		GetVersionHistory(schemaID string) (*workflow.SchemaVersionHistory, error)
+
+		// FindLifecycle returns the lifecycle state of a schema.
+		FindLifecycle(schemaID string) (workflow.SchemaLifecycle, error)
+		// SetLifecycle updates the lifecycle state of a schema without creating a new version.
+		SetLifecycle(schemaID string, lifecycle workflow.SchemaLifecycle) error
+		// FindTrafficSplit returns the traffic split of a schema, nil when none is configured.
+		FindTrafficSplit(schemaID string) (*workflow.TrafficSplit, error)
+		// SetTrafficSplit stores the traffic split of a schema; a nil split removes it.
+		SetTrafficSplit(schemaID string, split *workflow.TrafficSplit) error
+		// Delete removes a schema together with all of its versions.
+		Delete(schemaID string) error
 	}
 )
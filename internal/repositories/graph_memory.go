@@ -16,6 +16,8 @@ type MemoryGraphRepository struct {
 	graphs         map[string]*workflow.Graph
 	versions       map[string][]workflow.SchemaVersion
 	activeVersions map[string]int
+	lifecycles     map[string]workflow.SchemaLifecycle
+	trafficSplits  map[string]*workflow.TrafficSplit
 }
 
 // NewMemoryGraphRepository creates a new in-memory GraphRepository
@@ -24,6 +26,8 @@ func NewMemoryGraphRepository() GraphRepository {
 		graphs:         make(map[string]*workflow.Graph),
 		versions:       make(map[string][]workflow.SchemaVersion),
 		activeVersions: make(map[string]int),
+		lifecycles:     make(map[string]workflow.SchemaLifecycle),
+		trafficSplits:  make(map[string]*workflow.TrafficSplit),
 	}
 }
 
@@ -60,7 +64,7 @@ func (m *MemoryGraphRepository) List() ([]GraphSchemaListItem, error) {
 	out := make([]GraphSchemaListItem, 0, len(m.graphs))
 	for _, g := range m.graphs {
 		s := g.Schema()
-		out = append(out, GraphSchemaListItem{SchemaID: s.ID, Name: s.Name})
+		out = append(out, GraphSchemaListItem{SchemaID: s.ID, Name: s.Name, Lifecycle: m.lifecycleLocked(s.ID)})
 	}
 	slices.SortFunc(out, func(a, b GraphSchemaListItem) int {
 		if a.SchemaID < b.SchemaID {
@@ -198,3 +202,76 @@ func (m *MemoryGraphRepository) GetVersionHistory(schemaID string) (*workflow.Sc
 		TotalVersions: len(versions),
 	}, nil
 }
+
+// FindLifecycle returns the lifecycle state of a schema; schemas never transitioned are active.
+func (m *MemoryGraphRepository) FindLifecycle(schemaID string) (workflow.SchemaLifecycle, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if _, ok := m.graphs[schemaID]; !ok {
+		return "", ErrGraphNotFound
+	}
+	return m.lifecycleLocked(schemaID), nil
+}
+
+// SetLifecycle updates the lifecycle state of a schema.
+func (m *MemoryGraphRepository) SetLifecycle(schemaID string, lifecycle workflow.SchemaLifecycle) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.graphs[schemaID]; !ok {
+		return ErrGraphNotFound
+	}
+	m.lifecycles[schemaID] = lifecycle
+	return nil
+}
+
+// FindTrafficSplit returns the traffic split of a schema, nil when none is configured.
+func (m *MemoryGraphRepository) FindTrafficSplit(schemaID string) (*workflow.TrafficSplit, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if _, ok := m.graphs[schemaID]; !ok {
+		return nil, ErrGraphNotFound
+	}
+	return m.trafficSplits[schemaID], nil
+}
+
+// SetTrafficSplit stores the traffic split of a schema; a nil split removes it.
+func (m *MemoryGraphRepository) SetTrafficSplit(schemaID string, split *workflow.TrafficSplit) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.graphs[schemaID]; !ok {
+		return ErrGraphNotFound
+	}
+	if split == nil {
+		delete(m.trafficSplits, schemaID)
+		return nil
+	}
+	m.trafficSplits[schemaID] = split
+	return nil
+}
+
+// Delete removes a schema together with all of its versions.
+func (m *MemoryGraphRepository) Delete(schemaID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.graphs[schemaID]; !ok {
+		return ErrGraphNotFound
+	}
+	delete(m.graphs, schemaID)
+	delete(m.versions, schemaID)
+	delete(m.activeVersions, schemaID)
+	delete(m.lifecycles, schemaID)
+	delete(m.trafficSplits, schemaID)
+	return nil
+}
+
+func (m *MemoryGraphRepository) lifecycleLocked(schemaID string) workflow.SchemaLifecycle {
+	if lifecycle, ok := m.lifecycles[schemaID]; ok {
+		return lifecycle
+	}
+	return workflow.SchemaActive
+}
@@ -1,6 +1,7 @@
 package repositories_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/open-source-cloud/fuse/internal/mocks"
@@ -32,3 +33,60 @@ func TestMemoryGraphRepository(t *testing.T) {
 		t.Fatalf("graph ID should be %s, got %s", graph.ID(), existingGraph.ID())
 	}
 }
+
+func TestMemoryGraphRepository_Lifecycle(t *testing.T) {
+	repo := repositories.NewMemoryGraphRepository()
+	graph, err := workflow.NewGraph(mocks.SmallTestGraphSchema())
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+	if err := repo.Save(graph); err != nil {
+		t.Fatalf("failed to save graph: %v", err)
+	}
+
+	lifecycle, err := repo.FindLifecycle(graph.ID())
+	if err != nil || lifecycle != workflow.SchemaActive {
+		t.Fatalf("new schema should be active, got %q (err %v)", lifecycle, err)
+	}
+
+	if err := repo.SetLifecycle(graph.ID(), workflow.SchemaArchived); err != nil {
+		t.Fatalf("failed to set lifecycle: %v", err)
+	}
+	items, err := repo.List()
+	if err != nil || len(items) != 1 || items[0].Lifecycle != workflow.SchemaArchived {
+		t.Fatalf("list should report the archived lifecycle, got %+v (err %v)", items, err)
+	}
+
+	if err := repo.SetLifecycle("missing", workflow.SchemaArchived); !errors.Is(err, repositories.ErrGraphNotFound) {
+		t.Fatalf("expected ErrGraphNotFound, got %v", err)
+	}
+}
+
+func TestMemoryGraphRepository_Delete(t *testing.T) {
+	repo := repositories.NewMemoryGraphRepository()
+	schema := mocks.SmallTestGraphSchema()
+	graph, err := workflow.NewGraph(schema)
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+	if err := repo.Save(graph); err != nil {
+		t.Fatalf("failed to save graph: %v", err)
+	}
+	if err := repo.SaveVersion(&workflow.SchemaVersion{SchemaID: schema.ID, Version: 1, Schema: schema.Clone(), IsActive: true}); err != nil {
+		t.Fatalf("failed to save version: %v", err)
+	}
+
+	if err := repo.Delete(schema.ID); err != nil {
+		t.Fatalf("failed to delete schema: %v", err)
+	}
+
+	if _, err := repo.FindByID(schema.ID); !errors.Is(err, repositories.ErrGraphNotFound) {
+		t.Fatalf("expected ErrGraphNotFound after delete, got %v", err)
+	}
+	if _, err := repo.FindByIDAndVersion(schema.ID, 1); !errors.Is(err, repositories.ErrSchemaVersionNotFound) {
+		t.Fatalf("expected versions to be deleted, got %v", err)
+	}
+	if err := repo.Delete(schema.ID); !errors.Is(err, repositories.ErrGraphNotFound) {
+		t.Fatalf("expected ErrGraphNotFound on second delete, got %v", err)
+	}
+}
@@ -17,4 +17,7 @@ type JournalRepository interface {
 
 	// FindFailed returns all step:failed journal entries for the given workflow
 	FindFailed(workflowID string) ([]workflow.JournalEntry, error)
+
+	// DeleteByWorkflowIDs removes the journals of the given workflows; returns entries deleted
+	DeleteByWorkflowIDs(workflowIDs []string) (int64, error)
 }
@@ -73,3 +73,15 @@ func (m *MemoryJournalRepository) LastSequence(workflowID string) (uint64, error
 	}
 	return maxSeq, nil
 }
+
+// DeleteByWorkflowIDs removes the journals of the given workflows
+func (m *MemoryJournalRepository) DeleteByWorkflowIDs(workflowIDs []string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var deleted int64
+	for _, id := range workflowIDs {
+		deleted += int64(len(m.journals[id]))
+		delete(m.journals, id)
+	}
+	return deleted, nil
+}
@@ -105,3 +105,23 @@ func TestMemoryJournalRepository_IsolatesWorkflows(t *testing.T) {
 	require.Len(t, entries2, 1)
 	assert.Equal(t, workflow.JournalStepStarted, entries2[0].Type)
 }
+
+func TestMemoryJournalRepository_DeleteByWorkflowIDs(t *testing.T) {
+	// Arrange
+	repo := repositories.NewMemoryJournalRepository()
+	require.NoError(t, repo.Append("wf-1", workflow.JournalEntry{Sequence: 1}, workflow.JournalEntry{Sequence: 2}))
+	require.NoError(t, repo.Append("wf-2", workflow.JournalEntry{Sequence: 1}))
+
+	// Act
+	deleted, err := repo.DeleteByWorkflowIDs([]string{"wf-1"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+	loaded, err := repo.LoadAll("wf-1")
+	require.NoError(t, err)
+	assert.Empty(t, loaded)
+	loaded, err = repo.LoadAll("wf-2")
+	require.NoError(t, err)
+	assert.Len(t, loaded, 1)
+}
@@ -0,0 +1,36 @@
+package repositories
+
+import "time"
+
+type (
+	// LLMSpend is accumulated LLM spend: the priced cost and the total tokens of the calls.
+	LLMSpend struct {
+		CostUSD float64
+		Tokens  int
+	}
+
+	// LLMSpendRepository accumulates the LLM spend that schema budgets are enforced against, per
+	// workflow execution and per schema, environment and UTC day. Add is an atomic increment so
+	// concurrent ai nodes of one execution, or executions on different cluster nodes, never lose
+	// spend.
+	LLMSpendRepository interface {
+		// AddExecution adds spend to a workflow execution's total and returns the new total.
+		AddExecution(workflowID string, spend LLMSpend) (LLMSpend, error)
+		// GetExecution returns a workflow execution's total (zero when it has no spend).
+		GetExecution(workflowID string) (LLMSpend, error)
+		// AddDaily adds spend to a schema's total for an environment on day's UTC date and returns
+		// the new total.
+		AddDaily(schemaID, environment string, day time.Time, spend LLMSpend) (LLMSpend, error)
+		// GetDaily returns a schema's total for an environment on day's UTC date.
+		GetDaily(schemaID, environment string, day time.Time) (LLMSpend, error)
+		// DeleteByWorkflowIDs removes the execution totals of the given workflows; returns rows
+		// deleted. Daily totals are kept: they are small and not tied to one execution.
+		DeleteByWorkflowIDs(workflowIDs []string) (int64, error)
+	}
+)
+
+// SpendDay truncates t to its UTC date, the key of daily spend totals.
+func SpendDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
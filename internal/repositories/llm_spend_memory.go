@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"sync"
+	"time"
+)
+
+// MemoryLLMSpendRepository is an in-memory LLMSpendRepository for dev and testing.
+type MemoryLLMSpendRepository struct {
+	mu         sync.Mutex
+	executions map[string]LLMSpend
+	daily      map[dailySpendKey]LLMSpend
+}
+
+type dailySpendKey struct {
+	schemaID    string
+	environment string
+	day         time.Time
+}
+
+// NewMemoryLLMSpendRepository creates an empty memory LLM spend repository.
+func NewMemoryLLMSpendRepository() *MemoryLLMSpendRepository {
+	return &MemoryLLMSpendRepository{
+		executions: make(map[string]LLMSpend),
+		daily:      make(map[dailySpendKey]LLMSpend),
+	}
+}
+
+// AddExecution adds spend to a workflow execution's total.
+func (r *MemoryLLMSpendRepository) AddExecution(workflowID string, spend LLMSpend) (LLMSpend, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	total := addSpend(r.executions[workflowID], spend)
+	r.executions[workflowID] = total
+	return total, nil
+}
+
+// GetExecution returns a workflow execution's total.
+func (r *MemoryLLMSpendRepository) GetExecution(workflowID string) (LLMSpend, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.executions[workflowID], nil
+}
+
+// AddDaily adds spend to a schema's daily total for an environment.
+func (r *MemoryLLMSpendRepository) AddDaily(schemaID, environment string, day time.Time, spend LLMSpend) (LLMSpend, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := dailySpendKey{schemaID, environment, SpendDay(day)}
+	total := addSpend(r.daily[key], spend)
+	r.daily[key] = total
+	return total, nil
+}
+
+// GetDaily returns a schema's daily total for an environment.
+func (r *MemoryLLMSpendRepository) GetDaily(schemaID, environment string, day time.Time) (LLMSpend, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.daily[dailySpendKey{schemaID, environment, SpendDay(day)}], nil
+}
+
+// DeleteByWorkflowIDs removes the execution totals of the given workflows.
+func (r *MemoryLLMSpendRepository) DeleteByWorkflowIDs(workflowIDs []string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for _, id := range workflowIDs {
+		if _, ok := r.executions[id]; ok {
+			delete(r.executions, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func addSpend(total, spend LLMSpend) LLMSpend {
+	return LLMSpend{CostUSD: total.CostUSD + spend.CostUSD, Tokens: total.Tokens + spend.Tokens}
+}
@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryLLMSpendRepository(t *testing.T) {
+	t.Parallel()
+
+	t.Run("execution totals accumulate per workflow", func(t *testing.T) {
+		t.Parallel()
+		repo := NewMemoryLLMSpendRepository()
+		_, err := repo.AddExecution("wf-1", LLMSpend{CostUSD: 0.25, Tokens: 100})
+		require.NoError(t, err)
+		total, err := repo.AddExecution("wf-1", LLMSpend{CostUSD: 0.5, Tokens: 50})
+		require.NoError(t, err)
+		assert.Equal(t, LLMSpend{CostUSD: 0.75, Tokens: 150}, total)
+
+		other, err := repo.GetExecution("wf-2")
+		require.NoError(t, err)
+		assert.Zero(t, other)
+	})
+
+	t.Run("daily totals are keyed by UTC date", func(t *testing.T) {
+		t.Parallel()
+		repo := NewMemoryLLMSpendRepository()
+		morning := time.Date(2026, 3, 1, 1, 0, 0, 0, time.UTC)
+		evening := time.Date(2026, 3, 1, 23, 0, 0, 0, time.UTC)
+		_, err := repo.AddDaily("s", "prod", morning, LLMSpend{Tokens: 10})
+		require.NoError(t, err)
+		_, err = repo.AddDaily("s", "prod", evening, LLMSpend{Tokens: 20})
+		require.NoError(t, err)
+		_, err = repo.AddDaily("s", "prod", evening.Add(2*time.Hour), LLMSpend{Tokens: 40})
+		require.NoError(t, err)
+		_, err = repo.AddDaily("s", "staging", morning, LLMSpend{Tokens: 80})
+		require.NoError(t, err)
+
+		total, err := repo.GetDaily("s", "prod", morning)
+		require.NoError(t, err)
+		assert.Equal(t, 30, total.Tokens)
+	})
+
+	t.Run("DeleteByWorkflowIDs removes execution totals", func(t *testing.T) {
+		t.Parallel()
+		repo := NewMemoryLLMSpendRepository()
+		_, err := repo.AddExecution("wf-1", LLMSpend{Tokens: 1})
+		require.NoError(t, err)
+
+		deleted, err := repo.DeleteByWorkflowIDs([]string{"wf-1", "missing"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+		total, err := repo.GetExecution("wf-1")
+		require.NoError(t, err)
+		assert.Zero(t, total)
+	})
+}
@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"errors"
+
+	"github.com/open-source-cloud/fuse/pkg/mcp"
+)
+
+// ErrMCPServerNotFound is returned when an MCP server registration is not found.
+var ErrMCPServerNotFound = errors.New("mcp server not found")
+
+type (
+	// MCPServerRepository stores the MCP servers registered per namespace. Registrations hold
+	// secret and credential references, never the values they resolve to.
+	MCPServerRepository interface {
+		FindByID(namespace, id string) (*mcp.ServerConfig, error)
+		FindAll(namespace string) ([]*mcp.ServerConfig, error)
+		Save(namespace string, server *mcp.ServerConfig) error
+		Delete(namespace, id string) error
+	}
+)
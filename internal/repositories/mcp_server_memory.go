@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/open-source-cloud/fuse/pkg/mcp"
+)
+
+// MemoryMCPServerRepository is an in-memory MCPServerRepository for dev and testing.
+type MemoryMCPServerRepository struct {
+	mu      sync.RWMutex
+	servers map[string]map[string]*mcp.ServerConfig // namespace -> id -> server
+}
+
+// NewMemoryMCPServerRepository creates an empty memory MCP server repository.
+func NewMemoryMCPServerRepository() *MemoryMCPServerRepository {
+	return &MemoryMCPServerRepository{servers: make(map[string]map[string]*mcp.ServerConfig)}
+}
+
+// FindByID finds a server of a namespace by id.
+func (r *MemoryMCPServerRepository) FindByID(namespace, id string) (*mcp.ServerConfig, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	server, ok := r.servers[namespace][id]
+	if !ok {
+		return nil, ErrMCPServerNotFound
+	}
+	return server, nil
+}
+
+// FindAll returns the servers of a namespace sorted by id.
+func (r *MemoryMCPServerRepository) FindAll(namespace string) ([]*mcp.ServerConfig, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	servers := make([]*mcp.ServerConfig, 0, len(r.servers[namespace]))
+	for _, server := range r.servers[namespace] {
+		servers = append(servers, server)
+	}
+	sort.Slice(servers, func(i, j int) bool { return servers[i].ID < servers[j].ID })
+	return servers, nil
+}
+
+// Save upserts a server in a namespace.
+func (r *MemoryMCPServerRepository) Save(namespace string, server *mcp.ServerConfig) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.servers[namespace] == nil {
+		r.servers[namespace] = make(map[string]*mcp.ServerConfig)
+	}
+	r.servers[namespace][server.ID] = server
+	return nil
+}
+
+// Delete removes a server of a namespace by id.
+func (r *MemoryMCPServerRepository) Delete(namespace, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.servers[namespace], id)
+	return nil
+}
@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/open-source-cloud/fuse/pkg/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryMCPServerRepository(t *testing.T) {
+	t.Parallel()
+	repo := NewMemoryMCPServerRepository()
+	require.NoError(t, repo.Save("default", &mcp.ServerConfig{ID: "wiki", Transport: mcp.TransportHTTP, URL: "https://wiki.example.com/mcp"}))
+	require.NoError(t, repo.Save("default", &mcp.ServerConfig{ID: "git", Transport: mcp.TransportStdio, Command: "mcp-git"}))
+
+	servers, err := repo.FindAll("default")
+	require.NoError(t, err)
+	require.Len(t, servers, 2)
+	assert.Equal(t, "git", servers[0].ID)
+
+	_, err = repo.FindByID("billing", "wiki")
+	assert.ErrorIs(t, err, ErrMCPServerNotFound)
+	require.NoError(t, repo.Delete("default", "wiki"))
+	_, err = repo.FindByID("default", "wiki")
+	assert.ErrorIs(t, err, ErrMCPServerNotFound)
+}
@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"errors"
+
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+)
+
+// ErrNamespaceNotFound is returned when a namespace is not found.
+var ErrNamespaceNotFound = errors.New("namespace not found")
+
+type (
+	// NamespaceRepository stores the declared namespaces and their quotas.
+	NamespaceRepository interface {
+		FindByName(name string) (*workflow.Namespace, error)
+		FindAll() ([]*workflow.Namespace, error)
+		Save(ns *workflow.Namespace) error
+		Delete(name string) error
+	}
+)
@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+)
+
+// MemoryNamespaceRepository is an in-memory NamespaceRepository seeded with the default
+// namespace (matches the postgres migration seed).
+type MemoryNamespaceRepository struct {
+	mu         sync.RWMutex
+	namespaces map[string]*workflow.Namespace
+}
+
+// NewMemoryNamespaceRepository creates a memory namespace repository seeded with the default
+// namespace.
+func NewMemoryNamespaceRepository() *MemoryNamespaceRepository {
+	return &MemoryNamespaceRepository{
+		namespaces: map[string]*workflow.Namespace{
+			workflow.DefaultNamespaceName: workflow.NewNamespace(workflow.DefaultNamespaceName, "Default namespace"),
+		},
+	}
+}
+
+// FindByName finds a namespace by name.
+func (r *MemoryNamespaceRepository) FindByName(name string) (*workflow.Namespace, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ns, ok := r.namespaces[name]
+	if !ok {
+		return nil, ErrNamespaceNotFound
+	}
+	clone := *ns
+	return &clone, nil
+}
+
+// FindAll returns all namespaces sorted by name.
+func (r *MemoryNamespaceRepository) FindAll() ([]*workflow.Namespace, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	namespaces := make([]*workflow.Namespace, 0, len(r.namespaces))
+	for _, ns := range r.namespaces {
+		clone := *ns
+		namespaces = append(namespaces, &clone)
+	}
+	sort.Slice(namespaces, func(i, j int) bool { return namespaces[i].Name < namespaces[j].Name })
+	return namespaces, nil
+}
+
+// Save upserts a namespace.
+func (r *MemoryNamespaceRepository) Save(ns *workflow.Namespace) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clone := *ns
+	r.namespaces[ns.Name] = &clone
+	return nil
+}
+
+// Delete removes a namespace by name.
+func (r *MemoryNamespaceRepository) Delete(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.namespaces, name)
+	return nil
+}
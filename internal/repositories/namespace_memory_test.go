@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryNamespaceRepository(t *testing.T) {
+	t.Parallel()
+
+	t.Run("seeds the default namespace", func(t *testing.T) {
+		t.Parallel()
+		repo := NewMemoryNamespaceRepository()
+
+		ns, err := repo.FindByName(workflow.DefaultNamespaceName)
+
+		require.NoError(t, err)
+		assert.Equal(t, workflow.DefaultNamespaceName, ns.Name)
+	})
+
+	t.Run("Save stores a copy with quotas", func(t *testing.T) {
+		t.Parallel()
+		repo := NewMemoryNamespaceRepository()
+		ns := &workflow.Namespace{Name: "billing", Quota: workflow.NamespaceQuota{MaxConcurrentWorkflows: 5}}
+
+		require.NoError(t, repo.Save(ns))
+		ns.Quota.MaxConcurrentWorkflows = 50
+
+		got, err := repo.FindByName("billing")
+		require.NoError(t, err)
+		assert.Equal(t, 5, got.Quota.MaxConcurrentWorkflows)
+	})
+
+	t.Run("FindAll returns sorted namespaces", func(t *testing.T) {
+		t.Parallel()
+		repo := NewMemoryNamespaceRepository()
+		require.NoError(t, repo.Save(workflow.NewNamespace("shipping", "")))
+		require.NoError(t, repo.Save(workflow.NewNamespace("billing", "")))
+
+		namespaces, err := repo.FindAll()
+		require.NoError(t, err)
+		require.Len(t, namespaces, 3)
+		assert.Equal(t, "billing", namespaces[0].Name)
+		assert.Equal(t, workflow.DefaultNamespaceName, namespaces[1].Name)
+		assert.Equal(t, "shipping", namespaces[2].Name)
+	})
+
+	t.Run("Delete removes a namespace", func(t *testing.T) {
+		t.Parallel()
+		repo := NewMemoryNamespaceRepository()
+		require.NoError(t, repo.Save(workflow.NewNamespace("billing", "")))
+
+		require.NoError(t, repo.Delete("billing"))
+		_, err := repo.FindByName("billing")
+		assert.ErrorIs(t, err, ErrNamespaceNotFound)
+	})
+}
@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"errors"
+
+	"github.com/open-source-cloud/fuse/internal/auth"
+)
+
+var (
+	// ErrRoleNotFound is returned when a role is not found.
+	ErrRoleNotFound = errors.New("role not found")
+	// ErrRoleBindingNotFound is returned when a role binding is not found.
+	ErrRoleBindingNotFound = errors.New("role binding not found")
+)
+
+type (
+	// PolicyRepository stores RBAC roles and the bindings granting them to subjects. Deleting a
+	// role deletes its bindings.
+	PolicyRepository interface {
+		FindRole(name string) (*auth.Role, error)
+		FindAllRoles() ([]*auth.Role, error)
+		SaveRole(role *auth.Role) error
+		DeleteRole(name string) error
+		FindAllBindings() ([]*auth.RoleBinding, error)
+		FindBindingsBySubject(subject string) ([]*auth.RoleBinding, error)
+		SaveBinding(binding *auth.RoleBinding) error
+		DeleteBinding(subject, role string) error
+	}
+)
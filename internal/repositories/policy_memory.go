@@ -0,0 +1,160 @@
+package repositories
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/open-source-cloud/fuse/internal/auth"
+)
+
+// MemoryPolicyRepository is an in-memory PolicyRepository for dev and testing.
+type MemoryPolicyRepository struct {
+	mu       sync.RWMutex
+	roles    map[string]*auth.Role
+	bindings map[string]map[string]*auth.RoleBinding // subject -> role -> binding
+}
+
+// NewMemoryPolicyRepository creates an empty memory policy repository.
+func NewMemoryPolicyRepository() *MemoryPolicyRepository {
+	return &MemoryPolicyRepository{
+		roles:    make(map[string]*auth.Role),
+		bindings: make(map[string]map[string]*auth.RoleBinding),
+	}
+}
+
+// FindRole finds a role by name.
+func (r *MemoryPolicyRepository) FindRole(name string) (*auth.Role, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	role, ok := r.roles[name]
+	if !ok {
+		return nil, ErrRoleNotFound
+	}
+	return cloneRole(role), nil
+}
+
+// FindAllRoles returns all roles sorted by name.
+func (r *MemoryPolicyRepository) FindAllRoles() ([]*auth.Role, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	roles := make([]*auth.Role, 0, len(r.roles))
+	for _, role := range r.roles {
+		roles = append(roles, cloneRole(role))
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i].Name < roles[j].Name })
+	return roles, nil
+}
+
+// SaveRole upserts a role.
+func (r *MemoryPolicyRepository) SaveRole(role *auth.Role) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.roles[role.Name] = cloneRole(role)
+	return nil
+}
+
+// DeleteRole removes a role and its bindings.
+func (r *MemoryPolicyRepository) DeleteRole(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.roles[name]; !ok {
+		return ErrRoleNotFound
+	}
+	delete(r.roles, name)
+	for subject, roles := range r.bindings {
+		delete(roles, name)
+		if len(roles) == 0 {
+			delete(r.bindings, subject)
+		}
+	}
+	return nil
+}
+
+// FindAllBindings returns all role bindings sorted by subject and role.
+func (r *MemoryPolicyRepository) FindAllBindings() ([]*auth.RoleBinding, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	bindings := make([]*auth.RoleBinding, 0)
+	for _, roles := range r.bindings {
+		for _, binding := range roles {
+			clone := *binding
+			bindings = append(bindings, &clone)
+		}
+	}
+	sortBindings(bindings)
+	return bindings, nil
+}
+
+// FindBindingsBySubject returns the role bindings of a subject sorted by role.
+func (r *MemoryPolicyRepository) FindBindingsBySubject(subject string) ([]*auth.RoleBinding, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	bindings := make([]*auth.RoleBinding, 0, len(r.bindings[subject]))
+	for _, binding := range r.bindings[subject] {
+		clone := *binding
+		bindings = append(bindings, &clone)
+	}
+	sortBindings(bindings)
+	return bindings, nil
+}
+
+// SaveBinding stores a role binding; saving an existing binding keeps its creation time.
+func (r *MemoryPolicyRepository) SaveBinding(binding *auth.RoleBinding) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	roles, ok := r.bindings[binding.Subject]
+	if !ok {
+		roles = make(map[string]*auth.RoleBinding)
+		r.bindings[binding.Subject] = roles
+	}
+	if _, exists := roles[binding.Role]; !exists {
+		clone := *binding
+		roles[binding.Role] = &clone
+	}
+	return nil
+}
+
+// DeleteBinding removes a role binding.
+func (r *MemoryPolicyRepository) DeleteBinding(subject, role string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.bindings[subject][role]; !ok {
+		return ErrRoleBindingNotFound
+	}
+	delete(r.bindings[subject], role)
+	if len(r.bindings[subject]) == 0 {
+		delete(r.bindings, subject)
+	}
+	return nil
+}
+
+func cloneRole(role *auth.Role) *auth.Role {
+	clone := *role
+	clone.Rules = make([]auth.Rule, len(role.Rules))
+	for i, rule := range role.Rules {
+		clone.Rules[i] = auth.Rule{
+			Permissions:  append([]auth.Permission(nil), rule.Permissions...),
+			Namespaces:   append([]string(nil), rule.Namespaces...),
+			Schemas:      append([]string(nil), rule.Schemas...),
+			Environments: append([]string(nil), rule.Environments...),
+		}
+	}
+	return &clone
+}
+
+func sortBindings(bindings []*auth.RoleBinding) {
+	sort.Slice(bindings, func(i, j int) bool {
+		if bindings[i].Subject != bindings[j].Subject {
+			return bindings[i].Subject < bindings[j].Subject
+		}
+		return bindings[i].Role < bindings[j].Role
+	})
+}
@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/open-source-cloud/fuse/internal/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryPolicyRepository(t *testing.T) {
+	t.Parallel()
+
+	newRole := func(name string) *auth.Role {
+		return &auth.Role{
+			Name:  name,
+			Rules: []auth.Rule{{Permissions: []auth.Permission{auth.PermWorkflowTrigger}, Schemas: []string{"billing-*"}}},
+		}
+	}
+
+	t.Run("SaveRole and FindRole round-trip without sharing rules", func(t *testing.T) {
+		t.Parallel()
+		repo := NewMemoryPolicyRepository()
+		role := newRole("billing-operator")
+		require.NoError(t, repo.SaveRole(role))
+		role.Rules[0].Schemas[0] = "*"
+
+		found, err := repo.FindRole("billing-operator")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"billing-*"}, found.Rules[0].Schemas)
+
+		_, err = repo.FindRole("nope")
+		assert.ErrorIs(t, err, ErrRoleNotFound)
+	})
+
+	t.Run("bindings are listed by subject and deduplicated", func(t *testing.T) {
+		t.Parallel()
+		repo := NewMemoryPolicyRepository()
+		require.NoError(t, repo.SaveRole(newRole("a")))
+		require.NoError(t, repo.SaveRole(newRole("b")))
+		require.NoError(t, repo.SaveBinding(&auth.RoleBinding{Subject: "apikey:1", Role: "b"}))
+		require.NoError(t, repo.SaveBinding(&auth.RoleBinding{Subject: "apikey:1", Role: "a"}))
+		require.NoError(t, repo.SaveBinding(&auth.RoleBinding{Subject: "apikey:1", Role: "a"}))
+		require.NoError(t, repo.SaveBinding(&auth.RoleBinding{Subject: "user-1", Role: "a"}))
+
+		bindings, err := repo.FindBindingsBySubject("apikey:1")
+		require.NoError(t, err)
+		require.Len(t, bindings, 2)
+		assert.Equal(t, "a", bindings[0].Role)
+
+		all, err := repo.FindAllBindings()
+		require.NoError(t, err)
+		assert.Len(t, all, 3)
+
+		require.NoError(t, repo.DeleteBinding("apikey:1", "b"))
+		assert.ErrorIs(t, repo.DeleteBinding("apikey:1", "b"), ErrRoleBindingNotFound)
+	})
+
+	t.Run("DeleteRole removes its bindings", func(t *testing.T) {
+		t.Parallel()
+		repo := NewMemoryPolicyRepository()
+		require.NoError(t, repo.SaveRole(newRole("a")))
+		require.NoError(t, repo.SaveBinding(&auth.RoleBinding{Subject: "user-1", Role: "a"}))
+
+		require.NoError(t, repo.DeleteRole("a"))
+		bindings, err := repo.FindBindingsBySubject("user-1")
+		require.NoError(t, err)
+		assert.Empty(t, bindings)
+		assert.ErrorIs(t, repo.DeleteRole("a"), ErrRoleNotFound)
+	})
+}
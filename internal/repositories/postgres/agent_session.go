@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+)
+
+// AgentSessionRepository is a PostgreSQL-backed AgentSessionRepository. The transcript is stored
+// as a JSONB array of messages.
+type AgentSessionRepository struct {
+	pool *pgxpool.Pool
+}
+
+// compile-time assertion.
+var _ repositories.AgentSessionRepository = (*AgentSessionRepository)(nil)
+
+// NewAgentSessionRepository creates a new PostgreSQL-backed AgentSessionRepository.
+func NewAgentSessionRepository(pool *pgxpool.Pool) repositories.AgentSessionRepository {
+	return &AgentSessionRepository{pool: pool}
+}
+
+// Get retrieves a live session.
+func (r *AgentSessionRepository) Get(namespace, id string) (*repositories.AgentSession, error) {
+	session := &repositories.AgentSession{Namespace: namespace, ID: id}
+	var messages []byte
+	err := r.pool.QueryRow(context.Background(), `
+		SELECT messages, created_at, updated_at, expires_at FROM agent_sessions
+		WHERE namespace = $1 AND id = $2 AND expires_at > NOW()
+	`, namespace, id).Scan(&messages, &session.CreatedAt, &session.UpdatedAt, &session.ExpiresAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repositories.ErrAgentSessionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("postgres/agent_session: get %q: %w", id, err)
+	}
+	if err := json.Unmarshal(messages, &session.Messages); err != nil {
+		return nil, fmt.Errorf("postgres/agent_session: decode %q: %w", id, err)
+	}
+	return session, nil
+}
+
+// Save upserts a session.
+func (r *AgentSessionRepository) Save(session *repositories.AgentSession) error {
+	messages, err := json.Marshal(session.Messages)
+	if err != nil {
+		return fmt.Errorf("postgres/agent_session: encode %q: %w", session.ID, err)
+	}
+	_, err = r.pool.Exec(context.Background(), `
+		INSERT INTO agent_sessions (namespace, id, messages, created_at, updated_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (namespace, id) DO UPDATE SET
+			messages = EXCLUDED.messages,
+			updated_at = EXCLUDED.updated_at,
+			expires_at = EXCLUDED.expires_at
+	`, session.Namespace, session.ID, messages, session.CreatedAt, session.UpdatedAt, session.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("postgres/agent_session: upsert %q: %w", session.ID, err)
+	}
+	return nil
+}
+
+// Delete removes a session.
+func (r *AgentSessionRepository) Delete(namespace, id string) error {
+	tag, err := r.pool.Exec(context.Background(),
+		`DELETE FROM agent_sessions WHERE namespace = $1 AND id = $2`, namespace, id)
+	if err != nil {
+		return fmt.Errorf("postgres/agent_session: delete %q: %w", id, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repositories.ErrAgentSessionNotFound
+	}
+	return nil
+}
+
+// DeleteExpired removes the sessions that expired before the given time.
+func (r *AgentSessionRepository) DeleteExpired(before time.Time) (int64, error) {
+	tag, err := r.pool.Exec(context.Background(), `DELETE FROM agent_sessions WHERE expires_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("postgres/agent_session: delete expired: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/open-source-cloud/fuse/internal/auth"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+)
+
+// APIKeyRepository is a PostgreSQL-backed APIKeyRepository.
+type APIKeyRepository struct {
+	pool *pgxpool.Pool
+}
+
+// compile-time assertion.
+var _ repositories.APIKeyRepository = (*APIKeyRepository)(nil)
+
+// NewAPIKeyRepository creates a new PostgreSQL-backed APIKeyRepository.
+func NewAPIKeyRepository(pool *pgxpool.Pool) repositories.APIKeyRepository {
+	return &APIKeyRepository{pool: pool}
+}
+
+const apiKeyColumns = `id, name, key_hash, created_at, expires_at, revoked_at`
+
+// FindByID retrieves an API key by id.
+func (r *APIKeyRepository) FindByID(id string) (*auth.APIKey, error) {
+	ctx := context.Background()
+	key, err := scanAPIKey(r.pool.QueryRow(ctx, `SELECT `+apiKeyColumns+` FROM api_keys WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repositories.ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("postgres/api_key: find by id: %w", err)
+	}
+	return key, nil
+}
+
+// FindAll retrieves all API keys, newest first.
+func (r *APIKeyRepository) FindAll() ([]*auth.APIKey, error) {
+	ctx := context.Background()
+	rows, err := r.pool.Query(ctx, `SELECT `+apiKeyColumns+` FROM api_keys ORDER BY created_at DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("postgres/api_key: find all: %w", err)
+	}
+	defer rows.Close()
+
+	keys := make([]*auth.APIKey, 0)
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("postgres/api_key: scan row: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// Save upserts an API key.
+func (r *APIKeyRepository) Save(key *auth.APIKey) error {
+	ctx := context.Background()
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO api_keys (id, name, key_hash, created_at, expires_at, revoked_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (id) DO UPDATE SET
+			name = EXCLUDED.name,
+			expires_at = EXCLUDED.expires_at,
+			revoked_at = EXCLUDED.revoked_at
+	`, key.ID, key.Name, key.Hash, key.CreatedAt, key.ExpiresAt, key.RevokedAt)
+	if err != nil {
+		return fmt.Errorf("postgres/api_key: upsert %q: %w", key.ID, err)
+	}
+	return nil
+}
+
+// Revoke marks an API key revoked; revoking twice keeps the first revocation time.
+func (r *APIKeyRepository) Revoke(id string, at time.Time) error {
+	ctx := context.Background()
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE api_keys SET revoked_at = COALESCE(revoked_at, $2) WHERE id = $1`, id, at,
+	)
+	if err != nil {
+		return fmt.Errorf("postgres/api_key: revoke %q: %w", id, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repositories.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+func scanAPIKey(row pgx.Row) (*auth.APIKey, error) {
+	var key auth.APIKey
+	if err := row.Scan(&key.ID, &key.Name, &key.Hash, &key.CreatedAt, &key.ExpiresAt, &key.RevokedAt); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
@@ -0,0 +1,127 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/open-source-cloud/fuse/internal/audit"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+)
+
+// AuditRepository is a PostgreSQL-backed AuditRepository. A trigger rejects updates and deletes
+// of audit_log rows.
+type AuditRepository struct {
+	pool *pgxpool.Pool
+}
+
+// compile-time assertion.
+var _ repositories.AuditRepository = (*AuditRepository)(nil)
+
+// NewAuditRepository creates a new PostgreSQL-backed AuditRepository.
+func NewAuditRepository(pool *pgxpool.Pool) repositories.AuditRepository {
+	return &AuditRepository{pool: pool}
+}
+
+// Append inserts an entry.
+func (r *AuditRepository) Append(entry *audit.Entry) error {
+	ctx := context.Background()
+
+	details, err := json.Marshal(entry.Details)
+	if err != nil {
+		return fmt.Errorf("postgres/audit: marshal details: %w", err)
+	}
+	request, err := json.Marshal(entry.Request)
+	if err != nil {
+		return fmt.Errorf("postgres/audit: marshal request: %w", err)
+	}
+
+	_, err = r.pool.Exec(ctx, `
+		INSERT INTO audit_log (id, time, actor, auth_method, action, resource_type, resource,
+		                       namespace, environment, before_hash, after_hash, details, request)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`, entry.ID, entry.Time, entry.Actor, entry.AuthMethod, string(entry.Action), entry.ResourceType, entry.Resource,
+		entry.Namespace, entry.Environment, entry.BeforeHash, entry.AfterHash, details, request)
+	if err != nil {
+		return fmt.Errorf("postgres/audit: append %s: %w", entry.Action, err)
+	}
+	return nil
+}
+
+// Find returns the matching entries, newest first.
+func (r *AuditRepository) Find(filter audit.Filter) ([]*audit.Entry, error) {
+	ctx := context.Background()
+
+	where := `WHERE TRUE`
+	args := []any{}
+	idx := 1
+	add := func(condition string, value any) {
+		where += fmt.Sprintf(` AND `+condition, idx)
+		args = append(args, value)
+		idx++
+	}
+	if filter.Actor != "" {
+		add(`actor = $%d`, filter.Actor)
+	}
+	if filter.Action != "" {
+		add(`action = $%d`, string(filter.Action))
+	}
+	if filter.ResourceType != "" {
+		add(`resource_type = $%d`, filter.ResourceType)
+	}
+	if filter.Resource != "" {
+		add(`resource = $%d`, filter.Resource)
+	}
+	if filter.Namespace != "" {
+		add(`namespace = $%d`, filter.Namespace)
+	}
+	if !filter.Since.IsZero() {
+		add(`time >= $%d`, filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		add(`time < $%d`, filter.Until)
+	}
+	if filter.Before != "" {
+		add(`id < $%d`, filter.Before)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, time, actor, auth_method, action, resource_type, resource, namespace,
+		       environment, before_hash, after_hash, details, request
+		FROM audit_log %s
+		ORDER BY id DESC
+		LIMIT $%d
+	`, where, idx)
+	args = append(args, filter.EffectiveLimit())
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("postgres/audit: find: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]*audit.Entry, 0)
+	for rows.Next() {
+		var e audit.Entry
+		var action string
+		var details, request []byte
+		if err := rows.Scan(&e.ID, &e.Time, &e.Actor, &e.AuthMethod, &action, &e.ResourceType, &e.Resource,
+			&e.Namespace, &e.Environment, &e.BeforeHash, &e.AfterHash, &details, &request); err != nil {
+			return nil, fmt.Errorf("postgres/audit: scan: %w", err)
+		}
+		e.Action = audit.Action(action)
+		if len(details) > 0 {
+			if err := json.Unmarshal(details, &e.Details); err != nil {
+				return nil, fmt.Errorf("postgres/audit: unmarshal details of %s: %w", e.ID, err)
+			}
+		}
+		if len(request) > 0 {
+			if err := json.Unmarshal(request, &e.Request); err != nil {
+				return nil, fmt.Errorf("postgres/audit: unmarshal request of %s: %w", e.ID, err)
+			}
+		}
+		entries = append(entries, &e)
+	}
+	return entries, rows.Err()
+}
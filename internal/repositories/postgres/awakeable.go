@@ -157,6 +157,22 @@ func (r *AwakeableRepository) Resolve(id string, result map[string]any) error {
 	return nil
 }
 
+// Reopen returns a resolved awakeable to pending. Its stored result is left to be overwritten by
+// the next resolution.
+func (r *AwakeableRepository) Reopen(id string) error {
+	tag, err := r.pool.Exec(context.Background(), `
+		UPDATE awakeables SET status = 'pending', result_ref = NULL, updated_at = NOW()
+		WHERE awakeable_id = $1 AND status = 'resolved'
+	`, id)
+	if err != nil {
+		return fmt.Errorf("postgres/awakeable: reopen: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repositories.ErrAwakeableNotFound
+	}
+	return nil
+}
+
 // scanAwakeable executes a single-row query and scans into an Awakeable.
 func (r *AwakeableRepository) scanAwakeable(ctx context.Context, query string, args ...any) (*workflow.Awakeable, *string, error) {
 	row := r.pool.QueryRow(ctx, query, args...)
@@ -242,3 +258,24 @@ func (r *AwakeableRepository) fetchResult(ctx context.Context, ref string, a *wo
 	a.Result = result
 	return nil
 }
+
+// DeleteByWorkflowIDs removes all awakeables of the given workflows along with their result objects.
+func (r *AwakeableRepository) DeleteByWorkflowIDs(workflowIDs []string) (int64, error) {
+	ctx := context.Background()
+
+	rows, err := r.pool.Query(ctx, `
+		DELETE FROM awakeables WHERE workflow_id = ANY($1)
+		RETURNING result_ref
+	`, workflowIDs)
+	if err != nil {
+		return 0, fmt.Errorf("postgres/awakeable: delete: %w", err)
+	}
+	deleted, refs, err := scanDeletedRefs(rows)
+	if err != nil {
+		return deleted, fmt.Errorf("postgres/awakeable: delete: %w", err)
+	}
+	if err := deleteObjects(ctx, r.store, refs); err != nil {
+		return deleted, fmt.Errorf("postgres/awakeable: delete results: %w", err)
+	}
+	return deleted, nil
+}
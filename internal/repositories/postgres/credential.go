@@ -24,14 +24,14 @@ func NewCredentialRepository(pool *pgxpool.Pool) repositories.CredentialReposito
 	return &CredentialRepository{pool: pool}
 }
 
-// FindByID retrieves a credential by id.
-func (r *CredentialRepository) FindByID(id string) (*workflow.Credential, error) {
+// FindByID retrieves a credential of a namespace by id.
+func (r *CredentialRepository) FindByID(namespace, id string) (*workflow.Credential, error) {
 	ctx := context.Background()
 
 	var credType, description string
 	var fields []string
 	err := r.pool.QueryRow(ctx,
-		`SELECT type, description, fields FROM credentials WHERE id = $1`, id,
+		`SELECT type, description, fields FROM credentials WHERE namespace = $1 AND id = $2`, namespace, id,
 	).Scan(&credType, &description, &fields)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -42,11 +42,12 @@ func (r *CredentialRepository) FindByID(id string) (*workflow.Credential, error)
 	return workflow.NewCredential(id, credType, description, fields), nil
 }
 
-// FindAll retrieves all credentials sorted by id.
-func (r *CredentialRepository) FindAll() ([]*workflow.Credential, error) {
+// FindAll retrieves the credentials of a namespace sorted by id.
+func (r *CredentialRepository) FindAll(namespace string) ([]*workflow.Credential, error) {
 	ctx := context.Background()
 
-	rows, err := r.pool.Query(ctx, `SELECT id, type, description, fields FROM credentials ORDER BY id`)
+	rows, err := r.pool.Query(ctx,
+		`SELECT id, type, description, fields FROM credentials WHERE namespace = $1 ORDER BY id`, namespace)
 	if err != nil {
 		return nil, fmt.Errorf("postgres/credential: find all: %w", err)
 	}
@@ -64,8 +65,8 @@ func (r *CredentialRepository) FindAll() ([]*workflow.Credential, error) {
 	return creds, rows.Err()
 }
 
-// Save upserts a credential's metadata.
-func (r *CredentialRepository) Save(cred *workflow.Credential) error {
+// Save upserts a credential's metadata in a namespace.
+func (r *CredentialRepository) Save(namespace string, cred *workflow.Credential) error {
 	ctx := context.Background()
 
 	// A nil slice encodes as SQL NULL and violates the NOT NULL fields column; the empty array is
@@ -76,24 +77,24 @@ func (r *CredentialRepository) Save(cred *workflow.Credential) error {
 	}
 
 	_, err := r.pool.Exec(ctx, `
-		INSERT INTO credentials (id, type, description, fields, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, NOW(), NOW())
-		ON CONFLICT (id) DO UPDATE SET
+		INSERT INTO credentials (namespace, id, type, description, fields, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+		ON CONFLICT (namespace, id) DO UPDATE SET
 			type = EXCLUDED.type,
 			description = EXCLUDED.description,
 			fields = EXCLUDED.fields,
 			updated_at = NOW()
-	`, cred.ID, cred.Type, cred.Description, fields)
+	`, namespace, cred.ID, cred.Type, cred.Description, fields)
 	if err != nil {
 		return fmt.Errorf("postgres/credential: upsert %q: %w", cred.ID, err)
 	}
 	return nil
 }
 
-// Delete removes a credential's metadata by id.
-func (r *CredentialRepository) Delete(id string) error {
+// Delete removes a credential's metadata of a namespace by id.
+func (r *CredentialRepository) Delete(namespace, id string) error {
 	ctx := context.Background()
-	if _, err := r.pool.Exec(ctx, `DELETE FROM credentials WHERE id = $1`, id); err != nil {
+	if _, err := r.pool.Exec(ctx, `DELETE FROM credentials WHERE namespace = $1 AND id = $2`, namespace, id); err != nil {
 		return fmt.Errorf("postgres/credential: delete %q: %w", id, err)
 	}
 	return nil
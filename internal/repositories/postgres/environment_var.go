@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+)
+
+// EnvironmentVarRepository is a PostgreSQL-backed EnvironmentVarRepository. Values are stored in
+// plain text, unlike the secrets table.
+type EnvironmentVarRepository struct {
+	pool *pgxpool.Pool
+}
+
+// compile-time assertion.
+var _ repositories.EnvironmentVarRepository = (*EnvironmentVarRepository)(nil)
+
+// NewEnvironmentVarRepository creates a new PostgreSQL-backed EnvironmentVarRepository.
+func NewEnvironmentVarRepository(pool *pgxpool.Pool) repositories.EnvironmentVarRepository {
+	return &EnvironmentVarRepository{pool: pool}
+}
+
+// FindAll retrieves the variables of a namespace's environment.
+func (r *EnvironmentVarRepository) FindAll(namespace, environment string) (map[string]string, error) {
+	ctx := context.Background()
+
+	rows, err := r.pool.Query(ctx,
+		`SELECT name, value FROM environment_vars WHERE namespace = $1 AND environment = $2`, namespace, environment)
+	if err != nil {
+		return nil, fmt.Errorf("postgres/environment_var: find all: %w", err)
+	}
+	defer rows.Close()
+
+	vars := make(map[string]string)
+	for rows.Next() {
+		var name, value string
+		if err := rows.Scan(&name, &value); err != nil {
+			return nil, fmt.Errorf("postgres/environment_var: scan row: %w", err)
+		}
+		vars[name] = value
+	}
+	return vars, rows.Err()
+}
+
+// Set upserts a variable.
+func (r *EnvironmentVarRepository) Set(namespace, environment, name, value string) error {
+	ctx := context.Background()
+
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO environment_vars (namespace, environment, name, value, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW())
+		ON CONFLICT (namespace, environment, name) DO UPDATE SET
+			value = EXCLUDED.value,
+			updated_at = NOW()
+	`, namespace, environment, name, value)
+	if err != nil {
+		return fmt.Errorf("postgres/environment_var: upsert %q: %w", name, err)
+	}
+	return nil
+}
+
+// Delete removes a variable.
+func (r *EnvironmentVarRepository) Delete(namespace, environment, name string) error {
+	ctx := context.Background()
+	if _, err := r.pool.Exec(ctx,
+		`DELETE FROM environment_vars WHERE namespace = $1 AND environment = $2 AND name = $3`,
+		namespace, environment, name); err != nil {
+		return fmt.Errorf("postgres/environment_var: delete %q: %w", name, err)
+	}
+	return nil
+}
@@ -66,10 +66,10 @@ func (r *GraphRepository) FindByID(id string) (*workflow.Graph, error) {
 	return graph, nil
 }
 
-// List returns schema_id and name for all rows in graph_schemas, ordered by schema_id.
+// List returns schema_id, name and lifecycle for all rows in graph_schemas, ordered by schema_id.
 func (r *GraphRepository) List() ([]repositories.GraphSchemaListItem, error) {
 	ctx := context.Background()
-	rows, err := r.pool.Query(ctx, `SELECT schema_id, name FROM graph_schemas ORDER BY schema_id`)
+	rows, err := r.pool.Query(ctx, `SELECT schema_id, name, lifecycle FROM graph_schemas ORDER BY schema_id`)
 	if err != nil {
 		return nil, fmt.Errorf("postgres/graph: list: %w", err)
 	}
@@ -77,11 +77,15 @@ func (r *GraphRepository) List() ([]repositories.GraphSchemaListItem, error) {
 
 	out := make([]repositories.GraphSchemaListItem, 0)
 	for rows.Next() {
-		var id, name string
-		if err := rows.Scan(&id, &name); err != nil {
+		var id, name, lifecycle string
+		if err := rows.Scan(&id, &name, &lifecycle); err != nil {
 			return nil, fmt.Errorf("postgres/graph: list scan: %w", err)
 		}
-		out = append(out, repositories.GraphSchemaListItem{SchemaID: id, Name: name})
+		out = append(out, repositories.GraphSchemaListItem{
+			SchemaID:  id,
+			Name:      name,
+			Lifecycle: workflow.SchemaLifecycle(lifecycle),
+		})
 	}
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("postgres/graph: list rows: %w", err)
@@ -408,3 +412,113 @@ func (r *GraphRepository) GetVersionHistory(schemaID string) (*workflow.SchemaVe
 		TotalVersions: total,
 	}, nil
 }
+
+// FindLifecycle returns the lifecycle state of a schema.
+func (r *GraphRepository) FindLifecycle(schemaID string) (workflow.SchemaLifecycle, error) {
+	ctx := context.Background()
+
+	var lifecycle string
+	err := r.pool.QueryRow(ctx,
+		`SELECT lifecycle FROM graph_schemas WHERE schema_id = $1`, schemaID,
+	).Scan(&lifecycle)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return "", repositories.ErrGraphNotFound
+		}
+		return "", fmt.Errorf("postgres/graph: find lifecycle: %w", err)
+	}
+	return workflow.SchemaLifecycle(lifecycle), nil
+}
+
+// SetLifecycle updates the lifecycle state of a schema.
+func (r *GraphRepository) SetLifecycle(schemaID string, lifecycle workflow.SchemaLifecycle) error {
+	ctx := context.Background()
+
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE graph_schemas SET lifecycle = $1, updated_at = NOW() WHERE schema_id = $2`,
+		string(lifecycle), schemaID)
+	if err != nil {
+		return fmt.Errorf("postgres/graph: set lifecycle: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repositories.ErrGraphNotFound
+	}
+	return nil
+}
+
+// FindTrafficSplit returns the traffic split of a schema, nil when none is configured.
+func (r *GraphRepository) FindTrafficSplit(schemaID string) (*workflow.TrafficSplit, error) {
+	ctx := context.Background()
+
+	var data []byte
+	err := r.pool.QueryRow(ctx,
+		`SELECT traffic_split FROM graph_schemas WHERE schema_id = $1`, schemaID,
+	).Scan(&data)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, repositories.ErrGraphNotFound
+		}
+		return nil, fmt.Errorf("postgres/graph: find traffic split: %w", err)
+	}
+	if data == nil {
+		return nil, nil
+	}
+	var split workflow.TrafficSplit
+	if err := json.Unmarshal(data, &split); err != nil {
+		return nil, fmt.Errorf("postgres/graph: unmarshal traffic split: %w", err)
+	}
+	return &split, nil
+}
+
+// SetTrafficSplit stores the traffic split of a schema; a nil split removes it.
+func (r *GraphRepository) SetTrafficSplit(schemaID string, split *workflow.TrafficSplit) error {
+	ctx := context.Background()
+
+	var data []byte
+	if split != nil {
+		var err error
+		if data, err = json.Marshal(split); err != nil {
+			return fmt.Errorf("postgres/graph: marshal traffic split: %w", err)
+		}
+	}
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE graph_schemas SET traffic_split = $1, updated_at = NOW() WHERE schema_id = $2`,
+		data, schemaID)
+	if err != nil {
+		return fmt.Errorf("postgres/graph: set traffic split: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repositories.ErrGraphNotFound
+	}
+	return nil
+}
+
+// Delete removes a schema row (tags, metadata, node index and versions cascade) and then the
+// definition objects it referenced. Object cleanup is best effort once the rows are gone.
+func (r *GraphRepository) Delete(schemaID string) error {
+	ctx := context.Background()
+
+	rows, err := r.pool.Query(ctx,
+		`SELECT definition_ref FROM graph_schema_versions WHERE schema_id = $1`, schemaID)
+	if err != nil {
+		return fmt.Errorf("postgres/graph: list version refs: %w", err)
+	}
+	_, refs, err := scanDeletedRefs(rows)
+	if err != nil {
+		return fmt.Errorf("postgres/graph: scan version refs: %w", err)
+	}
+
+	tag, err := r.pool.Exec(ctx, `DELETE FROM graph_schemas WHERE schema_id = $1`, schemaID)
+	if err != nil {
+		return fmt.Errorf("postgres/graph: delete schema: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repositories.ErrGraphNotFound
+	}
+
+	refs = append(refs, graphObjectKey(schemaID))
+	if err := deleteObjects(ctx, r.store, refs); err != nil {
+		return fmt.Errorf("postgres/graph: delete definitions: %w", err)
+	}
+	return nil
+}
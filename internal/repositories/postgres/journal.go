@@ -334,3 +334,24 @@ func uint16Slice(s []int16) []uint16 {
 	}
 	return u
 }
+
+// DeleteByWorkflowIDs removes the journals of the given workflows along with their payload objects.
+func (r *JournalRepository) DeleteByWorkflowIDs(workflowIDs []string) (int64, error) {
+	ctx := context.Background()
+
+	rows, err := r.pool.Query(ctx, `
+		DELETE FROM journal_entries WHERE workflow_id = ANY($1)
+		RETURNING input_ref, result_ref, data_ref
+	`, workflowIDs)
+	if err != nil {
+		return 0, fmt.Errorf("postgres/journal: delete: %w", err)
+	}
+	deleted, refs, err := scanDeletedRefs(rows)
+	if err != nil {
+		return deleted, fmt.Errorf("postgres/journal: delete: %w", err)
+	}
+	if err := deleteObjects(ctx, r.store, refs); err != nil {
+		return deleted, fmt.Errorf("postgres/journal: delete payloads: %w", err)
+	}
+	return deleted, nil
+}
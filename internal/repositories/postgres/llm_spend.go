@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+)
+
+// LLMSpendRepository is a PostgreSQL-backed LLMSpendRepository. Totals are incremented with a
+// single upsert, so concurrent writers on any node never lose spend.
+type LLMSpendRepository struct {
+	pool *pgxpool.Pool
+}
+
+// compile-time assertion.
+var _ repositories.LLMSpendRepository = (*LLMSpendRepository)(nil)
+
+// NewLLMSpendRepository creates a new PostgreSQL-backed LLMSpendRepository.
+func NewLLMSpendRepository(pool *pgxpool.Pool) repositories.LLMSpendRepository {
+	return &LLMSpendRepository{pool: pool}
+}
+
+// AddExecution adds spend to a workflow execution's total.
+func (r *LLMSpendRepository) AddExecution(workflowID string, spend repositories.LLMSpend) (repositories.LLMSpend, error) {
+	var total repositories.LLMSpend
+	err := r.pool.QueryRow(context.Background(), `
+		INSERT INTO llm_execution_spend (workflow_id, cost_usd, tokens, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (workflow_id) DO UPDATE SET
+			cost_usd = llm_execution_spend.cost_usd + EXCLUDED.cost_usd,
+			tokens = llm_execution_spend.tokens + EXCLUDED.tokens,
+			updated_at = NOW()
+		RETURNING cost_usd, tokens
+	`, workflowID, spend.CostUSD, spend.Tokens).Scan(&total.CostUSD, &total.Tokens)
+	if err != nil {
+		return total, fmt.Errorf("postgres/llm_spend: add execution %q: %w", workflowID, err)
+	}
+	return total, nil
+}
+
+// GetExecution returns a workflow execution's total.
+func (r *LLMSpendRepository) GetExecution(workflowID string) (repositories.LLMSpend, error) {
+	var total repositories.LLMSpend
+	err := r.pool.QueryRow(context.Background(),
+		`SELECT cost_usd, tokens FROM llm_execution_spend WHERE workflow_id = $1`, workflowID,
+	).Scan(&total.CostUSD, &total.Tokens)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return total, fmt.Errorf("postgres/llm_spend: get execution %q: %w", workflowID, err)
+	}
+	return total, nil
+}
+
+// AddDaily adds spend to a schema's daily total for an environment.
+func (r *LLMSpendRepository) AddDaily(schemaID, environment string, day time.Time, spend repositories.LLMSpend) (repositories.LLMSpend, error) {
+	var total repositories.LLMSpend
+	err := r.pool.QueryRow(context.Background(), `
+		INSERT INTO llm_daily_spend (schema_id, environment, day, cost_usd, tokens, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		ON CONFLICT (schema_id, environment, day) DO UPDATE SET
+			cost_usd = llm_daily_spend.cost_usd + EXCLUDED.cost_usd,
+			tokens = llm_daily_spend.tokens + EXCLUDED.tokens,
+			updated_at = NOW()
+		RETURNING cost_usd, tokens
+	`, schemaID, environment, repositories.SpendDay(day), spend.CostUSD, spend.Tokens).Scan(&total.CostUSD, &total.Tokens)
+	if err != nil {
+		return total, fmt.Errorf("postgres/llm_spend: add daily %q: %w", schemaID, err)
+	}
+	return total, nil
+}
+
+// GetDaily returns a schema's daily total for an environment.
+func (r *LLMSpendRepository) GetDaily(schemaID, environment string, day time.Time) (repositories.LLMSpend, error) {
+	var total repositories.LLMSpend
+	err := r.pool.QueryRow(context.Background(),
+		`SELECT cost_usd, tokens FROM llm_daily_spend WHERE schema_id = $1 AND environment = $2 AND day = $3`,
+		schemaID, environment, repositories.SpendDay(day),
+	).Scan(&total.CostUSD, &total.Tokens)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return total, fmt.Errorf("postgres/llm_spend: get daily %q: %w", schemaID, err)
+	}
+	return total, nil
+}
+
+// DeleteByWorkflowIDs removes the execution totals of the given workflows.
+func (r *LLMSpendRepository) DeleteByWorkflowIDs(workflowIDs []string) (int64, error) {
+	tag, err := r.pool.Exec(context.Background(),
+		`DELETE FROM llm_execution_spend WHERE workflow_id = ANY($1)`, workflowIDs)
+	if err != nil {
+		return 0, fmt.Errorf("postgres/llm_spend: delete: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
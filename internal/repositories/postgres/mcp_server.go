@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/pkg/mcp"
+)
+
+// MCPServerRepository is a PostgreSQL-backed MCPServerRepository. The registration is stored as
+// JSONB; it holds secret references, never secret values.
+type MCPServerRepository struct {
+	pool *pgxpool.Pool
+}
+
+// compile-time assertion.
+var _ repositories.MCPServerRepository = (*MCPServerRepository)(nil)
+
+// NewMCPServerRepository creates a new PostgreSQL-backed MCPServerRepository.
+func NewMCPServerRepository(pool *pgxpool.Pool) repositories.MCPServerRepository {
+	return &MCPServerRepository{pool: pool}
+}
+
+// FindByID retrieves a server of a namespace by id.
+func (r *MCPServerRepository) FindByID(namespace, id string) (*mcp.ServerConfig, error) {
+	var config []byte
+	err := r.pool.QueryRow(context.Background(),
+		`SELECT config FROM mcp_servers WHERE namespace = $1 AND id = $2`, namespace, id,
+	).Scan(&config)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repositories.ErrMCPServerNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("postgres/mcp_server: find by id: %w", err)
+	}
+	return decodeMCPServer(id, config)
+}
+
+// FindAll retrieves the servers of a namespace sorted by id.
+func (r *MCPServerRepository) FindAll(namespace string) ([]*mcp.ServerConfig, error) {
+	rows, err := r.pool.Query(context.Background(),
+		`SELECT id, config FROM mcp_servers WHERE namespace = $1 ORDER BY id`, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("postgres/mcp_server: find all: %w", err)
+	}
+	defer rows.Close()
+
+	servers := make([]*mcp.ServerConfig, 0)
+	for rows.Next() {
+		var id string
+		var config []byte
+		if err := rows.Scan(&id, &config); err != nil {
+			return nil, fmt.Errorf("postgres/mcp_server: scan row: %w", err)
+		}
+		server, err := decodeMCPServer(id, config)
+		if err != nil {
+			return nil, err
+		}
+		servers = append(servers, server)
+	}
+	return servers, rows.Err()
+}
+
+// Save upserts a server in a namespace.
+func (r *MCPServerRepository) Save(namespace string, server *mcp.ServerConfig) error {
+	config, err := json.Marshal(server)
+	if err != nil {
+		return fmt.Errorf("postgres/mcp_server: encode %q: %w", server.ID, err)
+	}
+	_, err = r.pool.Exec(context.Background(), `
+		INSERT INTO mcp_servers (namespace, id, config, created_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+		ON CONFLICT (namespace, id) DO UPDATE SET
+			config = EXCLUDED.config,
+			updated_at = NOW()
+	`, namespace, server.ID, config)
+	if err != nil {
+		return fmt.Errorf("postgres/mcp_server: upsert %q: %w", server.ID, err)
+	}
+	return nil
+}
+
+// Delete removes a server of a namespace by id.
+func (r *MCPServerRepository) Delete(namespace, id string) error {
+	if _, err := r.pool.Exec(context.Background(),
+		`DELETE FROM mcp_servers WHERE namespace = $1 AND id = $2`, namespace, id); err != nil {
+		return fmt.Errorf("postgres/mcp_server: delete %q: %w", id, err)
+	}
+	return nil
+}
+
+func decodeMCPServer(id string, config []byte) (*mcp.ServerConfig, error) {
+	var server mcp.ServerConfig
+	if err := json.Unmarshal(config, &server); err != nil {
+		return nil, fmt.Errorf("postgres/mcp_server: decode %q: %w", id, err)
+	}
+	return &server, nil
+}
@@ -0,0 +1,3 @@
+-- Retention (purge of terminal executions) ranks executions per schema and state by
+-- updated_at, which for terminal states is the completion time.
+CREATE INDEX IF NOT EXISTS idx_workflows_retention ON workflows (schema_id, state, updated_at DESC);
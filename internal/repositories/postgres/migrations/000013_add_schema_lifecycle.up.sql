@@ -0,0 +1,7 @@
+-- Schema lifecycle: active schemas accept new executions, deprecated ones reject them while
+-- keeping their triggers, archived ones also have their cron/webhook/event triggers removed.
+-- Existing schemas are back-filled as active.
+
+ALTER TABLE graph_schemas
+    ADD COLUMN lifecycle VARCHAR(16) NOT NULL DEFAULT 'active'
+        CHECK (lifecycle IN ('active', 'deprecated', 'archived'));
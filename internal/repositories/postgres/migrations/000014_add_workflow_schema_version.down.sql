@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_workflows_schema_version;
+ALTER TABLE workflows DROP COLUMN IF EXISTS schema_version;
@@ -0,0 +1,8 @@
+-- Record the schema version each execution runs on, so executions pinned to a non-active
+-- version (canary testing) replay against that version and can be listed by version.
+-- 0 means "not recorded" and backfills executions created before this migration; they keep
+-- loading the schema's current definition on recovery.
+
+ALTER TABLE workflows ADD COLUMN schema_version INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_workflows_schema_version ON workflows (schema_id, schema_version);
@@ -0,0 +1 @@
+ALTER TABLE graph_schemas DROP COLUMN IF EXISTS traffic_split;
@@ -0,0 +1,5 @@
+-- Canary releases: an optional per-schema traffic split routing new executions across schema
+-- versions by weight, with an optional sticky key and automatic rollback rule. Stored as JSON
+-- (workflow.TrafficSplit); NULL means every execution runs the active version.
+
+ALTER TABLE graph_schemas ADD COLUMN traffic_split JSONB;
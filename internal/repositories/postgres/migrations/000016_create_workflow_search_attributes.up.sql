@@ -0,0 +1,18 @@
+-- Search attributes: named values of an execution computed from the schema's searchAttributes
+-- expressions as the workflow progresses, indexed so executions can be found by business keys
+-- such as an order ID, within one schema or across schemas.
+
+CREATE TABLE workflow_search_attributes (
+    workflow_id     VARCHAR(36)     NOT NULL,
+    schema_id       VARCHAR(128)    NOT NULL,
+    name            VARCHAR(64)     NOT NULL,
+    value           TEXT            NOT NULL,
+    updated_at      TIMESTAMPTZ     NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (workflow_id, name),
+    CONSTRAINT fk_search_attribute_workflow FOREIGN KEY (workflow_id)
+        REFERENCES workflows(workflow_id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_search_attributes_value ON workflow_search_attributes (name, value);
+CREATE INDEX idx_search_attributes_schema_value ON workflow_search_attributes (schema_id, name, value);
@@ -0,0 +1,11 @@
+-- API keys authenticating REST API callers. Only the SHA-256 hash of a key is stored; the
+-- plaintext (fuse_<id>_<secret>) is shown once at creation. Revoked keys are kept for auditing.
+
+CREATE TABLE api_keys (
+    id              VARCHAR(32)     PRIMARY KEY,
+    name            VARCHAR(255)    NOT NULL,
+    key_hash        CHAR(64)        NOT NULL,
+    created_at      TIMESTAMPTZ     NOT NULL DEFAULT NOW(),
+    expires_at      TIMESTAMPTZ,
+    revoked_at      TIMESTAMPTZ
+);
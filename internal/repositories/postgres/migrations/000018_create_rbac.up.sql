@@ -0,0 +1,21 @@
+-- RBAC roles and the bindings granting them to principal subjects ("apikey:<id>" or a JWT sub).
+-- A role's rules (permissions plus schema/environment glob patterns) are stored as JSONB since
+-- they are always read and written as a whole.
+
+CREATE TABLE roles (
+    name            VARCHAR(64)     PRIMARY KEY,
+    description     VARCHAR(512)    NOT NULL DEFAULT '',
+    rules           JSONB           NOT NULL,
+    created_at      TIMESTAMPTZ     NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ     NOT NULL DEFAULT NOW()
+);
+
+-- role is not a foreign key: the built-in admin role has no row.
+CREATE TABLE role_bindings (
+    subject         VARCHAR(255)    NOT NULL,
+    role            VARCHAR(64)     NOT NULL,
+    created_at      TIMESTAMPTZ     NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (subject, role)
+);
+
+CREATE INDEX idx_role_bindings_role ON role_bindings (role);
@@ -0,0 +1,15 @@
+-- Rows of non-default namespaces would collide once the namespace is dropped from the keys.
+DELETE FROM secrets WHERE namespace <> 'default';
+ALTER TABLE secrets DROP CONSTRAINT IF EXISTS uq_secrets_ns_env_name;
+ALTER TABLE secrets ADD CONSTRAINT uq_secrets_env_name UNIQUE (environment, name);
+ALTER TABLE secrets DROP COLUMN IF EXISTS namespace;
+
+DELETE FROM credentials WHERE namespace <> 'default';
+ALTER TABLE credentials DROP CONSTRAINT IF EXISTS credentials_pkey;
+ALTER TABLE credentials ADD PRIMARY KEY (id);
+ALTER TABLE credentials DROP COLUMN IF EXISTS namespace;
+
+DROP INDEX IF EXISTS idx_workflows_namespace_state;
+ALTER TABLE workflows DROP COLUMN IF EXISTS namespace;
+
+DROP TABLE IF EXISTS namespaces;
@@ -0,0 +1,40 @@
+-- Multi-tenant namespaces. Schemas and packages of a namespace are stored under qualified IDs
+-- ("<namespace>:<id>"), so their tables need no new column; executions record the namespace for
+-- listing and quota counting, and credentials and secrets gain it as part of their key.
+--
+-- The separator is reserved from this migration on: writes reject unqualified IDs containing it.
+-- A schema or package stored earlier under an ID like "billing:invoice" is read from now on as
+-- schema "invoice" of namespace "billing"; the namespaces such IDs name are created below so those
+-- definitions stay reachable under /v1/ns/{ns}. IDs whose prefix is not a valid namespace name
+-- (or that contain the separator more than once) must be renamed before upgrading.
+
+CREATE TABLE namespaces (
+    name                     VARCHAR(128) PRIMARY KEY,
+    description              VARCHAR(512) NOT NULL DEFAULT '',
+    max_concurrent_workflows INTEGER      NOT NULL DEFAULT 0,
+    max_storage_bytes        BIGINT       NOT NULL DEFAULT 0,
+    created_at               TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at               TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO namespaces (name, description)
+    VALUES ('default', 'Default namespace')
+    ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO namespaces (name, description)
+    SELECT DISTINCT split_part(ids.id, ':', 1), 'Created from pre-existing qualified IDs'
+    FROM (SELECT schema_id AS id FROM graph_schemas UNION SELECT package_id FROM packages) AS ids
+    WHERE split_part(ids.id, ':', 1) ~ '^[a-z0-9][a-z0-9_.\-]*$' AND position(':' IN ids.id) > 1
+    ON CONFLICT (name) DO NOTHING;
+
+ALTER TABLE workflows ADD COLUMN namespace VARCHAR(128) NOT NULL DEFAULT 'default';
+UPDATE workflows SET namespace = split_part(schema_id, ':', 1) WHERE position(':' IN schema_id) > 1;
+CREATE INDEX idx_workflows_namespace_state ON workflows (namespace, state);
+
+ALTER TABLE credentials ADD COLUMN namespace VARCHAR(128) NOT NULL DEFAULT 'default';
+ALTER TABLE credentials DROP CONSTRAINT credentials_pkey;
+ALTER TABLE credentials ADD PRIMARY KEY (namespace, id);
+
+ALTER TABLE secrets ADD COLUMN namespace VARCHAR(128) NOT NULL DEFAULT 'default';
+ALTER TABLE secrets DROP CONSTRAINT uq_secrets_env_name;
+ALTER TABLE secrets ADD CONSTRAINT uq_secrets_ns_env_name UNIQUE (namespace, environment, name);
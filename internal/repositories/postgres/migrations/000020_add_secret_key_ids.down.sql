@@ -0,0 +1,17 @@
+-- Envelope-encrypted rows cannot be read without their data key, and the rollback never deletes
+-- secrets: it refuses to run while any exist. Delete them (`fuse secrets delete`) and set them
+-- again after rolling back; rows still sealed directly with SECRETS_ENCRYPTION_KEY survive.
+DO $$
+DECLARE
+    envelope_rows BIGINT;
+BEGIN
+    SELECT count(*) INTO envelope_rows FROM secrets WHERE encrypted_dek IS NOT NULL OR key_id <> 'default';
+    IF envelope_rows > 0 THEN
+        RAISE EXCEPTION '% envelope-encrypted secrets would be unreadable after this rollback; delete them with `fuse secrets delete` first and set them again afterwards', envelope_rows;
+    END IF;
+END
+$$;
+
+DROP INDEX IF EXISTS idx_secrets_key_id;
+ALTER TABLE secrets DROP COLUMN IF EXISTS encrypted_dek;
+ALTER TABLE secrets DROP COLUMN IF EXISTS key_id;
@@ -0,0 +1,9 @@
+-- Envelope encryption with a keyring. Each secret is sealed with its own data key, which is
+-- wrapped by the keyring key named in key_id. Rows written before this migration were sealed
+-- directly with SECRETS_ENCRYPTION_KEY (key id 'default') and have no encrypted_dek until
+-- `fuse secrets rotate-key` re-encrypts them.
+
+ALTER TABLE secrets ADD COLUMN key_id VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE secrets ADD COLUMN encrypted_dek BYTEA;
+
+CREATE INDEX idx_secrets_key_id ON secrets (key_id);
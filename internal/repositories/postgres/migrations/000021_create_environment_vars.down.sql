@@ -0,0 +1,2 @@
+ALTER TABLE workflows DROP COLUMN IF EXISTS vars;
+DROP TABLE IF EXISTS environment_vars;
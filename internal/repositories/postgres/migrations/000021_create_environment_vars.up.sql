@@ -0,0 +1,15 @@
+-- Non-secret environment variables, resolved through {{var:NAME}} and source:"var" mappings.
+-- Executions pin the variables of their environment when triggered (workflows.vars), so replay
+-- and recovery resolve the values the execution started with.
+
+CREATE TABLE environment_vars (
+    namespace   VARCHAR(128) NOT NULL DEFAULT 'default',
+    environment VARCHAR(128) NOT NULL,
+    name        VARCHAR(255) NOT NULL,
+    value       TEXT         NOT NULL,
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (namespace, environment, name)
+);
+
+ALTER TABLE workflows ADD COLUMN vars JSONB NOT NULL DEFAULT '{}';
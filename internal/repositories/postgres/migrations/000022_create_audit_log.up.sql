@@ -0,0 +1,34 @@
+-- Append-only log of administrative changes (schema, package, credential, secret, environment
+-- and variable writes, cancels and manual retries). Secret values are never stored: credentials
+-- and secrets are recorded by name, and hashes cover metadata only.
+
+CREATE TABLE audit_log (
+    id            VARCHAR(36)  PRIMARY KEY,
+    time          TIMESTAMPTZ  NOT NULL,
+    actor         VARCHAR(255) NOT NULL,
+    auth_method   VARCHAR(32)  NOT NULL DEFAULT '',
+    action        VARCHAR(64)  NOT NULL,
+    resource_type VARCHAR(32)  NOT NULL,
+    resource      VARCHAR(512) NOT NULL,
+    namespace     VARCHAR(128) NOT NULL DEFAULT '',
+    environment   VARCHAR(128) NOT NULL DEFAULT '',
+    before_hash   VARCHAR(80)  NOT NULL DEFAULT '',
+    after_hash    VARCHAR(80)  NOT NULL DEFAULT '',
+    details       JSONB,
+    request       JSONB
+);
+
+CREATE INDEX idx_audit_log_time ON audit_log (time DESC);
+CREATE INDEX idx_audit_log_resource ON audit_log (resource_type, resource);
+CREATE INDEX idx_audit_log_actor ON audit_log (actor);
+
+-- Entries are immutable once written.
+CREATE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_append_only
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
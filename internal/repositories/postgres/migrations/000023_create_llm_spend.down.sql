@@ -0,0 +1,4 @@
+ALTER TABLE execution_trace_steps DROP COLUMN IF EXISTS llm_cost_usd;
+ALTER TABLE execution_traces DROP COLUMN IF EXISTS llm_cost_usd;
+DROP TABLE IF EXISTS llm_daily_spend;
+DROP TABLE IF EXISTS llm_execution_spend;
@@ -0,0 +1,23 @@
+-- LLM spend totals that schema budgets (llmBudget.perExecution / llmBudget.daily) are enforced
+-- against. Rows are incremented in place by every priced LLM call of an ai node.
+
+CREATE TABLE llm_execution_spend (
+    workflow_id VARCHAR(36)      PRIMARY KEY,
+    cost_usd    DOUBLE PRECISION NOT NULL DEFAULT 0,
+    tokens      BIGINT           NOT NULL DEFAULT 0,
+    updated_at  TIMESTAMPTZ      NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE llm_daily_spend (
+    schema_id   VARCHAR(128)     NOT NULL,
+    environment VARCHAR(128)     NOT NULL,
+    day         DATE             NOT NULL,
+    cost_usd    DOUBLE PRECISION NOT NULL DEFAULT 0,
+    tokens      BIGINT           NOT NULL DEFAULT 0,
+    updated_at  TIMESTAMPTZ      NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (schema_id, environment, day)
+);
+
+-- Priced LLM cost of each execution and step, shown in execution traces.
+ALTER TABLE execution_traces ADD COLUMN llm_cost_usd DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE execution_trace_steps ADD COLUMN llm_cost_usd DOUBLE PRECISION NOT NULL DEFAULT 0;
@@ -0,0 +1,14 @@
+-- Conversation transcripts of ai/chat and ai/agent runs that share a sessionId. Sessions expire
+-- after their TTL; expired rows are ignored on read and removed by the retention sweep.
+
+CREATE TABLE agent_sessions (
+    namespace  VARCHAR(128) NOT NULL DEFAULT 'default',
+    id         VARCHAR(128) NOT NULL,
+    messages   JSONB        NOT NULL DEFAULT '[]',
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ  NOT NULL,
+    PRIMARY KEY (namespace, id)
+);
+
+CREATE INDEX idx_agent_sessions_expires_at ON agent_sessions (expires_at);
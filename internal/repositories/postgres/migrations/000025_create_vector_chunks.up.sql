@@ -0,0 +1,30 @@
+-- Embedded document chunks for ai/index and ai/retrieve (VECTOR_STORE_DRIVER=postgres). The table
+-- needs the pgvector extension. On a server that does not ship it, or where the migration role may
+-- not create it, this migration is a no-op so the rest of the schema still applies; the postgres
+-- vector store then refuses to start until the extension is installed and this migration re-run.
+
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_available_extensions WHERE name = 'vector') THEN
+        CREATE EXTENSION IF NOT EXISTS vector;
+
+        CREATE TABLE IF NOT EXISTS vector_chunks (
+            namespace  VARCHAR(128) NOT NULL DEFAULT 'default',
+            collection VARCHAR(128) NOT NULL,
+            id         VARCHAR(255) NOT NULL,
+            content    TEXT         NOT NULL DEFAULT '',
+            metadata   JSONB        NOT NULL DEFAULT 'null',
+            dims       INTEGER      NOT NULL,
+            embedding  vector       NOT NULL,
+            created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+            updated_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+            PRIMARY KEY (namespace, collection, id)
+        );
+    ELSE
+        RAISE NOTICE 'pgvector is not available; skipping vector_chunks';
+    END IF;
+EXCEPTION
+    WHEN insufficient_privilege THEN
+        RAISE NOTICE 'not allowed to create the pgvector extension; skipping vector_chunks';
+END
+$$;
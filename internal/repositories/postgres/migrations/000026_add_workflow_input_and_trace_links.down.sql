@@ -0,0 +1,4 @@
+ALTER TABLE execution_trace_steps DROP COLUMN IF EXISTS children;
+ALTER TABLE execution_traces DROP COLUMN IF EXISTS parent_exec_id;
+ALTER TABLE execution_traces DROP COLUMN IF EXISTS parent_workflow_id;
+ALTER TABLE workflows DROP COLUMN IF EXISTS input;
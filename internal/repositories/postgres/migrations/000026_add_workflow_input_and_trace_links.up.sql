@@ -0,0 +1,9 @@
+-- The trigger input of each execution, read by source:"input" mappings. Like workflows.vars it is
+-- set once at creation so replay and recovery map the input the execution started with.
+ALTER TABLE workflows ADD COLUMN input JSONB NOT NULL DEFAULT '{}';
+
+-- Parent/child links between executions, shown in execution traces: the step (or tool call) of the
+-- parent that started a sub-workflow, and the sub-workflows each step started.
+ALTER TABLE execution_traces ADD COLUMN parent_workflow_id VARCHAR(36);
+ALTER TABLE execution_traces ADD COLUMN parent_exec_id VARCHAR(64);
+ALTER TABLE execution_trace_steps ADD COLUMN children JSONB;
@@ -0,0 +1,11 @@
+-- MCP servers registered per namespace, whose tools ai/agent nodes can call. The config holds
+-- {{secret:...}} and {{credential:...}} references; the values stay in the secret store.
+
+CREATE TABLE mcp_servers (
+    namespace  VARCHAR(128) NOT NULL DEFAULT 'default',
+    id         VARCHAR(32)  NOT NULL,
+    config     JSONB        NOT NULL,
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (namespace, id)
+);
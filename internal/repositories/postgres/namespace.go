@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+)
+
+// NamespaceRepository is a PostgreSQL-backed NamespaceRepository. Quotas are plain columns where
+// zero means unlimited.
+type NamespaceRepository struct {
+	pool *pgxpool.Pool
+}
+
+// compile-time assertion.
+var _ repositories.NamespaceRepository = (*NamespaceRepository)(nil)
+
+// NewNamespaceRepository creates a new PostgreSQL-backed NamespaceRepository.
+func NewNamespaceRepository(pool *pgxpool.Pool) repositories.NamespaceRepository {
+	return &NamespaceRepository{pool: pool}
+}
+
+// FindByName retrieves a namespace by name.
+func (r *NamespaceRepository) FindByName(name string) (*workflow.Namespace, error) {
+	ctx := context.Background()
+
+	ns := &workflow.Namespace{Name: name}
+	err := r.pool.QueryRow(ctx,
+		`SELECT description, max_concurrent_workflows, max_storage_bytes FROM namespaces WHERE name = $1`, name,
+	).Scan(&ns.Description, &ns.Quota.MaxConcurrentWorkflows, &ns.Quota.MaxStorageBytes)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, repositories.ErrNamespaceNotFound
+		}
+		return nil, fmt.Errorf("postgres/namespace: find by name: %w", err)
+	}
+	return ns, nil
+}
+
+// FindAll retrieves all namespaces sorted by name.
+func (r *NamespaceRepository) FindAll() ([]*workflow.Namespace, error) {
+	ctx := context.Background()
+
+	rows, err := r.pool.Query(ctx,
+		`SELECT name, description, max_concurrent_workflows, max_storage_bytes FROM namespaces ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("postgres/namespace: find all: %w", err)
+	}
+	defer rows.Close()
+
+	namespaces := make([]*workflow.Namespace, 0)
+	for rows.Next() {
+		ns := &workflow.Namespace{}
+		if err := rows.Scan(&ns.Name, &ns.Description, &ns.Quota.MaxConcurrentWorkflows, &ns.Quota.MaxStorageBytes); err != nil {
+			return nil, fmt.Errorf("postgres/namespace: scan row: %w", err)
+		}
+		namespaces = append(namespaces, ns)
+	}
+	return namespaces, rows.Err()
+}
+
+// Save upserts a namespace.
+func (r *NamespaceRepository) Save(ns *workflow.Namespace) error {
+	ctx := context.Background()
+
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO namespaces (name, description, max_concurrent_workflows, max_storage_bytes, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW())
+		ON CONFLICT (name) DO UPDATE SET
+			description = EXCLUDED.description,
+			max_concurrent_workflows = EXCLUDED.max_concurrent_workflows,
+			max_storage_bytes = EXCLUDED.max_storage_bytes,
+			updated_at = NOW()
+	`, ns.Name, ns.Description, ns.Quota.MaxConcurrentWorkflows, ns.Quota.MaxStorageBytes)
+	if err != nil {
+		return fmt.Errorf("postgres/namespace: upsert %q: %w", ns.Name, err)
+	}
+	return nil
+}
+
+// Delete removes a namespace by name.
+func (r *NamespaceRepository) Delete(name string) error {
+	ctx := context.Background()
+	if _, err := r.pool.Exec(ctx, `DELETE FROM namespaces WHERE name = $1`, name); err != nil {
+		return fmt.Errorf("postgres/namespace: delete %q: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/open-source-cloud/fuse/internal/auth"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+)
+
+// PolicyRepository is a PostgreSQL-backed PolicyRepository.
+type PolicyRepository struct {
+	pool *pgxpool.Pool
+}
+
+// compile-time assertion.
+var _ repositories.PolicyRepository = (*PolicyRepository)(nil)
+
+// NewPolicyRepository creates a new PostgreSQL-backed PolicyRepository.
+func NewPolicyRepository(pool *pgxpool.Pool) repositories.PolicyRepository {
+	return &PolicyRepository{pool: pool}
+}
+
+const roleColumns = `name, description, rules, created_at, updated_at`
+
+// FindRole retrieves a role by name.
+func (r *PolicyRepository) FindRole(name string) (*auth.Role, error) {
+	ctx := context.Background()
+	role, err := scanRole(r.pool.QueryRow(ctx, `SELECT `+roleColumns+` FROM roles WHERE name = $1`, name))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repositories.ErrRoleNotFound
+		}
+		return nil, fmt.Errorf("postgres/policy: find role: %w", err)
+	}
+	return role, nil
+}
+
+// FindAllRoles retrieves all roles sorted by name.
+func (r *PolicyRepository) FindAllRoles() ([]*auth.Role, error) {
+	ctx := context.Background()
+	rows, err := r.pool.Query(ctx, `SELECT `+roleColumns+` FROM roles ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("postgres/policy: find all roles: %w", err)
+	}
+	defer rows.Close()
+
+	roles := make([]*auth.Role, 0)
+	for rows.Next() {
+		role, err := scanRole(rows)
+		if err != nil {
+			return nil, fmt.Errorf("postgres/policy: scan role: %w", err)
+		}
+		roles = append(roles, role)
+	}
+	return roles, rows.Err()
+}
+
+// SaveRole upserts a role.
+func (r *PolicyRepository) SaveRole(role *auth.Role) error {
+	ctx := context.Background()
+	rules, err := json.Marshal(role.Rules)
+	if err != nil {
+		return fmt.Errorf("postgres/policy: marshal rules of %q: %w", role.Name, err)
+	}
+	_, err = r.pool.Exec(ctx, `
+		INSERT INTO roles (name, description, rules, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (name) DO UPDATE SET
+			description = EXCLUDED.description,
+			rules = EXCLUDED.rules,
+			updated_at = EXCLUDED.updated_at
+	`, role.Name, role.Description, rules, role.CreatedAt, role.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("postgres/policy: upsert role %q: %w", role.Name, err)
+	}
+	return nil
+}
+
+// DeleteRole removes a role and its bindings.
+func (r *PolicyRepository) DeleteRole(name string) error {
+	ctx := context.Background()
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("postgres/policy: begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, `DELETE FROM roles WHERE name = $1`, name)
+	if err != nil {
+		return fmt.Errorf("postgres/policy: delete role %q: %w", name, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repositories.ErrRoleNotFound
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM role_bindings WHERE role = $1`, name); err != nil {
+		return fmt.Errorf("postgres/policy: delete bindings of role %q: %w", name, err)
+	}
+	return tx.Commit(ctx)
+}
+
+// FindAllBindings retrieves all role bindings sorted by subject and role.
+func (r *PolicyRepository) FindAllBindings() ([]*auth.RoleBinding, error) {
+	return r.findBindings(`SELECT subject, role, created_at FROM role_bindings ORDER BY subject, role`)
+}
+
+// FindBindingsBySubject retrieves the role bindings of a subject sorted by role.
+func (r *PolicyRepository) FindBindingsBySubject(subject string) ([]*auth.RoleBinding, error) {
+	return r.findBindings(`SELECT subject, role, created_at FROM role_bindings WHERE subject = $1 ORDER BY role`, subject)
+}
+
+func (r *PolicyRepository) findBindings(query string, args ...any) ([]*auth.RoleBinding, error) {
+	ctx := context.Background()
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("postgres/policy: find bindings: %w", err)
+	}
+	defer rows.Close()
+
+	bindings := make([]*auth.RoleBinding, 0)
+	for rows.Next() {
+		var b auth.RoleBinding
+		if err := rows.Scan(&b.Subject, &b.Role, &b.CreatedAt); err != nil {
+			return nil, fmt.Errorf("postgres/policy: scan binding: %w", err)
+		}
+		bindings = append(bindings, &b)
+	}
+	return bindings, rows.Err()
+}
+
+// SaveBinding stores a role binding; saving an existing binding keeps its creation time.
+func (r *PolicyRepository) SaveBinding(binding *auth.RoleBinding) error {
+	ctx := context.Background()
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO role_bindings (subject, role, created_at) VALUES ($1, $2, $3)
+		ON CONFLICT (subject, role) DO NOTHING
+	`, binding.Subject, binding.Role, binding.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("postgres/policy: save binding %s/%s: %w", binding.Subject, binding.Role, err)
+	}
+	return nil
+}
+
+// DeleteBinding removes a role binding.
+func (r *PolicyRepository) DeleteBinding(subject, role string) error {
+	ctx := context.Background()
+	tag, err := r.pool.Exec(ctx, `DELETE FROM role_bindings WHERE subject = $1 AND role = $2`, subject, role)
+	if err != nil {
+		return fmt.Errorf("postgres/policy: delete binding %s/%s: %w", subject, role, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repositories.ErrRoleBindingNotFound
+	}
+	return nil
+}
+
+func scanRole(row pgx.Row) (*auth.Role, error) {
+	var role auth.Role
+	var rules []byte
+	if err := row.Scan(&role.Name, &role.Description, &rules, &role.CreatedAt, &role.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(rules, &role.Rules); err != nil {
+		return nil, fmt.Errorf("unmarshal rules of %q: %w", role.Name, err)
+	}
+	return &role, nil
+}
@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/open-source-cloud/fuse/pkg/objectstore"
+)
+
+// scanDeletedRefs drains the rows of a query returning object store reference columns (usually a
+// DELETE ... RETURNING statement) and returns the row count along with every non-NULL reference.
+func scanDeletedRefs(rows pgx.Rows) (int64, []string, error) {
+	defer rows.Close()
+
+	var deleted int64
+	var refs []string
+	cols := len(rows.FieldDescriptions())
+	for rows.Next() {
+		values := make([]*string, cols)
+		dest := make([]any, cols)
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return deleted, refs, err
+		}
+		deleted++
+		for _, v := range values {
+			if v != nil && *v != "" {
+				refs = append(refs, *v)
+			}
+		}
+	}
+	return deleted, refs, rows.Err()
+}
+
+// deleteObjects removes payload objects whose rows have already been deleted. Missing objects are
+// not an error (Delete is idempotent); other failures are joined so one bad key does not stop the rest.
+func deleteObjects(ctx context.Context, store objectstore.ObjectStore, refs []string) error {
+	var errs []error
+	for _, ref := range refs {
+		if err := store.Delete(ctx, ref); err != nil {
+			errs = append(errs, fmt.Errorf("delete object %q: %w", ref, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -10,35 +10,38 @@ import (
 )
 
 // SecretStore is an encrypted-at-rest secrets.ManagedSecretStore backed by
-// PostgreSQL. Values are AES-256-GCM encrypted on write and decrypted on read;
-// the database column only ever holds ciphertext.
+// PostgreSQL. Values are envelope-encrypted (AES-256-GCM) on write and decrypted
+// on read; the database columns only ever hold ciphertext and the key ID.
 type SecretStore struct {
-	pool   *pgxpool.Pool
-	cipher *secrets.Cipher
+	pool    *pgxpool.Pool
+	keyring *secrets.Keyring
 }
 
-// compile-time assertion.
-var _ secrets.ManagedSecretStore = (*SecretStore)(nil)
+// compile-time assertions.
+var (
+	_ secrets.ManagedSecretStore = (*SecretStore)(nil)
+	_ secrets.KeyRotator         = (*SecretStore)(nil)
+)
 
 // NewSecretStore creates a PostgreSQL-backed encrypted secret store.
-func NewSecretStore(pool *pgxpool.Pool, cipher *secrets.Cipher) *SecretStore {
-	return &SecretStore{pool: pool, cipher: cipher}
+func NewSecretStore(pool *pgxpool.Pool, keyring *secrets.Keyring) *SecretStore {
+	return &SecretStore{pool: pool, keyring: keyring}
 }
 
-// Resolve decrypts and returns the secret for (environment, name).
+// Resolve decrypts and returns the secret for (namespace, environment, name).
 func (r *SecretStore) Resolve(ctx context.Context, scope secrets.Scope, name string) (secrets.SecretValue, error) {
-	var enc []byte
+	var env secrets.Envelope
 	err := r.pool.QueryRow(ctx,
-		`SELECT encrypted_value FROM secrets WHERE environment = $1 AND name = $2`,
-		scope.Environment, name,
-	).Scan(&enc)
+		`SELECT key_id, encrypted_dek, encrypted_value FROM secrets WHERE namespace = $1 AND environment = $2 AND name = $3`,
+		scope.NamespaceOrDefault(), scope.Environment, name,
+	).Scan(&env.KeyID, &env.EncryptedDEK, &env.Ciphertext)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return secrets.SecretValue{}, fmt.Errorf("%w: %q (environment %q)", secrets.ErrSecretNotFound, name, scope.Environment)
+			return secrets.SecretValue{}, fmt.Errorf("%w: %q (namespace %q, environment %q)", secrets.ErrSecretNotFound, name, scope.NamespaceOrDefault(), scope.Environment)
 		}
 		return secrets.SecretValue{}, fmt.Errorf("postgres/secrets: query: %w", err)
 	}
-	plaintext, err := r.cipher.Decrypt(enc)
+	plaintext, err := r.keyring.Open(env)
 	if err != nil {
 		return secrets.SecretValue{}, fmt.Errorf("postgres/secrets: decrypt %q: %w", name, err)
 	}
@@ -47,16 +50,17 @@ func (r *SecretStore) Resolve(ctx context.Context, scope secrets.Scope, name str
 
 // Set encrypts and upserts a secret value.
 func (r *SecretStore) Set(ctx context.Context, scope secrets.Scope, name, value string) error {
-	enc, err := r.cipher.Encrypt([]byte(value))
+	env, err := r.keyring.Seal([]byte(value))
 	if err != nil {
 		return fmt.Errorf("postgres/secrets: encrypt %q: %w", name, err)
 	}
 	_, err = r.pool.Exec(ctx,
-		`INSERT INTO secrets (environment, name, encrypted_value, source, created_at, updated_at)
-		 VALUES ($1, $2, $3, 'manual', NOW(), NOW())
-		 ON CONFLICT (environment, name)
-		 DO UPDATE SET encrypted_value = EXCLUDED.encrypted_value, updated_at = NOW()`,
-		scope.Environment, name, enc,
+		`INSERT INTO secrets (namespace, environment, name, key_id, encrypted_dek, encrypted_value, source, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, 'manual', NOW(), NOW())
+		 ON CONFLICT (namespace, environment, name)
+		 DO UPDATE SET key_id = EXCLUDED.key_id, encrypted_dek = EXCLUDED.encrypted_dek,
+		               encrypted_value = EXCLUDED.encrypted_value, updated_at = NOW()`,
+		scope.NamespaceOrDefault(), scope.Environment, name, env.KeyID, env.EncryptedDEK, env.Ciphertext,
 	)
 	if err != nil {
 		return fmt.Errorf("postgres/secrets: upsert %q: %w", name, err)
@@ -64,9 +68,10 @@ func (r *SecretStore) Set(ctx context.Context, scope secrets.Scope, name, value
 	return nil
 }
 
-// List returns the secret names in an environment, sorted.
-func (r *SecretStore) List(ctx context.Context, environment string) ([]string, error) {
-	rows, err := r.pool.Query(ctx, `SELECT name FROM secrets WHERE environment = $1 ORDER BY name`, environment)
+// List returns the secret names in a namespace and environment, sorted.
+func (r *SecretStore) List(ctx context.Context, scope secrets.Scope) ([]string, error) {
+	rows, err := r.pool.Query(ctx, `SELECT name FROM secrets WHERE namespace = $1 AND environment = $2 ORDER BY name`,
+		scope.NamespaceOrDefault(), scope.Environment)
 	if err != nil {
 		return nil, fmt.Errorf("postgres/secrets: list: %w", err)
 	}
@@ -85,8 +90,82 @@ func (r *SecretStore) List(ctx context.Context, environment string) ([]string, e
 
 // Delete removes a secret.
 func (r *SecretStore) Delete(ctx context.Context, scope secrets.Scope, name string) error {
-	if _, err := r.pool.Exec(ctx, `DELETE FROM secrets WHERE environment = $1 AND name = $2`, scope.Environment, name); err != nil {
+	if _, err := r.pool.Exec(ctx, `DELETE FROM secrets WHERE namespace = $1 AND environment = $2 AND name = $3`,
+		scope.NamespaceOrDefault(), scope.Environment, name); err != nil {
 		return fmt.Errorf("postgres/secrets: delete %q: %w", name, err)
 	}
 	return nil
 }
+
+// RotateKeys re-wraps the data keys of every secret not under the active key, one transaction
+// per batch. Rows are locked only while their batch is rewritten, so reads and writes continue;
+// a secret set concurrently is already sealed with the active key and is not picked up again.
+// Rows that predate envelope encryption are re-encrypted with a fresh data key.
+func (r *SecretStore) RotateKeys(ctx context.Context, batchSize int) (int, error) {
+	if batchSize <= 0 {
+		return 0, fmt.Errorf("postgres/secrets: batch size must be positive")
+	}
+	total := 0
+	for {
+		n, err := r.rotateBatch(ctx, batchSize)
+		if err != nil {
+			return total, err
+		}
+		total += n
+		if n < batchSize {
+			return total, nil
+		}
+	}
+}
+
+func (r *SecretStore) rotateBatch(ctx context.Context, batchSize int) (int, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("postgres/secrets: begin rotation: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	activeID := r.keyring.ActiveKeyID()
+	rows, err := tx.Query(ctx,
+		`SELECT id, key_id, encrypted_dek, encrypted_value FROM secrets
+		 WHERE key_id <> $1 OR encrypted_dek IS NULL
+		 ORDER BY id LIMIT $2 FOR UPDATE`,
+		activeID, batchSize,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("postgres/secrets: select rotation batch: %w", err)
+	}
+	ids := make([]int64, 0, batchSize)
+	envs := make([]secrets.Envelope, 0, batchSize)
+	for rows.Next() {
+		var id int64
+		var env secrets.Envelope
+		if err := rows.Scan(&id, &env.KeyID, &env.EncryptedDEK, &env.Ciphertext); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("postgres/secrets: scan: %w", err)
+		}
+		ids = append(ids, id)
+		envs = append(envs, env)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("postgres/secrets: select rotation batch: %w", err)
+	}
+
+	for i, env := range envs {
+		rotated, err := r.keyring.Rewrap(env)
+		if err != nil {
+			return 0, fmt.Errorf("postgres/secrets: rewrap secret %d: %w", ids[i], err)
+		}
+		if _, err := tx.Exec(ctx,
+			`UPDATE secrets SET key_id = $2, encrypted_dek = $3, encrypted_value = $4 WHERE id = $1`,
+			ids[i], rotated.KeyID, rotated.EncryptedDEK, rotated.Ciphertext,
+		); err != nil {
+			return 0, fmt.Errorf("postgres/secrets: update secret %d: %w", ids[i], err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("postgres/secrets: commit rotation: %w", err)
+	}
+	return len(envs), nil
+}
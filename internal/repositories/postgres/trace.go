@@ -44,18 +44,22 @@ func (r *TraceRepository) Save(trace *workflow.ExecutionTrace) error {
 	_, err = tx.Exec(ctx, `
 		INSERT INTO execution_traces (
 			workflow_id, schema_id, status, triggered_at, completed_at,
-			duration, error, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+			duration, error, llm_cost_usd, parent_workflow_id, parent_exec_id, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''), NULLIF($10, ''), NOW())
 		ON CONFLICT (workflow_id) DO UPDATE SET
-			status       = EXCLUDED.status,
-			completed_at = EXCLUDED.completed_at,
-			duration     = EXCLUDED.duration,
-			error        = EXCLUDED.error,
-			updated_at   = NOW()
+			status             = EXCLUDED.status,
+			completed_at       = EXCLUDED.completed_at,
+			duration           = EXCLUDED.duration,
+			error              = EXCLUDED.error,
+			llm_cost_usd       = EXCLUDED.llm_cost_usd,
+			parent_workflow_id = EXCLUDED.parent_workflow_id,
+			parent_exec_id     = EXCLUDED.parent_exec_id,
+			updated_at         = NOW()
 	`,
 		trace.WorkflowID, trace.SchemaID, trace.Status.String(),
 		trace.TriggeredAt, trace.CompletedAt,
-		trace.Duration, trace.Error,
+		trace.Duration, trace.Error, trace.LLMCostUSD,
+		trace.ParentWorkflowID, trace.ParentExecID,
 	)
 	if err != nil {
 		return fmt.Errorf("postgres/trace: upsert header: %w", err)
@@ -87,17 +91,24 @@ func (r *TraceRepository) Save(trace *workflow.ExecutionTrace) error {
 			outputRef = &ref
 		}
 
+		var children []byte
+		if len(step.Children) > 0 {
+			if children, err = json.Marshal(step.Children); err != nil {
+				return fmt.Errorf("postgres/trace: marshal step children: %w", err)
+			}
+		}
+
 		_, err = tx.Exec(ctx, `
 			INSERT INTO execution_trace_steps (
 				workflow_id, exec_id, thread_id, function_node_id,
 				started_at, completed_at, duration, input_ref, output_ref,
-				status, attempt, error
-			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+				status, attempt, error, llm_cost_usd, children
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 		`,
 			trace.WorkflowID, step.ExecID, safeUint16ToInt16(step.ThreadID),
 			step.FunctionNodeID, step.StartedAt, step.CompletedAt,
 			step.Duration, inputRef, outputRef,
-			step.Status, step.Attempt, step.Error,
+			step.Status, step.Attempt, step.Error, step.LLMCostUSD, children,
 		)
 		if err != nil {
 			return fmt.Errorf("postgres/trace: insert step %s: %w", step.ExecID, err)
@@ -114,7 +125,8 @@ func (r *TraceRepository) FindByWorkflowID(workflowID string) (*workflow.Executi
 	// Fetch header
 	row := r.pool.QueryRow(ctx, `
 		SELECT workflow_id, schema_id, status::TEXT, triggered_at, completed_at,
-		       duration, error
+		       duration, error, llm_cost_usd,
+		       COALESCE(parent_workflow_id, ''), COALESCE(parent_exec_id, '')
 		FROM execution_traces
 		WHERE workflow_id = $1
 	`, workflowID)
@@ -124,7 +136,8 @@ func (r *TraceRepository) FindByWorkflowID(workflowID string) (*workflow.Executi
 	err := row.Scan(
 		&t.WorkflowID, &t.SchemaID, &statusStr,
 		&t.TriggeredAt, &t.CompletedAt,
-		&t.Duration, &t.Error,
+		&t.Duration, &t.Error, &t.LLMCostUSD,
+		&t.ParentWorkflowID, &t.ParentExecID,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -183,7 +196,8 @@ func (r *TraceRepository) FindBySchemaID(schemaID string, opts repositories.Trac
 
 	query := fmt.Sprintf(`
 		SELECT workflow_id, schema_id, status::TEXT, triggered_at, completed_at,
-		       duration, error
+		       duration, error, llm_cost_usd,
+		       COALESCE(parent_workflow_id, ''), COALESCE(parent_exec_id, '')
 		FROM execution_traces %s
 		ORDER BY triggered_at DESC
 		LIMIT $%d OFFSET $%d
@@ -200,10 +214,11 @@ func (r *TraceRepository) FindBySchemaID(schemaID string, opts repositories.Trac
 	for rows.Next() {
 		var t workflow.ExecutionTrace
 		var statusStr string
-		if scanErr := rows.Scan(
+			if scanErr := rows.Scan(
 			&t.WorkflowID, &t.SchemaID, &statusStr,
 			&t.TriggeredAt, &t.CompletedAt,
-			&t.Duration, &t.Error,
+			&t.Duration, &t.Error, &t.LLMCostUSD,
+			&t.ParentWorkflowID, &t.ParentExecID,
 		); scanErr != nil {
 			return nil, fmt.Errorf("postgres/trace: scan: %w", scanErr)
 		}
@@ -241,10 +256,47 @@ func (r *TraceRepository) Delete(workflowID string) error {
 	return nil
 }
 
+// DeleteByWorkflowIDs removes the traces of the given workflows, their steps, and the step payload objects.
+func (r *TraceRepository) DeleteByWorkflowIDs(workflowIDs []string) (int64, error) {
+	ctx := context.Background()
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("postgres/trace: begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Steps would cascade, but deleting them explicitly returns the payload refs to clean up
+	rows, err := tx.Query(ctx, `
+		DELETE FROM execution_trace_steps WHERE workflow_id = ANY($1)
+		RETURNING input_ref, output_ref
+	`, workflowIDs)
+	if err != nil {
+		return 0, fmt.Errorf("postgres/trace: delete steps: %w", err)
+	}
+	_, refs, err := scanDeletedRefs(rows)
+	if err != nil {
+		return 0, fmt.Errorf("postgres/trace: delete steps: %w", err)
+	}
+
+	tag, err := tx.Exec(ctx, `DELETE FROM execution_traces WHERE workflow_id = ANY($1)`, workflowIDs)
+	if err != nil {
+		return 0, fmt.Errorf("postgres/trace: delete: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("postgres/trace: commit: %w", err)
+	}
+
+	if err := deleteObjects(ctx, r.store, refs); err != nil {
+		return tag.RowsAffected(), fmt.Errorf("postgres/trace: delete step payloads: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
 func (r *TraceRepository) loadSteps(ctx context.Context, workflowID string) ([]workflow.ExecutionStepTrace, error) {
 	rows, err := r.pool.Query(ctx, `
 		SELECT exec_id, thread_id, function_node_id, started_at, completed_at,
-		       duration, input_ref, output_ref, status, attempt, error
+		       duration, input_ref, output_ref, status, attempt, error, llm_cost_usd, children
 		FROM execution_trace_steps
 		WHERE workflow_id = $1
 		ORDER BY id
@@ -259,16 +311,22 @@ func (r *TraceRepository) loadSteps(ctx context.Context, workflowID string) ([]w
 		var s workflow.ExecutionStepTrace
 		var threadID int16
 		var inputRef, outputRef *string
+		var children []byte
 
 		if scanErr := rows.Scan(
 			&s.ExecID, &threadID, &s.FunctionNodeID,
 			&s.StartedAt, &s.CompletedAt, &s.Duration,
 			&inputRef, &outputRef,
-			&s.Status, &s.Attempt, &s.Error,
+			&s.Status, &s.Attempt, &s.Error, &s.LLMCostUSD, &children,
 		); scanErr != nil {
 			return nil, fmt.Errorf("postgres/trace: scan step: %w", scanErr)
 		}
 		s.ThreadID = safeInt16ToUint16(threadID)
+		if len(children) > 0 {
+			if err := json.Unmarshal(children, &s.Children); err != nil {
+				return nil, fmt.Errorf("postgres/trace: decode step children: %w", err)
+			}
+		}
 
 		// Fetch payloads from object store
 		if inputRef != nil {
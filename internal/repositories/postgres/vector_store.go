@@ -0,0 +1,198 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/open-source-cloud/fuse/pkg/vectorstore"
+)
+
+// ErrVectorStoreUnavailable is returned by VectorStore.Available when the vector_chunks table is
+// missing, i.e. the pgvector extension was not installed when migrations ran.
+var ErrVectorStoreUnavailable = errors.New("postgres/vector_store: vector_chunks table is missing (install the pgvector extension and re-run migration 000025)")
+
+// VectorStore is a vectorstore.VectorStore backed by the pgvector extension. Vectors are passed as
+// text literals cast to vector, so no pgvector client types are needed. The embedding column has
+// no fixed width (collections may use different models), so queries scan the collection exactly
+// rather than through an approximate index.
+type VectorStore struct {
+	pool *pgxpool.Pool
+}
+
+// compile-time assertion.
+var _ vectorstore.VectorStore = (*VectorStore)(nil)
+
+// NewVectorStore creates a pgvector-backed vector store.
+func NewVectorStore(pool *pgxpool.Pool) *VectorStore {
+	return &VectorStore{pool: pool}
+}
+
+// Available reports ErrVectorStoreUnavailable when the vector_chunks table does not exist.
+func (s *VectorStore) Available(ctx context.Context) error {
+	var exists bool
+	if err := s.pool.QueryRow(ctx, `SELECT to_regclass('vector_chunks') IS NOT NULL`).Scan(&exists); err != nil {
+		return fmt.Errorf("postgres/vector_store: check table: %w", err)
+	}
+	if !exists {
+		return ErrVectorStoreUnavailable
+	}
+	return nil
+}
+
+// Upsert inserts or replaces chunks in one transaction. The collection is locked for the
+// transaction so concurrent first writes cannot fix two different dimensions.
+func (s *VectorStore) Upsert(ctx context.Context, namespace, collection string, chunks []vectorstore.Chunk) error {
+	if len(chunks) == 0 {
+		return nil
+	}
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("postgres/vector_store: begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, namespace+"/"+collection); err != nil {
+		return fmt.Errorf("postgres/vector_store: lock collection %q: %w", collection, err)
+	}
+	dims, err := collectionDims(ctx, tx, namespace, collection)
+	if err != nil {
+		return err
+	}
+
+	batch := &pgx.Batch{}
+	for _, chunk := range chunks {
+		if len(chunk.Vector) == 0 {
+			return fmt.Errorf("chunk %q: %w", chunk.ID, vectorstore.ErrEmptyVector)
+		}
+		if dims == 0 {
+			dims = len(chunk.Vector)
+		}
+		if len(chunk.Vector) != dims {
+			return fmt.Errorf("chunk %q has %d dimensions, want %d: %w", chunk.ID, len(chunk.Vector), dims, vectorstore.ErrDimensionMismatch)
+		}
+		metadata, err := json.Marshal(chunk.Metadata)
+		if err != nil {
+			return fmt.Errorf("postgres/vector_store: encode metadata of %q: %w", chunk.ID, err)
+		}
+		batch.Queue(`
+			INSERT INTO vector_chunks (namespace, collection, id, content, metadata, dims, embedding, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7::vector, NOW(), NOW())
+			ON CONFLICT (namespace, collection, id) DO UPDATE SET
+				content = EXCLUDED.content,
+				metadata = EXCLUDED.metadata,
+				dims = EXCLUDED.dims,
+				embedding = EXCLUDED.embedding,
+				updated_at = NOW()
+		`, namespace, collection, chunk.ID, chunk.Content, metadata, len(chunk.Vector), vectorLiteral(chunk.Vector))
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("postgres/vector_store: upsert into %q: %w", collection, err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("postgres/vector_store: commit: %w", err)
+	}
+	return nil
+}
+
+// Query orders the collection by cosine distance (<=>) and reports 1 - distance as the score.
+func (s *VectorStore) Query(ctx context.Context, namespace, collection string, vector []float32, topK int) ([]vectorstore.Match, error) {
+	if len(vector) == 0 {
+		return nil, vectorstore.ErrEmptyVector
+	}
+	matches := make([]vectorstore.Match, 0)
+	if topK <= 0 {
+		return matches, nil
+	}
+	dims, err := collectionDims(ctx, s.pool, namespace, collection)
+	if err != nil {
+		return nil, err
+	}
+	if dims == 0 {
+		return matches, nil
+	}
+	if len(vector) != dims {
+		return nil, fmt.Errorf("query has %d dimensions, want %d: %w", len(vector), dims, vectorstore.ErrDimensionMismatch)
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT id, content, metadata, 1 - (embedding <=> $3::vector) AS score
+		FROM vector_chunks
+		WHERE namespace = $1 AND collection = $2
+		ORDER BY embedding <=> $3::vector, id
+		LIMIT $4
+	`, namespace, collection, vectorLiteral(vector), topK)
+	if err != nil {
+		return nil, fmt.Errorf("postgres/vector_store: query %q: %w", collection, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var m vectorstore.Match
+		var metadata []byte
+		if err := rows.Scan(&m.ID, &m.Content, &metadata, &m.Score); err != nil {
+			return nil, fmt.Errorf("postgres/vector_store: scan: %w", err)
+		}
+		if err := json.Unmarshal(metadata, &m.Metadata); err != nil {
+			return nil, fmt.Errorf("postgres/vector_store: decode metadata of %q: %w", m.ID, err)
+		}
+		matches = append(matches, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgres/vector_store: query %q: %w", collection, err)
+	}
+	return matches, nil
+}
+
+// Delete removes chunks by ID.
+func (s *VectorStore) Delete(ctx context.Context, namespace, collection string, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := s.pool.Exec(ctx,
+		`DELETE FROM vector_chunks WHERE namespace = $1 AND collection = $2 AND id = ANY($3)`,
+		namespace, collection, ids)
+	if err != nil {
+		return fmt.Errorf("postgres/vector_store: delete from %q: %w", collection, err)
+	}
+	return nil
+}
+
+// querier is the subset of pgxpool.Pool and pgx.Tx used by collectionDims.
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// collectionDims returns the dimensions of a collection, or 0 when it has no chunks.
+func collectionDims(ctx context.Context, q querier, namespace, collection string) (int, error) {
+	var dims int
+	err := q.QueryRow(ctx,
+		`SELECT dims FROM vector_chunks WHERE namespace = $1 AND collection = $2 LIMIT 1`,
+		namespace, collection).Scan(&dims)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("postgres/vector_store: read dimensions of %q: %w", collection, err)
+	}
+	return dims, nil
+}
+
+// vectorLiteral formats v in pgvector's text form, e.g. [0.1,0.2].
+func vectorLiteral(v []float32) string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, f := range v {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.FormatFloat(float64(f), 'g', -1, 32))
+	}
+	b.WriteByte(']')
+	return b.String()
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -46,11 +47,13 @@ func (r *WorkflowRepository) Get(id string) (*workflow.Workflow, error) {
 	ctx := context.Background()
 
 	var schemaID, state, environment string
+	var schemaVersion int
 	var outputRef *string
+	var vars, input []byte
 	err := r.pool.QueryRow(ctx, `
-		SELECT schema_id, state, output_ref, environment
+		SELECT schema_id, state, output_ref, environment, schema_version, vars, input
 		FROM workflows WHERE workflow_id = $1
-	`, id).Scan(&schemaID, &state, &outputRef, &environment)
+	`, id).Scan(&schemaID, &state, &outputRef, &environment, &schemaVersion, &vars, &input)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, fmt.Errorf("workflow %s not found", id)
@@ -59,12 +62,23 @@ func (r *WorkflowRepository) Get(id string) (*workflow.Workflow, error) {
 	}
 
 	// Load graph schema from DB + object store
-	graph, err := r.loadGraph(ctx, schemaID)
+	graph, err := r.loadGraph(ctx, schemaID, schemaVersion)
 	if err != nil {
 		return nil, fmt.Errorf("postgres/workflow: load graph for %q: %w", schemaID, err)
 	}
 
 	wf := workflow.New(pkgwf.ID(id), graph, environment)
+	wf.SetSchemaVersion(schemaVersion)
+	var pinnedVars map[string]string
+	if err := json.Unmarshal(vars, &pinnedVars); err != nil {
+		return nil, fmt.Errorf("postgres/workflow: decode vars of %q: %w", id, err)
+	}
+	wf.SetVars(pinnedVars)
+	var triggerInput map[string]any
+	if err := json.Unmarshal(input, &triggerInput); err != nil {
+		return nil, fmt.Errorf("postgres/workflow: decode input of %q: %w", id, err)
+	}
+	wf.SetInput(triggerInput)
 
 	// Restore state without appending a journal entry.
 	// SetState() appends a state:changed journal entry, which is wrong during reconstruction.
@@ -96,16 +110,35 @@ func (r *WorkflowRepository) Save(wf *workflow.Workflow) error {
 		outputRef = &key
 	}
 
-	// environment is set once at create and intentionally excluded from the DO UPDATE clause:
-	// later saves happen on every state change and must not clobber the original scope (ADR-0031).
-	_, err := r.pool.Exec(ctx, `
-		INSERT INTO workflows (workflow_id, schema_id, state, output_ref, environment, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+	vars := wf.Vars()
+	if vars == nil {
+		vars = map[string]string{}
+	}
+	varsJSON, err := json.Marshal(vars)
+	if err != nil {
+		return fmt.Errorf("postgres/workflow: marshal vars: %w", err)
+	}
+	input := wf.Input()
+	if input == nil {
+		input = map[string]any{}
+	}
+	inputJSON, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("postgres/workflow: marshal input: %w", err)
+	}
+
+	// environment, namespace, schema_version, vars and input are set once at create and
+	// intentionally excluded from the DO UPDATE clause: later saves happen on every state change and
+	// must not clobber the original scope (ADR-0031), the version the execution was started on or
+	// the variables and input it pinned.
+	_, err = r.pool.Exec(ctx, `
+		INSERT INTO workflows (workflow_id, schema_id, state, output_ref, environment, namespace, schema_version, vars, input, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW(), NOW())
 		ON CONFLICT (workflow_id) DO UPDATE SET
 			state = EXCLUDED.state,
 			output_ref = EXCLUDED.output_ref,
 			updated_at = NOW()
-	`, wfID, wf.Schema().ID, wf.State().String(), outputRef, wf.Environment())
+	`, wfID, wf.Schema().ID, wf.State().String(), outputRef, wf.Environment(), wf.Namespace(), wf.SchemaVersion(), varsJSON, inputJSON)
 	if err != nil {
 		return fmt.Errorf("postgres/workflow: save: %w", err)
 	}
@@ -238,11 +271,20 @@ func (r *WorkflowRepository) FindActiveSubWorkflows(parentID string) ([]*workflo
 }
 
 // loadGraph fetches the graph schema definition from the object store and constructs a Graph.
-func (r *WorkflowRepository) loadGraph(ctx context.Context, schemaID string) (*workflow.Graph, error) {
+// Executions pinned to a schema version load that version's definition; executions without a
+// recorded version load the schema's current definition.
+func (r *WorkflowRepository) loadGraph(ctx context.Context, schemaID string, version int) (*workflow.Graph, error) {
 	var defRef string
-	err := r.pool.QueryRow(ctx,
-		`SELECT definition_ref FROM graph_schemas WHERE schema_id = $1`, schemaID,
-	).Scan(&defRef)
+	var err error
+	if version > 0 {
+		err = r.pool.QueryRow(ctx,
+			`SELECT definition_ref FROM graph_schema_versions WHERE schema_id = $1 AND version = $2`, schemaID, version,
+		).Scan(&defRef)
+	} else {
+		err = r.pool.QueryRow(ctx,
+			`SELECT definition_ref FROM graph_schemas WHERE schema_id = $1`, schemaID,
+		).Scan(&defRef)
+	}
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, fmt.Errorf("graph schema %q not found", schemaID)
@@ -292,7 +334,57 @@ func (r *WorkflowRepository) SetSnapshotRef(workflowID string, snapshotRef strin
 	return nil
 }
 
-// FindExecutions returns a paginated list of workflow executions filtered by schema, status, and time range.
+// UpsertSearchAttributes stores or updates search attribute values of an execution.
+func (r *WorkflowRepository) UpsertSearchAttributes(workflowID string, attributes map[string]string) error {
+	if len(attributes) == 0 {
+		return nil
+	}
+	ctx := context.Background()
+	names := make([]string, 0, len(attributes))
+	values := make([]string, 0, len(attributes))
+	for name, value := range attributes {
+		names = append(names, name)
+		values = append(values, value)
+	}
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO workflow_search_attributes (workflow_id, schema_id, name, value)
+		SELECT w.workflow_id, w.schema_id, a.name, a.value
+		FROM workflows w, UNNEST($2::TEXT[], $3::TEXT[]) AS a(name, value)
+		WHERE w.workflow_id = $1
+		ON CONFLICT (workflow_id, name) DO UPDATE SET
+			value = EXCLUDED.value,
+			updated_at = NOW()
+	`, workflowID, names, values)
+	if err != nil {
+		return fmt.Errorf("postgres/workflow: upsert search attributes: %w", err)
+	}
+	return nil
+}
+
+// FindSearchAttributes returns the search attribute values of an execution.
+func (r *WorkflowRepository) FindSearchAttributes(workflowID string) (map[string]string, error) {
+	ctx := context.Background()
+	rows, err := r.pool.Query(ctx,
+		`SELECT name, value FROM workflow_search_attributes WHERE workflow_id = $1`, workflowID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("postgres/workflow: find search attributes: %w", err)
+	}
+	defer rows.Close()
+
+	attributes := make(map[string]string)
+	for rows.Next() {
+		var name, value string
+		if scanErr := rows.Scan(&name, &value); scanErr != nil {
+			return nil, fmt.Errorf("postgres/workflow: scan search attribute: %w", scanErr)
+		}
+		attributes[name] = value
+	}
+	return attributes, rows.Err()
+}
+
+// FindExecutions returns a paginated list of workflow executions filtered by namespace, schema,
+// status, version, search attributes and time range.
 func (r *WorkflowRepository) FindExecutions(filter repositories.ExecutionListFilter) (*repositories.ExecutionListResult, error) {
 	ctx := context.Background()
 
@@ -307,15 +399,36 @@ func (r *WorkflowRepository) FindExecutions(filter repositories.ExecutionListFil
 	offset := (page - 1) * size
 
 	// Build WHERE clause dynamically
-	where := "WHERE schema_id = $1"
-	args := []any{filter.SchemaID}
-	argIdx := 2
+	where := "WHERE TRUE"
+	args := []any{}
+	argIdx := 1
 
+	if filter.Namespace != "" {
+		where += fmt.Sprintf(" AND namespace = $%d", argIdx)
+		args = append(args, filter.Namespace)
+		argIdx++
+	}
+	if filter.SchemaID != "" {
+		where += fmt.Sprintf(" AND schema_id = $%d", argIdx)
+		args = append(args, filter.SchemaID)
+		argIdx++
+	}
+	for name, value := range filter.Attributes {
+		where += fmt.Sprintf(` AND workflow_id IN (
+			SELECT workflow_id FROM workflow_search_attributes WHERE name = $%d AND value = $%d)`, argIdx, argIdx+1)
+		args = append(args, name, value)
+		argIdx += 2
+	}
 	if filter.Status != "" {
 		where += fmt.Sprintf(" AND state = $%d::workflow_state", argIdx)
 		args = append(args, filter.Status)
 		argIdx++
 	}
+	if filter.Version > 0 {
+		where += fmt.Sprintf(" AND schema_version = $%d", argIdx)
+		args = append(args, filter.Version)
+		argIdx++
+	}
 	if !filter.From.IsZero() {
 		where += fmt.Sprintf(" AND created_at >= $%d", argIdx)
 		args = append(args, filter.From)
@@ -336,8 +449,10 @@ func (r *WorkflowRepository) FindExecutions(filter repositories.ExecutionListFil
 
 	// Fetch page
 	dataQuery := fmt.Sprintf(`
-		SELECT workflow_id, schema_id, state::TEXT, created_at, updated_at
-		FROM workflows %s
+		SELECT w.workflow_id, w.schema_id, w.state::TEXT, w.schema_version,
+			(SELECT jsonb_object_agg(a.name, a.value) FROM workflow_search_attributes a WHERE a.workflow_id = w.workflow_id),
+			w.created_at, w.updated_at
+		FROM workflows w %s
 		ORDER BY id DESC
 		LIMIT $%d OFFSET $%d
 	`, where, argIdx, argIdx+1)
@@ -352,7 +467,7 @@ func (r *WorkflowRepository) FindExecutions(filter repositories.ExecutionListFil
 	var items []repositories.ExecutionListItem
 	for rows.Next() {
 		var item repositories.ExecutionListItem
-		if scanErr := rows.Scan(&item.WorkflowID, &item.SchemaID, &item.State, &item.CreatedAt, &item.UpdatedAt); scanErr != nil {
+		if scanErr := rows.Scan(&item.WorkflowID, &item.SchemaID, &item.State, &item.Version, &item.Attributes, &item.CreatedAt, &item.UpdatedAt); scanErr != nil {
 			return nil, fmt.Errorf("postgres/workflow: scan execution: %w", scanErr)
 		}
 		items = append(items, item)
@@ -375,6 +490,113 @@ func (r *WorkflowRepository) FindExecutions(filter repositories.ExecutionListFil
 	}, rows.Err()
 }
 
+// CountActive returns the number of untriggered, running and sleeping executions of a namespace.
+func (r *WorkflowRepository) CountActive(namespace string) (int, error) {
+	var count int
+	err := r.pool.QueryRow(context.Background(), `
+		SELECT COUNT(*) FROM workflows
+		WHERE namespace = $1 AND state IN ('untriggered', 'running', 'sleeping')
+	`, namespace).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("postgres/workflow: count active: %w", err)
+	}
+	return count, nil
+}
+
+// FindPurgeable returns the IDs of root executions matching the purge query, oldest first.
+// Executions are ranked per schema and state by updated_at, which is the completion time for
+// terminal states; sub-workflow executions are excluded and purged together with their root.
+func (r *WorkflowRepository) FindPurgeable(query repositories.PurgeQuery) ([]string, error) {
+	ctx := context.Background()
+
+	var conditions []string
+	args := []any{query.SchemaID, query.State.String()}
+	if !query.Before.IsZero() {
+		args = append(args, query.Before)
+		conditions = append(conditions, fmt.Sprintf("updated_at < $%d", len(args)))
+	}
+	if query.Keep > 0 {
+		args = append(args, query.Keep)
+		conditions = append(conditions, fmt.Sprintf("rank > $%d", len(args)))
+	}
+	if len(conditions) == 0 {
+		return nil, nil
+	}
+
+	limit := ""
+	if query.Limit > 0 {
+		args = append(args, query.Limit)
+		limit = fmt.Sprintf("LIMIT $%d", len(args))
+	}
+
+	rows, err := r.pool.Query(ctx, fmt.Sprintf(`
+		SELECT workflow_id FROM (
+			SELECT w.workflow_id, w.updated_at, w.id,
+			       ROW_NUMBER() OVER (ORDER BY w.updated_at DESC, w.id DESC) AS rank
+			FROM workflows w
+			WHERE w.schema_id = $1 AND w.state = $2::workflow_state
+			  AND NOT EXISTS (SELECT 1 FROM sub_workflow_refs s WHERE s.child_workflow_id = w.workflow_id)
+		) ranked
+		WHERE %s
+		ORDER BY updated_at, id
+		%s
+	`, strings.Join(conditions, " OR "), limit), args...)
+	if err != nil {
+		return nil, fmt.Errorf("postgres/workflow: find purgeable: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("postgres/workflow: scan id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// Delete removes the given executions, their sub-workflow references and output objects.
+// Journal, awakeable and trace rows reference workflows and must be deleted first.
+func (r *WorkflowRepository) Delete(workflowIDs []string) (int64, error) {
+	ctx := context.Background()
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("postgres/workflow: begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	_, err = tx.Exec(ctx, `
+		DELETE FROM sub_workflow_refs
+		WHERE child_workflow_id = ANY($1) OR parent_workflow_id = ANY($1)
+	`, workflowIDs)
+	if err != nil {
+		return 0, fmt.Errorf("postgres/workflow: delete sub-workflow refs: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, `
+		DELETE FROM workflows WHERE workflow_id = ANY($1)
+		RETURNING output_ref
+	`, workflowIDs)
+	if err != nil {
+		return 0, fmt.Errorf("postgres/workflow: delete: %w", err)
+	}
+	deleted, refs, err := scanDeletedRefs(rows)
+	if err != nil {
+		return 0, fmt.Errorf("postgres/workflow: delete: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("postgres/workflow: commit: %w", err)
+	}
+
+	if err := deleteObjects(ctx, r.store, refs); err != nil {
+		return deleted, fmt.Errorf("postgres/workflow: delete outputs: %w", err)
+	}
+	return deleted, nil
+}
+
 // restoreState sets the workflow state directly without appending a journal entry.
 // This is used during reconstruction from the database.
 func (r *WorkflowRepository) restoreState(wf *workflow.Workflow, state workflow.State) {
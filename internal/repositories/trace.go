@@ -36,4 +36,6 @@ type TraceRepository interface {
 	FindBySchemaID(schemaID string, opts TraceQueryOpts) (*TraceQueryResult, error)
 	// Delete removes a trace
 	Delete(workflowID string) error
+	// DeleteByWorkflowIDs removes the traces of the given workflows; returns traces deleted
+	DeleteByWorkflowIDs(workflowIDs []string) (int64, error)
 }
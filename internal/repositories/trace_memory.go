@@ -97,9 +97,29 @@ func (r *MemoryTraceRepository) Delete(workflowID string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	r.deleteLocked(workflowID)
+	return nil
+}
+
+// DeleteByWorkflowIDs removes the traces of the given workflows
+func (r *MemoryTraceRepository) DeleteByWorkflowIDs(workflowIDs []string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for _, id := range workflowIDs {
+		if r.deleteLocked(id) {
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// deleteLocked removes a trace and its schema index entry; the caller must hold the write lock
+func (r *MemoryTraceRepository) deleteLocked(workflowID string) bool {
 	trace, exists := r.traces[workflowID]
 	if !exists {
-		return nil
+		return false
 	}
 
 	delete(r.traces, workflowID)
@@ -113,5 +133,5 @@ func (r *MemoryTraceRepository) Delete(workflowID string) error {
 		}
 	}
 
-	return nil
+	return true
 }
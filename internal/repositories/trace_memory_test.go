@@ -158,3 +158,19 @@ func TestMemoryTraceRepository_FindBySchemaID_SinceFilter(t *testing.T) {
 	assert.Equal(t, 1, result.Total)
 	assert.Equal(t, "wf-recent", result.Traces[0].WorkflowID)
 }
+
+func TestMemoryTraceRepository_DeleteByWorkflowIDs(t *testing.T) {
+	repo := NewMemoryTraceRepository()
+	require.NoError(t, repo.Save(newTestTrace("wf-1", "schema-1", workflow.StateFinished)))
+	require.NoError(t, repo.Save(newTestTrace("wf-2", "schema-1", workflow.StateError)))
+	require.NoError(t, repo.Save(newTestTrace("wf-3", "schema-1", workflow.StateFinished)))
+
+	deleted, err := repo.DeleteByWorkflowIDs([]string{"wf-1", "wf-2", "missing"})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+	result, err := repo.FindBySchemaID("schema-1", TraceQueryOpts{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Total)
+	assert.Equal(t, "wf-3", result.Traces[0].WorkflowID)
+}
@@ -8,21 +8,26 @@ import (
 
 // ExecutionListItem is a lightweight projection of a workflow for list endpoints.
 type ExecutionListItem struct {
-	WorkflowID string    `json:"workflowId"`
-	SchemaID   string    `json:"schemaId"`
-	State      string    `json:"state"`
-	CreatedAt  time.Time `json:"createdAt"`
-	UpdatedAt  time.Time `json:"updatedAt"`
+	WorkflowID string            `json:"workflowId"`
+	SchemaID   string            `json:"schemaId"`
+	State      string            `json:"state"`
+	Version    int               `json:"version"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	CreatedAt  time.Time         `json:"createdAt"`
+	UpdatedAt  time.Time         `json:"updatedAt"`
 }
 
 // ExecutionListFilter defines the query parameters for listing executions.
 type ExecutionListFilter struct {
-	SchemaID string
-	Status   string    // optional filter by state
-	From     time.Time // optional: created_at >= from
-	To       time.Time // optional: created_at <= to
-	Page     int
-	Size     int
+	Namespace  string            // optional: empty searches across namespaces
+	SchemaID   string            // optional: empty searches across schemas
+	Status     string            // optional filter by state
+	Version    int               // optional filter by schema version (0 = any)
+	Attributes map[string]string // optional: every search attribute must match exactly
+	From       time.Time         // optional: created_at >= from
+	To         time.Time         // optional: created_at <= to
+	Page       int
+	Size       int
 }
 
 // ExecutionListResult is a paginated result for listing executions.
@@ -34,6 +39,16 @@ type ExecutionListResult struct {
 	LastPage int                 `json:"lastPage"`
 }
 
+// PurgeQuery selects terminal root executions of a schema that exceed a retention policy.
+// Sub-workflow executions are never selected on their own; they are purged with their root.
+type PurgeQuery struct {
+	SchemaID string
+	State    workflow.State
+	Before   time.Time // optional: select executions last updated before this instant
+	Keep     int       // optional: select all but the Keep most recently updated executions
+	Limit    int       // optional: maximum number of IDs returned (0 = unlimited)
+}
+
 type (
 	// WorkflowRepository defines the interface o a WorkflowRepository repository
 	WorkflowRepository interface {
@@ -52,7 +67,19 @@ type (
 		GetSnapshotRef(workflowID string) (string, error)
 		// SetSnapshotRef records the object store key of the execution snapshot
 		SetSnapshotRef(workflowID string, snapshotRef string) error
-		// FindExecutions returns a paginated list of workflow executions filtered by schema, status, and time range.
+		// FindExecutions returns a paginated list of workflow executions filtered by namespace,
+		// schema, status, version, search attributes and time range.
 		FindExecutions(filter ExecutionListFilter) (*ExecutionListResult, error)
+		// CountActive returns the number of untriggered, running and sleeping executions of a
+		// namespace.
+		CountActive(namespace string) (int, error)
+		// UpsertSearchAttributes stores or updates search attribute values of an execution.
+		UpsertSearchAttributes(workflowID string, attributes map[string]string) error
+		// FindSearchAttributes returns the search attribute values of an execution.
+		FindSearchAttributes(workflowID string) (map[string]string, error)
+		// FindPurgeable returns the IDs of root executions matching the purge query, oldest first.
+		FindPurgeable(query PurgeQuery) ([]string, error)
+		// Delete removes the given executions and their sub-workflow references; returns rows deleted.
+		Delete(workflowIDs []string) (int64, error)
 	}
 )
@@ -2,8 +2,10 @@ package repositories
 
 import (
 	"fmt"
+	"maps"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/open-source-cloud/fuse/internal/workflow"
 )
@@ -16,6 +18,8 @@ type MemoryWorkflowRepository struct {
 	subWorkflowRefs map[string]*workflow.SubWorkflowRef // childID -> ref
 	parentChildren  map[string][]string                 // parentID -> []childID
 	snapshotRefs    map[string]string                   // workflowID -> snapshot ref
+	updatedAt       map[string]time.Time                // workflowID -> last save
+	searchAttrs     map[string]map[string]string        // workflowID -> name -> value
 }
 
 // NewMemoryWorkflowRepository creates a new in-memory WorkflowRepository repository
@@ -25,6 +29,8 @@ func NewMemoryWorkflowRepository() WorkflowRepository {
 		subWorkflowRefs: make(map[string]*workflow.SubWorkflowRef),
 		parentChildren:  make(map[string][]string),
 		snapshotRefs:    make(map[string]string),
+		updatedAt:       make(map[string]time.Time),
+		searchAttrs:     make(map[string]map[string]string),
 	}
 }
 
@@ -52,6 +58,7 @@ func (m *MemoryWorkflowRepository) Save(workflow *workflow.Workflow) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.workflows[workflow.ID().String()] = workflow
+	m.updatedAt[workflow.ID().String()] = time.Now()
 	return nil
 }
 
@@ -115,16 +122,27 @@ func (m *MemoryWorkflowRepository) FindExecutions(filter ExecutionListFilter) (*
 
 	matching := make([]ExecutionListItem, 0, len(m.workflows))
 	for id, wf := range m.workflows {
+		if filter.Namespace != "" && wf.Namespace() != filter.Namespace {
+			continue
+		}
 		if filter.SchemaID != "" && wf.Graph().ID() != filter.SchemaID {
 			continue
 		}
 		if filter.Status != "" && wf.State().String() != filter.Status {
 			continue
 		}
+		if filter.Version > 0 && wf.SchemaVersion() != filter.Version {
+			continue
+		}
+		if !matchesAttributes(m.searchAttrs[id], filter.Attributes) {
+			continue
+		}
 		matching = append(matching, ExecutionListItem{
 			WorkflowID: id,
 			SchemaID:   wf.Graph().ID(),
 			State:      wf.State().String(),
+			Version:    wf.SchemaVersion(),
+			Attributes: maps.Clone(m.searchAttrs[id]),
 		})
 	}
 
@@ -164,6 +182,33 @@ func (m *MemoryWorkflowRepository) FindExecutions(filter ExecutionListFilter) (*
 	}, nil
 }
 
+// UpsertSearchAttributes stores or updates search attribute values of an execution.
+func (m *MemoryWorkflowRepository) UpsertSearchAttributes(workflowID string, attributes map[string]string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.searchAttrs[workflowID] == nil {
+		m.searchAttrs[workflowID] = make(map[string]string, len(attributes))
+	}
+	maps.Copy(m.searchAttrs[workflowID], attributes)
+	return nil
+}
+
+// FindSearchAttributes returns the search attribute values of an execution.
+func (m *MemoryWorkflowRepository) FindSearchAttributes(workflowID string) (map[string]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return maps.Clone(m.searchAttrs[workflowID]), nil
+}
+
+func matchesAttributes(attrs map[string]string, want map[string]string) bool {
+	for name, value := range want {
+		if got, ok := attrs[name]; !ok || got != value {
+			return false
+		}
+	}
+	return true
+}
+
 // FindActiveSubWorkflows finds all sub-workflow references for a parent
 func (m *MemoryWorkflowRepository) FindActiveSubWorkflows(parentID string) ([]*workflow.SubWorkflowRef, error) {
 	m.mu.RLock()
@@ -177,3 +222,87 @@ func (m *MemoryWorkflowRepository) FindActiveSubWorkflows(parentID string) ([]*w
 	}
 	return refs, nil
 }
+
+// CountActive returns the number of untriggered, running and sleeping executions of a namespace.
+func (m *MemoryWorkflowRepository) CountActive(namespace string) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	count := 0
+	for _, wf := range m.workflows {
+		if wf.Namespace() == namespace && wf.State().IsActive() {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// FindPurgeable returns the IDs of root executions matching the purge query, oldest first.
+func (m *MemoryWorkflowRepository) FindPurgeable(query PurgeQuery) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var roots []string
+	for id, wf := range m.workflows {
+		if wf.Graph().ID() != query.SchemaID || wf.State() != query.State {
+			continue
+		}
+		if _, isChild := m.subWorkflowRefs[id]; isChild {
+			continue
+		}
+		roots = append(roots, id)
+	}
+
+	// Most recent first, so the index doubles as the rank for Keep
+	sort.Slice(roots, func(i, j int) bool {
+		ti, tj := m.updatedAt[roots[i]], m.updatedAt[roots[j]]
+		if ti.Equal(tj) {
+			return roots[i] > roots[j]
+		}
+		return ti.After(tj)
+	})
+
+	var ids []string
+	for i := len(roots) - 1; i >= 0; i-- {
+		expired := !query.Before.IsZero() && m.updatedAt[roots[i]].Before(query.Before)
+		overflow := query.Keep > 0 && i >= query.Keep
+		if !expired && !overflow {
+			continue
+		}
+		ids = append(ids, roots[i])
+		if query.Limit > 0 && len(ids) >= query.Limit {
+			break
+		}
+	}
+	return ids, nil
+}
+
+// Delete removes the given executions and their sub-workflow references.
+func (m *MemoryWorkflowRepository) Delete(workflowIDs []string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var deleted int64
+	for _, id := range workflowIDs {
+		if _, exists := m.workflows[id]; exists {
+			deleted++
+		}
+		delete(m.workflows, id)
+		delete(m.snapshotRefs, id)
+		delete(m.updatedAt, id)
+		delete(m.searchAttrs, id)
+		delete(m.parentChildren, id)
+		if ref, isChild := m.subWorkflowRefs[id]; isChild {
+			parentID := ref.ParentWorkflowID.String()
+			children := m.parentChildren[parentID]
+			for i, childID := range children {
+				if childID == id {
+					m.parentChildren[parentID] = append(children[:i], children[i+1:]...)
+					break
+				}
+			}
+			delete(m.subWorkflowRefs, id)
+		}
+	}
+	return deleted, nil
+}
@@ -2,6 +2,7 @@ package repositories_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/open-source-cloud/fuse/internal/mocks"
 	"github.com/open-source-cloud/fuse/internal/repositories"
@@ -91,3 +92,140 @@ func TestMemoryWorkflowRepository_FindByState(t *testing.T) {
 		})
 	}
 }
+
+func TestMemoryWorkflowRepository_FindPurgeable(t *testing.T) {
+	// Arrange
+	repo := repositories.NewMemoryWorkflowRepository()
+	var finished []string
+	for range 3 {
+		wf := newTestWorkflow(t)
+		wf.SetState(internalworkflow.StateFinished)
+		require.NoError(t, repo.Save(wf))
+		finished = append(finished, wf.ID().String())
+	}
+	running := newTestWorkflow(t)
+	running.SetState(internalworkflow.StateRunning)
+	require.NoError(t, repo.Save(running))
+
+	child := newTestWorkflow(t)
+	child.SetState(internalworkflow.StateFinished)
+	require.NoError(t, repo.Save(child))
+	require.NoError(t, repo.SaveSubWorkflowRef(&internalworkflow.SubWorkflowRef{
+		ParentWorkflowID: workflow.ID(finished[0]),
+		ChildWorkflowID:  child.ID(),
+		ChildSchemaID:    "test",
+	}))
+
+	t.Run("max age selects every finished root", func(t *testing.T) {
+		ids, err := repo.FindPurgeable(repositories.PurgeQuery{
+			SchemaID: "test", State: internalworkflow.StateFinished, Before: time.Now().Add(time.Second),
+		})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, finished, ids)
+	})
+
+	t.Run("max count keeps the most recent", func(t *testing.T) {
+		ids, err := repo.FindPurgeable(repositories.PurgeQuery{
+			SchemaID: "test", State: internalworkflow.StateFinished, Keep: 1,
+		})
+		require.NoError(t, err)
+		assert.Len(t, ids, 2)
+	})
+
+	t.Run("limit caps the batch", func(t *testing.T) {
+		ids, err := repo.FindPurgeable(repositories.PurgeQuery{
+			SchemaID: "test", State: internalworkflow.StateFinished, Before: time.Now().Add(time.Second), Limit: 1,
+		})
+		require.NoError(t, err)
+		assert.Len(t, ids, 1)
+	})
+
+	t.Run("no limits selects nothing", func(t *testing.T) {
+		ids, err := repo.FindPurgeable(repositories.PurgeQuery{SchemaID: "test", State: internalworkflow.StateFinished})
+		require.NoError(t, err)
+		assert.Empty(t, ids)
+	})
+}
+
+func TestMemoryWorkflowRepository_Delete(t *testing.T) {
+	// Arrange
+	repo := repositories.NewMemoryWorkflowRepository()
+	parent := newTestWorkflow(t)
+	child := newTestWorkflow(t)
+	require.NoError(t, repo.Save(parent))
+	require.NoError(t, repo.Save(child))
+	require.NoError(t, repo.SaveSubWorkflowRef(&internalworkflow.SubWorkflowRef{
+		ParentWorkflowID: parent.ID(),
+		ChildWorkflowID:  child.ID(),
+		ChildSchemaID:    "test",
+	}))
+	require.NoError(t, repo.SetSnapshotRef(parent.ID().String(), "workflows/x/execution-snapshot.json"))
+
+	// Act
+	deleted, err := repo.Delete([]string{child.ID().String(), parent.ID().String(), "missing"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+	assert.False(t, repo.Exists(parent.ID().String()))
+	assert.False(t, repo.Exists(child.ID().String()))
+	_, err = repo.FindSubWorkflowRef(child.ID().String())
+	assert.Error(t, err)
+	refs, err := repo.FindActiveSubWorkflows(parent.ID().String())
+	require.NoError(t, err)
+	assert.Empty(t, refs)
+	ref, err := repo.GetSnapshotRef(parent.ID().String())
+	require.NoError(t, err)
+	assert.Empty(t, ref)
+}
+
+func TestMemoryWorkflowRepository_FindExecutionsByVersion(t *testing.T) {
+	repo := repositories.NewMemoryWorkflowRepository()
+	active := newTestWorkflow(t)
+	active.SetSchemaVersion(2)
+	require.NoError(t, repo.Save(active))
+	canary := newTestWorkflow(t)
+	canary.SetSchemaVersion(3)
+	require.NoError(t, repo.Save(canary))
+
+	result, err := repo.FindExecutions(repositories.ExecutionListFilter{SchemaID: "test", Version: 3})
+	require.NoError(t, err)
+	require.Len(t, result.Items, 1)
+	assert.Equal(t, canary.ID().String(), result.Items[0].WorkflowID)
+	assert.Equal(t, 3, result.Items[0].Version)
+
+	all, err := repo.FindExecutions(repositories.ExecutionListFilter{SchemaID: "test"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, all.Total)
+}
+
+func TestMemoryWorkflowRepository_FindExecutionsByAttributes(t *testing.T) {
+	repo := repositories.NewMemoryWorkflowRepository()
+	order := newTestWorkflow(t)
+	require.NoError(t, repo.Save(order))
+	require.NoError(t, repo.UpsertSearchAttributes(order.ID().String(), map[string]string{"orderId": "12345", "region": "eu"}))
+	other := newTestWorkflow(t)
+	require.NoError(t, repo.Save(other))
+	require.NoError(t, repo.UpsertSearchAttributes(other.ID().String(), map[string]string{"orderId": "67890", "region": "eu"}))
+
+	result, err := repo.FindExecutions(repositories.ExecutionListFilter{Attributes: map[string]string{"orderId": "12345"}})
+	require.NoError(t, err)
+	require.Len(t, result.Items, 1)
+	assert.Equal(t, order.ID().String(), result.Items[0].WorkflowID)
+	assert.Equal(t, "12345", result.Items[0].Attributes["orderId"])
+
+	byRegion, err := repo.FindExecutions(repositories.ExecutionListFilter{SchemaID: "test", Attributes: map[string]string{"region": "eu"}})
+	require.NoError(t, err)
+	assert.Equal(t, 2, byRegion.Total)
+
+	require.NoError(t, repo.UpsertSearchAttributes(order.ID().String(), map[string]string{"region": "us"}))
+	attrs, err := repo.FindSearchAttributes(order.ID().String())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"orderId": "12345", "region": "us"}, attrs)
+
+	_, err = repo.Delete([]string{order.ID().String()})
+	require.NoError(t, err)
+	attrs, err = repo.FindSearchAttributes(order.ID().String())
+	require.NoError(t, err)
+	assert.Empty(t, attrs)
+}
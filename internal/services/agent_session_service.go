@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/open-source-cloud/fuse/internal/app/config"
+	"github.com/open-source-cloud/fuse/internal/audit"
+	"github.com/open-source-cloud/fuse/internal/packages/functions/ai"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/pkg/llm"
+)
+
+type (
+	// AgentSessionService keeps the conversations of ai/chat and ai/agent nodes that set a sessionId
+	// (ADR-0028). It is the session store of the ai package and backs the /v1/ai/sessions API used
+	// to inspect and erase them.
+	AgentSessionService interface {
+		ai.SessionStore
+		// Get returns a live session or repositories.ErrAgentSessionNotFound.
+		Get(namespace, id string) (*repositories.AgentSession, error)
+		// Delete erases a session; repositories.ErrAgentSessionNotFound when there is none.
+		Delete(ctx context.Context, namespace, id string) error
+	}
+
+	// DefaultAgentSessionService is the default AgentSessionService implementation.
+	DefaultAgentSessionService struct {
+		repo  repositories.AgentSessionRepository
+		ttl   time.Duration
+		audit AuditService
+		now   func() time.Time
+	}
+)
+
+// NewAgentSessionService returns a new AgentSessionService. Sessions saved without a TTL are kept
+// for LLM_SESSION_TTL after their last run.
+func NewAgentSessionService(cfg *config.Config, repo repositories.AgentSessionRepository, auditService AuditService) AgentSessionService {
+	return &DefaultAgentSessionService{repo: repo, ttl: cfg.LLM.SessionTTL, audit: auditService, now: time.Now}
+}
+
+// Load returns the transcript of a live session, or nil when there is none.
+func (s *DefaultAgentSessionService) Load(_ context.Context, namespace, id string) ([]llm.Message, error) {
+	session, err := s.repo.Get(namespace, id)
+	if errors.Is(err, repositories.ErrAgentSessionNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return session.Messages, nil
+}
+
+// Save replaces a session's transcript and pushes its expiry back by ttl, or by the configured
+// TTL when ttl is 0.
+func (s *DefaultAgentSessionService) Save(_ context.Context, namespace, id string, messages []llm.Message, ttl time.Duration) error {
+	if ttl <= 0 {
+		ttl = s.ttl
+	}
+	now := s.now().UTC()
+	return s.repo.Save(&repositories.AgentSession{
+		Namespace: namespace,
+		ID:        id,
+		Messages:  messages,
+		CreatedAt: now,
+		UpdatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	})
+}
+
+// Get returns a live session.
+func (s *DefaultAgentSessionService) Get(namespace, id string) (*repositories.AgentSession, error) {
+	return s.repo.Get(namespace, id)
+}
+
+// Delete erases a session and records it in the audit log.
+func (s *DefaultAgentSessionService) Delete(ctx context.Context, namespace, id string) error {
+	if err := s.repo.Delete(namespace, id); err != nil {
+		return err
+	}
+	recordAudit(ctx, s.audit, audit.Entry{
+		Action:       audit.ActionSessionDelete,
+		ResourceType: audit.ResourceSession,
+		Resource:     id,
+		Namespace:    namespace,
+	})
+	return nil
+}
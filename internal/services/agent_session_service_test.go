@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/open-source-cloud/fuse/internal/app/config"
+	"github.com/open-source-cloud/fuse/internal/audit"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/pkg/llm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAgentSessionService(auditRepo repositories.AuditRepository, now time.Time) *DefaultAgentSessionService {
+	cfg := &config.Config{LLM: config.LLMConfig{SessionTTL: 24 * time.Hour}}
+	svc := NewAgentSessionService(cfg, repositories.NewMemoryAgentSessionRepository(), NewAuditService(auditRepo)).(*DefaultAgentSessionService)
+	svc.now = func() time.Time { return now }
+	return svc
+}
+
+func TestAgentSessionService_LoadSave(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	now := time.Now().UTC()
+	svc := newTestAgentSessionService(repositories.NewMemoryAuditRepository(), now)
+
+	history, err := svc.Load(ctx, "default", "s-1")
+	require.NoError(t, err)
+	assert.Nil(t, history)
+
+	turns := []llm.Message{{Role: llm.RoleUser, Content: "hi"}, {Role: llm.RoleAssistant, Content: "hello"}}
+	require.NoError(t, svc.Save(ctx, "default", "s-1", turns, 0))
+	require.NoError(t, svc.Save(ctx, "default", "s-2", turns, time.Hour))
+
+	history, err = svc.Load(ctx, "default", "s-1")
+	require.NoError(t, err)
+	assert.Equal(t, turns, history)
+	session, err := svc.Get("default", "s-1")
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(24*time.Hour), session.ExpiresAt, "defaults to LLM_SESSION_TTL")
+	session, err = svc.Get("default", "s-2")
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(time.Hour), session.ExpiresAt)
+}
+
+func TestAgentSessionService_DeleteIsAudited(t *testing.T) {
+	t.Parallel()
+	auditRepo := repositories.NewMemoryAuditRepository()
+	svc := newTestAgentSessionService(auditRepo, time.Now())
+	ctx := audit.WithActor(context.Background(), "cli:dpo")
+	require.NoError(t, svc.Save(ctx, "billing", "s-1", []llm.Message{{Role: llm.RoleUser, Content: "my card is 4242"}}, 0))
+
+	require.NoError(t, svc.Delete(ctx, "billing", "s-1"))
+	assert.ErrorIs(t, svc.Delete(ctx, "billing", "s-1"), repositories.ErrAgentSessionNotFound)
+
+	_, err := svc.Get("billing", "s-1")
+	assert.ErrorIs(t, err, repositories.ErrAgentSessionNotFound)
+	entries, err := auditRepo.Find(audit.Filter{Namespace: "billing"})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, audit.ActionSessionDelete, entries[0].Action)
+	assert.Equal(t, "s-1", entries[0].Resource)
+	assert.Equal(t, "cli:dpo", entries[0].Actor)
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/open-source-cloud/fuse/internal/auth"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/rs/zerolog/log"
+)
+
+// ErrInvalidAPIKeyRequest is returned when an API key is created without a name or with a negative lifetime
+var ErrInvalidAPIKeyRequest = errors.New("invalid api key request")
+
+type (
+	// APIKeyService issues, lists and revokes API keys.
+	APIKeyService interface {
+		// Create issues a new key. The plaintext key is returned only here; a zero ttl never expires.
+		Create(name string, ttl time.Duration) (*auth.APIKey, string, error)
+		FindAll() ([]*auth.APIKey, error)
+		Revoke(id string) error
+	}
+
+	// DefaultAPIKeyService is the default APIKeyService implementation.
+	DefaultAPIKeyService struct {
+		repo repositories.APIKeyRepository
+	}
+)
+
+// NewAPIKeyService returns a new APIKeyService.
+func NewAPIKeyService(repo repositories.APIKeyRepository) APIKeyService {
+	return &DefaultAPIKeyService{repo: repo}
+}
+
+// Create issues a new API key and stores its hash.
+func (s *DefaultAPIKeyService) Create(name string, ttl time.Duration) (*auth.APIKey, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", fmt.Errorf("%w: name is required", ErrInvalidAPIKeyRequest)
+	}
+	if ttl < 0 {
+		return nil, "", fmt.Errorf("%w: ttl must not be negative", ErrInvalidAPIKeyRequest)
+	}
+	var expiresAt *time.Time
+	if ttl > 0 {
+		at := time.Now().UTC().Add(ttl)
+		expiresAt = &at
+	}
+	key, plaintext, err := auth.NewAPIKey(name, expiresAt)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := s.repo.Save(key); err != nil {
+		return nil, "", err
+	}
+	log.Info().Str("apiKey", key.ID).Str("name", key.Name).Msg("api key created")
+	return key, plaintext, nil
+}
+
+// FindAll returns every API key, including revoked and expired ones.
+func (s *DefaultAPIKeyService) FindAll() ([]*auth.APIKey, error) {
+	return s.repo.FindAll()
+}
+
+// Revoke revokes an API key; requests using it are rejected immediately.
+func (s *DefaultAPIKeyService) Revoke(id string) error {
+	if err := s.repo.Revoke(id, time.Now().UTC()); err != nil {
+		return err
+	}
+	log.Info().Str("apiKey", id).Msg("api key revoked")
+	return nil
+}
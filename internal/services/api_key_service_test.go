@@ -0,0 +1,43 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/open-source-cloud/fuse/internal/auth"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyService_CreateAuthenticateRevoke(t *testing.T) {
+	t.Parallel()
+	repo := repositories.NewMemoryAPIKeyRepository()
+	svc := NewAPIKeyService(repo)
+	authn := auth.NewAPIKeyAuthenticator(repo)
+
+	key, plaintext, err := svc.Create("ci-pipeline", 24*time.Hour)
+	require.NoError(t, err)
+	require.NotNil(t, key.ExpiresAt)
+	assert.NotContains(t, key.Hash, plaintext)
+
+	principal, err := authn.AuthenticateKey(plaintext)
+	require.NoError(t, err)
+	assert.Equal(t, "apikey:"+key.ID, principal.Subject)
+	assert.Equal(t, "ci-pipeline", principal.Name)
+
+	require.NoError(t, svc.Revoke(key.ID))
+	_, err = authn.AuthenticateKey(plaintext)
+	assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+}
+
+func TestAPIKeyService_CreateValidates(t *testing.T) {
+	t.Parallel()
+	svc := NewAPIKeyService(repositories.NewMemoryAPIKeyRepository())
+
+	_, _, err := svc.Create("  ", 0)
+	assert.ErrorIs(t, err, ErrInvalidAPIKeyRequest)
+	_, _, err = svc.Create("ci", -time.Hour)
+	assert.ErrorIs(t, err, ErrInvalidAPIKeyRequest)
+	assert.ErrorIs(t, svc.Revoke("unknown"), repositories.ErrAPIKeyNotFound)
+}
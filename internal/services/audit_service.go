@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/open-source-cloud/fuse/internal/audit"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/pkg/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type (
+	// AuditService records administrative changes and queries the audit log.
+	AuditService interface {
+		// Record completes entry with an ID, the time, the actor and the request of ctx, and
+		// appends it. The change has already happened, so a failed write is logged rather than
+		// returned.
+		Record(ctx context.Context, entry audit.Entry)
+		Find(filter audit.Filter) ([]*audit.Entry, error)
+	}
+
+	// DefaultAuditService is the default AuditService implementation.
+	DefaultAuditService struct {
+		repo repositories.AuditRepository
+	}
+)
+
+// NewAuditService returns a new AuditService.
+func NewAuditService(repo repositories.AuditRepository) AuditService {
+	return &DefaultAuditService{repo: repo}
+}
+
+// Record appends an entry for a change made by the caller of ctx.
+func (s *DefaultAuditService) Record(ctx context.Context, entry audit.Entry) {
+	entry.ID = uuid.V7()
+	entry.Time = time.Now().UTC()
+	entry.Actor, entry.AuthMethod = audit.ActorFromContext(ctx)
+	entry.Request = audit.RequestFromContext(ctx)
+	if err := s.repo.Append(&entry); err != nil {
+		log.Error().Err(err).
+			Str("action", string(entry.Action)).
+			Str("resource", entry.Resource).
+			Str("actor", entry.Actor).
+			Msg("failed to write audit entry")
+	}
+}
+
+// Find returns the audit entries matching filter, newest first.
+func (s *DefaultAuditService) Find(filter audit.Filter) ([]*audit.Entry, error) {
+	return s.repo.Find(filter)
+}
+
+// recordAudit records entry when auditing is wired; services built without an AuditService (as
+// in unit tests) skip it.
+func recordAudit(ctx context.Context, auditService AuditService, entry audit.Entry) {
+	if auditService != nil {
+		auditService.Record(ctx, entry)
+	}
+}
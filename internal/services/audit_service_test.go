@@ -0,0 +1,100 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/open-source-cloud/fuse/internal/audit"
+	"github.com/open-source-cloud/fuse/internal/auth"
+	"github.com/open-source-cloud/fuse/internal/mocks"
+	"github.com/open-source-cloud/fuse/internal/packages"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/internal/services"
+	"github.com/open-source-cloud/fuse/pkg/llm"
+	"github.com/open-source-cloud/fuse/pkg/secrets"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditService_RecordFillsActorAndTime(t *testing.T) {
+	t.Parallel()
+	svc := services.NewAuditService(repositories.NewMemoryAuditRepository())
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "apikey:k1", Method: auth.MethodAPIKey})
+
+	svc.Record(ctx, audit.Entry{Action: audit.ActionEnvironmentSave, ResourceType: audit.ResourceEnvironment, Resource: "prod"})
+	svc.Record(context.Background(), audit.Entry{Action: audit.ActionEnvironmentDelete, ResourceType: audit.ResourceEnvironment, Resource: "prod"})
+
+	entries, err := svc.Find(audit.Filter{})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, audit.ActionEnvironmentDelete, entries[0].Action, "newest first")
+	assert.Equal(t, audit.ActorSystem, entries[0].Actor)
+	assert.Equal(t, "apikey:k1", entries[1].Actor)
+	assert.Equal(t, auth.MethodAPIKey, entries[1].AuthMethod)
+	assert.NotEmpty(t, entries[1].ID)
+	assert.False(t, entries[1].Time.IsZero())
+}
+
+func TestGraphService_RecordsAuditTrail(t *testing.T) {
+	t.Parallel()
+	auditService := services.NewAuditService(repositories.NewMemoryAuditRepository())
+	pkgRegistry := packages.NewPackageRegistry()
+	internalPackages := packages.NewInternal(packages.InternalDeps{Providers: llm.NewRegistry(nil, ""), Registry: pkgRegistry})
+	pkgSvc := services.NewPackageService(repositories.NewMemoryPackageRepository(), pkgRegistry, internalPackages, nil)
+	require.NoError(t, pkgSvc.RegisterInternalPackages())
+	svc := services.NewGraphService(repositories.NewMemoryGraphRepository(), pkgRegistry, nil, auditService)
+	ctx := context.Background()
+
+	schema := mocks.SmallTestGraphSchema()
+	_, err := svc.Upsert(ctx, schema.ID, schema)
+	require.NoError(t, err)
+	v2 := mocks.SmallTestGraphSchema()
+	v2.Name = "v2"
+	_, err = svc.Upsert(ctx, v2.ID, v2)
+	require.NoError(t, err)
+	_, err = svc.Rollback(ctx, schema.ID, 1, "bad v2")
+	require.NoError(t, err)
+
+	entries, err := auditService.Find(audit.Filter{ResourceType: audit.ResourceSchema, Resource: schema.ID})
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	rollback, second, first := entries[0], entries[1], entries[2]
+
+	assert.Equal(t, audit.ActionSchemaUpsert, first.Action)
+	assert.Empty(t, first.BeforeHash, "the schema did not exist")
+	assert.Equal(t, workflow.DefaultNamespaceName, first.Namespace)
+	assert.Equal(t, first.AfterHash, second.BeforeHash)
+	assert.NotEqual(t, second.BeforeHash, second.AfterHash)
+
+	assert.Equal(t, audit.ActionSchemaRollback, rollback.Action)
+	assert.Equal(t, second.AfterHash, rollback.BeforeHash)
+	assert.Equal(t, first.AfterHash, rollback.AfterHash, "rolled back to v1's content")
+	assert.Equal(t, map[string]string{"restoredFrom": "1", "version": "3"}, rollback.Details)
+}
+
+func TestCredentialService_AuditNeverHoldsValues(t *testing.T) {
+	t.Parallel()
+	repo := repositories.NewMemoryAuditRepository()
+	svc := services.NewCredentialService(repositories.NewMemoryCredentialRepository(), secrets.NewMemorySecretStore(), services.NewAuditService(repo))
+	ctx := audit.WithActor(context.Background(), "cli:alice")
+	scope := secrets.Scope{Namespace: "billing", Environment: "prod"}
+
+	_, err := svc.Save(ctx, workflow.NewCredential("stripe", "custom", "", nil), map[string]string{"apiKey": "sk-live-123"}, scope)
+	require.NoError(t, err)
+	require.NoError(t, svc.Delete(ctx, "stripe", scope))
+
+	entries, err := repo.Find(audit.Filter{Namespace: "billing"})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, audit.ActionCredentialDelete, entries[0].Action)
+	assert.Equal(t, entries[1].AfterHash, entries[0].BeforeHash)
+	assert.Equal(t, "cli:alice", entries[1].Actor)
+	assert.Equal(t, "prod", entries[1].Environment)
+	assert.Equal(t, "apiKey", entries[1].Details["fields"])
+
+	raw, err := json.Marshal(entries)
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "sk-live-123")
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/open-source-cloud/fuse/internal/packages/functions/ai"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/pkg/llm"
+)
+
+type (
+	// BudgetService enforces the LLM budgets of workflow schemas (llmBudget) against the spend totals
+	// shared by every node of the cluster. It is the ledger ai nodes charge their calls to.
+	BudgetService interface {
+		ai.BudgetLedger
+	}
+
+	// DefaultBudgetService is the default BudgetService implementation. Only budgets a schema sets
+	// are tracked, so schemas without an llmBudget cost no writes.
+	DefaultBudgetService struct {
+		repo repositories.LLMSpendRepository
+		now  func() time.Time
+	}
+)
+
+// NewBudgetService returns a new BudgetService.
+func NewBudgetService(repo repositories.LLMSpendRepository) BudgetService {
+	return &DefaultBudgetService{repo: repo, now: time.Now}
+}
+
+// Check returns a *llm.BudgetExceededError when the execution's or the schema's daily spend is
+// already over its budget.
+func (s *DefaultBudgetService) Check(_ context.Context, scope ai.BudgetScope) error {
+	if scope.Budget == nil {
+		return nil
+	}
+	if scope.Budget.PerExecution != nil {
+		total, err := s.repo.GetExecution(scope.WorkflowID)
+		if err != nil {
+			return err
+		}
+		if err := ai.LimitError(llm.BudgetScopeExecution, scope.Budget.PerExecution, total.CostUSD, total.Tokens); err != nil {
+			return err
+		}
+	}
+	if scope.Budget.Daily != nil {
+		total, err := s.repo.GetDaily(scope.SchemaID, scope.Environment, s.now())
+		if err != nil {
+			return err
+		}
+		return ai.LimitError(llm.BudgetScopeDaily, scope.Budget.Daily, total.CostUSD, total.Tokens)
+	}
+	return nil
+}
+
+// Charge adds a call's spend to the execution's and the schema's daily totals. Both totals are
+// charged even when the first goes over its budget; the first exceeded budget is returned.
+func (s *DefaultBudgetService) Charge(_ context.Context, scope ai.BudgetScope, costUSD float64, tokens int) error {
+	if scope.Budget == nil {
+		return nil
+	}
+	spend := repositories.LLMSpend{CostUSD: costUSD, Tokens: tokens}
+	var errs []error
+	if scope.Budget.PerExecution != nil {
+		total, err := s.repo.AddExecution(scope.WorkflowID, spend)
+		if err == nil {
+			err = ai.LimitError(llm.BudgetScopeExecution, scope.Budget.PerExecution, total.CostUSD, total.Tokens)
+		}
+		errs = append(errs, err)
+	}
+	if scope.Budget.Daily != nil {
+		total, err := s.repo.AddDaily(scope.SchemaID, scope.Environment, s.now(), spend)
+		if err == nil {
+			err = ai.LimitError(llm.BudgetScopeDaily, scope.Budget.Daily, total.CostUSD, total.Tokens)
+		}
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
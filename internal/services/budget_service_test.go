@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/open-source-cloud/fuse/internal/packages/functions/ai"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/pkg/llm"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBudgetService(repo repositories.LLMSpendRepository, now time.Time) *DefaultBudgetService {
+	svc := NewBudgetService(repo).(*DefaultBudgetService)
+	svc.now = func() time.Time { return now }
+	return svc
+}
+
+func TestBudgetService_PerExecution(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	svc := newTestBudgetService(repositories.NewMemoryLLMSpendRepository(), time.Now())
+	scope := ai.BudgetScope{WorkflowID: "wf-1", SchemaID: "orders", Environment: "prod", Budget: &workflow.LLMBudget{
+		PerExecution: &workflow.BudgetLimit{MaxCostUSD: 1},
+	}}
+
+	require.NoError(t, svc.Charge(ctx, scope, 0.6, 100))
+	require.NoError(t, svc.Check(ctx, scope))
+
+	err := svc.Charge(ctx, scope, 0.6, 100)
+	var budgetErr *llm.BudgetExceededError
+	require.True(t, errors.As(err, &budgetErr))
+	assert.Equal(t, llm.BudgetScopeExecution, budgetErr.Scope)
+	assert.Equal(t, llm.BudgetResourceCost, budgetErr.Resource)
+	assert.InDelta(t, 1.2, budgetErr.Spent, 1e-9)
+	require.ErrorAs(t, svc.Check(ctx, scope), &budgetErr)
+
+	other := scope
+	other.WorkflowID = "wf-2"
+	assert.NoError(t, svc.Check(ctx, other), "the limit is per execution")
+}
+
+func TestBudgetService_DailyPerSchemaAndEnvironment(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo := repositories.NewMemoryLLMSpendRepository()
+	day := time.Date(2026, 6, 1, 10, 0, 0, 0, time.UTC)
+	svc := newTestBudgetService(repo, day)
+	budget := &workflow.LLMBudget{Daily: &workflow.BudgetLimit{MaxTokens: 1000}}
+	scope := func(wfID, env string) ai.BudgetScope {
+		return ai.BudgetScope{WorkflowID: wfID, SchemaID: "orders", Environment: env, Budget: budget}
+	}
+
+	require.NoError(t, svc.Charge(ctx, scope("wf-1", "prod"), 0, 600))
+	err := svc.Charge(ctx, scope("wf-2", "prod"), 0, 600)
+	var budgetErr *llm.BudgetExceededError
+	require.ErrorAs(t, err, &budgetErr)
+	assert.Equal(t, llm.BudgetScopeDaily, budgetErr.Scope)
+	assert.Equal(t, llm.BudgetResourceTokens, budgetErr.Resource)
+
+	assert.Error(t, svc.Check(ctx, scope("wf-3", "prod")))
+	assert.NoError(t, svc.Check(ctx, scope("wf-3", "staging")))
+
+	svc.now = func() time.Time { return day.Add(24 * time.Hour) }
+	assert.NoError(t, svc.Check(ctx, scope("wf-3", "prod")), "a new day starts a new total")
+
+	executions, err := repo.GetExecution("wf-1")
+	require.NoError(t, err)
+	assert.Zero(t, executions, "execution totals are only kept for perExecution budgets")
+}
+
+func TestBudgetService_NoBudget(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo := repositories.NewMemoryLLMSpendRepository()
+	svc := NewBudgetService(repo)
+	scope := ai.BudgetScope{WorkflowID: "wf-1", SchemaID: "orders", Environment: "prod"}
+
+	require.NoError(t, svc.Charge(ctx, scope, 100, 1_000_000))
+	require.NoError(t, svc.Check(ctx, scope))
+	total, err := repo.GetExecution("wf-1")
+	require.NoError(t, err)
+	assert.Zero(t, total)
+}
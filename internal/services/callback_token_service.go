@@ -0,0 +1,186 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/open-source-cloud/fuse/internal/app/config"
+	"github.com/open-source-cloud/fuse/internal/idempotency"
+	"github.com/rs/zerolog/log"
+)
+
+// minCallbackKeyLen is the minimum HMAC key size accepted for CALLBACK_SIGNING_KEY.
+const minCallbackKeyLen = 32
+
+var (
+	// ErrCallbackTokenInvalid is returned for a missing, malformed or forged callback token, or one
+	// minted for another execution or awakeable.
+	ErrCallbackTokenInvalid = errors.New("invalid callback token")
+	// ErrCallbackTokenExpired is returned for a callback token past its expiry.
+	ErrCallbackTokenExpired = errors.New("callback token expired")
+	// ErrCallbackTokenUsed is returned when a callback token has already been consumed.
+	ErrCallbackTokenUsed = errors.New("callback token already used")
+	// ErrCallbackTokenSuperseded is returned for the token of an execution attempt that was
+	// retried since; only the latest attempt's token completes the execution.
+	ErrCallbackTokenSuperseded = errors.New("callback token superseded by a later attempt")
+)
+
+// CallbackKind is what a callback token completes; a token of one kind never verifies as another.
+type CallbackKind string
+
+const (
+	// CallbackExec tokens authorize POST /v1/workflows/{workflowID}/execs/{execID}.
+	CallbackExec CallbackKind = "exec"
+	// CallbackAwakeable tokens authorize POST /v1/awakeables/{awakeableID}/resolve.
+	CallbackAwakeable CallbackKind = "awakeable"
+)
+
+type (
+	// CallbackTokenService mints and consumes the signed, single-use tokens that authorize async
+	// function results and awakeable resolutions.
+	CallbackTokenService interface {
+		// Mint returns a token for subject (an exec or awakeable ID) of workflowID. A zero ttl uses
+		// the configured default lifetime. An exec token supersedes those minted for the exec's
+		// earlier attempts.
+		Mint(kind CallbackKind, workflowID, subject string, ttl time.Duration) string
+		// Consume verifies token against kind, workflowID and subject and marks it used, so only
+		// the first call for a token succeeds.
+		Consume(token string, kind CallbackKind, workflowID, subject string) error
+		// Release makes a consumed token usable again, for a request that could not deliver what
+		// the token authorized.
+		Release(token string)
+	}
+
+	// DefaultCallbackTokenService is the default CallbackTokenService implementation. Tokens are
+	// HMAC-SHA256 signatures; the latest token of each exec and the consumed tokens are recorded in
+	// the idempotency store until they expire.
+	DefaultCallbackTokenService struct {
+		key        []byte
+		defaultTTL time.Duration
+		used       idempotency.Store
+		now        func() time.Time
+	}
+)
+
+// NewCallbackTokenService returns a new CallbackTokenService signing with CALLBACK_SIGNING_KEY, or
+// with a random per-process key when it is not set.
+func NewCallbackTokenService(cfg *config.Config, used idempotency.Store) (CallbackTokenService, error) {
+	key, err := callbackSigningKey(cfg.Callback.SigningKey)
+	if err != nil {
+		return nil, err
+	}
+	return &DefaultCallbackTokenService{
+		key:        key,
+		defaultTTL: cfg.Callback.TokenTTL,
+		used:       used,
+		now:        time.Now,
+	}, nil
+}
+
+func callbackSigningKey(encoded string) ([]byte, error) {
+	if encoded == "" {
+		log.Warn().Msg("CALLBACK_SIGNING_KEY is not set; using a per-process key, so callback tokens only verify on the node that minted them")
+		key := make([]byte, minCallbackKeyLen)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("generate callback signing key: %w", err)
+		}
+		return key, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("CALLBACK_SIGNING_KEY is not valid base64: %w", err)
+	}
+	if len(key) < minCallbackKeyLen {
+		return nil, fmt.Errorf("CALLBACK_SIGNING_KEY must decode to at least %d bytes, got %d", minCallbackKeyLen, len(key))
+	}
+	return key, nil
+}
+
+// Mint returns "<expiry unix seconds>.<base64url signature>".
+func (s *DefaultCallbackTokenService) Mint(kind CallbackKind, workflowID, subject string, ttl time.Duration) string {
+	if ttl <= 0 {
+		ttl = s.defaultTTL
+	}
+	expiry := strconv.FormatInt(s.now().Add(ttl).Unix(), 10)
+	token := expiry + "." + s.sign(kind, workflowID, subject, expiry)
+	if kind == CallbackExec {
+		if err := s.used.Set(currentTokenKey(workflowID, subject), tokenFingerprint(token), ttl+time.Second); err != nil {
+			log.Error().Err(err).Str("workflowID", workflowID).Str("execID", subject).Msg("failed to record the current callback token")
+		}
+	}
+	return token
+}
+
+// Consume checks the signature and expiry, then records the token as used until it expires.
+func (s *DefaultCallbackTokenService) Consume(token string, kind CallbackKind, workflowID, subject string) error {
+	expiry, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return ErrCallbackTokenInvalid
+	}
+	expUnix, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil {
+		return ErrCallbackTokenInvalid
+	}
+	if !hmac.Equal([]byte(sig), []byte(s.sign(kind, workflowID, subject, expiry))) {
+		return ErrCallbackTokenInvalid
+	}
+	remaining := time.Unix(expUnix, 0).Sub(s.now())
+	if remaining <= 0 {
+		return ErrCallbackTokenExpired
+	}
+	// A store that lost the record (a restarted memory store) falls back to the signature alone.
+	if kind == CallbackExec {
+		if current, ok := s.used.Check(currentTokenKey(workflowID, subject)); ok && current != tokenFingerprint(token) {
+			return ErrCallbackTokenSuperseded
+		}
+	}
+
+	// Each attempt writes a fresh value so a store that reports re-inserting its own value as new
+	// still tells the second caller apart.
+	if _, existed := s.used.CheckAndSet(usedTokenKey(token), uuid.NewString(), remaining+time.Second); existed {
+		return ErrCallbackTokenUsed
+	}
+	return nil
+}
+
+// Release forgets that the token was used.
+func (s *DefaultCallbackTokenService) Release(token string) {
+	if err := s.used.Delete(usedTokenKey(token)); err != nil {
+		log.Error().Err(err).Msg("failed to release callback token")
+	}
+}
+
+// usedTokenKey is the idempotency store key a consumed token is recorded under.
+func usedTokenKey(token string) string {
+	return "callback:" + tokenDigest(token)
+}
+
+// currentTokenKey is the idempotency store key the latest token of an exec is recorded under.
+func currentTokenKey(workflowID, execID string) string {
+	return "callback-current:" + workflowID + ":" + execID
+}
+
+func tokenDigest(token string) string {
+	digest := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(digest[:])
+}
+
+// tokenFingerprint identifies a token in a stored value, which holds at most 36 characters.
+func tokenFingerprint(token string) string {
+	return tokenDigest(token)[:32]
+}
+
+func (s *DefaultCallbackTokenService) sign(kind CallbackKind, workflowID, subject, expiry string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(string(kind) + "|" + workflowID + "|" + subject + "|" + expiry))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
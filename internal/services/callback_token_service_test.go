@@ -0,0 +1,109 @@
+package services
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/open-source-cloud/fuse/internal/app/config"
+	"github.com/open-source-cloud/fuse/internal/idempotency"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCallbackTokenService(t *testing.T) *DefaultCallbackTokenService {
+	t.Helper()
+	cfg := &config.Config{Callback: config.CallbackConfig{
+		SigningKey: base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32))),
+		TokenTTL:   time.Hour,
+	}}
+	svc, err := NewCallbackTokenService(cfg, idempotency.NewMemoryStore(t.Context()))
+	require.NoError(t, err)
+	return svc.(*DefaultCallbackTokenService)
+}
+
+func TestCallbackTokenService_ConsumeOnce(t *testing.T) {
+	t.Parallel()
+	svc := newTestCallbackTokenService(t)
+	token := svc.Mint(CallbackExec, "wf-1", "exec-1", 0)
+
+	require.NoError(t, svc.Consume(token, CallbackExec, "wf-1", "exec-1"))
+	assert.ErrorIs(t, svc.Consume(token, CallbackExec, "wf-1", "exec-1"), ErrCallbackTokenUsed)
+}
+
+func TestCallbackTokenService_Release(t *testing.T) {
+	t.Parallel()
+	svc := newTestCallbackTokenService(t)
+	token := svc.Mint(CallbackExec, "wf-1", "exec-1", 0)
+
+	require.NoError(t, svc.Consume(token, CallbackExec, "wf-1", "exec-1"))
+	svc.Release(token)
+	require.NoError(t, svc.Consume(token, CallbackExec, "wf-1", "exec-1"), "a released token can be used again")
+	assert.ErrorIs(t, svc.Consume(token, CallbackExec, "wf-1", "exec-1"), ErrCallbackTokenUsed)
+}
+
+func TestCallbackTokenService_RetrySupersedesEarlierAttempts(t *testing.T) {
+	t.Parallel()
+	svc := newTestCallbackTokenService(t)
+	now := time.Now()
+	svc.now = func() time.Time { return now }
+	first := svc.Mint(CallbackExec, "wf-1", "exec-1", time.Minute)
+	svc.now = func() time.Time { return now.Add(time.Second) }
+	retry := svc.Mint(CallbackExec, "wf-1", "exec-1", time.Minute)
+
+	assert.ErrorIs(t, svc.Consume(first, CallbackExec, "wf-1", "exec-1"), ErrCallbackTokenSuperseded)
+	require.NoError(t, svc.Consume(retry, CallbackExec, "wf-1", "exec-1"))
+
+	other := svc.Mint(CallbackExec, "wf-1", "exec-2", time.Minute)
+	require.NoError(t, svc.Consume(other, CallbackExec, "wf-1", "exec-2"), "attempts are tracked per exec")
+}
+
+func TestCallbackTokenService_RejectsOtherSubjects(t *testing.T) {
+	t.Parallel()
+	svc := newTestCallbackTokenService(t)
+	token := svc.Mint(CallbackExec, "wf-1", "exec-1", 0)
+
+	assert.ErrorIs(t, svc.Consume(token, CallbackExec, "wf-1", "exec-2"), ErrCallbackTokenInvalid)
+	assert.ErrorIs(t, svc.Consume(token, CallbackExec, "wf-2", "exec-1"), ErrCallbackTokenInvalid)
+	assert.ErrorIs(t, svc.Consume(token, CallbackAwakeable, "wf-1", "exec-1"), ErrCallbackTokenInvalid)
+	assert.ErrorIs(t, svc.Consume("", CallbackExec, "wf-1", "exec-1"), ErrCallbackTokenInvalid)
+
+	expiry, _, _ := strings.Cut(token, ".")
+	sig := svc.sign(CallbackExec, "wf-1", "exec-1", strconv.FormatInt(time.Now().Add(2*time.Hour).Unix(), 10))
+	assert.ErrorIs(t, svc.Consume(expiry+"."+sig, CallbackExec, "wf-1", "exec-1"), ErrCallbackTokenInvalid,
+		"the expiry is covered by the signature")
+
+	require.NoError(t, svc.Consume(token, CallbackExec, "wf-1", "exec-1"), "failed attempts do not use up the token")
+}
+
+func TestCallbackTokenService_Expiry(t *testing.T) {
+	t.Parallel()
+	svc := newTestCallbackTokenService(t)
+	now := time.Now()
+	svc.now = func() time.Time { return now }
+	token := svc.Mint(CallbackAwakeable, "wf-1", "awk-1", time.Minute)
+
+	svc.now = func() time.Time { return now.Add(2 * time.Minute) }
+	assert.ErrorIs(t, svc.Consume(token, CallbackAwakeable, "wf-1", "awk-1"), ErrCallbackTokenExpired)
+}
+
+func TestNewCallbackTokenService_SigningKey(t *testing.T) {
+	t.Parallel()
+	store := idempotency.NewMemoryStore(t.Context())
+
+	_, err := NewCallbackTokenService(&config.Config{Callback: config.CallbackConfig{SigningKey: "not base64!"}}, store)
+	require.Error(t, err)
+	short := base64.StdEncoding.EncodeToString([]byte("short"))
+	_, err = NewCallbackTokenService(&config.Config{Callback: config.CallbackConfig{SigningKey: short}}, store)
+	require.Error(t, err)
+
+	a, err := NewCallbackTokenService(&config.Config{Callback: config.CallbackConfig{TokenTTL: time.Hour}}, store)
+	require.NoError(t, err)
+	b, err := NewCallbackTokenService(&config.Config{Callback: config.CallbackConfig{TokenTTL: time.Hour}}, store)
+	require.NoError(t, err)
+	token := a.Mint(CallbackExec, "wf-1", "exec-1", 0)
+	assert.ErrorIs(t, b.Consume(token, CallbackExec, "wf-1", "exec-1"), ErrCallbackTokenInvalid,
+		"generated keys are per process")
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"sort"
+	"strings"
 
+	"github.com/open-source-cloud/fuse/internal/audit"
 	"github.com/open-source-cloud/fuse/internal/repositories"
 	"github.com/open-source-cloud/fuse/pkg/secrets"
 	"github.com/open-source-cloud/fuse/pkg/workflow"
@@ -16,46 +18,51 @@ var ErrReadOnlySecretStore = errors.New("the configured SECRETS_DRIVER is read-o
 
 type (
 	// CredentialService manages credential metadata and their per-environment field values
-	// (ADR-0031 Option B). Metadata lives in the CredentialRepository; values live in the
-	// SecretStore at cred/<id>/<field>. Values are never returned by reads.
+	// (ADR-0031 Option B) within a namespace. Metadata lives in the CredentialRepository; values
+	// live in the SecretStore at cred/<id>/<field>. Values are never returned by reads.
 	CredentialService interface {
-		FindAll() ([]*workflow.Credential, error)
-		FindByID(id string) (*workflow.Credential, error)
-		Save(cred *workflow.Credential, fieldValues map[string]string, environment string) (*workflow.Credential, error)
-		Delete(id string, environment string) error
-		Resolve(ctx context.Context, environment, id, field string) (secrets.SecretValue, error)
+		FindAll(namespace string) ([]*workflow.Credential, error)
+		FindByID(namespace, id string) (*workflow.Credential, error)
+		Save(ctx context.Context, cred *workflow.Credential, fieldValues map[string]string, scope secrets.Scope) (*workflow.Credential, error)
+		Delete(ctx context.Context, id string, scope secrets.Scope) error
+		Resolve(ctx context.Context, scope secrets.Scope, id, field string) (secrets.SecretValue, error)
 	}
 
 	// DefaultCredentialService is the default CredentialService implementation.
 	DefaultCredentialService struct {
 		repo  repositories.CredentialRepository
 		store secrets.SecretStore
+		audit AuditService
 	}
 )
 
 // NewCredentialService returns a new CredentialService.
-func NewCredentialService(repo repositories.CredentialRepository, store secrets.SecretStore) CredentialService {
-	return &DefaultCredentialService{repo: repo, store: store}
+func NewCredentialService(repo repositories.CredentialRepository, store secrets.SecretStore, auditService AuditService) CredentialService {
+	return &DefaultCredentialService{repo: repo, store: store, audit: auditService}
 }
 
-// FindAll returns all credential metadata (never values).
-func (s *DefaultCredentialService) FindAll() ([]*workflow.Credential, error) {
-	return s.repo.FindAll()
+// FindAll returns the credential metadata of a namespace (never values).
+func (s *DefaultCredentialService) FindAll(namespace string) ([]*workflow.Credential, error) {
+	return s.repo.FindAll(namespace)
 }
 
 // FindByID returns a single credential's metadata (never values).
-func (s *DefaultCredentialService) FindByID(id string) (*workflow.Credential, error) {
-	return s.repo.FindByID(id)
+func (s *DefaultCredentialService) FindByID(namespace, id string) (*workflow.Credential, error) {
+	return s.repo.FindByID(namespace, id)
 }
 
-// Save validates and persists the credential metadata, then writes each field value to the
-// SecretStore at cred/<id>/<field> in the given environment. The credential's Fields are the
+// Save validates and persists the credential metadata in the scope's namespace, then writes each
+// field value to the SecretStore at cred/<id>/<field> in the scope. The credential's Fields are the
 // union of any previously-recorded field names and the provided value keys, so metadata tracks
-// every field that has a value (and incremental single-field updates don't drop others).
-func (s *DefaultCredentialService) Save(cred *workflow.Credential, fieldValues map[string]string, environment string) (*workflow.Credential, error) {
+// every field that has a value (and incremental single-field updates don't drop others). The audit
+// entry hashes the metadata only and names the written fields, never their values.
+func (s *DefaultCredentialService) Save(ctx context.Context, cred *workflow.Credential, fieldValues map[string]string, scope secrets.Scope) (*workflow.Credential, error) {
+	namespace := scope.NamespaceOrDefault()
 	existing := make([]string, 0)
-	if prev, err := s.repo.FindByID(cred.ID); err == nil {
+	beforeHash := ""
+	if prev, err := s.repo.FindByID(namespace, cred.ID); err == nil {
 		existing = prev.Fields
+		beforeHash = audit.Hash(prev)
 	}
 	cred.Fields = unionSorted(existing, keys(fieldValues))
 	if err := cred.Validate(); err != nil {
@@ -67,38 +74,69 @@ func (s *DefaultCredentialService) Save(cred *workflow.Credential, fieldValues m
 		return nil, ErrReadOnlySecretStore
 	}
 
-	if err := s.repo.Save(cred); err != nil {
+	if err := s.repo.Save(namespace, cred); err != nil {
 		return nil, err
 	}
 
 	for field, value := range fieldValues {
-		if err := managed.Set(context.Background(), secrets.Scope{Environment: environment}, secrets.CredentialSecretName(cred.ID, field), value); err != nil {
+		if err := managed.Set(ctx, scope, secrets.CredentialSecretName(cred.ID, field), value); err != nil {
 			return nil, err
 		}
 	}
+
+	entry := credentialAuditEntry(audit.ActionCredentialSave, cred.ID, scope)
+	entry.BeforeHash, entry.AfterHash = beforeHash, audit.Hash(cred)
+	entry.Details = map[string]string{"type": cred.Type, "fields": strings.Join(sortedKeys(fieldValues), ",")}
+	recordAudit(ctx, s.audit, entry)
 	return cred, nil
 }
 
-// Delete removes the credential's field secrets in the given environment, then its metadata.
-func (s *DefaultCredentialService) Delete(id string, environment string) error {
-	cred, err := s.repo.FindByID(id)
+// Delete removes the credential's field secrets in the scope, then its metadata.
+func (s *DefaultCredentialService) Delete(ctx context.Context, id string, scope secrets.Scope) error {
+	namespace := scope.NamespaceOrDefault()
+	cred, err := s.repo.FindByID(namespace, id)
 	if err != nil {
 		return err
 	}
 
 	if managed, ok := s.store.(secrets.ManagedSecretStore); ok {
 		for _, field := range cred.Fields {
-			if delErr := managed.Delete(context.Background(), secrets.Scope{Environment: environment}, secrets.CredentialSecretName(id, field)); delErr != nil {
+			if delErr := managed.Delete(ctx, scope, secrets.CredentialSecretName(id, field)); delErr != nil {
 				return delErr
 			}
 		}
 	}
-	return s.repo.Delete(id)
+	if err := s.repo.Delete(namespace, id); err != nil {
+		return err
+	}
+
+	entry := credentialAuditEntry(audit.ActionCredentialDelete, id, scope)
+	entry.BeforeHash = audit.Hash(cred)
+	recordAudit(ctx, s.audit, entry)
+	return nil
 }
 
-// Resolve returns a credential field's value for an environment as a redacted SecretValue.
-func (s *DefaultCredentialService) Resolve(ctx context.Context, environment, id, field string) (secrets.SecretValue, error) {
-	return s.store.Resolve(ctx, secrets.Scope{Environment: environment}, secrets.CredentialSecretName(id, field))
+// credentialAuditEntry starts the audit entry of a change to a credential in scope.
+func credentialAuditEntry(action audit.Action, id string, scope secrets.Scope) audit.Entry {
+	return audit.Entry{
+		Action:       action,
+		ResourceType: audit.ResourceCredential,
+		Resource:     id,
+		Namespace:    scope.NamespaceOrDefault(),
+		Environment:  scope.Environment,
+	}
+}
+
+// Resolve returns a credential field's value in a scope as a redacted SecretValue.
+func (s *DefaultCredentialService) Resolve(ctx context.Context, scope secrets.Scope, id, field string) (secrets.SecretValue, error) {
+	return s.store.Resolve(ctx, scope, secrets.CredentialSecretName(id, field))
+}
+
+// sortedKeys returns the keys of m in order.
+func sortedKeys(m map[string]string) []string {
+	out := keys(m)
+	sort.Strings(out)
+	return out
 }
 
 func keys(m map[string]string) []string {
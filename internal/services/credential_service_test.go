@@ -20,7 +20,7 @@ func (readOnlyStore) Resolve(_ context.Context, _ secrets.Scope, _ string) (secr
 
 func newCredentialService() (CredentialService, secrets.SecretStore) {
 	store := secrets.NewMemorySecretStore()
-	return NewCredentialService(repositories.NewMemoryCredentialRepository(), store), store
+	return NewCredentialService(repositories.NewMemoryCredentialRepository(), store, nil), store
 }
 
 func TestCredentialService_SaveWritesValuesToSecretStore(t *testing.T) {
@@ -28,8 +28,8 @@ func TestCredentialService_SaveWritesValuesToSecretStore(t *testing.T) {
 	ctx := context.Background()
 	svc, store := newCredentialService()
 
-	_, err := svc.Save(workflow.NewCredential("openai-prod", "openai", "Prod", nil),
-		map[string]string{"apiKey": "sk-staging"}, "staging")
+	_, err := svc.Save(ctx, workflow.NewCredential("openai-prod", "openai", "Prod", nil),
+		map[string]string{"apiKey": "sk-staging"}, secrets.Scope{Environment: "staging"})
 	require.NoError(t, err)
 
 	// Value is stored at the reserved name in the right environment.
@@ -42,7 +42,7 @@ func TestCredentialService_SaveWritesValuesToSecretStore(t *testing.T) {
 	assert.ErrorIs(t, err, secrets.ErrSecretNotFound)
 
 	// Metadata records the field name; never the value.
-	cred, err := svc.FindByID("openai-prod")
+	cred, err := svc.FindByID(workflow.DefaultNamespaceName, "openai-prod")
 	require.NoError(t, err)
 	assert.Equal(t, []string{"apiKey"}, cred.Fields)
 }
@@ -51,21 +51,21 @@ func TestCredentialService_SaveMergesFields(t *testing.T) {
 	t.Parallel()
 	svc, _ := newCredentialService()
 
-	_, err := svc.Save(workflow.NewCredential("c1", "custom", "", nil), map[string]string{"apiKey": "a"}, "default")
+	_, err := svc.Save(context.Background(), workflow.NewCredential("c1", "custom", "", nil), map[string]string{"apiKey": "a"}, secrets.Scope{Environment: "default"})
 	require.NoError(t, err)
-	_, err = svc.Save(workflow.NewCredential("c1", "custom", "", nil), map[string]string{"baseUrl": "b"}, "default")
+	_, err = svc.Save(context.Background(), workflow.NewCredential("c1", "custom", "", nil), map[string]string{"baseUrl": "b"}, secrets.Scope{Environment: "default"})
 	require.NoError(t, err)
 
-	cred, err := svc.FindByID("c1")
+	cred, err := svc.FindByID(workflow.DefaultNamespaceName, "c1")
 	require.NoError(t, err)
 	assert.Equal(t, []string{"apiKey", "baseUrl"}, cred.Fields)
 }
 
 func TestCredentialService_SaveReadOnlyStoreErrors(t *testing.T) {
 	t.Parallel()
-	svc := NewCredentialService(repositories.NewMemoryCredentialRepository(), readOnlyStore{})
+	svc := NewCredentialService(repositories.NewMemoryCredentialRepository(), readOnlyStore{}, nil)
 
-	_, err := svc.Save(workflow.NewCredential("c1", "custom", "", nil), map[string]string{"k": "v"}, "default")
+	_, err := svc.Save(context.Background(), workflow.NewCredential("c1", "custom", "", nil), map[string]string{"k": "v"}, secrets.Scope{Environment: "default"})
 	assert.ErrorIs(t, err, ErrReadOnlySecretStore)
 }
 
@@ -73,12 +73,12 @@ func TestCredentialService_DeleteRemovesValues(t *testing.T) {
 	t.Parallel()
 	ctx := context.Background()
 	svc, store := newCredentialService()
-	_, err := svc.Save(workflow.NewCredential("c1", "custom", "", nil), map[string]string{"apiKey": "a"}, "staging")
+	_, err := svc.Save(ctx, workflow.NewCredential("c1", "custom", "", nil), map[string]string{"apiKey": "a"}, secrets.Scope{Environment: "staging"})
 	require.NoError(t, err)
 
-	require.NoError(t, svc.Delete("c1", "staging"))
+	require.NoError(t, svc.Delete(ctx, "c1", secrets.Scope{Environment: "staging"}))
 
-	_, err = svc.FindByID("c1")
+	_, err = svc.FindByID(workflow.DefaultNamespaceName, "c1")
 	assert.ErrorIs(t, err, repositories.ErrCredentialNotFound)
 	_, err = store.Resolve(ctx, secrets.Scope{Environment: "staging"}, secrets.CredentialSecretName("c1", "apiKey"))
 	assert.ErrorIs(t, err, secrets.ErrSecretNotFound)
@@ -87,10 +87,28 @@ func TestCredentialService_DeleteRemovesValues(t *testing.T) {
 func TestCredentialService_Resolve(t *testing.T) {
 	t.Parallel()
 	svc, _ := newCredentialService()
-	_, err := svc.Save(workflow.NewCredential("c1", "custom", "", nil), map[string]string{"apiKey": "a"}, "default")
+	_, err := svc.Save(context.Background(), workflow.NewCredential("c1", "custom", "", nil), map[string]string{"apiKey": "a"}, secrets.Scope{Environment: "default"})
 	require.NoError(t, err)
 
-	v, err := svc.Resolve(context.Background(), "default", "c1", "apiKey")
+	v, err := svc.Resolve(context.Background(), secrets.Scope{Environment: "default"}, "c1", "apiKey")
 	require.NoError(t, err)
 	assert.Equal(t, "a", v.Reveal())
 }
+
+func TestCredentialService_NamespaceIsolation(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	svc, store := newCredentialService()
+	billing := secrets.Scope{Namespace: "billing", Environment: "prod"}
+	_, err := svc.Save(ctx, workflow.NewCredential("stripe", "custom", "", nil), map[string]string{"apiKey": "sk"}, billing)
+	require.NoError(t, err)
+
+	_, err = svc.FindByID(workflow.DefaultNamespaceName, "stripe")
+	assert.ErrorIs(t, err, repositories.ErrCredentialNotFound)
+	_, err = store.Resolve(ctx, secrets.Scope{Environment: "prod"}, secrets.CredentialSecretName("stripe", "apiKey"))
+	assert.ErrorIs(t, err, secrets.ErrSecretNotFound)
+
+	v, err := svc.Resolve(ctx, billing, "stripe", "apiKey")
+	require.NoError(t, err)
+	assert.Equal(t, "sk", v.Reveal())
+}
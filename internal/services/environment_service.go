@@ -1,6 +1,9 @@
 package services
 
 import (
+	"context"
+
+	"github.com/open-source-cloud/fuse/internal/audit"
 	"github.com/open-source-cloud/fuse/internal/repositories"
 	"github.com/open-source-cloud/fuse/pkg/workflow"
 )
@@ -11,8 +14,8 @@ type (
 	EnvironmentService interface {
 		FindAll() ([]*workflow.Environment, error)
 		FindByID(name string) (*workflow.Environment, error)
-		Save(env *workflow.Environment) (*workflow.Environment, error)
-		Delete(name string) error
+		Save(ctx context.Context, env *workflow.Environment) (*workflow.Environment, error)
+		Delete(ctx context.Context, name string) error
 		// IsValid reports whether name is a declared environment. The default environment is
 		// always valid even if the registry has not been seeded.
 		IsValid(name string) bool
@@ -20,13 +23,14 @@ type (
 
 	// DefaultEnvironmentService is the default EnvironmentService implementation.
 	DefaultEnvironmentService struct {
-		repo repositories.EnvironmentRepository
+		repo  repositories.EnvironmentRepository
+		audit AuditService
 	}
 )
 
 // NewEnvironmentService returns a new EnvironmentService.
-func NewEnvironmentService(repo repositories.EnvironmentRepository) EnvironmentService {
-	return &DefaultEnvironmentService{repo: repo}
+func NewEnvironmentService(repo repositories.EnvironmentRepository, auditService AuditService) EnvironmentService {
+	return &DefaultEnvironmentService{repo: repo, audit: auditService}
 }
 
 // FindAll returns all declared environments.
@@ -40,19 +44,45 @@ func (s *DefaultEnvironmentService) FindByID(name string) (*workflow.Environment
 }
 
 // Save validates and upserts an environment.
-func (s *DefaultEnvironmentService) Save(env *workflow.Environment) (*workflow.Environment, error) {
+func (s *DefaultEnvironmentService) Save(ctx context.Context, env *workflow.Environment) (*workflow.Environment, error) {
 	if err := env.Validate(); err != nil {
 		return nil, err
 	}
+	beforeHash := ""
+	if prev, err := s.repo.FindByID(env.Name); err == nil {
+		beforeHash = audit.Hash(prev)
+	}
 	if err := s.repo.Save(env); err != nil {
 		return nil, err
 	}
+	recordAudit(ctx, s.audit, audit.Entry{
+		Action:       audit.ActionEnvironmentSave,
+		ResourceType: audit.ResourceEnvironment,
+		Resource:     env.Name,
+		Environment:  env.Name,
+		BeforeHash:   beforeHash,
+		AfterHash:    audit.Hash(env),
+	})
 	return env, nil
 }
 
 // Delete removes an environment by name.
-func (s *DefaultEnvironmentService) Delete(name string) error {
-	return s.repo.Delete(name)
+func (s *DefaultEnvironmentService) Delete(ctx context.Context, name string) error {
+	beforeHash := ""
+	if prev, err := s.repo.FindByID(name); err == nil {
+		beforeHash = audit.Hash(prev)
+	}
+	if err := s.repo.Delete(name); err != nil {
+		return err
+	}
+	recordAudit(ctx, s.audit, audit.Entry{
+		Action:       audit.ActionEnvironmentDelete,
+		ResourceType: audit.ResourceEnvironment,
+		Resource:     name,
+		Environment:  name,
+		BeforeHash:   beforeHash,
+	})
+	return nil
 }
 
 // IsValid reports whether name is a declared environment (the default is always valid).
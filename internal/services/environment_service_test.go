@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"testing"
 
 	"github.com/open-source-cloud/fuse/internal/repositories"
@@ -10,14 +11,14 @@ import (
 )
 
 func newEnvironmentService() EnvironmentService {
-	return NewEnvironmentService(repositories.NewMemoryEnvironmentRepository())
+	return NewEnvironmentService(repositories.NewMemoryEnvironmentRepository(), nil)
 }
 
 func TestEnvironmentService_IsValid(t *testing.T) {
 	t.Parallel()
 
 	svc := newEnvironmentService()
-	_, err := svc.Save(workflow.NewEnvironment("staging", "Staging"))
+	_, err := svc.Save(context.Background(), workflow.NewEnvironment("staging", "Staging"))
 	require.NoError(t, err)
 
 	tests := []struct {
@@ -43,7 +44,7 @@ func TestEnvironmentService_SaveRejectsInvalidName(t *testing.T) {
 
 	svc := newEnvironmentService()
 
-	_, err := svc.Save(workflow.NewEnvironment("Invalid Name", ""))
+	_, err := svc.Save(context.Background(), workflow.NewEnvironment("Invalid Name", ""))
 
 	require.Error(t, err)
 }
@@ -53,7 +54,7 @@ func TestEnvironmentService_CRUDRoundTrip(t *testing.T) {
 
 	svc := newEnvironmentService()
 
-	saved, err := svc.Save(workflow.NewEnvironment("prod", "Production"))
+	saved, err := svc.Save(context.Background(), workflow.NewEnvironment("prod", "Production"))
 	require.NoError(t, err)
 	assert.Equal(t, "prod", saved.Name)
 
@@ -61,6 +62,6 @@ func TestEnvironmentService_CRUDRoundTrip(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "Production", found.Description)
 
-	require.NoError(t, svc.Delete("prod"))
+	require.NoError(t, svc.Delete(context.Background(), "prod"))
 	assert.False(t, svc.IsValid("prod"))
 }
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+
+	"github.com/open-source-cloud/fuse/internal/audit"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+)
+
+type (
+	// EnvironmentVarService manages the non-secret variables of a namespace's environments. An
+	// execution pins the variables of its environment when it is triggered.
+	EnvironmentVarService interface {
+		FindAll(namespace, environment string) (map[string]string, error)
+		// Set validates and upserts a variable of a declared environment; an undeclared one fails
+		// with repositories.ErrEnvironmentNotFound.
+		Set(ctx context.Context, namespace, environment, name, value string) error
+		Delete(ctx context.Context, namespace, environment, name string) error
+	}
+
+	// DefaultEnvironmentVarService is the default EnvironmentVarService implementation.
+	DefaultEnvironmentVarService struct {
+		repo         repositories.EnvironmentVarRepository
+		environments EnvironmentService
+		audit        AuditService
+	}
+)
+
+// NewEnvironmentVarService returns a new EnvironmentVarService.
+func NewEnvironmentVarService(repo repositories.EnvironmentVarRepository, environments EnvironmentService, auditService AuditService) EnvironmentVarService {
+	return &DefaultEnvironmentVarService{repo: repo, environments: environments, audit: auditService}
+}
+
+// FindAll returns the variables of a namespace's environment.
+func (s *DefaultEnvironmentVarService) FindAll(namespace, environment string) (map[string]string, error) {
+	return s.repo.FindAll(namespace, environment)
+}
+
+// Set validates and upserts a variable.
+func (s *DefaultEnvironmentVarService) Set(ctx context.Context, namespace, environment, name, value string) error {
+	if !s.environments.IsValid(environment) {
+		return repositories.ErrEnvironmentNotFound
+	}
+	if err := workflow.ValidateEnvVar(name, value); err != nil {
+		return err
+	}
+	beforeHash := s.varHash(namespace, environment, name)
+	if err := s.repo.Set(namespace, environment, name, value); err != nil {
+		return err
+	}
+	entry := varAuditEntry(audit.ActionVarSet, namespace, environment, name)
+	entry.BeforeHash, entry.AfterHash = beforeHash, audit.Hash(value)
+	recordAudit(ctx, s.audit, entry)
+	return nil
+}
+
+// Delete removes a variable.
+func (s *DefaultEnvironmentVarService) Delete(ctx context.Context, namespace, environment, name string) error {
+	beforeHash := s.varHash(namespace, environment, name)
+	if err := s.repo.Delete(namespace, environment, name); err != nil {
+		return err
+	}
+	entry := varAuditEntry(audit.ActionVarDelete, namespace, environment, name)
+	entry.BeforeHash = beforeHash
+	recordAudit(ctx, s.audit, entry)
+	return nil
+}
+
+// varHash returns the audit hash of a variable's current value, "" when it is not set.
+func (s *DefaultEnvironmentVarService) varHash(namespace, environment, name string) string {
+	vars, err := s.repo.FindAll(namespace, environment)
+	if err != nil {
+		return ""
+	}
+	value, ok := vars[name]
+	if !ok {
+		return ""
+	}
+	return audit.Hash(value)
+}
+
+func varAuditEntry(action audit.Action, namespace, environment, name string) audit.Entry {
+	return audit.Entry{
+		Action:       action,
+		ResourceType: audit.ResourceVar,
+		Resource:     name,
+		Namespace:    namespace,
+		Environment:  environment,
+	}
+}
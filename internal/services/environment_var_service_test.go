@@ -0,0 +1,35 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvironmentVarService_Set(t *testing.T) {
+	t.Parallel()
+	envs := newEnvironmentService()
+	_, err := envs.Save(context.Background(), workflow.NewEnvironment("staging", ""))
+	require.NoError(t, err)
+	svc := NewEnvironmentVarService(repositories.NewMemoryEnvironmentVarRepository(), envs, nil)
+	ns := workflow.DefaultNamespaceName
+
+	require.NoError(t, svc.Set(context.Background(), ns, "staging", "API_BASE_URL", "https://staging.example.com"))
+	require.NoError(t, svc.Set(context.Background(), ns, workflow.DefaultEnvironmentName, "API_BASE_URL", "https://api.example.com"))
+
+	vars, err := svc.FindAll(ns, "staging")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"API_BASE_URL": "https://staging.example.com"}, vars)
+
+	assert.ErrorIs(t, svc.Set(context.Background(), ns, "bogus", "API_BASE_URL", "x"), repositories.ErrEnvironmentNotFound)
+	assert.Error(t, svc.Set(context.Background(), ns, "staging", "bad name", "x"))
+
+	require.NoError(t, svc.Delete(context.Background(), ns, "staging", "API_BASE_URL"))
+	vars, err = svc.FindAll(ns, "staging")
+	require.NoError(t, err)
+	assert.Empty(t, vars)
+}
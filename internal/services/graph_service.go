@@ -2,14 +2,18 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/open-source-cloud/fuse/internal/audit"
 	"github.com/open-source-cloud/fuse/internal/packages"
 	"github.com/open-source-cloud/fuse/internal/repositories"
 	"github.com/open-source-cloud/fuse/internal/workflow"
+	pkgworkflow "github.com/open-source-cloud/fuse/pkg/workflow"
 	"github.com/rs/zerolog/log"
 )
 
@@ -26,11 +30,11 @@ type (
 		// GetVersionHistory returns aggregate version metadata for a schema.
 		GetVersionHistory(schemaID string) (*workflow.SchemaVersionHistory, error)
 		// Upsert creates or updates a schema, always creating a new version.
-		Upsert(schemaID string, schema *workflow.GraphSchema) (*workflow.Graph, error)
+		Upsert(ctx context.Context, schemaID string, schema *workflow.GraphSchema) (*workflow.Graph, error)
 		// SetActiveVersion activates a specific existing version of a schema.
-		SetActiveVersion(schemaID string, version int) error
+		SetActiveVersion(ctx context.Context, schemaID string, version int) error
 		// Rollback creates a new version with the content of an older version and activates it.
-		Rollback(schemaID string, toVersion int, comment string) (*workflow.SchemaVersion, error)
+		Rollback(ctx context.Context, schemaID string, toVersion int, comment string) (*workflow.SchemaVersion, error)
 		// ApplyReplicatedUpsert applies a schema from a peer cluster event (does not republish).
 		ApplyReplicatedUpsert(schemaID string, schemaJSON []byte) error
 		// EnsureNodeMetadata populates function metadata on graph nodes if not already present.
@@ -42,6 +46,7 @@ type (
 		graphRepo       repositories.GraphRepository
 		packageRegistry packages.Registry
 		publisher       SchemaUpsertPublisher
+		audit           AuditService
 	}
 )
 
@@ -50,11 +55,13 @@ func NewGraphService(
 	graphRepo repositories.GraphRepository,
 	packageRegistry packages.Registry,
 	publisher SchemaUpsertPublisher,
+	auditService AuditService,
 ) GraphService {
 	return &DefaultGraphService{
 		graphRepo:       graphRepo,
 		packageRegistry: packageRegistry,
 		publisher:       publisher,
+		audit:           auditService,
 	}
 }
 
@@ -108,20 +115,28 @@ func (gs *DefaultGraphService) GetVersionHistory(schemaID string) (*workflow.Sch
 }
 
 // Upsert upserts a workflow.GraphSchema into the database, creating a new version on each call.
-func (gs *DefaultGraphService) Upsert(schemaID string, schema *workflow.GraphSchema) (*workflow.Graph, error) {
+func (gs *DefaultGraphService) Upsert(ctx context.Context, schemaID string, schema *workflow.GraphSchema) (*workflow.Graph, error) {
+	id := schemaID
+	if id == "" {
+		id = schema.ID
+	}
+	beforeHash := gs.activeSchemaHash(id)
 	g, err := gs.upsertGraph(schemaID, schema)
 	if err != nil {
 		return nil, err
 	}
+	pubSchema := g.Schema()
 	if gs.publisher != nil {
-		pubSchema := g.Schema()
 		gs.publisher.PublishLocalUpsert(pubSchema.ID, &pubSchema)
 	}
+	entry := schemaAuditEntry(audit.ActionSchemaUpsert, g.ID())
+	entry.BeforeHash, entry.AfterHash = beforeHash, audit.Hash(pubSchema)
+	recordAudit(ctx, gs.audit, entry)
 	return g, nil
 }
 
 // SetActiveVersion activates a specific existing version of a schema.
-func (gs *DefaultGraphService) SetActiveVersion(schemaID string, version int) error {
+func (gs *DefaultGraphService) SetActiveVersion(ctx context.Context, schemaID string, version int) error {
 	_, err := gs.graphRepo.FindByIDAndVersion(schemaID, version)
 	if err != nil {
 		if errors.Is(err, repositories.ErrSchemaVersionNotFound) {
@@ -129,11 +144,51 @@ func (gs *DefaultGraphService) SetActiveVersion(schemaID string, version int) er
 		}
 		return err
 	}
-	return gs.graphRepo.SetActiveVersion(schemaID, version)
+	beforeHash := gs.activeSchemaHash(schemaID)
+	if err := gs.graphRepo.SetActiveVersion(schemaID, version); err != nil {
+		return err
+	}
+	entry := schemaAuditEntry(audit.ActionSchemaActivate, schemaID)
+	entry.BeforeHash, entry.AfterHash = beforeHash, gs.activeSchemaHash(schemaID)
+	entry.Details = map[string]string{"version": strconv.Itoa(version)}
+	recordAudit(ctx, gs.audit, entry)
+	return nil
 }
 
 // Rollback creates a new version with the content of an older version and activates it.
-func (gs *DefaultGraphService) Rollback(schemaID string, toVersion int, comment string) (*workflow.SchemaVersion, error) {
+func (gs *DefaultGraphService) Rollback(ctx context.Context, schemaID string, toVersion int, comment string) (*workflow.SchemaVersion, error) {
+	beforeHash := gs.activeSchemaHash(schemaID)
+	sv, err := gs.rollback(schemaID, toVersion, comment)
+	if err != nil {
+		return nil, err
+	}
+	entry := schemaAuditEntry(audit.ActionSchemaRollback, schemaID)
+	entry.BeforeHash, entry.AfterHash = beforeHash, audit.Hash(sv.Schema)
+	entry.Details = map[string]string{"restoredFrom": strconv.Itoa(toVersion), "version": strconv.Itoa(sv.Version)}
+	recordAudit(ctx, gs.audit, entry)
+	return sv, nil
+}
+
+// activeSchemaHash returns the audit hash of a schema's active definition, "" when it does not
+// exist yet.
+func (gs *DefaultGraphService) activeSchemaHash(schemaID string) string {
+	if schemaID == "" {
+		return ""
+	}
+	graph, err := gs.graphRepo.FindByID(schemaID)
+	if err != nil || graph == nil {
+		return ""
+	}
+	return audit.Hash(graph.Schema())
+}
+
+// schemaAuditEntry starts the audit entry of a change to a namespace-qualified schema.
+func schemaAuditEntry(action audit.Action, schemaID string) audit.Entry {
+	namespace, localID := pkgworkflow.SplitQualifiedID(schemaID)
+	return audit.Entry{Action: action, ResourceType: audit.ResourceSchema, Resource: localID, Namespace: namespace}
+}
+
+func (gs *DefaultGraphService) rollback(schemaID string, toVersion int, comment string) (*workflow.SchemaVersion, error) {
 	oldGraph, err := gs.graphRepo.FindByIDAndVersion(schemaID, toVersion)
 	if err != nil {
 		if errors.Is(err, repositories.ErrSchemaVersionNotFound) {
@@ -195,6 +250,9 @@ func (gs *DefaultGraphService) upsertGraph(schemaID string, schema *workflow.Gra
 	if schema.ID == "" {
 		return nil, errors.New("graph schema id is required")
 	}
+	if err := pkgworkflow.ValidateQualifiedID(schema.ID); err != nil {
+		return nil, err
+	}
 
 	graph, err := gs.graphRepo.FindByID(schema.ID)
 	if err != nil {
@@ -321,6 +379,12 @@ func (gs *DefaultGraphService) populateNodeMetadata(graph *workflow.Graph, nodes
 			log.Error().Msgf("invalid function format '%s': must contain '/' to separate package and function", node.Function)
 			return workflow.ErrInvalidFunctionFormat
 		}
+		// schemas only see their own namespace's packages and the shared default-namespace ones
+		if pkgNamespace := pkgworkflow.NamespaceOf(pkgID); pkgNamespace != pkgworkflow.DefaultNamespaceName &&
+			pkgNamespace != pkgworkflow.NamespaceOf(graph.ID()) {
+			log.Error().Msgf("package %s is not visible from the namespace of graph %s", pkgID, graph.ID())
+			return fmt.Errorf("%w: package %s is not visible from graph %s", packages.ErrLoadedPackageNotFound, pkgID, graph.ID())
+		}
 		pkg, err := gs.packageRegistry.Get(pkgID)
 		if err != nil {
 			log.Error().Err(err).Msgf("failed to get package %s metadata for node %s", pkgID, node.ID)
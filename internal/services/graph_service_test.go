@@ -1,6 +1,7 @@
 package services_test
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 
@@ -23,6 +24,8 @@ func (r *recordingSchemaPublisher) PublishLocalUpsert(_ string, _ *workflow.Grap
 	r.upserts++
 }
 
+func (r *recordingSchemaPublisher) PublishLocalLifecycle(string, workflow.SchemaLifecycle, bool) {}
+
 func (r *recordingSchemaPublisher) BindNode(gen.Node) {
 	// Required by services.GraphSchemaPublisher; unused in this test recorder.
 }
@@ -33,18 +36,18 @@ func TestGraphService(t *testing.T) {
 
 	pkgRepo := repositories.NewMemoryPackageRepository()
 	pkgRegistry := packages.NewPackageRegistry()
-	internalPackages := packages.NewInternal(llm.NewRegistry(nil, ""), pkgRegistry, nil)
+	internalPackages := packages.NewInternal(packages.InternalDeps{Providers: llm.NewRegistry(nil, ""), Registry: pkgRegistry})
 
-	pkgSvc := services.NewPackageService(pkgRepo, pkgRegistry, internalPackages)
+	pkgSvc := services.NewPackageService(pkgRepo, pkgRegistry, internalPackages, nil)
 	if err := pkgSvc.RegisterInternalPackages(); err != nil {
 		t.Fatalf("failed to register internal packages: %v", err)
 	}
 
-	graphService := services.NewGraphService(memGraphRepo, pkgRegistry, nil)
+	graphService := services.NewGraphService(memGraphRepo, pkgRegistry, nil, nil)
 
 	schema := mocks.SmallTestGraphSchema()
 
-	graph, err := graphService.Upsert(schema.ID, schema)
+	graph, err := graphService.Upsert(context.Background(), schema.ID, schema)
 	if err != nil {
 		t.Fatalf("failed to upsert graph: %v", err)
 	}
@@ -66,16 +69,16 @@ func TestGraphService(t *testing.T) {
 func TestGraphService_ListSchemas(t *testing.T) {
 	memGraphRepo := repositories.NewMemoryGraphRepository()
 	pkgRegistry := packages.NewPackageRegistry()
-	internalPackages := packages.NewInternal(llm.NewRegistry(nil, ""), pkgRegistry, nil)
-	pkgSvc := services.NewPackageService(repositories.NewMemoryPackageRepository(), pkgRegistry, internalPackages)
+	internalPackages := packages.NewInternal(packages.InternalDeps{Providers: llm.NewRegistry(nil, ""), Registry: pkgRegistry})
+	pkgSvc := services.NewPackageService(repositories.NewMemoryPackageRepository(), pkgRegistry, internalPackages, nil)
 	if err := pkgSvc.RegisterInternalPackages(); err != nil {
 		t.Fatalf("failed to register internal packages: %v", err)
 	}
 
-	graphService := services.NewGraphService(memGraphRepo, pkgRegistry, nil)
+	graphService := services.NewGraphService(memGraphRepo, pkgRegistry, nil, nil)
 
 	schema := mocks.SmallTestGraphSchema()
-	_, err := graphService.Upsert(schema.ID, schema)
+	_, err := graphService.Upsert(context.Background(), schema.ID, schema)
 	if err != nil {
 		t.Fatalf("failed to upsert graph: %v", err)
 	}
@@ -98,15 +101,15 @@ func TestGraphService_ListSchemas(t *testing.T) {
 func TestGraphService_Upsert_invokesPublisher(t *testing.T) {
 	memGraphRepo := repositories.NewMemoryGraphRepository()
 	pkgRegistry := packages.NewPackageRegistry()
-	internalPackages := packages.NewInternal(llm.NewRegistry(nil, ""), pkgRegistry, nil)
-	pkgSvc := services.NewPackageService(repositories.NewMemoryPackageRepository(), pkgRegistry, internalPackages)
+	internalPackages := packages.NewInternal(packages.InternalDeps{Providers: llm.NewRegistry(nil, ""), Registry: pkgRegistry})
+	pkgSvc := services.NewPackageService(repositories.NewMemoryPackageRepository(), pkgRegistry, internalPackages, nil)
 	require.NoError(t, pkgSvc.RegisterInternalPackages())
 
 	pub := &recordingSchemaPublisher{}
-	graphService := services.NewGraphService(memGraphRepo, pkgRegistry, pub)
+	graphService := services.NewGraphService(memGraphRepo, pkgRegistry, pub, nil)
 
 	schema := mocks.SmallTestGraphSchema()
-	_, err := graphService.Upsert(schema.ID, schema)
+	_, err := graphService.Upsert(context.Background(), schema.ID, schema)
 	require.NoError(t, err)
 	require.Equal(t, 1, pub.upserts)
 }
@@ -114,17 +117,17 @@ func TestGraphService_Upsert_invokesPublisher(t *testing.T) {
 func TestGraphService_Upsert_pathSchemaIDOverridesBodyID(t *testing.T) {
 	memGraphRepo := repositories.NewMemoryGraphRepository()
 	pkgRegistry := packages.NewPackageRegistry()
-	internalPackages := packages.NewInternal(llm.NewRegistry(nil, ""), pkgRegistry, nil)
-	pkgSvc := services.NewPackageService(repositories.NewMemoryPackageRepository(), pkgRegistry, internalPackages)
+	internalPackages := packages.NewInternal(packages.InternalDeps{Providers: llm.NewRegistry(nil, ""), Registry: pkgRegistry})
+	pkgSvc := services.NewPackageService(repositories.NewMemoryPackageRepository(), pkgRegistry, internalPackages, nil)
 	require.NoError(t, pkgSvc.RegisterInternalPackages())
 
-	graphService := services.NewGraphService(memGraphRepo, pkgRegistry, nil)
+	graphService := services.NewGraphService(memGraphRepo, pkgRegistry, nil, nil)
 
 	schema := mocks.SmallTestGraphSchema()
 	schema.ID = "body-json-id"
 
 	const apiID = "api-path-schema-id"
-	_, err := graphService.Upsert(apiID, schema)
+	_, err := graphService.Upsert(context.Background(), apiID, schema)
 	require.NoError(t, err)
 
 	g, err := graphService.FindByID(apiID)
@@ -139,12 +142,12 @@ func TestGraphService_Upsert_pathSchemaIDOverridesBodyID(t *testing.T) {
 func TestGraphService_ApplyReplicatedUpsert(t *testing.T) {
 	memGraphRepo := repositories.NewMemoryGraphRepository()
 	pkgRegistry := packages.NewPackageRegistry()
-	internalPackages := packages.NewInternal(llm.NewRegistry(nil, ""), pkgRegistry, nil)
-	pkgSvc := services.NewPackageService(repositories.NewMemoryPackageRepository(), pkgRegistry, internalPackages)
+	internalPackages := packages.NewInternal(packages.InternalDeps{Providers: llm.NewRegistry(nil, ""), Registry: pkgRegistry})
+	pkgSvc := services.NewPackageService(repositories.NewMemoryPackageRepository(), pkgRegistry, internalPackages, nil)
 	require.NoError(t, pkgSvc.RegisterInternalPackages())
 
 	pub := &recordingSchemaPublisher{}
-	graphService := services.NewGraphService(memGraphRepo, pkgRegistry, pub)
+	graphService := services.NewGraphService(memGraphRepo, pkgRegistry, pub, nil)
 
 	schema := mocks.SmallTestGraphSchema()
 	payload, err := json.Marshal(schema)
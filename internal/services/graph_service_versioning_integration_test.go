@@ -4,6 +4,7 @@ package services_test
 // These tests exercise the service layer end-to-end using in-memory repositories.
 
 import (
+	"context"
 	"testing"
 
 	"github.com/open-source-cloud/fuse/internal/mocks"
@@ -20,10 +21,10 @@ func setupVersioningService(t *testing.T) services.GraphService {
 	t.Helper()
 	repo := repositories.NewMemoryGraphRepository()
 	pkgRegistry := packages.NewPackageRegistry()
-	internalPkgs := packages.NewInternal(llm.NewRegistry(nil, ""), pkgRegistry, nil)
-	pkgSvc := services.NewPackageService(repositories.NewMemoryPackageRepository(), pkgRegistry, internalPkgs)
+	internalPkgs := packages.NewInternal(packages.InternalDeps{Providers: llm.NewRegistry(nil, ""), Registry: pkgRegistry})
+	pkgSvc := services.NewPackageService(repositories.NewMemoryPackageRepository(), pkgRegistry, internalPkgs, nil)
 	require.NoError(t, pkgSvc.RegisterInternalPackages())
-	return services.NewGraphService(repo, pkgRegistry, nil)
+	return services.NewGraphService(repo, pkgRegistry, nil, nil)
 }
 
 // TestVersioning_FullLifecycle exercises create → update → rollback.
@@ -33,7 +34,7 @@ func TestVersioning_FullLifecycle(t *testing.T) {
 	schemaID := schema.ID
 
 	// Step 1: Create schema → version 1
-	_, err := svc.Upsert(schemaID, schema)
+	_, err := svc.Upsert(context.Background(), schemaID, schema)
 	require.NoError(t, err)
 
 	v, err := svc.ListVersions(schemaID)
@@ -44,7 +45,7 @@ func TestVersioning_FullLifecycle(t *testing.T) {
 
 	// Step 2: Update schema → version 2
 	schema.Name = "Version 2"
-	_, err = svc.Upsert(schemaID, schema)
+	_, err = svc.Upsert(context.Background(), schemaID, schema)
 	require.NoError(t, err)
 
 	v, err = svc.ListVersions(schemaID)
@@ -65,7 +66,7 @@ func TestVersioning_FullLifecycle(t *testing.T) {
 	assert.Equal(t, "Version 2", gActive.Schema().Name)
 
 	// Step 3: Rollback to v1 → creates v3
-	sv, err := svc.Rollback(schemaID, 1, "v2 had a bug")
+	sv, err := svc.Rollback(context.Background(), schemaID, 1, "v2 had a bug")
 	require.NoError(t, err)
 	assert.Equal(t, 3, sv.Version)
 	assert.True(t, sv.IsActive)
@@ -84,7 +85,7 @@ func TestVersioning_FullLifecycle(t *testing.T) {
 	assert.Equal(t, 3, h.ActiveVersion)
 
 	// Step 4: Activate v2 explicitly
-	require.NoError(t, svc.SetActiveVersion(schemaID, 2))
+	require.NoError(t, svc.SetActiveVersion(context.Background(), schemaID, 2))
 
 	gActive, err = svc.FindByID(schemaID)
 	require.NoError(t, err)
@@ -102,8 +103,8 @@ func TestVersioning_FullLifecycle(t *testing.T) {
 func TestVersioning_ExistingSchema_MigrationPath(t *testing.T) {
 	repo := repositories.NewMemoryGraphRepository()
 	pkgRegistry := packages.NewPackageRegistry()
-	internalPkgs := packages.NewInternal(llm.NewRegistry(nil, ""), pkgRegistry, nil)
-	pkgSvc := services.NewPackageService(repositories.NewMemoryPackageRepository(), pkgRegistry, internalPkgs)
+	internalPkgs := packages.NewInternal(packages.InternalDeps{Providers: llm.NewRegistry(nil, ""), Registry: pkgRegistry})
+	pkgSvc := services.NewPackageService(repositories.NewMemoryPackageRepository(), pkgRegistry, internalPkgs, nil)
 	require.NoError(t, pkgSvc.RegisterInternalPackages())
 
 	// Simulate pre-migration: save a graph directly into the repo without version tracking
@@ -112,7 +113,7 @@ func TestVersioning_ExistingSchema_MigrationPath(t *testing.T) {
 	require.NoError(t, err)
 	require.NoError(t, repo.Save(graph))
 
-	svc := services.NewGraphService(repo, pkgRegistry, nil)
+	svc := services.NewGraphService(repo, pkgRegistry, nil, nil)
 
 	// GetVersionHistory on a schema with no versions returns zeroed state
 	h, err := svc.GetVersionHistory(schema.ID)
@@ -121,7 +122,7 @@ func TestVersioning_ExistingSchema_MigrationPath(t *testing.T) {
 
 	// First update via the service creates version 1
 	schema.Name = "Post-migration update"
-	_, err = svc.Upsert(schema.ID, schema)
+	_, err = svc.Upsert(context.Background(), schema.ID, schema)
 	require.NoError(t, err)
 
 	versions, err := svc.ListVersions(schema.ID)
@@ -140,15 +141,15 @@ func TestVersioning_MultipleSchemas(t *testing.T) {
 	schemaB := mocks.SmallTestGraphSchema()
 	schemaB.ID = "schema-b"
 
-	_, err := svc.Upsert(schemaA.ID, schemaA)
+	_, err := svc.Upsert(context.Background(), schemaA.ID, schemaA)
 	require.NoError(t, err)
-	_, err = svc.Upsert(schemaB.ID, schemaB)
+	_, err = svc.Upsert(context.Background(), schemaB.ID, schemaB)
 	require.NoError(t, err)
 
 	// Update A twice
-	_, err = svc.Upsert(schemaA.ID, schemaA)
+	_, err = svc.Upsert(context.Background(), schemaA.ID, schemaA)
 	require.NoError(t, err)
-	_, err = svc.Upsert(schemaA.ID, schemaA)
+	_, err = svc.Upsert(context.Background(), schemaA.ID, schemaA)
 	require.NoError(t, err)
 
 	hA, err := svc.GetVersionHistory(schemaA.ID)
@@ -1,6 +1,7 @@
 package services_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/open-source-cloud/fuse/internal/mocks"
@@ -16,10 +17,10 @@ func newVersioningGraphService(t *testing.T) services.GraphService {
 	t.Helper()
 	memGraphRepo := repositories.NewMemoryGraphRepository()
 	pkgRegistry := packages.NewPackageRegistry()
-	internalPackages := packages.NewInternal(llm.NewRegistry(nil, ""), pkgRegistry, nil)
-	pkgSvc := services.NewPackageService(repositories.NewMemoryPackageRepository(), pkgRegistry, internalPackages)
+	internalPackages := packages.NewInternal(packages.InternalDeps{Providers: llm.NewRegistry(nil, ""), Registry: pkgRegistry})
+	pkgSvc := services.NewPackageService(repositories.NewMemoryPackageRepository(), pkgRegistry, internalPackages, nil)
 	require.NoError(t, pkgSvc.RegisterInternalPackages())
-	return services.NewGraphService(memGraphRepo, pkgRegistry, nil)
+	return services.NewGraphService(memGraphRepo, pkgRegistry, nil, nil)
 }
 
 // TestGraphService_Upsert_CreatesVersionOne verifies the first Upsert creates version 1.
@@ -27,7 +28,7 @@ func TestGraphService_Upsert_CreatesVersionOne(t *testing.T) {
 	svc := newVersioningGraphService(t)
 	schema := mocks.SmallTestGraphSchema()
 
-	_, err := svc.Upsert(schema.ID, schema)
+	_, err := svc.Upsert(context.Background(), schema.ID, schema)
 	require.NoError(t, err)
 
 	versions, err := svc.ListVersions(schema.ID)
@@ -42,11 +43,11 @@ func TestGraphService_Upsert_SecondCallCreatesVersionTwo(t *testing.T) {
 	svc := newVersioningGraphService(t)
 	schema := mocks.SmallTestGraphSchema()
 
-	_, err := svc.Upsert(schema.ID, schema)
+	_, err := svc.Upsert(context.Background(), schema.ID, schema)
 	require.NoError(t, err)
 
 	schema.Name = "Updated Name"
-	_, err = svc.Upsert(schema.ID, schema)
+	_, err = svc.Upsert(context.Background(), schema.ID, schema)
 	require.NoError(t, err)
 
 	versions, err := svc.ListVersions(schema.ID)
@@ -63,11 +64,11 @@ func TestGraphService_FindByIDAndVersion(t *testing.T) {
 	svc := newVersioningGraphService(t)
 	schema := mocks.SmallTestGraphSchema()
 
-	_, err := svc.Upsert(schema.ID, schema)
+	_, err := svc.Upsert(context.Background(), schema.ID, schema)
 	require.NoError(t, err)
 
 	schema.Name = "V2 Name"
-	_, err = svc.Upsert(schema.ID, schema)
+	_, err = svc.Upsert(context.Background(), schema.ID, schema)
 	require.NoError(t, err)
 
 	// v1 should still have the original name
@@ -85,7 +86,7 @@ func TestGraphService_FindByIDAndVersion(t *testing.T) {
 func TestGraphService_FindByIDAndVersion_NotFound(t *testing.T) {
 	svc := newVersioningGraphService(t)
 	schema := mocks.SmallTestGraphSchema()
-	_, err := svc.Upsert(schema.ID, schema)
+	_, err := svc.Upsert(context.Background(), schema.ID, schema)
 	require.NoError(t, err)
 
 	_, err = svc.FindByIDAndVersion(schema.ID, 99)
@@ -97,9 +98,9 @@ func TestGraphService_GetVersionHistory(t *testing.T) {
 	svc := newVersioningGraphService(t)
 	schema := mocks.SmallTestGraphSchema()
 
-	_, err := svc.Upsert(schema.ID, schema)
+	_, err := svc.Upsert(context.Background(), schema.ID, schema)
 	require.NoError(t, err)
-	_, err = svc.Upsert(schema.ID, schema)
+	_, err = svc.Upsert(context.Background(), schema.ID, schema)
 	require.NoError(t, err)
 
 	history, err := svc.GetVersionHistory(schema.ID)
@@ -115,13 +116,13 @@ func TestGraphService_SetActiveVersion(t *testing.T) {
 	svc := newVersioningGraphService(t)
 	schema := mocks.SmallTestGraphSchema()
 
-	_, err := svc.Upsert(schema.ID, schema)
+	_, err := svc.Upsert(context.Background(), schema.ID, schema)
 	require.NoError(t, err)
-	_, err = svc.Upsert(schema.ID, schema)
+	_, err = svc.Upsert(context.Background(), schema.ID, schema)
 	require.NoError(t, err)
 
 	// Activate v1
-	require.NoError(t, svc.SetActiveVersion(schema.ID, 1))
+	require.NoError(t, svc.SetActiveVersion(context.Background(), schema.ID, 1))
 
 	history, err := svc.GetVersionHistory(schema.ID)
 	require.NoError(t, err)
@@ -133,11 +134,11 @@ func TestGraphService_SetActiveVersion_AlreadyActive(t *testing.T) {
 	svc := newVersioningGraphService(t)
 	schema := mocks.SmallTestGraphSchema()
 
-	_, err := svc.Upsert(schema.ID, schema)
+	_, err := svc.Upsert(context.Background(), schema.ID, schema)
 	require.NoError(t, err)
 
 	// Activating the already-active version is a no-op
-	require.NoError(t, svc.SetActiveVersion(schema.ID, 1))
+	require.NoError(t, svc.SetActiveVersion(context.Background(), schema.ID, 1))
 
 	history, err := svc.GetVersionHistory(schema.ID)
 	require.NoError(t, err)
@@ -149,10 +150,10 @@ func TestGraphService_SetActiveVersion_NotFound(t *testing.T) {
 	svc := newVersioningGraphService(t)
 	schema := mocks.SmallTestGraphSchema()
 
-	_, err := svc.Upsert(schema.ID, schema)
+	_, err := svc.Upsert(context.Background(), schema.ID, schema)
 	require.NoError(t, err)
 
-	err = svc.SetActiveVersion(schema.ID, 99)
+	err = svc.SetActiveVersion(context.Background(), schema.ID, 99)
 	assert.ErrorIs(t, err, repositories.ErrSchemaVersionNotFound)
 }
 
@@ -162,16 +163,16 @@ func TestGraphService_Rollback(t *testing.T) {
 	schema := mocks.SmallTestGraphSchema()
 
 	// Create v1
-	_, err := svc.Upsert(schema.ID, schema)
+	_, err := svc.Upsert(context.Background(), schema.ID, schema)
 	require.NoError(t, err)
 
 	// Create v2 with a name change
 	schema.Name = "Broken Version"
-	_, err = svc.Upsert(schema.ID, schema)
+	_, err = svc.Upsert(context.Background(), schema.ID, schema)
 	require.NoError(t, err)
 
 	// Rollback to v1
-	sv, err := svc.Rollback(schema.ID, 1, "rolling back due to bug in v2")
+	sv, err := svc.Rollback(context.Background(), schema.ID, 1, "rolling back due to bug in v2")
 	require.NoError(t, err)
 	assert.Equal(t, 3, sv.Version)
 	assert.Equal(t, "test", sv.Schema.Name) // content from v1, not "Broken Version"
@@ -196,10 +197,10 @@ func TestGraphService_Rollback_NotFound(t *testing.T) {
 	svc := newVersioningGraphService(t)
 	schema := mocks.SmallTestGraphSchema()
 
-	_, err := svc.Upsert(schema.ID, schema)
+	_, err := svc.Upsert(context.Background(), schema.ID, schema)
 	require.NoError(t, err)
 
-	_, err = svc.Rollback(schema.ID, 99, "")
+	_, err = svc.Rollback(context.Background(), schema.ID, 99, "")
 	assert.ErrorIs(t, err, repositories.ErrSchemaVersionNotFound)
 }
 
@@ -208,11 +209,11 @@ func TestGraphService_Rollback_ToCurrent(t *testing.T) {
 	svc := newVersioningGraphService(t)
 	schema := mocks.SmallTestGraphSchema()
 
-	_, err := svc.Upsert(schema.ID, schema)
+	_, err := svc.Upsert(context.Background(), schema.ID, schema)
 	require.NoError(t, err)
 
 	// Rolling back to the current version creates a redundant new version
-	sv, err := svc.Rollback(schema.ID, 1, "rollback to current")
+	sv, err := svc.Rollback(context.Background(), schema.ID, 1, "rollback to current")
 	require.NoError(t, err)
 	assert.Equal(t, 2, sv.Version)
 
@@ -226,11 +227,11 @@ func TestGraphService_FindByID_ReturnsActiveVersion(t *testing.T) {
 	svc := newVersioningGraphService(t)
 	schema := mocks.SmallTestGraphSchema()
 
-	_, err := svc.Upsert(schema.ID, schema)
+	_, err := svc.Upsert(context.Background(), schema.ID, schema)
 	require.NoError(t, err)
 
 	schema.Name = "V2 Name"
-	_, err = svc.Upsert(schema.ID, schema)
+	_, err = svc.Upsert(context.Background(), schema.ID, schema)
 	require.NoError(t, err)
 
 	// Default: v2 is active
@@ -239,7 +240,7 @@ func TestGraphService_FindByID_ReturnsActiveVersion(t *testing.T) {
 	assert.Equal(t, "V2 Name", g.Schema().Name)
 
 	// Activate v1
-	require.NoError(t, svc.SetActiveVersion(schema.ID, 1))
+	require.NoError(t, svc.SetActiveVersion(context.Background(), schema.ID, 1))
 
 	// Now FindByID should return v1's content
 	g, err = svc.FindByID(schema.ID)
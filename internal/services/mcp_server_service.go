@@ -0,0 +1,454 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/open-source-cloud/fuse/internal/app/config"
+	"github.com/open-source-cloud/fuse/internal/audit"
+	"github.com/open-source-cloud/fuse/internal/packages/functions/ai"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/pkg/mcp"
+	"github.com/open-source-cloud/fuse/pkg/secrets"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+	"github.com/rs/zerolog/log"
+)
+
+// mcpToolsTTL is how long the tools a server listed are offered before it is asked again.
+const mcpToolsTTL = 5 * time.Minute
+
+// mcpToolNameUnsafe matches the characters of an MCP tool name that providers refuse in tool names.
+var mcpToolNameUnsafe = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+// ErrMCPStdioDisabled is returned when a stdio server is registered while MCP_STDIO_ENABLED is off.
+var ErrMCPStdioDisabled = errors.New("stdio MCP servers run as engine subprocesses and are disabled; set MCP_STDIO_ENABLED=true to allow them")
+
+type (
+	// MCPServerService manages the MCP servers registered per namespace and lends their tools to
+	// ai/agent nodes. It keeps one connection per server, namespace and environment, since the
+	// secret references of a registration resolve per environment, and reuses it across runs.
+	MCPServerService interface {
+		FindAll(namespace string) ([]*mcp.ServerConfig, error)
+		FindByID(namespace, id string) (*mcp.ServerConfig, error)
+		Save(ctx context.Context, namespace string, server *mcp.ServerConfig) error
+		Delete(ctx context.Context, namespace, id string) error
+		// ListTools returns the tools of the servers of the namespace of execInfo's schema. A
+		// server that cannot be reached is left out.
+		ListTools(ctx context.Context, execInfo *workflow.ExecutionInfo) []ai.ToolDescriptor
+		// InvokeTool calls the tool mcp/<server>/<tool> with execInfo's input, bounded by the
+		// server's timeout and execInfo's context.
+		InvokeTool(functionID string, execInfo *workflow.ExecutionInfo) (workflow.FunctionResult, error)
+		// Close disconnects every server; stdio servers are stopped.
+		Close()
+	}
+
+	// DefaultMCPServerService is the default MCPServerService implementation.
+	DefaultMCPServerService struct {
+		repo               repositories.MCPServerRepository
+		store              secrets.SecretStore
+		audit              AuditService
+		defaultEnvironment string
+		stdioEnabled       bool
+
+		mu    sync.Mutex
+		conns map[mcpConnKey]*mcpConn
+	}
+
+	mcpConnKey struct {
+		namespace   string
+		environment string
+		id          string
+	}
+
+	// mcpConn is the connection to one server. mu serializes connecting and listing tools; the
+	// client itself is safe for concurrent calls.
+	mcpConn struct {
+		server *mcp.ServerConfig
+
+		mu       sync.Mutex
+		client   *mcp.Client
+		tools    []mcp.Tool
+		listedAt time.Time
+	}
+)
+
+// NewMCPServerService returns a new MCPServerService.
+func NewMCPServerService(cfg *config.Config, repo repositories.MCPServerRepository, store secrets.SecretStore, auditService AuditService) MCPServerService {
+	return &DefaultMCPServerService{
+		repo:               repo,
+		store:              store,
+		audit:              auditService,
+		defaultEnvironment: cfg.Environment,
+		stdioEnabled:       cfg.LLM.MCPStdioEnabled,
+		conns:              make(map[mcpConnKey]*mcpConn),
+	}
+}
+
+// FindAll returns the servers registered in a namespace.
+func (s *DefaultMCPServerService) FindAll(namespace string) ([]*mcp.ServerConfig, error) {
+	return s.repo.FindAll(namespace)
+}
+
+// FindByID returns a server registered in a namespace.
+func (s *DefaultMCPServerService) FindByID(namespace, id string) (*mcp.ServerConfig, error) {
+	return s.repo.FindByID(namespace, id)
+}
+
+// Save validates and registers a server, replacing any registration with its id; open
+// connections to the old registration are closed.
+func (s *DefaultMCPServerService) Save(ctx context.Context, namespace string, server *mcp.ServerConfig) error {
+	if err := server.Validate(); err != nil {
+		return err
+	}
+	if server.Transport == mcp.TransportStdio && !s.stdioEnabled {
+		return ErrMCPStdioDisabled
+	}
+	beforeHash := ""
+	if prev, err := s.repo.FindByID(namespace, server.ID); err == nil {
+		beforeHash = audit.Hash(prev)
+	}
+	if err := s.repo.Save(namespace, server); err != nil {
+		return err
+	}
+	s.disconnect(namespace, server.ID)
+
+	entry := mcpServerAuditEntry(audit.ActionMCPServerSave, namespace, server.ID)
+	entry.BeforeHash, entry.AfterHash = beforeHash, audit.Hash(server)
+	entry.Details = map[string]string{"transport": string(server.Transport)}
+	recordAudit(ctx, s.audit, entry)
+	return nil
+}
+
+// Delete unregisters a server and closes its connections.
+func (s *DefaultMCPServerService) Delete(ctx context.Context, namespace, id string) error {
+	server, err := s.repo.FindByID(namespace, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(namespace, id); err != nil {
+		return err
+	}
+	s.disconnect(namespace, id)
+
+	entry := mcpServerAuditEntry(audit.ActionMCPServerDelete, namespace, id)
+	entry.BeforeHash = audit.Hash(server)
+	recordAudit(ctx, s.audit, entry)
+	return nil
+}
+
+// mcpServerAuditEntry starts the audit entry of a change to a server registration.
+func mcpServerAuditEntry(action audit.Action, namespace, id string) audit.Entry {
+	return audit.Entry{
+		Action:       action,
+		ResourceType: audit.ResourceMCPServer,
+		Resource:     id,
+		Namespace:    namespace,
+	}
+}
+
+// ListTools lists the tools of every server of the namespace concurrently, each bounded by its
+// timeout. Tools are ordered by server, then as the server listed them.
+func (s *DefaultMCPServerService) ListTools(ctx context.Context, execInfo *workflow.ExecutionInfo) []ai.ToolDescriptor {
+	namespace := workflow.NamespaceOf(execInfo.SchemaID)
+	servers, err := s.repo.FindAll(namespace)
+	if err != nil {
+		log.Warn().Err(err).Str("namespace", namespace).Msg("mcp: failed to load servers")
+		return nil
+	}
+
+	perServer := make([][]ai.ToolDescriptor, len(servers))
+	var wg sync.WaitGroup
+	for i, server := range servers {
+		wg.Go(func() {
+			ctx, cancel := context.WithTimeout(ctx, server.CallTimeout())
+			defer cancel()
+			tools, err := s.tools(ctx, s.key(namespace, execInfo.Environment, server.ID), server)
+			if err != nil {
+				log.Warn().Err(err).Str("namespace", namespace).Str("server", server.ID).Msg("mcp: server tools unavailable")
+				return
+			}
+			perServer[i] = toolDescriptors(server, tools)
+		})
+	}
+	wg.Wait()
+
+	descriptors := make([]ai.ToolDescriptor, 0)
+	for _, tools := range perServer {
+		descriptors = append(descriptors, tools...)
+	}
+	return descriptors
+}
+
+// InvokeTool calls a tool of a server of the namespace of execInfo's schema. A tool that reports
+// a failure returns an error output carrying its text.
+func (s *DefaultMCPServerService) InvokeTool(functionID string, execInfo *workflow.ExecutionInfo) (workflow.FunctionResult, error) {
+	serverID, tool, ok := mcp.ParseToolFunctionID(functionID)
+	if !ok {
+		return workflow.FunctionResult{}, fmt.Errorf("invalid MCP tool id %q", functionID)
+	}
+	namespace := workflow.NamespaceOf(execInfo.SchemaID)
+	server, err := s.repo.FindByID(namespace, serverID)
+	if err != nil {
+		return workflow.FunctionResult{}, fmt.Errorf("mcp server %s: %w", serverID, err)
+	}
+	if !server.OffersTool(tool) {
+		return workflow.FunctionResult{}, fmt.Errorf("mcp server %s does not offer tool %q", serverID, tool)
+	}
+
+	ctx, cancel := context.WithTimeout(execInfo.Ctx(), server.CallTimeout())
+	defer cancel()
+	key := s.key(namespace, execInfo.Environment, serverID)
+	client, err := s.client(ctx, key, server)
+	if err != nil {
+		return workflow.FunctionResult{}, err
+	}
+	var args map[string]any
+	if execInfo.Input != nil {
+		args = execInfo.Input.Raw()
+	}
+	res, err := client.CallTool(ctx, tool, args)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return workflow.FunctionResult{}, fmt.Errorf("mcp server %s: tool %s did not answer: %w", serverID, tool, ctxErr)
+		}
+		s.dropOnTransportError(key, err)
+		return workflow.FunctionResult{}, fmt.Errorf("mcp server %s: tool %s: %w", serverID, tool, err)
+	}
+	if res.IsError {
+		return workflow.NewFunctionResult(workflow.FunctionError, map[string]any{"error": res.Text()}), nil
+	}
+	return workflow.NewFunctionResultSuccessWith(toolResultData(res)), nil
+}
+
+// Close disconnects every server.
+func (s *DefaultMCPServerService) Close() {
+	s.mu.Lock()
+	conns := s.conns
+	s.conns = make(map[mcpConnKey]*mcpConn)
+	s.mu.Unlock()
+	for _, conn := range conns {
+		conn.close()
+	}
+}
+
+// key returns the connection key of a server in a namespace and environment.
+func (s *DefaultMCPServerService) key(namespace, environment, id string) mcpConnKey {
+	if environment == "" {
+		environment = s.defaultEnvironment
+	}
+	return mcpConnKey{namespace: namespace, environment: environment, id: id}
+}
+
+// tools returns the tools the server offers, listing them again once mcpToolsTTL has passed.
+func (s *DefaultMCPServerService) tools(ctx context.Context, key mcpConnKey, server *mcp.ServerConfig) ([]mcp.Tool, error) {
+	conn := s.conn(key, server)
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	if conn.client != nil && time.Since(conn.listedAt) < mcpToolsTTL {
+		return conn.tools, nil
+	}
+	client, err := s.connect(ctx, key, conn)
+	if err != nil {
+		return nil, err
+	}
+	tools, err := client.ListTools(ctx)
+	if err != nil {
+		s.dropOnTransportError(key, err)
+		return nil, err
+	}
+	conn.tools, conn.listedAt = tools, time.Now()
+	return tools, nil
+}
+
+// client returns the connected client of a server.
+func (s *DefaultMCPServerService) client(ctx context.Context, key mcpConnKey, server *mcp.ServerConfig) (*mcp.Client, error) {
+	conn := s.conn(key, server)
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	return s.connect(ctx, key, conn)
+}
+
+// conn returns the connection of key, replacing one made for an outdated registration (saved on
+// another node, say).
+func (s *DefaultMCPServerService) conn(key mcpConnKey, server *mcp.ServerConfig) *mcpConn {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	conn, ok := s.conns[key]
+	if ok && reflect.DeepEqual(conn.server, server) {
+		return conn
+	}
+	if ok {
+		go conn.close()
+	}
+	conn = &mcpConn{server: server}
+	s.conns[key] = conn
+	return conn
+}
+
+// connect connects conn if it is not connected yet, resolving the registration's secret
+// references in the connection's namespace and environment. conn.mu must be held.
+func (s *DefaultMCPServerService) connect(ctx context.Context, key mcpConnKey, conn *mcpConn) (*mcp.Client, error) {
+	if conn.client != nil {
+		return conn.client, nil
+	}
+	resolved, err := s.resolve(ctx, key, conn.server)
+	if err != nil {
+		return nil, fmt.Errorf("mcp server %s: %w", key.id, err)
+	}
+	client, err := mcp.Connect(ctx, resolved, mcpImplementation())
+	if err != nil {
+		return nil, fmt.Errorf("mcp server %s: connect: %w", key.id, err)
+	}
+	conn.client, conn.listedAt = client, time.Time{}
+	return client, nil
+}
+
+// resolve returns a copy of server with the references in its env and headers replaced by their
+// values.
+func (s *DefaultMCPServerService) resolve(ctx context.Context, key mcpConnKey, server *mcp.ServerConfig) (mcp.ServerConfig, error) {
+	scope := secrets.Scope{Namespace: key.namespace, Environment: key.environment}
+	resolveRef := func(name string) (string, error) {
+		v, err := s.store.Resolve(ctx, scope, name)
+		if err != nil {
+			return "", err
+		}
+		return v.Reveal(), nil
+	}
+	resolveAll := func(values map[string]string) (map[string]string, error) {
+		if values == nil {
+			return nil, nil
+		}
+		out := make(map[string]string, len(values))
+		for name, value := range values {
+			v, err := secrets.ReplaceSecretRefs(value, resolveRef)
+			if err == nil {
+				v, err = secrets.ReplaceCredentialRefs(v, resolveRef)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("resolve %s for environment %q: %w", name, key.environment, err)
+			}
+			out[name] = v
+		}
+		return out, nil
+	}
+
+	resolved := *server
+	var err error
+	if resolved.Env, err = resolveAll(server.Env); err != nil {
+		return mcp.ServerConfig{}, err
+	}
+	if resolved.Headers, err = resolveAll(server.Headers); err != nil {
+		return mcp.ServerConfig{}, err
+	}
+	return resolved, nil
+}
+
+// disconnect closes the connections to a server in every environment.
+func (s *DefaultMCPServerService) disconnect(namespace, id string) {
+	s.mu.Lock()
+	closing := make([]*mcpConn, 0)
+	for key, conn := range s.conns {
+		if key.namespace == namespace && key.id == id {
+			closing = append(closing, conn)
+			delete(s.conns, key)
+		}
+	}
+	s.mu.Unlock()
+	for _, conn := range closing {
+		conn.close()
+	}
+}
+
+// dropOnTransportError forgets the connection of key after a failure that left it unusable (the
+// process exited, the session expired), so the next use reconnects. Errors the server answered
+// with leave the connection alone.
+func (s *DefaultMCPServerService) dropOnTransportError(key mcpConnKey, err error) {
+	var rpcErr *mcp.Error
+	if errors.As(err, &rpcErr) {
+		return
+	}
+	s.mu.Lock()
+	conn, ok := s.conns[key]
+	if ok {
+		delete(s.conns, key)
+	}
+	s.mu.Unlock()
+	if ok {
+		go conn.close()
+	}
+}
+
+// close closes the connection's client, waiting for a connect in progress.
+func (c *mcpConn) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.client != nil {
+		_ = c.client.Close()
+		c.client = nil
+	}
+}
+
+// toolDescriptors turns the tools a server offers into agent tools named mcp__<server>__<tool>.
+func toolDescriptors(server *mcp.ServerConfig, tools []mcp.Tool) []ai.ToolDescriptor {
+	descriptors := make([]ai.ToolDescriptor, 0, len(tools))
+	for _, tool := range tools {
+		if !server.OffersTool(tool.Name) {
+			continue
+		}
+		params := tool.InputSchema
+		if params == nil {
+			params = map[string]any{"type": "object", "properties": map[string]any{}}
+		}
+		description := tool.Description
+		if description == "" {
+			description = tool.Title
+		}
+		descriptors = append(descriptors, ai.ToolDescriptor{
+			FunctionID:  mcp.ToolFunctionID(server.ID, tool.Name),
+			MangledName: "mcp__" + server.ID + "__" + mcpToolNameUnsafe.ReplaceAllString(tool.Name, "_"),
+			Description: fmt.Sprintf("MCP tool %s of server %s: %s", tool.Name, server.ID, description),
+			Parameters:  params,
+		})
+	}
+	return descriptors
+}
+
+// toolResultData is what the model sees of a tool result: its text, its structured content and
+// any other content items.
+func toolResultData(res *mcp.CallToolResult) map[string]any {
+	data := map[string]any{"text": res.Text()}
+	if res.StructuredContent != nil {
+		data["structuredContent"] = res.StructuredContent
+	}
+	other := make([]any, 0)
+	for _, c := range res.Content {
+		if c.Type == "text" {
+			continue
+		}
+		var item map[string]any
+		if raw, err := json.Marshal(c); err == nil && json.Unmarshal(raw, &item) == nil {
+			other = append(other, item)
+		}
+	}
+	if len(other) > 0 {
+		data["content"] = other
+	}
+	return data
+}
+
+// mcpImplementation names FUSE in MCP handshakes, as client and as server, with the module
+// version it was built from.
+func mcpImplementation() mcp.Implementation {
+	version := "devel"
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		version = info.Main.Version
+	}
+	return mcp.Implementation{Name: "fuse", Version: version}
+}
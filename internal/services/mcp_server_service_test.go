@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/open-source-cloud/fuse/internal/app/config"
+	"github.com/open-source-cloud/fuse/internal/audit"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/pkg/mcp"
+	"github.com/open-source-cloud/fuse/pkg/mcp/mcptest"
+	"github.com/open-source-cloud/fuse/pkg/secrets"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMCPServerService(t *testing.T, auditRepo repositories.AuditRepository, stdioEnabled bool) *DefaultMCPServerService {
+	t.Helper()
+	store := secrets.NewMemorySecretStore()
+	require.NoError(t, store.Set(context.Background(), secrets.Scope{Namespace: "billing", Environment: "prod"}, "WIKI_TOKEN", "s3cret"))
+	cfg := &config.Config{Environment: "prod", LLM: config.LLMConfig{MCPStdioEnabled: stdioEnabled}}
+	svc := NewMCPServerService(cfg, repositories.NewMemoryMCPServerRepository(), store, NewAuditService(auditRepo)).(*DefaultMCPServerService)
+	t.Cleanup(svc.Close)
+	return svc
+}
+
+func mcpExecInfo(t *testing.T, args map[string]any) *workflow.ExecutionInfo {
+	t.Helper()
+	input, err := workflow.NewFunctionInputWith(args)
+	require.NoError(t, err)
+	info := workflow.NewExecutionInfo("wf-1", "exec-1", "", input)
+	info.SchemaID = workflow.QualifyID("billing", "invoices")
+	return info
+}
+
+func TestMCPServerService_ToolsOverHTTP(t *testing.T) {
+	t.Parallel()
+	fake := &mcptest.Server{
+		Tools: []mcp.Tool{
+			{Name: "search", Description: "Searches the wiki", InputSchema: map[string]any{"type": "object"}},
+			{Name: "page.delete"},
+			{Name: "page.read", Title: "Read a page"},
+		},
+		Call: func(ctx context.Context, name string, args map[string]any) (*mcp.CallToolResult, error) {
+			switch name {
+			case "search":
+				return &mcp.CallToolResult{
+					Content:           []mcp.Content{mcp.TextContent("found 1 page"), {Type: "resource_link", URI: "wiki://pages/1"}},
+					StructuredContent: map[string]any{"hits": 1.0},
+				}, nil
+			case "page.read":
+				if args["slow"] == true {
+					<-ctx.Done()
+					return nil, ctx.Err()
+				}
+				return &mcp.CallToolResult{Content: []mcp.Content{mcp.TextContent("no such page")}, IsError: true}, nil
+			}
+			return nil, errors.New("unexpected tool")
+		},
+	}
+	httpServer := httptest.NewServer(fake)
+	defer httpServer.Close()
+
+	ctx := context.Background()
+	svc := newTestMCPServerService(t, repositories.NewMemoryAuditRepository(), false)
+	require.NoError(t, svc.Save(ctx, "billing", &mcp.ServerConfig{
+		ID: "wiki", Transport: mcp.TransportHTTP, URL: httpServer.URL,
+		Headers: map[string]string{"Authorization": "Bearer {{secret:WIKI_TOKEN}}"},
+		Timeout: 200 * time.Millisecond,
+		Tools:   []string{"search", "page.read"},
+	}))
+
+	tools := svc.ListTools(ctx, mcpExecInfo(t, nil))
+	require.Len(t, tools, 2, "page.delete is not allowlisted")
+	assert.Equal(t, "mcp/wiki/search", tools[0].FunctionID)
+	assert.Equal(t, "mcp__wiki__search", tools[0].MangledName)
+	assert.Equal(t, map[string]any{"type": "object"}, tools[0].Parameters)
+	assert.Equal(t, "mcp__wiki__page_read", tools[1].MangledName)
+	assert.Contains(t, tools[1].Description, "Read a page")
+	assert.Empty(t, svc.ListTools(ctx, &workflow.ExecutionInfo{SchemaID: "invoices"}), "servers are per namespace")
+
+	result, err := svc.InvokeTool("mcp/wiki/search", mcpExecInfo(t, map[string]any{"query": "refunds"}))
+	require.NoError(t, err)
+	assert.Equal(t, workflow.FunctionSuccess, result.Output.Status)
+	assert.Equal(t, "found 1 page", result.Output.Data["text"])
+	assert.Equal(t, map[string]any{"hits": 1.0}, result.Output.Data["structuredContent"])
+	assert.Equal(t, []any{map[string]any{"type": "resource_link", "uri": "wiki://pages/1"}}, result.Output.Data["content"])
+
+	result, err = svc.InvokeTool("mcp/wiki/page.read", mcpExecInfo(t, map[string]any{"slug": "x"}))
+	require.NoError(t, err)
+	assert.Equal(t, workflow.FunctionError, result.Output.Status)
+	assert.Equal(t, "no such page", result.Output.Data["error"])
+
+	_, err = svc.InvokeTool("mcp/wiki/page.read", mcpExecInfo(t, map[string]any{"slow": true}))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	_, err = svc.InvokeTool("mcp/wiki/page.delete", mcpExecInfo(t, nil))
+	assert.ErrorContains(t, err, "does not offer")
+
+	for _, h := range fake.Headers() {
+		assert.Equal(t, "Bearer s3cret", h.Get("Authorization"))
+	}
+	assert.Equal(t, 1, fake.Sessions(), "the connection and tool list are reused")
+	assert.Equal(t, []mcp.CallToolParams{
+		{Name: "search", Arguments: map[string]any{"query": "refunds"}},
+		{Name: "page.read", Arguments: map[string]any{"slug": "x"}},
+		{Name: "page.read", Arguments: map[string]any{"slow": true}},
+	}, fake.Calls())
+}
+
+func TestMCPServerService_SaveReconnects(t *testing.T) {
+	t.Parallel()
+	fake := &mcptest.Server{Tools: []mcp.Tool{{Name: "search"}, {Name: "open"}}}
+	httpServer := httptest.NewServer(fake)
+	defer httpServer.Close()
+
+	ctx := context.Background()
+	svc := newTestMCPServerService(t, repositories.NewMemoryAuditRepository(), false)
+	server := &mcp.ServerConfig{ID: "wiki", Transport: mcp.TransportHTTP, URL: httpServer.URL, Tools: []string{"search"}}
+	require.NoError(t, svc.Save(ctx, "billing", server))
+	assert.Len(t, svc.ListTools(ctx, mcpExecInfo(t, nil)), 1)
+
+	require.NoError(t, svc.Save(ctx, "billing", &mcp.ServerConfig{ID: "wiki", Transport: mcp.TransportHTTP, URL: httpServer.URL}))
+	assert.Len(t, svc.ListTools(ctx, mcpExecInfo(t, nil)), 2, "the new registration is listed again")
+	assert.Equal(t, 2, fake.Sessions())
+
+	require.NoError(t, svc.Delete(ctx, "billing", "wiki"))
+	assert.Empty(t, svc.ListTools(ctx, mcpExecInfo(t, nil)))
+	_, err := svc.InvokeTool("mcp/wiki/search", mcpExecInfo(t, nil))
+	assert.ErrorIs(t, err, repositories.ErrMCPServerNotFound)
+}
+
+func TestMCPServerService_UnreachableServerIsSkipped(t *testing.T) {
+	t.Parallel()
+	fake := &mcptest.Server{Tools: []mcp.Tool{{Name: "search"}}}
+	httpServer := httptest.NewServer(fake)
+	defer httpServer.Close()
+
+	ctx := context.Background()
+	svc := newTestMCPServerService(t, repositories.NewMemoryAuditRepository(), false)
+	require.NoError(t, svc.Save(ctx, "billing", &mcp.ServerConfig{ID: "wiki", Transport: mcp.TransportHTTP, URL: httpServer.URL}))
+	require.NoError(t, svc.Save(ctx, "billing", &mcp.ServerConfig{
+		ID: "jira", Transport: mcp.TransportHTTP, URL: httpServer.URL,
+		Headers: map[string]string{"Authorization": "{{secret:MISSING}}"},
+	}))
+
+	tools := svc.ListTools(ctx, mcpExecInfo(t, nil))
+	require.Len(t, tools, 1)
+	assert.Equal(t, "mcp/wiki/search", tools[0].FunctionID)
+}
+
+func TestMCPServerService_SaveValidatesAndAudits(t *testing.T) {
+	t.Parallel()
+	auditRepo := repositories.NewMemoryAuditRepository()
+	ctx := audit.WithActor(context.Background(), "user:ops")
+	stdio := &mcp.ServerConfig{ID: "git", Transport: mcp.TransportStdio, Command: "mcp-server-git"}
+
+	svc := newTestMCPServerService(t, auditRepo, false)
+	assert.ErrorIs(t, svc.Save(ctx, "billing", stdio), ErrMCPStdioDisabled)
+	assert.ErrorIs(t, svc.Save(ctx, "billing", &mcp.ServerConfig{ID: "wiki", Transport: mcp.TransportHTTP}), mcp.ErrInvalidServerConfig)
+	require.NoError(t, newTestMCPServerService(t, auditRepo, true).Save(ctx, "billing", stdio))
+
+	entries, err := auditRepo.Find(audit.Filter{Namespace: "billing"})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, audit.ActionMCPServerSave, entries[0].Action)
+	assert.Equal(t, audit.ResourceMCPServer, entries[0].ResourceType)
+	assert.Equal(t, "git", entries[0].Resource)
+	assert.Equal(t, "user:ops", entries[0].Actor)
+	assert.Equal(t, "stdio", entries[0].Details["transport"])
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+)
+
+var (
+	// ErrNamespaceQuotaExceeded is returned when an operation would exceed a namespace quota.
+	ErrNamespaceQuotaExceeded = errors.New("namespace quota exceeded")
+	// ErrDefaultNamespace is returned when deleting the default namespace.
+	ErrDefaultNamespace = errors.New("the default namespace cannot be deleted")
+	// ErrNamespaceInUse is returned when deleting a namespace that still owns schemas or packages.
+	ErrNamespaceInUse = errors.New("namespace still has schemas or packages")
+)
+
+type (
+	// NamespaceService manages the namespaces registry and enforces namespace quotas.
+	NamespaceService interface {
+		FindAll() ([]*workflow.Namespace, error)
+		FindByName(name string) (*workflow.Namespace, error)
+		Save(ns *workflow.Namespace) (*workflow.Namespace, error)
+		Delete(name string) error
+		// IsValid reports whether name is a declared namespace. The default namespace is always
+		// valid even if the registry has not been seeded.
+		IsValid(name string) bool
+		// CheckConcurrency returns ErrNamespaceQuotaExceeded when the namespace already runs its
+		// maximum number of concurrent workflows.
+		CheckConcurrency(name string) error
+		// CheckStorage returns ErrNamespaceQuotaExceeded when growing the namespace's stored
+		// definitions by delta bytes would exceed its storage quota.
+		CheckStorage(name string, delta int64) error
+		// StorageUsage returns the JSON size of the namespace's schema versions and packages.
+		StorageUsage(name string) (int64, error)
+	}
+
+	// DefaultNamespaceService is the default NamespaceService implementation.
+	DefaultNamespaceService struct {
+		repo         repositories.NamespaceRepository
+		workflowRepo repositories.WorkflowRepository
+		graphRepo    repositories.GraphRepository
+		packageRepo  repositories.PackageRepository
+	}
+)
+
+// NewNamespaceService returns a new NamespaceService.
+func NewNamespaceService(
+	repo repositories.NamespaceRepository,
+	workflowRepo repositories.WorkflowRepository,
+	graphRepo repositories.GraphRepository,
+	packageRepo repositories.PackageRepository,
+) NamespaceService {
+	return &DefaultNamespaceService{
+		repo:         repo,
+		workflowRepo: workflowRepo,
+		graphRepo:    graphRepo,
+		packageRepo:  packageRepo,
+	}
+}
+
+// FindAll returns all declared namespaces.
+func (s *DefaultNamespaceService) FindAll() ([]*workflow.Namespace, error) {
+	return s.repo.FindAll()
+}
+
+// FindByName returns a single namespace by name.
+func (s *DefaultNamespaceService) FindByName(name string) (*workflow.Namespace, error) {
+	return s.repo.FindByName(name)
+}
+
+// Save validates and upserts a namespace.
+func (s *DefaultNamespaceService) Save(ns *workflow.Namespace) (*workflow.Namespace, error) {
+	if err := ns.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Save(ns); err != nil {
+		return nil, err
+	}
+	return ns, nil
+}
+
+// Delete removes an empty namespace by name. Executions, credentials and secrets of a deleted
+// namespace are left to retention and explicit cleanup.
+func (s *DefaultNamespaceService) Delete(name string) error {
+	if name == workflow.DefaultNamespaceName {
+		return ErrDefaultNamespace
+	}
+	if _, err := s.repo.FindByName(name); err != nil {
+		return err
+	}
+	schemas, err := s.graphRepo.List()
+	if err != nil {
+		return err
+	}
+	for _, item := range schemas {
+		if workflow.NamespaceOf(item.SchemaID) == name {
+			return fmt.Errorf("%w: schema %s", ErrNamespaceInUse, item.SchemaID)
+		}
+	}
+	pkgs, err := s.packageRepo.FindAll()
+	if err != nil {
+		return err
+	}
+	for _, pkg := range pkgs {
+		if workflow.NamespaceOf(pkg.ID) == name {
+			return fmt.Errorf("%w: package %s", ErrNamespaceInUse, pkg.ID)
+		}
+	}
+	return s.repo.Delete(name)
+}
+
+// IsValid reports whether name is a declared namespace (the default is always valid).
+func (s *DefaultNamespaceService) IsValid(name string) bool {
+	if name == workflow.DefaultNamespaceName {
+		return true
+	}
+	_, err := s.repo.FindByName(name)
+	return err == nil
+}
+
+// CheckConcurrency counts the namespace's active executions against its quota.
+func (s *DefaultNamespaceService) CheckConcurrency(name string) error {
+	quota, err := s.quota(name)
+	if err != nil || quota.MaxConcurrentWorkflows == 0 {
+		return err
+	}
+	active, err := s.workflowRepo.CountActive(name)
+	if err != nil {
+		return err
+	}
+	if active >= quota.MaxConcurrentWorkflows {
+		return fmt.Errorf("%w: namespace %s already runs %d of %d concurrent workflows",
+			ErrNamespaceQuotaExceeded, name, active, quota.MaxConcurrentWorkflows)
+	}
+	return nil
+}
+
+// CheckStorage measures the namespace's stored definitions against its quota.
+func (s *DefaultNamespaceService) CheckStorage(name string, delta int64) error {
+	quota, err := s.quota(name)
+	if err != nil || quota.MaxStorageBytes == 0 {
+		return err
+	}
+	used, err := s.StorageUsage(name)
+	if err != nil {
+		return err
+	}
+	if used+delta > quota.MaxStorageBytes {
+		return fmt.Errorf("%w: namespace %s would store %d of %d bytes",
+			ErrNamespaceQuotaExceeded, name, used+delta, quota.MaxStorageBytes)
+	}
+	return nil
+}
+
+// StorageUsage sums the JSON size of every schema version and package manifest of a namespace.
+func (s *DefaultNamespaceService) StorageUsage(name string) (int64, error) {
+	var used int64
+	schemas, err := s.graphRepo.List()
+	if err != nil {
+		return 0, err
+	}
+	for _, item := range schemas {
+		if workflow.NamespaceOf(item.SchemaID) != name {
+			continue
+		}
+		versions, err := s.graphRepo.ListVersions(item.SchemaID)
+		if err != nil {
+			return 0, err
+		}
+		for _, sv := range versions {
+			size, err := JSONSize(sv.Schema)
+			if err != nil {
+				return 0, err
+			}
+			used += size
+		}
+	}
+	pkgs, err := s.packageRepo.FindAll()
+	if err != nil {
+		return 0, err
+	}
+	for _, pkg := range pkgs {
+		if workflow.NamespaceOf(pkg.ID) != name {
+			continue
+		}
+		size, err := JSONSize(pkg)
+		if err != nil {
+			return 0, err
+		}
+		used += size
+	}
+	return used, nil
+}
+
+// quota returns the quota of a namespace; the default namespace is unlimited unless declared.
+func (s *DefaultNamespaceService) quota(name string) (workflow.NamespaceQuota, error) {
+	ns, err := s.repo.FindByName(name)
+	if err != nil {
+		if name == workflow.DefaultNamespaceName && errors.Is(err, repositories.ErrNamespaceNotFound) {
+			return workflow.NamespaceQuota{}, nil
+		}
+		return workflow.NamespaceQuota{}, err
+	}
+	return ns.Quota, nil
+}
+
+// JSONSize returns the size of v encoded as JSON, the unit of namespace storage quotas.
+func JSONSize(v any) (int64, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(raw)), nil
+}
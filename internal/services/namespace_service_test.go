@@ -0,0 +1,108 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/open-source-cloud/fuse/internal/mocks"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	internalworkflow "github.com/open-source-cloud/fuse/internal/workflow"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type namespaceFixture struct {
+	svc          NamespaceService
+	workflowRepo repositories.WorkflowRepository
+	graphRepo    repositories.GraphRepository
+	packageRepo  *repositories.MemoryPackageRepository
+}
+
+func newNamespaceFixture(t *testing.T) namespaceFixture {
+	t.Helper()
+	f := namespaceFixture{
+		workflowRepo: repositories.NewMemoryWorkflowRepository(),
+		graphRepo:    repositories.NewMemoryGraphRepository(),
+		packageRepo:  repositories.NewMemoryPackageRepository(),
+	}
+	f.svc = NewNamespaceService(repositories.NewMemoryNamespaceRepository(), f.workflowRepo, f.graphRepo, f.packageRepo)
+	return f
+}
+
+// saveSchema stores version 1 of the small test schema under schemaID.
+func (f namespaceFixture) saveSchema(t *testing.T, schemaID string) *internalworkflow.Graph {
+	t.Helper()
+	schema := mocks.SmallTestGraphSchema()
+	schema.ID = schemaID
+	graph, err := internalworkflow.NewGraph(schema)
+	require.NoError(t, err)
+	require.NoError(t, f.graphRepo.Save(graph))
+	require.NoError(t, f.graphRepo.SaveVersion(&internalworkflow.SchemaVersion{SchemaID: schemaID, Version: 1, Schema: *schema, IsActive: true}))
+	return graph
+}
+
+func TestNamespaceService_IsValid(t *testing.T) {
+	t.Parallel()
+	f := newNamespaceFixture(t)
+	_, err := f.svc.Save(workflow.NewNamespace("billing", ""))
+	require.NoError(t, err)
+
+	assert.True(t, f.svc.IsValid(workflow.DefaultNamespaceName))
+	assert.True(t, f.svc.IsValid("billing"))
+	assert.False(t, f.svc.IsValid("shipping"))
+}
+
+func TestNamespaceService_CheckConcurrency(t *testing.T) {
+	t.Parallel()
+	f := newNamespaceFixture(t)
+	_, err := f.svc.Save(&workflow.Namespace{Name: "billing", Quota: workflow.NamespaceQuota{MaxConcurrentWorkflows: 1}})
+	require.NoError(t, err)
+	graph := f.saveSchema(t, "billing:invoice")
+
+	require.NoError(t, f.svc.CheckConcurrency("billing"))
+
+	wf := internalworkflow.New(workflow.NewID(), graph, workflow.DefaultEnvironmentName)
+	wf.SetState(internalworkflow.StateRunning)
+	require.NoError(t, f.workflowRepo.Save(wf))
+	assert.ErrorIs(t, f.svc.CheckConcurrency("billing"), ErrNamespaceQuotaExceeded)
+	assert.NoError(t, f.svc.CheckConcurrency(workflow.DefaultNamespaceName), "other namespaces are unaffected")
+
+	wf.SetState(internalworkflow.StateFinished)
+	require.NoError(t, f.workflowRepo.Save(wf))
+	assert.NoError(t, f.svc.CheckConcurrency("billing"), "finished executions free their slot")
+}
+
+func TestNamespaceService_CheckStorage(t *testing.T) {
+	t.Parallel()
+	f := newNamespaceFixture(t)
+	f.saveSchema(t, "billing:invoice")
+	f.saveSchema(t, "invoice")
+	require.NoError(t, f.packageRepo.Save(workflow.NewPackage("billing:tools")))
+
+	used, err := f.svc.StorageUsage("billing")
+	require.NoError(t, err)
+	schemaSize, err := JSONSize(mocks.SmallTestGraphSchema())
+	require.NoError(t, err)
+	assert.Greater(t, used, schemaSize, "counts the schema version and the package")
+
+	_, err = f.svc.Save(&workflow.Namespace{Name: "billing", Quota: workflow.NamespaceQuota{MaxStorageBytes: used + 10}})
+	require.NoError(t, err)
+	assert.NoError(t, f.svc.CheckStorage("billing", 10))
+	assert.ErrorIs(t, f.svc.CheckStorage("billing", 11), ErrNamespaceQuotaExceeded)
+}
+
+func TestNamespaceService_Delete(t *testing.T) {
+	t.Parallel()
+	f := newNamespaceFixture(t)
+	_, err := f.svc.Save(workflow.NewNamespace("billing", ""))
+	require.NoError(t, err)
+	_, err = f.svc.Save(workflow.NewNamespace("shipping", ""))
+	require.NoError(t, err)
+	f.saveSchema(t, "billing:invoice")
+
+	assert.ErrorIs(t, f.svc.Delete(workflow.DefaultNamespaceName), ErrDefaultNamespace)
+	assert.ErrorIs(t, f.svc.Delete("billing"), ErrNamespaceInUse)
+	assert.ErrorIs(t, f.svc.Delete("unknown"), repositories.ErrNamespaceNotFound)
+	require.NoError(t, f.svc.Delete("shipping"))
+	assert.False(t, f.svc.IsValid("shipping"))
+}
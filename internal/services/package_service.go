@@ -1,10 +1,12 @@
 package services
 
 import (
+	"context"
 	"sync"
 
 	"github.com/rs/zerolog/log"
 
+	"github.com/open-source-cloud/fuse/internal/audit"
 	"github.com/open-source-cloud/fuse/internal/packages"
 	"github.com/open-source-cloud/fuse/internal/repositories"
 	"github.com/open-source-cloud/fuse/pkg/workflow"
@@ -19,7 +21,7 @@ type (
 	PackageService interface {
 		FindAll(opts PackageOptions) ([]*workflow.Package, error)
 		FindByID(id string, opts PackageOptions) (*workflow.Package, error)
-		Save(pkg *workflow.Package) (*workflow.Package, error)
+		Save(ctx context.Context, pkg *workflow.Package) (*workflow.Package, error)
 		RegisterInternalPackages() error
 	}
 	// DefaultPackageService is the default implementation of the PackageService interface
@@ -28,15 +30,22 @@ type (
 		packageRepo      repositories.PackageRepository
 		packageRegistry  packages.Registry
 		internalPackages packages.InternalPackages
+		audit            AuditService
 	}
 )
 
 // NewPackageService returns a new PackageService
-func NewPackageService(packageRepo repositories.PackageRepository, packageRegistry packages.Registry, internalPackages packages.InternalPackages) PackageService {
+func NewPackageService(
+	packageRepo repositories.PackageRepository,
+	packageRegistry packages.Registry,
+	internalPackages packages.InternalPackages,
+	auditService AuditService,
+) PackageService {
 	return &DefaultPackageService{
 		packageRepo:      packageRepo,
 		packageRegistry:  packageRegistry,
 		internalPackages: internalPackages,
+		audit:            auditService,
 	}
 }
 
@@ -77,17 +86,34 @@ func (s *DefaultPackageService) FindByID(id string, opts PackageOptions) (*workf
 }
 
 // Save saves a package to the repository and registry if it is not already registered
-func (s *DefaultPackageService) Save(pkg *workflow.Package) (*workflow.Package, error) {
+func (s *DefaultPackageService) Save(ctx context.Context, pkg *workflow.Package) (*workflow.Package, error) {
 	if err := pkg.Validate(); err != nil {
 		return nil, err
 	}
+	if err := workflow.ValidateQualifiedID(pkg.ID); err != nil {
+		return nil, err
+	}
 
+	beforeHash := ""
+	if prev, err := s.packageRepo.FindByID(pkg.ID); err == nil {
+		beforeHash = audit.Hash(prev)
+	}
 	if err := s.packageRepo.Save(pkg); err != nil {
 		return nil, err
 	}
 
 	s.packageRegistry.Register(pkg)
 
+	namespace, localID := workflow.SplitQualifiedID(pkg.ID)
+	recordAudit(ctx, s.audit, audit.Entry{
+		Action:       audit.ActionPackageRegister,
+		ResourceType: audit.ResourcePackage,
+		Resource:     localID,
+		Namespace:    namespace,
+		BeforeHash:   beforeHash,
+		AfterHash:    audit.Hash(pkg),
+	})
+
 	return pkg, nil
 }
 
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/open-source-cloud/fuse/internal/auth"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	// ErrBuiltinRole is returned when the built-in admin role is modified or deleted
+	ErrBuiltinRole = errors.New("the built-in admin role cannot be modified")
+	// ErrInvalidRoleBinding is returned when a role binding has no subject
+	ErrInvalidRoleBinding = errors.New("invalid role binding")
+)
+
+type (
+	// PolicyService manages RBAC roles and role bindings. The built-in admin role is listed and
+	// bindable like a stored role but cannot be changed.
+	PolicyService interface {
+		FindRole(name string) (*auth.Role, error)
+		FindAllRoles() ([]*auth.Role, error)
+		SaveRole(role *auth.Role) (*auth.Role, error)
+		DeleteRole(name string) error
+		// FindBindings returns all role bindings, or those of subject when it is not empty.
+		FindBindings(subject string) ([]*auth.RoleBinding, error)
+		Bind(subject, role string) (*auth.RoleBinding, error)
+		Unbind(subject, role string) error
+	}
+
+	// DefaultPolicyService is the default PolicyService implementation.
+	DefaultPolicyService struct {
+		repo repositories.PolicyRepository
+	}
+)
+
+// NewPolicyService returns a new PolicyService.
+func NewPolicyService(repo repositories.PolicyRepository) PolicyService {
+	return &DefaultPolicyService{repo: repo}
+}
+
+// FindRole returns a role by name, including the built-in admin role.
+func (s *DefaultPolicyService) FindRole(name string) (*auth.Role, error) {
+	if name == auth.AdminRole {
+		return auth.BuiltinAdminRole(), nil
+	}
+	return s.repo.FindRole(name)
+}
+
+// FindAllRoles returns the built-in admin role followed by the stored roles.
+func (s *DefaultPolicyService) FindAllRoles() ([]*auth.Role, error) {
+	roles, err := s.repo.FindAllRoles()
+	if err != nil {
+		return nil, err
+	}
+	return append([]*auth.Role{auth.BuiltinAdminRole()}, roles...), nil
+}
+
+// SaveRole validates and upserts a role, keeping the creation time of an existing role.
+func (s *DefaultPolicyService) SaveRole(role *auth.Role) (*auth.Role, error) {
+	if role.Name == auth.AdminRole {
+		return nil, ErrBuiltinRole
+	}
+	if err := role.Validate(); err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	role.CreatedAt, role.UpdatedAt = now, now
+	existing, err := s.repo.FindRole(role.Name)
+	switch {
+	case err == nil:
+		role.CreatedAt = existing.CreatedAt
+	case !errors.Is(err, repositories.ErrRoleNotFound):
+		return nil, err
+	}
+	if err := s.repo.SaveRole(role); err != nil {
+		return nil, err
+	}
+	log.Info().Str("role", role.Name).Msg("role saved")
+	return role, nil
+}
+
+// DeleteRole deletes a role and unbinds it from every subject.
+func (s *DefaultPolicyService) DeleteRole(name string) error {
+	if name == auth.AdminRole {
+		return ErrBuiltinRole
+	}
+	if err := s.repo.DeleteRole(name); err != nil {
+		return err
+	}
+	log.Info().Str("role", name).Msg("role deleted")
+	return nil
+}
+
+// FindBindings returns all role bindings, or those of subject when it is not empty.
+func (s *DefaultPolicyService) FindBindings(subject string) ([]*auth.RoleBinding, error) {
+	if subject != "" {
+		return s.repo.FindBindingsBySubject(subject)
+	}
+	return s.repo.FindAllBindings()
+}
+
+// Bind grants an existing role to a subject; binding twice is a no-op.
+func (s *DefaultPolicyService) Bind(subject, role string) (*auth.RoleBinding, error) {
+	subject = strings.TrimSpace(subject)
+	if subject == "" {
+		return nil, fmt.Errorf("%w: subject is required", ErrInvalidRoleBinding)
+	}
+	if _, err := s.FindRole(role); err != nil {
+		return nil, err
+	}
+	binding := &auth.RoleBinding{Subject: subject, Role: role, CreatedAt: time.Now().UTC()}
+	if err := s.repo.SaveBinding(binding); err != nil {
+		return nil, err
+	}
+	log.Info().Str("subject", subject).Str("role", role).Msg("role bound")
+	return binding, nil
+}
+
+// Unbind revokes a role from a subject.
+func (s *DefaultPolicyService) Unbind(subject, role string) error {
+	if err := s.repo.DeleteBinding(subject, role); err != nil {
+		return err
+	}
+	log.Info().Str("subject", subject).Str("role", role).Msg("role unbound")
+	return nil
+}
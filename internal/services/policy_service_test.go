@@ -0,0 +1,68 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/open-source-cloud/fuse/internal/auth"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyService_AuthorizesThroughBindings(t *testing.T) {
+	t.Parallel()
+	repo := repositories.NewMemoryPolicyRepository()
+	svc := NewPolicyService(repo)
+	rbac := auth.NewRBAC(repo, nil)
+
+	_, err := svc.SaveRole(&auth.Role{
+		Name: "billing-operator",
+		Rules: []auth.Rule{{
+			Permissions:  []auth.Permission{auth.PermWorkflowTrigger, auth.PermSchemaWrite},
+			Schemas:      []string{"billing-*"},
+			Environments: []string{"staging"},
+		}},
+	})
+	require.NoError(t, err)
+	_, err = svc.Bind("apikey:team", "billing-operator")
+	require.NoError(t, err)
+	team := &auth.Principal{Subject: "apikey:team"}
+
+	assert.NoError(t, rbac.Authorize(team, auth.PermWorkflowTrigger, auth.Resource{SchemaID: "billing-invoices", Environment: "staging"}))
+	assert.ErrorIs(t, rbac.Authorize(team, auth.PermWorkflowTrigger, auth.Resource{SchemaID: "billing-invoices", Environment: "prod"}), auth.ErrForbidden)
+	assert.ErrorIs(t, rbac.Authorize(team, auth.PermWorkflowTrigger, auth.Resource{SchemaID: "shipping", Environment: "staging"}), auth.ErrForbidden)
+	assert.ErrorIs(t, rbac.Authorize(team, auth.PermCredentialWrite, auth.Resource{Environment: "prod"}), auth.ErrForbidden)
+
+	_, err = svc.Bind("apikey:team", auth.AdminRole)
+	require.NoError(t, err)
+	assert.NoError(t, rbac.Authorize(team, auth.PermCredentialWrite, auth.Resource{Environment: "prod"}))
+
+	require.NoError(t, svc.DeleteRole("billing-operator"))
+	bindings, err := svc.FindBindings("apikey:team")
+	require.NoError(t, err)
+	require.Len(t, bindings, 1)
+	assert.Equal(t, auth.AdminRole, bindings[0].Role)
+}
+
+func TestPolicyService_Validates(t *testing.T) {
+	t.Parallel()
+	svc := NewPolicyService(repositories.NewMemoryPolicyRepository())
+
+	_, err := svc.SaveRole(&auth.Role{Name: auth.AdminRole, Rules: []auth.Rule{{Permissions: []auth.Permission{auth.PermAll}}}})
+	assert.ErrorIs(t, err, ErrBuiltinRole)
+	assert.ErrorIs(t, svc.DeleteRole(auth.AdminRole), ErrBuiltinRole)
+
+	_, err = svc.SaveRole(&auth.Role{Name: "r", Rules: []auth.Rule{{Permissions: []auth.Permission{"schema:destroy"}}}})
+	assert.ErrorIs(t, err, auth.ErrInvalidRole)
+	_, err = svc.SaveRole(&auth.Role{Name: "r", Rules: []auth.Rule{{Permissions: []auth.Permission{auth.PermSchemaWrite}, Schemas: []string{"[a-"}}}})
+	assert.ErrorIs(t, err, auth.ErrInvalidRole)
+
+	_, err = svc.Bind("", auth.AdminRole)
+	assert.ErrorIs(t, err, ErrInvalidRoleBinding)
+	_, err = svc.Bind("user-1", "unknown")
+	assert.ErrorIs(t, err, repositories.ErrRoleNotFound)
+
+	roles, err := svc.FindAllRoles()
+	require.NoError(t, err)
+	assert.Equal(t, auth.AdminRole, roles[0].Name)
+}
@@ -0,0 +1,291 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/internal/workflow"
+	"github.com/open-source-cloud/fuse/pkg/secrets"
+	pkgworkflow "github.com/open-source-cloud/fuse/pkg/workflow"
+)
+
+// ErrPromoteToSameEnvironment is returned when a promotion's source and target are the same.
+var ErrPromoteToSameEnvironment = errors.New("source and target environments must differ")
+
+// Variable differences reported by a promotion plan.
+const (
+	// VarDiffMissing variables are set in the source only; applying the plan copies them.
+	VarDiffMissing = "missing"
+	// VarDiffChanged variables have another value in the target; applying the plan copies them
+	// only with overwrite.
+	VarDiffChanged = "changed"
+	// VarDiffTargetOnly variables are set in the target only and are left alone.
+	VarDiffTargetOnly = "target-only"
+)
+
+type (
+	// PromotionService compares two environments of a namespace and copies what can be copied from
+	// one to the other. Schemas and credential metadata are shared by every environment of a
+	// namespace, so a promotion copies variables and reports the secret values the target lacks.
+	PromotionService interface {
+		// Plan reports the differences between from and to without changing anything.
+		Plan(ctx context.Context, namespace, from, to string) (*PromotionPlan, error)
+		// Apply copies the variables missing from to (and, with overwrite, those that differ)
+		// and returns the plan of what is still missing afterwards.
+		Apply(ctx context.Context, namespace, from, to string, overwrite bool) (*PromotionPlan, error)
+	}
+
+	// PromotionPlan is the outcome of comparing two environments.
+	PromotionPlan struct {
+		Namespace string `json:"namespace"`
+		From      string `json:"from"`
+		To        string `json:"to"`
+		// Applied lists the variables copied to the target; empty for a plan.
+		Applied     []string          `json:"applied,omitempty"`
+		Variables   []VariableDiff    `json:"variables"`
+		Credentials []CredentialGap   `json:"credentials"`
+		Schemas     []SchemaReadiness `json:"schemas"`
+		// Deployable is true when every schema can resolve all of its references in the target.
+		Deployable bool `json:"deployable"`
+	}
+
+	// VariableDiff is a variable whose value differs between the environments. Variables are not
+	// secret, so both values are reported.
+	VariableDiff struct {
+		Name   string `json:"name"`
+		Status string `json:"status"`
+		From   string `json:"from,omitempty"`
+		To     string `json:"to,omitempty"`
+	}
+
+	// CredentialGap is a credential with fields that have no value in the target.
+	CredentialGap struct {
+		ID            string   `json:"id"`
+		Type          string   `json:"type"`
+		MissingFields []string `json:"missingFields"`
+	}
+
+	// SchemaReadiness reports whether a schema's active version can resolve its secrets,
+	// credential fields and variables in the target.
+	SchemaReadiness struct {
+		SchemaID           string   `json:"schemaId"`
+		Deployable         bool     `json:"deployable"`
+		MissingSecrets     []string `json:"missingSecrets,omitempty"`
+		MissingCredentials []string `json:"missingCredentials,omitempty"`
+		MissingVars        []string `json:"missingVars,omitempty"`
+	}
+
+	// DefaultPromotionService is the default PromotionService implementation.
+	DefaultPromotionService struct {
+		graphRepo      repositories.GraphRepository
+		credentialRepo repositories.CredentialRepository
+		store          secrets.SecretStore
+		environments   EnvironmentService
+		vars           EnvironmentVarService
+	}
+)
+
+// NewPromotionService returns a new PromotionService.
+func NewPromotionService(
+	graphRepo repositories.GraphRepository,
+	credentialRepo repositories.CredentialRepository,
+	store secrets.SecretStore,
+	environments EnvironmentService,
+	vars EnvironmentVarService,
+) PromotionService {
+	return &DefaultPromotionService{
+		graphRepo:      graphRepo,
+		credentialRepo: credentialRepo,
+		store:          store,
+		environments:   environments,
+		vars:           vars,
+	}
+}
+
+// Plan compares the variables of both environments, then checks the target for every credential
+// field and for the references of the namespace's schemas that accept executions.
+func (s *DefaultPromotionService) Plan(ctx context.Context, namespace, from, to string) (*PromotionPlan, error) {
+	if from == to {
+		return nil, ErrPromoteToSameEnvironment
+	}
+	if !s.environments.IsValid(from) || !s.environments.IsValid(to) {
+		return nil, repositories.ErrEnvironmentNotFound
+	}
+	plan := &PromotionPlan{
+		Namespace:   namespace,
+		From:        from,
+		To:          to,
+		Variables:   []VariableDiff{},
+		Credentials: []CredentialGap{},
+		Schemas:     []SchemaReadiness{},
+		Deployable:  true,
+	}
+
+	fromVars, err := s.vars.FindAll(namespace, from)
+	if err != nil {
+		return nil, fmt.Errorf("promotion: variables of %s: %w", from, err)
+	}
+	toVars, err := s.vars.FindAll(namespace, to)
+	if err != nil {
+		return nil, fmt.Errorf("promotion: variables of %s: %w", to, err)
+	}
+	plan.Variables = diffVars(fromVars, toVars)
+
+	scope := secrets.Scope{Namespace: namespace, Environment: to}
+	creds, err := s.credentialRepo.FindAll(namespace)
+	if err != nil {
+		return nil, fmt.Errorf("promotion: credentials: %w", err)
+	}
+	credTypes := make(map[string]string, len(creds))
+	for _, cred := range creds {
+		credTypes[cred.ID] = cred.Type
+		var missing []string
+		for _, field := range cred.Fields {
+			ok, err := s.hasSecret(ctx, scope, secrets.CredentialSecretName(cred.ID, field))
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				missing = append(missing, field)
+			}
+		}
+		if len(missing) > 0 {
+			plan.Credentials = append(plan.Credentials, CredentialGap{ID: cred.ID, Type: cred.Type, MissingFields: missing})
+		}
+	}
+
+	items, err := s.graphRepo.List()
+	if err != nil {
+		return nil, fmt.Errorf("promotion: list schemas: %w", err)
+	}
+	for _, item := range items {
+		if ns, _ := pkgworkflow.SplitQualifiedID(item.SchemaID); ns != namespace || !item.Lifecycle.AcceptsExecutions() {
+			continue
+		}
+		graph, err := s.graphRepo.FindByID(item.SchemaID)
+		if err != nil {
+			return nil, fmt.Errorf("promotion: schema %s: %w", item.SchemaID, err)
+		}
+		readiness, err := s.schemaReadiness(ctx, scope, graph, credTypes, toVars)
+		if err != nil {
+			return nil, err
+		}
+		plan.Deployable = plan.Deployable && readiness.Deployable
+		plan.Schemas = append(plan.Schemas, readiness)
+	}
+	return plan, nil
+}
+
+// Apply copies variables from the source to the target, then plans again so the result reports
+// what is still missing.
+func (s *DefaultPromotionService) Apply(ctx context.Context, namespace, from, to string, overwrite bool) (*PromotionPlan, error) {
+	plan, err := s.Plan(ctx, namespace, from, to)
+	if err != nil {
+		return nil, err
+	}
+	var applied []string
+	for _, diff := range plan.Variables {
+		if diff.Status == VarDiffMissing || (overwrite && diff.Status == VarDiffChanged) {
+			if err := s.vars.Set(ctx, namespace, to, diff.Name, diff.From); err != nil {
+				return nil, fmt.Errorf("promotion: copy variable %s: %w", diff.Name, err)
+			}
+			applied = append(applied, diff.Name)
+		}
+	}
+	if plan, err = s.Plan(ctx, namespace, from, to); err != nil {
+		return nil, err
+	}
+	plan.Applied = applied
+	return plan, nil
+}
+
+func (s *DefaultPromotionService) schemaReadiness(ctx context.Context, scope secrets.Scope, graph *workflow.Graph, credTypes, vars map[string]string) (SchemaReadiness, error) {
+	_, localID := pkgworkflow.SplitQualifiedID(graph.ID())
+	schema := graph.Schema()
+	refs := schema.References()
+	readiness := SchemaReadiness{SchemaID: localID}
+	for _, name := range refs.Secrets {
+		ok, err := s.hasSecret(ctx, scope, name)
+		if err != nil {
+			return readiness, err
+		}
+		if !ok {
+			readiness.MissingSecrets = append(readiness.MissingSecrets, name)
+		}
+	}
+	for _, ref := range refs.Credentials {
+		ok, err := s.hasCredentialField(ctx, scope, credTypes, ref)
+		if err != nil {
+			return readiness, err
+		}
+		if !ok {
+			readiness.MissingCredentials = append(readiness.MissingCredentials, ref)
+		}
+	}
+	for _, name := range refs.Vars {
+		if _, ok := vars[name]; !ok {
+			readiness.MissingVars = append(readiness.MissingVars, name)
+		}
+	}
+	readiness.Deployable = len(readiness.MissingSecrets) == 0 && len(readiness.MissingCredentials) == 0 && len(readiness.MissingVars) == 0
+	return readiness, nil
+}
+
+// hasCredentialField reports whether the "<id>.<field>" reference resolves in scope. The computed
+// access token of an OAuth2 credential counts as present when its grant's fields are, so planning
+// never contacts a token endpoint.
+func (s *DefaultPromotionService) hasCredentialField(ctx context.Context, scope secrets.Scope, credTypes map[string]string, ref string) (bool, error) {
+	id, field, valid := workflow.SplitCredentialRef(ref)
+	if !valid {
+		return false, nil
+	}
+	fields := []string{field}
+	if required, isOAuth2 := secrets.OAuth2RequiredFields(credTypes[id]); isOAuth2 && field == secrets.OAuth2FieldAccessToken {
+		fields = required
+	}
+	for _, f := range fields {
+		if ok, err := s.hasSecret(ctx, scope, secrets.CredentialSecretName(id, f)); err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// hasSecret reports whether name resolves in scope. The value is discarded; a plan never carries
+// secret values.
+func (s *DefaultPromotionService) hasSecret(ctx context.Context, scope secrets.Scope, name string) (bool, error) {
+	_, err := s.store.Resolve(ctx, scope, name)
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, secrets.ErrSecretNotFound):
+		return false, nil
+	default:
+		return false, fmt.Errorf("promotion: resolve %s in %s: %w", name, scope.Environment, err)
+	}
+}
+
+// diffVars returns the variables that differ between from and to, sorted by name.
+func diffVars(from, to map[string]string) []VariableDiff {
+	diffs := []VariableDiff{}
+	for name, value := range from {
+		target, ok := to[name]
+		switch {
+		case !ok:
+			diffs = append(diffs, VariableDiff{Name: name, Status: VarDiffMissing, From: value})
+		case target != value:
+			diffs = append(diffs, VariableDiff{Name: name, Status: VarDiffChanged, From: value, To: target})
+		}
+	}
+	for name, value := range to {
+		if _, ok := from[name]; !ok {
+			diffs = append(diffs, VariableDiff{Name: name, Status: VarDiffTargetOnly, To: value})
+		}
+	}
+	slices.SortFunc(diffs, func(a, b VariableDiff) int { return strings.Compare(a.Name, b.Name) })
+	return diffs
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/open-source-cloud/fuse/internal/mocks"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/internal/workflow"
+	"github.com/open-source-cloud/fuse/pkg/secrets"
+	pkgworkflow "github.com/open-source-cloud/fuse/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromotionService_PlanAndApply(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	ns := pkgworkflow.DefaultNamespaceName
+
+	envRepo := repositories.NewMemoryEnvironmentRepository()
+	require.NoError(t, envRepo.Save(pkgworkflow.NewEnvironment("staging", "")))
+	require.NoError(t, envRepo.Save(pkgworkflow.NewEnvironment("prod", "")))
+	environments := NewEnvironmentService(envRepo, nil)
+	vars := NewEnvironmentVarService(repositories.NewMemoryEnvironmentVarRepository(), environments, nil)
+
+	schema := mocks.SmallTestGraphSchema()
+	schema.Edges[0].Input = append(schema.Edges[0].Input,
+		workflow.InputMapping{Source: workflow.SourceSchema, Value: "{{var:BASE_URL}}?k={{secret:api-key}}", MapTo: "url"},
+		workflow.InputMapping{Source: workflow.SourceCredential, Variable: "crm.accessToken", MapTo: "token"},
+	)
+	graph, err := workflow.NewGraph(schema)
+	require.NoError(t, err)
+	graphRepo := repositories.NewMemoryGraphRepository()
+	require.NoError(t, graphRepo.Save(graph))
+
+	credRepo := repositories.NewMemoryCredentialRepository()
+	require.NoError(t, credRepo.Save(ns, pkgworkflow.NewCredential("crm", secrets.CredentialTypeOAuth2ClientCredentials, "",
+		[]string{"tokenUrl", "clientId", "clientSecret"})))
+	store := secrets.NewMemorySecretStore()
+	prod := secrets.Scope{Environment: "prod"}
+	require.NoError(t, store.Set(ctx, prod, "api-key", "k"))
+	require.NoError(t, store.Set(ctx, prod, secrets.CredentialSecretName("crm", "tokenUrl"), "https://auth.example.com/token"))
+	require.NoError(t, store.Set(ctx, prod, secrets.CredentialSecretName("crm", "clientId"), "app"))
+
+	require.NoError(t, vars.Set(ctx, ns, "staging", "BASE_URL", "https://staging.example.com"))
+	require.NoError(t, vars.Set(ctx, ns, "staging", "REGION", "eu"))
+	require.NoError(t, vars.Set(ctx, ns, "prod", "REGION", "us"))
+	require.NoError(t, vars.Set(ctx, ns, "prod", "PROD_ONLY", "x"))
+
+	svc := NewPromotionService(graphRepo, credRepo, store, environments, vars)
+
+	plan, err := svc.Plan(ctx, ns, "staging", "prod")
+	require.NoError(t, err)
+	assert.Equal(t, []VariableDiff{
+		{Name: "BASE_URL", Status: VarDiffMissing, From: "https://staging.example.com"},
+		{Name: "PROD_ONLY", Status: VarDiffTargetOnly, To: "x"},
+		{Name: "REGION", Status: VarDiffChanged, From: "eu", To: "us"},
+	}, plan.Variables)
+	assert.Equal(t, []CredentialGap{{ID: "crm", Type: secrets.CredentialTypeOAuth2ClientCredentials, MissingFields: []string{"clientSecret"}}}, plan.Credentials)
+	require.Len(t, plan.Schemas, 1)
+	assert.Equal(t, SchemaReadiness{
+		SchemaID:           "test",
+		MissingCredentials: []string{"crm.accessToken"},
+		MissingVars:        []string{"BASE_URL"},
+	}, plan.Schemas[0], "the computed access token needs the grant's fields")
+	assert.False(t, plan.Deployable)
+	assert.Empty(t, plan.Applied)
+
+	require.NoError(t, store.Set(ctx, prod, secrets.CredentialSecretName("crm", "clientSecret"), "s"))
+	applied, err := svc.Apply(ctx, ns, "staging", "prod", false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BASE_URL"}, applied.Applied, "differing values are kept without overwrite")
+	assert.True(t, applied.Deployable)
+	assert.Empty(t, applied.Credentials)
+	prodVars, err := vars.FindAll(ns, "prod")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"BASE_URL": "https://staging.example.com", "REGION": "us", "PROD_ONLY": "x"}, prodVars)
+
+	applied, err = svc.Apply(ctx, ns, "staging", "prod", true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"REGION"}, applied.Applied)
+
+	_, err = svc.Plan(ctx, ns, "staging", "staging")
+	require.ErrorIs(t, err, ErrPromoteToSameEnvironment)
+	_, err = svc.Plan(ctx, ns, "staging", "qa")
+	require.ErrorIs(t, err, repositories.ErrEnvironmentNotFound)
+}
@@ -0,0 +1,282 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/open-source-cloud/fuse/internal/app/config"
+	"github.com/open-source-cloud/fuse/internal/metrics"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/internal/workflow"
+	"github.com/open-source-cloud/fuse/pkg/objectstore"
+	"github.com/rs/zerolog/log"
+)
+
+// Resources reported by a purge (also the values of the fuse_retention_purged_rows_total label).
+const (
+	PurgeResourceWorkflows  = "workflows"
+	PurgeResourceJournal    = "journal_entries"
+	PurgeResourceTraces     = "execution_traces"
+	PurgeResourceAwakeables = "awakeables"
+	PurgeResourceSnapshots  = "snapshots"
+	PurgeResourceLLMSpend   = "llm_spend"
+	PurgeResourceSessions   = "agent_sessions"
+)
+
+type (
+	// RetentionService enforces the retention policies of terminal executions: the global policy
+	// from RETENTION_* config and the per-schema "retention" overrides. It also removes expired
+	// agent sessions.
+	RetentionService interface {
+		// Purge deletes every terminal execution that exceeds its policy. With dryRun it only
+		// reports what would be deleted.
+		Purge(ctx context.Context, dryRun bool) (*PurgeReport, error)
+	}
+
+	// PurgeReport summarises a purge run.
+	PurgeReport struct {
+		DryRun bool `json:"dryRun"`
+		// Executions counts purged (or purgeable) executions per schema, sub-workflows included.
+		Executions map[string]int `json:"executions"`
+		// Rows counts deleted rows and objects per resource; empty on dry runs.
+		Rows map[string]int64 `json:"rows"`
+		// Skipped counts root executions kept because a sub-workflow is still in flight.
+		Skipped int `json:"skipped"`
+	}
+
+	// DefaultRetentionService is the default RetentionService implementation.
+	DefaultRetentionService struct {
+		config        *config.Config
+		graphRepo     repositories.GraphRepository
+		workflowRepo  repositories.WorkflowRepository
+		journalRepo   repositories.JournalRepository
+		traceRepo     repositories.TraceRepository
+		awakeableRepo repositories.AwakeableRepository
+		spendRepo     repositories.LLMSpendRepository
+		sessionRepo   repositories.AgentSessionRepository
+		store         objectstore.ObjectStore
+		metrics       *metrics.FuseMetrics
+	}
+)
+
+// NewRetentionService returns a new RetentionService.
+func NewRetentionService(
+	cfg *config.Config,
+	graphRepo repositories.GraphRepository,
+	workflowRepo repositories.WorkflowRepository,
+	journalRepo repositories.JournalRepository,
+	traceRepo repositories.TraceRepository,
+	awakeableRepo repositories.AwakeableRepository,
+	spendRepo repositories.LLMSpendRepository,
+	sessionRepo repositories.AgentSessionRepository,
+	store objectstore.ObjectStore,
+	fuseMetrics *metrics.FuseMetrics,
+) RetentionService {
+	return &DefaultRetentionService{
+		config:        cfg,
+		graphRepo:     graphRepo,
+		workflowRepo:  workflowRepo,
+		journalRepo:   journalRepo,
+		traceRepo:     traceRepo,
+		awakeableRepo: awakeableRepo,
+		spendRepo:     spendRepo,
+		sessionRepo:   sessionRepo,
+		store:         store,
+		metrics:       fuseMetrics,
+	}
+}
+
+// GlobalRetentionPolicy builds the engine-wide retention policy from RETENTION_* config.
+func GlobalRetentionPolicy(cfg config.RetentionConfig) *workflow.RetentionPolicy {
+	toTrace := func(maxAge time.Duration, maxCount int) workflow.TraceRetentionConfig {
+		return workflow.TraceRetentionConfig{MaxAge: workflow.FlexibleDuration(maxAge), MaxCount: maxCount}
+	}
+	return &workflow.RetentionPolicy{
+		Default: toTrace(cfg.MaxAge, cfg.MaxCount),
+		States: map[workflow.State]workflow.TraceRetentionConfig{
+			workflow.StateFinished:  toTrace(cfg.Finished.MaxAge, cfg.Finished.MaxCount),
+			workflow.StateError:     toTrace(cfg.Error.MaxAge, cfg.Error.MaxCount),
+			workflow.StateCancelled: toTrace(cfg.Cancelled.MaxAge, cfg.Cancelled.MaxCount),
+		},
+	}
+}
+
+// Purge walks every schema and terminal state, selecting expired root executions in batches of
+// RETENTION_BATCH_SIZE. Each root is deleted together with its sub-workflow tree, and only once
+// every execution in that tree is terminal. Expired agent sessions are deleted afterwards (not on
+// dry runs: they already read as gone).
+func (s *DefaultRetentionService) Purge(ctx context.Context, dryRun bool) (*PurgeReport, error) {
+	report := &PurgeReport{
+		DryRun:     dryRun,
+		Executions: make(map[string]int),
+		Rows:       make(map[string]int64),
+	}
+
+	schemas, err := s.graphRepo.List()
+	if err != nil {
+		return report, fmt.Errorf("retention: list schemas: %w", err)
+	}
+
+	global := GlobalRetentionPolicy(s.config.Retention)
+	now := time.Now()
+	var errs []error
+	for _, item := range schemas {
+		var schemaPolicy *workflow.RetentionPolicy
+		if graph, findErr := s.graphRepo.FindByID(item.SchemaID); findErr == nil {
+			schemaPolicy = graph.Schema().Retention
+		}
+
+		for _, state := range workflow.RetentionStates {
+			if ctx.Err() != nil {
+				return report, ctx.Err()
+			}
+			limits := schemaPolicy.Resolve(state, global)
+			if limits.IsZero() {
+				continue
+			}
+			query := repositories.PurgeQuery{
+				SchemaID: item.SchemaID,
+				State:    state,
+				Keep:     limits.MaxCount,
+			}
+			if limits.MaxAge > 0 {
+				query.Before = now.Add(-limits.MaxAge.Duration())
+			}
+			if purgeErr := s.purgeQuery(ctx, query, report); purgeErr != nil {
+				errs = append(errs, fmt.Errorf("retention: schema %s state %s: %w", item.SchemaID, state, purgeErr))
+			}
+		}
+	}
+	if !dryRun {
+		deleted, sessionErr := s.sessionRepo.DeleteExpired(now)
+		s.record(report, PurgeResourceSessions, deleted)
+		if sessionErr != nil {
+			errs = append(errs, fmt.Errorf("retention: delete expired sessions: %w", sessionErr))
+		}
+	}
+	return report, errors.Join(errs...)
+}
+
+// purgeQuery drains a single schema/state query. A dry run reads all candidates in one go since
+// nothing is deleted between batches; a real run stops once a batch deletes nothing, so roots that
+// keep being skipped cannot loop forever.
+func (s *DefaultRetentionService) purgeQuery(ctx context.Context, query repositories.PurgeQuery, report *PurgeReport) error {
+	if !report.DryRun {
+		query.Limit = s.batchSize()
+	}
+	skipped := make(map[string]struct{})
+	for {
+		roots, err := s.workflowRepo.FindPurgeable(query)
+		if err != nil {
+			return err
+		}
+
+		batch := make([]string, 0, len(roots))
+		for _, rootID := range roots {
+			tree, complete, treeErr := s.collectTree(rootID)
+			if treeErr != nil {
+				return treeErr
+			}
+			if !complete {
+				if _, seen := skipped[rootID]; !seen {
+					skipped[rootID] = struct{}{}
+					report.Skipped++
+				}
+				continue
+			}
+			batch = append(batch, tree...)
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		report.Executions[query.SchemaID] += len(batch)
+		if report.DryRun {
+			return nil
+		}
+		if err := s.deleteBatch(ctx, batch, report); err != nil {
+			return err
+		}
+		if len(roots) < query.Limit {
+			return nil
+		}
+	}
+}
+
+// collectTree returns rootID and all of its descendant sub-workflows, children first. complete is
+// false when any descendant has not reached a terminal state yet (e.g. an async child still running).
+func (s *DefaultRetentionService) collectTree(rootID string) ([]string, bool, error) {
+	refs, err := s.workflowRepo.FindActiveSubWorkflows(rootID)
+	if err != nil {
+		return nil, false, err
+	}
+	var tree []string
+	for _, ref := range refs {
+		childID := ref.ChildWorkflowID.String()
+		if child, getErr := s.workflowRepo.Get(childID); getErr == nil && !slices.Contains(workflow.RetentionStates, child.State()) {
+			return nil, false, nil
+		}
+		subtree, complete, subErr := s.collectTree(childID)
+		if subErr != nil || !complete {
+			return nil, complete, subErr
+		}
+		tree = append(tree, subtree...)
+	}
+	return append(tree, rootID), true, nil
+}
+
+// deleteBatch removes a batch of executions across every repository. Dependent rows go first
+// because they reference the workflows table.
+func (s *DefaultRetentionService) deleteBatch(ctx context.Context, workflowIDs []string, report *PurgeReport) error {
+	var snapshots int64
+	for _, id := range workflowIDs {
+		ref, err := s.workflowRepo.GetSnapshotRef(id)
+		if err != nil || ref == "" {
+			continue
+		}
+		if err := s.store.Delete(ctx, ref); err != nil {
+			log.Warn().Err(err).Str("workflowID", id).Msg("retention: failed to delete execution snapshot")
+			continue
+		}
+		snapshots++
+	}
+	s.record(report, PurgeResourceSnapshots, snapshots)
+
+	steps := []struct {
+		resource string
+		delete   func([]string) (int64, error)
+	}{
+		{PurgeResourceJournal, s.journalRepo.DeleteByWorkflowIDs},
+		{PurgeResourceAwakeables, s.awakeableRepo.DeleteByWorkflowIDs},
+		{PurgeResourceTraces, s.traceRepo.DeleteByWorkflowIDs},
+		{PurgeResourceLLMSpend, s.spendRepo.DeleteByWorkflowIDs},
+		{PurgeResourceWorkflows, s.workflowRepo.Delete},
+	}
+	for _, step := range steps {
+		deleted, err := step.delete(workflowIDs)
+		s.record(report, step.resource, deleted)
+		if err != nil {
+			return fmt.Errorf("delete %s: %w", step.resource, err)
+		}
+	}
+	return nil
+}
+
+func (s *DefaultRetentionService) record(report *PurgeReport, resource string, n int64) {
+	if n == 0 {
+		return
+	}
+	report.Rows[resource] += n
+	if s.metrics != nil {
+		s.metrics.RetentionPurged.WithLabelValues(resource).Add(float64(n))
+	}
+}
+
+func (s *DefaultRetentionService) batchSize() int {
+	if s.config.Retention.BatchSize > 0 {
+		return s.config.Retention.BatchSize
+	}
+	return 500
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/open-source-cloud/fuse/internal/app/config"
+	"github.com/open-source-cloud/fuse/internal/mocks"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/internal/workflow"
+	"github.com/open-source-cloud/fuse/pkg/objectstore"
+	pkgworkflow "github.com/open-source-cloud/fuse/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type retentionFixture struct {
+	svc          RetentionService
+	graph        *workflow.Graph
+	workflowRepo repositories.WorkflowRepository
+	journalRepo  repositories.JournalRepository
+	spendRepo    repositories.LLMSpendRepository
+	sessionRepo  repositories.AgentSessionRepository
+	store        *objectstore.MemoryObjectStore
+}
+
+func newRetentionFixture(t *testing.T, retention config.RetentionConfig, schemaPolicy *workflow.RetentionPolicy) *retentionFixture {
+	t.Helper()
+	schema := mocks.SmallTestGraphSchema()
+	schema.Retention = schemaPolicy
+	graph, err := workflow.NewGraph(schema)
+	require.NoError(t, err)
+
+	graphRepo := repositories.NewMemoryGraphRepository()
+	require.NoError(t, graphRepo.Save(graph))
+
+	f := &retentionFixture{
+		graph:        graph,
+		workflowRepo: repositories.NewMemoryWorkflowRepository(),
+		journalRepo:  repositories.NewMemoryJournalRepository(),
+		spendRepo:    repositories.NewMemoryLLMSpendRepository(),
+		sessionRepo:  repositories.NewMemoryAgentSessionRepository(),
+		store:        objectstore.NewMemoryObjectStore(),
+	}
+	cfg := &config.Config{Retention: retention}
+	f.svc = NewRetentionService(cfg, graphRepo, f.workflowRepo, f.journalRepo,
+		repositories.NewMemoryTraceRepository(), repositories.NewMemoryAwakeableRepository(), f.spendRepo, f.sessionRepo, f.store, nil)
+	return f
+}
+
+func (f *retentionFixture) addWorkflow(t *testing.T, state workflow.State) string {
+	t.Helper()
+	wf := workflow.New(pkgworkflow.NewID(), f.graph, "default")
+	wf.SetState(state)
+	require.NoError(t, f.workflowRepo.Save(wf))
+	id := wf.ID().String()
+	require.NoError(t, f.journalRepo.Append(id, workflow.JournalEntry{Sequence: 1}))
+	_, err := f.spendRepo.AddExecution(id, repositories.LLMSpend{Tokens: 10})
+	require.NoError(t, err)
+	ref := "workflows/" + id + "/execution-snapshot.json"
+	require.NoError(t, f.store.Put(context.Background(), ref, []byte("{}")))
+	require.NoError(t, f.workflowRepo.SetSnapshotRef(id, ref))
+	return id
+}
+
+func TestRetentionService_Purge(t *testing.T) {
+	t.Parallel()
+
+	f := newRetentionFixture(t, config.RetentionConfig{MaxCount: 1}, nil)
+	f.addWorkflow(t, workflow.StateFinished)
+	f.addWorkflow(t, workflow.StateFinished)
+	running := f.addWorkflow(t, workflow.StateRunning)
+
+	report, err := f.svc.Purge(context.Background(), false)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Executions["test"])
+	assert.Equal(t, int64(1), report.Rows[PurgeResourceWorkflows])
+	assert.Equal(t, int64(1), report.Rows[PurgeResourceJournal])
+	assert.Equal(t, int64(1), report.Rows[PurgeResourceLLMSpend])
+	assert.Equal(t, int64(1), report.Rows[PurgeResourceSnapshots])
+	assert.True(t, f.workflowRepo.Exists(running))
+	remaining, err := f.workflowRepo.FindByState(workflow.StateFinished)
+	require.NoError(t, err)
+	assert.Len(t, remaining, 1)
+}
+
+func TestRetentionService_PurgeDryRun(t *testing.T) {
+	t.Parallel()
+
+	f := newRetentionFixture(t, config.RetentionConfig{MaxAge: time.Nanosecond}, nil)
+	id := f.addWorkflow(t, workflow.StateError)
+	time.Sleep(time.Millisecond)
+
+	report, err := f.svc.Purge(context.Background(), true)
+
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 1, report.Executions["test"])
+	assert.Empty(t, report.Rows)
+	assert.True(t, f.workflowRepo.Exists(id))
+}
+
+func TestRetentionService_PurgesExpiredSessions(t *testing.T) {
+	t.Parallel()
+
+	f := newRetentionFixture(t, config.RetentionConfig{}, nil)
+	now := time.Now()
+	require.NoError(t, f.sessionRepo.Save(&repositories.AgentSession{Namespace: "default", ID: "old", ExpiresAt: now.Add(-time.Hour)}))
+	require.NoError(t, f.sessionRepo.Save(&repositories.AgentSession{Namespace: "default", ID: "live", ExpiresAt: now.Add(time.Hour)}))
+
+	dry, err := f.svc.Purge(context.Background(), true)
+	require.NoError(t, err)
+	assert.Empty(t, dry.Rows)
+
+	report, err := f.svc.Purge(context.Background(), false)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), report.Rows[PurgeResourceSessions])
+	_, err = f.sessionRepo.Get("default", "live")
+	assert.NoError(t, err)
+}
+
+func TestRetentionService_PurgeSkipsRunningSubWorkflows(t *testing.T) {
+	t.Parallel()
+
+	f := newRetentionFixture(t, config.RetentionConfig{MaxAge: time.Nanosecond}, nil)
+	parent := f.addWorkflow(t, workflow.StateFinished)
+	child := f.addWorkflow(t, workflow.StateRunning)
+	require.NoError(t, f.workflowRepo.SaveSubWorkflowRef(&workflow.SubWorkflowRef{
+		ParentWorkflowID: pkgworkflow.ID(parent),
+		ChildWorkflowID:  pkgworkflow.ID(child),
+		ChildSchemaID:    "test",
+	}))
+	time.Sleep(time.Millisecond)
+
+	report, err := f.svc.Purge(context.Background(), false)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Skipped)
+	assert.Empty(t, report.Executions)
+	assert.True(t, f.workflowRepo.Exists(parent))
+	assert.True(t, f.workflowRepo.Exists(child))
+}
+
+func TestRetentionService_SchemaPolicyOverridesGlobal(t *testing.T) {
+	t.Parallel()
+
+	policy := &workflow.RetentionPolicy{
+		States: map[workflow.State]workflow.TraceRetentionConfig{
+			workflow.StateError: {MaxCount: 5},
+		},
+	}
+	f := newRetentionFixture(t, config.RetentionConfig{MaxCount: 1}, policy)
+	for range 3 {
+		f.addWorkflow(t, workflow.StateError)
+	}
+
+	report, err := f.svc.Purge(context.Background(), false)
+
+	require.NoError(t, err)
+	assert.Empty(t, report.Executions)
+	remaining, err := f.workflowRepo.FindByState(workflow.StateError)
+	require.NoError(t, err)
+	assert.Len(t, remaining, 3)
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/open-source-cloud/fuse/internal/events"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/internal/workflow"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	// ErrInvalidSchemaLifecycle is returned when transitioning to an unknown lifecycle state
+	ErrInvalidSchemaLifecycle = errors.New("invalid schema lifecycle")
+	// ErrSchemaNotAcceptingExecutions is returned when triggering a deprecated or archived schema
+	ErrSchemaNotAcceptingExecutions = errors.New("schema does not accept new executions")
+	// ErrSchemaHasExecutionsInFlight is returned when deleting a schema that still has executions in flight
+	ErrSchemaHasExecutionsInFlight = errors.New("schema has executions in flight")
+)
+
+// inFlightStates are the workflow states that block deleting a schema.
+var inFlightStates = []workflow.State{workflow.StateUntriggered, workflow.StateRunning, workflow.StateSleeping}
+
+type (
+	// SchemaLifecycleService manages the active → deprecated → archived lifecycle of workflow schemas
+	// and their deletion. Trigger actors follow lifecycle changes through the event bus; in cluster
+	// mode the changes are replicated to peers, which republish them on their own bus.
+	SchemaLifecycleService interface {
+		// Find returns the lifecycle state of a schema.
+		Find(schemaID string) (workflow.SchemaLifecycle, error)
+		// List returns the schemas in any of the given lifecycle states (every schema when none are given).
+		List(lifecycles ...workflow.SchemaLifecycle) ([]repositories.GraphSchemaListItem, error)
+		// Transition moves a schema to the given lifecycle state. Any transition is allowed,
+		// including reactivating an archived schema.
+		Transition(schemaID string, lifecycle workflow.SchemaLifecycle) error
+		// CheckAcceptsExecutions returns ErrSchemaNotAcceptingExecutions for deprecated and archived schemas.
+		CheckAcceptsExecutions(schemaID string) error
+		// Delete removes a schema and all of its versions. Unless force is set it refuses with
+		// ErrSchemaHasExecutionsInFlight while executions of the schema have not finished.
+		Delete(schemaID string, force bool) error
+	}
+
+	// DefaultSchemaLifecycleService is the default SchemaLifecycleService implementation.
+	DefaultSchemaLifecycleService struct {
+		graphRepo    repositories.GraphRepository
+		workflowRepo repositories.WorkflowRepository
+		eventBus     events.EventBus
+		publisher    SchemaUpsertPublisher
+	}
+)
+
+// NewSchemaLifecycleService returns a new SchemaLifecycleService.
+func NewSchemaLifecycleService(
+	graphRepo repositories.GraphRepository,
+	workflowRepo repositories.WorkflowRepository,
+	eventBus events.EventBus,
+	publisher SchemaUpsertPublisher,
+) SchemaLifecycleService {
+	return &DefaultSchemaLifecycleService{
+		graphRepo:    graphRepo,
+		workflowRepo: workflowRepo,
+		eventBus:     eventBus,
+		publisher:    publisher,
+	}
+}
+
+// Find returns the lifecycle state of a schema.
+func (s *DefaultSchemaLifecycleService) Find(schemaID string) (workflow.SchemaLifecycle, error) {
+	return s.graphRepo.FindLifecycle(schemaID)
+}
+
+// List returns the schemas in any of the given lifecycle states.
+func (s *DefaultSchemaLifecycleService) List(lifecycles ...workflow.SchemaLifecycle) ([]repositories.GraphSchemaListItem, error) {
+	items, err := s.graphRepo.List()
+	if err != nil || len(lifecycles) == 0 {
+		return items, err
+	}
+	return slices.DeleteFunc(items, func(item repositories.GraphSchemaListItem) bool {
+		return !slices.Contains(lifecycles, item.Lifecycle)
+	}), nil
+}
+
+// Transition moves a schema to the given lifecycle state and publishes EventSchemaLifecycleChanged.
+func (s *DefaultSchemaLifecycleService) Transition(schemaID string, lifecycle workflow.SchemaLifecycle) error {
+	if !lifecycle.IsValid() {
+		return fmt.Errorf("%w: %q", ErrInvalidSchemaLifecycle, lifecycle)
+	}
+	if err := s.graphRepo.SetLifecycle(schemaID, lifecycle); err != nil {
+		return err
+	}
+	log.Info().Str("schemaID", schemaID).Str("lifecycle", string(lifecycle)).Msg("schema lifecycle changed")
+	s.publish(schemaID, lifecycle, false)
+	return nil
+}
+
+// CheckAcceptsExecutions returns ErrSchemaNotAcceptingExecutions for deprecated and archived schemas.
+func (s *DefaultSchemaLifecycleService) CheckAcceptsExecutions(schemaID string) error {
+	lifecycle, err := s.graphRepo.FindLifecycle(schemaID)
+	if err != nil {
+		return err
+	}
+	if !lifecycle.AcceptsExecutions() {
+		return fmt.Errorf("%w: schema %s is %s", ErrSchemaNotAcceptingExecutions, schemaID, lifecycle)
+	}
+	return nil
+}
+
+// Delete removes a schema and publishes EventSchemaDeleted. A forced delete leaves in-flight
+// executions running on the graph they already loaded; they cannot be recovered after a restart.
+func (s *DefaultSchemaLifecycleService) Delete(schemaID string, force bool) error {
+	if _, err := s.graphRepo.FindLifecycle(schemaID); err != nil {
+		return err
+	}
+	if !force {
+		inFlight, err := s.countInFlight(schemaID)
+		if err != nil {
+			return err
+		}
+		if inFlight > 0 {
+			return fmt.Errorf("%w: %d execution(s) of schema %s", ErrSchemaHasExecutionsInFlight, inFlight, schemaID)
+		}
+	}
+	if err := s.graphRepo.Delete(schemaID); err != nil {
+		return err
+	}
+	log.Info().Str("schemaID", schemaID).Bool("force", force).Msg("schema deleted")
+	s.publish(schemaID, "", true)
+	return nil
+}
+
+func (s *DefaultSchemaLifecycleService) countInFlight(schemaID string) (int, error) {
+	total := 0
+	for _, state := range inFlightStates {
+		result, err := s.workflowRepo.FindExecutions(repositories.ExecutionListFilter{
+			SchemaID: schemaID,
+			Status:   state.String(),
+			Size:     1,
+		})
+		if err != nil {
+			return 0, err
+		}
+		total += result.Total
+	}
+	return total, nil
+}
+
+// publish notifies the local trigger actors and the cluster replication actor.
+func (s *DefaultSchemaLifecycleService) publish(schemaID string, lifecycle workflow.SchemaLifecycle, deleted bool) {
+	if s.publisher != nil {
+		s.publisher.PublishLocalLifecycle(schemaID, lifecycle, deleted)
+	}
+	if s.eventBus == nil {
+		return
+	}
+	event := SchemaLifecycleEvent(schemaID, lifecycle, deleted)
+	if err := s.eventBus.Publish(event); err != nil {
+		log.Warn().Err(err).Str("schemaID", schemaID).Msgf("failed to publish %s event", event.Type)
+	}
+}
+
+// SchemaLifecycleEvent builds the EventSchemaLifecycleChanged, or EventSchemaDeleted, bus event of
+// a schema.
+func SchemaLifecycleEvent(schemaID string, lifecycle workflow.SchemaLifecycle, deleted bool) events.Event {
+	eventType := events.EventSchemaLifecycleChanged
+	if deleted {
+		eventType = events.EventSchemaDeleted
+	}
+	data := map[string]any{"schemaId": schemaID}
+	if lifecycle != "" {
+		data["lifecycle"] = string(lifecycle)
+	}
+	return events.Event{
+		Type:   eventType,
+		Source: schemaID,
+		Data:   data,
+	}
+}
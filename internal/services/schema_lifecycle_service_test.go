@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ergo.services/ergo/gen"
+
+	"github.com/open-source-cloud/fuse/internal/events"
+	"github.com/open-source-cloud/fuse/internal/mocks"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/internal/workflow"
+	pkgworkflow "github.com/open-source-cloud/fuse/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSchemaLifecycleService(t *testing.T, bus events.EventBus) (SchemaLifecycleService, *workflow.Graph, repositories.WorkflowRepository) {
+	t.Helper()
+	graph, err := workflow.NewGraph(mocks.SmallTestGraphSchema())
+	require.NoError(t, err)
+	graphRepo := repositories.NewMemoryGraphRepository()
+	require.NoError(t, graphRepo.Save(graph))
+	workflowRepo := repositories.NewMemoryWorkflowRepository()
+	return NewSchemaLifecycleService(graphRepo, workflowRepo, bus, nil), graph, workflowRepo
+}
+
+func TestSchemaLifecycleService_Transition(t *testing.T) {
+	t.Parallel()
+
+	bus := events.NewMemoryBus(context.Background())
+	received := make(chan events.Event, 1)
+	_, err := bus.Subscribe(events.EventSchemaLifecycleChanged, func(event events.Event) error {
+		received <- event
+		return nil
+	})
+	require.NoError(t, err)
+	svc, graph, _ := newSchemaLifecycleService(t, bus)
+
+	require.NoError(t, svc.CheckAcceptsExecutions(graph.ID()))
+	require.NoError(t, svc.Transition(graph.ID(), workflow.SchemaDeprecated))
+
+	assert.ErrorIs(t, svc.CheckAcceptsExecutions(graph.ID()), ErrSchemaNotAcceptingExecutions)
+	select {
+	case event := <-received:
+		assert.Equal(t, graph.ID(), event.Data["schemaId"])
+		assert.Equal(t, string(workflow.SchemaDeprecated), event.Data["lifecycle"])
+	case <-time.After(time.Second):
+		t.Fatal("lifecycle event was not published")
+	}
+}
+
+func TestSchemaLifecycleService_TransitionRejectsUnknownState(t *testing.T) {
+	t.Parallel()
+
+	svc, graph, _ := newSchemaLifecycleService(t, nil)
+
+	err := svc.Transition(graph.ID(), "retired")
+
+	assert.ErrorIs(t, err, ErrInvalidSchemaLifecycle)
+}
+
+func TestSchemaLifecycleService_ListFiltersByLifecycle(t *testing.T) {
+	t.Parallel()
+
+	svc, graph, _ := newSchemaLifecycleService(t, nil)
+	require.NoError(t, svc.Transition(graph.ID(), workflow.SchemaArchived))
+
+	active, err := svc.List(workflow.SchemaActive)
+	require.NoError(t, err)
+	assert.Empty(t, active)
+
+	archived, err := svc.List(workflow.SchemaArchived)
+	require.NoError(t, err)
+	require.Len(t, archived, 1)
+	assert.Equal(t, graph.ID(), archived[0].SchemaID)
+
+	all, err := svc.List()
+	require.NoError(t, err)
+	assert.Len(t, all, 1)
+}
+
+func TestSchemaLifecycleService_Delete(t *testing.T) {
+	t.Parallel()
+
+	svc, graph, workflowRepo := newSchemaLifecycleService(t, nil)
+	running := workflow.New(pkgworkflow.NewID(), graph, "default")
+	running.SetState(workflow.StateRunning)
+	require.NoError(t, workflowRepo.Save(running))
+
+	err := svc.Delete(graph.ID(), false)
+	require.ErrorIs(t, err, ErrSchemaHasExecutionsInFlight)
+	_, err = svc.Find(graph.ID())
+	require.NoError(t, err)
+
+	require.NoError(t, svc.Delete(graph.ID(), true))
+	_, err = svc.Find(graph.ID())
+	assert.ErrorIs(t, err, repositories.ErrGraphNotFound)
+}
+
+func TestSchemaLifecycleService_DeleteWithFinishedExecutions(t *testing.T) {
+	t.Parallel()
+
+	svc, graph, workflowRepo := newSchemaLifecycleService(t, nil)
+	finished := workflow.New(pkgworkflow.NewID(), graph, "default")
+	finished.SetState(workflow.StateFinished)
+	require.NoError(t, workflowRepo.Save(finished))
+
+	require.NoError(t, svc.Delete(graph.ID(), false))
+	assert.ErrorIs(t, svc.Delete(graph.ID(), false), repositories.ErrGraphNotFound)
+}
+
+// recordingLifecyclePublisher records the lifecycle changes handed to the cluster replication actor.
+type recordingLifecyclePublisher struct {
+	changes []string
+}
+
+func (p *recordingLifecyclePublisher) PublishLocalUpsert(string, *workflow.GraphSchema) {}
+
+func (p *recordingLifecyclePublisher) BindNode(gen.Node) {}
+
+func (p *recordingLifecyclePublisher) PublishLocalLifecycle(schemaID string, lifecycle workflow.SchemaLifecycle, deleted bool) {
+	if deleted {
+		p.changes = append(p.changes, schemaID+" deleted")
+		return
+	}
+	p.changes = append(p.changes, schemaID+" "+string(lifecycle))
+}
+
+func TestSchemaLifecycleService_ReplicatesChanges(t *testing.T) {
+	t.Parallel()
+
+	graph, err := workflow.NewGraph(mocks.SmallTestGraphSchema())
+	require.NoError(t, err)
+	graphRepo := repositories.NewMemoryGraphRepository()
+	require.NoError(t, graphRepo.Save(graph))
+	publisher := &recordingLifecyclePublisher{}
+	svc := NewSchemaLifecycleService(graphRepo, repositories.NewMemoryWorkflowRepository(), nil, publisher)
+
+	require.NoError(t, svc.Transition(graph.ID(), workflow.SchemaArchived))
+	require.NoError(t, svc.Delete(graph.ID(), false))
+
+	assert.Equal(t, []string{graph.ID() + " archived", graph.ID() + " deleted"}, publisher.changes)
+}
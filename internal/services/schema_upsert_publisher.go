@@ -12,9 +12,11 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-// SchemaUpsertPublisher notifies the cluster replication actor after a local schema upsert.
+// SchemaUpsertPublisher notifies the cluster replication actor after a local schema upsert,
+// lifecycle change or deletion.
 type SchemaUpsertPublisher interface {
 	PublishLocalUpsert(schemaID string, schema *workflow.GraphSchema)
+	PublishLocalLifecycle(schemaID string, lifecycle workflow.SchemaLifecycle, deleted bool)
 	BindNode(node gen.Node)
 }
 
@@ -42,22 +44,39 @@ func (p *ErgoSchemaUpsertPublisher) PublishLocalUpsert(schemaID string, schema *
 	if p == nil || p.cfg == nil || !p.cfg.Cluster.Enabled {
 		return
 	}
-	p.mu.RLock()
-	n := p.node
-	p.mu.RUnlock()
-	if n == nil {
-		return
-	}
 	b, err := json.Marshal(schema)
 	if err != nil {
 		log.Error().Err(err).Str("schemaID", schemaID).Msg("schema replication: marshal failed")
 		return
 	}
-	msg := messaging.NewPublishGraphSchemaUpsertMessage(messaging.GraphSchemaReplicationPayload{
+	p.send(messaging.GraphSchemaReplicationPayload{
 		SchemaID:   schemaID,
 		SchemaJSON: b,
 	})
+}
+
+// PublishLocalLifecycle sends a lifecycle change, or a deletion, to the replication actor so peers
+// update their trigger registrations.
+func (p *ErgoSchemaUpsertPublisher) PublishLocalLifecycle(schemaID string, lifecycle workflow.SchemaLifecycle, deleted bool) {
+	if p == nil || p.cfg == nil || !p.cfg.Cluster.Enabled {
+		return
+	}
+	p.send(messaging.GraphSchemaReplicationPayload{
+		SchemaID:  schemaID,
+		Lifecycle: string(lifecycle),
+		Deleted:   deleted,
+	})
+}
+
+func (p *ErgoSchemaUpsertPublisher) send(payload messaging.GraphSchemaReplicationPayload) {
+	p.mu.RLock()
+	n := p.node
+	p.mu.RUnlock()
+	if n == nil {
+		return
+	}
+	msg := messaging.NewPublishGraphSchemaUpsertMessage(payload)
 	if err := n.Send(gen.Atom(actornames.SchemaReplicationActorName), msg); err != nil {
-		log.Warn().Err(err).Str("schemaID", schemaID).Msg("schema replication: send to actor failed")
+		log.Warn().Err(err).Str("schemaID", payload.SchemaID).Msg("schema replication: send to actor failed")
 	}
 }
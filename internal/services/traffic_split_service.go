@@ -0,0 +1,286 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/open-source-cloud/fuse/internal/events"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/internal/workflow"
+	"github.com/rs/zerolog/log"
+)
+
+// ErrInvalidTrafficSplit is returned when a traffic split fails validation or routes to unknown versions
+var ErrInvalidTrafficSplit = errors.New("invalid traffic split")
+
+type (
+	// TrafficSplitService manages canary traffic splits between schema versions, resolves the
+	// version each new execution runs on and rolls canaries back when they breach their rule.
+	TrafficSplitService interface {
+		// Find returns the traffic split of a schema, nil when none is configured.
+		Find(schemaID string) (*workflow.TrafficSplit, error)
+		// Set validates and stores a traffic split; every routed version must exist.
+		Set(schemaID string, split *workflow.TrafficSplit) error
+		// Clear removes the traffic split so every execution runs the active version again.
+		Clear(schemaID string) error
+		// ResolveVersion picks the version a new execution of the schema runs on. Zero means the
+		// schema has no split and the active version runs.
+		ResolveVersion(schemaID string, input map[string]any) (int, error)
+		// Stats returns completed and failed execution counts of every routed version since the
+		// split was configured.
+		Stats(schemaID string) ([]VersionStats, error)
+		// Start subscribes to workflow completion events to evaluate automatic rollback rules.
+		Start() error
+		// Stop unsubscribes from workflow completion events.
+		Stop()
+	}
+
+	// VersionStats summarises the executions of one routed version since the split was configured.
+	VersionStats struct {
+		Version   int     `json:"version"`
+		Weight    int     `json:"weight"`
+		Baseline  bool    `json:"baseline"`
+		Completed int     `json:"completed"`
+		Failed    int     `json:"failed"`
+		ErrorRate float64 `json:"errorRate"`
+	}
+
+	// DefaultTrafficSplitService is the default TrafficSplitService implementation.
+	DefaultTrafficSplitService struct {
+		graphRepo    repositories.GraphRepository
+		workflowRepo repositories.WorkflowRepository
+		graphService GraphService
+		eventBus     events.EventBus
+
+		// rollbackMu serialises rollback evaluation so concurrent failures roll back only once.
+		rollbackMu sync.Mutex
+		subs       []events.SubscriptionID
+	}
+)
+
+// NewTrafficSplitService returns a new TrafficSplitService.
+func NewTrafficSplitService(
+	graphRepo repositories.GraphRepository,
+	workflowRepo repositories.WorkflowRepository,
+	graphService GraphService,
+	eventBus events.EventBus,
+) TrafficSplitService {
+	return &DefaultTrafficSplitService{
+		graphRepo:    graphRepo,
+		workflowRepo: workflowRepo,
+		graphService: graphService,
+		eventBus:     eventBus,
+	}
+}
+
+// Find returns the traffic split of a schema, nil when none is configured.
+func (s *DefaultTrafficSplitService) Find(schemaID string) (*workflow.TrafficSplit, error) {
+	return s.graphRepo.FindTrafficSplit(schemaID)
+}
+
+// Set validates and stores a traffic split, resetting its statistics window.
+func (s *DefaultTrafficSplitService) Set(schemaID string, split *workflow.TrafficSplit) error {
+	if err := split.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidTrafficSplit, err)
+	}
+	versions, err := s.graphRepo.ListVersions(schemaID)
+	if err != nil {
+		return err
+	}
+	known := make(map[int]bool, len(versions))
+	for _, sv := range versions {
+		known[sv.Version] = true
+	}
+	for _, route := range split.Routes {
+		if !known[route.Version] {
+			return fmt.Errorf("%w: version %d of schema %s does not exist", ErrInvalidTrafficSplit, route.Version, schemaID)
+		}
+	}
+
+	split.CreatedAt = time.Now().UTC()
+	if err := s.graphRepo.SetTrafficSplit(schemaID, split); err != nil {
+		return err
+	}
+	log.Info().Str("schemaID", schemaID).Interface("routes", split.Routes).Msg("schema traffic split configured")
+	return nil
+}
+
+// Clear removes the traffic split of a schema.
+func (s *DefaultTrafficSplitService) Clear(schemaID string) error {
+	if err := s.graphRepo.SetTrafficSplit(schemaID, nil); err != nil {
+		return err
+	}
+	log.Info().Str("schemaID", schemaID).Msg("schema traffic split cleared")
+	return nil
+}
+
+// ResolveVersion picks the version a new execution runs on. A sticky key that cannot be
+// evaluated against the input falls back to random routing rather than failing the trigger.
+func (s *DefaultTrafficSplitService) ResolveVersion(schemaID string, input map[string]any) (int, error) {
+	split, err := s.graphRepo.FindTrafficSplit(schemaID)
+	if err != nil || split == nil {
+		return 0, err
+	}
+	if split.StickyKey != "" {
+		bucket, err := split.StickyBucket(input)
+		if err == nil {
+			return split.VersionForBucket(bucket), nil
+		}
+		log.Warn().Err(err).Str("schemaID", schemaID).Msg("falling back to random traffic split routing")
+	}
+	// nolint:gosec // traffic routing is not security sensitive
+	return split.VersionForBucket(rand.IntN(100)), nil
+}
+
+// Stats returns completed and failed execution counts of every routed version.
+func (s *DefaultTrafficSplitService) Stats(schemaID string) ([]VersionStats, error) {
+	split, err := s.graphRepo.FindTrafficSplit(schemaID)
+	if err != nil || split == nil {
+		return nil, err
+	}
+	baseline := split.Baseline()
+	stats := make([]VersionStats, 0, len(split.Routes))
+	for _, route := range split.Routes {
+		vs, err := s.versionStats(schemaID, split, route.Version)
+		if err != nil {
+			return nil, err
+		}
+		vs.Baseline = route.Version == baseline
+		stats = append(stats, vs)
+	}
+	return stats, nil
+}
+
+func (s *DefaultTrafficSplitService) versionStats(schemaID string, split *workflow.TrafficSplit, version int) (VersionStats, error) {
+	count := func(state workflow.State) (int, error) {
+		result, err := s.workflowRepo.FindExecutions(repositories.ExecutionListFilter{
+			SchemaID: schemaID,
+			Status:   state.String(),
+			Version:  version,
+			From:     split.CreatedAt,
+			Size:     1,
+		})
+		if err != nil {
+			return 0, err
+		}
+		return result.Total, nil
+	}
+	finished, err := count(workflow.StateFinished)
+	if err != nil {
+		return VersionStats{}, err
+	}
+	failed, err := count(workflow.StateError)
+	if err != nil {
+		return VersionStats{}, err
+	}
+	vs := VersionStats{
+		Version:   version,
+		Weight:    split.Weight(version),
+		Completed: finished + failed,
+		Failed:    failed,
+	}
+	if vs.Completed > 0 {
+		vs.ErrorRate = float64(failed) / float64(vs.Completed)
+	}
+	return vs, nil
+}
+
+// Start subscribes to workflow completion events. Completions are node-local events, but the
+// statistics they trigger are read from the shared workflow repository.
+func (s *DefaultTrafficSplitService) Start() error {
+	if s.eventBus == nil {
+		return nil
+	}
+	for _, eventType := range []string{events.EventWorkflowCompleted, events.EventWorkflowFailed} {
+		subID, err := s.eventBus.Subscribe(eventType, s.onWorkflowCompleted)
+		if err != nil {
+			return fmt.Errorf("subscribe to %s: %w", eventType, err)
+		}
+		s.subs = append(s.subs, subID)
+	}
+	return nil
+}
+
+// Stop unsubscribes from workflow completion events.
+func (s *DefaultTrafficSplitService) Stop() {
+	for _, subID := range s.subs {
+		_ = s.eventBus.Unsubscribe(subID)
+	}
+	s.subs = nil
+}
+
+func (s *DefaultTrafficSplitService) onWorkflowCompleted(event events.Event) error {
+	schemaID, _ := event.Data["schemaId"].(string)
+	version, _ := event.Data["schemaVersion"].(int)
+	if schemaID == "" || version == 0 {
+		return nil
+	}
+	return s.evaluateRollback(schemaID, version)
+}
+
+// evaluateRollback rolls a canary back when its completed executions breach the split's rule:
+// the split is cleared so all traffic returns to the active version, and when the active version
+// is not the baseline (typically because the canary was uploaded as the newest version) the
+// baseline is restored with Rollback.
+func (s *DefaultTrafficSplitService) evaluateRollback(schemaID string, version int) error {
+	s.rollbackMu.Lock()
+	defer s.rollbackMu.Unlock()
+
+	split, err := s.graphRepo.FindTrafficSplit(schemaID)
+	if err != nil || split == nil || split.AutoRollback == nil {
+		return err
+	}
+	baseline := split.Baseline()
+	if version == baseline || split.Weight(version) == 0 {
+		return nil
+	}
+	stats, err := s.versionStats(schemaID, split, version)
+	if err != nil {
+		return err
+	}
+	if !split.AutoRollback.Breached(stats.Completed, stats.Failed) {
+		return nil
+	}
+
+	log.Warn().Str("schemaID", schemaID).Int("version", version).Int("baseline", baseline).
+		Int("completed", stats.Completed).Int("failed", stats.Failed).
+		Msg("canary error rate exceeded, rolling back")
+	if err := s.graphRepo.SetTrafficSplit(schemaID, nil); err != nil {
+		return err
+	}
+	history, err := s.graphRepo.GetVersionHistory(schemaID)
+	if err != nil {
+		return err
+	}
+	restored := baseline
+	if history.ActiveVersion != baseline {
+		comment := fmt.Sprintf("automatic canary rollback: version %d failed %d of %d executions", version, stats.Failed, stats.Completed)
+		sv, err := s.graphService.Rollback(context.Background(), schemaID, baseline, comment)
+		if err != nil {
+			return err
+		}
+		restored = sv.Version
+	}
+
+	if s.eventBus != nil {
+		if err := s.eventBus.Publish(events.Event{
+			Type:   events.EventSchemaCanaryRolledBack,
+			Source: schemaID,
+			Data: map[string]any{
+				"schemaId":        schemaID,
+				"canaryVersion":   version,
+				"baselineVersion": baseline,
+				"activeVersion":   restored,
+				"failed":          stats.Failed,
+				"completed":       stats.Completed,
+			},
+		}); err != nil {
+			log.Warn().Err(err).Str("schemaID", schemaID).Msg("failed to publish canary rollback event")
+		}
+	}
+	return nil
+}
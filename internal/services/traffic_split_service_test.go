@@ -0,0 +1,153 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/open-source-cloud/fuse/internal/events"
+	"github.com/open-source-cloud/fuse/internal/mocks"
+	"github.com/open-source-cloud/fuse/internal/packages"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/internal/services"
+	"github.com/open-source-cloud/fuse/internal/workflow"
+	"github.com/open-source-cloud/fuse/pkg/llm"
+	pkgworkflow "github.com/open-source-cloud/fuse/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type trafficFixture struct {
+	svc          services.TrafficSplitService
+	graphService services.GraphService
+	graphRepo    repositories.GraphRepository
+	workflowRepo repositories.WorkflowRepository
+	schemaID     string
+}
+
+// newTrafficFixture stores two versions of the small test schema; version 2 is active.
+func newTrafficFixture(t *testing.T, bus events.EventBus) *trafficFixture {
+	t.Helper()
+	graphRepo := repositories.NewMemoryGraphRepository()
+	pkgRegistry := packages.NewPackageRegistry()
+	internalPackages := packages.NewInternal(packages.InternalDeps{Providers: llm.NewRegistry(nil, ""), Registry: pkgRegistry})
+	pkgSvc := services.NewPackageService(repositories.NewMemoryPackageRepository(), pkgRegistry, internalPackages, nil)
+	require.NoError(t, pkgSvc.RegisterInternalPackages())
+	graphService := services.NewGraphService(graphRepo, pkgRegistry, nil, nil)
+
+	schema := mocks.SmallTestGraphSchema()
+	_, err := graphService.Upsert(context.Background(), schema.ID, schema)
+	require.NoError(t, err)
+	schema.Name = "canary"
+	_, err = graphService.Upsert(context.Background(), schema.ID, schema)
+	require.NoError(t, err)
+
+	workflowRepo := repositories.NewMemoryWorkflowRepository()
+	return &trafficFixture{
+		svc:          services.NewTrafficSplitService(graphRepo, workflowRepo, graphService, bus),
+		graphService: graphService,
+		graphRepo:    graphRepo,
+		workflowRepo: workflowRepo,
+		schemaID:     schema.ID,
+	}
+}
+
+func (f *trafficFixture) saveExecution(t *testing.T, version int, state workflow.State) {
+	t.Helper()
+	graph, err := f.graphService.FindByIDAndVersion(f.schemaID, version)
+	require.NoError(t, err)
+	wf := workflow.New(pkgworkflow.NewID(), graph, "default")
+	wf.SetSchemaVersion(version)
+	wf.SetState(state)
+	require.NoError(t, f.workflowRepo.Save(wf))
+}
+
+func TestTrafficSplitService_SetRejectsUnknownVersion(t *testing.T) {
+	f := newTrafficFixture(t, nil)
+
+	err := f.svc.Set(f.schemaID, &workflow.TrafficSplit{Routes: []workflow.TrafficRoute{
+		{Version: 1, Weight: 90},
+		{Version: 5, Weight: 10},
+	}})
+
+	assert.ErrorIs(t, err, services.ErrInvalidTrafficSplit)
+}
+
+func TestTrafficSplitService_ResolveVersion(t *testing.T) {
+	f := newTrafficFixture(t, nil)
+
+	version, err := f.svc.ResolveVersion(f.schemaID, nil)
+	require.NoError(t, err)
+	assert.Zero(t, version, "no split runs the active version")
+
+	require.NoError(t, f.svc.Set(f.schemaID, &workflow.TrafficSplit{
+		Routes:    []workflow.TrafficRoute{{Version: 1, Weight: 50}, {Version: 2, Weight: 50}},
+		StickyKey: "input.customerId",
+	}))
+	first, err := f.svc.ResolveVersion(f.schemaID, map[string]any{"customerId": "acme"})
+	require.NoError(t, err)
+	assert.Contains(t, []int{1, 2}, first)
+	for range 10 {
+		again, err := f.svc.ResolveVersion(f.schemaID, map[string]any{"customerId": "acme"})
+		require.NoError(t, err)
+		assert.Equal(t, first, again)
+	}
+}
+
+func TestTrafficSplitService_Stats(t *testing.T) {
+	f := newTrafficFixture(t, nil)
+	require.NoError(t, f.svc.Set(f.schemaID, &workflow.TrafficSplit{
+		Routes: []workflow.TrafficRoute{{Version: 1, Weight: 90}, {Version: 2, Weight: 10}},
+	}))
+	f.saveExecution(t, 2, workflow.StateFinished)
+	f.saveExecution(t, 2, workflow.StateError)
+	f.saveExecution(t, 1, workflow.StateFinished)
+
+	stats, err := f.svc.Stats(f.schemaID)
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+	assert.Equal(t, services.VersionStats{Version: 1, Weight: 90, Baseline: true, Completed: 1}, stats[0])
+	assert.Equal(t, services.VersionStats{Version: 2, Weight: 10, Completed: 2, Failed: 1, ErrorRate: 0.5}, stats[1])
+}
+
+func TestTrafficSplitService_AutoRollback(t *testing.T) {
+	bus := events.NewMemoryBus(context.Background())
+	rolledBack := make(chan events.Event, 1)
+	_, err := bus.Subscribe(events.EventSchemaCanaryRolledBack, func(event events.Event) error {
+		rolledBack <- event
+		return nil
+	})
+	require.NoError(t, err)
+
+	f := newTrafficFixture(t, bus)
+	require.NoError(t, f.svc.Start())
+	t.Cleanup(f.svc.Stop)
+	require.NoError(t, f.svc.Set(f.schemaID, &workflow.TrafficSplit{
+		Routes:       []workflow.TrafficRoute{{Version: 1, Weight: 90}, {Version: 2, Weight: 10}},
+		AutoRollback: &workflow.CanaryRollbackRule{MaxErrorRate: 0.2, MinExecutions: 2},
+	}))
+	f.saveExecution(t, 2, workflow.StateError)
+	f.saveExecution(t, 2, workflow.StateError)
+
+	require.NoError(t, bus.Publish(events.Event{
+		Type: events.EventWorkflowFailed,
+		Data: map[string]any{"schemaId": f.schemaID, "schemaVersion": 2, "status": "error"},
+	}))
+
+	select {
+	case event := <-rolledBack:
+		assert.Equal(t, 2, event.Data["canaryVersion"])
+		assert.Equal(t, 1, event.Data["baselineVersion"])
+	case <-time.After(2 * time.Second):
+		t.Fatal("canary was not rolled back")
+	}
+	split, err := f.svc.Find(f.schemaID)
+	require.NoError(t, err)
+	assert.Nil(t, split)
+	history, err := f.graphService.GetVersionHistory(f.schemaID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, history.ActiveVersion, "baseline content is restored as a new active version")
+	graph, err := f.graphService.FindByID(f.schemaID)
+	require.NoError(t, err)
+	assert.Equal(t, "test", graph.Schema().Name)
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/open-source-cloud/fuse/internal/packages/functions/ai"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+)
+
+// maxCatalogDepth bounds the walk up a chain of sub-workflows, so a corrupt chain of references
+// cannot loop forever. It is far above any depth an orchestrator accepts.
+const maxCatalogDepth = 64
+
+type (
+	// WorkflowCatalog describes the workflow schemas ai/orchestrate runs as child workflows and
+	// how deeply executions are nested.
+	WorkflowCatalog interface {
+		ai.WorkflowCatalog
+	}
+
+	// DefaultWorkflowCatalog is the default WorkflowCatalog implementation, reading schemas from the
+	// graph repository and parent links from the workflow repository.
+	DefaultWorkflowCatalog struct {
+		graphRepo    repositories.GraphRepository
+		workflowRepo repositories.WorkflowRepository
+	}
+)
+
+// NewWorkflowCatalog returns a new WorkflowCatalog.
+func NewWorkflowCatalog(graphRepo repositories.GraphRepository, workflowRepo repositories.WorkflowRepository) WorkflowCatalog {
+	return &DefaultWorkflowCatalog{graphRepo: graphRepo, workflowRepo: workflowRepo}
+}
+
+// DescribeWorkflow returns the active version of a schema of namespace as a tool. Schemas that do
+// not accept new executions (deprecated, archived) cannot be offered.
+func (c *DefaultWorkflowCatalog) DescribeWorkflow(namespace, schemaID string) (ai.WorkflowTool, error) {
+	_, local := workflow.SplitQualifiedID(schemaID)
+	qualified := workflow.QualifyID(namespace, local)
+	graph, err := c.graphRepo.FindByID(qualified)
+	if err != nil {
+		return ai.WorkflowTool{}, err
+	}
+	lifecycle, err := c.graphRepo.FindLifecycle(qualified)
+	if err != nil {
+		return ai.WorkflowTool{}, err
+	}
+	if !lifecycle.AcceptsExecutions() {
+		return ai.WorkflowTool{}, fmt.Errorf("schema is %s and accepts no new executions", lifecycle)
+	}
+	schema := graph.Schema()
+	return ai.WorkflowTool{
+		SchemaID:    local,
+		Name:        schema.Name,
+		Description: schema.Metadata["description"],
+		Input:       schema.Input,
+	}, nil
+}
+
+// Depth counts the parents of an execution by following its sub-workflow references.
+func (c *DefaultWorkflowCatalog) Depth(workflowID workflow.ID) (int, error) {
+	depth := 0
+	for id := workflowID; ; depth++ {
+		if depth >= maxCatalogDepth {
+			return 0, fmt.Errorf("workflow %s is nested more than %d levels deep", workflowID, maxCatalogDepth)
+		}
+		ref, err := c.workflowRepo.FindSubWorkflowRef(id.String())
+		if err != nil || ref == nil {
+			return depth, nil
+		}
+		id = ref.ParentWorkflowID
+	}
+}
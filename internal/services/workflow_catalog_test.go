@@ -0,0 +1,54 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/open-source-cloud/fuse/internal/mocks"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	internalworkflow "github.com/open-source-cloud/fuse/internal/workflow"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkflowCatalog_DescribeWorkflow(t *testing.T) {
+	t.Parallel()
+	graphRepo := repositories.NewMemoryGraphRepository()
+	schema := mocks.SmallTestGraphSchema()
+	schema.Metadata = map[string]string{"description": "adds numbers"}
+	schema.Input = []workflow.ParameterSchema{{Name: "values", Type: "[]int", Required: true}}
+	graph, err := internalworkflow.NewGraph(schema)
+	require.NoError(t, err)
+	require.NoError(t, graphRepo.Save(graph))
+	catalog := NewWorkflowCatalog(graphRepo, repositories.NewMemoryWorkflowRepository())
+
+	tool, err := catalog.DescribeWorkflow(workflow.DefaultNamespaceName, schema.ID)
+	require.NoError(t, err)
+	assert.Equal(t, schema.ID, tool.SchemaID)
+	assert.Equal(t, schema.Name, tool.Name)
+	assert.Equal(t, "adds numbers", tool.Description)
+	assert.Equal(t, schema.Input, tool.Input)
+
+	require.NoError(t, graphRepo.SetLifecycle(schema.ID, internalworkflow.SchemaDeprecated))
+	_, err = catalog.DescribeWorkflow(workflow.DefaultNamespaceName, schema.ID)
+	assert.ErrorContains(t, err, "accepts no new executions")
+
+	_, err = catalog.DescribeWorkflow(workflow.DefaultNamespaceName, "missing")
+	assert.ErrorIs(t, err, repositories.ErrGraphNotFound)
+	_, err = catalog.DescribeWorkflow("billing", schema.ID)
+	assert.ErrorIs(t, err, repositories.ErrGraphNotFound, "schemas resolve in the orchestrator's namespace")
+}
+
+func TestWorkflowCatalog_Depth(t *testing.T) {
+	t.Parallel()
+	workflowRepo := repositories.NewMemoryWorkflowRepository()
+	require.NoError(t, workflowRepo.SaveSubWorkflowRef(&internalworkflow.SubWorkflowRef{ParentWorkflowID: "root", ChildWorkflowID: "child"}))
+	require.NoError(t, workflowRepo.SaveSubWorkflowRef(&internalworkflow.SubWorkflowRef{ParentWorkflowID: "child", ChildWorkflowID: "grandchild"}))
+	catalog := NewWorkflowCatalog(repositories.NewMemoryGraphRepository(), workflowRepo)
+
+	for id, want := range map[workflow.ID]int{"root": 0, "child": 1, "grandchild": 2} {
+		depth, err := catalog.Depth(id)
+		require.NoError(t, err)
+		assert.Equal(t, want, depth, id)
+	}
+}
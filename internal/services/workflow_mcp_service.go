@@ -0,0 +1,385 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/actors/actornames"
+	"github.com/open-source-cloud/fuse/internal/app/config"
+	"github.com/open-source-cloud/fuse/internal/auth"
+	"github.com/open-source-cloud/fuse/internal/messaging"
+	"github.com/open-source-cloud/fuse/internal/packages/functions/ai"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	internalworkflow "github.com/open-source-cloud/fuse/internal/workflow"
+	"github.com/open-source-cloud/fuse/pkg/mcp"
+	"github.com/open-source-cloud/fuse/pkg/objectstore"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// workflowMCPPollInterval is how often a tool call checks whether its execution has ended.
+	workflowMCPPollInterval = 250 * time.Millisecond
+	// workflowResourcePrefix starts the URI of every execution resource.
+	workflowResourcePrefix = "fuse://workflows/"
+	jsonMimeType           = "application/json"
+)
+
+// errWorkflowMCPNotBound is returned by tool calls before the actor node is bound at startup.
+var errWorkflowMCPNotBound = errors.New("workflow MCP service is not bound to an actor node")
+
+type (
+	// WorkflowMCPService offers the schemas that opt in with mcp.expose to external AI assistants
+	// as the tools of an MCP server per namespace. A tool call triggers the schema and answers with
+	// its output, or with a handle when the execution outlives the wait; executions are readable
+	// as resources.
+	WorkflowMCPService interface {
+		// Server returns the MCP server of namespace; its tool calls run in environment.
+		Server(namespace, environment string) *mcp.Server
+		// BindNode wires the ergo node triggers are sent through; call once at startup.
+		BindNode(node gen.Node)
+	}
+
+	// DefaultWorkflowMCPService is the default WorkflowMCPService implementation.
+	DefaultWorkflowMCPService struct {
+		graphRepo        repositories.GraphRepository
+		workflowRepo     repositories.WorkflowRepository
+		journalRepo      repositories.JournalRepository
+		traceRepo        repositories.TraceRepository
+		store            objectstore.ObjectStore
+		namespaceService NamespaceService
+		wait             time.Duration
+
+		mu   sync.RWMutex
+		node gen.Node
+	}
+
+	// workflowMCPHandler answers the MCP methods of one namespace and environment.
+	workflowMCPHandler struct {
+		svc         *DefaultWorkflowMCPService
+		namespace   string
+		environment string
+	}
+
+	// workflowMCPTool is an exposed schema and the tool it is offered as.
+	workflowMCPTool struct {
+		schemaID string
+		schema   internalworkflow.GraphSchema
+		tool     mcp.Tool
+	}
+)
+
+// NewWorkflowMCPService returns a new WorkflowMCPService; call BindNode from fx OnStart after the
+// node exists.
+func NewWorkflowMCPService(
+	cfg *config.Config,
+	graphRepo repositories.GraphRepository,
+	workflowRepo repositories.WorkflowRepository,
+	journalRepo repositories.JournalRepository,
+	traceRepo repositories.TraceRepository,
+	store objectstore.ObjectStore,
+	namespaceService NamespaceService,
+) WorkflowMCPService {
+	return &DefaultWorkflowMCPService{
+		graphRepo:        graphRepo,
+		workflowRepo:     workflowRepo,
+		journalRepo:      journalRepo,
+		traceRepo:        traceRepo,
+		store:            store,
+		namespaceService: namespaceService,
+		wait:             cfg.LLM.MCPServerWait,
+	}
+}
+
+// BindNode wires the ergo node triggers are sent through; safe to call once at startup.
+func (s *DefaultWorkflowMCPService) BindNode(node gen.Node) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.node = node
+}
+
+// Server returns the MCP server of namespace.
+func (s *DefaultWorkflowMCPService) Server(namespace, environment string) *mcp.Server {
+	instructions := fmt.Sprintf("Each tool runs a FUSE workflow of namespace %s in environment %s. "+
+		"A call waits for the workflow to end and returns its output; when it runs longer, the call returns its workflowId "+
+		"and the %s{workflowId} resource to read the status and output from later.", namespace, environment, workflowResourcePrefix)
+	return mcp.NewServer(mcpImplementation(), instructions, &workflowMCPHandler{svc: s, namespace: namespace, environment: environment})
+}
+
+// ListTools lists the exposed schemas of the namespace that accept executions and that the caller
+// may trigger in the environment.
+func (h *workflowMCPHandler) ListTools(ctx context.Context) ([]mcp.Tool, error) {
+	tools, err := h.tools(ctx)
+	if err != nil {
+		log.Error().Err(err).Str("namespace", h.namespace).Msg("mcp: list workflow tools failed")
+		return nil, err
+	}
+	out := make([]mcp.Tool, len(tools))
+	for i, t := range tools {
+		out[i] = t.tool
+	}
+	return out, nil
+}
+
+// CallTool triggers the tool's schema with args as input and waits for the execution.
+func (h *workflowMCPHandler) CallTool(ctx context.Context, name string, args map[string]any) (*mcp.CallToolResult, error) {
+	tools, err := h.tools(ctx)
+	if err != nil {
+		log.Error().Err(err).Str("namespace", h.namespace).Msg("mcp: list workflow tools failed")
+		return nil, err
+	}
+	idx := slices.IndexFunc(tools, func(t workflowMCPTool) bool { return t.tool.Name == name })
+	if idx < 0 {
+		return nil, &mcp.Error{Code: mcp.CodeInvalidParams, Message: "unknown tool " + name}
+	}
+	target := tools[idx]
+
+	var missing []string
+	for _, param := range target.schema.Input {
+		if _, ok := args[param.Name]; param.Required && !ok {
+			missing = append(missing, param.Name)
+		}
+	}
+	if len(missing) > 0 {
+		return toolError("missing required input: " + strings.Join(missing, ", ")), nil
+	}
+	if err := h.svc.namespaceService.CheckConcurrency(h.namespace); err != nil {
+		if errors.Is(err, ErrNamespaceQuotaExceeded) {
+			return toolError(err.Error()), nil
+		}
+		log.Error().Err(err).Str("namespace", h.namespace).Msg("mcp: check namespace quota failed")
+		return nil, err
+	}
+
+	h.svc.mu.RLock()
+	node := h.svc.node
+	h.svc.mu.RUnlock()
+	if node == nil {
+		return nil, errWorkflowMCPNotBound
+	}
+	workflowID := workflow.NewID()
+	if args == nil {
+		args = map[string]any{}
+	}
+	msg := messaging.NewTriggerWorkflowWithEnvAndInputMessage(target.schemaID, workflowID, h.environment, args)
+	if err := node.Send(gen.Atom(actornames.WorkflowSupervisorName), msg); err != nil {
+		log.Error().Err(err).Str("schemaID", target.schemaID).Msg("mcp: trigger workflow failed")
+		return nil, err
+	}
+
+	wait := h.svc.wait
+	if d := target.schema.MCP.Wait.Duration(); d > 0 {
+		wait = d
+	}
+	wf := h.svc.awaitEnd(ctx, workflowID.String(), wait)
+	if wf == nil || wf.State().IsActive() {
+		return runningResult(workflowID.String()), nil
+	}
+	return finishedResult(workflowID.String(), wf), nil
+}
+
+// ListResources lists no concrete resources: executions are reached through the templates.
+func (h *workflowMCPHandler) ListResources(context.Context) ([]mcp.Resource, error) {
+	return nil, nil
+}
+
+// ListResourceTemplates describes the status, trace and snapshot resources of an execution.
+func (h *workflowMCPHandler) ListResourceTemplates(context.Context) ([]mcp.ResourceTemplate, error) {
+	return []mcp.ResourceTemplate{
+		{
+			URITemplate: workflowResourcePrefix + "{workflowId}",
+			Name:        "workflow",
+			Title:       "Workflow execution",
+			Description: "Status of a workflow execution and the output it produced so far",
+			MimeType:    jsonMimeType,
+		},
+		{
+			URITemplate: workflowResourcePrefix + "{workflowId}/trace",
+			Name:        "workflow-trace",
+			Title:       "Workflow execution trace",
+			Description: "Every step of a workflow execution with its input, output, timing and attempts",
+			MimeType:    jsonMimeType,
+		},
+		{
+			URITemplate: workflowResourcePrefix + "{workflowId}/snapshot",
+			Name:        "workflow-snapshot",
+			Title:       "Workflow execution snapshot",
+			Description: "State of a workflow execution rebuilt from its journal",
+			MimeType:    jsonMimeType,
+		},
+	}, nil
+}
+
+// ReadResource reads an execution resource. Executions of other namespaces, and those the caller
+// may not read, are not found.
+func (h *workflowMCPHandler) ReadResource(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	notFound := &mcp.Error{Code: mcp.CodeResourceNotFound, Message: "resource not found: " + uri}
+	path, ok := strings.CutPrefix(uri, workflowResourcePrefix)
+	if !ok {
+		return nil, notFound
+	}
+	workflowID, kind, _ := strings.Cut(path, "/")
+	wf, err := h.svc.workflowRepo.Get(workflowID)
+	if err != nil || wf.Namespace() != h.namespace {
+		return nil, notFound
+	}
+	if err := auth.Check(ctx, auth.PermWorkflowRead, auth.NewSchemaResource(wf.Graph().ID(), wf.Environment())); err != nil {
+		if errors.Is(err, auth.ErrForbidden) {
+			return nil, notFound
+		}
+		return nil, err
+	}
+
+	var body any
+	switch kind {
+	case "":
+		body = workflowResult(workflowID, wf)
+	case "trace":
+		trace, err := h.svc.traceRepo.FindByWorkflowID(workflowID)
+		if err != nil {
+			return nil, notFound
+		}
+		body = trace
+	case "snapshot":
+		snapshot, err := h.svc.snapshot(workflowID, wf)
+		if err != nil {
+			log.Error().Err(err).Str("workflowID", workflowID).Msg("mcp: read workflow snapshot failed")
+			return nil, err
+		}
+		body = snapshot
+	default:
+		return nil, notFound
+	}
+
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	return &mcp.ReadResourceResult{Contents: []mcp.ResourceContents{{URI: uri, MimeType: jsonMimeType, Text: string(raw)}}}, nil
+}
+
+// tools returns the exposed schemas of the namespace the caller may trigger, ordered by schema ID.
+func (h *workflowMCPHandler) tools(ctx context.Context) ([]workflowMCPTool, error) {
+	items, err := h.svc.graphRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	var tools []workflowMCPTool
+	for _, item := range items {
+		namespace, local := workflow.SplitQualifiedID(item.SchemaID)
+		if namespace != h.namespace || !item.Lifecycle.AcceptsExecutions() {
+			continue
+		}
+		if auth.Check(ctx, auth.PermWorkflowTrigger, auth.NewSchemaResource(item.SchemaID, h.environment)) != nil {
+			continue
+		}
+		graph, err := h.svc.graphRepo.FindByID(item.SchemaID)
+		if err != nil {
+			return nil, err
+		}
+		schema := graph.Schema()
+		if !schema.MCP.Exposed() {
+			continue
+		}
+		description := schema.MCP.Description
+		if description == "" {
+			description = schema.Metadata["description"]
+		}
+		tools = append(tools, workflowMCPTool{
+			schemaID: item.SchemaID,
+			schema:   schema,
+			tool: mcp.Tool{
+				Name:        mcpToolNameUnsafe.ReplaceAllString(local, "_"),
+				Title:       schema.Name,
+				Description: description,
+				InputSchema: ai.ParameterSchemaToJSONSchema(schema.Input),
+			},
+		})
+	}
+	return tools, nil
+}
+
+// awaitEnd polls the execution until it is no longer active, wait elapses or ctx ends, and returns
+// it as last seen; nil when it was not created yet. Polling the repository rather than listening
+// for completion events finds executions that run on another cluster node.
+func (s *DefaultWorkflowMCPService) awaitEnd(ctx context.Context, workflowID string, wait time.Duration) *internalworkflow.Workflow {
+	deadline := time.NewTimer(wait)
+	defer deadline.Stop()
+	poll := time.NewTicker(workflowMCPPollInterval)
+	defer poll.Stop()
+	for {
+		wf, err := s.workflowRepo.Get(workflowID)
+		if err == nil && !wf.State().IsActive() {
+			return wf
+		}
+		select {
+		case <-ctx.Done():
+			return wf
+		case <-deadline.C:
+			return wf
+		case <-poll.C:
+		}
+	}
+}
+
+// snapshot returns the persisted snapshot of the execution, or builds one from its journal.
+func (s *DefaultWorkflowMCPService) snapshot(workflowID string, wf *internalworkflow.Workflow) (any, error) {
+	if ref, _ := s.workflowRepo.GetSnapshotRef(workflowID); ref != "" {
+		if data, err := s.store.Get(context.Background(), ref); err == nil {
+			return json.RawMessage(data), nil
+		}
+	}
+	entries, err := s.journalRepo.LoadAll(workflowID)
+	if err != nil {
+		return nil, err
+	}
+	return internalworkflow.BuildExecutionSnapshot(workflowID, wf.Graph().ID(), wf.State(), entries, wf.AggregatedOutputSnapshot()), nil
+}
+
+// workflowResult is the status and output of an execution, shaped like a sub-workflow's output.
+func workflowResult(workflowID string, wf *internalworkflow.Workflow) map[string]any {
+	return map[string]any{
+		"workflowId": workflowID,
+		"status":     wf.State().String(),
+		"output":     wf.AggregatedOutputSnapshot(),
+	}
+}
+
+// finishedResult answers a tool call whose execution ended; it is an error unless it finished.
+func finishedResult(workflowID string, wf *internalworkflow.Workflow) *mcp.CallToolResult {
+	data := workflowResult(workflowID, wf)
+	raw, _ := json.Marshal(data)
+	return &mcp.CallToolResult{
+		Content:           []mcp.Content{mcp.TextContent(string(raw))},
+		StructuredContent: data,
+		IsError:           wf.State() != internalworkflow.StateFinished,
+	}
+}
+
+// runningResult answers a tool call whose execution outlived the wait with a handle to it.
+func runningResult(workflowID string) *mcp.CallToolResult {
+	uri := workflowResourcePrefix + workflowID
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent(fmt.Sprintf("Workflow %s is still running; read %s for its status and output.", workflowID, uri)),
+			{Type: "resource_link", URI: uri, Name: "workflow", MimeType: jsonMimeType},
+		},
+		StructuredContent: map[string]any{
+			"workflowId": workflowID,
+			"status":     internalworkflow.StateRunning.String(),
+			"resource":   uri,
+		},
+	}
+}
+
+// toolError is a tool result reporting a failure the caller can correct.
+func toolError(message string) *mcp.CallToolResult {
+	return &mcp.CallToolResult{Content: []mcp.Content{mcp.TextContent(message)}, IsError: true}
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"ergo.services/ergo/gen"
+	"github.com/open-source-cloud/fuse/internal/app/config"
+	"github.com/open-source-cloud/fuse/internal/auth"
+	"github.com/open-source-cloud/fuse/internal/messaging"
+	"github.com/open-source-cloud/fuse/internal/mocks"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	internalworkflow "github.com/open-source-cloud/fuse/internal/workflow"
+	"github.com/open-source-cloud/fuse/pkg/mcp"
+	"github.com/open-source-cloud/fuse/pkg/objectstore"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// triggerNode stands in for the actor node: each trigger sent to the supervisor stores the
+// execution in the state the test chose.
+type triggerNode struct {
+	gen.Node
+	state    internalworkflow.State
+	graphs   repositories.GraphRepository
+	repo     repositories.WorkflowRepository
+	triggers []messaging.TriggerWorkflowMessage
+}
+
+func (n *triggerNode) Send(_ any, message any) error {
+	msg, err := message.(messaging.Message).TriggerWorkflowMessage()
+	if err != nil {
+		return err
+	}
+	n.triggers = append(n.triggers, msg)
+	graph, err := n.graphs.FindByID(msg.SchemaID)
+	if err != nil {
+		return err
+	}
+	wf := internalworkflow.New(msg.WorkflowID, graph, msg.Environment)
+	wf.SetState(n.state)
+	return n.repo.Save(wf)
+}
+
+type denyAuthorizer struct{ schemaID string }
+
+func (a denyAuthorizer) Authorize(_ *auth.Principal, _ auth.Permission, resource auth.Resource) error {
+	if resource.SchemaID == a.schemaID {
+		return auth.ErrForbidden
+	}
+	return nil
+}
+
+func newTestWorkflowMCPService(t *testing.T) (*DefaultWorkflowMCPService, *triggerNode) {
+	t.Helper()
+	graphRepo := repositories.NewMemoryGraphRepository()
+	workflowRepo := repositories.NewMemoryWorkflowRepository()
+	save := func(id string, cfg *internalworkflow.MCPConfig) {
+		schema := mocks.SmallTestGraphSchema()
+		schema.ID, schema.Name, schema.MCP = id, "Refund order", cfg
+		schema.Input = []workflow.ParameterSchema{{Name: "orderId", Type: "string", Required: true}}
+		graph, err := internalworkflow.NewGraph(schema)
+		require.NoError(t, err)
+		require.NoError(t, graphRepo.Save(graph))
+	}
+	save(workflow.QualifyID("billing", "refund-order"), &internalworkflow.MCPConfig{Expose: true, Description: "Refunds an order"})
+	save(workflow.QualifyID("billing", "internal"), nil)
+	save(workflow.QualifyID("billing", "legacy"), &internalworkflow.MCPConfig{Expose: true})
+	require.NoError(t, graphRepo.SetLifecycle(workflow.QualifyID("billing", "legacy"), internalworkflow.SchemaDeprecated))
+	save("other", &internalworkflow.MCPConfig{Expose: true})
+
+	namespaceRepo := repositories.NewMemoryNamespaceRepository()
+	require.NoError(t, namespaceRepo.Save(&workflow.Namespace{Name: "billing"}))
+	namespaces := NewNamespaceService(namespaceRepo, workflowRepo, graphRepo, repositories.NewMemoryPackageRepository())
+	cfg := &config.Config{Environment: "prod", LLM: config.LLMConfig{MCPServerWait: 50 * time.Millisecond}}
+	svc := NewWorkflowMCPService(cfg, graphRepo, workflowRepo, repositories.NewMemoryJournalRepository(),
+		repositories.NewMemoryTraceRepository(), objectstore.NewMemoryObjectStore(), namespaces).(*DefaultWorkflowMCPService)
+
+	node := &triggerNode{state: internalworkflow.StateFinished, graphs: graphRepo, repo: workflowRepo}
+	svc.BindNode(node)
+	return svc, node
+}
+
+func TestWorkflowMCPService_ToolsOverHTTP(t *testing.T) {
+	svc, node := newTestWorkflowMCPService(t)
+	httpServer := httptest.NewServer(svc.Server("billing", "prod"))
+	defer httpServer.Close()
+	ctx := context.Background()
+	client, err := mcp.Connect(ctx, mcp.ServerConfig{ID: "fuse", Transport: mcp.TransportHTTP, URL: httpServer.URL}, mcpImplementation())
+	require.NoError(t, err)
+	defer func() { _ = client.Close() }()
+
+	tools, err := client.ListTools(ctx)
+	require.NoError(t, err)
+	require.Len(t, tools, 1, "only exposed schemas of the namespace that accept executions")
+	assert.Equal(t, "refund-order", tools[0].Name)
+	assert.Equal(t, "Refund order", tools[0].Title)
+	assert.Equal(t, "Refunds an order", tools[0].Description)
+	assert.Equal(t, []any{"orderId"}, tools[0].InputSchema["required"])
+
+	res, err := client.CallTool(ctx, "refund-order", map[string]any{"orderId": "o-1"})
+	require.NoError(t, err)
+	assert.False(t, res.IsError)
+	assert.Equal(t, "finished", res.StructuredContent["status"])
+	require.Len(t, node.triggers, 1)
+	trigger := node.triggers[0]
+	assert.Equal(t, workflow.QualifyID("billing", "refund-order"), trigger.SchemaID)
+	assert.Equal(t, "prod", trigger.Environment)
+	assert.Equal(t, map[string]any{"orderId": "o-1"}, trigger.Input)
+	assert.Equal(t, trigger.WorkflowID.String(), res.StructuredContent["workflowId"])
+
+	res, err = client.CallTool(ctx, "refund-order", nil)
+	require.NoError(t, err)
+	assert.True(t, res.IsError)
+	assert.Contains(t, res.Text(), "orderId")
+	assert.Len(t, node.triggers, 1, "a call missing required input triggers nothing")
+
+	_, err = client.CallTool(ctx, "internal", map[string]any{"orderId": "o-1"})
+	var rpcErr *mcp.Error
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, mcp.CodeInvalidParams, rpcErr.Code)
+
+	resource, err := client.ReadResource(ctx, workflowResourcePrefix+trigger.WorkflowID.String())
+	require.NoError(t, err)
+	var status map[string]any
+	require.NoError(t, json.Unmarshal([]byte(resource.Contents[0].Text), &status))
+	assert.Equal(t, "finished", status["status"])
+
+	snapshot, err := client.ReadResource(ctx, workflowResourcePrefix+trigger.WorkflowID.String()+"/snapshot")
+	require.NoError(t, err)
+	assert.Contains(t, snapshot.Contents[0].Text, trigger.WorkflowID.String())
+
+	_, err = client.ReadResource(ctx, workflowResourcePrefix+trigger.WorkflowID.String()+"/trace")
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, mcp.CodeResourceNotFound, rpcErr.Code, "no trace was recorded")
+}
+
+func TestWorkflowMCPService_LongRunningAndFailedCalls(t *testing.T) {
+	svc, node := newTestWorkflowMCPService(t)
+	server := svc.Server("billing", "prod")
+	ctx := context.Background()
+	call := func(t *testing.T) *mcp.CallToolResult {
+		t.Helper()
+		req, err := mcp.NewRequest(1, mcp.MethodToolsCall, mcp.CallToolParams{Name: "refund-order", Arguments: map[string]any{"orderId": "o-1"}})
+		require.NoError(t, err)
+		resp := server.Handle(ctx, req)
+		require.Nil(t, resp.Error)
+		var res mcp.CallToolResult
+		require.NoError(t, json.Unmarshal(resp.Result, &res))
+		return &res
+	}
+
+	node.state = internalworkflow.StateRunning
+	res := call(t)
+	assert.False(t, res.IsError)
+	assert.Equal(t, "running", res.StructuredContent["status"])
+	workflowID := node.triggers[0].WorkflowID.String()
+	assert.Equal(t, workflowResourcePrefix+workflowID, res.StructuredContent["resource"], "a run outliving the wait answers with a handle")
+
+	node.state = internalworkflow.StateError
+	res = call(t)
+	assert.True(t, res.IsError)
+	assert.Equal(t, "error", res.StructuredContent["status"])
+
+	req, err := mcp.NewRequest(2, mcp.MethodResourcesRead, mcp.ReadResourceParams{URI: workflowResourcePrefix + workflowID})
+	require.NoError(t, err)
+	resp := svc.Server(workflow.DefaultNamespaceName, "prod").Handle(ctx, req)
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, mcp.CodeResourceNotFound, resp.Error.Code, "executions of other namespaces are not readable")
+}
+
+func TestWorkflowMCPService_ToolsFollowRBAC(t *testing.T) {
+	svc, _ := newTestWorkflowMCPService(t)
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "apikey:support"})
+	ctx = auth.WithAuthorizer(ctx, denyAuthorizer{schemaID: "refund-order"})
+
+	req, err := mcp.NewRequest(1, mcp.MethodToolsList, nil)
+	require.NoError(t, err)
+	resp := svc.Server("billing", "prod").Handle(ctx, req)
+	require.Nil(t, resp.Error)
+	assert.JSONEq(t, `{"tools":[]}`, string(resp.Result), "schemas the caller may not trigger are not offered")
+}
+
+func TestWorkflowMCPService_ResourcesFollowRBAC(t *testing.T) {
+	svc, node := newTestWorkflowMCPService(t)
+	server := svc.Server("billing", "prod")
+	req, err := mcp.NewRequest(1, mcp.MethodToolsCall, mcp.CallToolParams{Name: "refund-order", Arguments: map[string]any{"orderId": "o-1"}})
+	require.NoError(t, err)
+	require.Nil(t, server.Handle(context.Background(), req).Error)
+	workflowID := node.triggers[0].WorkflowID.String()
+
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "apikey:support"})
+	ctx = auth.WithAuthorizer(ctx, denyAuthorizer{schemaID: "refund-order"})
+	req, err = mcp.NewRequest(2, mcp.MethodResourcesRead, mcp.ReadResourceParams{URI: workflowResourcePrefix + workflowID})
+	require.NoError(t, err)
+	resp := server.Handle(ctx, req)
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, mcp.CodeResourceNotFound, resp.Error.Code, "executions the caller may not read are not found")
+}
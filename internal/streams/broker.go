@@ -0,0 +1,198 @@
+// Package streams carries the incremental output of running nodes, such as LLM token deltas, to
+// clients following an execution live over server-sent events. Streams are held in memory on the
+// node that runs the execution and are best effort: the journaled node output remains the
+// durable result.
+package streams
+
+import (
+	"sync"
+	"time"
+)
+
+// Event types published on an execution stream.
+const (
+	// EventDelta carries a piece of model output: {"content": "..."}.
+	EventDelta = "delta"
+	// EventStep marks the end of an agent iteration that called tools; the deltas before it were
+	// intermediate reasoning rather than the final answer.
+	EventStep = "step"
+	// EventDone is the last event of a stream: {"status": "success"|"error"}.
+	EventDone = "done"
+)
+
+const (
+	// DefaultRetention is how long a closed stream stays readable for clients that connect late.
+	DefaultRetention = time.Minute
+	// maxBacklog is the number of recent events a stream keeps for clients that connect or
+	// reconnect mid-stream.
+	maxBacklog = 4096
+	// subscriberBuffer is the live-event buffer of one subscriber. A subscriber that falls further
+	// behind is disconnected and can resume from the backlog with its last event ID.
+	subscriberBuffer = 256
+)
+
+// Event is one entry of an execution stream.
+type Event struct {
+	// ID is the event's position in its stream, starting at 1.
+	ID   uint64         `json:"id"`
+	Type string         `json:"type"`
+	Data map[string]any `json:"data,omitempty"`
+}
+
+// Broker fans the events of execution streams out to their subscribers. A stream is keyed by
+// workflow and exec ID, opened by its first event or subscriber and ended by Close.
+type Broker struct {
+	mu        sync.Mutex
+	streams   map[string]*stream
+	retention time.Duration
+}
+
+type stream struct {
+	events []Event
+	lastID uint64
+	subs   map[*Subscription]struct{}
+	closed bool
+}
+
+// Subscription receives the live events of one stream.
+type Subscription struct {
+	broker *Broker
+	key    string
+	events chan Event
+}
+
+// NewBroker creates a Broker that keeps closed streams readable for retention.
+func NewBroker(retention time.Duration) *Broker {
+	return &Broker{streams: make(map[string]*stream), retention: retention}
+}
+
+// Publish appends event to the execution's stream, assigning its ID, and delivers it to the
+// current subscribers. Publishing to a closed stream starts a new one, as a re-run of the same
+// execution does.
+func (b *Broker) Publish(workflowID, execID string, event Event) {
+	key := streamKey(workflowID, execID)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s := b.streams[key]
+	if s == nil || s.closed {
+		s = &stream{subs: make(map[*Subscription]struct{})}
+		b.streams[key] = s
+	}
+	s.lastID++
+	event.ID = s.lastID
+	s.events = append(s.events, event)
+	if len(s.events) > 2*maxBacklog {
+		s.events = append([]Event(nil), s.events[len(s.events)-maxBacklog:]...)
+	}
+	for sub := range s.subs {
+		select {
+		case sub.events <- event:
+		default:
+			delete(s.subs, sub)
+			close(sub.events)
+		}
+	}
+}
+
+// Close ends the execution's stream: subscribers' channels are closed and the stream is dropped
+// after the retention period.
+func (b *Broker) Close(workflowID, execID string) {
+	key := streamKey(workflowID, execID)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s := b.streams[key]
+	if s == nil || s.closed {
+		return
+	}
+	s.closed = true
+	for sub := range s.subs {
+		close(sub.events)
+	}
+	s.subs = nil
+	time.AfterFunc(b.retention, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if b.streams[key] == s {
+			delete(b.streams, key)
+		}
+	})
+}
+
+// Subscribe returns the retained events of the execution's stream after the given event ID and a
+// subscription to the events that follow. The subscription's channel is closed when the stream
+// ends, at once if it already has; a stream that has not started yet is waited for.
+func (b *Broker) Subscribe(workflowID, execID string, after uint64) ([]Event, *Subscription) {
+	key := streamKey(workflowID, execID)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s := b.streams[key]
+	if s == nil {
+		s = &stream{subs: make(map[*Subscription]struct{})}
+		b.streams[key] = s
+	}
+	backlog := s.after(after)
+	sub := &Subscription{broker: b, key: key, events: make(chan Event, subscriberBuffer)}
+	if s.closed {
+		close(sub.events)
+	} else {
+		s.subs[sub] = struct{}{}
+	}
+	return backlog, sub
+}
+
+// Retained returns the events of the execution's stream after the given event ID that are still
+// held, without waiting for more; it is for executions that already ended, and opens no stream.
+func (b *Broker) Retained(workflowID, execID string, after uint64) []Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s := b.streams[streamKey(workflowID, execID)]
+	if s == nil {
+		return nil
+	}
+	return s.after(after)
+}
+
+// after returns the stream's retained events after the given event ID.
+func (s *stream) after(id uint64) []Event {
+	var events []Event
+	for _, e := range s.events {
+		if e.ID > id {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+// Events returns the channel of live events; it is closed when the stream ends or the subscriber
+// falls too far behind.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Cancel stops the subscription. A stream that never started is forgotten once its last
+// subscriber leaves.
+func (s *Subscription) Cancel() {
+	b := s.broker
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	st := b.streams[s.key]
+	if st == nil {
+		return
+	}
+	if _, ok := st.subs[s]; ok {
+		delete(st.subs, s)
+		close(s.events)
+	}
+	if !st.closed && len(st.subs) == 0 && len(st.events) == 0 {
+		delete(b.streams, s.key)
+	}
+}
+
+func streamKey(workflowID, execID string) string {
+	return workflowID + "/" + execID
+}
@@ -0,0 +1,118 @@
+package streams
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func delta(content string) Event {
+	return Event{Type: EventDelta, Data: map[string]any{"content": content}}
+}
+
+// drain reads a subscription until its channel is closed.
+func drain(t *testing.T, sub *Subscription) []Event {
+	t.Helper()
+	var got []Event
+	for {
+		select {
+		case e, ok := <-sub.Events():
+			if !ok {
+				return got
+			}
+			got = append(got, e)
+		case <-time.After(time.Second):
+			t.Fatal("subscription was not closed")
+		}
+	}
+}
+
+func TestBroker_SubscriberGetsBacklogThenLiveEvents(t *testing.T) {
+	b := NewBroker(time.Minute)
+	b.Publish("wf", "ex", delta("Hel"))
+
+	backlog, sub := b.Subscribe("wf", "ex", 0)
+	require.Len(t, backlog, 1)
+	assert.Equal(t, uint64(1), backlog[0].ID)
+
+	b.Publish("wf", "ex", delta("lo"))
+	b.Publish("wf", "ex", Event{Type: EventDone, Data: map[string]any{"status": "success"}})
+	b.Close("wf", "ex")
+
+	live := drain(t, sub)
+	require.Len(t, live, 2)
+	assert.Equal(t, uint64(2), live[0].ID)
+	assert.Equal(t, EventDone, live[1].Type)
+}
+
+func TestBroker_ResumesAfterLastEventID(t *testing.T) {
+	b := NewBroker(time.Minute)
+	for _, c := range []string{"a", "b", "c"} {
+		b.Publish("wf", "ex", delta(c))
+	}
+	b.Close("wf", "ex")
+
+	backlog, sub := b.Subscribe("wf", "ex", 2)
+	require.Len(t, backlog, 1)
+	assert.Equal(t, "c", backlog[0].Data["content"])
+	assert.Empty(t, drain(t, sub), "a closed stream has no live events")
+}
+
+func TestBroker_WaitsForAStreamThatHasNotStarted(t *testing.T) {
+	b := NewBroker(time.Minute)
+	backlog, sub := b.Subscribe("wf", "ex", 0)
+	assert.Empty(t, backlog)
+
+	b.Publish("wf", "ex", delta("hi"))
+	b.Close("wf", "ex")
+	assert.Len(t, drain(t, sub), 1)
+}
+
+func TestBroker_ClosedStreamIsDroppedAfterRetention(t *testing.T) {
+	b := NewBroker(10 * time.Millisecond)
+	b.Publish("wf", "ex", delta("hi"))
+	b.Close("wf", "ex")
+
+	require.Eventually(t, func() bool {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		return len(b.streams) == 0
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestBroker_CancelForgetsAnUnstartedStream(t *testing.T) {
+	b := NewBroker(time.Minute)
+	_, sub := b.Subscribe("wf", "ex", 0)
+	sub.Cancel()
+
+	assert.Empty(t, drain(t, sub))
+	assert.Empty(t, b.streams)
+}
+
+func TestBroker_RetainedOpensNoStream(t *testing.T) {
+	b := NewBroker(time.Minute)
+	b.Publish("wf", "ex", delta("a"))
+	b.Publish("wf", "ex", delta("b"))
+	b.Close("wf", "ex")
+
+	retained := b.Retained("wf", "ex", 1)
+	require.Len(t, retained, 1)
+	assert.Equal(t, uint64(2), retained[0].ID)
+
+	assert.Empty(t, b.Retained("wf", "unknown", 0))
+	assert.Len(t, b.streams, 1, "reading an unknown stream leaves nothing behind")
+}
+
+func TestBroker_SlowSubscriberIsDisconnected(t *testing.T) {
+	b := NewBroker(time.Minute)
+	_, sub := b.Subscribe("wf", "ex", 0)
+	for i := 0; i < subscriberBuffer+1; i++ {
+		b.Publish("wf", "ex", delta("x"))
+	}
+
+	assert.Len(t, drain(t, sub), subscriberBuffer, "the channel is closed once the buffer overflows")
+	backlog, _ := b.Subscribe("wf", "ex", subscriberBuffer)
+	assert.Len(t, backlog, 1, "the subscriber can resume from its last event")
+}
@@ -0,0 +1,79 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/open-source-cloud/fuse/internal/workflow/workflowactions"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func triggerTestWorkflow(t *testing.T) (*Workflow, workflow.ExecID) {
+	t.Helper()
+	wf := New(workflow.NewID(), loadTestGraph(t), "default")
+	action := wf.Trigger()
+	require.NotNil(t, action)
+	return wf, action.(*workflowactions.RunFunctionAction).FunctionExecID
+}
+
+func TestCheckpoint_RecordsInOrder(t *testing.T) {
+	wf, execID := triggerTestWorkflow(t)
+
+	require.True(t, wf.Checkpoint(execID, 0, map[string]any{"iteration": 0}))
+	require.True(t, wf.Checkpoint(execID, 1, map[string]any{"iteration": 1}))
+
+	assert.Equal(t, []map[string]any{{"iteration": 0}, {"iteration": 1}}, wf.Checkpoints(execID))
+
+	entries := wf.Journal().Entries()
+	last := entries[len(entries)-1]
+	assert.Equal(t, JournalStepCheckpoint, last.Type)
+	assert.Equal(t, execID.String(), last.ExecID)
+}
+
+func TestCheckpoint_RejectsOutOfOrderIndex(t *testing.T) {
+	wf, execID := triggerTestWorkflow(t)
+
+	assert.False(t, wf.Checkpoint(execID, 1, map[string]any{"iteration": 1}), "gap")
+	require.True(t, wf.Checkpoint(execID, 0, map[string]any{"iteration": 0}))
+	assert.False(t, wf.Checkpoint(execID, 0, map[string]any{"iteration": 0}), "duplicate")
+	assert.Len(t, wf.Checkpoints(execID), 1)
+}
+
+func TestCheckpoint_RejectsUnknownOrFinishedExec(t *testing.T) {
+	wf, execID := triggerTestWorkflow(t)
+
+	assert.False(t, wf.Checkpoint(workflow.NewExecID(0), 0, map[string]any{}), "unknown exec")
+
+	result := workflow.NewFunctionResultSuccessWith(map[string]any{"ok": true})
+	wf.SetResultFor(execID, &result)
+	assert.False(t, wf.Checkpoint(execID, 0, map[string]any{}), "completed exec")
+	assert.Empty(t, wf.Checkpoints(execID))
+}
+
+func TestCheckpoints_ResetOnRetry(t *testing.T) {
+	wf, execID := triggerTestWorkflow(t)
+	require.True(t, wf.Checkpoint(execID, 0, map[string]any{"iteration": 0}))
+
+	failed := workflow.NewFunctionResultSuccessWith(map[string]any{"error": "boom"})
+	failed.Output.Status = workflow.FunctionError
+	wf.SetResultFor(execID, &failed)
+	wf.Journal().Append(JournalEntry{Type: JournalStepRetrying, ExecID: execID.String()})
+
+	assert.Empty(t, wf.Checkpoints(execID), "a retried attempt starts over")
+	assert.True(t, wf.Checkpoint(execID, 0, map[string]any{"iteration": 0}))
+}
+
+func TestCheckpoints_SurviveReplay(t *testing.T) {
+	wf, execID := triggerTestWorkflow(t)
+	require.True(t, wf.Checkpoint(execID, 0, map[string]any{"iteration": 0}))
+
+	resumed := New(wf.ID(), wf.Graph(), "default")
+	resumed.Journal().LoadFrom(wf.Journal().Entries())
+	action := resumed.Resume()
+
+	run, ok := action.(*workflowactions.RunFunctionAction)
+	require.True(t, ok, "pending step should be replayed, got %T", action)
+	assert.Equal(t, execID, run.FunctionExecID)
+	assert.Equal(t, []map[string]any{{"iteration": 0}}, resumed.Checkpoints(execID))
+}
@@ -25,6 +25,14 @@ const (
 	// The value is read from the SecretStore at cred/<id>/<field>, scoped by the workflow's
 	// environment, and redacted in every engine sink (ADR-0031).
 	SourceCredential InputMappingSource = "credential"
+	// SourceVar resolves a non-secret environment variable by name (held in Variable) from the
+	// values pinned when the execution was triggered. The value is not redacted and is converted
+	// to the destination parameter's type.
+	SourceVar InputMappingSource = "var"
+	// SourceInput reads a field (named in Variable) of the execution's trigger input, such as a
+	// webhook body or the input a parent passed to a sub-workflow. The value is converted to the
+	// destination parameter's type.
+	SourceInput InputMappingSource = "input"
 )
 
 type (
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"maps"
+	"slices"
 
 	"github.com/go-playground/validator/v10"
 	pkgworkflow "github.com/open-source-cloud/fuse/pkg/workflow"
@@ -25,6 +26,17 @@ type GraphSchema struct {
 	Timeout       *GraphTimeoutConfig            `json:"timeout,omitempty"`
 	Concurrency   *pkgworkflow.ConcurrencyConfig `json:"concurrency,omitempty"`
 	TriggerConfig *TriggerConfig                 `json:"triggerConfig,omitempty"`
+	Retention     *RetentionPolicy               `json:"retention,omitempty"`
+	// Input is the contract of the trigger input: the fields source:"input" mappings read. It
+	// documents the schema for callers, and ai/orchestrate offers it to the model as the
+	// schema's tool parameters.
+	Input []pkgworkflow.ParameterSchema `json:"input,omitempty" validate:"omitempty,dive"`
+	// LLMBudget caps the LLM spend of the schema's ai nodes per execution and per day.
+	LLMBudget *pkgworkflow.LLMBudget `json:"llmBudget,omitempty"`
+	// SearchAttributes are evaluated as the execution progresses and indexed for execution search.
+	SearchAttributes []SearchAttribute `json:"searchAttributes,omitempty" validate:"omitempty,dive"`
+	// MCP offers the schema as a tool of the engine's MCP endpoint.
+	MCP *MCPConfig `json:"mcp,omitempty"`
 }
 
 // NewGraphSchemaFromJSON creates a new graph schema from a JSON specification
@@ -40,7 +52,10 @@ func NewGraphSchemaFromJSON(jsonSpec []byte) (*GraphSchema, error) {
 // Validate validates the graph schema
 func (f *GraphSchema) Validate() error {
 	validate := validator.New(validator.WithRequiredStructEnabled())
-	return validate.Struct(f)
+	if err := validate.Struct(f); err != nil {
+		return err
+	}
+	return validateSearchAttributes(f.SearchAttributes)
 }
 
 // Clone clones the graph schema and returns a new instance
@@ -73,5 +88,10 @@ func (f *GraphSchema) Clone() GraphSchema {
 		tc := *f.TriggerConfig
 		clone.TriggerConfig = &tc
 	}
+	clone.Retention = f.Retention.Clone()
+	clone.LLMBudget = f.LLMBudget.Clone()
+	clone.Input = slices.Clone(f.Input)
+	clone.SearchAttributes = slices.Clone(f.SearchAttributes)
+	clone.MCP = f.MCP.Clone()
 	return clone
 }
@@ -55,6 +55,59 @@ func TestGraphSchema_Clone_CopiesTriggerConfig(t *testing.T) {
 	assert.Equal(t, "0 */5 * * *", clone.TriggerConfig.Cron.Expression)
 }
 
+func TestGraphSchema_Clone_CopiesLLMBudget(t *testing.T) {
+	original := GraphSchema{
+		ID:    "test",
+		Name:  "Test",
+		Nodes: []*NodeSchema{{ID: "n1", Function: "debug/print"}},
+		Edges: []*EdgeSchema{},
+		LLMBudget: &pkgworkflow.LLMBudget{
+			PerExecution: &pkgworkflow.BudgetLimit{MaxCostUSD: 0.5},
+			Daily:        &pkgworkflow.BudgetLimit{MaxTokens: 100000},
+		},
+	}
+
+	clone := original.Clone()
+
+	original.LLMBudget.PerExecution.MaxCostUSD = 9
+	original.LLMBudget.Daily = nil
+	require.NotNil(t, clone.LLMBudget)
+	assert.InDelta(t, 0.5, clone.LLMBudget.PerExecution.MaxCostUSD, 0)
+	require.NotNil(t, clone.LLMBudget.Daily)
+	assert.Equal(t, 100000, clone.LLMBudget.Daily.MaxTokens)
+}
+
+func TestGraphSchema_Clone_CopiesMCP(t *testing.T) {
+	original := GraphSchema{
+		ID:    "test",
+		Name:  "Test",
+		Nodes: []*NodeSchema{{ID: "n1", Function: "debug/print"}},
+		Edges: []*EdgeSchema{},
+		MCP:   &MCPConfig{Expose: true, Description: "Refunds an order"},
+	}
+
+	clone := original.Clone()
+
+	original.MCP.Expose = false
+	require.NotNil(t, clone.MCP)
+	assert.True(t, clone.MCP.Exposed())
+	assert.Equal(t, "Refunds an order", clone.MCP.Description)
+}
+
+func TestGraphSchema_Validate_RejectsNegativeLLMBudget(t *testing.T) {
+	schema := GraphSchema{
+		ID:        "test",
+		Name:      "Test",
+		Nodes:     []*NodeSchema{{ID: "n1", Function: "debug/print"}},
+		Edges:     []*EdgeSchema{},
+		LLMBudget: &pkgworkflow.LLMBudget{Daily: &pkgworkflow.BudgetLimit{MaxCostUSD: -1}},
+	}
+
+	assert.Error(t, schema.Validate())
+	schema.LLMBudget.Daily.MaxCostUSD = 5
+	assert.NoError(t, schema.Validate())
+}
+
 func TestGraphSchema_Clone_NilOptionalFields(t *testing.T) {
 	original := GraphSchema{
 		ID:   "test",
@@ -70,6 +123,9 @@ func TestGraphSchema_Clone_NilOptionalFields(t *testing.T) {
 	assert.Nil(t, clone.Concurrency)
 	assert.Nil(t, clone.TriggerConfig)
 	assert.Nil(t, clone.Timeout)
+	assert.Nil(t, clone.LLMBudget)
+	assert.Nil(t, clone.MCP)
+	assert.False(t, clone.MCP.Exposed())
 }
 
 func TestGraphSchema_JSON_WithTriggerConfig(t *testing.T) {
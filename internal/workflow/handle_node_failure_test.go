@@ -75,3 +75,58 @@ func TestHandleNodeFailure_RetriesWhenPolicyAllows(t *testing.T) {
 	act3 := w.HandleNodeFailure(threadID, execID)
 	require.Nil(t, act3, "after max retries with no onError edge, expect nil (terminal error)")
 }
+
+func TestHandleNodeFailure_RoutesConditionalOnErrorEdges(t *testing.T) {
+	t.Parallel()
+
+	schema, err := NewGraphSchemaFromJSON([]byte(`{
+		"id": "error-edge-conditional",
+		"name": "Conditional error edges",
+		"nodes": [
+			{"id": "trigger", "function": "fuse/pkg/debug/nil"},
+			{"id": "ai", "function": "fuse/pkg/debug/fail"},
+			{"id": "over-budget", "function": "fuse/pkg/debug/nil"},
+			{"id": "rate-limited", "function": "fuse/pkg/logic/timer"},
+			{"id": "fallback", "function": "fuse/pkg/logic/sum"}
+		],
+		"edges": [
+			{"id": "e1", "from": "trigger", "to": "ai"},
+			{"id": "e2", "from": "ai", "to": "over-budget", "onError": true,
+				"conditional": {"name": "budget", "value": "budget_exceeded"}},
+			{"id": "e3", "from": "ai", "to": "rate-limited", "onError": true,
+				"conditional": {"name": "rate", "type": "expression", "expression": "output.status == 429"}},
+			{"id": "e4", "from": "ai", "to": "fallback", "onError": true,
+				"conditional": {"name": "other", "type": "default"}}
+		]
+	}`))
+	require.NoError(t, err)
+
+	cases := []struct {
+		name   string
+		output map[string]any
+		want   string
+	}{
+		{"exact matches errorType", map[string]any{"error": "over", "errorType": "budget_exceeded"}, "fuse/pkg/debug/nil"},
+		{"expression", map[string]any{"error": "slow down", "status": 429}, "fuse/pkg/logic/timer"},
+		{"default", map[string]any{"error": "boom"}, "fuse/pkg/logic/sum"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			g, err := NewGraph(schema)
+			require.NoError(t, err)
+			w := New(pkgwf.ID("wf-"+tc.name), g, "default")
+
+			node, err := g.FindNode("ai")
+			require.NoError(t, err)
+			threadID := node.Thread()
+			execID := pkgwf.NewExecID(threadID)
+			w.threads.New(threadID, execID)
+			w.auditLog.NewEntry(threadID, node.ID(), execID.String(), map[string]any{})
+			w.aggregatedOutput.Set(node.ID(), tc.output)
+
+			run, ok := w.HandleNodeFailure(threadID, execID).(*workflowactions.RunFunctionAction)
+			require.True(t, ok)
+			require.Equal(t, tc.want, run.FunctionID)
+		})
+	}
+}
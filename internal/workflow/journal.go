@@ -17,6 +17,9 @@ const (
 	JournalStepCompleted JournalEntryType = "step:completed"
 	// JournalStepFailed a function step failed
 	JournalStepFailed JournalEntryType = "step:failed"
+	// JournalStepCheckpoint a running function step journaled one of its sub-steps (e.g. an
+	// ai/agent reasoning iteration); Data holds the function-defined checkpoint
+	JournalStepCheckpoint JournalEntryType = "step:checkpoint"
 	// JournalStepRetrying a function step is being retried
 	JournalStepRetrying JournalEntryType = "step:retrying"
 	// JournalThreadCreated a new thread was created
@@ -47,6 +50,11 @@ const (
 	JournalForEachIterationCompleted JournalEntryType = "foreach:iteration:completed"
 	// JournalForEachCompleted all foreach iterations have completed
 	JournalForEachCompleted JournalEntryType = "foreach:completed"
+	// JournalToolCallStarted a running node started a function as an async tool call (ADR-0027);
+	// ExecID is the child execution and Data holds the parent exec id and function id
+	JournalToolCallStarted JournalEntryType = "tool:started"
+	// JournalToolCallCompleted an async tool call completed and its result was routed to the node
+	JournalToolCallCompleted JournalEntryType = "tool:completed"
 )
 
 // JournalEntry is a single recorded event in the execution journal
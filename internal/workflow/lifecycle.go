@@ -0,0 +1,40 @@
+package workflow
+
+// SchemaLifecycle is the lifecycle state of a workflow schema. It is stored alongside the schema
+// (not inside its definition), so changing it never creates a new schema version.
+type SchemaLifecycle string
+
+const (
+	// SchemaActive schemas accept new executions from every trigger
+	SchemaActive SchemaLifecycle = "active"
+	// SchemaDeprecated schemas keep their triggers registered but reject new executions;
+	// executions already in flight run to completion
+	SchemaDeprecated SchemaLifecycle = "deprecated"
+	// SchemaArchived schemas reject new executions and have their cron, webhook and event
+	// triggers unregistered; they are hidden from schema listings by default
+	SchemaArchived SchemaLifecycle = "archived"
+)
+
+// SchemaLifecycles lists every valid lifecycle state.
+var SchemaLifecycles = []SchemaLifecycle{SchemaActive, SchemaDeprecated, SchemaArchived}
+
+// IsValid reports whether l is a known lifecycle state.
+func (l SchemaLifecycle) IsValid() bool {
+	switch l {
+	case SchemaActive, SchemaDeprecated, SchemaArchived:
+		return true
+	default:
+		return false
+	}
+}
+
+// AcceptsExecutions reports whether new executions may be triggered for the schema.
+// Schemas stored before lifecycles existed carry an empty state and count as active.
+func (l SchemaLifecycle) AcceptsExecutions() bool {
+	return l == "" || l == SchemaActive
+}
+
+// KeepsTriggers reports whether the schema's cron, webhook and event triggers stay registered.
+func (l SchemaLifecycle) KeepsTriggers() bool {
+	return l != SchemaArchived
+}
@@ -0,0 +1,30 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaLifecycle(t *testing.T) {
+	tests := []struct {
+		lifecycle         SchemaLifecycle
+		valid             bool
+		acceptsExecutions bool
+		keepsTriggers     bool
+	}{
+		{lifecycle: "", valid: false, acceptsExecutions: true, keepsTriggers: true},
+		{lifecycle: SchemaActive, valid: true, acceptsExecutions: true, keepsTriggers: true},
+		{lifecycle: SchemaDeprecated, valid: true, acceptsExecutions: false, keepsTriggers: true},
+		{lifecycle: SchemaArchived, valid: true, acceptsExecutions: false, keepsTriggers: false},
+		{lifecycle: "retired", valid: false, acceptsExecutions: false, keepsTriggers: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.lifecycle), func(t *testing.T) {
+			assert.Equal(t, tt.valid, tt.lifecycle.IsValid())
+			assert.Equal(t, tt.acceptsExecutions, tt.lifecycle.AcceptsExecutions())
+			assert.Equal(t, tt.keepsTriggers, tt.lifecycle.KeepsTriggers())
+		})
+	}
+}
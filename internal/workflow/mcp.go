@@ -0,0 +1,28 @@
+package workflow
+
+// MCPConfig opts a schema into the engine's MCP endpoint, where it is offered to external AI
+// assistants as a tool whose parameters are the schema's input contract.
+type MCPConfig struct {
+	// Expose lists the schema as a tool of its namespace's MCP endpoint.
+	Expose bool `json:"expose"`
+	// Description tells the assistant what the tool does; it defaults to the "description"
+	// metadata entry.
+	Description string `json:"description,omitempty" validate:"lte=1024"`
+	// Wait is how long a tool call waits for the execution to end before it answers with a
+	// handle to poll instead. Zero uses the server default.
+	Wait FlexibleDuration `json:"wait,omitempty" swaggertype:"string" example:"30s"`
+}
+
+// Exposed reports whether the schema is offered over MCP. A nil config is not.
+func (c *MCPConfig) Exposed() bool {
+	return c != nil && c.Expose
+}
+
+// Clone returns a copy of the config.
+func (c *MCPConfig) Clone() *MCPConfig {
+	if c == nil {
+		return nil
+	}
+	clone := *c
+	return &clone
+}
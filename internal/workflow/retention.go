@@ -0,0 +1,43 @@
+package workflow
+
+import "maps"
+
+// RetentionStates are the terminal workflow states subject to retention; running and sleeping
+// executions are never purged.
+var RetentionStates = []State{StateFinished, StateError, StateCancelled}
+
+// RetentionPolicy configures retention of terminal executions. Default applies to every terminal
+// state; States overrides it for individual states (e.g. keep errors longer than successes).
+type RetentionPolicy struct {
+	Default TraceRetentionConfig           `json:"default"`
+	States  map[State]TraceRetentionConfig `json:"states,omitempty"`
+}
+
+// For returns the limits configured for the given state, falling back to Default.
+// A nil policy yields a zero config (keep forever).
+func (p *RetentionPolicy) For(state State) TraceRetentionConfig {
+	if p == nil {
+		return TraceRetentionConfig{}
+	}
+	if cfg, ok := p.States[state]; ok && !cfg.IsZero() {
+		return cfg
+	}
+	return p.Default
+}
+
+// Resolve returns the effective limits for a state: the schema policy wins when it sets
+// anything for that state, otherwise the global policy applies.
+func (p *RetentionPolicy) Resolve(state State, global *RetentionPolicy) TraceRetentionConfig {
+	if cfg := p.For(state); !cfg.IsZero() {
+		return cfg
+	}
+	return global.For(state)
+}
+
+// Clone returns a deep copy of the policy.
+func (p *RetentionPolicy) Clone() *RetentionPolicy {
+	if p == nil {
+		return nil
+	}
+	return &RetentionPolicy{Default: p.Default, States: maps.Clone(p.States)}
+}
@@ -0,0 +1,46 @@
+package workflow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetentionPolicy_Resolve(t *testing.T) {
+	global := &RetentionPolicy{
+		Default: TraceRetentionConfig{MaxAge: FlexibleDuration(24 * time.Hour)},
+		States: map[State]TraceRetentionConfig{
+			StateError: {MaxAge: FlexibleDuration(72 * time.Hour)},
+		},
+	}
+	schema := &RetentionPolicy{
+		States: map[State]TraceRetentionConfig{
+			StateCancelled: {MaxCount: 10},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		policy *RetentionPolicy
+		state  State
+		want   TraceRetentionConfig
+	}{
+		{name: "nil schema falls back to global default", policy: nil, state: StateFinished, want: global.Default},
+		{name: "global state override", policy: nil, state: StateError, want: global.States[StateError]},
+		{name: "schema state override wins", policy: schema, state: StateCancelled, want: TraceRetentionConfig{MaxCount: 10}},
+		{name: "schema without limits for state defers to global", policy: schema, state: StateFinished, want: global.Default},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.policy.Resolve(tt.state, global))
+		})
+	}
+}
+
+func TestRetentionPolicy_NilIsKeepForever(t *testing.T) {
+	var p *RetentionPolicy
+	assert.True(t, p.Resolve(StateFinished, nil).IsZero())
+	assert.Nil(t, p.Clone())
+}
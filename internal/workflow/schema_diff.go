@@ -0,0 +1,163 @@
+package workflow
+
+import (
+	"reflect"
+	"slices"
+)
+
+type (
+	// SchemaDiff is the structural difference between two graph schemas. Nodes and edges are
+	// matched by ID; an element present in both with different configuration is reported as
+	// changed together with the fields that differ.
+	SchemaDiff struct {
+		NodesAdded   []string   `json:"nodesAdded"`
+		NodesRemoved []string   `json:"nodesRemoved"`
+		NodesChanged []NodeDiff `json:"nodesChanged"`
+		EdgesAdded   []string   `json:"edgesAdded"`
+		EdgesRemoved []string   `json:"edgesRemoved"`
+		EdgesChanged []EdgeDiff `json:"edgesChanged"`
+		// Changes lists schema-level fields (name, timeout, concurrency, triggers, retention) that differ.
+		Changes []FieldChange `json:"changes"`
+	}
+
+	// NodeDiff lists the fields that differ for a node present in both schemas.
+	NodeDiff struct {
+		ID      string        `json:"id"`
+		Changes []FieldChange `json:"changes"`
+	}
+
+	// EdgeDiff lists the fields that differ for an edge present in both schemas.
+	EdgeDiff struct {
+		ID      string        `json:"id"`
+		Changes []FieldChange `json:"changes"`
+	}
+
+	// FieldChange is a single field whose value differs between the two schemas.
+	FieldChange struct {
+		Field string `json:"field"`
+		From  any    `json:"from"`
+		To    any    `json:"to"`
+	}
+)
+
+// IsEmpty reports whether the two schemas are structurally identical.
+func (d SchemaDiff) IsEmpty() bool {
+	return len(d.NodesAdded) == 0 && len(d.NodesRemoved) == 0 && len(d.NodesChanged) == 0 &&
+		len(d.EdgesAdded) == 0 && len(d.EdgesRemoved) == 0 && len(d.EdgesChanged) == 0 &&
+		len(d.Changes) == 0
+}
+
+// DiffSchemas compares schema a (the base) against schema b. Every list in the result is
+// non-nil and sorted by ID so the diff is stable and serialises to empty arrays.
+func DiffSchemas(a, b *GraphSchema) SchemaDiff {
+	diff := SchemaDiff{
+		NodesAdded:   []string{},
+		NodesRemoved: []string{},
+		NodesChanged: []NodeDiff{},
+		EdgesAdded:   []string{},
+		EdgesRemoved: []string{},
+		EdgesChanged: []EdgeDiff{},
+		Changes:      []FieldChange{},
+	}
+
+	fromNodes, toNodes := indexByID(a.Nodes, nodeID), indexByID(b.Nodes, nodeID)
+	for _, id := range sortedKeys(fromNodes) {
+		to, ok := toNodes[id]
+		if !ok {
+			diff.NodesRemoved = append(diff.NodesRemoved, id)
+			continue
+		}
+		if changes := diffNode(fromNodes[id], to); len(changes) > 0 {
+			diff.NodesChanged = append(diff.NodesChanged, NodeDiff{ID: id, Changes: changes})
+		}
+	}
+	for _, id := range sortedKeys(toNodes) {
+		if _, ok := fromNodes[id]; !ok {
+			diff.NodesAdded = append(diff.NodesAdded, id)
+		}
+	}
+
+	fromEdges, toEdges := indexByID(a.Edges, edgeID), indexByID(b.Edges, edgeID)
+	for _, id := range sortedKeys(fromEdges) {
+		to, ok := toEdges[id]
+		if !ok {
+			diff.EdgesRemoved = append(diff.EdgesRemoved, id)
+			continue
+		}
+		if changes := diffEdge(fromEdges[id], to); len(changes) > 0 {
+			diff.EdgesChanged = append(diff.EdgesChanged, EdgeDiff{ID: id, Changes: changes})
+		}
+	}
+	for _, id := range sortedKeys(toEdges) {
+		if _, ok := fromEdges[id]; !ok {
+			diff.EdgesAdded = append(diff.EdgesAdded, id)
+		}
+	}
+
+	var changes fieldChanges
+	changes.compare("name", a.Name, b.Name)
+	changes.compare("timeout", a.Timeout, b.Timeout)
+	changes.compare("concurrency", a.Concurrency, b.Concurrency)
+	changes.compare("triggerConfig", a.TriggerConfig, b.TriggerConfig)
+	changes.compare("retention", a.Retention, b.Retention)
+	changes.compare("llmBudget", a.LLMBudget, b.LLMBudget)
+	changes.compare("mcp", a.MCP, b.MCP)
+	// nil and empty input contracts are equivalent once serialised
+	if len(a.Input) > 0 || len(b.Input) > 0 {
+		changes.compare("input", a.Input, b.Input)
+	}
+	diff.Changes = append(diff.Changes, changes...)
+
+	return diff
+}
+
+func diffNode(a, b *NodeSchema) []FieldChange {
+	var changes fieldChanges
+	changes.compare("function", a.Function, b.Function)
+	changes.compare("retry", a.Retry, b.Retry)
+	changes.compare("timeout", a.Timeout, b.Timeout)
+	changes.compare("merge", a.Merge, b.Merge)
+	return changes
+}
+
+func diffEdge(a, b *EdgeSchema) []FieldChange {
+	var changes fieldChanges
+	changes.compare("from", a.From, b.From)
+	changes.compare("to", a.To, b.To)
+	changes.compare("conditional", a.Conditional, b.Conditional)
+	// nil and empty input mappings are equivalent once serialised
+	if len(a.Input) > 0 || len(b.Input) > 0 {
+		changes.compare("input", a.Input, b.Input)
+	}
+	changes.compare("onError", a.OnError, b.OnError)
+	return changes
+}
+
+type fieldChanges []FieldChange
+
+func (c *fieldChanges) compare(field string, from, to any) {
+	if !reflect.DeepEqual(from, to) {
+		*c = append(*c, FieldChange{Field: field, From: from, To: to})
+	}
+}
+
+func nodeID(n *NodeSchema) string { return n.ID }
+
+func edgeID(e *EdgeSchema) string { return e.ID }
+
+func indexByID[T any](items []T, id func(T) string) map[string]T {
+	index := make(map[string]T, len(items))
+	for _, item := range items {
+		index[id(item)] = item
+	}
+	return index
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
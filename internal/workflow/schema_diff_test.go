@@ -0,0 +1,80 @@
+package workflow_test
+
+import (
+	"testing"
+
+	"github.com/open-source-cloud/fuse/internal/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func diffTestSchema() *workflow.GraphSchema {
+	return &workflow.GraphSchema{
+		ID:   "diff-schema",
+		Name: "Diff Schema",
+		Nodes: []*workflow.NodeSchema{
+			{ID: "trigger", Function: "system/trigger"},
+			{ID: "fetch", Function: "http/get", Retry: &workflow.RetryPolicy{MaxAttempts: 3}},
+			{ID: "log", Function: "debug/log"},
+		},
+		Edges: []*workflow.EdgeSchema{
+			{ID: "trigger-fetch", From: "trigger", To: "fetch"},
+			{ID: "fetch-log", From: "fetch", To: "log"},
+		},
+	}
+}
+
+func TestDiffSchemas_Identical(t *testing.T) {
+	diff := workflow.DiffSchemas(diffTestSchema(), diffTestSchema())
+
+	assert.True(t, diff.IsEmpty())
+	assert.NotNil(t, diff.NodesAdded)
+	assert.NotNil(t, diff.EdgesChanged)
+}
+
+func TestDiffSchemas_NodesAndEdges(t *testing.T) {
+	from := diffTestSchema()
+	to := diffTestSchema()
+	to.Nodes = append(to.Nodes[:2], &workflow.NodeSchema{ID: "notify", Function: "slack/post"})
+	to.Nodes[1].Retry = &workflow.RetryPolicy{MaxAttempts: 5}
+	to.Nodes[1].Merge = &workflow.MergeConfig{Strategy: workflow.MergeAppend}
+	to.Edges = []*workflow.EdgeSchema{
+		{ID: "trigger-fetch", From: "trigger", To: "fetch", OnError: true},
+		{ID: "fetch-notify", From: "fetch", To: "notify"},
+	}
+
+	diff := workflow.DiffSchemas(from, to)
+
+	assert.Equal(t, []string{"notify"}, diff.NodesAdded)
+	assert.Equal(t, []string{"log"}, diff.NodesRemoved)
+	require.Len(t, diff.NodesChanged, 1)
+	assert.Equal(t, "fetch", diff.NodesChanged[0].ID)
+	fields := make([]string, 0, len(diff.NodesChanged[0].Changes))
+	for _, change := range diff.NodesChanged[0].Changes {
+		fields = append(fields, change.Field)
+	}
+	assert.Equal(t, []string{"retry", "merge"}, fields)
+
+	assert.Equal(t, []string{"fetch-notify"}, diff.EdgesAdded)
+	assert.Equal(t, []string{"fetch-log"}, diff.EdgesRemoved)
+	require.Len(t, diff.EdgesChanged, 1)
+	assert.Equal(t, "trigger-fetch", diff.EdgesChanged[0].ID)
+	assert.Equal(t, []workflow.FieldChange{{Field: "onError", From: false, To: true}}, diff.EdgesChanged[0].Changes)
+	assert.Empty(t, diff.Changes)
+}
+
+func TestDiffSchemas_SchemaLevelChanges(t *testing.T) {
+	from := diffTestSchema()
+	to := diffTestSchema()
+	to.Name = "Renamed"
+	to.Timeout = &workflow.GraphTimeoutConfig{}
+
+	diff := workflow.DiffSchemas(from, to)
+
+	require.Len(t, diff.Changes, 2)
+	assert.Equal(t, "name", diff.Changes[0].Field)
+	assert.Equal(t, "Diff Schema", diff.Changes[0].From)
+	assert.Equal(t, "Renamed", diff.Changes[0].To)
+	assert.Equal(t, "timeout", diff.Changes[1].Field)
+	assert.Empty(t, diff.NodesChanged)
+}
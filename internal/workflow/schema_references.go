@@ -0,0 +1,68 @@
+package workflow
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/open-source-cloud/fuse/pkg/secrets"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+)
+
+// SchemaReferences lists the environment-scoped values a schema's input mappings need at run time.
+// Each list is sorted and free of duplicates.
+type SchemaReferences struct {
+	Secrets []string `json:"secrets,omitempty"`
+	// Credentials holds "<id>.<field>" pairs.
+	Credentials []string `json:"credentials,omitempty"`
+	Vars        []string `json:"vars,omitempty"`
+}
+
+// References collects the secrets, credential fields and variables referenced by the schema's
+// edges, through source "secret", "credential" and "var" mappings or tokens embedded in
+// "schema" values.
+func (f *GraphSchema) References() SchemaReferences {
+	var refs SchemaReferences
+	for _, edge := range f.Edges {
+		for _, input := range edge.Input {
+			switch input.Source {
+			case SourceSecret:
+				refs.Secrets = append(refs.Secrets, input.Variable)
+			case SourceCredential:
+				refs.Credentials = append(refs.Credentials, input.Variable)
+			case SourceVar:
+				refs.Vars = append(refs.Vars, input.Variable)
+			case SourceSchema:
+				s, ok := input.Value.(string)
+				if !ok {
+					continue
+				}
+				refs.Secrets = append(refs.Secrets, secrets.SecretRefNames(s)...)
+				for _, ref := range secrets.CredentialRefs(s) {
+					refs.Credentials = append(refs.Credentials, ref[0]+"."+ref[1])
+				}
+				refs.Vars = append(refs.Vars, workflow.VarRefNames(s)...)
+			}
+		}
+	}
+	refs.Secrets = sortedUnique(refs.Secrets)
+	refs.Credentials = sortedUnique(refs.Credentials)
+	refs.Vars = sortedUnique(refs.Vars)
+	return refs
+}
+
+// SplitCredentialRef splits a "<id>.<field>" credential reference on its last dot.
+func SplitCredentialRef(ref string) (id, field string, ok bool) {
+	dot := strings.LastIndex(ref, ".")
+	if dot <= 0 || dot == len(ref)-1 {
+		return "", "", false
+	}
+	return ref[:dot], ref[dot+1:], true
+}
+
+func sortedUnique(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	slices.Sort(values)
+	return slices.Compact(values)
+}
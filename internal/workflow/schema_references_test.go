@@ -0,0 +1,38 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphSchema_References(t *testing.T) {
+	t.Parallel()
+	schema := &GraphSchema{
+		Edges: []*EdgeSchema{
+			{ID: "e1", Input: []InputMapping{
+				{Source: SourceSchema, Value: "{{var:BASE_URL}}/orders?key={{secret:api-key}}", MapTo: "url"},
+				{Source: SourceSchema, Value: 42, MapTo: "limit"},
+				{Source: SourceCredential, Variable: "my.crm.apiKey", MapTo: "key"},
+			}},
+			{ID: "e2", Input: []InputMapping{
+				{Source: SourceSecret, Variable: "api-key", MapTo: "token"},
+				{Source: SourceVar, Variable: "MAX_ITEMS", MapTo: "max"},
+				{Source: SourceSchema, Value: "Bearer {{credential:openai.apiKey}}", MapTo: "auth"},
+				{Source: SourceFlow, Variable: "trigger.{{var:IGNORED}}", MapTo: "x"},
+			}},
+		},
+	}
+
+	refs := schema.References()
+	assert.Equal(t, []string{"api-key"}, refs.Secrets)
+	assert.Equal(t, []string{"my.crm.apiKey", "openai.apiKey"}, refs.Credentials)
+	assert.Equal(t, []string{"BASE_URL", "MAX_ITEMS"}, refs.Vars)
+
+	id, field, ok := SplitCredentialRef("my.crm.apiKey")
+	assert.True(t, ok)
+	assert.Equal(t, "my.crm", id)
+	assert.Equal(t, "apiKey", field)
+	_, _, ok = SplitCredentialRef("nodot")
+	assert.False(t, ok)
+}
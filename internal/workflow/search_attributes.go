@@ -0,0 +1,88 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"strconv"
+
+	"github.com/expr-lang/expr"
+)
+
+// ErrInvalidSearchAttribute is returned when a schema declares a malformed search attribute
+var ErrInvalidSearchAttribute = errors.New("invalid search attribute")
+
+var searchAttributeName = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]{0,63}$`)
+
+// SearchAttribute declares a named, indexed value of an execution, computed by an expression over
+// the trigger input (`input`) and the outputs of the nodes that have run so far (by node ID), e.g.
+// `input.orderId` or `fetch.customer.id`. Executions can then be listed by attribute value.
+type SearchAttribute struct {
+	Name       string `json:"name" validate:"required"`
+	Expression string `json:"expression" validate:"required"`
+}
+
+func validateSearchAttributes(attrs []SearchAttribute) error {
+	seen := make(map[string]bool, len(attrs))
+	for _, attr := range attrs {
+		if !searchAttributeName.MatchString(attr.Name) {
+			return fmt.Errorf("%w: name %q must start with a letter and contain only letters, digits and underscores (max 64)", ErrInvalidSearchAttribute, attr.Name)
+		}
+		if seen[attr.Name] {
+			return fmt.Errorf("%w: %q is declared more than once", ErrInvalidSearchAttribute, attr.Name)
+		}
+		seen[attr.Name] = true
+		if _, err := expr.Compile(attr.Expression); err != nil {
+			return fmt.Errorf("%w: %q: %w", ErrInvalidSearchAttribute, attr.Name, err)
+		}
+	}
+	return nil
+}
+
+// EvaluateSearchAttributes evaluates the declared attributes against the trigger input and node
+// outputs. Attributes whose expression fails (typically because the node they read has not run
+// yet) or yields nil or a non-scalar value are left out; scalars are indexed in string form.
+func EvaluateSearchAttributes(attrs []SearchAttribute, input map[string]any, outputs map[string]any) map[string]string {
+	if len(attrs) == 0 {
+		return nil
+	}
+	env := make(map[string]any, len(outputs)+1)
+	maps.Copy(env, outputs)
+	if input == nil {
+		input = map[string]any{}
+	}
+	env["input"] = input
+
+	values := make(map[string]string, len(attrs))
+	for _, attr := range attrs {
+		result, err := expr.Eval(attr.Expression, env)
+		if err != nil {
+			continue
+		}
+		if value, ok := searchAttributeValue(result); ok {
+			values[attr.Name] = value
+		}
+	}
+	return values
+}
+
+func searchAttributeValue(v any) (string, bool) {
+	switch value := v.(type) {
+	case string:
+		return value, value != ""
+	case bool:
+		return strconv.FormatBool(value), true
+	case int:
+		return strconv.Itoa(value), true
+	case int64:
+		return strconv.FormatInt(value, 10), true
+	case float64:
+		// JSON numbers decode as float64: index 12345.0 as "12345" so it matches ?attr.x=12345
+		return strconv.FormatFloat(value, 'f', -1, 64), true
+	case fmt.Stringer:
+		return value.String(), true
+	default:
+		return "", false
+	}
+}
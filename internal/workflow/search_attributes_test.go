@@ -0,0 +1,71 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func searchableSchema(attrs ...SearchAttribute) GraphSchema {
+	return GraphSchema{
+		ID:               "orders",
+		Name:             "Orders",
+		Nodes:            []*NodeSchema{{ID: "n1", Function: "debug/print"}},
+		Edges:            []*EdgeSchema{},
+		SearchAttributes: attrs,
+	}
+}
+
+func TestGraphSchema_Validate_SearchAttributes(t *testing.T) {
+	tests := []struct {
+		name   string
+		attrs  []SearchAttribute
+		errMsg string
+	}{
+		{name: "valid", attrs: []SearchAttribute{{Name: "orderId", Expression: "input.orderId"}}},
+		{name: "invalid name", attrs: []SearchAttribute{{Name: "order-id", Expression: "input.orderId"}}, errMsg: "must start with a letter"},
+		{
+			name: "duplicate name",
+			attrs: []SearchAttribute{
+				{Name: "orderId", Expression: "input.orderId"},
+				{Name: "orderId", Expression: "input.id"},
+			},
+			errMsg: "more than once",
+		},
+		{name: "invalid expression", attrs: []SearchAttribute{{Name: "orderId", Expression: "input.("}}, errMsg: "orderId"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := searchableSchema(tt.attrs...)
+			err := schema.Validate()
+			if tt.errMsg == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrInvalidSearchAttribute)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestEvaluateSearchAttributes(t *testing.T) {
+	attrs := []SearchAttribute{
+		{Name: "orderId", Expression: "input.orderId"},
+		{Name: "customer", Expression: "fetch.customer.id"},
+		{Name: "express", Expression: "input.express"},
+		{Name: "items", Expression: "input.items"},
+		{Name: "pending", Expression: "charge.status"},
+	}
+	input := map[string]any{"orderId": float64(12345), "express": true, "items": []any{"a"}}
+	outputs := map[string]any{"fetch": map[string]any{"customer": map[string]any{"id": "c-1"}}}
+
+	values := EvaluateSearchAttributes(attrs, input, outputs)
+
+	// items is not a scalar and charge has not run yet, so both are left out
+	assert.Equal(t, map[string]string{
+		"orderId":  "12345",
+		"customer": "c-1",
+		"express":  "true",
+	}, values)
+}
@@ -0,0 +1,66 @@
+package workflow
+
+import "github.com/open-source-cloud/fuse/pkg/workflow"
+
+// StartToolCall journals that the running execution parentExecID started functionID as the async
+// tool call execID (ADR-0027). Tool calls are child executions outside the graph: they have no
+// audit entry and their results never advance a thread.
+func (w *Workflow) StartToolCall(parentExecID, execID workflow.ExecID, functionID string, input map[string]any) {
+	w.journal.Append(JournalEntry{
+		Type:     JournalToolCallStarted,
+		ThreadID: parentExecID.Thread(),
+		ExecID:   execID.String(),
+		Input:    input,
+		Data:     map[string]any{"parentExecId": parentExecID.String(), "functionId": functionID},
+	})
+}
+
+// CompleteToolCall journals the result of the async tool call execID.
+func (w *Workflow) CompleteToolCall(execID workflow.ExecID, result *workflow.FunctionResult) {
+	w.journal.Append(JournalEntry{
+		Type:     JournalToolCallCompleted,
+		ThreadID: execID.Thread(),
+		ExecID:   execID.String(),
+		Result:   result,
+	})
+}
+
+// OpenToolCalls returns the tool calls journaled as started but not completed, mapped to the
+// functions they run. After a restart their results must still be recognised as tool results
+// rather than routed into the graph.
+func (w *Workflow) OpenToolCalls() map[workflow.ExecID]string {
+	open := make(map[workflow.ExecID]string)
+	for _, e := range w.journal.Entries() {
+		switch e.Type {
+		case JournalToolCallStarted:
+			functionID, _ := e.Data["functionId"].(string)
+			open[workflow.ExecID(e.ExecID)] = functionID
+		case JournalToolCallCompleted:
+			delete(open, workflow.ExecID(e.ExecID))
+		}
+	}
+	return open
+}
+
+// ToolCallResult returns the journaled result of the async tool call execID, if it completed. A
+// node replayed after a restart issues its calls again under the same exec ids and gets their
+// recorded results instead of running them twice.
+func (w *Workflow) ToolCallResult(execID workflow.ExecID) (*workflow.FunctionResult, bool) {
+	for _, e := range w.journal.Entries() {
+		if e.Type == JournalToolCallCompleted && e.ExecID == execID.String() && e.Result != nil {
+			return e.Result, true
+		}
+	}
+	return nil, false
+}
+
+// ToolCallChild returns the sub-workflow the tool call execID started, if any.
+func (w *Workflow) ToolCallChild(execID workflow.ExecID) (workflow.ID, bool) {
+	for _, e := range w.journal.Entries() {
+		if e.Type == JournalSubWorkflowStarted && e.ExecID == execID.String() {
+			child, ok := e.Data["childWorkflowId"].(string)
+			return workflow.ID(child), ok
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,31 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToolCalls_JournaledOutsideTheGraph(t *testing.T) {
+	wf, parentExecID := triggerTestWorkflow(t)
+	first := workflow.NewExecID(parentExecID.Thread())
+	second := workflow.NewExecID(parentExecID.Thread())
+
+	wf.StartToolCall(parentExecID, first, "fuse/pkg/ai/chat", map[string]any{"input": "hi"})
+	wf.StartToolCall(parentExecID, second, "fuse/pkg/system/sleep", map[string]any{"duration": "1s"})
+	assert.Equal(t, map[workflow.ExecID]string{first: "fuse/pkg/ai/chat", second: "fuse/pkg/system/sleep"}, wf.OpenToolCalls())
+
+	result := workflow.NewFunctionResultSuccessWith(map[string]any{"output": "hello"})
+	wf.CompleteToolCall(first, &result)
+	assert.Equal(t, map[workflow.ExecID]string{second: "fuse/pkg/system/sleep"}, wf.OpenToolCalls())
+
+	entries := wf.Journal().Entries()
+	last := entries[len(entries)-1]
+	assert.Equal(t, JournalToolCallCompleted, last.Type)
+	assert.Equal(t, parentExecID.Thread(), last.ThreadID)
+	assert.Equal(t, &result, last.Result)
+
+	_, inAuditLog := wf.AuditLog().Get(first.String())
+	assert.False(t, inAuditLog, "a tool call is not a graph node execution")
+}
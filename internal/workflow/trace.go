@@ -16,6 +16,12 @@ type ExecutionTrace struct {
 	Duration    *string              `json:"duration,omitempty" example:"5s"`
 	Steps       []ExecutionStepTrace `json:"steps"`
 	Error       *string              `json:"error,omitempty"`
+	// LLMCostUSD is the priced cost of every LLM call the execution's ai nodes made.
+	LLMCostUSD float64 `json:"llmCostUSD,omitempty"`
+	// ParentWorkflowID is the workflow that started this execution as a sub-workflow, if any.
+	ParentWorkflowID string `json:"parentWorkflowId,omitempty"`
+	// ParentExecID is the parent's step, or the tool call of a parent step, that started it.
+	ParentExecID string `json:"parentExecId,omitempty"`
 }
 
 // ExecutionStepTrace is the trace for a single step (node execution)
@@ -31,10 +37,33 @@ type ExecutionStepTrace struct {
 	Status         string                   `json:"status"`
 	Attempt        int                      `json:"attempt"`
 	Error          *string                  `json:"error,omitempty"`
+	// LLMCostUSD is the priced cost of the step's LLM calls across all its attempts.
+	LLMCostUSD float64 `json:"llmCostUSD,omitempty"`
+	// Children are the sub-workflows the step started, directly or through its tool calls.
+	Children []ChildWorkflowTrace `json:"children,omitempty"`
 }
 
-// TraceRetentionConfig defines retention policy for execution traces
+// ChildWorkflowTrace links a step to a sub-workflow it started.
+type ChildWorkflowTrace struct {
+	WorkflowID string `json:"workflowId"`
+	SchemaID   string `json:"schemaId"`
+	// ToolExecID is the tool call that started the child, when a running node (such as
+	// ai/orchestrate) started it rather than a system/subworkflow step.
+	ToolExecID string `json:"toolExecId,omitempty"`
+	// Status is the child's final state once it completed; until then "running", or "triggered"
+	// for a child the parent does not wait for.
+	Status string `json:"status"`
+}
+
+// TraceRetentionConfig defines how long terminal executions (and their traces) are kept.
+// MaxAge purges executions whose last update is older than the given duration; MaxCount keeps
+// only the most recent N executions per schema. Zero values disable the respective limit.
 type TraceRetentionConfig struct {
-	MaxAge   time.Duration `json:"maxAge,omitempty"`
-	MaxCount int           `json:"maxCount,omitempty"`
+	MaxAge   FlexibleDuration `json:"maxAge,omitempty" swaggertype:"string" example:"720h"`
+	MaxCount int              `json:"maxCount,omitempty" validate:"gte=0"`
+}
+
+// IsZero reports whether neither limit is set.
+func (c TraceRetentionConfig) IsZero() bool {
+	return c.MaxAge <= 0 && c.MaxCount <= 0
 }
@@ -3,6 +3,8 @@ package workflow
 import (
 	"fmt"
 	"time"
+
+	"github.com/open-source-cloud/fuse/pkg/workflow"
 )
 
 // BuildTrace constructs an ExecutionTrace from journal entries.
@@ -16,6 +18,7 @@ func BuildTrace(workflowID, schemaID string, entries []JournalEntry) *ExecutionT
 
 	// Track steps by execID for updating across multiple journal entries
 	stepIdx := make(map[string]int) // execID -> index in trace.Steps
+	children := newChildLinks(trace)
 
 	for _, entry := range entries {
 		switch entry.Type {
@@ -42,6 +45,7 @@ func BuildTrace(workflowID, schemaID string, entries []JournalEntry) *ExecutionT
 				if entry.Result != nil {
 					trace.Steps[idx].Output = &entry.Result.Output
 				}
+				addLLMCost(trace, idx, entry.Result)
 			}
 
 		case JournalStepFailed:
@@ -57,6 +61,7 @@ func BuildTrace(workflowID, schemaID string, entries []JournalEntry) *ExecutionT
 						trace.Steps[idx].Error = &s
 					}
 				}
+				addLLMCost(trace, idx, entry.Result)
 			}
 
 		case JournalStepRetrying:
@@ -65,6 +70,24 @@ func BuildTrace(workflowID, schemaID string, entries []JournalEntry) *ExecutionT
 				trace.Steps[idx].Attempt++
 			}
 
+		case JournalToolCallStarted:
+			children.toolCallStarted(entry)
+
+		case JournalSubWorkflowStarted:
+			children.started(entry, stepIdx)
+
+		case JournalSubWorkflowCompleted:
+			childID, _ := entry.Data["childWorkflowId"].(string)
+			state, _ := entry.Data["childFinalState"].(string)
+			children.completed(childID, state)
+
+		case JournalToolCallCompleted:
+			if entry.Result != nil {
+				childID, _ := entry.Result.Output.Data["workflowId"].(string)
+				state, _ := entry.Result.Output.Data["status"].(string)
+				children.completed(childID, state)
+			}
+
 		case JournalStateChanged:
 			trace.Status = entry.State
 			if entry.State == StateRunning && trace.TriggeredAt.IsZero() {
@@ -93,6 +116,71 @@ func BuildTrace(workflowID, schemaID string, entries []JournalEntry) *ExecutionT
 	return trace
 }
 
+// childLinks attaches the sub-workflows started in an execution to the steps that started them.
+type childLinks struct {
+	trace *ExecutionTrace
+	// toolParents maps a tool call to the step that made it.
+	toolParents map[string]string
+	// byChild locates a child's link as (step index, child index).
+	byChild map[string][2]int
+}
+
+func newChildLinks(trace *ExecutionTrace) *childLinks {
+	return &childLinks{trace: trace, toolParents: make(map[string]string), byChild: make(map[string][2]int)}
+}
+
+func (c *childLinks) toolCallStarted(entry JournalEntry) {
+	if parent, ok := entry.Data["parentExecId"].(string); ok {
+		c.toolParents[entry.ExecID] = parent
+	}
+}
+
+func (c *childLinks) started(entry JournalEntry, stepIdx map[string]int) {
+	link := ChildWorkflowTrace{Status: string(StateRunning)}
+	link.WorkflowID, _ = entry.Data["childWorkflowId"].(string)
+	link.SchemaID, _ = entry.Data["childSchemaId"].(string)
+	if async, _ := entry.Data["async"].(bool); async {
+		link.Status = "triggered"
+	}
+	stepExecID := entry.ExecID
+	if parent, ok := c.toolParents[entry.ExecID]; ok {
+		stepExecID = parent
+		link.ToolExecID = entry.ExecID
+	}
+	idx, ok := stepIdx[stepExecID]
+	if !ok {
+		return
+	}
+	step := &c.trace.Steps[idx]
+	c.byChild[link.WorkflowID] = [2]int{idx, len(step.Children)}
+	step.Children = append(step.Children, link)
+}
+
+func (c *childLinks) completed(childWorkflowID, state string) {
+	at, ok := c.byChild[childWorkflowID]
+	if !ok || state == "" {
+		return
+	}
+	c.trace.Steps[at[0]].Children[at[1]].Status = state
+}
+
+// addLLMCost adds the LLM cost an ai node reported in its "usage" output to the step and the trace.
+func addLLMCost(trace *ExecutionTrace, idx int, result *workflow.FunctionResult) {
+	if result == nil {
+		return
+	}
+	usage, ok := result.Output.Data["usage"].(map[string]any)
+	if !ok {
+		return
+	}
+	cost, ok := usage["costUSD"].(float64)
+	if !ok || cost <= 0 {
+		return
+	}
+	trace.Steps[idx].LLMCostUSD += cost
+	trace.LLMCostUSD += cost
+}
+
 func durationStr(d time.Duration) *string {
 	s := d.String()
 	return &s
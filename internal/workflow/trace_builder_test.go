@@ -133,3 +133,51 @@ func TestBuildTrace_EmptyEntries(t *testing.T) {
 	assert.Empty(t, trace.Steps)
 	assert.True(t, trace.TriggeredAt.IsZero())
 }
+
+func TestBuildTrace_LLMCost(t *testing.T) {
+	now := time.Now()
+	usage := func(cost float64) map[string]any {
+		return map[string]any{"usage": map[string]any{"totalTokens": 10, "costUSD": cost}}
+	}
+	entries := []JournalEntry{
+		{Sequence: 1, Timestamp: now, Type: JournalStateChanged, State: StateRunning},
+		{Sequence: 2, Timestamp: now, Type: JournalStepStarted, ExecID: "exec-1", FunctionNodeID: "agent"},
+		{Sequence: 3, Timestamp: now, Type: JournalStepFailed, ExecID: "exec-1", Result: &workflow.FunctionResult{Output: workflow.FunctionOutput{Status: workflow.FunctionError, Data: usage(0.25)}}},
+		{Sequence: 4, Timestamp: now, Type: JournalStepRetrying, ExecID: "exec-1"},
+		{Sequence: 5, Timestamp: now, Type: JournalStepCompleted, ExecID: "exec-1", Result: &workflow.FunctionResult{Output: workflow.FunctionOutput{Status: workflow.FunctionSuccess, Data: usage(0.5)}}},
+		{Sequence: 6, Timestamp: now, Type: JournalStepStarted, ExecID: "exec-2", FunctionNodeID: "http"},
+		{Sequence: 7, Timestamp: now, Type: JournalStepCompleted, ExecID: "exec-2", Result: &workflow.FunctionResult{Output: workflow.FunctionOutput{Status: workflow.FunctionSuccess, Data: map[string]any{"usage": "n/a"}}}},
+	}
+
+	trace := BuildTrace("wf-cost", "schema-1", entries)
+
+	require.Len(t, trace.Steps, 2)
+	assert.InDelta(t, 0.75, trace.Steps[0].LLMCostUSD, 1e-12, "every attempt's spend counts")
+	assert.Zero(t, trace.Steps[1].LLMCostUSD)
+	assert.InDelta(t, 0.75, trace.LLMCostUSD, 1e-12)
+}
+
+func TestBuildTrace_ChildWorkflowLinks(t *testing.T) {
+	now := time.Now()
+	entries := []JournalEntry{
+		{Sequence: 1, Timestamp: now, Type: JournalStateChanged, State: StateRunning},
+		{Sequence: 2, Timestamp: now, Type: JournalStepStarted, ExecID: "exec-sub", FunctionNodeID: "sub"},
+		{Sequence: 3, Timestamp: now, Type: JournalSubWorkflowStarted, ExecID: "exec-sub", Data: map[string]any{"childWorkflowId": "child-1", "childSchemaId": "billing"}},
+		{Sequence: 4, Timestamp: now, Type: JournalSubWorkflowCompleted, ExecID: "exec-sub", Data: map[string]any{"childWorkflowId": "child-1", "childFinalState": "finished"}},
+		{Sequence: 5, Timestamp: now, Type: JournalStepStarted, ExecID: "exec-orch", FunctionNodeID: "orchestrate"},
+		{Sequence: 6, Timestamp: now, Type: JournalToolCallStarted, ExecID: "tool-1", Data: map[string]any{"parentExecId": "exec-orch", "functionId": "fuse/pkg/system/subworkflow"}},
+		{Sequence: 7, Timestamp: now, Type: JournalSubWorkflowStarted, ExecID: "tool-1", Data: map[string]any{"childWorkflowId": "child-2", "childSchemaId": "refunds"}},
+		{Sequence: 8, Timestamp: now, Type: JournalToolCallStarted, ExecID: "tool-2", Data: map[string]any{"parentExecId": "exec-orch", "functionId": "fuse/pkg/system/subworkflow"}},
+		{Sequence: 9, Timestamp: now, Type: JournalSubWorkflowStarted, ExecID: "tool-2", Data: map[string]any{"childWorkflowId": "child-3", "childSchemaId": "refunds"}},
+		{Sequence: 10, Timestamp: now, Type: JournalToolCallCompleted, ExecID: "tool-1", Result: &workflow.FunctionResult{Output: workflow.FunctionOutput{Status: workflow.FunctionError, Data: map[string]any{"workflowId": "child-2", "status": "error"}}}},
+	}
+
+	trace := BuildTrace("wf-1", "schema-1", entries)
+
+	require.Len(t, trace.Steps, 2)
+	assert.Equal(t, []ChildWorkflowTrace{{WorkflowID: "child-1", SchemaID: "billing", Status: "finished"}}, trace.Steps[0].Children)
+	assert.Equal(t, []ChildWorkflowTrace{
+		{WorkflowID: "child-2", SchemaID: "refunds", ToolExecID: "tool-1", Status: "error"},
+		{WorkflowID: "child-3", SchemaID: "refunds", ToolExecID: "tool-2", Status: "running"},
+	}, trace.Steps[1].Children)
+}
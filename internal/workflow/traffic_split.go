@@ -0,0 +1,145 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/expr-lang/expr"
+)
+
+// trafficBuckets is the resolution of a traffic split: weights are percentages.
+const trafficBuckets = 100
+
+type (
+	// TrafficSplit routes new executions of a schema across several of its versions by weight,
+	// e.g. 90% to the stable version and 10% to a canary. It is schema-level configuration and is
+	// not versioned with the schema definition.
+	TrafficSplit struct {
+		Routes []TrafficRoute `json:"routes"`
+		// StickyKey is an expression over the trigger input (available as `input`), such as
+		// `input.customerId`. Executions whose key evaluates to the same value always run the same
+		// version. When empty, or when the key cannot be evaluated, routing is random.
+		StickyKey string `json:"stickyKey,omitempty"`
+		// AutoRollback rolls back to the baseline version when a canary misbehaves.
+		AutoRollback *CanaryRollbackRule `json:"autoRollback,omitempty"`
+		// CreatedAt marks when the split was configured; canary statistics only count executions
+		// created since then.
+		CreatedAt time.Time `json:"createdAt"`
+	}
+
+	// TrafficRoute sends Weight percent of new executions to a schema version.
+	TrafficRoute struct {
+		Version int `json:"version"`
+		Weight  int `json:"weight"`
+	}
+
+	// CanaryRollbackRule triggers a rollback once a canary version has completed at least
+	// MinExecutions executions and more than MaxErrorRate of them ended in error.
+	CanaryRollbackRule struct {
+		// MaxErrorRate is the tolerated fraction of failed executions, between 0 and 1.
+		MaxErrorRate float64 `json:"maxErrorRate"`
+		// MinExecutions is the number of completed canary executions required before the rule applies.
+		MinExecutions int `json:"minExecutions"`
+	}
+)
+
+// Validate checks that the routes reference distinct versions and that their weights add up to 100.
+func (s *TrafficSplit) Validate() error {
+	if len(s.Routes) == 0 {
+		return errors.New("at least one route is required")
+	}
+	total := 0
+	seen := make(map[int]bool, len(s.Routes))
+	for _, route := range s.Routes {
+		if route.Version < 1 {
+			return fmt.Errorf("route version must be a positive integer, got %d", route.Version)
+		}
+		if seen[route.Version] {
+			return fmt.Errorf("version %d is routed more than once", route.Version)
+		}
+		seen[route.Version] = true
+		if route.Weight < 0 || route.Weight > trafficBuckets {
+			return fmt.Errorf("weight of version %d must be between 0 and %d, got %d", route.Version, trafficBuckets, route.Weight)
+		}
+		total += route.Weight
+	}
+	if total != trafficBuckets {
+		return fmt.Errorf("route weights must add up to %d, got %d", trafficBuckets, total)
+	}
+	if s.StickyKey != "" {
+		if _, err := expr.Compile(s.StickyKey); err != nil {
+			return fmt.Errorf("invalid stickyKey expression %q: %w", s.StickyKey, err)
+		}
+	}
+	if rule := s.AutoRollback; rule != nil {
+		if rule.MaxErrorRate < 0 || rule.MaxErrorRate > 1 {
+			return fmt.Errorf("autoRollback.maxErrorRate must be between 0 and 1, got %g", rule.MaxErrorRate)
+		}
+		if rule.MinExecutions < 1 {
+			return fmt.Errorf("autoRollback.minExecutions must be a positive integer, got %d", rule.MinExecutions)
+		}
+	}
+	return nil
+}
+
+// Baseline returns the version carrying the most traffic (the lowest version on a tie). It is
+// the version an automatic rollback returns to; every other routed version is a canary.
+func (s *TrafficSplit) Baseline() int {
+	baseline := TrafficRoute{}
+	for _, route := range s.Routes {
+		if route.Weight > baseline.Weight || (route.Weight == baseline.Weight && (baseline.Version == 0 || route.Version < baseline.Version)) {
+			baseline = route
+		}
+	}
+	return baseline.Version
+}
+
+// Weight returns the percentage of traffic routed to version, zero when it is not routed.
+func (s *TrafficSplit) Weight(version int) int {
+	for _, route := range s.Routes {
+		if route.Version == version {
+			return route.Weight
+		}
+	}
+	return 0
+}
+
+// VersionForBucket maps a bucket in [0, 100) to the version whose cumulative weight covers it.
+func (s *TrafficSplit) VersionForBucket(bucket int) int {
+	cumulative := 0
+	for _, route := range s.Routes {
+		cumulative += route.Weight
+		if bucket < cumulative {
+			return route.Version
+		}
+	}
+	return s.Baseline()
+}
+
+// StickyBucket evaluates StickyKey against the trigger input and hashes the result to a bucket
+// in [0, 100), so equal keys always land on the same version.
+func (s *TrafficSplit) StickyBucket(input map[string]any) (int, error) {
+	if input == nil {
+		input = map[string]any{}
+	}
+	value, err := expr.Eval(s.StickyKey, map[string]any{"input": input})
+	if err != nil {
+		return 0, fmt.Errorf("failed to evaluate stickyKey %q: %w", s.StickyKey, err)
+	}
+	if value == nil {
+		return 0, fmt.Errorf("stickyKey %q evaluated to nil", s.StickyKey)
+	}
+	hash := fnv.New32a()
+	_, _ = fmt.Fprint(hash, value)
+	return int(hash.Sum32() % trafficBuckets), nil
+}
+
+// Breached reports whether completed canary executions, failed of which ended in error, exceed the rule.
+func (r *CanaryRollbackRule) Breached(completed, failed int) bool {
+	if completed < r.MinExecutions || completed == 0 {
+		return false
+	}
+	return float64(failed)/float64(completed) > r.MaxErrorRate
+}
@@ -0,0 +1,86 @@
+package workflow_test
+
+import (
+	"testing"
+
+	"github.com/open-source-cloud/fuse/internal/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func canarySplit() *workflow.TrafficSplit {
+	return &workflow.TrafficSplit{Routes: []workflow.TrafficRoute{
+		{Version: 7, Weight: 90},
+		{Version: 8, Weight: 10},
+	}}
+}
+
+func TestTrafficSplit_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(s *workflow.TrafficSplit)
+		errMsg string
+	}{
+		{name: "valid", mutate: func(*workflow.TrafficSplit) {}},
+		{name: "weights do not add up", mutate: func(s *workflow.TrafficSplit) { s.Routes[1].Weight = 20 }, errMsg: "add up to 100"},
+		{name: "duplicate version", mutate: func(s *workflow.TrafficSplit) { s.Routes[1].Version = 7 }, errMsg: "more than once"},
+		{name: "invalid version", mutate: func(s *workflow.TrafficSplit) { s.Routes[0].Version = 0 }, errMsg: "positive integer"},
+		{name: "invalid sticky key", mutate: func(s *workflow.TrafficSplit) { s.StickyKey = "input.(" }, errMsg: "stickyKey"},
+		{
+			name: "invalid rollback rate",
+			mutate: func(s *workflow.TrafficSplit) {
+				s.AutoRollback = &workflow.CanaryRollbackRule{MaxErrorRate: 2, MinExecutions: 1}
+			},
+			errMsg: "maxErrorRate",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			split := canarySplit()
+			tt.mutate(split)
+			err := split.Validate()
+			if tt.errMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestTrafficSplit_Routing(t *testing.T) {
+	split := canarySplit()
+
+	assert.Equal(t, 7, split.Baseline())
+	assert.Equal(t, 7, split.VersionForBucket(0))
+	assert.Equal(t, 7, split.VersionForBucket(89))
+	assert.Equal(t, 8, split.VersionForBucket(90))
+	assert.Equal(t, 8, split.VersionForBucket(99))
+	assert.Equal(t, 10, split.Weight(8))
+	assert.Zero(t, split.Weight(9))
+}
+
+func TestTrafficSplit_StickyBucket(t *testing.T) {
+	split := canarySplit()
+	split.StickyKey = "input.customerId"
+
+	a, err := split.StickyBucket(map[string]any{"customerId": "acme"})
+	require.NoError(t, err)
+	b, err := split.StickyBucket(map[string]any{"customerId": "acme", "other": 1})
+	require.NoError(t, err)
+	assert.Equal(t, a, b)
+	assert.GreaterOrEqual(t, a, 0)
+	assert.Less(t, a, 100)
+
+	_, err = split.StickyBucket(map[string]any{})
+	assert.Error(t, err, "a missing key cannot be routed stickily")
+}
+
+func TestCanaryRollbackRule_Breached(t *testing.T) {
+	rule := &workflow.CanaryRollbackRule{MaxErrorRate: 0.1, MinExecutions: 10}
+
+	assert.False(t, rule.Breached(5, 5), "below the minimum number of executions")
+	assert.False(t, rule.Breached(10, 1))
+	assert.True(t, rule.Breached(10, 2))
+}
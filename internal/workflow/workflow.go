@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"regexp"
 	"strings"
 	"sync"
 
@@ -44,6 +45,12 @@ const (
 	StateCancelled State = "cancelled"
 )
 
+// IsActive reports whether an execution in state s has not finished yet and counts against
+// namespace concurrency quotas.
+func (s State) IsActive() bool {
+	return s == StateUntriggered || s == StateRunning || s == StateSleeping
+}
+
 const (
 	logMsgFailedParamValidation = "Failed param validation"
 	logMsgErrorParsingValue     = "Error parsing value"
@@ -77,12 +84,21 @@ func (w *Workflow) SetSecretResolver(r secrets.Resolver) {
 	w.secretResolver = r
 }
 
-// resolveSchemaValue resolves any {{secret:NAME}} and {{credential:ID.FIELD}} references embedded
-// in a schema string value, wrapping the whole result as a SecretValue so it is redacted in every
-// sink. Non-string or reference-free values pass through unchanged.
+// schemaRefPattern matches every reference token a schema string value may embed. Each match is
+// resolved on its own, so a substituted value is never scanned for further references.
+var schemaRefPattern = regexp.MustCompile(`\{\{(?:secret|credential|var):[A-Za-z0-9_.\-]+\}\}`)
+
+// resolveSchemaValue resolves any {{var:NAME}}, {{secret:NAME}} and {{credential:ID.FIELD}}
+// references embedded in a schema string value. A value with secret or credential references is
+// wrapped as a SecretValue so it is redacted in every sink; one with only variable references stays
+// plain text. Non-string or reference-free values pass through unchanged.
 func (w *Workflow) resolveSchemaValue(value any) (any, error) {
 	s, ok := value.(string)
-	if !ok || (!secrets.HasSecretRef(s) && !secrets.HasCredentialRef(s)) {
+	if !ok {
+		return value, nil
+	}
+	hasSecretRefs := secrets.HasSecretRef(s) || secrets.HasCredentialRef(s)
+	if !hasSecretRefs && !workflow.HasVarRef(s) {
 		return value, nil
 	}
 	reveal := func(name string) (string, error) {
@@ -92,17 +108,62 @@ func (w *Workflow) resolveSchemaValue(value any) (any, error) {
 		}
 		return sv.Reveal(), nil
 	}
-	resolved, err := secrets.ReplaceSecretRefs(s, reveal)
-	if err != nil {
-		return nil, err
+	var firstErr error
+	resolved := schemaRefPattern.ReplaceAllStringFunc(s, func(token string) string {
+		if firstErr != nil {
+			return token
+		}
+		var out string
+		var err error
+		switch {
+		case workflow.HasVarRef(token):
+			out, err = workflow.ReplaceVarRefs(token, w.vars)
+		case secrets.HasSecretRef(token):
+			out, err = secrets.ReplaceSecretRefs(token, reveal)
+		default:
+			// Credential refs map to the reserved cred/<id>/<field> secret name and resolve via
+			// the same environment-scoped resolver.
+			out, err = secrets.ReplaceCredentialRefs(token, reveal)
+		}
+		if err != nil {
+			firstErr = err
+			return token
+		}
+		return out
+	})
+	if firstErr != nil {
+		return nil, firstErr
 	}
-	// Credential refs map to the reserved cred/<id>/<field> secret name and resolve via the same
-	// environment-scoped resolver.
-	resolved, err = secrets.ReplaceCredentialRefs(resolved, reveal)
-	if err != nil {
-		return nil, err
+	if hasSecretRefs {
+		return secrets.NewSecretValue(resolved), nil
+	}
+	return resolved, nil
+}
+
+// resolveVar resolves a SourceVar mapping from the variables pinned at trigger time, converting
+// the value to the destination parameter's type when it declares one.
+func (w *Workflow) resolveVar(name, paramType string) (any, error) {
+	value, ok := w.vars[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", workflow.ErrEnvVarNotFound, name)
+	}
+	if paramType == "" || paramType == "any" {
+		return value, nil
+	}
+	return typeschema.ParseValue(paramType, value)
+}
+
+// resolveInput resolves a SourceInput mapping to a field of the trigger input, converted to the
+// destination parameter's type.
+func (w *Workflow) resolveInput(name, paramType string) (any, error) {
+	value, ok := w.input[name]
+	if !ok {
+		return nil, fmt.Errorf("trigger input has no field %q", name)
+	}
+	if paramType == "" || paramType == "any" {
+		return value, nil
 	}
-	return secrets.NewSecretValue(resolved), nil
+	return typeschema.ParseValue(paramType, value)
 }
 
 // resolveSecret resolves a SourceSecret mapping (the secret name) to a SecretValue.
@@ -114,11 +175,11 @@ func (w *Workflow) resolveSecret(name string) (secrets.SecretValue, error) {
 // split on the LAST dot (so ids may contain dots) and read from the reserved cred/<id>/<field>
 // secret name, scoped to this workflow's environment (ADR-0031).
 func (w *Workflow) resolveCredential(variable string) (secrets.SecretValue, error) {
-	dot := strings.LastIndex(variable, ".")
-	if dot <= 0 || dot == len(variable)-1 {
+	id, field, ok := SplitCredentialRef(variable)
+	if !ok {
 		return secrets.SecretValue{}, fmt.Errorf("invalid credential reference %q: expected \"<id>.<field>\"", variable)
 	}
-	return w.secretValueByName(secrets.CredentialSecretName(variable[:dot], variable[dot+1:]))
+	return w.secretValueByName(secrets.CredentialSecretName(id, field))
 }
 
 // secretValueByName resolves a secret by name, scoped to this workflow's environment.
@@ -135,6 +196,9 @@ type (
 		id               workflow.ID
 		graph            *Graph
 		environment      string
+		schemaVersion    int
+		vars             map[string]string
+		input            map[string]any
 		journal          *Journal
 		auditLog         *AuditLog
 		retryTracker     *RetryTracker
@@ -523,6 +587,59 @@ func (w *Workflow) hasJournalEntryType(execID string, entryType JournalEntryType
 	return false
 }
 
+// Checkpoint journals a sub-step of the in-flight execution execID. index is the number of
+// checkpoints the sender already knows for its attempt; a checkpoint is only accepted when it
+// extends the current attempt's list exactly, which drops duplicates and stragglers from an
+// abandoned attempt. Returns false when the checkpoint was not recorded.
+func (w *Workflow) Checkpoint(execID workflow.ExecID, index int, data map[string]any) bool {
+	entry, exists := w.auditLog.Get(execID.String())
+	if !exists {
+		return false
+	}
+	checkpoints, running := w.attemptCheckpoints(execID.String())
+	if !running || index != len(checkpoints) {
+		return false
+	}
+	w.journal.Append(JournalEntry{
+		Type:           JournalStepCheckpoint,
+		ThreadID:       entry.ThreadID,
+		FunctionNodeID: entry.FunctionNodeID,
+		ExecID:         execID.String(),
+		Data:           data,
+	})
+	return true
+}
+
+// Checkpoints returns the sub-steps journaled by the current attempt of execID, oldest first.
+// A failed or retried attempt starts over with no checkpoints.
+func (w *Workflow) Checkpoints(execID workflow.ExecID) []map[string]any {
+	checkpoints, _ := w.attemptCheckpoints(execID.String())
+	return checkpoints
+}
+
+// attemptCheckpoints scans the journal for execID, returning the current attempt's checkpoints and
+// whether the step is still running (started or retrying, and not yet completed or failed).
+func (w *Workflow) attemptCheckpoints(execID string) ([]map[string]any, bool) {
+	var checkpoints []map[string]any
+	running := false
+	for _, e := range w.journal.Entries() {
+		if e.ExecID != execID {
+			continue
+		}
+		switch e.Type {
+		case JournalStepStarted, JournalStepRetrying:
+			checkpoints = nil
+			running = true
+		case JournalStepCompleted, JournalStepFailed:
+			checkpoints = nil
+			running = false
+		case JournalStepCheckpoint:
+			checkpoints = append(checkpoints, e.Data)
+		}
+	}
+	return checkpoints, running
+}
+
 // ID Workflow ID
 func (w *Workflow) ID() workflow.ID {
 	return w.id
@@ -534,6 +651,53 @@ func (w *Workflow) Environment() string {
 	return w.environment
 }
 
+// Vars returns the environment variables pinned when the execution was triggered.
+func (w *Workflow) Vars() map[string]string {
+	return w.vars
+}
+
+// SetVars pins the environment variables the execution resolves {{var:NAME}} references and
+// source:"var" mappings against. It is set once when the execution is created and restored by the
+// repository on reconstruction, so replay sees the values the execution started with even if the
+// environment's variables changed since.
+func (w *Workflow) SetVars(vars map[string]string) {
+	w.vars = vars
+}
+
+// Input returns the input the execution was triggered with.
+func (w *Workflow) Input() map[string]any {
+	return w.input
+}
+
+// SetInput records the trigger input source:"input" mappings read from. Like the variables it is
+// set once when the execution is created and restored by the repository on reconstruction.
+func (w *Workflow) SetInput(input map[string]any) {
+	w.input = input
+}
+
+// Namespace returns the namespace of the execution, which is the namespace of its schema.
+func (w *Workflow) Namespace() string {
+	return workflow.NamespaceOf(w.graph.ID())
+}
+
+// SchemaVersion returns the schema version this execution runs on. Zero means the version
+// was not recorded (executions created before schema versions were tracked).
+func (w *Workflow) SchemaVersion() int {
+	return w.schemaVersion
+}
+
+// SetSchemaVersion records the schema version this execution runs on. It is set once when
+// the execution is created and restored by the repository on reconstruction.
+func (w *Workflow) SetSchemaVersion(version int) {
+	w.schemaVersion = version
+}
+
+// SearchAttributes evaluates the schema's search attributes against the trigger input and the
+// node outputs produced so far.
+func (w *Workflow) SearchAttributes(input map[string]any) map[string]string {
+	return EvaluateSearchAttributes(w.graph.schema.SearchAttributes, input, w.aggregatedOutput.Snapshot())
+}
+
 // State Workflow state
 func (w *Workflow) State() State {
 	w.state.mu.RLock()
@@ -711,6 +875,22 @@ func (w *Workflow) inputMapping(edge *Edge, mappings []InputMapping) map[string]
 				continue
 			}
 			args.Set(mapping.MapTo, sv)
+		case SourceVar:
+			value, err := w.resolveVar(mapping.Variable, inputParamSchema.Type)
+			if err != nil {
+				log.Error().Err(err).Str("edge", edge.ID()).Str("param", mapping.MapTo).
+					Str("var", mapping.Variable).Msg("failed to resolve environment variable")
+				continue
+			}
+			args.Set(mapping.MapTo, value)
+		case SourceInput:
+			value, err := w.resolveInput(mapping.Variable, inputParamSchema.Type)
+			if err != nil {
+				log.Error().Err(err).Str("edge", edge.ID()).Str("param", mapping.MapTo).
+					Str("input", mapping.Variable).Msg("failed to resolve trigger input")
+				continue
+			}
+			args.Set(mapping.MapTo, value)
 		case SourceFlow:
 			w.applyFlowMapping(args, edge, mapping, inputParamSchema, allowCustomInputParameters)
 		}
@@ -896,12 +1076,43 @@ func (w *Workflow) getRetryPolicy(node *Node) *RetryPolicy {
 	return node.Schema().Retry
 }
 
+// findErrorEdges returns the onError edges to follow once the node has failed for good. An onError
+// edge with a conditional is taken only when it matches the failed node's output (an exact condition
+// compares its value with the output's errorType); a default edge is taken when nothing else matched.
 func (w *Workflow) findErrorEdges(node *Node) []*Edge {
 	var errorEdges []*Edge
+	var defaultEdge *Edge
 	for _, edge := range node.OutputEdges() {
-		if edge.schema.OnError {
+		if !edge.schema.OnError {
+			continue
+		}
+		condition := edge.Condition()
+		if condition == nil {
 			errorEdges = append(errorEdges, edge)
+			continue
 		}
+		if condition.Type == ConditionDefault {
+			defaultEdge = edge
+			continue
+		}
+		if w.errorConditionMatches(condition, node) {
+			errorEdges = append(errorEdges, edge)
+		}
+	}
+	if len(errorEdges) == 0 && defaultEdge != nil {
+		errorEdges = append(errorEdges, defaultEdge)
 	}
 	return errorEdges
 }
+
+func (w *Workflow) errorConditionMatches(condition *EdgeCondition, node *Node) bool {
+	if condition.Type == ConditionExpression {
+		matches, err := EvaluateCondition(condition, w.aggregatedOutput, node)
+		if err != nil {
+			log.Error().Err(err).Str("node", node.ID()).Msg("onError condition evaluation failed")
+			return false
+		}
+		return matches
+	}
+	return condition.Value == w.aggregatedOutput.Get(fmt.Sprintf("%s.errorType", node.ID()))
+}
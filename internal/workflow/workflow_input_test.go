@@ -0,0 +1,30 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkflow_ResolveTriggerInput(t *testing.T) {
+	t.Parallel()
+	w := &Workflow{id: workflow.ID("wf-1")}
+	w.SetInput(map[string]any{"orderId": "o-42", "amount": "12", "items": []any{"a", "b"}})
+
+	v, err := w.resolveInput("orderId", "string")
+	require.NoError(t, err)
+	assert.Equal(t, "o-42", v)
+
+	// source:"input" converts to the destination parameter's type.
+	n, err := w.resolveInput("amount", "int")
+	require.NoError(t, err)
+	assert.Equal(t, 12, n)
+	v, err = w.resolveInput("items", "")
+	require.NoError(t, err)
+	assert.Equal(t, []any{"a", "b"}, v)
+
+	_, err = w.resolveInput("missing", "string")
+	assert.ErrorContains(t, err, `"missing"`)
+}
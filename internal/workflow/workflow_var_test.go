@@ -0,0 +1,48 @@
+package workflow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/open-source-cloud/fuse/pkg/secrets"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkflow_ResolveVarReferences(t *testing.T) {
+	t.Parallel()
+	store := secrets.NewMemorySecretStore()
+	require.NoError(t, store.Set(context.Background(), secrets.Scope{Environment: "test"}, "tok", "{{var:BASE_URL}}"))
+
+	w := &Workflow{id: workflow.ID("wf-1")}
+	w.SetSecretResolver(secrets.NewResolver(store, "test"))
+	w.SetVars(map[string]string{"BASE_URL": "https://api.example.com", "RETRIES": "3", "TOKEN_HINT": "{{secret:tok}}"})
+
+	// Variables are plain text, not SecretValues.
+	v, err := w.resolveSchemaValue("{{var:BASE_URL}}/v2/orders")
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.example.com/v2/orders", v)
+
+	// Mixed with a secret, the whole value is redacted; substituted values are not re-scanned.
+	v, err = w.resolveSchemaValue("{{var:TOKEN_HINT}} {{secret:tok}}")
+	require.NoError(t, err)
+	sv, ok := v.(secrets.SecretValue)
+	require.True(t, ok)
+	assert.Equal(t, "{{secret:tok}} {{var:BASE_URL}}", sv.Reveal())
+
+	_, err = w.resolveSchemaValue("{{var:MISSING}}")
+	require.ErrorIs(t, err, workflow.ErrEnvVarNotFound)
+
+	// source:"var" converts to the destination parameter's type.
+	n, err := w.resolveVar("RETRIES", "int")
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+	s, err := w.resolveVar("RETRIES", "")
+	require.NoError(t, err)
+	assert.Equal(t, "3", s)
+	_, err = w.resolveVar("BASE_URL", "int")
+	require.Error(t, err)
+	_, err = w.resolveVar("MISSING", "string")
+	assert.ErrorIs(t, err, workflow.ErrEnvVarNotFound)
+}
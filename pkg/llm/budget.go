@@ -0,0 +1,45 @@
+package llm
+
+import "fmt"
+
+// ErrorTypeBudgetExceeded is the errorType an ai node reports in its error output when an LLM
+// budget is exceeded, so onError edges can route on it.
+const ErrorTypeBudgetExceeded = "budget_exceeded"
+
+// Budget scopes reported by BudgetExceededError.
+const (
+	BudgetScopeNode      = "node"
+	BudgetScopeExecution = "execution"
+	BudgetScopeDaily     = "daily"
+)
+
+// Budget resources reported by BudgetExceededError.
+const (
+	BudgetResourceCost   = "costUSD"
+	BudgetResourceTokens = "tokens"
+)
+
+// BudgetExceededError reports that LLM spend went over a budget.
+type BudgetExceededError struct {
+	// Scope is the budget that was exceeded: node, execution, or daily.
+	Scope string
+	// Resource is the limited quantity: costUSD or tokens.
+	Resource string
+	Limit    float64
+	Spent    float64
+}
+
+// Error implements error.
+func (e *BudgetExceededError) Error() string {
+	return fmt.Sprintf("llm %s budget exceeded: %s %v of %v", e.Scope, e.Resource, e.Spent, e.Limit)
+}
+
+// Data renders the error as function output data.
+func (e *BudgetExceededError) Data() map[string]any {
+	return map[string]any{
+		"scope":    e.Scope,
+		"resource": e.Resource,
+		"limit":    e.Limit,
+		"spent":    e.Spent,
+	}
+}
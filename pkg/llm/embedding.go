@@ -0,0 +1,31 @@
+package llm
+
+import "context"
+
+// EmbeddingRequest asks a provider to embed one or more texts.
+type EmbeddingRequest struct {
+	// Model is the embedding model; empty uses the provider's configured default.
+	Model string `json:"model"`
+	// Input holds the texts to embed; the response carries one vector per text, in order.
+	Input []string `json:"input"`
+	// Dimensions requests shortened vectors from models that support it (0 = model default).
+	Dimensions int `json:"dimensions,omitempty"`
+}
+
+// EmbeddingResponse is the result of an embedding request.
+type EmbeddingResponse struct {
+	// Vectors[i] is the embedding of EmbeddingRequest.Input[i].
+	Vectors [][]float32 `json:"vectors"`
+	// Model is the model that produced the vectors.
+	Model string `json:"model"`
+	// Usage reports prompt tokens; embeddings have no completion tokens.
+	Usage Usage `json:"usage"`
+}
+
+// EmbeddingProvider is an optional capability, detected like StreamingProvider with a type
+// assertion on a Provider value.
+type EmbeddingProvider interface {
+	Provider
+	// Embed returns one vector per input text.
+	Embed(ctx context.Context, req EmbeddingRequest) (EmbeddingResponse, error)
+}
@@ -0,0 +1,85 @@
+package llm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// tokensPerPriceUnit is the token count a Price is quoted for (USD per million tokens).
+const tokensPerPriceUnit = 1_000_000
+
+// Price is the USD cost of a model per million prompt (input) and completion (output) tokens.
+type Price struct {
+	InputPerMillion  float64 `json:"inputPerMillion"`
+	OutputPerMillion float64 `json:"outputPerMillion"`
+}
+
+// Pricing maps "provider/model" keys to prices. A "provider/*" key prices every model of a
+// provider that has no exact entry, including calls that use the provider's default model.
+type Pricing map[string]Price
+
+// ParsePricing parses a pricing table of comma-separated "provider/model=input:output" entries,
+// with prices in USD per million tokens, e.g.
+// "openai/gpt-4o=2.5:10,anthropic/*=3:15,ollama/*=0:0". The model part may itself contain
+// slashes or colons (openrouter/openai/gpt-4o, ollama/llama3.1:8b). An empty spec is an empty table.
+func ParsePricing(spec string) (Pricing, error) {
+	pricing := Pricing{}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		eq := strings.LastIndex(entry, "=")
+		if eq < 0 {
+			return nil, fmt.Errorf("llm pricing entry %q: expected provider/model=input:output", entry)
+		}
+		key, value := strings.TrimSpace(entry[:eq]), strings.TrimSpace(entry[eq+1:])
+		if slash := strings.Index(key, "/"); slash <= 0 || slash == len(key)-1 {
+			return nil, fmt.Errorf("llm pricing entry %q: key must be provider/model or provider/*", entry)
+		}
+		input, output, ok := strings.Cut(value, ":")
+		if !ok {
+			return nil, fmt.Errorf("llm pricing entry %q: expected input:output prices", entry)
+		}
+		var price Price
+		var err error
+		if price.InputPerMillion, err = parsePrice(input); err != nil {
+			return nil, fmt.Errorf("llm pricing entry %q: input price: %w", entry, err)
+		}
+		if price.OutputPerMillion, err = parsePrice(output); err != nil {
+			return nil, fmt.Errorf("llm pricing entry %q: output price: %w", entry, err)
+		}
+		pricing[key] = price
+	}
+	return pricing, nil
+}
+
+func parsePrice(s string) (float64, error) {
+	v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0, err
+	}
+	if v < 0 {
+		return 0, fmt.Errorf("price %v is negative", v)
+	}
+	return v, nil
+}
+
+// Lookup returns the price of a provider's model, falling back to the provider's wildcard entry.
+func (p Pricing) Lookup(provider, model string) (Price, bool) {
+	if price, ok := p[provider+"/"+model]; ok && model != "" {
+		return price, true
+	}
+	price, ok := p[provider+"/*"]
+	return price, ok
+}
+
+// Cost returns the USD cost of a completion's usage, or 0 when the model has no price.
+func (p Pricing) Cost(provider, model string, u Usage) float64 {
+	price, ok := p.Lookup(provider, model)
+	if !ok {
+		return 0
+	}
+	return (float64(u.PromptTokens)*price.InputPerMillion + float64(u.CompletionTokens)*price.OutputPerMillion) / tokensPerPriceUnit
+}
@@ -0,0 +1,60 @@
+package llm_test
+
+import (
+	"testing"
+
+	"github.com/open-source-cloud/fuse/pkg/llm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePricing(t *testing.T) {
+	t.Parallel()
+
+	pricing, err := llm.ParsePricing(" openai/gpt-4o=2.5:10, openrouter/openai/gpt-4o=3:12 ,ollama/llama3.1:8b=0:0,anthropic/*=3:15,")
+	require.NoError(t, err)
+	assert.Equal(t, llm.Price{InputPerMillion: 2.5, OutputPerMillion: 10}, pricing["openai/gpt-4o"])
+	assert.Equal(t, llm.Price{InputPerMillion: 3, OutputPerMillion: 12}, pricing["openrouter/openai/gpt-4o"])
+	assert.Contains(t, pricing, "ollama/llama3.1:8b")
+	assert.Contains(t, pricing, "anthropic/*")
+
+	empty, err := llm.ParsePricing("")
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+}
+
+func TestParsePricing_Invalid(t *testing.T) {
+	t.Parallel()
+	for _, spec := range []string{
+		"openai/gpt-4o",
+		"gpt-4o=1:2",
+		"openai/=1:2",
+		"openai/gpt-4o=1",
+		"openai/gpt-4o=x:2",
+		"openai/gpt-4o=1:-2",
+	} {
+		_, err := llm.ParsePricing(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestPricing_Cost(t *testing.T) {
+	t.Parallel()
+	pricing := llm.Pricing{
+		"openai/gpt-4o": {InputPerMillion: 2.5, OutputPerMillion: 10},
+		"openai/*":      {InputPerMillion: 1, OutputPerMillion: 1},
+	}
+	u := llm.Usage{PromptTokens: 1000, CompletionTokens: 500, TotalTokens: 1500}
+
+	assert.InDelta(t, 0.0075, pricing.Cost("openai", "gpt-4o", u), 1e-12)
+	assert.InDelta(t, 0.0015, pricing.Cost("openai", "gpt-4o-mini", u), 1e-12, "wildcard")
+	assert.InDelta(t, 0.0015, pricing.Cost("openai", "", u), 1e-12, "default model uses the wildcard")
+	assert.Zero(t, pricing.Cost("anthropic", "claude", u), "unpriced")
+}
+
+func TestBudgetExceededError(t *testing.T) {
+	t.Parallel()
+	err := &llm.BudgetExceededError{Scope: llm.BudgetScopeExecution, Resource: llm.BudgetResourceTokens, Limit: 100, Spent: 120}
+	assert.Equal(t, "llm execution budget exceeded: tokens 120 of 100", err.Error())
+	assert.Equal(t, "execution", err.Data()["scope"])
+}
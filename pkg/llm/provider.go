@@ -68,6 +68,9 @@ type ChatResponse struct {
 	Message      Message `json:"message"`
 	FinishReason string  `json:"finishReason"`
 	Usage        Usage   `json:"usage"`
+	// Routing is set when a routing policy served the completion: the route it took and the
+	// routes it fell back from.
+	Routing *Routing `json:"routing,omitempty"`
 }
 
 // Usage reports token accounting for a completion.
@@ -86,20 +89,25 @@ type Provider interface {
 	Chat(ctx context.Context, req ChatRequest) (ChatResponse, error)
 }
 
-// StreamChunk is one incremental piece of a streamed completion (Phase C).
+// StreamChunk is one incremental piece of a streamed completion.
 type StreamChunk struct {
 	// ContentDelta is the incremental assistant text for this chunk.
 	ContentDelta string `json:"contentDelta"`
 	// Done is true on the final chunk; Response carries the aggregated result.
 	Done     bool          `json:"done"`
 	Response *ChatResponse `json:"response,omitempty"`
-	Err      error         `json:"-"`
+	// Err is set on a terminal chunk when the completion failed mid-stream.
+	Err error `json:"-"`
 }
 
 // StreamingProvider is an optional capability. Detect support with a type
 // assertion on a Provider value.
 type StreamingProvider interface {
 	Provider
-	// ChatStream performs a streaming chat completion.
+	// ChatStream performs a streaming chat completion. The returned channel
+	// yields content deltas and then exactly one terminal chunk (Done with the
+	// aggregated Response, or Err) before it is closed; it is also closed,
+	// without a terminal chunk, when ctx is done. An error is returned only when
+	// the request cannot be started.
 	ChatStream(ctx context.Context, req ChatRequest) (<-chan StreamChunk, error)
 }
@@ -0,0 +1,357 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrRoutesExhausted is returned when every route of a routing policy failed or was skipped.
+var ErrRoutesExhausted = fmt.Errorf("every route of the llm routing policy failed")
+
+// errStreamIncomplete is returned when a route's stream ended without a terminal chunk.
+var errStreamIncomplete = fmt.Errorf("chat stream ended without a final response")
+
+// RoutingOptions tune the providers built by RoutingFactories.
+type RoutingOptions struct {
+	// BreakerFailures is how many consecutive retryable failures open a route's circuit; 0
+	// disables circuit breaking.
+	BreakerFailures int
+	// BreakerCooldown is how long an open circuit skips its route before letting a single trial
+	// request through.
+	BreakerCooldown time.Duration
+	// AttemptTimeout bounds each attempt so a hung route falls back instead of using up the
+	// caller's deadline; 0 bounds attempts by the caller's context only.
+	AttemptTimeout time.Duration
+}
+
+// RoutingFactories builds a ProviderFactory per policy, to be registered alongside the provider
+// factories it routes to. A policy resolves its routes' providers per attempt, for the
+// environment it was built for. Circuit breakers are shared by everything the factories build,
+// so a route's health carries over between executions. A policy named like a provider, or
+// routing to a provider that is not registered, is an error.
+func RoutingFactories(policies []RoutingPolicy, providers map[string]ProviderFactory, opts RoutingOptions) (map[string]ProviderFactory, error) {
+	routes := make(map[string]ProviderFactory, len(providers))
+	for name, factory := range providers {
+		routes[name] = factory
+	}
+	breakers := &circuitBreakers{threshold: opts.BreakerFailures, cooldown: opts.BreakerCooldown, byRoute: make(map[string]*breaker)}
+
+	factories := make(map[string]ProviderFactory, len(policies))
+	for _, policy := range policies {
+		if _, ok := routes[policy.Name]; ok {
+			return nil, fmt.Errorf("llm routing policy %q: name is taken by a provider", policy.Name)
+		}
+		for _, tier := range policy.Tiers {
+			for _, route := range tier {
+				if _, ok := routes[route.Provider]; !ok {
+					return nil, fmt.Errorf("llm routing policy %q: %w: %q", policy.Name, ErrProviderNotFound, route.Provider)
+				}
+			}
+		}
+		p := policy
+		factories[p.Name] = func(_ context.Context, environment string) (Provider, error) {
+			return &routedProvider{policy: p, routes: routes, environment: environment, breakers: breakers, attemptTimeout: opts.AttemptTimeout}, nil
+		}
+	}
+	return factories, nil
+}
+
+// routedProvider serves a routing policy as a single Provider.
+type routedProvider struct {
+	policy         RoutingPolicy
+	routes         map[string]ProviderFactory
+	environment    string
+	breakers       *circuitBreakers
+	attemptTimeout time.Duration
+}
+
+var _ StreamingProvider = (*routedProvider)(nil)
+
+// attemptFunc sends req to one route's provider.
+type attemptFunc func(ctx context.Context, provider Provider, req ChatRequest) (ChatResponse, error)
+
+// Name returns the policy name, the key the policy is registered under.
+func (p *routedProvider) Name() string { return p.policy.Name }
+
+// Chat sends the completion to the policy's routes in order until one succeeds.
+func (p *routedProvider) Chat(ctx context.Context, req ChatRequest) (ChatResponse, error) {
+	return p.route(ctx, req, func(ctx context.Context, provider Provider, req ChatRequest) (ChatResponse, error) {
+		return provider.Chat(ctx, req)
+	})
+}
+
+// ChatStream streams the completion from the first route that starts answering. A route that
+// fails before its first content delta falls back like Chat; once text has reached the caller,
+// a failure ends the stream. Routes whose provider cannot stream answer in a single delta.
+func (p *routedProvider) ChatStream(ctx context.Context, req ChatRequest) (<-chan StreamChunk, error) {
+	chunks := make(chan StreamChunk)
+	go func() {
+		defer close(chunks)
+		resp, err := p.route(ctx, req, streamAttempt(chunks))
+		if err != nil {
+			sendChunk(ctx, chunks, StreamChunk{Err: err})
+			return
+		}
+		sendChunk(ctx, chunks, StreamChunk{Done: true, Response: &resp})
+	}()
+	return chunks, nil
+}
+
+// route runs call against the policy's routes: tiers in order, the routes of a tier in weighted
+// random order. It falls back on rate limits, server errors, timeouts and unreachable providers,
+// skips routes whose circuit is open, and stops at the first other error.
+func (p *routedProvider) route(ctx context.Context, req ChatRequest, call attemptFunc) (ChatResponse, error) {
+	var fallbacks []FallbackAttempt
+	var lastErr error
+	for _, tier := range p.policy.Tiers {
+		for _, route := range weightedOrder(tier) {
+			if err := ctx.Err(); err != nil {
+				return ChatResponse{}, err
+			}
+			routeReq := req
+			if route.Model != "" {
+				routeReq.Model = route.Model
+			}
+			served := Route{Provider: route.Provider, Model: routeReq.Model}
+			fallback := FallbackAttempt{Provider: served.Provider, Model: served.Model}
+
+			b := p.breakers.get(served.String())
+			if !b.allow() {
+				fallback.Reason = FallbackCircuitOpen
+				fallbacks = append(fallbacks, fallback)
+				continue
+			}
+			provider, err := p.routes[route.Provider](ctx, p.environment)
+			if err != nil {
+				b.release()
+				return ChatResponse{}, fmt.Errorf("routing[%s]: %s: %w", p.policy.Name, served, err)
+			}
+
+			resp, err := p.attempt(ctx, provider, routeReq, call)
+			if err == nil {
+				b.success()
+				resp.Routing = &Routing{Policy: p.policy.Name, Provider: served.Provider, Model: served.Model, Fallbacks: fallbacks}
+				return resp, nil
+			}
+			if ctx.Err() != nil {
+				b.release()
+				return ChatResponse{}, fmt.Errorf("routing[%s]: %s: %w", p.policy.Name, served, err)
+			}
+			reason, retryable := fallbackReason(err)
+			if !retryable {
+				b.release()
+				return ChatResponse{}, fmt.Errorf("routing[%s]: %s: %w", p.policy.Name, served, err)
+			}
+			b.failure()
+			var committed *streamCommittedError
+			if errors.As(err, &committed) {
+				return ChatResponse{}, fmt.Errorf("routing[%s]: %s: %w", p.policy.Name, served, err)
+			}
+			fallback.Reason = reason
+			fallbacks = append(fallbacks, fallback)
+			lastErr = err
+		}
+	}
+
+	tried := make([]string, len(fallbacks))
+	for i, f := range fallbacks {
+		tried[i] = Route{Provider: f.Provider, Model: f.Model}.String() + " (" + f.Reason + ")"
+	}
+	err := fmt.Errorf("routing[%s]: %w: %s", p.policy.Name, ErrRoutesExhausted, strings.Join(tried, ", "))
+	if lastErr != nil {
+		err = fmt.Errorf("%w; last error: %w", err, lastErr)
+	}
+	return ChatResponse{}, err
+}
+
+// attempt runs call against one route, bounded by the attempt timeout.
+func (p *routedProvider) attempt(ctx context.Context, provider Provider, req ChatRequest, call attemptFunc) (ChatResponse, error) {
+	if p.attemptTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.attemptTimeout)
+		defer cancel()
+	}
+	return call(ctx, provider, req)
+}
+
+// streamCommittedError is a route failure after part of the answer was already streamed to the
+// caller; no other route can take it over.
+type streamCommittedError struct{ err error }
+
+func (e *streamCommittedError) Error() string { return e.err.Error() }
+
+func (e *streamCommittedError) Unwrap() error { return e.err }
+
+// streamAttempt returns an attemptFunc that forwards a route's content deltas to out and returns
+// its final response, leaving the terminal chunk to the caller.
+func streamAttempt(out chan<- StreamChunk) attemptFunc {
+	return func(ctx context.Context, provider Provider, req ChatRequest) (ChatResponse, error) {
+		sp, ok := provider.(StreamingProvider)
+		if !ok {
+			resp, err := provider.Chat(ctx, req)
+			if err == nil && resp.Message.Content != "" && !sendChunk(ctx, out, StreamChunk{ContentDelta: resp.Message.Content}) {
+				return ChatResponse{}, ctx.Err()
+			}
+			return resp, err
+		}
+		in, err := sp.ChatStream(ctx, req)
+		if err != nil {
+			return ChatResponse{}, err
+		}
+		forwarded := false
+		fail := func(err error) (ChatResponse, error) {
+			if forwarded {
+				return ChatResponse{}, &streamCommittedError{err: err}
+			}
+			return ChatResponse{}, err
+		}
+		for chunk := range in {
+			switch {
+			case chunk.Err != nil:
+				return fail(chunk.Err)
+			case chunk.Done:
+				if chunk.Response == nil {
+					return fail(errStreamIncomplete)
+				}
+				return *chunk.Response, nil
+			case chunk.ContentDelta != "":
+				if !sendChunk(ctx, out, StreamChunk{ContentDelta: chunk.ContentDelta}) {
+					return fail(ctx.Err())
+				}
+				forwarded = true
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return fail(err)
+		}
+		return fail(errStreamIncomplete)
+	}
+}
+
+// sendChunk delivers chunk unless ctx is done first; it reports whether the consumer got it.
+func sendChunk(ctx context.Context, chunks chan<- StreamChunk, chunk StreamChunk) bool {
+	select {
+	case chunks <- chunk:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// weightedOrder returns the routes of a tier in random order, each position drawn with
+// probability proportional to the weights of the routes not yet placed.
+func weightedOrder(tier []Route) []Route {
+	if len(tier) == 1 {
+		return tier
+	}
+	rest := append([]Route(nil), tier...)
+	ordered := make([]Route, 0, len(tier))
+	for len(rest) > 0 {
+		total := 0
+		for _, r := range rest {
+			total += r.Weight
+		}
+		n, i := rand.IntN(total), 0
+		for n >= rest[i].Weight {
+			n -= rest[i].Weight
+			i++
+		}
+		ordered = append(ordered, rest[i])
+		rest = append(rest[:i], rest[i+1:]...)
+	}
+	return ordered
+}
+
+// circuitBreakers holds one breaker per provider/model, created on first use.
+type circuitBreakers struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu      sync.Mutex
+	byRoute map[string]*breaker
+}
+
+// get returns the breaker of a route, or nil when circuit breaking is disabled.
+func (c *circuitBreakers) get(route string) *breaker {
+	if c.threshold <= 0 {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	b, ok := c.byRoute[route]
+	if !ok {
+		b = &breaker{threshold: c.threshold, cooldown: c.cooldown}
+		c.byRoute[route] = b
+	}
+	return b
+}
+
+// breaker opens after threshold consecutive failures and skips its route until the cooldown
+// ends; it then lets one trial request through, which closes it on success and reopens it on
+// failure. A nil breaker always allows.
+type breaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	trial     bool
+}
+
+// allow reports whether a request may be sent to the route.
+func (b *breaker) allow() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.threshold {
+		return true
+	}
+	if b.trial || time.Now().Before(b.openUntil) {
+		return false
+	}
+	b.trial = true
+	return true
+}
+
+// success closes the circuit.
+func (b *breaker) success() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures, b.trial = 0, false
+}
+
+// failure counts a retryable failure, opening the circuit at the threshold.
+func (b *breaker) failure() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	b.trial = false
+	if b.failures >= b.threshold {
+		b.openUntil = time.Now().Add(b.cooldown)
+	}
+}
+
+// release ends an allowed request that says nothing about the route's health (the caller gave
+// up, or the request itself was rejected), freeing the trial slot of a half-open circuit.
+func (b *breaker) release() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trial = false
+}
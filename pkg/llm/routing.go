@@ -0,0 +1,153 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Route is one provider, and optionally one of its models, a routing policy may send a
+// completion to.
+type Route struct {
+	Provider string `json:"provider"`
+	// Model overrides the request's model; empty keeps the request's (or the provider's default).
+	Model string `json:"model,omitempty"`
+	// Weight is the route's share of the traffic of its tier.
+	Weight int `json:"weight"`
+}
+
+// String renders the route as provider/model, or just the provider when it has no model.
+func (r Route) String() string {
+	if r.Model == "" {
+		return r.Provider
+	}
+	return r.Provider + "/" + r.Model
+}
+
+// RoutingPolicy is a named fallback chain served as a single Provider. Tiers are tried in order;
+// the routes of a tier are tried in weighted random order before falling back to the next tier.
+type RoutingPolicy struct {
+	Name  string    `json:"name"`
+	Tiers [][]Route `json:"tiers"`
+}
+
+// Routing reports how a routing policy served a completion.
+type Routing struct {
+	// Policy is the name of the policy the completion was requested from.
+	Policy string `json:"policy"`
+	// Provider and Model identify the route that served it; Model is empty when the route used
+	// the provider's default model.
+	Provider string `json:"provider"`
+	Model    string `json:"model,omitempty"`
+	// Fallbacks are the routes tried, or skipped, before it, in order.
+	Fallbacks []FallbackAttempt `json:"fallbacks,omitempty"`
+}
+
+// FallbackCircuitOpen is the Reason of a route skipped, without a request, because its circuit
+// breaker is open.
+const FallbackCircuitOpen = "circuit open"
+
+// FallbackAttempt is a route a routing policy moved past, and why.
+type FallbackAttempt struct {
+	Provider string `json:"provider"`
+	Model    string `json:"model,omitempty"`
+	// Reason is FallbackCircuitOpen, "timeout", "connection failed", or "status <code>".
+	Reason string `json:"reason"`
+}
+
+// ParseRoutingPolicies parses semicolon-separated "name=chain" policies. A chain lists tiers
+// separated by ">", a tier lists routes separated by "|", and a route is provider[/model][*weight]
+// with the model split off at the first slash, e.g.
+// "resilient=anthropic/claude-sonnet-4-5>openai/gpt-4o>ollama;canary=openai/gpt-4o*9|openai/gpt-4.1>ollama".
+// Weights default to 1. An empty spec defines no policies.
+func ParseRoutingPolicies(spec string) ([]RoutingPolicy, error) {
+	var policies []RoutingPolicy
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, chain, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || strings.TrimSpace(chain) == "" {
+			return nil, fmt.Errorf("llm routing policy %q: expected name=provider/model>provider/model", entry)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("llm routing policy %q is defined twice", name)
+		}
+		seen[name] = true
+
+		policy := RoutingPolicy{Name: name}
+		for _, tierSpec := range strings.Split(chain, ">") {
+			var tier []Route
+			for _, routeSpec := range strings.Split(tierSpec, "|") {
+				route, err := parseRoute(strings.TrimSpace(routeSpec))
+				if err != nil {
+					return nil, fmt.Errorf("llm routing policy %q: %w", name, err)
+				}
+				tier = append(tier, route)
+			}
+			policy.Tiers = append(policy.Tiers, tier)
+		}
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
+
+func parseRoute(spec string) (Route, error) {
+	route := Route{Weight: 1}
+	if star := strings.LastIndex(spec, "*"); star >= 0 {
+		weight, err := strconv.Atoi(strings.TrimSpace(spec[star+1:]))
+		if err != nil || weight < 1 {
+			return Route{}, fmt.Errorf("route %q: weight must be a positive integer", spec)
+		}
+		route.Weight, spec = weight, strings.TrimSpace(spec[:star])
+	}
+	provider, model, hasModel := strings.Cut(spec, "/")
+	route.Provider, route.Model = strings.TrimSpace(provider), strings.TrimSpace(model)
+	if route.Provider == "" || (hasModel && route.Model == "") {
+		return Route{}, fmt.Errorf("route %q: expected provider or provider/model", spec)
+	}
+	return route, nil
+}
+
+// StatusError carries the HTTP status of a request a provider's API rejected, so a routing policy
+// can tell rate limits and outages from requests no other route would accept either.
+type StatusError struct {
+	StatusCode int
+	Err        error
+}
+
+// Error implements error.
+func (e *StatusError) Error() string { return e.Err.Error() }
+
+// Unwrap returns the underlying SDK error.
+func (e *StatusError) Unwrap() error { return e.Err }
+
+// fallbackReason classifies a failed attempt. It returns false for failures another route would
+// not fix (a malformed request, bad credentials), which end the policy instead of falling back.
+func fallbackReason(err error) (string, bool) {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		code := statusErr.StatusCode
+		return "status " + strconv.Itoa(code), code == http.StatusTooManyRequests || code == http.StatusRequestTimeout || code >= http.StatusInternalServerError
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timeout", true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return "timeout", true
+	}
+	// A connection that could not be established never reached the provider.
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return "connection failed", true
+	}
+	return "", false
+}
@@ -0,0 +1,230 @@
+package llm_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/open-source-cloud/fuse/pkg/llm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedProvider answers with the next scripted error (nil answers "ok from <name>") and
+// records the models it was asked for.
+type scriptedProvider struct {
+	name string
+
+	mu     sync.Mutex
+	errs   []error
+	models []string
+}
+
+func (p *scriptedProvider) Name() string { return p.name }
+
+func (p *scriptedProvider) Chat(ctx context.Context, req llm.ChatRequest) (llm.ChatResponse, error) {
+	p.mu.Lock()
+	p.models = append(p.models, req.Model)
+	var err error
+	if len(p.errs) > 0 {
+		err, p.errs = p.errs[0], p.errs[1:]
+	}
+	p.mu.Unlock()
+	if errors.Is(err, context.DeadlineExceeded) {
+		<-ctx.Done()
+		return llm.ChatResponse{}, ctx.Err()
+	}
+	if err != nil {
+		return llm.ChatResponse{}, err
+	}
+	return llm.ChatResponse{Message: llm.Message{Role: llm.RoleAssistant, Content: "ok from " + p.name}}, nil
+}
+
+func (p *scriptedProvider) calls() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.models)
+}
+
+// streamingProvider streams its answer in two deltas, or fails before the first one.
+type streamingProvider struct {
+	scriptedProvider
+}
+
+func (p *streamingProvider) ChatStream(ctx context.Context, req llm.ChatRequest) (<-chan llm.StreamChunk, error) {
+	resp, err := p.Chat(ctx, req)
+	chunks := make(chan llm.StreamChunk, 3)
+	if err != nil {
+		chunks <- llm.StreamChunk{Err: err}
+	} else {
+		chunks <- llm.StreamChunk{ContentDelta: "ok "}
+		chunks <- llm.StreamChunk{ContentDelta: "from " + p.name}
+		chunks <- llm.StreamChunk{Done: true, Response: &resp}
+	}
+	close(chunks)
+	return chunks, nil
+}
+
+func rateLimited() error {
+	return &llm.StatusError{StatusCode: 429, Err: errors.New("too many requests")}
+}
+
+func newPolicy(t *testing.T, spec string, opts llm.RoutingOptions, providers ...llm.Provider) llm.Provider {
+	t.Helper()
+	policies, err := llm.ParseRoutingPolicies(spec)
+	require.NoError(t, err)
+	base := make(map[string]llm.ProviderFactory, len(providers))
+	for _, p := range providers {
+		base[p.Name()] = func(context.Context, string) (llm.Provider, error) { return p, nil }
+	}
+	factories, err := llm.RoutingFactories(policies, base, opts)
+	require.NoError(t, err)
+	provider, err := factories[policies[0].Name](context.Background(), "")
+	require.NoError(t, err)
+	return provider
+}
+
+func TestParseRoutingPolicies(t *testing.T) {
+	t.Parallel()
+
+	policies, err := llm.ParseRoutingPolicies(" resilient=anthropic/claude-sonnet-4-5 > openai/gpt-4o > ollama ; canary=openrouter/openai/gpt-4o*9|openai/gpt-4.1;")
+	require.NoError(t, err)
+	require.Len(t, policies, 2)
+	assert.Equal(t, llm.RoutingPolicy{Name: "resilient", Tiers: [][]llm.Route{
+		{{Provider: "anthropic", Model: "claude-sonnet-4-5", Weight: 1}},
+		{{Provider: "openai", Model: "gpt-4o", Weight: 1}},
+		{{Provider: "ollama", Weight: 1}},
+	}}, policies[0])
+	assert.Equal(t, [][]llm.Route{{
+		{Provider: "openrouter", Model: "openai/gpt-4o", Weight: 9},
+		{Provider: "openai", Model: "gpt-4.1", Weight: 1},
+	}}, policies[1].Tiers)
+
+	empty, err := llm.ParseRoutingPolicies("")
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+
+	for _, spec := range []string{"resilient", "=openai", "p=openai>", "p=openai/", "p=openai*0", "p=openai*x", "p=openai;p=ollama"} {
+		_, err := llm.ParseRoutingPolicies(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestRoutingFactories_Validation(t *testing.T) {
+	t.Parallel()
+
+	base := map[string]llm.ProviderFactory{"openai": nil}
+	_, err := llm.RoutingFactories([]llm.RoutingPolicy{{Name: "openai", Tiers: [][]llm.Route{{{Provider: "openai", Weight: 1}}}}}, base, llm.RoutingOptions{})
+	assert.ErrorContains(t, err, "name is taken")
+
+	_, err = llm.RoutingFactories([]llm.RoutingPolicy{{Name: "resilient", Tiers: [][]llm.Route{{{Provider: "anthropic", Weight: 1}}}}}, base, llm.RoutingOptions{})
+	assert.ErrorIs(t, err, llm.ErrProviderNotFound)
+}
+
+func TestRoutedProvider_FallsBackOnRetryableErrors(t *testing.T) {
+	t.Parallel()
+
+	anthropic := &scriptedProvider{name: "anthropic", errs: []error{rateLimited()}}
+	openai := &scriptedProvider{name: "openai", errs: []error{context.DeadlineExceeded}}
+	ollama := &scriptedProvider{name: "ollama"}
+	policy := newPolicy(t, "resilient=anthropic/claude-sonnet-4-5>openai/gpt-4o>ollama",
+		llm.RoutingOptions{AttemptTimeout: 20 * time.Millisecond}, anthropic, openai, ollama)
+	assert.Equal(t, "resilient", policy.Name())
+
+	resp, err := policy.Chat(context.Background(), llm.ChatRequest{Model: "llama3.1"})
+	require.NoError(t, err)
+	assert.Equal(t, "ok from ollama", resp.Message.Content)
+	assert.Equal(t, &llm.Routing{
+		Policy:   "resilient",
+		Provider: "ollama",
+		Model:    "llama3.1",
+		Fallbacks: []llm.FallbackAttempt{
+			{Provider: "anthropic", Model: "claude-sonnet-4-5", Reason: "status 429"},
+			{Provider: "openai", Model: "gpt-4o", Reason: "timeout"},
+		},
+	}, resp.Routing)
+	assert.Equal(t, []string{"claude-sonnet-4-5"}, anthropic.models, "a route's model overrides the request's")
+	assert.Equal(t, []string{"llama3.1"}, ollama.models)
+}
+
+func TestRoutedProvider_StopsOnOtherErrors(t *testing.T) {
+	t.Parallel()
+
+	badRequest := &llm.StatusError{StatusCode: 400, Err: errors.New("invalid tools")}
+	anthropic := &scriptedProvider{name: "anthropic", errs: []error{badRequest}}
+	openai := &scriptedProvider{name: "openai", errs: []error{rateLimited()}}
+	ollama := &scriptedProvider{name: "ollama"}
+
+	_, err := newPolicy(t, "p=anthropic>ollama", llm.RoutingOptions{}, anthropic, ollama).Chat(context.Background(), llm.ChatRequest{})
+	assert.ErrorIs(t, err, badRequest)
+	assert.Zero(t, ollama.calls(), "a request no route would accept does not fall back")
+
+	_, err = newPolicy(t, "p=openai", llm.RoutingOptions{}, openai).Chat(context.Background(), llm.ChatRequest{})
+	assert.ErrorIs(t, err, llm.ErrRoutesExhausted)
+	assert.ErrorContains(t, err, "openai (status 429)")
+}
+
+func TestRoutedProvider_CircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	openai := &scriptedProvider{name: "openai", errs: []error{rateLimited(), rateLimited()}}
+	ollama := &scriptedProvider{name: "ollama"}
+	policy := newPolicy(t, "p=openai/gpt-4o>ollama", llm.RoutingOptions{BreakerFailures: 2, BreakerCooldown: 50 * time.Millisecond}, openai, ollama)
+	ctx := context.Background()
+
+	for range 2 {
+		resp, err := policy.Chat(ctx, llm.ChatRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, "status 429", resp.Routing.Fallbacks[0].Reason)
+	}
+	resp, err := policy.Chat(ctx, llm.ChatRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "circuit open", resp.Routing.Fallbacks[0].Reason)
+	assert.Equal(t, 2, openai.calls(), "an open circuit skips its route")
+
+	time.Sleep(60 * time.Millisecond)
+	resp, err = policy.Chat(ctx, llm.ChatRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "openai", resp.Routing.Provider, "the trial after the cooldown closes the circuit")
+	assert.Empty(t, resp.Routing.Fallbacks)
+}
+
+func TestRoutedProvider_WeightedTier(t *testing.T) {
+	t.Parallel()
+
+	primary := &scriptedProvider{name: "primary"}
+	canary := &scriptedProvider{name: "canary"}
+	policy := newPolicy(t, "p=primary*3|canary", llm.RoutingOptions{}, primary, canary)
+	for range 400 {
+		_, err := policy.Chat(context.Background(), llm.ChatRequest{})
+		require.NoError(t, err)
+	}
+	assert.InDelta(t, 300, primary.calls(), 60)
+	assert.Equal(t, 400, primary.calls()+canary.calls())
+}
+
+func TestRoutedProvider_StreamFallsBackBeforeFirstDelta(t *testing.T) {
+	t.Parallel()
+
+	openai := &streamingProvider{scriptedProvider{name: "openai", errs: []error{&llm.StatusError{StatusCode: 503, Err: errors.New("unavailable")}}}}
+	ollama := &streamingProvider{scriptedProvider{name: "ollama"}}
+	policy := newPolicy(t, "p=openai>ollama", llm.RoutingOptions{}, openai, ollama)
+
+	chunks, err := policy.(llm.StreamingProvider).ChatStream(context.Background(), llm.ChatRequest{})
+	require.NoError(t, err)
+	var text string
+	var final *llm.ChatResponse
+	for chunk := range chunks {
+		require.NoError(t, chunk.Err)
+		text += chunk.ContentDelta
+		if chunk.Done {
+			final = chunk.Response
+		}
+	}
+	assert.Equal(t, "ok from ollama", text)
+	require.NotNil(t, final)
+	assert.Equal(t, "ollama", final.Routing.Provider)
+	assert.Equal(t, []llm.FallbackAttempt{{Provider: "openai", Reason: "status 503"}}, final.Routing.Fallbacks)
+}
@@ -0,0 +1,183 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"slices"
+	"sync/atomic"
+)
+
+// maxToolPages bounds how many tools/list pages a client follows, so a server that keeps
+// returning a cursor cannot stall discovery.
+const maxToolPages = 100
+
+// ErrClosed is returned by calls on a closed transport, or one whose server went away.
+var ErrClosed = errors.New("mcp: connection closed")
+
+// Transport carries JSON-RPC messages to one server.
+type Transport interface {
+	// RoundTrip sends a request and waits for its response, or for ctx to end.
+	RoundTrip(ctx context.Context, req *Message) (*Message, error)
+	// Notify sends a notification.
+	Notify(ctx context.Context, msg *Message) error
+	// Close ends the connection; a stdio server is stopped.
+	Close() error
+}
+
+// protocolVersionSetter is implemented by transports that send the negotiated protocol version
+// with every message (streamable HTTP).
+type protocolVersionSetter interface {
+	setProtocolVersion(version string)
+}
+
+// Client is an MCP client of one server. It is safe for concurrent use once initialized.
+type Client struct {
+	transport Transport
+	info      Implementation
+	nextID    atomic.Int64
+	server    InitializeResult
+}
+
+// NewClient returns a client over transport; call Initialize before anything else.
+func NewClient(transport Transport, info Implementation) *Client {
+	return &Client{transport: transport, info: info}
+}
+
+// Connect starts or reaches the server config describes and completes the handshake. The config's
+// references must already be resolved.
+func Connect(ctx context.Context, cfg ServerConfig, info Implementation) (*Client, error) {
+	var transport Transport
+	switch cfg.Transport {
+	case TransportStdio:
+		cmd := exec.Command(cfg.Command, cfg.Args...) //nolint:gosec // G204: the command is the registered server's
+		cmd.Env = stdioEnv(cfg.Env)
+		t, err := NewStdioTransport(cmd)
+		if err != nil {
+			return nil, err
+		}
+		transport = t
+	case TransportHTTP:
+		transport = NewHTTPTransport(cfg.URL, cfg.Headers, nil)
+	default:
+		return nil, fmt.Errorf("mcp: unknown transport %q", cfg.Transport)
+	}
+
+	client := NewClient(transport, info)
+	if _, err := client.Initialize(ctx); err != nil {
+		_ = transport.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+// stdioEnv is the environment of a stdio server: PATH and HOME of the engine, so the command can
+// be found and run, plus the configured variables.
+func stdioEnv(env map[string]string) []string {
+	out := make([]string, 0, len(env)+2)
+	for _, name := range []string{"PATH", "HOME"} {
+		if v, ok := os.LookupEnv(name); ok {
+			if _, set := env[name]; !set {
+				out = append(out, name+"="+v)
+			}
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(env)) {
+		out = append(out, name+"="+env[name])
+	}
+	return out
+}
+
+// Initialize performs the handshake: it offers ProtocolVersion, checks the server's answer and
+// sends notifications/initialized.
+func (c *Client) Initialize(ctx context.Context) (*InitializeResult, error) {
+	params := InitializeParams{ProtocolVersion: ProtocolVersion, Capabilities: map[string]any{}, ClientInfo: c.info}
+	var result InitializeResult
+	if err := c.call(ctx, MethodInitialize, params, &result); err != nil {
+		return nil, err
+	}
+	if _, ok := supportedProtocolVersions[result.ProtocolVersion]; !ok {
+		return nil, fmt.Errorf("mcp: server %q speaks unsupported protocol version %q", result.ServerInfo.Name, result.ProtocolVersion)
+	}
+	if setter, ok := c.transport.(protocolVersionSetter); ok {
+		setter.setProtocolVersion(result.ProtocolVersion)
+	}
+	msg, err := NewNotification(MethodInitialized, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.transport.Notify(ctx, msg); err != nil {
+		return nil, err
+	}
+	c.server = result
+	return &result, nil
+}
+
+// Server returns what the server reported in the handshake.
+func (c *Client) Server() InitializeResult {
+	return c.server
+}
+
+// ListTools returns every tool the server offers, following pagination.
+func (c *Client) ListTools(ctx context.Context) ([]Tool, error) {
+	tools := make([]Tool, 0)
+	cursor := ""
+	for range maxToolPages {
+		var page ListToolsResult
+		if err := c.call(ctx, MethodToolsList, ListToolsParams{Cursor: cursor}, &page); err != nil {
+			return nil, err
+		}
+		tools = append(tools, page.Tools...)
+		if page.NextCursor == "" {
+			return tools, nil
+		}
+		cursor = page.NextCursor
+	}
+	return nil, fmt.Errorf("mcp: tools/list returned more than %d pages", maxToolPages)
+}
+
+// CallTool calls a tool. A failure of the tool itself is a result with IsError set, not an error.
+func (c *Client) CallTool(ctx context.Context, name string, args map[string]any) (*CallToolResult, error) {
+	var result CallToolResult
+	if err := c.call(ctx, MethodToolsCall, CallToolParams{Name: name, Arguments: args}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ReadResource reads the resource at uri.
+func (c *Client) ReadResource(ctx context.Context, uri string) (*ReadResourceResult, error) {
+	var result ReadResourceResult
+	if err := c.call(ctx, MethodResourcesRead, ReadResourceParams{URI: uri}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Close closes the transport.
+func (c *Client) Close() error {
+	return c.transport.Close()
+}
+
+// call sends a request and decodes its result into result.
+func (c *Client) call(ctx context.Context, method string, params, result any) error {
+	req, err := NewRequest(c.nextID.Add(1), method, params)
+	if err != nil {
+		return err
+	}
+	resp, err := c.transport.RoundTrip(ctx, req)
+	if err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if err := json.Unmarshal(resp.Result, result); err != nil {
+		return fmt.Errorf("mcp: decode %s result: %w", method, err)
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package mcp_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/open-source-cloud/fuse/pkg/mcp"
+	"github.com/open-source-cloud/fuse/pkg/mcp/mcptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stdioServerEnv makes the test binary serve the fake server over stdio instead of running tests.
+const stdioServerEnv = "MCPTEST_STDIO_SERVER"
+
+var clientInfo = mcp.Implementation{Name: "fuse-test", Version: "0.0.0"}
+
+var searchTool = mcp.Tool{
+	Name:        "search",
+	Description: "Searches the wiki",
+	InputSchema: map[string]any{"type": "object", "properties": map[string]any{"query": map[string]any{"type": "string"}}},
+}
+
+func TestMain(m *testing.M) {
+	if os.Getenv(stdioServerEnv) != "" {
+		server := &mcptest.Server{Tools: []mcp.Tool{searchTool}}
+		if err := server.ServeStdio(os.Stdin, os.Stdout); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func TestClient_HTTP(t *testing.T) {
+	for _, sse := range []bool{false, true} {
+		name := "json"
+		if sse {
+			name = "event stream"
+		}
+		t.Run(name, func(t *testing.T) {
+			server := &mcptest.Server{Tools: []mcp.Tool{searchTool, {Name: "open"}, {Name: "close"}}, PageSize: 2, SSE: sse}
+			httpServer := httptest.NewServer(server)
+			defer httpServer.Close()
+			ctx := context.Background()
+
+			client, err := mcp.Connect(ctx, mcp.ServerConfig{
+				ID: "wiki", Transport: mcp.TransportHTTP, URL: httpServer.URL,
+				Headers: map[string]string{"Authorization": "Bearer token"},
+			}, clientInfo)
+			require.NoError(t, err)
+
+			tools, err := client.ListTools(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"search", "open", "close"}, toolNames(tools))
+
+			res, err := client.CallTool(ctx, "search", map[string]any{"query": "fuse"})
+			require.NoError(t, err)
+			assert.JSONEq(t, `{"query":"fuse"}`, res.Text())
+			require.NoError(t, client.Close())
+
+			headers := server.Headers()
+			require.NotEmpty(t, headers)
+			for _, h := range headers {
+				assert.Equal(t, "Bearer token", h.Get("Authorization"))
+			}
+			last := headers[len(headers)-1]
+			assert.Equal(t, mcptest.SessionID, last.Get(mcp.HeaderSessionID))
+			assert.Equal(t, mcp.ProtocolVersion, last.Get(mcp.HeaderProtocolVersion))
+			assert.Equal(t, 1, server.Sessions())
+		})
+	}
+}
+
+func TestClient_Stdio(t *testing.T) {
+	exe, err := os.Executable()
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	client, err := mcp.Connect(ctx, mcp.ServerConfig{
+		ID: "wiki", Transport: mcp.TransportStdio, Command: exe, Env: map[string]string{stdioServerEnv: "1"},
+	}, clientInfo)
+	require.NoError(t, err)
+	assert.Equal(t, "mcptest", client.Server().ServerInfo.Name)
+
+	tools, err := client.ListTools(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"search"}, toolNames(tools))
+
+	res, err := client.CallTool(ctx, "search", map[string]any{"query": "stdio"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"query":"stdio"}`, res.Text())
+
+	require.NoError(t, client.Close())
+	_, err = client.CallTool(ctx, "search", nil)
+	assert.ErrorIs(t, err, mcp.ErrClosed)
+}
+
+func TestClient_Errors(t *testing.T) {
+	server := &mcptest.Server{Call: func(ctx context.Context, name string, _ map[string]any) (*mcp.CallToolResult, error) {
+		switch name {
+		case "broken":
+			return &mcp.CallToolResult{Content: []mcp.Content{mcp.TextContent("quota exceeded")}, IsError: true}, nil
+		case "slow":
+			<-ctx.Done()
+			return nil, ctx.Err()
+		default:
+			return nil, errors.New("no such tool")
+		}
+	}}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+	ctx := context.Background()
+	client, err := mcp.Connect(ctx, mcp.ServerConfig{ID: "wiki", Transport: mcp.TransportHTTP, URL: httpServer.URL}, clientInfo)
+	require.NoError(t, err)
+	defer func() { _ = client.Close() }()
+
+	res, err := client.CallTool(ctx, "broken", nil)
+	require.NoError(t, err, "a failing tool is a result")
+	assert.True(t, res.IsError)
+	assert.Equal(t, "quota exceeded", res.Text())
+
+	_, err = client.CallTool(ctx, "missing", nil)
+	var rpcErr *mcp.Error
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, mcp.CodeInternalError, rpcErr.Code)
+
+	timeout, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	_, err = client.CallTool(timeout, "slow", nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestClient_ExpiredSession(t *testing.T) {
+	server := &mcptest.Server{}
+	var expired atomic.Bool
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if expired.Load() {
+			http.Error(w, "unknown session", http.StatusNotFound)
+			return
+		}
+		server.ServeHTTP(w, r)
+	}))
+	defer httpServer.Close()
+	ctx := context.Background()
+	client, err := mcp.Connect(ctx, mcp.ServerConfig{ID: "wiki", Transport: mcp.TransportHTTP, URL: httpServer.URL}, clientInfo)
+	require.NoError(t, err)
+
+	expired.Store(true)
+	_, err = client.ListTools(ctx)
+	assert.ErrorIs(t, err, mcp.ErrSessionExpired)
+}
+
+func TestServerConfig_Validate(t *testing.T) {
+	cases := map[string]struct {
+		cfg  mcp.ServerConfig
+		want string
+	}{
+		"stdio":           {cfg: mcp.ServerConfig{ID: "git", Transport: mcp.TransportStdio, Command: "mcp-git"}},
+		"http":            {cfg: mcp.ServerConfig{ID: "wiki-search", Transport: mcp.TransportHTTP, URL: "https://wiki.example.com/mcp", Timeout: time.Minute}},
+		"id":              {cfg: mcp.ServerConfig{ID: "Wiki__Search", Transport: mcp.TransportHTTP, URL: "https://wiki.example.com/mcp"}, want: "id"},
+		"transport":       {cfg: mcp.ServerConfig{ID: "wiki", Transport: "websocket"}, want: "transport"},
+		"no command":      {cfg: mcp.ServerConfig{ID: "git", Transport: mcp.TransportStdio}, want: "command"},
+		"stdio with url":  {cfg: mcp.ServerConfig{ID: "git", Transport: mcp.TransportStdio, Command: "mcp-git", URL: "https://x"}, want: "url"},
+		"no url":          {cfg: mcp.ServerConfig{ID: "wiki", Transport: mcp.TransportHTTP, URL: "wiki.example.com"}, want: "url"},
+		"http with env":   {cfg: mcp.ServerConfig{ID: "wiki", Transport: mcp.TransportHTTP, URL: "https://x", Env: map[string]string{"A": "b"}}, want: "env"},
+		"timeout too big": {cfg: mcp.ServerConfig{ID: "wiki", Transport: mcp.TransportHTTP, URL: "https://x", Timeout: time.Hour}, want: "timeout"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if tc.want == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, mcp.ErrInvalidServerConfig)
+			assert.ErrorContains(t, err, tc.want)
+		})
+	}
+}
+
+func toolNames(tools []mcp.Tool) []string {
+	names := make([]string, len(tools))
+	for i, tool := range tools {
+		names[i] = tool.Name
+	}
+	return names
+}
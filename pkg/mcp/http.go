@@ -0,0 +1,206 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Streamable HTTP headers.
+const (
+	HeaderSessionID       = "Mcp-Session-Id"
+	HeaderProtocolVersion = "Mcp-Protocol-Version"
+)
+
+const (
+	contentTypeJSON = "application/json"
+	contentTypeSSE  = "text/event-stream"
+	// maxErrorBody bounds how much of a failed response is quoted in the error.
+	maxErrorBody = 512
+	// sessionCloseTimeout bounds the DELETE that ends a session on Close.
+	sessionCloseTimeout = 5 * time.Second
+)
+
+// ErrSessionExpired is returned when the server no longer knows the session; the client has to
+// connect again.
+var ErrSessionExpired = errors.New("mcp: session expired")
+
+// HTTPTransport talks to a server over streamable HTTP: every message is POSTed to one endpoint
+// and the response comes back as JSON or as a server-sent event stream. It keeps the session id
+// the server assigns during initialize.
+type HTTPTransport struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+
+	mu              sync.Mutex
+	sessionID       string
+	protocolVersion string
+}
+
+// NewHTTPTransport returns a transport to the endpoint url, sending headers (e.g. Authorization)
+// with every request. A nil client uses a default one; calls are bounded by their context.
+func NewHTTPTransport(url string, headers map[string]string, client *http.Client) *HTTPTransport {
+	if client == nil {
+		client = &http.Client{}
+	}
+	return &HTTPTransport{url: url, headers: headers, client: client}
+}
+
+// RoundTrip POSTs req and reads its response from the JSON body or the event stream.
+func (t *HTTPTransport) RoundTrip(ctx context.Context, req *Message) (*Message, error) {
+	resp, err := t.post(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	switch mediaType {
+	case contentTypeJSON:
+		var msg Message
+		if err := json.NewDecoder(resp.Body).Decode(&msg); err != nil {
+			return nil, fmt.Errorf("mcp: decode %s response: %w", req.Method, err)
+		}
+		return &msg, nil
+	case contentTypeSSE:
+		return readEventStream(resp.Body, string(req.ID))
+	default:
+		return nil, fmt.Errorf("mcp: %s response has unexpected content type %q", req.Method, mediaType)
+	}
+}
+
+// Notify POSTs a notification; the server acknowledges it without a body.
+func (t *HTTPTransport) Notify(ctx context.Context, msg *Message) error {
+	resp, err := t.post(ctx, msg)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return resp.Body.Close()
+}
+
+// Close ends the session, when the server assigned one.
+func (t *HTTPTransport) Close() error {
+	t.mu.Lock()
+	sessionID := t.sessionID
+	t.sessionID = ""
+	t.mu.Unlock()
+	if sessionID == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sessionCloseTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, t.url, nil)
+	if err != nil {
+		return err
+	}
+	t.setHeaders(req, sessionID)
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (t *HTTPTransport) setProtocolVersion(version string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.protocolVersion = version
+}
+
+// post sends msg and returns the successful response; its body must be closed.
+func (t *HTTPTransport) post(ctx context.Context, msg *Message) (*http.Response, error) {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("mcp: encode %s: %w", msg.Method, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("mcp: %s request: %w", msg.Method, err)
+	}
+	t.mu.Lock()
+	sessionID := t.sessionID
+	t.mu.Unlock()
+	t.setHeaders(req, sessionID)
+	req.Header.Set("Content-Type", contentTypeJSON)
+	req.Header.Set("Accept", contentTypeJSON+", "+contentTypeSSE)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("mcp: %s: %w", msg.Method, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		_ = resp.Body.Close()
+		if resp.StatusCode == http.StatusNotFound && sessionID != "" {
+			return nil, ErrSessionExpired
+		}
+		return nil, fmt.Errorf("mcp: %s: server answered %s: %s", msg.Method, resp.Status, strings.TrimSpace(string(snippet)))
+	}
+	if id := resp.Header.Get(HeaderSessionID); id != "" && msg.Method == MethodInitialize {
+		t.mu.Lock()
+		t.sessionID = id
+		t.mu.Unlock()
+	}
+	return resp, nil
+}
+
+// setHeaders sets the configured headers, the session id and the negotiated protocol version.
+func (t *HTTPTransport) setHeaders(req *http.Request, sessionID string) {
+	for name, value := range t.headers {
+		req.Header.Set(name, value)
+	}
+	if sessionID != "" {
+		req.Header.Set(HeaderSessionID, sessionID)
+	}
+	t.mu.Lock()
+	version := t.protocolVersion
+	t.mu.Unlock()
+	if version != "" {
+		req.Header.Set(HeaderProtocolVersion, version)
+	}
+}
+
+// readEventStream reads server-sent events until the response with id arrives. Requests and
+// notifications the server sends on the stream first are skipped.
+func readEventStream(body io.Reader, id string) (*Message, error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxMessageSize)
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line != "" {
+			if payload, ok := strings.CutPrefix(line, "data:"); ok {
+				if data.Len() > 0 {
+					data.WriteByte('\n')
+				}
+				data.WriteString(strings.TrimPrefix(payload, " "))
+			}
+			continue
+		}
+		if data.Len() == 0 {
+			continue
+		}
+		var msg Message
+		err := json.Unmarshal([]byte(data.String()), &msg)
+		data.Reset()
+		if err == nil && msg.IsResponse() && string(msg.ID) == id {
+			return &msg, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("mcp: read event stream: %w", err)
+	}
+	return nil, fmt.Errorf("%w: event stream ended without a response", ErrClosed)
+}
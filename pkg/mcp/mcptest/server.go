@@ -0,0 +1,193 @@
+// Package mcptest provides a small in-process MCP server for tests, served over streamable HTTP
+// or stdio.
+package mcptest
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/open-source-cloud/fuse/pkg/mcp"
+)
+
+// SessionID is the session id the server assigns over HTTP.
+const SessionID = "mcptest-session"
+
+// Server is a fake MCP server offering Tools. Its zero value serves no tools.
+type Server struct {
+	// Tools are listed by tools/list.
+	Tools []mcp.Tool
+	// Call answers tools/call. An error becomes a JSON-RPC error; a nil Call echoes the
+	// arguments back as text.
+	Call func(ctx context.Context, name string, args map[string]any) (*mcp.CallToolResult, error)
+	// PageSize splits tools/list into pages of that many tools; zero lists them in one page.
+	PageSize int
+	// SSE makes the HTTP server answer requests with an event stream instead of a JSON body.
+	SSE bool
+
+	mu       sync.Mutex
+	calls    []mcp.CallToolParams
+	headers  []http.Header
+	sessions int
+}
+
+// Calls returns the tools/call requests received so far.
+func (s *Server) Calls() []mcp.CallToolParams {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]mcp.CallToolParams(nil), s.calls...)
+}
+
+// Headers returns the headers of the HTTP requests received so far.
+func (s *Server) Headers() []http.Header {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]http.Header(nil), s.headers...)
+}
+
+// Sessions returns how many times a client initialized.
+func (s *Server) Sessions() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sessions
+}
+
+// Handle answers one message, or returns nil for a notification.
+func (s *Server) Handle(ctx context.Context, msg *mcp.Message) *mcp.Message {
+	if msg.IsNotification() {
+		return nil
+	}
+	switch msg.Method {
+	case mcp.MethodInitialize:
+		s.mu.Lock()
+		s.sessions++
+		s.mu.Unlock()
+		return result(msg, mcp.InitializeResult{
+			ProtocolVersion: mcp.ProtocolVersion,
+			Capabilities:    map[string]any{"tools": map[string]any{}},
+			ServerInfo:      mcp.Implementation{Name: "mcptest", Version: "1.0.0"},
+		})
+	case mcp.MethodPing:
+		return result(msg, map[string]any{})
+	case mcp.MethodToolsList:
+		var params mcp.ListToolsParams
+		_ = json.Unmarshal(msg.Params, &params)
+		return result(msg, s.page(params.Cursor))
+	case mcp.MethodToolsCall:
+		var params mcp.CallToolParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return mcp.NewErrorResponse(msg.ID, mcp.CodeInvalidParams, err.Error())
+		}
+		s.mu.Lock()
+		s.calls = append(s.calls, params)
+		s.mu.Unlock()
+		res, err := s.call(ctx, params)
+		if err != nil {
+			return mcp.NewErrorResponse(msg.ID, mcp.CodeInternalError, err.Error())
+		}
+		return result(msg, res)
+	default:
+		return mcp.NewErrorResponse(msg.ID, mcp.CodeMethodNotFound, "unknown method "+msg.Method)
+	}
+}
+
+// ServeHTTP serves the streamable HTTP transport on any path.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.headers = append(s.headers, r.Header.Clone())
+	s.mu.Unlock()
+
+	if r.Method == http.MethodDelete {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	var msg mcp.Message
+	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if msg.Method != mcp.MethodInitialize && r.Header.Get(mcp.HeaderSessionID) != SessionID {
+		http.Error(w, "unknown session", http.StatusNotFound)
+		return
+	}
+
+	resp := s.Handle(r.Context(), &msg)
+	if resp == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	if msg.Method == mcp.MethodInitialize {
+		w.Header().Set(mcp.HeaderSessionID, SessionID)
+	}
+	raw, _ := json.Marshal(resp)
+	if s.SSE {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprintf(w, "event: message\ndata: %s\n\n", `{"jsonrpc":"2.0","method":"notifications/progress","params":{}}`)
+		_, _ = fmt.Fprintf(w, "event: message\ndata: %s\n\n", raw)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(raw)
+}
+
+// ServeStdio serves newline-delimited messages from r to w until r ends.
+func (s *Server) ServeStdio(r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64<<10), 1<<20)
+	for scanner.Scan() {
+		var msg mcp.Message
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			continue
+		}
+		if resp := s.Handle(context.Background(), &msg); resp != nil {
+			raw, _ := json.Marshal(resp)
+			if _, err := w.Write(append(raw, '\n')); err != nil {
+				return err
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+// page returns the tools/list page starting at cursor.
+func (s *Server) page(cursor string) mcp.ListToolsResult {
+	start, _ := strconv.Atoi(cursor)
+	start = min(max(start, 0), len(s.Tools))
+	end := len(s.Tools)
+	if s.PageSize > 0 {
+		end = min(start+s.PageSize, end)
+	}
+	page := mcp.ListToolsResult{Tools: append([]mcp.Tool{}, s.Tools[start:end]...)}
+	if end < len(s.Tools) {
+		page.NextCursor = strconv.Itoa(end)
+	}
+	return page
+}
+
+func (s *Server) call(ctx context.Context, params mcp.CallToolParams) (*mcp.CallToolResult, error) {
+	if s.Call != nil {
+		return s.Call(ctx, params.Name, params.Arguments)
+	}
+	raw, err := json.Marshal(params.Arguments)
+	if err != nil {
+		return nil, err
+	}
+	return &mcp.CallToolResult{Content: []mcp.Content{mcp.TextContent(string(raw))}}, nil
+}
+
+func result(req *mcp.Message, v any) *mcp.Message {
+	resp, err := mcp.NewResult(req.ID, v)
+	if err != nil {
+		return mcp.NewErrorResponse(req.ID, mcp.CodeInternalError, err.Error())
+	}
+	return resp
+}
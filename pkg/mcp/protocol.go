@@ -0,0 +1,260 @@
+// Package mcp implements the parts of the Model Context Protocol (MCP) FUSE speaks: JSON-RPC 2.0
+// messages, the initialize handshake, the tools methods and read-only resources. It has a client
+// over the stdio and streamable HTTP transports and a stateless streamable HTTP server.
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// ProtocolVersion is the MCP revision FUSE requests in the initialize handshake.
+const ProtocolVersion = "2025-06-18"
+
+// supportedProtocolVersions are the revisions a server may answer the handshake with. They share
+// the tools methods FUSE uses.
+var supportedProtocolVersions = map[string]struct{}{
+	ProtocolVersion: {},
+	"2025-03-26":    {},
+	"2024-11-05":    {},
+}
+
+const jsonRPCVersion = "2.0"
+
+// Method names.
+const (
+	MethodInitialize  = "initialize"
+	MethodInitialized = "notifications/initialized"
+	MethodCancelled   = "notifications/cancelled"
+	MethodPing        = "ping"
+	MethodToolsList   = "tools/list"
+	MethodToolsCall   = "tools/call"
+
+	MethodResourcesList         = "resources/list"
+	MethodResourceTemplatesList = "resources/templates/list"
+	MethodResourcesRead         = "resources/read"
+)
+
+// JSON-RPC error codes.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+
+	// CodeResourceNotFound answers resources/read for a URI the server does not know.
+	CodeResourceNotFound = -32002
+)
+
+type (
+	// Message is a JSON-RPC 2.0 message: a request (Method and ID), a notification (Method, no
+	// ID) or a response (ID with Result or Error).
+	Message struct {
+		JSONRPC string          `json:"jsonrpc"`
+		ID      json.RawMessage `json:"id,omitempty"`
+		Method  string          `json:"method,omitempty"`
+		Params  json.RawMessage `json:"params,omitempty"`
+		Result  json.RawMessage `json:"result,omitempty"`
+		Error   *Error          `json:"error,omitempty"`
+	}
+
+	// Error is a JSON-RPC error object.
+	Error struct {
+		Code    int             `json:"code"`
+		Message string          `json:"message"`
+		Data    json.RawMessage `json:"data,omitempty"`
+	}
+
+	// Implementation names a client or server.
+	Implementation struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+
+	// InitializeParams are the params of the initialize request.
+	InitializeParams struct {
+		ProtocolVersion string         `json:"protocolVersion"`
+		Capabilities    map[string]any `json:"capabilities"`
+		ClientInfo      Implementation `json:"clientInfo"`
+	}
+
+	// InitializeResult is the server's answer to initialize.
+	InitializeResult struct {
+		ProtocolVersion string         `json:"protocolVersion"`
+		Capabilities    map[string]any `json:"capabilities"`
+		ServerInfo      Implementation `json:"serverInfo"`
+		Instructions    string         `json:"instructions,omitempty"`
+	}
+
+	// Tool is a tool a server offers.
+	Tool struct {
+		Name        string         `json:"name"`
+		Title       string         `json:"title,omitempty"`
+		Description string         `json:"description,omitempty"`
+		InputSchema map[string]any `json:"inputSchema"`
+	}
+
+	// ListToolsParams are the params of tools/list.
+	ListToolsParams struct {
+		Cursor string `json:"cursor,omitempty"`
+	}
+
+	// ListToolsResult is one page of tools/list.
+	ListToolsResult struct {
+		Tools      []Tool `json:"tools"`
+		NextCursor string `json:"nextCursor,omitempty"`
+	}
+
+	// CallToolParams are the params of tools/call.
+	CallToolParams struct {
+		Name      string         `json:"name"`
+		Arguments map[string]any `json:"arguments,omitempty"`
+	}
+
+	// CallToolResult is the result of tools/call. IsError reports a failure of the tool itself,
+	// which the caller should see, as opposed to a protocol error.
+	CallToolResult struct {
+		Content           []Content      `json:"content"`
+		StructuredContent map[string]any `json:"structuredContent,omitempty"`
+		IsError           bool           `json:"isError,omitempty"`
+	}
+
+	// Content is one item of a tool result: text, an image or audio (base64 Data), or a resource
+	// link or embedded resource.
+	Content struct {
+		Type     string          `json:"type"`
+		Text     string          `json:"text,omitempty"`
+		Data     string          `json:"data,omitempty"`
+		MimeType string          `json:"mimeType,omitempty"`
+		URI      string          `json:"uri,omitempty"`
+		Name     string          `json:"name,omitempty"`
+		Resource json.RawMessage `json:"resource,omitempty"`
+	}
+
+	// Resource is a concrete resource a server lists.
+	Resource struct {
+		URI         string `json:"uri"`
+		Name        string `json:"name"`
+		Title       string `json:"title,omitempty"`
+		Description string `json:"description,omitempty"`
+		MimeType    string `json:"mimeType,omitempty"`
+	}
+
+	// ResourceTemplate describes a family of resources by an RFC 6570 URI template.
+	ResourceTemplate struct {
+		URITemplate string `json:"uriTemplate"`
+		Name        string `json:"name"`
+		Title       string `json:"title,omitempty"`
+		Description string `json:"description,omitempty"`
+		MimeType    string `json:"mimeType,omitempty"`
+	}
+
+	// ListResourcesParams are the params of resources/list and resources/templates/list.
+	ListResourcesParams struct {
+		Cursor string `json:"cursor,omitempty"`
+	}
+
+	// ListResourcesResult is one page of resources/list.
+	ListResourcesResult struct {
+		Resources  []Resource `json:"resources"`
+		NextCursor string     `json:"nextCursor,omitempty"`
+	}
+
+	// ListResourceTemplatesResult is one page of resources/templates/list.
+	ListResourceTemplatesResult struct {
+		ResourceTemplates []ResourceTemplate `json:"resourceTemplates"`
+		NextCursor        string             `json:"nextCursor,omitempty"`
+	}
+
+	// ReadResourceParams are the params of resources/read.
+	ReadResourceParams struct {
+		URI string `json:"uri"`
+	}
+
+	// ReadResourceResult is the result of resources/read.
+	ReadResourceResult struct {
+		Contents []ResourceContents `json:"contents"`
+	}
+
+	// ResourceContents is the text (or base64 Blob) of a resource.
+	ResourceContents struct {
+		URI      string `json:"uri"`
+		MimeType string `json:"mimeType,omitempty"`
+		Text     string `json:"text,omitempty"`
+		Blob     string `json:"blob,omitempty"`
+	}
+)
+
+// Error implements error.
+func (e *Error) Error() string {
+	return fmt.Sprintf("mcp: error %d: %s", e.Code, e.Message)
+}
+
+// TextContent returns a text content item.
+func TextContent(text string) Content {
+	return Content{Type: "text", Text: text}
+}
+
+// Text joins the text items of the result.
+func (r *CallToolResult) Text() string {
+	text := ""
+	for _, c := range r.Content {
+		if c.Type != "text" {
+			continue
+		}
+		if text != "" {
+			text += "\n"
+		}
+		text += c.Text
+	}
+	return text
+}
+
+// IsResponse reports whether m answers a request.
+func (m *Message) IsResponse() bool {
+	return m.Method == "" && len(m.ID) > 0
+}
+
+// IsNotification reports whether m is a notification, which gets no response.
+func (m *Message) IsNotification() bool {
+	return m.Method != "" && len(m.ID) == 0
+}
+
+// NewRequest builds a request with a numeric id.
+func NewRequest(id int64, method string, params any) (*Message, error) {
+	msg, err := NewNotification(method, params)
+	if err != nil {
+		return nil, err
+	}
+	msg.ID = json.RawMessage(strconv.FormatInt(id, 10))
+	return msg, nil
+}
+
+// NewNotification builds a notification.
+func NewNotification(method string, params any) (*Message, error) {
+	msg := &Message{JSONRPC: jsonRPCVersion, Method: method}
+	if params != nil {
+		raw, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("mcp: encode %s params: %w", method, err)
+		}
+		msg.Params = raw
+	}
+	return msg, nil
+}
+
+// NewResult builds the response to the request with id.
+func NewResult(id json.RawMessage, result any) (*Message, error) {
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("mcp: encode result: %w", err)
+	}
+	return &Message{JSONRPC: jsonRPCVersion, ID: id, Result: raw}, nil
+}
+
+// NewErrorResponse builds an error response to the request with id.
+func NewErrorResponse(id json.RawMessage, code int, message string) *Message {
+	return &Message{JSONRPC: jsonRPCVersion, ID: id, Error: &Error{Code: code, Message: message}}
+}
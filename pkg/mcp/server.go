@@ -0,0 +1,179 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// ServerHandler answers the tools and resources methods of a Server. An *Error it returns is sent
+// to the client as it is; any other error becomes a generic internal error, so it should be
+// logged by the handler.
+type ServerHandler interface {
+	// ListTools returns the tools offered to the caller.
+	ListTools(ctx context.Context) ([]Tool, error)
+	// CallTool runs a tool. A failure of the tool itself is a result with IsError set.
+	CallTool(ctx context.Context, name string, args map[string]any) (*CallToolResult, error)
+	// ListResources returns the concrete resources offered to the caller.
+	ListResources(ctx context.Context) ([]Resource, error)
+	// ListResourceTemplates returns the resource templates offered to the caller.
+	ListResourceTemplates(ctx context.Context) ([]ResourceTemplate, error)
+	// ReadResource reads the resource at uri.
+	ReadResource(ctx context.Context, uri string) (*ReadResourceResult, error)
+}
+
+// Server answers MCP requests with a ServerHandler. Over HTTP it is stateless: it assigns no
+// session, so every POST stands on its own and GET streams are not offered.
+type Server struct {
+	info         Implementation
+	instructions string
+	handler      ServerHandler
+}
+
+// NewServer returns a server that introduces itself as info, with optional instructions for the
+// client's model.
+func NewServer(info Implementation, instructions string, handler ServerHandler) *Server {
+	return &Server{info: info, instructions: instructions, handler: handler}
+}
+
+// Handle answers one message, or returns nil for a notification or a response.
+func (s *Server) Handle(ctx context.Context, msg *Message) *Message {
+	if msg.JSONRPC != jsonRPCVersion {
+		return NewErrorResponse(msg.ID, CodeInvalidRequest, "jsonrpc must be "+jsonRPCVersion)
+	}
+	if msg.IsNotification() || msg.Method == "" {
+		return nil
+	}
+
+	switch msg.Method {
+	case MethodInitialize:
+		var params InitializeParams
+		if err := decodeParams(msg, &params); err != nil {
+			return err
+		}
+		version := params.ProtocolVersion
+		if _, ok := supportedProtocolVersions[version]; !ok {
+			version = ProtocolVersion
+		}
+		return serverResult(msg, InitializeResult{
+			ProtocolVersion: version,
+			Capabilities:    map[string]any{"tools": map[string]any{}, "resources": map[string]any{}},
+			ServerInfo:      s.info,
+			Instructions:    s.instructions,
+		}, nil)
+	case MethodPing:
+		return serverResult(msg, map[string]any{}, nil)
+	case MethodToolsList:
+		tools, err := s.handler.ListTools(ctx)
+		return serverResult(msg, ListToolsResult{Tools: nonNil(tools)}, err)
+	case MethodToolsCall:
+		var params CallToolParams
+		if err := decodeParams(msg, &params); err != nil {
+			return err
+		}
+		res, err := s.handler.CallTool(ctx, params.Name, params.Arguments)
+		return serverResult(msg, res, err)
+	case MethodResourcesList:
+		resources, err := s.handler.ListResources(ctx)
+		return serverResult(msg, ListResourcesResult{Resources: nonNil(resources)}, err)
+	case MethodResourceTemplatesList:
+		templates, err := s.handler.ListResourceTemplates(ctx)
+		return serverResult(msg, ListResourceTemplatesResult{ResourceTemplates: nonNil(templates)}, err)
+	case MethodResourcesRead:
+		var params ReadResourceParams
+		if err := decodeParams(msg, &params); err != nil {
+			return err
+		}
+		res, err := s.handler.ReadResource(ctx, params.URI)
+		return serverResult(msg, res, err)
+	default:
+		return NewErrorResponse(msg.ID, CodeMethodNotFound, "unknown method "+msg.Method)
+	}
+}
+
+// ServeHTTP serves the streamable HTTP transport: one JSON-RPC message per POST, answered with a
+// JSON body, or 202 Accepted for a notification.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	if version := r.Header.Get(HeaderProtocolVersion); version != "" {
+		if _, ok := supportedProtocolVersions[version]; !ok {
+			http.Error(w, "unsupported "+HeaderProtocolVersion+" "+version, http.StatusBadRequest)
+			return
+		}
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxMessageSize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "message too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(body), []byte("[")) {
+		writeMessage(w, http.StatusBadRequest, NewErrorResponse(nil, CodeInvalidRequest, "batches are not supported"))
+		return
+	}
+	var msg Message
+	if err := json.Unmarshal(body, &msg); err != nil {
+		writeMessage(w, http.StatusBadRequest, NewErrorResponse(nil, CodeParseError, err.Error()))
+		return
+	}
+
+	resp := s.Handle(r.Context(), &msg)
+	if resp == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	writeMessage(w, http.StatusOK, resp)
+}
+
+func writeMessage(w http.ResponseWriter, status int, msg *Message) {
+	w.Header().Set("Content-Type", contentTypeJSON)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(msg)
+}
+
+// decodeParams decodes the params of msg into v, or returns the invalid params response.
+func decodeParams(msg *Message, v any) *Message {
+	if len(msg.Params) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(msg.Params, v); err != nil {
+		return NewErrorResponse(msg.ID, CodeInvalidParams, err.Error())
+	}
+	return nil
+}
+
+// serverResult answers msg with result, or with err when the handler failed.
+func serverResult(msg *Message, result any, err error) *Message {
+	if err != nil {
+		var rpcErr *Error
+		if errors.As(err, &rpcErr) {
+			return &Message{JSONRPC: jsonRPCVersion, ID: msg.ID, Error: rpcErr}
+		}
+		return NewErrorResponse(msg.ID, CodeInternalError, "internal error")
+	}
+	resp, err := NewResult(msg.ID, result)
+	if err != nil {
+		return NewErrorResponse(msg.ID, CodeInternalError, err.Error())
+	}
+	return resp
+}
+
+// nonNil keeps empty lists encoded as [] rather than null.
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
@@ -0,0 +1,123 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// TransportType selects how FUSE reaches an MCP server.
+type TransportType string
+
+const (
+	// TransportStdio runs the server as a subprocess of the engine and talks over its stdin and
+	// stdout.
+	TransportStdio TransportType = "stdio"
+	// TransportHTTP talks to a remote server over streamable HTTP.
+	TransportHTTP TransportType = "http"
+)
+
+const (
+	// DefaultTimeout bounds a tool call, and connecting and listing tools, when a server sets none.
+	DefaultTimeout = 30 * time.Second
+	// MaxTimeout is the largest timeout a server may set.
+	MaxTimeout = 10 * time.Minute
+)
+
+// serverIDPattern keeps server ids short and provider-safe: they become part of the tool names
+// offered to models (mcp__<id>__<tool>), which most providers restrict to [A-Za-z0-9_-]{1,64}. An
+// id cannot contain "__" or "/", which separate it from the tool name.
+var serverIDPattern = regexp.MustCompile(`^[a-z0-9](?:[a-z0-9]|-[a-z0-9])*$`)
+
+const serverIDMaxLength = 32
+
+// ErrInvalidServerConfig is returned when a server registration fails validation.
+var ErrInvalidServerConfig = errors.New("invalid MCP server")
+
+// ServerConfig is an MCP server registered in a namespace. Env and Headers values may hold
+// {{secret:NAME}} and {{credential:ID.FIELD}} references, resolved from the secret store when the
+// server is connected; the registration itself never holds secret values.
+type ServerConfig struct {
+	ID          string        `json:"id"`
+	Description string        `json:"description,omitempty"`
+	Transport   TransportType `json:"transport"`
+	// Command and Args start a stdio server. Env is its whole environment besides PATH and HOME:
+	// the engine's own variables are not passed on.
+	Command string            `json:"command,omitempty"`
+	Args    []string          `json:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+	// URL and Headers reach an http server.
+	URL     string            `json:"url,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	// Timeout bounds each tool call; zero means DefaultTimeout.
+	Timeout time.Duration `json:"timeout,omitempty"`
+	// Tools lists the tool names offered to agents; empty offers every tool the server lists.
+	Tools []string `json:"tools,omitempty"`
+}
+
+// Validate checks the id, the transport and the fields it needs.
+func (c *ServerConfig) Validate() error {
+	if len(c.ID) > serverIDMaxLength || !serverIDPattern.MatchString(c.ID) {
+		return fmt.Errorf("%w: id %q must be at most %d lowercase alphanumerics separated by single dashes", ErrInvalidServerConfig, c.ID, serverIDMaxLength)
+	}
+	switch c.Transport {
+	case TransportStdio:
+		if c.Command == "" {
+			return fmt.Errorf("%w: a stdio server needs a command", ErrInvalidServerConfig)
+		}
+		if c.URL != "" || len(c.Headers) > 0 {
+			return fmt.Errorf("%w: url and headers only apply to http servers", ErrInvalidServerConfig)
+		}
+	case TransportHTTP:
+		u, err := url.Parse(c.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: an http server needs an http(s) url", ErrInvalidServerConfig)
+		}
+		if c.Command != "" || len(c.Args) > 0 || len(c.Env) > 0 {
+			return fmt.Errorf("%w: command, args and env only apply to stdio servers", ErrInvalidServerConfig)
+		}
+	default:
+		return fmt.Errorf("%w: transport %q must be %q or %q", ErrInvalidServerConfig, c.Transport, TransportStdio, TransportHTTP)
+	}
+	if c.Timeout < 0 || c.Timeout > MaxTimeout {
+		return fmt.Errorf("%w: timeout must be between 0 and %s", ErrInvalidServerConfig, MaxTimeout)
+	}
+	return nil
+}
+
+// CallTimeout returns the server's timeout, or DefaultTimeout when it sets none.
+func (c *ServerConfig) CallTimeout() time.Duration {
+	if c.Timeout <= 0 {
+		return DefaultTimeout
+	}
+	return c.Timeout
+}
+
+// OffersTool reports whether the tool named name may be offered to agents.
+func (c *ServerConfig) OffersTool(name string) bool {
+	return len(c.Tools) == 0 || slices.Contains(c.Tools, name)
+}
+
+// FunctionIDPrefix starts the function id of every MCP tool offered to agents.
+const FunctionIDPrefix = "mcp/"
+
+// ToolFunctionID returns the function id an agent knows the tool of a server by, e.g.
+// "mcp/wiki/search"; allowedTools lists it like any other function id.
+func ToolFunctionID(serverID, tool string) string {
+	return FunctionIDPrefix + serverID + "/" + tool
+}
+
+// ParseToolFunctionID splits a function id made by ToolFunctionID. Tool names may contain "/";
+// server ids cannot.
+func ParseToolFunctionID(functionID string) (serverID, tool string, ok bool) {
+	rest, ok := strings.CutPrefix(functionID, FunctionIDPrefix)
+	if !ok {
+		return "", "", false
+	}
+	serverID, tool, ok = strings.Cut(rest, "/")
+	return serverID, tool, ok && serverID != "" && tool != ""
+}
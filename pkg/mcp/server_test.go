@@ -0,0 +1,131 @@
+package mcp_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/open-source-cloud/fuse/pkg/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeHandler struct{}
+
+func (fakeHandler) ListTools(context.Context) ([]mcp.Tool, error) {
+	return []mcp.Tool{searchTool}, nil
+}
+
+func (fakeHandler) CallTool(_ context.Context, name string, args map[string]any) (*mcp.CallToolResult, error) {
+	if name != searchTool.Name {
+		return nil, &mcp.Error{Code: mcp.CodeInvalidParams, Message: "unknown tool " + name}
+	}
+	return &mcp.CallToolResult{Content: []mcp.Content{mcp.TextContent("found " + args["query"].(string))}}, nil
+}
+
+func (fakeHandler) ListResources(context.Context) ([]mcp.Resource, error) {
+	return nil, nil
+}
+
+func (fakeHandler) ListResourceTemplates(context.Context) ([]mcp.ResourceTemplate, error) {
+	return []mcp.ResourceTemplate{{URITemplate: "wiki://pages/{id}", Name: "page"}}, nil
+}
+
+func (fakeHandler) ReadResource(_ context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	if uri != "wiki://pages/home" {
+		return nil, assert.AnError
+	}
+	return &mcp.ReadResourceResult{Contents: []mcp.ResourceContents{{URI: uri, MimeType: "text/plain", Text: "welcome"}}}, nil
+}
+
+func TestServer_HTTP(t *testing.T) {
+	httpServer := httptest.NewServer(mcp.NewServer(mcp.Implementation{Name: "wiki", Version: "1.0.0"}, "Search the wiki.", fakeHandler{}))
+	defer httpServer.Close()
+	ctx := context.Background()
+
+	client, err := mcp.Connect(ctx, mcp.ServerConfig{ID: "wiki", Transport: mcp.TransportHTTP, URL: httpServer.URL}, clientInfo)
+	require.NoError(t, err)
+	defer func() { _ = client.Close() }()
+	assert.Equal(t, "wiki", client.Server().ServerInfo.Name)
+	assert.Equal(t, "Search the wiki.", client.Server().Instructions)
+	assert.Contains(t, client.Server().Capabilities, "resources")
+
+	tools, err := client.ListTools(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"search"}, toolNames(tools))
+
+	res, err := client.CallTool(ctx, "search", map[string]any{"query": "fuse"})
+	require.NoError(t, err)
+	assert.Equal(t, "found fuse", res.Text())
+
+	_, err = client.CallTool(ctx, "delete", nil)
+	var rpcErr *mcp.Error
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, mcp.CodeInvalidParams, rpcErr.Code)
+
+	page, err := client.ReadResource(ctx, "wiki://pages/home")
+	require.NoError(t, err)
+	assert.Equal(t, "welcome", page.Contents[0].Text)
+
+	_, err = client.ReadResource(ctx, "wiki://pages/secret")
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, mcp.CodeInternalError, rpcErr.Code)
+	assert.NotContains(t, rpcErr.Message, assert.AnError.Error(), "handler errors are not leaked")
+}
+
+func TestServer_Handle(t *testing.T) {
+	server := mcp.NewServer(mcp.Implementation{Name: "wiki"}, "", fakeHandler{})
+	ctx := context.Background()
+
+	req, err := mcp.NewRequest(1, mcp.MethodInitialize, mcp.InitializeParams{ProtocolVersion: "2024-11-05"})
+	require.NoError(t, err)
+	var initialized mcp.InitializeResult
+	require.NoError(t, json.Unmarshal(server.Handle(ctx, req).Result, &initialized))
+	assert.Equal(t, "2024-11-05", initialized.ProtocolVersion, "a supported client version is kept")
+
+	req, err = mcp.NewRequest(2, mcp.MethodInitialize, mcp.InitializeParams{ProtocolVersion: "1999-01-01"})
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(server.Handle(ctx, req).Result, &initialized))
+	assert.Equal(t, mcp.ProtocolVersion, initialized.ProtocolVersion)
+
+	req, err = mcp.NewRequest(3, mcp.MethodResourcesList, nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"resources":[]}`, string(server.Handle(ctx, req).Result))
+
+	req, err = mcp.NewRequest(4, "prompts/list", nil)
+	require.NoError(t, err)
+	assert.Equal(t, mcp.CodeMethodNotFound, server.Handle(ctx, req).Error.Code)
+
+	note, err := mcp.NewNotification(mcp.MethodInitialized, nil)
+	require.NoError(t, err)
+	assert.Nil(t, server.Handle(ctx, note))
+}
+
+func TestServer_HTTPErrors(t *testing.T) {
+	server := mcp.NewServer(mcp.Implementation{Name: "wiki"}, "", fakeHandler{})
+	cases := map[string]struct {
+		method, body, version string
+		want                  int
+	}{
+		"get":          {method: http.MethodGet, want: http.StatusMethodNotAllowed},
+		"batch":        {method: http.MethodPost, body: `[{"jsonrpc":"2.0","id":1,"method":"ping"}]`, want: http.StatusBadRequest},
+		"malformed":    {method: http.MethodPost, body: `{`, want: http.StatusBadRequest},
+		"version":      {method: http.MethodPost, body: `{"jsonrpc":"2.0","id":1,"method":"ping"}`, version: "1999-01-01", want: http.StatusBadRequest},
+		"notification": {method: http.MethodPost, body: `{"jsonrpc":"2.0","method":"notifications/initialized"}`, want: http.StatusAccepted},
+		"ping":         {method: http.MethodPost, body: `{"jsonrpc":"2.0","id":1,"method":"ping"}`, version: mcp.ProtocolVersion, want: http.StatusOK},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "/mcp", strings.NewReader(tc.body))
+			if tc.version != "" {
+				req.Header.Set(mcp.HeaderProtocolVersion, tc.version)
+			}
+			rec := httptest.NewRecorder()
+			server.ServeHTTP(rec, req)
+			assert.Equal(t, tc.want, rec.Code)
+		})
+	}
+}
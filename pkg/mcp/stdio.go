@@ -0,0 +1,198 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+const (
+	// maxMessageSize bounds one message read from a stdio server or an event stream.
+	maxMessageSize = 32 << 20
+	// stdioStopGrace is how long a stdio server has to exit after its stdin is closed before it
+	// is killed.
+	stdioStopGrace = 2 * time.Second
+)
+
+// StdioTransport talks to a server running as a subprocess: newline-delimited JSON-RPC messages
+// on its stdin and stdout. Pings from the server are answered; its other requests are refused and
+// its notifications ignored.
+type StdioTransport struct {
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	pending map[string]chan *Message
+	err     error
+
+	done      chan struct{} // closed when the server's stdout ends
+	exited    chan struct{} // closed once the process has been waited for
+	closeOnce sync.Once
+}
+
+// NewStdioTransport starts cmd and returns a transport over its stdin and stdout. The caller sets
+// cmd's environment and stderr; stderr is discarded when unset.
+func NewStdioTransport(cmd *exec.Cmd) (*StdioTransport, error) {
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("mcp: stdin of %s: %w", cmd.Path, err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("mcp: stdout of %s: %w", cmd.Path, err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("mcp: start %s: %w", cmd.Path, err)
+	}
+
+	t := &StdioTransport{
+		cmd:     cmd,
+		stdin:   stdin,
+		pending: make(map[string]chan *Message),
+		done:    make(chan struct{}),
+		exited:  make(chan struct{}),
+	}
+	go t.readLoop(stdout)
+	return t, nil
+}
+
+// RoundTrip writes req and waits for the response with its id. When ctx ends first the server is
+// told the request was cancelled.
+func (t *StdioTransport) RoundTrip(ctx context.Context, req *Message) (*Message, error) {
+	key := string(req.ID)
+	responses := make(chan *Message, 1)
+	t.mu.Lock()
+	if t.err != nil {
+		t.mu.Unlock()
+		return nil, t.err
+	}
+	t.pending[key] = responses
+	t.mu.Unlock()
+	defer func() {
+		t.mu.Lock()
+		delete(t.pending, key)
+		t.mu.Unlock()
+	}()
+
+	if err := t.write(req); err != nil {
+		return nil, err
+	}
+	select {
+	case resp := <-responses:
+		return resp, nil
+	case <-t.done:
+		return nil, t.closedErr()
+	case <-ctx.Done():
+		if cancel, err := NewNotification(MethodCancelled, map[string]any{"requestId": req.ID, "reason": ctx.Err().Error()}); err == nil {
+			_ = t.write(cancel)
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// Notify writes a notification.
+func (t *StdioTransport) Notify(_ context.Context, msg *Message) error {
+	return t.write(msg)
+}
+
+// Close closes the server's stdin, which asks it to exit, and kills it if it has not exited
+// after a grace period.
+func (t *StdioTransport) Close() error {
+	t.closeOnce.Do(func() {
+		_ = t.stdin.Close()
+		select {
+		case <-t.exited:
+		case <-time.After(stdioStopGrace):
+			_ = t.cmd.Process.Kill()
+			<-t.exited
+		}
+	})
+	return nil
+}
+
+// write sends one message followed by a newline.
+func (t *StdioTransport) write(msg *Message) error {
+	raw, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("mcp: encode %s: %w", msg.Method, err)
+	}
+	select {
+	case <-t.done:
+		return t.closedErr()
+	default:
+	}
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	if _, err := t.stdin.Write(append(raw, '\n')); err != nil {
+		return fmt.Errorf("%w: %w", ErrClosed, err)
+	}
+	return nil
+}
+
+// readLoop dispatches the server's messages until its stdout ends, then reaps the process. Lines
+// that are not JSON-RPC messages are skipped.
+func (t *StdioTransport) readLoop(stdout io.Reader) {
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxMessageSize)
+	for scanner.Scan() {
+		var msg Message
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			continue
+		}
+		switch {
+		case msg.IsResponse():
+			t.mu.Lock()
+			responses, ok := t.pending[string(msg.ID)]
+			t.mu.Unlock()
+			if ok {
+				responses <- &msg
+			}
+		case !msg.IsNotification() && msg.Method != "":
+			t.answer(&msg)
+		}
+	}
+
+	err := scanner.Err()
+	if err == nil {
+		err = io.EOF
+	}
+	t.mu.Lock()
+	t.err = fmt.Errorf("%w: %w", ErrClosed, err)
+	t.mu.Unlock()
+	close(t.done)
+
+	if waitErr := t.cmd.Wait(); waitErr != nil {
+		t.mu.Lock()
+		t.err = fmt.Errorf("%w: server exited: %w", ErrClosed, waitErr)
+		t.mu.Unlock()
+	}
+	close(t.exited)
+}
+
+// answer responds to a request from the server: pings succeed, anything else is not supported.
+func (t *StdioTransport) answer(req *Message) {
+	resp := NewErrorResponse(req.ID, CodeMethodNotFound, "method not supported by client: "+req.Method)
+	if req.Method == MethodPing {
+		if ok, err := NewResult(req.ID, map[string]any{}); err == nil {
+			resp = ok
+		}
+	}
+	_ = t.write(resp)
+}
+
+// closedErr returns why the connection ended.
+func (t *StdioTransport) closedErr() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.err == nil {
+		return ErrClosed
+	}
+	return t.err
+}
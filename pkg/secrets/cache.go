@@ -0,0 +1,120 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// refreshAheadFactor is the share of the TTL after which a read also refreshes its entry in the
+	// background, so secrets in use are renewed before they expire.
+	refreshAheadFactor = 0.8
+	// cacheRefreshTimeout bounds a background refresh, which no caller waits on.
+	cacheRefreshTimeout = 30 * time.Second
+)
+
+// CachingSecretStore wraps a read-only backend with a TTL cache. A read past refreshAheadFactor of
+// the TTL is served from the cache while the entry is refreshed in the background; an entry that
+// expired anyway is refreshed on its next read. When a refresh fails for any reason other than the
+// secret being gone, the last known value is served so a backend outage does not fail running
+// workflows.
+type CachingSecretStore struct {
+	inner SecretStore
+	ttl   time.Duration
+	now   func() time.Time
+
+	mu      sync.Mutex
+	entries map[cacheKey]cacheEntry
+	// group shares one backend read between the reads and the background refresh of an entry.
+	group singleflight.Group
+}
+
+type cacheKey struct {
+	namespace   string
+	environment string
+	name        string
+}
+
+type cacheEntry struct {
+	value     SecretValue
+	fetchedAt time.Time
+}
+
+// compile-time assertion.
+var _ SecretStore = (*CachingSecretStore)(nil)
+
+// NewCachingSecretStore caches inner's values for ttl.
+func NewCachingSecretStore(inner SecretStore, ttl time.Duration) *CachingSecretStore {
+	return &CachingSecretStore{
+		inner:   inner,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[cacheKey]cacheEntry),
+	}
+}
+
+// Resolve returns the cached value while fresh, refreshing it ahead of its expiry, and otherwise
+// reads through to the backend.
+func (c *CachingSecretStore) Resolve(ctx context.Context, scope Scope, name string) (SecretValue, error) {
+	key := cacheKey{namespace: scope.NamespaceOrDefault(), environment: scope.Environment, name: name}
+	c.mu.Lock()
+	entry, cached := c.entries[key]
+	c.mu.Unlock()
+	if cached {
+		age := c.now().Sub(entry.fetchedAt)
+		if age < c.ttl {
+			if age >= time.Duration(float64(c.ttl)*refreshAheadFactor) {
+				c.refreshAhead(ctx, scope, key)
+			}
+			return entry.value, nil
+		}
+	}
+
+	value, err, _ := c.group.Do(key.String(), func() (any, error) {
+		return c.load(ctx, scope, key)
+	})
+	switch {
+	case err == nil:
+		return value.(SecretValue), nil
+	case errors.Is(err, ErrSecretNotFound):
+		return SecretValue{}, err
+	case cached:
+		return entry.value, nil
+	default:
+		return SecretValue{}, err
+	}
+}
+
+// refreshAhead reloads the entry in the background unless a read of it is already under way. The
+// refresh keeps the caller's context values but not its cancellation.
+func (c *CachingSecretStore) refreshAhead(ctx context.Context, scope Scope, key cacheKey) {
+	ctx = context.WithoutCancel(ctx)
+	c.group.DoChan(key.String(), func() (any, error) {
+		refreshCtx, cancel := context.WithTimeout(ctx, cacheRefreshTimeout)
+		defer cancel()
+		return c.load(refreshCtx, scope, key)
+	})
+}
+
+// load reads the entry from the backend and records the outcome: a value is cached, a secret that
+// is gone is forgotten, and any other failure leaves the last known value in place.
+func (c *CachingSecretStore) load(ctx context.Context, scope Scope, key cacheKey) (SecretValue, error) {
+	value, err := c.inner.Resolve(ctx, scope, key.name)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch {
+	case err == nil:
+		c.entries[key] = cacheEntry{value: value, fetchedAt: c.now()}
+	case errors.Is(err, ErrSecretNotFound):
+		delete(c.entries, key)
+	}
+	return value, err
+}
+
+func (k cacheKey) String() string {
+	return k.namespace + "\x00" + k.environment + "\x00" + k.name
+}
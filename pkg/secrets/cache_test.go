@@ -0,0 +1,93 @@
+package secrets_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/open-source-cloud/fuse/pkg/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyStore counts reads and fails them while down is set.
+type flakyStore struct {
+	inner secrets.SecretStore
+	reads atomic.Int32
+	down  atomic.Bool
+}
+
+func (s *flakyStore) Resolve(ctx context.Context, scope secrets.Scope, name string) (secrets.SecretValue, error) {
+	s.reads.Add(1)
+	if s.down.Load() {
+		return secrets.SecretValue{}, errors.New("backend unavailable")
+	}
+	return s.inner.Resolve(ctx, scope, name)
+}
+
+func TestCachingSecretStore(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	scope := secrets.Scope{Environment: "prod"}
+	mem := secrets.NewMemorySecretStore()
+	require.NoError(t, mem.Set(ctx, scope, "api-key", "v1"))
+	backend := &flakyStore{inner: mem}
+	cache := secrets.NewCachingSecretStore(backend, 20*time.Millisecond)
+
+	for range 3 {
+		v, err := cache.Resolve(ctx, scope, "api-key")
+		require.NoError(t, err)
+		assert.Equal(t, "v1", v.Reveal())
+	}
+	assert.Equal(t, int32(1), backend.reads.Load(), "fresh entries are served from the cache")
+
+	require.NoError(t, mem.Set(ctx, scope, "api-key", "v2"))
+	time.Sleep(30 * time.Millisecond)
+	v, err := cache.Resolve(ctx, scope, "api-key")
+	require.NoError(t, err)
+	assert.Equal(t, "v2", v.Reveal(), "expired entries are refreshed")
+
+	backend.down.Store(true)
+	time.Sleep(30 * time.Millisecond)
+	v, err = cache.Resolve(ctx, scope, "api-key")
+	require.NoError(t, err, "a failed refresh serves the last known value")
+	assert.Equal(t, "v2", v.Reveal())
+
+	_, err = cache.Resolve(ctx, scope, "unknown")
+	require.Error(t, err, "nothing to fall back to")
+
+	backend.down.Store(false)
+	require.NoError(t, mem.Delete(ctx, scope, "api-key"))
+	time.Sleep(30 * time.Millisecond)
+	_, err = cache.Resolve(ctx, scope, "api-key")
+	assert.ErrorIs(t, err, secrets.ErrSecretNotFound, "deleted secrets are not served stale")
+}
+
+func TestCachingSecretStore_RefreshesAheadOfExpiry(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	scope := secrets.Scope{Environment: "prod"}
+	mem := secrets.NewMemorySecretStore()
+	require.NoError(t, mem.Set(ctx, scope, "api-key", "v1"))
+	backend := &flakyStore{inner: mem}
+	cache := secrets.NewCachingSecretStore(backend, 200*time.Millisecond)
+
+	_, err := cache.Resolve(ctx, scope, "api-key")
+	require.NoError(t, err)
+	require.NoError(t, mem.Set(ctx, scope, "api-key", "v2"))
+
+	time.Sleep(170 * time.Millisecond)
+	v, err := cache.Resolve(ctx, scope, "api-key")
+	require.NoError(t, err)
+	assert.Equal(t, "v1", v.Reveal(), "a read close to expiry is still served from the cache")
+	require.Eventually(t, func() bool { return backend.reads.Load() == 2 }, time.Second, 5*time.Millisecond,
+		"and refreshes the entry in the background")
+
+	time.Sleep(50 * time.Millisecond)
+	v, err = cache.Resolve(ctx, scope, "api-key")
+	require.NoError(t, err)
+	assert.Equal(t, "v2", v.Reveal())
+	assert.Equal(t, int32(2), backend.reads.Load(), "the first read after the original expiry does not wait for the backend")
+}
@@ -3,6 +3,7 @@ package secrets
 import (
 	"fmt"
 	"regexp"
+	"strings"
 )
 
 // credentialSecretPrefix namespaces credential field values within the SecretStore. A credential
@@ -16,6 +17,15 @@ func CredentialSecretName(id, field string) string {
 	return fmt.Sprintf("%s/%s/%s", credentialSecretPrefix, id, field)
 }
 
+// ParseCredentialSecretName splits a reserved "cred/<id>/<field>" name into its id and field.
+func ParseCredentialSecretName(name string) (id, field string, ok bool) {
+	parts := strings.Split(name, "/")
+	if len(parts) != 3 || parts[0] != credentialSecretPrefix || parts[1] == "" || parts[2] == "" {
+		return "", "", false
+	}
+	return parts[1], parts[2], true
+}
+
 // credentialRefPattern matches {{credential:ID.FIELD}} tokens. ID may contain dots; FIELD may not,
 // so the value is split on the LAST dot (ID captured greedily, FIELD as the trailing segment).
 var credentialRefPattern = regexp.MustCompile(`\{\{credential:([A-Za-z0-9_.\-]+)\.([A-Za-z0-9_\-]+)\}\}`)
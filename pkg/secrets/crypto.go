@@ -10,6 +10,9 @@ import (
 	"io"
 )
 
+// keySize is the AES-256 key size used for key-encryption keys and per-secret data keys.
+const keySize = 32
+
 // Cipher encrypts and decrypts secret values with AES-256-GCM.
 type Cipher struct {
 	aead cipher.AEAD
@@ -21,7 +24,11 @@ func NewCipherFromBase64Key(b64 string) (*Cipher, error) {
 	if err != nil {
 		return nil, fmt.Errorf("secrets: decode encryption key: %w", err)
 	}
-	if len(key) != 32 {
+	return newCipher(key)
+}
+
+func newCipher(key []byte) (*Cipher, error) {
+	if len(key) != keySize {
 		return nil, fmt.Errorf("secrets: encryption key must be 32 bytes (got %d)", len(key))
 	}
 	block, err := aes.NewCipher(key)
@@ -0,0 +1,63 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// namespacesDir holds the secrets of namespaces other than the default one. Environment names
+// cannot start with "_", so it never collides with an environment directory.
+const namespacesDir = "_namespaces"
+
+// FileSecretStore is a read-only SecretStore over a directory tree, such as Kubernetes secrets
+// mounted as volumes. A secret is the content of <dir>/<environment>/<name>, or of
+// <dir>/_namespaces/<namespace>/<environment>/<name> outside the default namespace; names with
+// "/" (e.g. credential fields) map to subdirectories. One trailing newline is ignored.
+type FileSecretStore struct {
+	dir string
+}
+
+// compile-time assertion.
+var _ SecretStore = (*FileSecretStore)(nil)
+
+// NewFileSecretStore creates a store reading secrets below dir.
+func NewFileSecretStore(dir string) *FileSecretStore {
+	return &FileSecretStore{dir: dir}
+}
+
+// Resolve reads the secret file of (namespace, environment, name).
+func (s *FileSecretStore) Resolve(_ context.Context, scope Scope, name string) (SecretValue, error) {
+	rel, err := scopedPath(scope, name)
+	if err != nil {
+		return SecretValue{}, err
+	}
+	raw, err := os.ReadFile(filepath.Join(s.dir, filepath.FromSlash(rel)))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return SecretValue{}, fmt.Errorf("%w: %q (namespace %q, environment %q)", ErrSecretNotFound, name, scope.NamespaceOrDefault(), scope.Environment)
+		}
+		return SecretValue{}, fmt.Errorf("secrets/file: read %q: %w", name, err)
+	}
+	value := strings.TrimSuffix(strings.TrimSuffix(string(raw), "\n"), "\r")
+	return NewSecretValue(value), nil
+}
+
+// scopedPath returns the slash-separated location of a secret below a backend's root:
+// <environment>/<name>, or _namespaces/<namespace>/<environment>/<name> outside the default
+// namespace. Names that would escape their scope are rejected.
+func scopedPath(scope Scope, name string) (string, error) {
+	for _, segment := range append([]string{scope.NamespaceOrDefault(), scope.Environment}, strings.Split(name, "/")...) {
+		if segment == "" || segment == "." || segment == ".." || strings.ContainsRune(segment, '\\') {
+			return "", fmt.Errorf("%w: %q (namespace %q, environment %q)", ErrSecretNotFound, name, scope.NamespaceOrDefault(), scope.Environment)
+		}
+	}
+	if ns := scope.NamespaceOrDefault(); ns != DefaultNamespace {
+		return namespacesDir + "/" + ns + "/" + scope.Environment + "/" + name, nil
+	}
+	return scope.Environment + "/" + name, nil
+}
@@ -0,0 +1,49 @@
+package secrets_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/open-source-cloud/fuse/pkg/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSecretFile(t *testing.T, dir, rel, content string) {
+	t.Helper()
+	path := filepath.Join(dir, filepath.FromSlash(rel))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func TestFileSecretStore(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeSecretFile(t, dir, "prod/api-key", "prod-key\n")
+	writeSecretFile(t, dir, "prod/cred/openai/apiKey", "sk-123")
+	writeSecretFile(t, dir, "_namespaces/billing/prod/api-key", "billing-key")
+	writeSecretFile(t, dir, "outside", "nope")
+
+	store := secrets.NewFileSecretStore(dir)
+	ctx := context.Background()
+	prod := secrets.Scope{Environment: "prod"}
+
+	v, err := store.Resolve(ctx, prod, "api-key")
+	require.NoError(t, err)
+	assert.Equal(t, "prod-key", v.Reveal(), "one trailing newline is ignored")
+
+	v, err = store.Resolve(ctx, prod, "cred/openai/apiKey")
+	require.NoError(t, err)
+	assert.Equal(t, "sk-123", v.Reveal())
+
+	v, err = store.Resolve(ctx, secrets.Scope{Namespace: "billing", Environment: "prod"}, "api-key")
+	require.NoError(t, err)
+	assert.Equal(t, "billing-key", v.Reveal())
+
+	_, err = store.Resolve(ctx, secrets.Scope{Environment: "dev"}, "api-key")
+	assert.ErrorIs(t, err, secrets.ErrSecretNotFound)
+	_, err = store.Resolve(ctx, prod, "../outside")
+	assert.ErrorIs(t, err, secrets.ErrSecretNotFound, "names cannot leave their scope")
+}
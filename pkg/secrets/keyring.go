@@ -0,0 +1,179 @@
+package secrets
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DefaultKeyID is the key ID of SECRETS_ENCRYPTION_KEY, and of every row written before key IDs
+// were stored.
+const DefaultKeyID = "default"
+
+// maxKeyIDLen matches the secrets.key_id column.
+const maxKeyIDLen = 64
+
+// ErrUnknownKeyID is returned when a ciphertext names a key that is not in the keyring.
+var ErrUnknownKeyID = errors.New("secrets: unknown encryption key id")
+
+// Keyring holds the key-encryption keys (KEKs) of the encrypted store by ID. The active key wraps
+// the data keys of new and rotated secrets; the other keys are decrypt-only, kept until
+// `fuse secrets rotate-key` has moved every row off them.
+type Keyring struct {
+	activeID string
+	keys     map[string]*Cipher
+}
+
+// Envelope is an envelope-encrypted secret: the value sealed with its own random data key (DEK),
+// and the DEK sealed with the KEK named by KeyID. An Envelope without EncryptedDEK predates
+// envelope encryption; its value is sealed directly with the KEK.
+type Envelope struct {
+	KeyID        string
+	EncryptedDEK []byte
+	Ciphertext   []byte
+}
+
+// NewKeyring builds a keyring from base64-encoded 32-byte keys by ID. activeID must be one of them.
+func NewKeyring(activeID string, b64Keys map[string]string) (*Keyring, error) {
+	if len(b64Keys) == 0 {
+		return nil, errors.New("secrets: keyring has no keys")
+	}
+	keys := make(map[string]*Cipher, len(b64Keys))
+	for id, b64 := range b64Keys {
+		if id == "" || len(id) > maxKeyIDLen || strings.ContainsAny(id, ":,") {
+			return nil, fmt.Errorf("secrets: invalid key id %q (1-%d characters, no ':' or ',')", id, maxKeyIDLen)
+		}
+		c, err := NewCipherFromBase64Key(b64)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+		keys[id] = c
+	}
+	if _, ok := keys[activeID]; !ok {
+		return nil, fmt.Errorf("%w: active key %q", ErrUnknownKeyID, activeID)
+	}
+	return &Keyring{activeID: activeID, keys: keys}, nil
+}
+
+// ParseKeyring builds the keyring from its configuration: keyringCSV lists "id:base64key" entries
+// and legacyKey, when set, is added as DefaultKeyID. activeID defaults to the first keyringCSV
+// entry, or to DefaultKeyID when the list is empty.
+func ParseKeyring(keyringCSV, activeID, legacyKey string) (*Keyring, error) {
+	keys := make(map[string]string)
+	first := ""
+	for _, entry := range strings.Split(keyringCSV, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, b64, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("secrets: keyring entry must be id:base64key (got id %q)", id)
+		}
+		if _, dup := keys[id]; dup {
+			return nil, fmt.Errorf("secrets: duplicate key id %q", id)
+		}
+		keys[id] = b64
+		if first == "" {
+			first = id
+		}
+	}
+	if legacyKey != "" {
+		if _, dup := keys[DefaultKeyID]; dup {
+			return nil, fmt.Errorf("secrets: key id %q is reserved for SECRETS_ENCRYPTION_KEY", DefaultKeyID)
+		}
+		keys[DefaultKeyID] = legacyKey
+	}
+	if activeID == "" {
+		activeID = first
+		if activeID == "" {
+			activeID = DefaultKeyID
+		}
+	}
+	return NewKeyring(activeID, keys)
+}
+
+// ActiveKeyID returns the ID of the key that wraps new data keys.
+func (k *Keyring) ActiveKeyID() string {
+	return k.activeID
+}
+
+// Seal encrypts plaintext with a fresh data key wrapped by the active key.
+func (k *Keyring) Seal(plaintext []byte) (Envelope, error) {
+	dek := make([]byte, keySize)
+	if _, err := io.ReadFull(rand.Reader, dek); err != nil {
+		return Envelope{}, fmt.Errorf("secrets: generate data key: %w", err)
+	}
+	dataCipher, err := newCipher(dek)
+	if err != nil {
+		return Envelope{}, err
+	}
+	ciphertext, err := dataCipher.Encrypt(plaintext)
+	if err != nil {
+		return Envelope{}, err
+	}
+	wrapped, err := k.keys[k.activeID].Encrypt(dek)
+	if err != nil {
+		return Envelope{}, err
+	}
+	return Envelope{KeyID: k.activeID, EncryptedDEK: wrapped, Ciphertext: ciphertext}, nil
+}
+
+// Open decrypts an envelope with the key it names.
+func (k *Keyring) Open(env Envelope) ([]byte, error) {
+	kek, err := k.key(env.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	if len(env.EncryptedDEK) == 0 {
+		return kek.Decrypt(env.Ciphertext)
+	}
+	dataCipher, err := k.unwrap(kek, env.EncryptedDEK)
+	if err != nil {
+		return nil, err
+	}
+	return dataCipher.Decrypt(env.Ciphertext)
+}
+
+// Rewrap moves an envelope to the active key. Only the data key is re-encrypted, so the value's
+// ciphertext is unchanged; an envelope that predates envelope encryption is sealed anew.
+func (k *Keyring) Rewrap(env Envelope) (Envelope, error) {
+	if len(env.EncryptedDEK) == 0 {
+		plaintext, err := k.Open(env)
+		if err != nil {
+			return Envelope{}, err
+		}
+		return k.Seal(plaintext)
+	}
+	kek, err := k.key(env.KeyID)
+	if err != nil {
+		return Envelope{}, err
+	}
+	dek, err := kek.Decrypt(env.EncryptedDEK)
+	if err != nil {
+		return Envelope{}, fmt.Errorf("secrets: unwrap data key: %w", err)
+	}
+	wrapped, err := k.keys[k.activeID].Encrypt(dek)
+	if err != nil {
+		return Envelope{}, err
+	}
+	return Envelope{KeyID: k.activeID, EncryptedDEK: wrapped, Ciphertext: env.Ciphertext}, nil
+}
+
+func (k *Keyring) key(id string) (*Cipher, error) {
+	kek, ok := k.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKeyID, id)
+	}
+	return kek, nil
+}
+
+func (k *Keyring) unwrap(kek *Cipher, encryptedDEK []byte) (*Cipher, error) {
+	dek, err := kek.Decrypt(encryptedDEK)
+	if err != nil {
+		return nil, fmt.Errorf("secrets: unwrap data key: %w", err)
+	}
+	return newCipher(dek)
+}
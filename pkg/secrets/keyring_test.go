@@ -0,0 +1,111 @@
+package secrets_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/open-source-cloud/fuse/pkg/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, 32))
+}
+
+func TestKeyring_SealOpen(t *testing.T) {
+	t.Parallel()
+	kr, err := secrets.NewKeyring("k1", map[string]string{"k1": testKey(1)})
+	require.NoError(t, err)
+
+	env, err := kr.Seal([]byte("hunter2"))
+	require.NoError(t, err)
+	assert.Equal(t, "k1", env.KeyID)
+	assert.NotEmpty(t, env.EncryptedDEK, "every secret gets its own wrapped data key")
+	assert.NotContains(t, string(env.Ciphertext), "hunter2")
+
+	pt, err := kr.Open(env)
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", string(pt))
+
+	other, err := kr.Seal([]byte("hunter2"))
+	require.NoError(t, err)
+	assert.NotEqual(t, env.EncryptedDEK, other.EncryptedDEK)
+
+	_, err = kr.Open(secrets.Envelope{KeyID: "gone", EncryptedDEK: env.EncryptedDEK, Ciphertext: env.Ciphertext})
+	assert.ErrorIs(t, err, secrets.ErrUnknownKeyID)
+}
+
+func TestKeyring_Rewrap(t *testing.T) {
+	t.Parallel()
+	old, err := secrets.NewKeyring("k1", map[string]string{"k1": testKey(1)})
+	require.NoError(t, err)
+	env, err := old.Seal([]byte("hunter2"))
+	require.NoError(t, err)
+
+	rotated, err := secrets.NewKeyring("k2", map[string]string{"k1": testKey(1), "k2": testKey(2)})
+	require.NoError(t, err)
+	pt, err := rotated.Open(env)
+	require.NoError(t, err, "decrypt-only keys still open their secrets")
+	assert.Equal(t, "hunter2", string(pt))
+
+	moved, err := rotated.Rewrap(env)
+	require.NoError(t, err)
+	assert.Equal(t, "k2", moved.KeyID)
+	assert.Equal(t, env.Ciphertext, moved.Ciphertext, "only the data key is re-encrypted")
+
+	onlyNew, err := secrets.NewKeyring("k2", map[string]string{"k2": testKey(2)})
+	require.NoError(t, err)
+	pt, err = onlyNew.Open(moved)
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", string(pt))
+}
+
+func TestKeyring_RewrapLegacy(t *testing.T) {
+	t.Parallel()
+	legacy, err := secrets.NewCipherFromBase64Key(testKey(1))
+	require.NoError(t, err)
+	ct, err := legacy.Encrypt([]byte("hunter2"))
+	require.NoError(t, err)
+	env := secrets.Envelope{KeyID: secrets.DefaultKeyID, Ciphertext: ct}
+
+	kr, err := secrets.ParseKeyring("k2:"+testKey(2), "", testKey(1))
+	require.NoError(t, err)
+	assert.Equal(t, "k2", kr.ActiveKeyID())
+
+	pt, err := kr.Open(env)
+	require.NoError(t, err, "rows sealed directly with SECRETS_ENCRYPTION_KEY stay readable")
+	assert.Equal(t, "hunter2", string(pt))
+
+	moved, err := kr.Rewrap(env)
+	require.NoError(t, err)
+	assert.Equal(t, "k2", moved.KeyID)
+	assert.NotEmpty(t, moved.EncryptedDEK)
+	pt, err = kr.Open(moved)
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", string(pt))
+}
+
+func TestParseKeyring(t *testing.T) {
+	t.Parallel()
+	kr, err := secrets.ParseKeyring("", "", testKey(1))
+	require.NoError(t, err)
+	assert.Equal(t, secrets.DefaultKeyID, kr.ActiveKeyID())
+
+	kr, err = secrets.ParseKeyring("a:"+testKey(1)+", b:"+testKey(2), "b", "")
+	require.NoError(t, err)
+	assert.Equal(t, "b", kr.ActiveKeyID())
+
+	for name, tc := range map[string]struct{ csv, active, legacy string }{
+		"no keys":           {},
+		"unknown active":    {csv: "a:" + testKey(1), active: "c"},
+		"missing separator": {csv: testKey(1)},
+		"duplicate id":      {csv: "a:" + testKey(1) + ",a:" + testKey(2)},
+		"reserved id":       {csv: "default:" + testKey(1), legacy: testKey(2)},
+		"short key":         {csv: "a:" + base64.StdEncoding.EncodeToString(make([]byte, 16))},
+	} {
+		_, err := secrets.ParseKeyring(tc.csv, tc.active, tc.legacy)
+		assert.Error(t, err, name)
+	}
+}
@@ -16,15 +16,25 @@ const envSecretPrefix = "FUSE_SECRET_"
 // MemorySecretStore is an in-memory ManagedSecretStore for dev and testing.
 type MemorySecretStore struct {
 	mu   sync.RWMutex
-	data map[string]map[string]string // environment -> name -> value
+	data map[scopeKey]map[string]string // (namespace, environment) -> name -> value
+}
+
+type scopeKey struct {
+	namespace   string
+	environment string
+}
+
+func keyOf(scope Scope) scopeKey {
+	return scopeKey{namespace: scope.NamespaceOrDefault(), environment: scope.Environment}
 }
 
 // NewMemorySecretStore creates an empty store.
 func NewMemorySecretStore() *MemorySecretStore {
-	return &MemorySecretStore{data: make(map[string]map[string]string)}
+	return &MemorySecretStore{data: make(map[scopeKey]map[string]string)}
 }
 
-// NewMemorySecretStoreFromEnv seeds from FUSE_SECRET_* env vars into defaultEnv.
+// NewMemorySecretStoreFromEnv seeds from FUSE_SECRET_* env vars into defaultEnv of the
+// default namespace.
 func NewMemorySecretStoreFromEnv(defaultEnv string) *MemorySecretStore {
 	s := NewMemorySecretStore()
 	for _, kv := range os.Environ() {
@@ -41,35 +51,37 @@ func NewMemorySecretStoreFromEnv(defaultEnv string) *MemorySecretStore {
 	return s
 }
 
-// Resolve returns the secret for (environment, name) or ErrSecretNotFound.
+// Resolve returns the secret for (namespace, environment, name) or ErrSecretNotFound.
 func (s *MemorySecretStore) Resolve(_ context.Context, scope Scope, name string) (SecretValue, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	if env, ok := s.data[scope.Environment]; ok {
+	if env, ok := s.data[keyOf(scope)]; ok {
 		if v, ok := env[name]; ok {
 			return NewSecretValue(v), nil
 		}
 	}
-	return SecretValue{}, fmt.Errorf("%w: %q (environment %q)", ErrSecretNotFound, name, scope.Environment)
+	return SecretValue{}, fmt.Errorf("%w: %q (namespace %q, environment %q)", ErrSecretNotFound, name, scope.NamespaceOrDefault(), scope.Environment)
 }
 
 // Set stores (or replaces) a secret value.
 func (s *MemorySecretStore) Set(_ context.Context, scope Scope, name, value string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	if s.data[scope.Environment] == nil {
-		s.data[scope.Environment] = make(map[string]string)
+	key := keyOf(scope)
+	if s.data[key] == nil {
+		s.data[key] = make(map[string]string)
 	}
-	s.data[scope.Environment][name] = value
+	s.data[key][name] = value
 	return nil
 }
 
-// List returns the secret names in an environment, sorted.
-func (s *MemorySecretStore) List(_ context.Context, environment string) ([]string, error) {
+// List returns the secret names in a namespace and environment, sorted.
+func (s *MemorySecretStore) List(_ context.Context, scope Scope) ([]string, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	names := make([]string, 0, len(s.data[environment]))
-	for k := range s.data[environment] {
+	env := s.data[keyOf(scope)]
+	names := make([]string, 0, len(env))
+	for k := range env {
 		names = append(names, k)
 	}
 	sort.Strings(names)
@@ -80,7 +92,7 @@ func (s *MemorySecretStore) List(_ context.Context, environment string) ([]strin
 func (s *MemorySecretStore) Delete(_ context.Context, scope Scope, name string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	if env, ok := s.data[scope.Environment]; ok {
+	if env, ok := s.data[keyOf(scope)]; ok {
 		delete(env, name)
 	}
 	return nil
@@ -0,0 +1,320 @@
+package secrets
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// OAuth2 credential types. Their accessToken field is not stored: OAuth2SecretStore computes it on
+// resolution from the other fields.
+const (
+	CredentialTypeOAuth2ClientCredentials = "oauth2_client_credentials"
+	CredentialTypeOAuth2RefreshToken      = "oauth2_refresh_token"
+)
+
+// OAuth2 credential field names.
+const (
+	OAuth2FieldTokenURL     = "tokenUrl"
+	OAuth2FieldClientID     = "clientId"
+	OAuth2FieldClientSecret = "clientSecret"
+	OAuth2FieldRefreshToken = "refreshToken"
+	// OAuth2FieldScopes is an optional space-separated scope list.
+	OAuth2FieldScopes = "scopes"
+	// OAuth2FieldAudience is optional and sent as the non-standard "audience" parameter some
+	// providers (e.g. Auth0) require.
+	OAuth2FieldAudience = "audience"
+	// OAuth2FieldAuthStyle selects how the client authenticates: "basic" (HTTP Basic, the
+	// default) or "body" (client_id/client_secret form parameters).
+	OAuth2FieldAuthStyle = "authStyle"
+	// OAuth2FieldAccessToken is the computed field.
+	OAuth2FieldAccessToken = "accessToken"
+)
+
+// oauth2DefaultLifetime caches tokens whose response carries no expires_in.
+const oauth2DefaultLifetime = 5 * time.Minute
+
+// oauth2FetchTimeout bounds a token request, which runs apart from the resolutions waiting on it.
+const oauth2FetchTimeout = 30 * time.Second
+
+// OAuth2RequiredFields returns the fields a credential of credType must have, and whether
+// credType is an OAuth2 type at all.
+func OAuth2RequiredFields(credType string) ([]string, bool) {
+	switch credType {
+	case CredentialTypeOAuth2ClientCredentials:
+		return []string{OAuth2FieldTokenURL, OAuth2FieldClientID, OAuth2FieldClientSecret}, true
+	case CredentialTypeOAuth2RefreshToken:
+		return []string{OAuth2FieldTokenURL, OAuth2FieldClientID, OAuth2FieldRefreshToken}, true
+	default:
+		return nil, false
+	}
+}
+
+// CredentialTypeFunc returns the type of the credential id in a namespace, or "" when there is
+// no such credential.
+type CredentialTypeFunc func(ctx context.Context, namespace, id string) (string, error)
+
+// OAuth2SecretStore resolves the accessToken field of OAuth2 credentials by running the
+// credential's grant against its token URL, and passes every other name through to the wrapped
+// store. Tokens are cached per namespace, environment and credential until shortly before they
+// expire, or for half their lifetime when they live shorter than the renewal margin; a cached token
+// is only reused while the credential's fields are those it was issued for. When the token endpoint
+// rotates a refresh token, the new one is written back to the wrapped store if it is a
+// ManagedSecretStore.
+type OAuth2SecretStore struct {
+	inner          SecretStore
+	credentialType CredentialTypeFunc
+	client         *http.Client
+	now            func() time.Time
+
+	mu     sync.Mutex
+	tokens map[oauth2TokenKey]oauth2Token
+	group  singleflight.Group
+}
+
+type oauth2TokenKey struct {
+	namespace   string
+	environment string
+	id          string
+}
+
+type oauth2Token struct {
+	value   string
+	renewAt time.Time
+	// config is the credentialConfig hash of the fields the token was issued for.
+	config string
+}
+
+// managedOAuth2SecretStore keeps the wrapped store's administration methods visible.
+type managedOAuth2SecretStore struct {
+	*OAuth2SecretStore
+	ManagedSecretStore
+}
+
+// compile-time assertions.
+var (
+	_ SecretStore        = (*OAuth2SecretStore)(nil)
+	_ ManagedSecretStore = (*managedOAuth2SecretStore)(nil)
+)
+
+// NewOAuth2SecretStore wraps inner. credentialType tells OAuth2 credentials apart; client
+// defaults to a client with a 10s timeout. The result implements ManagedSecretStore when inner
+// does.
+func NewOAuth2SecretStore(inner SecretStore, credentialType CredentialTypeFunc, client *http.Client) SecretStore {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	store := &OAuth2SecretStore{
+		inner:          inner,
+		credentialType: credentialType,
+		client:         client,
+		now:            time.Now,
+		tokens:         make(map[oauth2TokenKey]oauth2Token),
+	}
+	if managed, ok := inner.(ManagedSecretStore); ok {
+		return &managedOAuth2SecretStore{OAuth2SecretStore: store, ManagedSecretStore: managed}
+	}
+	return store
+}
+
+// Resolve implements SecretStore; the embedded ManagedSecretStore's Resolve would bypass the token
+// exchange.
+func (s *managedOAuth2SecretStore) Resolve(ctx context.Context, scope Scope, name string) (SecretValue, error) {
+	return s.OAuth2SecretStore.Resolve(ctx, scope, name)
+}
+
+// Resolve returns a current access token for cred/<id>/accessToken of an OAuth2 credential and
+// delegates everything else.
+func (s *OAuth2SecretStore) Resolve(ctx context.Context, scope Scope, name string) (SecretValue, error) {
+	id, field, ok := ParseCredentialSecretName(name)
+	if !ok || field != OAuth2FieldAccessToken {
+		return s.inner.Resolve(ctx, scope, name)
+	}
+	credType, err := s.credentialType(ctx, scope.NamespaceOrDefault(), id)
+	if err != nil {
+		return SecretValue{}, fmt.Errorf("secrets/oauth2: credential %q: %w", id, err)
+	}
+	if _, isOAuth2 := OAuth2RequiredFields(credType); !isOAuth2 {
+		return s.inner.Resolve(ctx, scope, name)
+	}
+
+	fields, err := s.credentialFields(ctx, scope, id, credType)
+	if err != nil {
+		return SecretValue{}, err
+	}
+	key := oauth2TokenKey{namespace: scope.NamespaceOrDefault(), environment: scope.Environment, id: id}
+	config := credentialConfig(fields)
+	if token, cached := s.cachedToken(key, config); cached {
+		return NewSecretValue(token), nil
+	}
+	// Concurrent resolutions of one credential share a single token request, so a rotating
+	// refresh token is never redeemed twice. The request runs on its own context: a caller that
+	// gives up must not fail the others waiting on it.
+	flightKey := key.namespace + "\x00" + key.environment + "\x00" + key.id + "\x00" + config
+	results := s.group.DoChan(flightKey, func() (any, error) {
+		if token, cached := s.cachedToken(key, config); cached {
+			return token, nil
+		}
+		fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), oauth2FetchTimeout)
+		defer cancel()
+		return s.fetchToken(fetchCtx, scope, id, credType, key)
+	})
+	select {
+	case res := <-results:
+		if res.Err != nil {
+			return SecretValue{}, res.Err
+		}
+		return NewSecretValue(res.Val.(string)), nil
+	case <-ctx.Done():
+		return SecretValue{}, fmt.Errorf("secrets/oauth2: credential %q: %w", id, ctx.Err())
+	}
+}
+
+func (s *OAuth2SecretStore) cachedToken(key oauth2TokenKey, config string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	token, ok := s.tokens[key]
+	if !ok || token.config != config || !s.now().Before(token.renewAt) {
+		return "", false
+	}
+	return token.value, true
+}
+
+// credentialConfig hashes the credential fields a token is issued for, so a token is not reused
+// once the credential is changed.
+func credentialConfig(fields map[string]string) string {
+	h := sha256.New()
+	for _, field := range []string{
+		OAuth2FieldTokenURL, OAuth2FieldClientID, OAuth2FieldClientSecret, OAuth2FieldRefreshToken,
+		OAuth2FieldScopes, OAuth2FieldAudience, OAuth2FieldAuthStyle,
+	} {
+		h.Write([]byte(fields[field]))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// fetchToken runs the credential's grant and caches the resulting access token. The fields are
+// read again, so a refresh token rotated by the previous request is the one redeemed.
+func (s *OAuth2SecretStore) fetchToken(ctx context.Context, scope Scope, id, credType string, key oauth2TokenKey) (string, error) {
+	fields, err := s.credentialFields(ctx, scope, id, credType)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{}
+	switch credType {
+	case CredentialTypeOAuth2ClientCredentials:
+		form.Set("grant_type", "client_credentials")
+	case CredentialTypeOAuth2RefreshToken:
+		form.Set("grant_type", "refresh_token")
+		form.Set("refresh_token", fields[OAuth2FieldRefreshToken])
+	}
+	if scopes := fields[OAuth2FieldScopes]; scopes != "" {
+		form.Set("scope", scopes)
+	}
+	if audience := fields[OAuth2FieldAudience]; audience != "" {
+		form.Set("audience", audience)
+	}
+	clientID, clientSecret := fields[OAuth2FieldClientID], fields[OAuth2FieldClientSecret]
+	basicAuth := fields[OAuth2FieldAuthStyle] != "body" && clientSecret != ""
+	if !basicAuth {
+		form.Set("client_id", clientID)
+		if clientSecret != "" {
+			form.Set("client_secret", clientSecret)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fields[OAuth2FieldTokenURL], strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("secrets/oauth2: credential %q: build token request: %w", id, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if basicAuth {
+		// RFC 6749 §2.3.1: the client id and secret are form-encoded before Basic encoding.
+		req.SetBasicAuth(url.QueryEscape(clientID), url.QueryEscape(clientSecret))
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("secrets/oauth2: credential %q: token request: %w", id, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var body struct {
+		AccessToken      string `json:"access_token"`
+		ExpiresIn        int64  `json:"expires_in"`
+		RefreshToken     string `json:"refresh_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	decodeErr := json.NewDecoder(resp.Body).Decode(&body)
+	if resp.StatusCode != http.StatusOK || body.Error != "" {
+		if body.Error != "" {
+			return "", fmt.Errorf("secrets/oauth2: credential %q: token endpoint returned status %d: %s %s", id, resp.StatusCode, body.Error, body.ErrorDescription)
+		}
+		return "", fmt.Errorf("secrets/oauth2: credential %q: token endpoint returned status %d", id, resp.StatusCode)
+	}
+	if decodeErr != nil || body.AccessToken == "" {
+		return "", fmt.Errorf("secrets/oauth2: credential %q: token response has no access_token", id)
+	}
+
+	if body.RefreshToken != "" && body.RefreshToken != fields[OAuth2FieldRefreshToken] && credType == CredentialTypeOAuth2RefreshToken {
+		// The old refresh token may already be revoked, so a failed write-back fails the
+		// resolution rather than leaving the credential unusable after this token expires.
+		if managed, ok := s.inner.(ManagedSecretStore); ok {
+			if err := managed.Set(ctx, scope, CredentialSecretName(id, OAuth2FieldRefreshToken), body.RefreshToken); err != nil {
+				return "", fmt.Errorf("secrets/oauth2: credential %q: store rotated refresh token: %w", id, err)
+			}
+			// the token stays valid for the credential as it now reads
+			fields[OAuth2FieldRefreshToken] = body.RefreshToken
+		}
+	}
+
+	lifetime := oauth2DefaultLifetime
+	if body.ExpiresIn > 0 {
+		lifetime = time.Duration(body.ExpiresIn) * time.Second
+	}
+	// Tokens living shorter than the renewal margin are still reused for half their lifetime.
+	renewIn := max(lifetime-tokenRenewMargin, lifetime/2)
+	s.mu.Lock()
+	s.tokens[key] = oauth2Token{value: body.AccessToken, renewAt: s.now().Add(renewIn), config: credentialConfig(fields)}
+	s.mu.Unlock()
+	return body.AccessToken, nil
+}
+
+// credentialFields reads the credential's required fields and whichever optional ones are set.
+func (s *OAuth2SecretStore) credentialFields(ctx context.Context, scope Scope, id, credType string) (map[string]string, error) {
+	required, _ := OAuth2RequiredFields(credType)
+	fields := make(map[string]string)
+	for _, field := range required {
+		value, err := s.inner.Resolve(ctx, scope, CredentialSecretName(id, field))
+		if err != nil {
+			return nil, fmt.Errorf("secrets/oauth2: credential %q: field %q: %w", id, field, err)
+		}
+		fields[field] = value.Reveal()
+	}
+	for _, field := range []string{OAuth2FieldClientSecret, OAuth2FieldScopes, OAuth2FieldAudience, OAuth2FieldAuthStyle} {
+		if _, ok := fields[field]; ok {
+			continue
+		}
+		value, err := s.inner.Resolve(ctx, scope, CredentialSecretName(id, field))
+		switch {
+		case err == nil:
+			fields[field] = value.Reveal()
+		case !errors.Is(err, ErrSecretNotFound):
+			return nil, fmt.Errorf("secrets/oauth2: credential %q: field %q: %w", id, field, err)
+		}
+	}
+	return fields, nil
+}
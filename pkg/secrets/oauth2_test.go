@@ -0,0 +1,239 @@
+package secrets_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/open-source-cloud/fuse/pkg/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTokenEndpoint issues access tokens for client "app"/"s3cr3t" and rotates refresh tokens
+// ("r1" -> "r2" -> ...).
+type fakeTokenEndpoint struct {
+	expiresIn int64
+	requests  atomic.Int32
+
+	mu       sync.Mutex
+	forms    []map[string]string
+	validRT  string
+	rotation int
+}
+
+func (e *fakeTokenEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n := e.requests.Add(1)
+	_ = r.ParseForm()
+	form := map[string]string{}
+	for k := range r.PostForm {
+		form[k] = r.PostForm.Get(k)
+	}
+	if user, pass, ok := r.BasicAuth(); ok {
+		form["basic"] = user + ":" + pass
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.forms = append(e.forms, form)
+
+	resp := map[string]any{"access_token": "at-" + string(rune('0'+n)), "token_type": "Bearer", "expires_in": e.expiresIn}
+	switch form["grant_type"] {
+	case "client_credentials":
+		if form["basic"] != "app:s3cr3t" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client"})
+			return
+		}
+	case "refresh_token":
+		if form["refresh_token"] != e.validRT {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant", "error_description": "refresh token revoked"})
+			return
+		}
+		e.rotation++
+		e.validRT = "r" + string(rune('1'+e.rotation))
+		resp["refresh_token"] = e.validRT
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (e *fakeTokenEndpoint) lastForm() map[string]string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.forms[len(e.forms)-1]
+}
+
+func credentialTypes(types map[string]string) secrets.CredentialTypeFunc {
+	return func(_ context.Context, _, id string) (string, error) {
+		return types[id], nil
+	}
+}
+
+func setFields(t *testing.T, store secrets.ManagedSecretStore, scope secrets.Scope, id string, fields map[string]string) {
+	t.Helper()
+	for field, value := range fields {
+		require.NoError(t, store.Set(context.Background(), scope, secrets.CredentialSecretName(id, field), value))
+	}
+}
+
+func TestOAuth2SecretStore_ClientCredentials(t *testing.T) {
+	t.Parallel()
+	endpoint := &fakeTokenEndpoint{expiresIn: 3600}
+	srv := httptest.NewServer(endpoint)
+	t.Cleanup(srv.Close)
+
+	inner := secrets.NewMemorySecretStore()
+	prod := secrets.Scope{Environment: "prod"}
+	setFields(t, inner, prod, "crm", map[string]string{
+		"tokenUrl": srv.URL, "clientId": "app", "clientSecret": "s3cr3t", "scopes": "read write",
+	})
+	setFields(t, inner, prod, "static", map[string]string{"accessToken": "stored"})
+	store := secrets.NewOAuth2SecretStore(inner, credentialTypes(map[string]string{
+		"crm": secrets.CredentialTypeOAuth2ClientCredentials, "static": "custom",
+	}), srv.Client())
+	_, managed := store.(secrets.ManagedSecretStore)
+	assert.True(t, managed, "administration stays available over a managed backend")
+
+	ctx := context.Background()
+	for range 3 {
+		v, err := store.Resolve(ctx, prod, secrets.CredentialSecretName("crm", "accessToken"))
+		require.NoError(t, err)
+		assert.Equal(t, "at-1", v.Reveal())
+	}
+	assert.Equal(t, int32(1), endpoint.requests.Load(), "the token is cached until it expires")
+	form := endpoint.lastForm()
+	assert.Equal(t, "client_credentials", form["grant_type"])
+	assert.Equal(t, "read write", form["scope"])
+	assert.Empty(t, form["client_secret"], "the secret travels in the Authorization header by default")
+
+	_, err := store.Resolve(ctx, secrets.Scope{Environment: "dev"}, secrets.CredentialSecretName("crm", "accessToken"))
+	require.ErrorIs(t, err, secrets.ErrSecretNotFound, "each environment has its own credential values")
+
+	v, err := store.Resolve(ctx, prod, secrets.CredentialSecretName("crm", "clientId"))
+	require.NoError(t, err)
+	assert.Equal(t, "app", v.Reveal(), "stored fields pass through")
+	v, err = store.Resolve(ctx, prod, secrets.CredentialSecretName("static", "accessToken"))
+	require.NoError(t, err)
+	assert.Equal(t, "stored", v.Reveal(), "other credential types are not intercepted")
+}
+
+func TestOAuth2SecretStore_RefreshTokenRotation(t *testing.T) {
+	t.Parallel()
+	// Tokens expire inside the renewal margin, so each is reused for half a second.
+	endpoint := &fakeTokenEndpoint{expiresIn: 1, validRT: "r1"}
+	srv := httptest.NewServer(endpoint)
+	t.Cleanup(srv.Close)
+
+	inner := secrets.NewMemorySecretStore()
+	prod := secrets.Scope{Environment: "prod"}
+	setFields(t, inner, prod, "mail", map[string]string{
+		"tokenUrl": srv.URL, "clientId": "public-app", "refreshToken": "r1",
+	})
+	store := secrets.NewOAuth2SecretStore(inner, credentialTypes(map[string]string{
+		"mail": secrets.CredentialTypeOAuth2RefreshToken,
+	}), srv.Client())
+	ctx := context.Background()
+	name := secrets.CredentialSecretName("mail", "accessToken")
+
+	v, err := store.Resolve(ctx, prod, name)
+	require.NoError(t, err)
+	assert.Equal(t, "at-1", v.Reveal())
+	assert.Equal(t, "public-app", endpoint.lastForm()["client_id"], "clients without a secret identify in the body")
+
+	rt, err := inner.Resolve(ctx, prod, secrets.CredentialSecretName("mail", "refreshToken"))
+	require.NoError(t, err)
+	assert.Equal(t, "r2", rt.Reveal(), "the rotated refresh token is written back")
+
+	v, err = store.Resolve(ctx, prod, name)
+	require.NoError(t, err)
+	assert.Equal(t, "at-1", v.Reveal(), "short-lived tokens are cached too")
+	assert.Equal(t, int32(1), endpoint.requests.Load())
+
+	require.Eventually(t, func() bool {
+		v, err = store.Resolve(ctx, prod, name)
+		return err == nil && v.Reveal() == "at-2"
+	}, 2*time.Second, 50*time.Millisecond)
+	assert.Equal(t, "r2", endpoint.lastForm()["refresh_token"])
+
+	setFields(t, inner, prod, "mail", map[string]string{"refreshToken": "revoked"})
+	_, err = store.Resolve(ctx, prod, name)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, secrets.ErrSecretNotFound)
+	assert.Contains(t, err.Error(), "invalid_grant")
+}
+
+func TestOAuth2SecretStore_ChangedCredentialIsNotServedFromCache(t *testing.T) {
+	t.Parallel()
+	endpoint := &fakeTokenEndpoint{expiresIn: 3600}
+	srv := httptest.NewServer(endpoint)
+	t.Cleanup(srv.Close)
+
+	inner := secrets.NewMemorySecretStore()
+	prod := secrets.Scope{Environment: "prod"}
+	setFields(t, inner, prod, "crm", map[string]string{
+		"tokenUrl": srv.URL, "clientId": "app", "clientSecret": "s3cr3t", "scopes": "read",
+	})
+	store := secrets.NewOAuth2SecretStore(inner, credentialTypes(map[string]string{
+		"crm": secrets.CredentialTypeOAuth2ClientCredentials,
+	}), srv.Client())
+	ctx := context.Background()
+	name := secrets.CredentialSecretName("crm", "accessToken")
+
+	v, err := store.Resolve(ctx, prod, name)
+	require.NoError(t, err)
+	assert.Equal(t, "at-1", v.Reveal())
+
+	setFields(t, inner, prod, "crm", map[string]string{"scopes": "read write"})
+	v, err = store.Resolve(ctx, prod, name)
+	require.NoError(t, err)
+	assert.Equal(t, "at-2", v.Reveal(), "a token issued for other scopes is not reused")
+	assert.Equal(t, "read write", endpoint.lastForm()["scope"])
+}
+
+func TestOAuth2SecretStore_AbandonedResolutionDoesNotFailOthers(t *testing.T) {
+	t.Parallel()
+	endpoint := &fakeTokenEndpoint{expiresIn: 3600}
+	arrived, release := make(chan struct{}), make(chan struct{})
+	var once sync.Once
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		once.Do(func() { close(arrived) })
+		<-release
+		endpoint.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	inner := secrets.NewMemorySecretStore()
+	prod := secrets.Scope{Environment: "prod"}
+	setFields(t, inner, prod, "crm", map[string]string{
+		"tokenUrl": srv.URL, "clientId": "app", "clientSecret": "s3cr3t",
+	})
+	store := secrets.NewOAuth2SecretStore(inner, credentialTypes(map[string]string{
+		"crm": secrets.CredentialTypeOAuth2ClientCredentials,
+	}), srv.Client())
+	name := secrets.CredentialSecretName("crm", "accessToken")
+
+	first, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := store.Resolve(first, prod, name)
+		firstErr <- err
+	}()
+	<-arrived
+	second := make(chan secrets.SecretValue, 1)
+	go func() {
+		v, err := store.Resolve(context.Background(), prod, name)
+		assert.NoError(t, err)
+		second <- v
+	}()
+
+	cancel()
+	require.ErrorIs(t, <-firstErr, context.Canceled)
+	close(release)
+	assert.Equal(t, "at-1", (<-second).Reveal(), "the shared request outlives the caller that started it")
+	assert.Equal(t, int32(1), endpoint.requests.Load())
+}
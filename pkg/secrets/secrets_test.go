@@ -88,7 +88,11 @@ func TestMemorySecretStore(t *testing.T) {
 	_, err = s.Resolve(ctx, secrets.Scope{Environment: "dev"}, "api-key")
 	require.ErrorIs(t, err, secrets.ErrSecretNotFound)
 
-	names, err := s.List(ctx, "prod")
+	// Scoped by namespace.
+	_, err = s.Resolve(ctx, secrets.Scope{Namespace: "billing", Environment: "prod"}, "api-key")
+	require.ErrorIs(t, err, secrets.ErrSecretNotFound)
+
+	names, err := s.List(ctx, scope)
 	require.NoError(t, err)
 	assert.Equal(t, []string{"api-key"}, names)
 
@@ -116,3 +120,22 @@ func TestResolver_BindsEnvironment(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "T", v.Reveal())
 }
+
+func TestNamespacedResolver_BindsNamespace(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	store := secrets.NewMemorySecretStore()
+	require.NoError(t, store.Set(ctx, secrets.Scope{Namespace: "billing", Environment: "prod"}, "tok", "B"))
+	require.NoError(t, store.Set(ctx, secrets.Scope{Environment: "prod"}, "tok", "D"))
+
+	v, err := secrets.NewNamespacedResolver(store, "billing", "prod").Resolve(ctx, "wf-1", "tok")
+	require.NoError(t, err)
+	assert.Equal(t, "B", v.Reveal())
+
+	v, err = secrets.NewResolver(store, "prod").Resolve(ctx, "wf-1", "tok")
+	require.NoError(t, err)
+	assert.Equal(t, "D", v.Reveal(), "the default namespace is the unqualified scope")
+
+	_, err = secrets.NewNamespacedResolver(store, "ops", "prod").Resolve(ctx, "wf-1", "tok")
+	require.ErrorIs(t, err, secrets.ErrSecretNotFound)
+}
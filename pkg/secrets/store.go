@@ -9,14 +9,27 @@ import (
 // ErrSecretNotFound is returned when a secret cannot be resolved.
 var ErrSecretNotFound = errors.New("secret not found")
 
-// Scope identifies the resolution context for a secret. Environment is the
-// primary scoping dimension; WorkflowID is available for future per-workflow
-// overrides (backends may ignore it).
+// Scope identifies the resolution context for a secret. Namespace and Environment
+// are the scoping dimensions (an empty Namespace is the default namespace);
+// WorkflowID is available for future per-workflow overrides (backends may ignore it).
 type Scope struct {
+	Namespace   string
 	Environment string
 	WorkflowID  string
 }
 
+// DefaultNamespace is the namespace of scopes that do not name one. It matches
+// workflow.DefaultNamespaceName, which this package cannot import.
+const DefaultNamespace = "default"
+
+// NamespaceOrDefault returns the scope's namespace, or DefaultNamespace when unset.
+func (s Scope) NamespaceOrDefault() string {
+	if s.Namespace == "" {
+		return DefaultNamespace
+	}
+	return s.Namespace
+}
+
 // SecretStore resolves secrets by name within a scope (read-only).
 type SecretStore interface {
 	Resolve(ctx context.Context, scope Scope, name string) (SecretValue, error)
@@ -28,27 +41,43 @@ type SecretStore interface {
 type ManagedSecretStore interface {
 	SecretStore
 	Set(ctx context.Context, scope Scope, name, value string) error
-	List(ctx context.Context, environment string) ([]string, error)
+	// List returns the secret names in the scope's namespace and environment.
+	List(ctx context.Context, scope Scope) ([]string, error)
 	Delete(ctx context.Context, scope Scope, name string) error
 }
 
+// KeyRotator is implemented by encrypted backends that can move stored secrets to the keyring's
+// active key while serving reads and writes.
+type KeyRotator interface {
+	// RotateKeys re-wraps, batchSize rows per transaction, every secret not yet under the active
+	// key and returns how many it rotated.
+	RotateKeys(ctx context.Context, batchSize int) (int, error)
+}
+
 // Resolver is the narrow capability the workflow engine depends on: resolve a
-// secret by name for the running workflow. The environment is bound in, so the
-// engine never deals with scoping or the store directly.
+// secret by name for the running workflow. The namespace and environment are
+// bound in, so the engine never deals with scoping or the store directly.
 type Resolver interface {
 	Resolve(ctx context.Context, workflowID, name string) (SecretValue, error)
 }
 
-// NewResolver binds a SecretStore + environment into a Resolver.
+// NewResolver binds a SecretStore + environment of the default namespace into a Resolver.
 func NewResolver(store SecretStore, environment string) Resolver {
-	return &scopedResolver{store: store, environment: environment}
+	return NewNamespacedResolver(store, DefaultNamespace, environment)
+}
+
+// NewNamespacedResolver binds a SecretStore + namespace + environment into a Resolver.
+func NewNamespacedResolver(store SecretStore, namespace, environment string) Resolver {
+	return &scopedResolver{store: store, namespace: namespace, environment: environment}
 }
 
 type scopedResolver struct {
 	store       SecretStore
+	namespace   string
 	environment string
 }
 
 func (r *scopedResolver) Resolve(ctx context.Context, workflowID, name string) (SecretValue, error) {
-	return r.store.Resolve(ctx, Scope{Environment: r.environment, WorkflowID: workflowID}, strings.TrimSpace(name))
+	scope := Scope{Namespace: r.namespace, Environment: r.environment, WorkflowID: workflowID}
+	return r.store.Resolve(ctx, scope, strings.TrimSpace(name))
 }
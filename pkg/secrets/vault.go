@@ -0,0 +1,230 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// VaultConfig configures a VaultSecretStore. Token auth is used when Token is set, AppRole auth
+// when RoleID is set.
+type VaultConfig struct {
+	// Address is the Vault server URL, e.g. https://vault.example.com:8200.
+	Address string
+	// Mount is the KV v2 secrets engine mount (default "secret").
+	Mount string
+	// PathPrefix is prepended to every secret path, so environments map to
+	// <PathPrefix>/<environment>/<name>.
+	PathPrefix string
+	// Field is the key read from each KV entry (default "value").
+	Field string
+	// Token is a static Vault token.
+	Token string
+	// RoleID and SecretID log in through the AppRole auth method mounted at AppRoleMount
+	// (default "approle"); the token is renewed by logging in again before its lease ends.
+	RoleID       string
+	SecretID     string
+	AppRoleMount string
+	// HTTPClient defaults to a client with a 10s timeout.
+	HTTPClient *http.Client
+}
+
+// VaultSecretStore is a read-only SecretStore over a HashiCorp Vault KV v2 engine. A secret is
+// the Field of the entry at <PathPrefix>/<environment>/<name>, or at
+// <PathPrefix>/_namespaces/<namespace>/<environment>/<name> outside the default namespace.
+type VaultSecretStore struct {
+	cfg    VaultConfig
+	client *http.Client
+	now    func() time.Time
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time // zero for static tokens and non-expiring AppRole tokens
+}
+
+// compile-time assertion.
+var _ SecretStore = (*VaultSecretStore)(nil)
+
+// tokenRenewMargin is how long before its lease ends an AppRole token is replaced.
+const tokenRenewMargin = 30 * time.Second
+
+// NewVaultSecretStore validates cfg and creates a Vault-backed store. It does not contact Vault;
+// AppRole logs in on the first read.
+func NewVaultSecretStore(cfg VaultConfig) (*VaultSecretStore, error) {
+	if cfg.Address == "" {
+		return nil, errors.New("secrets/vault: address is required")
+	}
+	if cfg.Token == "" && cfg.RoleID == "" {
+		return nil, errors.New("secrets/vault: a token or an AppRole role ID is required")
+	}
+	if cfg.Mount == "" {
+		cfg.Mount = "secret"
+	}
+	if cfg.Field == "" {
+		cfg.Field = "value"
+	}
+	if cfg.AppRoleMount == "" {
+		cfg.AppRoleMount = "approle"
+	}
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	cfg.Address = strings.TrimRight(cfg.Address, "/")
+	cfg.Mount = strings.Trim(cfg.Mount, "/")
+	cfg.PathPrefix = strings.Trim(cfg.PathPrefix, "/")
+	return &VaultSecretStore{cfg: cfg, client: client, now: time.Now, token: cfg.Token}, nil
+}
+
+// Resolve reads the secret's KV v2 entry and returns its configured field.
+func (s *VaultSecretStore) Resolve(ctx context.Context, scope Scope, name string) (SecretValue, error) {
+	rel, err := scopedPath(scope, name)
+	if err != nil {
+		return SecretValue{}, err
+	}
+	if s.cfg.PathPrefix != "" {
+		rel = s.cfg.PathPrefix + "/" + rel
+	}
+	notFound := fmt.Errorf("%w: %q (namespace %q, environment %q)", ErrSecretNotFound, name, scope.NamespaceOrDefault(), scope.Environment)
+
+	status, body, err := s.read(ctx, rel)
+	if err == nil && status == http.StatusForbidden && s.cfg.RoleID != "" {
+		// The token may have been revoked before its lease ended; log in once more.
+		s.mu.Lock()
+		s.token = ""
+		s.mu.Unlock()
+		status, body, err = s.read(ctx, rel)
+	}
+	if err != nil {
+		return SecretValue{}, err
+	}
+	switch status {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return SecretValue{}, notFound
+	default:
+		return SecretValue{}, fmt.Errorf("secrets/vault: read %q: %s", name, vaultError(status, body))
+	}
+
+	var resp struct {
+		Data struct {
+			Data map[string]any `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return SecretValue{}, fmt.Errorf("secrets/vault: decode %q: %w", name, err)
+	}
+	// A deleted (soft-deleted or destroyed) version has no data.
+	value, ok := resp.Data.Data[s.cfg.Field]
+	if !ok || value == nil {
+		return SecretValue{}, notFound
+	}
+	if str, isString := value.(string); isString {
+		return NewSecretValue(str), nil
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return SecretValue{}, fmt.Errorf("secrets/vault: encode %q: %w", name, err)
+	}
+	return NewSecretValue(string(raw)), nil
+}
+
+func (s *VaultSecretStore) read(ctx context.Context, rel string) (int, []byte, error) {
+	token, err := s.currentToken(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	endpoint := s.cfg.Address + "/v1/" + s.cfg.Mount + "/data/" + escapePath(rel)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return 0, nil, fmt.Errorf("secrets/vault: build request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", token)
+	return s.do(req)
+}
+
+// currentToken returns the static token, or a valid AppRole token, logging in when needed.
+func (s *VaultSecretStore) currentToken(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != "" && (s.tokenExpiry.IsZero() || s.now().Before(s.tokenExpiry.Add(-tokenRenewMargin))) {
+		return s.token, nil
+	}
+	if s.cfg.RoleID == "" {
+		s.token = s.cfg.Token
+		return s.token, nil
+	}
+
+	payload, err := json.Marshal(map[string]string{"role_id": s.cfg.RoleID, "secret_id": s.cfg.SecretID})
+	if err != nil {
+		return "", fmt.Errorf("secrets/vault: encode login: %w", err)
+	}
+	endpoint := s.cfg.Address + "/v1/auth/" + strings.Trim(s.cfg.AppRoleMount, "/") + "/login"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("secrets/vault: build login request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	status, body, err := s.do(req)
+	if err != nil {
+		return "", err
+	}
+	if status != http.StatusOK {
+		return "", fmt.Errorf("secrets/vault: approle login: %s", vaultError(status, body))
+	}
+	var resp struct {
+		Auth struct {
+			ClientToken   string `json:"client_token"`
+			LeaseDuration int64  `json:"lease_duration"`
+		} `json:"auth"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil || resp.Auth.ClientToken == "" {
+		return "", fmt.Errorf("secrets/vault: approle login returned no token")
+	}
+	s.token = resp.Auth.ClientToken
+	s.tokenExpiry = time.Time{}
+	if resp.Auth.LeaseDuration > 0 {
+		s.tokenExpiry = s.now().Add(time.Duration(resp.Auth.LeaseDuration) * time.Second)
+	}
+	return s.token, nil
+}
+
+func (s *VaultSecretStore) do(req *http.Request) (int, []byte, error) {
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("secrets/vault: %s %s: %w", req.Method, req.URL.Path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
+		return 0, nil, fmt.Errorf("secrets/vault: read response: %w", err)
+	}
+	return resp.StatusCode, buf.Bytes(), nil
+}
+
+// escapePath escapes each segment of a slash-separated Vault path.
+func escapePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// vaultError formats a Vault error response ({"errors": [...]}) without echoing secret data.
+func vaultError(status int, body []byte) string {
+	var resp struct {
+		Errors []string `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &resp); err == nil && len(resp.Errors) > 0 {
+		return fmt.Sprintf("status %d: %s", status, strings.Join(resp.Errors, "; "))
+	}
+	return fmt.Sprintf("status %d", status)
+}
@@ -0,0 +1,148 @@
+package secrets_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/open-source-cloud/fuse/pkg/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeVault serves KV v2 reads from entries (path below the mount -> data) and AppRole logins.
+type fakeVault struct {
+	entries map[string]map[string]any
+	token   atomic.Value // string
+	logins  atomic.Int32
+}
+
+func (v *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/auth/approle/login":
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body["role_id"] != "role" || body["secret_id"] != "secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"errors":["invalid role or secret ID"]}`))
+			return
+		}
+		v.logins.Add(1)
+		_ = json.NewEncoder(w).Encode(map[string]any{"auth": map[string]any{"client_token": v.token.Load(), "lease_duration": 3600}})
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/v1/kv/data/"):
+		if r.Header.Get("X-Vault-Token") != v.token.Load() {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		data, ok := v.entries[strings.TrimPrefix(r.URL.Path, "/v1/kv/data/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[]}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"data": data}})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newFakeVault(t *testing.T) (*fakeVault, *httptest.Server) {
+	t.Helper()
+	v := &fakeVault{
+		entries: map[string]map[string]any{
+			"fuse/prod/api-key":                     {"value": "prod-key"},
+			"fuse/prod/cred/openai/apiKey":          {"value": "sk-123"},
+			"fuse/_namespaces/billing/prod/api-key": {"value": "billing-key"},
+			"fuse/prod/structured":                  {"value": map[string]any{"a": 1}},
+			"fuse/prod/other-field":                 {"password": "p"},
+		},
+	}
+	v.token.Store("s.valid")
+	srv := httptest.NewServer(v)
+	t.Cleanup(srv.Close)
+	return v, srv
+}
+
+func TestVaultSecretStore_TokenAuth(t *testing.T) {
+	t.Parallel()
+	_, srv := newFakeVault(t)
+	store, err := secrets.NewVaultSecretStore(secrets.VaultConfig{
+		Address: srv.URL, Mount: "kv", PathPrefix: "fuse", Token: "s.valid",
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+	prod := secrets.Scope{Environment: "prod"}
+
+	v, err := store.Resolve(ctx, prod, "api-key")
+	require.NoError(t, err)
+	assert.Equal(t, "prod-key", v.Reveal())
+
+	v, err = store.Resolve(ctx, prod, "cred/openai/apiKey")
+	require.NoError(t, err)
+	assert.Equal(t, "sk-123", v.Reveal(), "credential fields map to nested paths")
+
+	v, err = store.Resolve(ctx, secrets.Scope{Namespace: "billing", Environment: "prod"}, "api-key")
+	require.NoError(t, err)
+	assert.Equal(t, "billing-key", v.Reveal())
+
+	v, err = store.Resolve(ctx, prod, "structured")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"a":1}`, v.Reveal())
+
+	_, err = store.Resolve(ctx, secrets.Scope{Environment: "dev"}, "api-key")
+	assert.ErrorIs(t, err, secrets.ErrSecretNotFound)
+	_, err = store.Resolve(ctx, prod, "other-field")
+	assert.ErrorIs(t, err, secrets.ErrSecretNotFound, "entries without the configured field are missing")
+	_, err = store.Resolve(ctx, prod, "../../sys/mounts")
+	assert.ErrorIs(t, err, secrets.ErrSecretNotFound, "names cannot leave their scope")
+
+	denied, err := secrets.NewVaultSecretStore(secrets.VaultConfig{Address: srv.URL, Mount: "kv", PathPrefix: "fuse", Token: "s.wrong"})
+	require.NoError(t, err)
+	_, err = denied.Resolve(ctx, prod, "api-key")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, secrets.ErrSecretNotFound)
+	assert.Contains(t, err.Error(), "permission denied")
+}
+
+func TestVaultSecretStore_AppRole(t *testing.T) {
+	t.Parallel()
+	vault, srv := newFakeVault(t)
+	store, err := secrets.NewVaultSecretStore(secrets.VaultConfig{
+		Address: srv.URL, Mount: "kv", PathPrefix: "fuse", RoleID: "role", SecretID: "secret",
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+	prod := secrets.Scope{Environment: "prod"}
+
+	for range 3 {
+		v, err := store.Resolve(ctx, prod, "api-key")
+		require.NoError(t, err)
+		assert.Equal(t, "prod-key", v.Reveal())
+	}
+	assert.Equal(t, int32(1), vault.logins.Load(), "the login token is reused until its lease ends")
+
+	// A revoked token is replaced by logging in again.
+	vault.token.Store("s.rotated")
+	v, err := store.Resolve(ctx, prod, "api-key")
+	require.NoError(t, err)
+	assert.Equal(t, "prod-key", v.Reveal())
+	assert.Equal(t, int32(2), vault.logins.Load())
+
+	bad, err := secrets.NewVaultSecretStore(secrets.VaultConfig{Address: srv.URL, Mount: "kv", RoleID: "role", SecretID: "nope"})
+	require.NoError(t, err)
+	_, err = bad.Resolve(ctx, prod, "api-key")
+	assert.ErrorContains(t, err, "invalid role or secret ID")
+}
+
+func TestNewVaultSecretStore_Validation(t *testing.T) {
+	t.Parallel()
+	_, err := secrets.NewVaultSecretStore(secrets.VaultConfig{Token: "t"})
+	assert.Error(t, err, "address is required")
+	_, err = secrets.NewVaultSecretStore(secrets.VaultConfig{Address: "http://vault:8200"})
+	assert.Error(t, err, "an auth method is required")
+}
@@ -9,3 +9,7 @@ const (
 	HTTP Type = "http"
 	gRPC Type = "grpc"
 )
+
+// CallbackTokenHeader carries an execution's callback token: the HTTP transport sends it with the
+// function request, and the async function returns it when reporting its result.
+const CallbackTokenHeader = "X-Fuse-Callback-Token"
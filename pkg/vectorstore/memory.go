@@ -0,0 +1,136 @@
+package vectorstore
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+)
+
+// MemoryVectorStore is an in-memory implementation of VectorStore for dev/test. Queries scan the
+// whole collection. Not safe for multi-process use; all data is lost on process exit.
+type MemoryVectorStore struct {
+	mu          sync.RWMutex
+	collections map[string]*memoryCollection
+}
+
+type memoryCollection struct {
+	dims   int
+	chunks map[string]Chunk
+}
+
+// NewMemoryVectorStore creates a new in-memory vector store.
+func NewMemoryVectorStore() *MemoryVectorStore {
+	return &MemoryVectorStore{collections: make(map[string]*memoryCollection)}
+}
+
+func collectionKey(namespace, collection string) string {
+	return namespace + "/" + collection
+}
+
+// Upsert stores chunks, fixing the collection's dimensions on its first write.
+func (m *MemoryVectorStore) Upsert(_ context.Context, namespace, collection string, chunks []Chunk) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := collectionKey(namespace, collection)
+	c := m.collections[key]
+	dims := 0
+	if c != nil {
+		dims = c.dims
+	}
+	for _, chunk := range chunks {
+		if len(chunk.Vector) == 0 {
+			return fmt.Errorf("chunk %q: %w", chunk.ID, ErrEmptyVector)
+		}
+		if dims == 0 {
+			dims = len(chunk.Vector)
+		}
+		if len(chunk.Vector) != dims {
+			return fmt.Errorf("chunk %q has %d dimensions, want %d: %w", chunk.ID, len(chunk.Vector), dims, ErrDimensionMismatch)
+		}
+	}
+	if len(chunks) == 0 {
+		return nil
+	}
+	if c == nil {
+		c = &memoryCollection{dims: dims, chunks: make(map[string]Chunk)}
+		m.collections[key] = c
+	}
+	for _, chunk := range chunks {
+		chunk.Vector = append([]float32(nil), chunk.Vector...)
+		c.chunks[chunk.ID] = chunk
+	}
+	return nil
+}
+
+// Query ranks every chunk of the collection by cosine similarity.
+func (m *MemoryVectorStore) Query(_ context.Context, namespace, collection string, vector []float32, topK int) ([]Match, error) {
+	if len(vector) == 0 {
+		return nil, ErrEmptyVector
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	c := m.collections[collectionKey(namespace, collection)]
+	if c == nil || topK <= 0 {
+		return []Match{}, nil
+	}
+	if len(vector) != c.dims {
+		return nil, fmt.Errorf("query has %d dimensions, want %d: %w", len(vector), c.dims, ErrDimensionMismatch)
+	}
+
+	matches := make([]Match, 0, len(c.chunks))
+	for _, chunk := range c.chunks {
+		matches = append(matches, Match{
+			ID:       chunk.ID,
+			Content:  chunk.Content,
+			Metadata: chunk.Metadata,
+			Score:    cosine(vector, chunk.Vector),
+		})
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].ID < matches[j].ID
+	})
+	if len(matches) > topK {
+		matches = matches[:topK]
+	}
+	return matches, nil
+}
+
+// Delete removes chunks by ID; a collection left empty forgets its dimensions.
+func (m *MemoryVectorStore) Delete(_ context.Context, namespace, collection string, ids []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := collectionKey(namespace, collection)
+	c := m.collections[key]
+	if c == nil {
+		return nil
+	}
+	for _, id := range ids {
+		delete(c.chunks, id)
+	}
+	if len(c.chunks) == 0 {
+		delete(m.collections, key)
+	}
+	return nil
+}
+
+// cosine returns the cosine similarity of two equal-length vectors; a zero vector scores 0.
+func cosine(a, b []float32) float64 {
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
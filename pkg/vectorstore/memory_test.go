@@ -0,0 +1,50 @@
+package vectorstore_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/open-source-cloud/fuse/pkg/vectorstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryVectorStore_RanksByCosineSimilarity(t *testing.T) {
+	ctx := context.Background()
+	store := vectorstore.NewMemoryVectorStore()
+	require.NoError(t, store.Upsert(ctx, "default", "docs", []vectorstore.Chunk{
+		{ID: "north", Content: "n", Vector: []float32{0, 1}},
+		{ID: "east", Content: "e", Vector: []float32{1, 0}},
+		{ID: "north-east", Content: "ne", Vector: []float32{3, 3}},
+	}))
+
+	matches, err := store.Query(ctx, "default", "docs", []float32{2, 0}, 2)
+
+	require.NoError(t, err)
+	require.Len(t, matches, 2)
+	assert.Equal(t, "east", matches[0].ID)
+	assert.InDelta(t, 1.0, matches[0].Score, 1e-9)
+	assert.Equal(t, "north-east", matches[1].ID)
+	assert.InDelta(t, 0.7071, matches[1].Score, 1e-4)
+}
+
+func TestMemoryVectorStore_ZeroVectorScoresZero(t *testing.T) {
+	ctx := context.Background()
+	store := vectorstore.NewMemoryVectorStore()
+	require.NoError(t, store.Upsert(ctx, "default", "docs", []vectorstore.Chunk{{ID: "zero", Vector: []float32{0, 0}}}))
+
+	matches, err := store.Query(ctx, "default", "docs", []float32{1, 0}, 1)
+
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Zero(t, matches[0].Score)
+}
+
+func TestMemoryVectorStore_DeletingEveryChunkResetsDimensions(t *testing.T) {
+	ctx := context.Background()
+	store := vectorstore.NewMemoryVectorStore()
+	require.NoError(t, store.Upsert(ctx, "default", "docs", []vectorstore.Chunk{{ID: "a", Vector: []float32{1, 0}}}))
+	require.NoError(t, store.Delete(ctx, "default", "docs", []string{"a"}))
+
+	assert.NoError(t, store.Upsert(ctx, "default", "docs", []vectorstore.Chunk{{ID: "a", Vector: []float32{1, 0, 0}}}))
+}
@@ -0,0 +1,53 @@
+// Package vectorstore defines the interface and implementations for the stores that hold embedded
+// document chunks for retrieval-augmented generation (ai/index, ai/retrieve).
+package vectorstore
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrDimensionMismatch is returned when a vector's length differs from the dimensions of the
+	// collection it is written to or queried against. A collection takes the dimensions of its
+	// first chunk, so every chunk in it must come from the same embedding model.
+	ErrDimensionMismatch = errors.New("vector dimensions do not match the collection")
+	// ErrEmptyVector is returned when a chunk or query has no vector.
+	ErrEmptyVector = errors.New("vector is empty")
+)
+
+// Chunk is one embedded piece of a document.
+type Chunk struct {
+	// ID identifies the chunk within its collection; upserting an existing ID replaces it.
+	ID       string         `json:"id"`
+	Content  string         `json:"content"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+	Vector   []float32      `json:"-"`
+}
+
+// Match is a chunk returned by a query, with its cosine similarity to the query vector
+// (1 = same direction, 0 = unrelated).
+type Match struct {
+	ID       string         `json:"id"`
+	Content  string         `json:"content"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+	Score    float64        `json:"score"`
+}
+
+// VectorStore stores chunks in named collections, scoped by namespace, and finds the chunks
+// nearest to a query vector.
+//
+// Implementations:
+//   - MemoryVectorStore: in-memory brute-force cosine search for dev/test
+//   - postgres.VectorStore: pgvector-backed, for production
+type VectorStore interface {
+	// Upsert inserts or replaces chunks by ID.
+	Upsert(ctx context.Context, namespace, collection string, chunks []Chunk) error
+
+	// Query returns up to topK chunks ordered by descending similarity to vector. An unknown
+	// collection has no matches.
+	Query(ctx context.Context, namespace, collection string, vector []float32, topK int) ([]Match, error)
+
+	// Delete removes chunks by ID; unknown IDs are ignored.
+	Delete(ctx context.Context, namespace, collection string, ids []string) error
+}
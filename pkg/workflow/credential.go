@@ -3,8 +3,10 @@ package workflow
 import (
 	"fmt"
 	"regexp"
+	"slices"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/open-source-cloud/fuse/pkg/secrets"
 )
 
 // credentialIDPattern restricts credential ids to lowercase alphanumerics plus -._ so they are
@@ -31,7 +33,8 @@ func NewCredential(id, credType, description string, fields []string) *Credentia
 	return &Credential{ID: id, Type: credType, Description: description, Fields: fields}
 }
 
-// Validate checks the credential's required fields and the id / field-name format.
+// Validate checks the credential's required fields and the id / field-name format. OAuth2 types
+// must carry their grant's fields and may not store the accessToken they compute.
 func (c *Credential) Validate() error {
 	validate := validator.New(validator.WithRequiredStructEnabled())
 	if err := validate.Struct(c); err != nil {
@@ -45,5 +48,15 @@ func (c *Credential) Validate() error {
 			return fmt.Errorf("invalid credential field name %q: must match %s", f, credentialFieldPattern.String())
 		}
 	}
+	if required, isOAuth2 := secrets.OAuth2RequiredFields(c.Type); isOAuth2 {
+		if slices.Contains(c.Fields, secrets.OAuth2FieldAccessToken) {
+			return fmt.Errorf("credential type %s computes %q; it cannot be set", c.Type, secrets.OAuth2FieldAccessToken)
+		}
+		for _, f := range required {
+			if !slices.Contains(c.Fields, f) {
+				return fmt.Errorf("credential type %s requires field %q", c.Type, f)
+			}
+		}
+	}
 	return nil
 }
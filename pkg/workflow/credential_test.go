@@ -22,6 +22,10 @@ func TestCredentialValidate(t *testing.T) {
 		{name: "missing type", cred: NewCredential("c1", "", "", []string{"apiKey"}), wantErr: true},
 		{name: "uppercase id rejected", cred: NewCredential("Prod", "openai", "", nil), wantErr: true},
 		{name: "bad field name rejected", cred: NewCredential("c1", "openai", "", []string{"api Key"}), wantErr: true},
+		{name: "oauth2 client credentials", cred: NewCredential("crm", "oauth2_client_credentials", "", []string{"clientId", "clientSecret", "scopes", "tokenUrl"}), wantErr: false},
+		{name: "oauth2 missing secret", cred: NewCredential("crm", "oauth2_client_credentials", "", []string{"clientId", "tokenUrl"}), wantErr: true},
+		{name: "oauth2 refresh token", cred: NewCredential("mail", "oauth2_refresh_token", "", []string{"clientId", "refreshToken", "tokenUrl"}), wantErr: false},
+		{name: "oauth2 access token is computed", cred: NewCredential("mail", "oauth2_refresh_token", "", []string{"accessToken", "clientId", "refreshToken", "tokenUrl"}), wantErr: true},
 	}
 
 	for _, tt := range tests {
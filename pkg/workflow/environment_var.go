@@ -0,0 +1,79 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// ErrEnvVarNotFound is returned when a {{var:NAME}} reference or a source:"var" mapping names a
+// variable the execution's environment does not define.
+var ErrEnvVarNotFound = errors.New("environment variable not found")
+
+// MaxEnvVarValueLength bounds a variable's value; larger configuration belongs in a package or
+// the object store.
+const MaxEnvVarValueLength = 64 * 1024
+
+// envVarNamePattern matches the {{secret:NAME}} charset so both token kinds read alike.
+var envVarNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.\-]+$`)
+
+// varRefPattern matches {{var:NAME}} tokens.
+var varRefPattern = regexp.MustCompile(`\{\{var:([A-Za-z0-9_.\-]+)\}\}`)
+
+// ValidateEnvVar checks an environment variable's name format and value size.
+func ValidateEnvVar(name, value string) error {
+	if !envVarNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid variable name %q: must match %s", name, envVarNamePattern.String())
+	}
+	if len(value) > MaxEnvVarValueLength {
+		return fmt.Errorf("variable %q is %d bytes; the limit is %d", name, len(value), MaxEnvVarValueLength)
+	}
+	return nil
+}
+
+// VarRefToken renders the {{var:NAME}} token form.
+func VarRefToken(name string) string {
+	return fmt.Sprintf("{{var:%s}}", name)
+}
+
+// HasVarRef reports whether s contains any {{var:NAME}} token.
+func HasVarRef(s string) bool {
+	return varRefPattern.MatchString(s)
+}
+
+// VarRefNames returns the distinct variable names referenced in s.
+func VarRefNames(s string) []string {
+	matches := varRefPattern.FindAllStringSubmatch(s, -1)
+	names := make([]string, 0, len(matches))
+	seen := make(map[string]struct{}, len(matches))
+	for _, m := range matches {
+		if _, ok := seen[m[1]]; ok {
+			continue
+		}
+		seen[m[1]] = struct{}{}
+		names = append(names, m[1])
+	}
+	return names
+}
+
+// ReplaceVarRefs replaces each {{var:NAME}} in s with vars[NAME]. Unlike secret references the
+// result is plain text. A name missing from vars fails with ErrEnvVarNotFound.
+func ReplaceVarRefs(s string, vars map[string]string) (string, error) {
+	var firstErr error
+	out := varRefPattern.ReplaceAllStringFunc(s, func(token string) string {
+		if firstErr != nil {
+			return token
+		}
+		name := varRefPattern.FindStringSubmatch(token)[1]
+		value, ok := vars[name]
+		if !ok {
+			firstErr = fmt.Errorf("%w: %q", ErrEnvVarNotFound, name)
+			return token
+		}
+		return value
+	})
+	if firstErr != nil {
+		return "", firstErr
+	}
+	return out, nil
+}
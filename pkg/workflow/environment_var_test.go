@@ -0,0 +1,35 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateEnvVar(t *testing.T) {
+	t.Parallel()
+	require.NoError(t, ValidateEnvVar("API_BASE_URL", "https://api.example.com"))
+	require.NoError(t, ValidateEnvVar("feature.new-checkout", ""))
+	assert.Error(t, ValidateEnvVar("has space", "x"))
+	assert.Error(t, ValidateEnvVar("", "x"))
+	assert.Error(t, ValidateEnvVar("big", strings.Repeat("x", MaxEnvVarValueLength+1)))
+}
+
+func TestReplaceVarRefs(t *testing.T) {
+	t.Parallel()
+	vars := map[string]string{"HOST": "api.example.com", "BUCKET": "orders"}
+
+	out, err := ReplaceVarRefs("https://{{var:HOST}}/{{var:BUCKET}}/{{var:HOST}}", vars)
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.example.com/orders/api.example.com", out)
+
+	assert.True(t, HasVarRef(VarRefToken("HOST")))
+	assert.False(t, HasVarRef("{{secret:HOST}}"))
+
+	assert.Equal(t, []string{"HOST", "BUCKET"}, VarRefNames("https://{{var:HOST}}/{{var:BUCKET}}/{{var:HOST}}"))
+
+	_, err = ReplaceVarRefs("{{var:NOPE}}", vars)
+	assert.ErrorIs(t, err, ErrEnvVarNotFound)
+}
@@ -1,5 +1,7 @@
 package workflow
 
+import "context"
+
 // NewExecutionInfo creates a new ExecutionInfo to pass to an executable function
 func NewExecutionInfo(workflowID ID, execID ExecID, environment string, input *FunctionInput) *ExecutionInfo {
 	return &ExecutionInfo{
@@ -18,6 +20,34 @@ type ExecutionInfo struct {
 	// resolve per-context capabilities (e.g. LLM provider keys) without the function touching the
 	// secret store; it is scope data, not a secret.
 	Environment string
-	Input       *FunctionInput
-	Finish      func(FunctionOutput)
+	// SchemaID is the schema the running workflow was triggered from.
+	SchemaID string
+	// LLMBudget is the schema's LLM spend cap, enforced by ai functions together with any per-node
+	// limits; nil when the schema sets none.
+	LLMBudget *LLMBudget
+	// CallbackToken is the signed, single-use token an async function must present when it
+	// reports its result over HTTP (POST /v1/workflows/{workflowID}/execs/{execID}, under
+	// /v1/ns/{ns} outside the default namespace). It expires with the node's execution timeout.
+	CallbackToken string
+	Input         *FunctionInput
+	Finish        func(FunctionOutput)
+	// Context is cancelled when the engine abandons this execution: the workflow is cancelled or
+	// ends, or the node's execution timeout fires. For an in-process tool invocation it is the
+	// agent's context bounded by the tool timeout. It may be nil; use Ctx to read it.
+	Context context.Context
+	// Checkpoints holds the sub-steps journaled by an earlier run of this execution attempt that was
+	// interrupted (crash, failover), oldest first. A long-running function resumes from them instead
+	// of redoing the work.
+	Checkpoints []map[string]any
+	// Checkpoint journals one sub-step of this execution. It is nil when the execution cannot be
+	// checkpointed (in-process tool invocations).
+	Checkpoint func(data map[string]any)
+}
+
+// Ctx returns the execution's context, or context.Background() when none was attached.
+func (e *ExecutionInfo) Ctx() context.Context {
+	if e.Context == nil {
+		return context.Background()
+	}
+	return e.Context
 }
@@ -0,0 +1,33 @@
+package workflow
+
+// LLMBudget caps the LLM spend of a workflow schema. Spend is the USD cost computed from the
+// configured pricing table plus the total tokens of every LLM call made by the schema's ai nodes.
+type LLMBudget struct {
+	// PerExecution caps the spend of a single workflow execution.
+	PerExecution *BudgetLimit `json:"perExecution,omitempty"`
+	// Daily caps the spend of all executions of the schema in one environment per UTC day.
+	Daily *BudgetLimit `json:"daily,omitempty"`
+}
+
+// BudgetLimit is a spend cap; a zero field is not enforced.
+type BudgetLimit struct {
+	MaxCostUSD float64 `json:"maxCostUSD,omitempty" validate:"gte=0"`
+	MaxTokens  int     `json:"maxTokens,omitempty" validate:"gte=0"`
+}
+
+// Clone returns a deep copy of the budget, or nil for a nil budget.
+func (b *LLMBudget) Clone() *LLMBudget {
+	if b == nil {
+		return nil
+	}
+	clone := &LLMBudget{}
+	if b.PerExecution != nil {
+		limit := *b.PerExecution
+		clone.PerExecution = &limit
+	}
+	if b.Daily != nil {
+		limit := *b.Daily
+		clone.Daily = &limit
+	}
+	return clone
+}
@@ -5,8 +5,10 @@ import "github.com/open-source-cloud/fuse/pkg/transport"
 // FunctionMetadata defines the metadata structure for a Function
 type FunctionMetadata struct {
 	Transport transport.Type `json:"transport"`
-	Input     InputMetadata  `json:"input"`
-	Output    OutputMetadata `json:"output,omitempty"`
+	// Endpoint is the URL the engine calls a function of the HTTP transport at.
+	Endpoint string         `json:"endpoint,omitempty"`
+	Input    InputMetadata  `json:"input"`
+	Output   OutputMetadata `json:"output,omitempty"`
 }
 
 // InputMetadata represents one Input or Result Metadata descriptor
@@ -0,0 +1,102 @@
+package workflow
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// DefaultNamespaceName is the namespace of every definition that is not qualified with one. It
+// always exists, has no quotas and keeps the pre-namespace IDs and /v1 routes unchanged.
+const DefaultNamespaceName = "default"
+
+// NamespaceSeparator joins a namespace and a schema or package ID into a qualified ID
+// ("billing:invoice"). It is reserved: unqualified IDs must not contain it.
+const NamespaceSeparator = ":"
+
+// namespaceNamePattern matches environment names, so namespaces are safe as URL path segments and
+// never contain the separator.
+var namespaceNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.\-]*$`)
+
+type (
+	// Namespace isolates the schemas, executions, packages, credentials and secrets of one tenant
+	// sharing the cluster.
+	Namespace struct {
+		Name        string         `json:"name" validate:"required,max=128"`
+		Description string         `json:"description,omitempty"`
+		Quota       NamespaceQuota `json:"quota"`
+	}
+
+	// NamespaceQuota caps what a namespace may consume; zero values are unlimited.
+	NamespaceQuota struct {
+		// MaxConcurrentWorkflows caps the executions that are untriggered, running or sleeping.
+		MaxConcurrentWorkflows int `json:"maxConcurrentWorkflows,omitempty" validate:"gte=0"`
+		// MaxStorageBytes caps the JSON size of the namespace's stored schema versions and
+		// package manifests. Execution data is bounded by retention policies instead.
+		MaxStorageBytes int64 `json:"maxStorageBytes,omitempty" validate:"gte=0"`
+	}
+)
+
+// NewNamespace creates a Namespace without quotas.
+func NewNamespace(name, description string) *Namespace {
+	return &Namespace{Name: name, Description: description}
+}
+
+// Validate checks the namespace's fields and name format.
+func (n *Namespace) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	if err := validate.Struct(n); err != nil {
+		return err
+	}
+	return ValidateNamespaceName(n.Name)
+}
+
+// ValidateNamespaceName returns an error when name is not a well-formed namespace name.
+func ValidateNamespaceName(name string) error {
+	if !namespaceNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid namespace name %q: must match %s", name, namespaceNamePattern.String())
+	}
+	return nil
+}
+
+// QualifyID returns the ID of a schema or package in namespace. IDs in the default namespace are
+// left bare.
+func QualifyID(namespace, id string) string {
+	if namespace == "" || namespace == DefaultNamespaceName {
+		return id
+	}
+	return namespace + NamespaceSeparator + id
+}
+
+// SplitQualifiedID returns the namespace and the unqualified ID of a schema or package ID.
+func SplitQualifiedID(id string) (namespace, local string) {
+	if ns, rest, found := strings.Cut(id, NamespaceSeparator); found && ns != "" {
+		return ns, rest
+	}
+	return DefaultNamespaceName, id
+}
+
+// ValidateQualifiedID returns an error when id cannot be read back as the schema or package ID it
+// was stored as: its unqualified part must be non-empty and free of the separator, and a qualifying
+// namespace must be a well-formed name other than the default one (whose IDs are left bare).
+func ValidateQualifiedID(id string) error {
+	namespace, local := SplitQualifiedID(id)
+	if local == "" || strings.Contains(local, NamespaceSeparator) || strings.HasPrefix(id, NamespaceSeparator) {
+		return fmt.Errorf("invalid ID %q: IDs must not contain the namespace separator %q", id, NamespaceSeparator)
+	}
+	if id == local {
+		return nil
+	}
+	if namespace == DefaultNamespaceName {
+		return fmt.Errorf("invalid ID %q: IDs of the %s namespace are not qualified", id, DefaultNamespaceName)
+	}
+	return ValidateNamespaceName(namespace)
+}
+
+// NamespaceOf returns the namespace of a schema or package ID.
+func NamespaceOf(id string) string {
+	namespace, _ := SplitQualifiedID(id)
+	return namespace
+}
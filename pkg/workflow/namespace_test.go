@@ -0,0 +1,69 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespaceValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		ns      *Namespace
+		wantErr bool
+	}{
+		{name: "valid", ns: NewNamespace("billing", "Billing unit"), wantErr: false},
+		{name: "default", ns: NewNamespace(DefaultNamespaceName, ""), wantErr: false},
+		{name: "with quota", ns: &Namespace{Name: "ops", Quota: NamespaceQuota{MaxConcurrentWorkflows: 10, MaxStorageBytes: 1 << 20}}, wantErr: false},
+		{name: "empty name", ns: NewNamespace("", ""), wantErr: true},
+		{name: "separator rejected", ns: NewNamespace("a:b", ""), wantErr: true},
+		{name: "uppercase rejected", ns: NewNamespace("Billing", ""), wantErr: true},
+		{name: "negative quota", ns: &Namespace{Name: "ops", Quota: NamespaceQuota{MaxConcurrentWorkflows: -1}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.ns.Validate()
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestQualifiedIDs(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "invoice", QualifyID(DefaultNamespaceName, "invoice"))
+	assert.Equal(t, "invoice", QualifyID("", "invoice"))
+	assert.Equal(t, "billing:invoice", QualifyID("billing", "invoice"))
+
+	ns, local := SplitQualifiedID("billing:invoice")
+	assert.Equal(t, "billing", ns)
+	assert.Equal(t, "invoice", local)
+
+	ns, local = SplitQualifiedID("fuse/pkg/logic")
+	assert.Equal(t, DefaultNamespaceName, ns)
+	assert.Equal(t, "fuse/pkg/logic", local)
+
+	assert.Equal(t, "billing", NamespaceOf("billing:tools/pkg"))
+	assert.Equal(t, DefaultNamespaceName, NamespaceOf(":odd"))
+}
+
+func TestValidateQualifiedID(t *testing.T) {
+	t.Parallel()
+
+	for _, id := range []string{"invoice", "fuse/pkg/logic", "billing:invoice", "billing:tools/pkg"} {
+		assert.NoError(t, ValidateQualifiedID(id), id)
+	}
+	for _, id := range []string{"", ":odd", "billing:", "billing:a:b", "default:invoice", "Billing:invoice"} {
+		assert.Error(t, ValidateQualifiedID(id), id)
+	}
+}
@@ -202,7 +202,7 @@ func TestE2E_POST_v1_workflows_execs_asyncResult(t *testing.T) {
 	t.Parallel()
 	client, base := RequireE2E(t)
 
-	// Arrange — valid body; workflow handler may not exist → 500 is acceptable for smoke
+	// Arrange — valid body but no callback token
 	wfID := uuid.New().String()
 	execID := "e2e-exec-" + uuid.New().String()[:8]
 	url := fmt.Sprintf("%s/v1/workflows/%s/execs/%s", base, wfID, execID)
@@ -211,17 +211,14 @@ func TestE2E_POST_v1_workflows_execs_asyncResult(t *testing.T) {
 	// Act
 	code, respBody, err := POSTJSON(client, url, body)
 
-	// Assert — endpoint accepts JSON; delivery may fail if no running workflow actor
+	// Assert — results are only accepted with the execution's signed callback token
 	require.NoError(t, err)
-	assert.Contains(t, []int{http.StatusOK, http.StatusInternalServerError}, code,
-		"async result endpoint should respond; body=%s", string(respBody))
-	if code == http.StatusOK {
-		var resp struct {
-			Code string `json:"code"`
-		}
-		require.NoError(t, json.Unmarshal(respBody, &resp))
-		assert.Equal(t, "OK", resp.Code)
+	assert.Equal(t, http.StatusForbidden, code, "body=%s", string(respBody))
+	var resp struct {
+		Code string `json:"code"`
 	}
+	require.NoError(t, json.Unmarshal(respBody, &resp))
+	assert.Equal(t, "FORBIDDEN", resp.Code)
 }
 
 func TestE2E_POST_v1_awakeables_resolve_notFound(t *testing.T) {
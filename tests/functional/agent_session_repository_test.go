@@ -0,0 +1,104 @@
+package functional_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/pkg/llm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func contractTestAgentSessionRepository(t *testing.T, newRepo func() repositories.AgentSessionRepository, reset func()) {
+	t.Helper()
+
+	newSession := func(namespace, id string, expiresAt time.Time, messages ...llm.Message) *repositories.AgentSession {
+		now := time.Now().UTC().Truncate(time.Millisecond)
+		return &repositories.AgentSession{
+			Namespace: namespace,
+			ID:        id,
+			Messages:  messages,
+			CreatedAt: now,
+			UpdatedAt: now,
+			ExpiresAt: expiresAt.UTC().Truncate(time.Millisecond),
+		}
+	}
+
+	t.Run("Save and Get round-trip the transcript", func(t *testing.T) {
+		reset()
+		repo := newRepo()
+		session := newSession("default", "s-1", time.Now().Add(time.Hour),
+			llm.Message{Role: llm.RoleUser, Content: "where is my order?"},
+			llm.Message{Role: llm.RoleAssistant, Content: "It shipped yesterday."},
+		)
+		require.NoError(t, repo.Save(session))
+
+		got, err := repo.Get("default", "s-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, session.Messages, got.Messages)
+		assert.True(t, session.ExpiresAt.Equal(got.ExpiresAt))
+	})
+
+	t.Run("sessions are scoped by namespace", func(t *testing.T) {
+		reset()
+		repo := newRepo()
+		require.NoError(t, repo.Save(newSession("billing", "s-1", time.Now().Add(time.Hour))))
+
+		_, err := repo.Get("default", "s-1")
+
+		assert.ErrorIs(t, err, repositories.ErrAgentSessionNotFound)
+	})
+
+	t.Run("Save replaces the transcript and keeps CreatedAt", func(t *testing.T) {
+		reset()
+		repo := newRepo()
+		first := newSession("default", "s-1", time.Now().Add(time.Hour), llm.Message{Role: llm.RoleUser, Content: "one"})
+		require.NoError(t, repo.Save(first))
+		second := newSession("default", "s-1", time.Now().Add(2*time.Hour), llm.Message{Role: llm.RoleUser, Content: "two"})
+		second.CreatedAt = first.CreatedAt.Add(time.Minute)
+		require.NoError(t, repo.Save(second))
+
+		got, err := repo.Get("default", "s-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, "two", got.Messages[0].Content)
+		assert.True(t, first.CreatedAt.Equal(got.CreatedAt))
+	})
+
+	t.Run("expired sessions are not found and DeleteExpired purges them", func(t *testing.T) {
+		reset()
+		repo := newRepo()
+		now := time.Now()
+		require.NoError(t, repo.Save(newSession("default", "old", now.Add(-time.Minute))))
+		require.NoError(t, repo.Save(newSession("default", "live", now.Add(time.Hour))))
+
+		_, err := repo.Get("default", "old")
+		assert.ErrorIs(t, err, repositories.ErrAgentSessionNotFound)
+
+		deleted, err := repo.DeleteExpired(now)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+		_, err = repo.Get("default", "live")
+		assert.NoError(t, err)
+	})
+
+	t.Run("Delete removes a session", func(t *testing.T) {
+		reset()
+		repo := newRepo()
+		require.NoError(t, repo.Save(newSession("default", "s-1", time.Now().Add(time.Hour))))
+
+		require.NoError(t, repo.Delete("default", "s-1"))
+
+		_, err := repo.Get("default", "s-1")
+		assert.ErrorIs(t, err, repositories.ErrAgentSessionNotFound)
+		assert.ErrorIs(t, repo.Delete("default", "s-1"), repositories.ErrAgentSessionNotFound)
+	})
+}
+
+func TestMemoryAgentSessionRepository_Contract(t *testing.T) {
+	contractTestAgentSessionRepository(t, func() repositories.AgentSessionRepository {
+		return repositories.NewMemoryAgentSessionRepository()
+	}, func() {})
+}
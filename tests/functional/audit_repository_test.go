@@ -0,0 +1,79 @@
+package functional_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/open-source-cloud/fuse/internal/audit"
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/pkg/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func contractTestAuditRepository(t *testing.T, newRepo func() repositories.AuditRepository, reset func()) {
+	t.Helper()
+
+	t.Run("Append and Find round-trip every field", func(t *testing.T) {
+		reset()
+		repo := newRepo()
+		entry := &audit.Entry{
+			ID: uuid.V7(), Time: time.Now().UTC().Truncate(time.Microsecond),
+			Actor: "apikey:ak1", AuthMethod: "api_key", Action: audit.ActionCredentialSave,
+			ResourceType: audit.ResourceCredential, Resource: "openai", Namespace: "billing", Environment: "prod",
+			BeforeHash: audit.Hash("before"), AfterHash: audit.Hash("after"),
+			Details: map[string]string{"fields": "apiKey"},
+			Request: &audit.Request{Method: "PUT", Path: "/v1/ns/billing/credentials/openai", RemoteAddr: "10.0.0.1:5000"},
+		}
+		require.NoError(t, repo.Append(entry))
+
+		entries, err := repo.Find(audit.Filter{Resource: "openai"})
+
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, entry.ID, entries[0].ID)
+		assert.True(t, entry.Time.Equal(entries[0].Time))
+		entries[0].Time = entry.Time
+		assert.Equal(t, entry, entries[0])
+	})
+
+	t.Run("Find filters and pages newest first", func(t *testing.T) {
+		reset()
+		repo := newRepo()
+		ids := make([]string, 0, 4)
+		for i, action := range []audit.Action{audit.ActionSchemaUpsert, audit.ActionSchemaActivate, audit.ActionSchemaUpsert, audit.ActionWorkflowCancel} {
+			id := uuid.V7()
+			ids = append(ids, id)
+			require.NoError(t, repo.Append(&audit.Entry{
+				ID: id, Time: time.Now().UTC().Add(time.Duration(i) * time.Second), Actor: "alice",
+				Action: action, ResourceType: audit.ResourceSchema, Resource: "orders",
+			}))
+		}
+
+		upserts, err := repo.Find(audit.Filter{Action: audit.ActionSchemaUpsert})
+		require.NoError(t, err)
+		require.Len(t, upserts, 2)
+		assert.Equal(t, ids[2], upserts[0].ID)
+		assert.Nil(t, upserts[0].Request)
+
+		page, err := repo.Find(audit.Filter{Actor: "alice", Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, page, 2)
+		assert.Equal(t, []string{ids[3], ids[2]}, []string{page[0].ID, page[1].ID})
+
+		next, err := repo.Find(audit.Filter{Actor: "alice", Limit: 2, Before: page[1].ID})
+		require.NoError(t, err)
+		require.Len(t, next, 2)
+		assert.Equal(t, []string{ids[1], ids[0]}, []string{next[0].ID, next[1].ID})
+
+		none, err := repo.Find(audit.Filter{Actor: "bob"})
+		require.NoError(t, err)
+		assert.Empty(t, none)
+	})
+}
+
+func TestMemoryAuditRepository_Contract(t *testing.T) {
+	contractTestAuditRepository(t, func() repositories.AuditRepository {
+		return repositories.NewMemoryAuditRepository()
+	}, func() {})
+}
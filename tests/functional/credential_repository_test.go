@@ -11,13 +11,14 @@ import (
 
 func contractTestCredentialRepository(t *testing.T, newRepo func() repositories.CredentialRepository, reset func()) {
 	t.Helper()
+	ns := workflow.DefaultNamespaceName
 
 	t.Run("Save and FindByID returns same credential incl. fields array", func(t *testing.T) {
 		reset()
 		repo := newRepo()
 
-		require.NoError(t, repo.Save(workflow.NewCredential("openai-prod", "openai", "Prod creds", []string{"apiKey", "baseUrl"})))
-		found, err := repo.FindByID("openai-prod")
+		require.NoError(t, repo.Save(ns, workflow.NewCredential("openai-prod", "openai", "Prod creds", []string{"apiKey", "baseUrl"})))
+		found, err := repo.FindByID(ns, "openai-prod")
 
 		require.NoError(t, err)
 		assert.Equal(t, "openai", found.Type)
@@ -28,17 +29,17 @@ func contractTestCredentialRepository(t *testing.T, newRepo func() repositories.
 	t.Run("FindByID returns ErrCredentialNotFound for unknown", func(t *testing.T) {
 		reset()
 		repo := newRepo()
-		_, err := repo.FindByID("nonexistent")
+		_, err := repo.FindByID(ns, "nonexistent")
 		assert.ErrorIs(t, err, repositories.ErrCredentialNotFound)
 	})
 
 	t.Run("FindAll returns saved credentials", func(t *testing.T) {
 		reset()
 		repo := newRepo()
-		require.NoError(t, repo.Save(workflow.NewCredential("a-cred", "custom", "", []string{"token"})))
-		require.NoError(t, repo.Save(workflow.NewCredential("b-cred", "custom", "", nil)))
+		require.NoError(t, repo.Save(ns, workflow.NewCredential("a-cred", "custom", "", []string{"token"})))
+		require.NoError(t, repo.Save(ns, workflow.NewCredential("b-cred", "custom", "", nil)))
 
-		all, err := repo.FindAll()
+		all, err := repo.FindAll(ns)
 		require.NoError(t, err)
 		ids := make([]string, len(all))
 		for i, c := range all {
@@ -51,10 +52,10 @@ func contractTestCredentialRepository(t *testing.T, newRepo func() repositories.
 	t.Run("Save overwrites metadata", func(t *testing.T) {
 		reset()
 		repo := newRepo()
-		require.NoError(t, repo.Save(workflow.NewCredential("c1", "openai", "old", []string{"apiKey"})))
-		require.NoError(t, repo.Save(workflow.NewCredential("c1", "openai", "new", []string{"apiKey", "baseUrl"})))
+		require.NoError(t, repo.Save(ns, workflow.NewCredential("c1", "openai", "old", []string{"apiKey"})))
+		require.NoError(t, repo.Save(ns, workflow.NewCredential("c1", "openai", "new", []string{"apiKey", "baseUrl"})))
 
-		found, err := repo.FindByID("c1")
+		found, err := repo.FindByID(ns, "c1")
 		require.NoError(t, err)
 		assert.Equal(t, "new", found.Description)
 		assert.Equal(t, []string{"apiKey", "baseUrl"}, found.Fields)
@@ -63,12 +64,27 @@ func contractTestCredentialRepository(t *testing.T, newRepo func() repositories.
 	t.Run("Delete removes credential", func(t *testing.T) {
 		reset()
 		repo := newRepo()
-		require.NoError(t, repo.Save(workflow.NewCredential("c1", "custom", "", nil)))
+		require.NoError(t, repo.Save(ns, workflow.NewCredential("c1", "custom", "", nil)))
 
-		require.NoError(t, repo.Delete("c1"))
-		_, err := repo.FindByID("c1")
+		require.NoError(t, repo.Delete(ns, "c1"))
+		_, err := repo.FindByID(ns, "c1")
 		assert.ErrorIs(t, err, repositories.ErrCredentialNotFound)
 	})
+
+	t.Run("namespaces are isolated", func(t *testing.T) {
+		reset()
+		repo := newRepo()
+		require.NoError(t, repo.Save("billing", workflow.NewCredential("c1", "stripe", "", nil)))
+
+		_, err := repo.FindByID(ns, "c1")
+		assert.ErrorIs(t, err, repositories.ErrCredentialNotFound)
+		all, err := repo.FindAll(ns)
+		require.NoError(t, err)
+		assert.Empty(t, all)
+		found, err := repo.FindByID("billing", "c1")
+		require.NoError(t, err)
+		assert.Equal(t, "stripe", found.Type)
+	})
 }
 
 func TestMemoryCredentialRepository_Contract(t *testing.T) {
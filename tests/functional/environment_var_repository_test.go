@@ -0,0 +1,69 @@
+package functional_test
+
+import (
+	"testing"
+
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func contractTestEnvironmentVarRepository(t *testing.T, newRepo func() repositories.EnvironmentVarRepository, reset func()) {
+	t.Helper()
+	ns := workflow.DefaultNamespaceName
+
+	t.Run("Set and FindAll round-trip per environment", func(t *testing.T) {
+		reset()
+		repo := newRepo()
+		require.NoError(t, repo.Set(ns, "prod", "API_BASE_URL", "https://api.example.com"))
+		require.NoError(t, repo.Set(ns, "prod", "BUCKET", "orders-prod"))
+		require.NoError(t, repo.Set(ns, "staging", "BUCKET", "orders-staging"))
+
+		vars, err := repo.FindAll(ns, "prod")
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"API_BASE_URL": "https://api.example.com", "BUCKET": "orders-prod"}, vars)
+	})
+
+	t.Run("Set overwrites a value", func(t *testing.T) {
+		reset()
+		repo := newRepo()
+		require.NoError(t, repo.Set(ns, "prod", "FLAG", "off"))
+		require.NoError(t, repo.Set(ns, "prod", "FLAG", "on"))
+
+		vars, err := repo.FindAll(ns, "prod")
+
+		require.NoError(t, err)
+		assert.Equal(t, "on", vars["FLAG"])
+	})
+
+	t.Run("namespaces are isolated", func(t *testing.T) {
+		reset()
+		repo := newRepo()
+		require.NoError(t, repo.Set("billing", "prod", "FLAG", "on"))
+
+		vars, err := repo.FindAll(ns, "prod")
+
+		require.NoError(t, err)
+		assert.Empty(t, vars)
+	})
+
+	t.Run("Delete removes a variable", func(t *testing.T) {
+		reset()
+		repo := newRepo()
+		require.NoError(t, repo.Set(ns, "prod", "FLAG", "on"))
+
+		require.NoError(t, repo.Delete(ns, "prod", "FLAG"))
+
+		vars, err := repo.FindAll(ns, "prod")
+		require.NoError(t, err)
+		assert.Empty(t, vars)
+	})
+}
+
+func TestMemoryEnvironmentVarRepository_Contract(t *testing.T) {
+	contractTestEnvironmentVarRepository(t, func() repositories.EnvironmentVarRepository {
+		return repositories.NewMemoryEnvironmentVarRepository()
+	}, func() {})
+}
@@ -0,0 +1,83 @@
+package functional_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func contractTestLLMSpendRepository(t *testing.T, newRepo func() repositories.LLMSpendRepository, reset func()) {
+	t.Helper()
+
+	t.Run("AddExecution accumulates and returns the total", func(t *testing.T) {
+		reset()
+		repo := newRepo()
+		_, err := repo.AddExecution("wf-1", repositories.LLMSpend{CostUSD: 0.5, Tokens: 100})
+		require.NoError(t, err)
+
+		total, err := repo.AddExecution("wf-1", repositories.LLMSpend{CostUSD: 0.25, Tokens: 20})
+
+		require.NoError(t, err)
+		assert.InDelta(t, 0.75, total.CostUSD, 1e-9)
+		assert.Equal(t, 120, total.Tokens)
+		stored, err := repo.GetExecution("wf-1")
+		require.NoError(t, err)
+		assert.Equal(t, total, stored)
+	})
+
+	t.Run("GetExecution of an execution without spend is zero", func(t *testing.T) {
+		reset()
+		total, err := newRepo().GetExecution("missing")
+		require.NoError(t, err)
+		assert.Zero(t, total)
+	})
+
+	t.Run("daily totals are scoped by schema, environment and UTC date", func(t *testing.T) {
+		reset()
+		repo := newRepo()
+		day := time.Date(2026, 5, 4, 9, 30, 0, 0, time.UTC)
+		_, err := repo.AddDaily("orders", "prod", day, repositories.LLMSpend{CostUSD: 1, Tokens: 10})
+		require.NoError(t, err)
+		_, err = repo.AddDaily("orders", "prod", day.Add(12*time.Hour), repositories.LLMSpend{CostUSD: 2, Tokens: 20})
+		require.NoError(t, err)
+		_, err = repo.AddDaily("orders", "prod", day.Add(24*time.Hour), repositories.LLMSpend{CostUSD: 4, Tokens: 40})
+		require.NoError(t, err)
+		_, err = repo.AddDaily("orders", "staging", day, repositories.LLMSpend{CostUSD: 8, Tokens: 80})
+		require.NoError(t, err)
+
+		total, err := repo.GetDaily("orders", "prod", day)
+
+		require.NoError(t, err)
+		assert.InDelta(t, 3.0, total.CostUSD, 1e-9)
+		assert.Equal(t, 30, total.Tokens)
+	})
+
+	t.Run("DeleteByWorkflowIDs removes execution totals", func(t *testing.T) {
+		reset()
+		repo := newRepo()
+		_, err := repo.AddExecution("wf-1", repositories.LLMSpend{Tokens: 1})
+		require.NoError(t, err)
+		_, err = repo.AddExecution("wf-2", repositories.LLMSpend{Tokens: 2})
+		require.NoError(t, err)
+
+		deleted, err := repo.DeleteByWorkflowIDs([]string{"wf-1"})
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+		gone, err := repo.GetExecution("wf-1")
+		require.NoError(t, err)
+		assert.Zero(t, gone)
+		kept, err := repo.GetExecution("wf-2")
+		require.NoError(t, err)
+		assert.Equal(t, 2, kept.Tokens)
+	})
+}
+
+func TestMemoryLLMSpendRepository_Contract(t *testing.T) {
+	contractTestLLMSpendRepository(t, func() repositories.LLMSpendRepository {
+		return repositories.NewMemoryLLMSpendRepository()
+	}, func() {})
+}
@@ -0,0 +1,87 @@
+package functional_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/open-source-cloud/fuse/internal/repositories"
+	"github.com/open-source-cloud/fuse/pkg/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func contractTestMCPServerRepository(t *testing.T, newRepo func() repositories.MCPServerRepository, reset func()) {
+	t.Helper()
+
+	t.Run("Save and FindByID round-trip the registration", func(t *testing.T) {
+		reset()
+		repo := newRepo()
+		server := &mcp.ServerConfig{
+			ID:          "wiki",
+			Description: "Internal wiki",
+			Transport:   mcp.TransportHTTP,
+			URL:         "https://wiki.example.com/mcp",
+			Headers:     map[string]string{"Authorization": "Bearer {{secret:WIKI_TOKEN}}"},
+			Timeout:     45 * time.Second,
+			Tools:       []string{"search"},
+		}
+		require.NoError(t, repo.Save("default", server))
+
+		got, err := repo.FindByID("default", "wiki")
+
+		require.NoError(t, err)
+		assert.Equal(t, server, got)
+		_, err = repo.FindByID("billing", "wiki")
+		assert.ErrorIs(t, err, repositories.ErrMCPServerNotFound)
+	})
+
+	t.Run("Save replaces a registration", func(t *testing.T) {
+		reset()
+		repo := newRepo()
+		require.NoError(t, repo.Save("default", &mcp.ServerConfig{ID: "git", Transport: mcp.TransportStdio, Command: "mcp-git"}))
+		require.NoError(t, repo.Save("default", &mcp.ServerConfig{ID: "git", Transport: mcp.TransportStdio, Command: "mcp-git", Args: []string{"--repo", "/srv"}}))
+
+		got, err := repo.FindByID("default", "git")
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"--repo", "/srv"}, got.Args)
+	})
+
+	t.Run("FindAll lists a namespace sorted by id", func(t *testing.T) {
+		reset()
+		repo := newRepo()
+		for _, id := range []string{"wiki", "git", "jira"} {
+			require.NoError(t, repo.Save("default", &mcp.ServerConfig{ID: id, Transport: mcp.TransportHTTP, URL: "https://" + id + ".example.com/mcp"}))
+		}
+		require.NoError(t, repo.Save("billing", &mcp.ServerConfig{ID: "ledger", Transport: mcp.TransportHTTP, URL: "https://ledger.example.com/mcp"}))
+
+		servers, err := repo.FindAll("default")
+
+		require.NoError(t, err)
+		ids := make([]string, len(servers))
+		for i, s := range servers {
+			ids[i] = s.ID
+		}
+		assert.Equal(t, []string{"git", "jira", "wiki"}, ids)
+		empty, err := repo.FindAll("empty")
+		require.NoError(t, err)
+		assert.Empty(t, empty)
+	})
+
+	t.Run("Delete removes a registration", func(t *testing.T) {
+		reset()
+		repo := newRepo()
+		require.NoError(t, repo.Save("default", &mcp.ServerConfig{ID: "wiki", Transport: mcp.TransportHTTP, URL: "https://wiki.example.com/mcp"}))
+
+		require.NoError(t, repo.Delete("default", "wiki"))
+
+		_, err := repo.FindByID("default", "wiki")
+		assert.ErrorIs(t, err, repositories.ErrMCPServerNotFound)
+	})
+}
+
+func TestMemoryMCPServerRepository_Contract(t *testing.T) {
+	contractTestMCPServerRepository(t, func() repositories.MCPServerRepository {
+		return repositories.NewMemoryMCPServerRepository()
+	}, func() {})
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/open-source-cloud/fuse/internal/repositories/postgres"
 	internalworkflow "github.com/open-source-cloud/fuse/internal/workflow"
 	"github.com/open-source-cloud/fuse/pkg/objectstore"
+	"github.com/open-source-cloud/fuse/pkg/vectorstore"
 	"github.com/open-source-cloud/fuse/pkg/workflow"
 	"github.com/stretchr/testify/require"
 )
@@ -196,6 +197,82 @@ func TestPostgresEnvironmentRepository_Contract(t *testing.T) {
 	})
 }
 
+// --- Postgres Environment Var Repository ---
+
+func TestPostgresEnvironmentVarRepository_Contract(t *testing.T) {
+	pool := setupTestPool(t)
+	contractTestEnvironmentVarRepository(t, func() repositories.EnvironmentVarRepository {
+		return postgres.NewEnvironmentVarRepository(pool)
+	}, func() {
+		_, err := pool.Exec(context.Background(), "TRUNCATE TABLE environment_vars")
+		require.NoError(t, err)
+	})
+}
+
+// --- Postgres Audit Repository ---
+
+func TestPostgresAuditRepository_Contract(t *testing.T) {
+	pool := setupTestPool(t)
+	contractTestAuditRepository(t, func() repositories.AuditRepository {
+		return postgres.NewAuditRepository(pool)
+	}, func() {
+		_, err := pool.Exec(context.Background(), "TRUNCATE TABLE audit_log")
+		require.NoError(t, err)
+	})
+}
+
+// --- Postgres LLM Spend Repository ---
+
+func TestPostgresLLMSpendRepository_Contract(t *testing.T) {
+	pool := setupTestPool(t)
+	contractTestLLMSpendRepository(t, func() repositories.LLMSpendRepository {
+		return postgres.NewLLMSpendRepository(pool)
+	}, func() {
+		_, err := pool.Exec(context.Background(), "TRUNCATE TABLE llm_execution_spend, llm_daily_spend")
+		require.NoError(t, err)
+	})
+}
+
+// --- Postgres Agent Session Repository ---
+
+func TestPostgresAgentSessionRepository_Contract(t *testing.T) {
+	pool := setupTestPool(t)
+	contractTestAgentSessionRepository(t, func() repositories.AgentSessionRepository {
+		return postgres.NewAgentSessionRepository(pool)
+	}, func() {
+		_, err := pool.Exec(context.Background(), "TRUNCATE TABLE agent_sessions")
+		require.NoError(t, err)
+	})
+}
+
+// --- Postgres MCP Server Repository ---
+
+func TestPostgresMCPServerRepository_Contract(t *testing.T) {
+	pool := setupTestPool(t)
+	contractTestMCPServerRepository(t, func() repositories.MCPServerRepository {
+		return postgres.NewMCPServerRepository(pool)
+	}, func() {
+		_, err := pool.Exec(context.Background(), "TRUNCATE TABLE mcp_servers")
+		require.NoError(t, err)
+	})
+}
+
+// --- Postgres Vector Store ---
+
+func TestPostgresVectorStore_Contract(t *testing.T) {
+	pool := setupTestPool(t)
+	store := postgres.NewVectorStore(pool)
+	if err := store.Available(context.Background()); err != nil {
+		t.Skipf("pgvector not installed: %v", err)
+	}
+	contractTestVectorStore(t, func() vectorstore.VectorStore {
+		return store
+	}, func() {
+		_, err := pool.Exec(context.Background(), "TRUNCATE TABLE vector_chunks")
+		require.NoError(t, err)
+	})
+}
+
 // --- Postgres Credential Repository ---
 
 func TestPostgresCredentialRepository_Contract(t *testing.T) {
@@ -19,10 +19,10 @@ func TestPostgresSecretStore_EncryptedRoundTrip(t *testing.T) {
 	_, _ = pool.Exec(ctx, "TRUNCATE TABLE secrets")
 
 	key := base64.StdEncoding.EncodeToString(make([]byte, 32))
-	cipher, err := secrets.NewCipherFromBase64Key(key)
+	keyring, err := secrets.ParseKeyring("", "", key)
 	require.NoError(t, err)
 
-	store := postgres.NewSecretStore(pool, cipher)
+	store := postgres.NewSecretStore(pool, keyring)
 	scope := secrets.Scope{Environment: "prod"}
 
 	// Not found initially.
@@ -47,9 +47,10 @@ func TestPostgresSecretStore_EncryptedRoundTrip(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "rotated", v.Reveal())
 
-	// List is scoped to the environment.
+	// List is scoped to the namespace and environment.
 	require.NoError(t, store.Set(ctx, secrets.Scope{Environment: "dev"}, "other", "x"))
-	names, err := store.List(ctx, "prod")
+	require.NoError(t, store.Set(ctx, secrets.Scope{Namespace: "billing", Environment: "prod"}, "other", "x"))
+	names, err := store.List(ctx, scope)
 	require.NoError(t, err)
 	assert.Equal(t, []string{"api-key"}, names)
 
@@ -58,3 +59,55 @@ func TestPostgresSecretStore_EncryptedRoundTrip(t *testing.T) {
 	_, err = store.Resolve(ctx, scope, "api-key")
 	require.ErrorIs(t, err, secrets.ErrSecretNotFound)
 }
+
+func TestPostgresSecretStore_RotateKeys(t *testing.T) {
+	pool := setupTestPool(t)
+	ctx := context.Background()
+	_, _ = pool.Exec(ctx, "TRUNCATE TABLE secrets")
+
+	oldKey := base64.StdEncoding.EncodeToString(make([]byte, 32))
+	newKey := base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+	scope := secrets.Scope{Environment: "prod"}
+
+	// A row written before envelope encryption: sealed directly with SECRETS_ENCRYPTION_KEY.
+	legacy, err := secrets.NewCipherFromBase64Key(oldKey)
+	require.NoError(t, err)
+	enc, err := legacy.Encrypt([]byte("legacy-value"))
+	require.NoError(t, err)
+	_, err = pool.Exec(ctx, `INSERT INTO secrets (namespace, environment, name, encrypted_value) VALUES ('default', 'prod', 'legacy', $1)`, enc)
+	require.NoError(t, err)
+
+	oldRing, err := secrets.ParseKeyring("", "", oldKey)
+	require.NoError(t, err)
+	oldStore := postgres.NewSecretStore(pool, oldRing)
+	for _, name := range []string{"a", "b", "c"} {
+		require.NoError(t, oldStore.Set(ctx, scope, name, "value-"+name))
+	}
+
+	// The new key becomes active; the old one stays decrypt-only while rotation runs.
+	rotating, err := secrets.ParseKeyring("k2:"+newKey, "k2", oldKey)
+	require.NoError(t, err)
+	store := postgres.NewSecretStore(pool, rotating)
+	v, err := store.Resolve(ctx, scope, "legacy")
+	require.NoError(t, err)
+	assert.Equal(t, "legacy-value", v.Reveal())
+
+	rotated, err := store.RotateKeys(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 4, rotated)
+	rotated, err = store.RotateKeys(ctx, 2)
+	require.NoError(t, err)
+	assert.Zero(t, rotated, "rotation is idempotent")
+
+	// With the old key removed every secret still resolves.
+	newRing, err := secrets.ParseKeyring("k2:"+newKey, "", "")
+	require.NoError(t, err)
+	newStore := postgres.NewSecretStore(pool, newRing)
+	for name, want := range map[string]string{"legacy": "legacy-value", "a": "value-a", "b": "value-b", "c": "value-c"} {
+		v, err := newStore.Resolve(ctx, scope, name)
+		require.NoError(t, err, name)
+		assert.Equal(t, want, v.Reveal())
+	}
+	_, err = oldStore.Resolve(ctx, scope, "a")
+	assert.ErrorIs(t, err, secrets.ErrUnknownKeyID)
+}
@@ -0,0 +1,98 @@
+package functional_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/open-source-cloud/fuse/pkg/vectorstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func contractTestVectorStore(t *testing.T, newStore func() vectorstore.VectorStore, reset func()) {
+	t.Helper()
+	ctx := context.Background()
+
+	t.Run("Query returns the nearest chunks first", func(t *testing.T) {
+		reset()
+		store := newStore()
+		require.NoError(t, store.Upsert(ctx, "default", "docs", []vectorstore.Chunk{
+			{ID: "refunds", Content: "Refunds take 5 days.", Metadata: map[string]any{"source": "faq.md"}, Vector: []float32{1, 0, 0}},
+			{ID: "shipping", Content: "We ship worldwide.", Vector: []float32{0, 1, 0}},
+			{ID: "returns", Content: "Returns within 30 days.", Vector: []float32{0.9, 0.1, 0}},
+		}))
+
+		matches, err := store.Query(ctx, "default", "docs", []float32{1, 0, 0}, 2)
+
+		require.NoError(t, err)
+		require.Len(t, matches, 2)
+		assert.Equal(t, "refunds", matches[0].ID)
+		assert.Equal(t, "Refunds take 5 days.", matches[0].Content)
+		assert.Equal(t, map[string]any{"source": "faq.md"}, matches[0].Metadata)
+		assert.InDelta(t, 1.0, matches[0].Score, 1e-5)
+		assert.Equal(t, "returns", matches[1].ID)
+		assert.Less(t, matches[1].Score, matches[0].Score)
+	})
+
+	t.Run("Upsert replaces a chunk by ID", func(t *testing.T) {
+		reset()
+		store := newStore()
+		require.NoError(t, store.Upsert(ctx, "default", "docs", []vectorstore.Chunk{{ID: "a", Content: "old", Vector: []float32{1, 0}}}))
+		require.NoError(t, store.Upsert(ctx, "default", "docs", []vectorstore.Chunk{{ID: "a", Content: "new", Vector: []float32{0, 1}}}))
+
+		matches, err := store.Query(ctx, "default", "docs", []float32{0, 1}, 5)
+
+		require.NoError(t, err)
+		require.Len(t, matches, 1)
+		assert.Equal(t, "new", matches[0].Content)
+		assert.InDelta(t, 1.0, matches[0].Score, 1e-5)
+	})
+
+	t.Run("Collections are scoped by namespace and name", func(t *testing.T) {
+		reset()
+		store := newStore()
+		require.NoError(t, store.Upsert(ctx, "team-a", "docs", []vectorstore.Chunk{{ID: "a", Vector: []float32{1, 0}}}))
+
+		other, err := store.Query(ctx, "team-b", "docs", []float32{1, 0}, 5)
+		require.NoError(t, err)
+		assert.Empty(t, other)
+		unknown, err := store.Query(ctx, "team-a", "notes", []float32{1, 0}, 5)
+		require.NoError(t, err)
+		assert.Empty(t, unknown)
+	})
+
+	t.Run("Dimensions are fixed per collection", func(t *testing.T) {
+		reset()
+		store := newStore()
+		require.NoError(t, store.Upsert(ctx, "default", "docs", []vectorstore.Chunk{{ID: "a", Vector: []float32{1, 0}}}))
+
+		err := store.Upsert(ctx, "default", "docs", []vectorstore.Chunk{{ID: "b", Vector: []float32{1, 0, 0}}})
+		require.ErrorIs(t, err, vectorstore.ErrDimensionMismatch)
+		_, err = store.Query(ctx, "default", "docs", []float32{1, 0, 0}, 5)
+		require.ErrorIs(t, err, vectorstore.ErrDimensionMismatch)
+		err = store.Upsert(ctx, "default", "docs", []vectorstore.Chunk{{ID: "c"}})
+		require.ErrorIs(t, err, vectorstore.ErrEmptyVector)
+	})
+
+	t.Run("Delete removes chunks and ignores unknown IDs", func(t *testing.T) {
+		reset()
+		store := newStore()
+		require.NoError(t, store.Upsert(ctx, "default", "docs", []vectorstore.Chunk{
+			{ID: "a", Vector: []float32{1, 0}},
+			{ID: "b", Vector: []float32{0, 1}},
+		}))
+
+		require.NoError(t, store.Delete(ctx, "default", "docs", []string{"a", "missing"}))
+
+		matches, err := store.Query(ctx, "default", "docs", []float32{1, 0}, 5)
+		require.NoError(t, err)
+		require.Len(t, matches, 1)
+		assert.Equal(t, "b", matches[0].ID)
+	})
+}
+
+func TestMemoryVectorStore_Contract(t *testing.T) {
+	contractTestVectorStore(t, func() vectorstore.VectorStore {
+		return vectorstore.NewMemoryVectorStore()
+	}, func() {})
+}
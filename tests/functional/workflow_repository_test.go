@@ -52,6 +52,32 @@ func contractTestWorkflowRepository(t *testing.T, newRepo func() repositories.Wo
 		assert.Equal(t, "staging", found.Environment())
 	})
 
+	t.Run("Save and Get preserves pinned vars", func(t *testing.T) {
+		reset()
+		repo := newRepo()
+		wf := newTestWorkflowWithEnv(t, "staging")
+		wf.SetVars(map[string]string{"API_BASE_URL": "https://staging.example.com"})
+
+		saveWf(t, repo, wf)
+		found, err := repo.Get(wf.ID().String())
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"API_BASE_URL": "https://staging.example.com"}, found.Vars())
+	})
+
+	t.Run("Save and Get preserves trigger input", func(t *testing.T) {
+		reset()
+		repo := newRepo()
+		wf := newTestWorkflow(t)
+		wf.SetInput(map[string]any{"orderId": "o-42", "amount": float64(12)})
+
+		saveWf(t, repo, wf)
+		found, err := repo.Get(wf.ID().String())
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"orderId": "o-42", "amount": float64(12)}, found.Input())
+	})
+
 	t.Run("Exists returns true for saved workflow", func(t *testing.T) {
 		reset()
 		repo := newRepo()